@@ -0,0 +1,377 @@
+// Package cache 提供本地 LRU + Redis 的两级缓存：读取依次查本地、Redis，都未命中时调用 loader 加载并回写两级缓存；
+// 写入 / 删除时通过 Redis pub/sub 通知其他实例清除本地副本。
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/pylemonorg/gotools/db"
+	"github.com/pylemonorg/gotools/hashutil"
+	"github.com/pylemonorg/gotools/logger"
+)
+
+// log cache 模块日志，可通过 logger.SetModuleLevel("cache", ...) 单独控制级别。
+var log = logger.Module("cache")
+
+// ErrEmptyName 缓存名为空。
+var ErrEmptyName = errors.New("cache: 缓存名不能为空")
+
+// Options 缓存配置。零值字段使用默认值，nil 等同于全部默认。
+type Options struct {
+	LocalSize int           // 本地 LRU 容量（条目数），0 时默认 10000，< 0 时不使用本地缓存
+	LocalTTL  time.Duration // 本地缓存有效期，<= 0 时默认 1m；失效消息丢失时本地副本最多陈旧这么久
+	RedisTTL  time.Duration // Redis 缓存有效期，<= 0 时默认 10m
+}
+
+func (o *Options) withDefaults() Options {
+	var r Options
+	if o != nil {
+		r = *o
+	}
+	if r.LocalSize == 0 {
+		r.LocalSize = 10000
+	}
+	if r.LocalTTL <= 0 {
+		r.LocalTTL = time.Minute
+	}
+	if r.RedisTTL <= 0 {
+		r.RedisTTL = 10 * time.Minute
+	}
+	return r
+}
+
+// Stats 缓存命中统计（自创建以来的累计值）。
+type Stats struct {
+	LocalHits  int64 // 本地命中次数
+	RedisHits  int64 // 本地未命中、Redis 命中次数
+	Misses     int64 // 两级均未命中次数
+	Loads      int64 // loader 调用次数（并发加载同一 key 只计一次）
+	LoadErrors int64 // loader 返回错误的次数
+	Evictions  int64 // 本地 LRU 因容量淘汰的条目数
+	LocalLen   int   // 本地缓存当前条目数
+}
+
+// invalidation pub/sub 失效消息。
+type invalidation struct {
+	Source string   `json:"src"` // 发送方实例 ID，收到自己发出的消息时忽略
+	Keys   []string `json:"keys"`
+}
+
+// call 一次进行中的加载，用于合并并发加载同一 key 的请求。
+type call[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// Cache 两级缓存，值以 JSON 序列化后存入 Redis，并发安全。使用的 Redis key（{name} 为缓存名）：
+//   - {name}:{key}        缓存值（String）
+//   - {name}:invalidate   失效通知频道（pub/sub）
+//
+// 需要在后台运行 Listen 接收其他实例的失效通知，否则本地副本最多陈旧 LocalTTL。
+// client 为 nil 时只使用本地缓存（单实例场景或测试）。
+//
+// 用法：
+//
+//	users, _ := cache.New[*User](redisClient, "cache:user", &cache.Options{LocalTTL: 30 * time.Second})
+//	go users.Listen(ctx)
+//
+//	u, err := users.GetOrLoad(ctx, strconv.FormatInt(id, 10), func(ctx context.Context) (*User, error) {
+//	    return loadUserFromDB(ctx, id)
+//	})
+//
+//	// 更新后使缓存失效（所有实例的本地副本一并清除）
+//	_ = users.Delete(ctx, strconv.FormatInt(id, 10))
+type Cache[T any] struct {
+	client *db.RedisClient // 为 nil 时只使用本地缓存；每次操作通过 GetClient() 取当前连接，Reconnect 后仍可使用
+	name   string
+	opts   Options
+	local  *lru[T] // LocalSize < 0 时为 nil
+	id     string  // 实例 ID
+
+	mu    sync.Mutex
+	calls map[string]*call[T]
+
+	localHits, redisHits, misses, loads, loadErrors atomic.Int64
+}
+
+// New 创建缓存。client 为 nil 时只使用本地缓存；opts 为 nil 时使用默认配置。
+func New[T any](client *db.RedisClient, name string, opts *Options) (*Cache[T], error) {
+	if name == "" {
+		return nil, ErrEmptyName
+	}
+	c := &Cache[T]{
+		client: client,
+		name:   name,
+		opts:   opts.withDefaults(),
+		id:     hashutil.NewULID(),
+		calls:  make(map[string]*call[T]),
+	}
+	if c.opts.LocalSize > 0 {
+		c.local = newLRU[T](c.opts.LocalSize)
+	}
+	return c, nil
+}
+
+// Name 返回缓存名。
+func (c *Cache[T]) Name() string { return c.name }
+
+func (c *Cache[T]) redisKey(key string) string { return c.name + ":" + key }
+func (c *Cache[T]) channel() string            { return c.name + ":invalidate" }
+
+// Get 依次查询本地缓存与 Redis，命中 Redis 时回填本地缓存；都未命中时返回 false。
+// 仅在访问 Redis 或反序列化失败时返回错误。
+func (c *Cache[T]) Get(ctx context.Context, key string) (T, bool, error) {
+	var zero T
+	if c.local != nil {
+		if v, ok := c.local.get(key, time.Now()); ok {
+			c.localHits.Add(1)
+			return v, true, nil
+		}
+	}
+	if c.client == nil {
+		c.misses.Add(1)
+		return zero, false, nil
+	}
+
+	data, err := c.client.GetClient().Get(ctx, c.redisKey(key)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		c.misses.Add(1)
+		return zero, false, nil
+	}
+	if err != nil {
+		return zero, false, fmt.Errorf("cache: 读取 [%s] 失败: %w", c.redisKey(key), err)
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return zero, false, fmt.Errorf("cache: 解析 [%s] 失败: %w", c.redisKey(key), err)
+	}
+	c.redisHits.Add(1)
+	if c.local != nil {
+		c.local.set(key, v, c.opts.LocalTTL, time.Now())
+	}
+	return v, true, nil
+}
+
+// GetOrLoad 读取缓存，未命中时调用 loader 加载并写入两级缓存（read-through）。
+// 同一实例内并发加载同一 key 时只调用一次 loader，其余调用等待并共享结果（loader 使用第一个调用方的 ctx）。
+// Redis 不可用时记录警告并直接调用 loader，缓存故障不影响可用性；loader 的错误原样返回且不缓存。
+func (c *Cache[T]) GetOrLoad(ctx context.Context, key string, loader func(ctx context.Context) (T, error)) (T, error) {
+	v, ok, err := c.Get(ctx, key)
+	if err != nil {
+		log.Warnf("%v，直接加载", err)
+	} else if ok {
+		return v, nil
+	}
+
+	c.mu.Lock()
+	if cl, ok := c.calls[key]; ok {
+		c.mu.Unlock()
+		select {
+		case <-cl.done:
+			return cl.value, cl.err
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+	cl := &call[T]{done: make(chan struct{})}
+	c.calls[key] = cl
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.calls, key)
+		c.mu.Unlock()
+		close(cl.done)
+	}()
+
+	c.loads.Add(1)
+	cl.err = fmt.Errorf("cache: 加载 [%s] 时 panic", key) // loader panic 时等待方收到此错误
+	cl.value, cl.err = loader(ctx)
+	if cl.err != nil {
+		c.loadErrors.Add(1)
+		return cl.value, cl.err
+	}
+	if err := c.set(ctx, key, cl.value, false); err != nil {
+		log.Warnf("%v", err)
+	}
+	return cl.value, nil
+}
+
+// Set 写入两级缓存（write-through），并通知其他实例清除该 key 的本地副本。
+func (c *Cache[T]) Set(ctx context.Context, key string, value T) error {
+	return c.set(ctx, key, value, true)
+}
+
+// set 写入 Redis 与本地缓存，notify 为 true 时发布失效通知。
+// 本地缓存总是写入，Redis 写入失败时返回错误。
+func (c *Cache[T]) set(ctx context.Context, key string, value T, notify bool) error {
+	if c.local != nil {
+		c.local.set(key, value, c.opts.LocalTTL, time.Now())
+	}
+	if c.client == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("cache: 序列化 [%s] 失败: %w", c.redisKey(key), err)
+	}
+	if err := c.client.GetClient().Set(ctx, c.redisKey(key), data, c.opts.RedisTTL).Err(); err != nil {
+		return fmt.Errorf("cache: 写入 [%s] 失败: %w", c.redisKey(key), err)
+	}
+	if notify {
+		return c.publish(ctx, key)
+	}
+	return nil
+}
+
+// Delete 从两级缓存中删除 keys，并通知其他实例清除本地副本。数据更新后调用。
+func (c *Cache[T]) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	if c.local != nil {
+		for _, key := range keys {
+			c.local.remove(key)
+		}
+	}
+	if c.client == nil {
+		return nil
+	}
+	redisKeys := make([]string, len(keys))
+	for i, key := range keys {
+		redisKeys[i] = c.redisKey(key)
+	}
+	if err := c.client.GetClient().Del(ctx, redisKeys...).Err(); err != nil {
+		return fmt.Errorf("cache: 删除 %s 下 %d 个 key 失败: %w", c.name, len(keys), err)
+	}
+	return c.publish(ctx, keys...)
+}
+
+// ClearLocal 清空本实例的本地缓存（不影响 Redis 与其他实例）。
+func (c *Cache[T]) ClearLocal() {
+	if c.local != nil {
+		c.local.clear()
+	}
+}
+
+// Stats 返回命中统计。
+func (c *Cache[T]) Stats() Stats {
+	s := Stats{
+		LocalHits:  c.localHits.Load(),
+		RedisHits:  c.redisHits.Load(),
+		Misses:     c.misses.Load(),
+		Loads:      c.loads.Load(),
+		LoadErrors: c.loadErrors.Load(),
+	}
+	if c.local != nil {
+		s.Evictions = c.local.evicted()
+		s.LocalLen = c.local.len()
+	}
+	return s
+}
+
+// publish 发布失效通知。
+func (c *Cache[T]) publish(ctx context.Context, keys ...string) error {
+	msg, err := json.Marshal(invalidation{Source: c.id, Keys: keys})
+	if err != nil {
+		return fmt.Errorf("cache: 序列化失效通知失败: %w", err)
+	}
+	if err := c.client.GetClient().Publish(ctx, c.channel(), msg).Err(); err != nil {
+		return fmt.Errorf("cache: 发布失效通知 [%s] 失败: %w", c.channel(), err)
+	}
+	return nil
+}
+
+// Listen 订阅失效通知并清除本地副本，阻塞直到 ctx 取消（返回 nil）。应在后台 goroutine 中运行。
+// 断线重连（包括 RedisClient.Reconnect 替换连接）后会重新订阅并清空本地缓存，避免断线期间错过的通知导致读到旧值。
+// 只使用本地缓存时直接返回 nil。
+func (c *Cache[T]) Listen(ctx context.Context) error {
+	if c.client == nil || c.local == nil {
+		return nil
+	}
+	var mu sync.Mutex // 保护 pubsub，Reconnect 后会替换
+	rdb := c.client.GetClient()
+	pubsub := rdb.Subscribe(ctx, c.channel())
+	// Receive 不感知 ctx，取消时关闭订阅使其返回
+	stop := context.AfterFunc(ctx, func() {
+		mu.Lock()
+		defer mu.Unlock()
+		pubsub.Close()
+	})
+	defer func() {
+		if stop() {
+			mu.Lock()
+			defer mu.Unlock()
+			pubsub.Close()
+		}
+	}()
+
+	subscribed := false
+	backoff := time.Second
+	for {
+		msg, err := pubsub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			// 连接被 Reconnect 替换时旧连接已关闭，改为在新连接上订阅；
+			// 其余情况 go-redis 会在下一次 Receive 时自动重连并重新订阅
+			if cur := c.client.GetClient(); cur != rdb {
+				mu.Lock()
+				pubsub.Close()
+				rdb = cur
+				pubsub = rdb.Subscribe(ctx, c.channel())
+				mu.Unlock()
+				continue
+			}
+			log.Warnf("cache: 接收失效通知失败，%v 后重试: %v", backoff, err)
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, 30*time.Second)
+			continue
+		}
+		backoff = time.Second
+
+		switch m := msg.(type) {
+		case *redis.Subscription:
+			if m.Kind != "subscribe" {
+				continue
+			}
+			if subscribed {
+				c.local.clear()
+				log.Infof("cache: [%s] 重新订阅失效通知，已清空本地缓存", c.name)
+			}
+			subscribed = true
+		case *redis.Message:
+			c.handleInvalidation(m.Payload)
+		}
+	}
+}
+
+// handleInvalidation 处理一条失效通知，忽略本实例发出的通知。
+func (c *Cache[T]) handleInvalidation(payload string) {
+	var inv invalidation
+	if err := json.Unmarshal([]byte(payload), &inv); err != nil {
+		log.Warnf("cache: [%s] 无法解析失效通知 %q: %v", c.name, payload, err)
+		return
+	}
+	if inv.Source == c.id {
+		return
+	}
+	for _, key := range inv.Keys {
+		c.local.remove(key)
+	}
+}
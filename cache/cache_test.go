@@ -0,0 +1,224 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pylemonorg/gotools/internal/redistest"
+)
+
+// ---------------------------------------------------------------------------
+// lru
+// ---------------------------------------------------------------------------
+
+func TestLRUEviction(t *testing.T) {
+	now := time.Now()
+	c := newLRU[int](2)
+	c.set("a", 1, time.Minute, now)
+	c.set("b", 2, time.Minute, now)
+	c.get("a", now) // a 变为最近访问
+	c.set("c", 3, time.Minute, now)
+
+	if _, ok := c.get("b", now); ok {
+		t.Error("b 应被淘汰")
+	}
+	if v, ok := c.get("a", now); !ok || v != 1 {
+		t.Errorf("a = %d, %v", v, ok)
+	}
+	if c.len() != 2 || c.evicted() != 1 {
+		t.Errorf("len = %d, evicted = %d, 期望 2、1", c.len(), c.evicted())
+	}
+
+	c.set("a", 10, time.Minute, now) // 更新不淘汰
+	if v, _ := c.get("a", now); v != 10 || c.evicted() != 1 {
+		t.Errorf("更新后 a = %d, evicted = %d", v, c.evicted())
+	}
+}
+
+func TestLRUExpiry(t *testing.T) {
+	now := time.Now()
+	c := newLRU[string](10)
+	c.set("k", "v", time.Second, now)
+	if _, ok := c.get("k", now.Add(500*time.Millisecond)); !ok {
+		t.Error("未过期时应命中")
+	}
+	if _, ok := c.get("k", now.Add(time.Second)); ok {
+		t.Error("过期后不应命中")
+	}
+	if c.len() != 0 {
+		t.Errorf("过期条目应在访问时删除, len = %d", c.len())
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Cache（仅本地）
+// ---------------------------------------------------------------------------
+
+func TestNew(t *testing.T) {
+	if _, err := New[int](nil, "", nil); !errors.Is(err, ErrEmptyName) {
+		t.Errorf("err = %v, 期望 ErrEmptyName", err)
+	}
+	c, err := New[int](nil, "test", &Options{LocalSize: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.local != nil {
+		t.Error("LocalSize < 0 时不应创建本地缓存")
+	}
+	if c.opts.LocalTTL != time.Minute || c.opts.RedisTTL != 10*time.Minute {
+		t.Errorf("默认值 = %+v", c.opts)
+	}
+}
+
+func TestGetOrLoad(t *testing.T) {
+	ctx := context.Background()
+	c, _ := New[string](nil, "test", nil)
+
+	var loads atomic.Int32
+	loader := func(context.Context) (string, error) {
+		loads.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		return "v", nil
+	}
+
+	// 并发加载同一 key 只调用一次 loader
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := c.GetOrLoad(ctx, "k", loader); err != nil || v != "v" {
+				t.Errorf("GetOrLoad = %q, %v", v, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := loads.Load(); n != 1 {
+		t.Errorf("loader 调用 %d 次, 期望 1", n)
+	}
+
+	if v, ok, err := c.Get(ctx, "k"); err != nil || !ok || v != "v" {
+		t.Errorf("Get = %q, %v, %v", v, ok, err)
+	}
+
+	s := c.Stats()
+	if s.Loads != 1 || s.LocalHits < 1 || s.LocalLen != 1 {
+		t.Errorf("Stats = %+v", s)
+	}
+}
+
+func TestGetOrLoadError(t *testing.T) {
+	ctx := context.Background()
+	c, _ := New[int](nil, "test", nil)
+
+	wantErr := errors.New("db down")
+	if _, err := c.GetOrLoad(ctx, "k", func(context.Context) (int, error) { return 0, wantErr }); !errors.Is(err, wantErr) {
+		t.Errorf("err = %v, 期望 %v", err, wantErr)
+	}
+	if _, ok, _ := c.Get(ctx, "k"); ok {
+		t.Error("加载失败不应缓存")
+	}
+	if s := c.Stats(); s.LoadErrors != 1 {
+		t.Errorf("LoadErrors = %d, 期望 1", s.LoadErrors)
+	}
+}
+
+func TestSetDelete(t *testing.T) {
+	ctx := context.Background()
+	c, _ := New[map[string]int](nil, "test", nil)
+
+	if err := c.Set(ctx, "a", map[string]int{"x": 1}); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok, _ := c.Get(ctx, "a"); !ok || v["x"] != 1 {
+		t.Errorf("Get = %v, %v", v, ok)
+	}
+	if err := c.Delete(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := c.Get(ctx, "a"); ok {
+		t.Error("Delete 后不应命中")
+	}
+}
+
+func TestHandleInvalidation(t *testing.T) {
+	ctx := context.Background()
+	c, _ := New[int](nil, "test", nil)
+	_ = c.Set(ctx, "a", 1)
+	_ = c.Set(ctx, "b", 2)
+
+	own, _ := json.Marshal(invalidation{Source: c.id, Keys: []string{"a"}})
+	c.handleInvalidation(string(own))
+	if _, ok, _ := c.Get(ctx, "a"); !ok {
+		t.Error("本实例发出的通知应忽略")
+	}
+
+	other, _ := json.Marshal(invalidation{Source: "other", Keys: []string{"a", "b"}})
+	c.handleInvalidation(string(other))
+	if c.Stats().LocalLen != 0 {
+		t.Errorf("其他实例的通知应清除本地副本, LocalLen = %d", c.Stats().LocalLen)
+	}
+
+	c.handleInvalidation("not json") // 只记录日志
+}
+
+// ---------------------------------------------------------------------------
+// Cache + Redis
+// ---------------------------------------------------------------------------
+
+// redisServer 返回只实现 Cache 用到的命令的测试服务端：GET 总是未命中，SET / DEL / PUBLISH 直接成功，
+// SUBSCRIBE 返回订阅确认后保持连接。
+func redisServer(t *testing.T) *redistest.Server {
+	t.Helper()
+	srv := redistest.NewServer(t)
+	srv.Handle("GET", func([]string) any { return nil })
+	srv.Handle("SET", func([]string) any { return redistest.OK })
+	srv.Handle("DEL", func([]string) any { return 0 })
+	srv.Handle("PUBLISH", func([]string) any { return 0 })
+	srv.Handle("SUBSCRIBE", func(args []string) any { return []any{"subscribe", args[1], 1} })
+	return srv
+}
+
+// 在 Get / Set / Delete / Listen 并发执行时反复 Reconnect，不应 panic 或出现数据竞争（配合 -race 运行）。
+func TestReconnectDuringOperations(t *testing.T) {
+	rc := redisServer(t).Client(t)
+	c, _ := New[int](rc, "test", nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	listenDone := make(chan error, 1)
+	go func() { listenDone <- c.Listen(ctx) }()
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				// 使用被替换的旧连接时可能返回连接已关闭错误，这里只关心不 panic
+				_, _, _ = c.Get(ctx, "k")
+				_ = c.Set(ctx, "k", 1)
+				_ = c.Delete(ctx, "k")
+			}
+		}()
+	}
+	for range 5 {
+		if err := rc.Reconnect(1, time.Millisecond); err != nil {
+			t.Errorf("Reconnect: %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	wg.Wait()
+	if err := <-listenDone; err != nil {
+		t.Errorf("Listen = %v", err)
+	}
+
+	if err := c.Set(context.Background(), "k", 2); err != nil {
+		t.Errorf("Reconnect 后 Set = %v", err)
+	}
+}
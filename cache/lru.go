@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// lru 带过期时间的 LRU 缓存，并发安全。容量满时淘汰最久未访问的条目，过期条目在访问时删除。
+type lru[V any] struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List // 元素为 *lruEntry[V]，队首为最近访问
+	items    map[string]*list.Element
+
+	evictions int64 // 因容量淘汰的条目数
+}
+
+// lruEntry lru 中的一个条目。
+type lruEntry[V any] struct {
+	key       string
+	value     V
+	expiresAt time.Time
+}
+
+// newLRU 创建容量为 capacity 的 lru。
+func newLRU[V any](capacity int) *lru[V] {
+	return &lru[V]{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// get 返回未过期的值并将其移到队首。
+func (c *lru[V]) get(key string, now time.Time) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+	el, ok := c.items[key]
+	if !ok {
+		return zero, false
+	}
+	e := el.Value.(*lruEntry[V])
+	if !now.Before(e.expiresAt) {
+		c.removeElement(el)
+		return zero, false
+	}
+	c.ll.MoveToFront(el)
+	return e.value, true
+}
+
+// set 写入值，ttl 后过期；超出容量时淘汰最久未访问的条目。
+func (c *lru[V]) set(key string, value V, ttl time.Duration, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*lruEntry[V])
+		e.value, e.expiresAt = value, now.Add(ttl)
+		c.ll.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.ll.PushFront(&lruEntry[V]{key: key, value: value, expiresAt: now.Add(ttl)})
+	for c.ll.Len() > c.capacity {
+		c.removeElement(c.ll.Back())
+		c.evictions++
+	}
+}
+
+// remove 删除条目，不存在时忽略。
+func (c *lru[V]) remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		c.removeElement(el)
+	}
+}
+
+// clear 删除全部条目。
+func (c *lru[V]) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ll.Init()
+	clear(c.items)
+}
+
+// len 返回条目数（可能包含尚未清理的过期条目）。
+func (c *lru[V]) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// evicted 返回因容量淘汰的条目数。
+func (c *lru[V]) evicted() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.evictions
+}
+
+// removeElement 删除链表元素及索引，调用方需持有锁。
+func (c *lru[V]) removeElement(el *list.Element) {
+	c.ll.Remove(el)
+	delete(c.items, el.Value.(*lruEntry[V]).key)
+}
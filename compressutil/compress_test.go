@@ -0,0 +1,180 @@
+package compressutil
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGzipRoundTrip(t *testing.T) {
+	input := []byte(strings.Repeat("hello gzip ", 1000))
+	compressed, err := GzipBytes(input)
+	if err != nil {
+		t.Fatalf("GzipBytes: %v", err)
+	}
+	if len(compressed) >= len(input) {
+		t.Errorf("compressed size %d should be smaller than input %d", len(compressed), len(input))
+	}
+
+	out, err := GunzipBytes(compressed)
+	if err != nil {
+		t.Fatalf("GunzipBytes: %v", err)
+	}
+	if !bytes.Equal(out, input) {
+		t.Error("GunzipBytes output differs from input")
+	}
+
+	if _, err := GunzipBytesLimit(compressed, 100); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("GunzipBytesLimit = %v, want ErrTooLarge", err)
+	}
+	if _, err := GunzipBytes([]byte("not gzip")); err == nil {
+		t.Error("GunzipBytes should reject invalid data")
+	}
+}
+
+func TestZstdRoundTrip(t *testing.T) {
+	input := []byte(strings.Repeat(`{"id":1,"name":"zstd"}`+"\n", 500))
+	compressed, err := ZstdBytes(input)
+	if err != nil {
+		t.Fatalf("ZstdBytes: %v", err)
+	}
+	out, err := UnzstdBytes(compressed)
+	if err != nil {
+		t.Fatalf("UnzstdBytes: %v", err)
+	}
+	if !bytes.Equal(out, input) {
+		t.Error("UnzstdBytes output differs from input")
+	}
+	if _, err := UnzstdBytesLimit(compressed, 10); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("UnzstdBytesLimit = %v, want ErrTooLarge", err)
+	}
+}
+
+func TestTarGzRoundTrip(t *testing.T) {
+	src := t.TempDir()
+	mustWrite(t, filepath.Join(src, "a.txt"), "A")
+	mustWrite(t, filepath.Join(src, "sub", "b.txt"), "B")
+	if err := os.MkdirAll(filepath.Join(src, "empty"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("a.txt", filepath.Join(src, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := TarGzDir(src, &buf); err != nil {
+		t.Fatalf("TarGzDir: %v", err)
+	}
+
+	dst := filepath.Join(t.TempDir(), "out")
+	if err := UntarGz(&buf, dst); err != nil {
+		t.Fatalf("UntarGz: %v", err)
+	}
+	for name, want := range map[string]string{"a.txt": "A", "sub/b.txt": "B", "link": "A"} {
+		got, err := os.ReadFile(filepath.Join(dst, name))
+		if err != nil || string(got) != want {
+			t.Errorf("%s = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	if fi, err := os.Stat(filepath.Join(dst, "empty")); err != nil || !fi.IsDir() {
+		t.Errorf("empty dir not restored: %v", err)
+	}
+}
+
+func TestUntarGzRejectsUnsafePaths(t *testing.T) {
+	tests := []struct {
+		name string
+		hdr  tar.Header
+	}{
+		{"parent traversal", tar.Header{Name: "../evil.txt", Typeflag: tar.TypeReg, Mode: 0o644}},
+		{"absolute path", tar.Header{Name: "/tmp/evil.txt", Typeflag: tar.TypeReg, Mode: 0o644}},
+		{"symlink escape", tar.Header{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "../../etc/passwd"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			gw := gzip.NewWriter(&buf)
+			tw := tar.NewWriter(gw)
+			if err := tw.WriteHeader(&tt.hdr); err != nil {
+				t.Fatal(err)
+			}
+			tw.Close()
+			gw.Close()
+
+			err := UntarGz(&buf, t.TempDir())
+			if !errors.Is(err, ErrUnsafePath) {
+				t.Errorf("UntarGz = %v, want ErrUnsafePath", err)
+			}
+		})
+	}
+}
+
+// tarGz 按顺序写入 headers 生成 tar.gz，普通文件内容为 size 个 'x'。
+func tarGz(t *testing.T, hdrs ...tar.Header) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for _, hdr := range hdrs {
+		if err := tw.WriteHeader(&hdr); err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			tw.Write(bytes.Repeat([]byte("x"), int(hdr.Size)))
+		}
+	}
+	tw.Close()
+	gw.Close()
+	return &buf
+}
+
+func TestUntarGzRejectsChainedSymlinks(t *testing.T) {
+	base := t.TempDir()
+	dst := filepath.Join(base, "a", "b", "dst")
+	buf := tarGz(t,
+		tar.Header{Name: "x/y/", Typeflag: tar.TypeDir, Mode: 0o755},
+		tar.Header{Name: "x/y/l", Typeflag: tar.TypeSymlink, Linkname: "../.."},
+		tar.Header{Name: "x/y/l/m", Typeflag: tar.TypeSymlink, Linkname: "../.."},
+		tar.Header{Name: "x/y/l/m/evil.txt", Typeflag: tar.TypeReg, Mode: 0o644, Size: 4},
+	)
+	err := UntarGz(buf, dst)
+	if !errors.Is(err, ErrUnsafePath) {
+		t.Errorf("UntarGz = %v, want ErrUnsafePath", err)
+	}
+	for _, p := range []string{filepath.Join(base, "a", "b", "evil.txt"), filepath.Join(base, "a", "evil.txt"), filepath.Join(dst, "evil.txt")} {
+		if _, err := os.Lstat(p); err == nil {
+			t.Errorf("%s should not have been written", p)
+		}
+	}
+}
+
+func TestUntarGzLimit(t *testing.T) {
+	files := []tar.Header{
+		{Name: "a.txt", Typeflag: tar.TypeReg, Mode: 0o644, Size: 60},
+		{Name: "b.txt", Typeflag: tar.TypeReg, Mode: 0o644, Size: 60},
+	}
+	if err := UntarGzLimit(tarGz(t, files...), t.TempDir(), 100, 0); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("size limit: UntarGzLimit = %v, want ErrTooLarge", err)
+	}
+	if err := UntarGzLimit(tarGz(t, files...), t.TempDir(), 0, 1); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("entry limit: UntarGzLimit = %v, want ErrTooLarge", err)
+	}
+	if err := UntarGzLimit(tarGz(t, files...), t.TempDir(), 120, 2); err != nil {
+		t.Errorf("within limits: UntarGzLimit = %v", err)
+	}
+}
+
+func mustWrite(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
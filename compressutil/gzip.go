@@ -0,0 +1,60 @@
+package compressutil
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+// GzipBytes 使用默认压缩级别 gzip 压缩 data。
+func GzipBytes(data []byte) ([]byte, error) {
+	return GzipBytesLevel(data, gzip.DefaultCompression)
+}
+
+// GzipBytesLevel 使用指定级别 gzip 压缩 data，level 取值同 compress/gzip（如 gzip.BestSpeed）。
+func GzipBytesLevel(data []byte, level int) ([]byte, error) {
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, level)
+	if err != nil {
+		return nil, fmt.Errorf("compressutil: gzip 压缩级别无效: %w", err)
+	}
+	if _, err := zw.Write(data); err != nil {
+		return nil, fmt.Errorf("compressutil: gzip 压缩失败: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("compressutil: gzip 压缩失败: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// GunzipBytes 解压 gzip 数据（支持多段拼接的 gzip 流）。
+// 数据来源不可信时请使用 GunzipBytesLimit 防止解压炸弹。
+func GunzipBytes(data []byte) ([]byte, error) {
+	return GunzipBytesLimit(data, 0)
+}
+
+// GunzipBytesLimit 同 GunzipBytes，解压后超过 maxSize 字节时返回 ErrTooLarge；maxSize <= 0 表示不限制。
+func GunzipBytesLimit(data []byte, maxSize int64) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("compressutil: gzip 数据无效: %w", err)
+	}
+	defer zr.Close()
+	return readAllLimit(zr, maxSize, "gzip")
+}
+
+// readAllLimit 读取全部数据，超过 maxSize 时返回 ErrTooLarge。
+func readAllLimit(r io.Reader, maxSize int64, name string) ([]byte, error) {
+	if maxSize > 0 {
+		r = io.LimitReader(r, maxSize+1)
+	}
+	out, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("compressutil: %s 解压失败: %w", name, err)
+	}
+	if maxSize > 0 && int64(len(out)) > maxSize {
+		return nil, fmt.Errorf("%w: 超过 %d 字节", ErrTooLarge, maxSize)
+	}
+	return out, nil
+}
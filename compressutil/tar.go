@@ -0,0 +1,241 @@
+package compressutil
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 压缩相关的哨兵错误。
+var (
+	ErrTooLarge    = errors.New("compressutil: 解压后数据过大")
+	ErrUnsafePath  = errors.New("compressutil: 归档中的路径越出目标目录")
+	ErrUnsupported = errors.New("compressutil: 不支持的归档条目类型")
+)
+
+// TarGzDir 将目录 dir 下的所有文件打包为 tar.gz 写入 w（不会关闭 w）。
+// 归档内路径相对于 dir，使用 "/" 分隔；保留文件权限和修改时间，符号链接按链接本身存储。
+//
+// 用法：
+//
+//	f, _ := os.Create("logs.tar.gz")
+//	defer f.Close()
+//	err := compressutil.TarGzDir("/var/log/app", f)
+func TarGzDir(dir string, w io.Writer) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		return addTarEntry(tw, path, filepath.ToSlash(rel), d)
+	})
+	if err != nil {
+		return fmt.Errorf("compressutil: 打包目录 [%s] 失败: %w", dir, err)
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("compressutil: 写入 tar 结尾失败: %w", err)
+	}
+	if err := gw.Close(); err != nil {
+		return fmt.Errorf("compressutil: 写入 gzip 结尾失败: %w", err)
+	}
+	return nil
+}
+
+// addTarEntry 写入单个条目。
+func addTarEntry(tw *tar.Writer, path, name string, d fs.DirEntry) error {
+	info, err := d.Info()
+	if err != nil {
+		return err
+	}
+	link := ""
+	if info.Mode()&fs.ModeSymlink != 0 {
+		if link, err = os.Readlink(path); err != nil {
+			return err
+		}
+	}
+	hdr, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+	if info.IsDir() {
+		hdr.Name += "/"
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// UntarGz 将 tar.gz 流解压到目录 dir（不存在时自动创建），不限制解压大小，来源不可信时使用 UntarGzLimit。
+// 会拒绝越出 dir 的路径（如 "../x" 或绝对路径）、指向 dir 之外的符号链接，以及经过符号链接写入
+// （包括归档中先创建的符号链接）的条目，返回 ErrUnsafePath。所有写入都通过 os.Root 限定在 dir 之内。
+func UntarGz(r io.Reader, dir string) error {
+	return UntarGzLimit(r, dir, 0, 0)
+}
+
+// UntarGzLimit 同 UntarGz，普通文件总大小超过 maxSize 字节或条目数超过 maxEntries 时停止解压并返回 ErrTooLarge
+// （已解压的文件不会删除）；maxSize / maxEntries <= 0 表示不限制。
+//
+// 用法：
+//
+//	err := compressutil.UntarGzLimit(resp.Body, "/data/import", 1<<30, 10000) // 最多 1 GiB、1 万个条目
+func UntarGzLimit(r io.Reader, dir string, maxSize int64, maxEntries int) error {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("compressutil: gzip 数据无效: %w", err)
+	}
+	defer gr.Close()
+
+	rootDir, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("compressutil: 解析目标目录失败: %w", err)
+	}
+	if err := os.MkdirAll(rootDir, 0o755); err != nil {
+		return fmt.Errorf("compressutil: 创建目标目录失败: %w", err)
+	}
+	root, err := os.OpenRoot(rootDir)
+	if err != nil {
+		return fmt.Errorf("compressutil: 打开目标目录失败: %w", err)
+	}
+	defer root.Close()
+
+	var total int64
+	tr := tar.NewReader(gr)
+	for entries := 1; ; entries++ {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("compressutil: 读取 tar 条目失败: %w", err)
+		}
+		if maxEntries > 0 && entries > maxEntries {
+			return fmt.Errorf("%w: 条目数超过 %d", ErrTooLarge, maxEntries)
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			total += hdr.Size
+			if maxSize > 0 && total > maxSize {
+				return fmt.Errorf("%w: 超过 %d 字节", ErrTooLarge, maxSize)
+			}
+		}
+		if err := extractEntry(tr, hdr, root, rootDir); err != nil {
+			return err
+		}
+	}
+}
+
+// extractEntry 解压单个条目到 root（rootDir 为其绝对路径，用于校验符号链接目标）。
+func extractEntry(tr *tar.Reader, hdr *tar.Header, root *os.Root, rootDir string) error {
+	target, err := safeJoin(rootDir, hdr.Name)
+	if err != nil {
+		return err
+	}
+	rel, _ := filepath.Rel(rootDir, target)
+	if rel == "." {
+		return nil // 根目录本身
+	}
+	if err := checkNoSymlink(root, rel, hdr.Name); err != nil {
+		return err
+	}
+	mode := hdr.FileInfo().Mode().Perm()
+
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		if err := root.MkdirAll(rel, mode|0o700); err != nil {
+			return fmt.Errorf("compressutil: 创建目录 [%s] 失败: %w", hdr.Name, err)
+		}
+	case tar.TypeReg:
+		if err := root.MkdirAll(filepath.Dir(rel), 0o755); err != nil {
+			return fmt.Errorf("compressutil: 创建目录失败: %w", err)
+		}
+		f, err := root.OpenFile(rel, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+		if err != nil {
+			return fmt.Errorf("compressutil: 创建文件 [%s] 失败: %w", hdr.Name, err)
+		}
+		if _, err := io.Copy(f, tr); err != nil {
+			f.Close()
+			return fmt.Errorf("compressutil: 写入文件 [%s] 失败: %w", hdr.Name, err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("compressutil: 写入文件 [%s] 失败: %w", hdr.Name, err)
+		}
+		root.Chtimes(rel, hdr.ModTime, hdr.ModTime)
+	case tar.TypeSymlink:
+		linkTarget := hdr.Linkname
+		if !filepath.IsAbs(linkTarget) {
+			linkTarget = filepath.Join(filepath.Dir(target), linkTarget)
+		}
+		if !within(rootDir, filepath.Clean(linkTarget)) {
+			return fmt.Errorf("%w: 符号链接 %s -> %s", ErrUnsafePath, hdr.Name, hdr.Linkname)
+		}
+		if err := root.MkdirAll(filepath.Dir(rel), 0o755); err != nil {
+			return fmt.Errorf("compressutil: 创建目录失败: %w", err)
+		}
+		if err := root.Symlink(hdr.Linkname, rel); err != nil {
+			return fmt.Errorf("compressutil: 创建符号链接 [%s] 失败: %w", hdr.Name, err)
+		}
+	default:
+		return fmt.Errorf("%w: %s (type %c)", ErrUnsupported, hdr.Name, hdr.Typeflag)
+	}
+	return nil
+}
+
+// checkNoSymlink 逐级检查 rel 的各级路径（含 rel 本身）都不是已存在的符号链接：
+// 经过符号链接写入时，链接的实际指向只能在磁盘上确定，词法检查无法发现多级链接组合后的越界。
+func checkNoSymlink(root *os.Root, rel, name string) error {
+	parts := strings.Split(rel, string(filepath.Separator))
+	for i := range parts {
+		info, err := root.Lstat(filepath.Join(parts[:i+1]...))
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("compressutil: 检查路径 [%s] 失败: %w", name, err)
+		}
+		if info.Mode()&fs.ModeSymlink != 0 {
+			return fmt.Errorf("%w: %s 经过符号链接 %s", ErrUnsafePath, name, filepath.Join(parts[:i+1]...))
+		}
+	}
+	return nil
+}
+
+// safeJoin 拼接归档路径，越出 root 时返回 ErrUnsafePath。
+func safeJoin(root, name string) (string, error) {
+	if filepath.IsAbs(name) || strings.HasPrefix(name, "/") {
+		return "", fmt.Errorf("%w: %s", ErrUnsafePath, name)
+	}
+	target := filepath.Join(root, filepath.FromSlash(name))
+	if !within(root, target) {
+		return "", fmt.Errorf("%w: %s", ErrUnsafePath, name)
+	}
+	return target, nil
+}
+
+// within 判断 path 是否位于 root 之内（含 root 本身）。
+func within(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
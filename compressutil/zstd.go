@@ -0,0 +1,68 @@
+package compressutil
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// NewZstdWriter 返回写入 w 的 zstd 压缩流，使用完毕必须 Close 以写出结尾数据（不会关闭 w）。
+//
+// 用法（JSONL 边生成边压缩）：
+//
+//	zw, _ := compressutil.NewZstdWriter(f)
+//	enc := json.NewEncoder(zw)
+//	for _, rec := range records {
+//	    enc.Encode(rec)
+//	}
+//	zw.Close()
+func NewZstdWriter(w io.Writer) (io.WriteCloser, error) {
+	zw, err := zstd.NewWriter(w)
+	if err != nil {
+		return nil, fmt.Errorf("compressutil: 创建 zstd 压缩器失败: %w", err)
+	}
+	return zw, nil
+}
+
+// NewZstdReader 返回从 r 读取并解压 zstd 数据的流，使用完毕应 Close 释放解码器资源（不会关闭 r）。
+func NewZstdReader(r io.Reader) (io.ReadCloser, error) {
+	zr, err := zstd.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("compressutil: 创建 zstd 解压器失败: %w", err)
+	}
+	return zr.IOReadCloser(), nil
+}
+
+// ZstdBytes 使用 zstd 压缩 data。
+func ZstdBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw, err := NewZstdWriter(&buf)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := zw.Write(data); err != nil {
+		zw.Close()
+		return nil, fmt.Errorf("compressutil: zstd 压缩失败: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("compressutil: zstd 压缩失败: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// UnzstdBytes 解压 zstd 数据。
+func UnzstdBytes(data []byte) ([]byte, error) {
+	return UnzstdBytesLimit(data, 0)
+}
+
+// UnzstdBytesLimit 同 UnzstdBytes，解压后超过 maxSize 字节时返回 ErrTooLarge；maxSize <= 0 表示不限制。
+func UnzstdBytesLimit(data []byte, maxSize int64) ([]byte, error) {
+	zr, err := NewZstdReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return readAllLimit(zr, maxSize, "zstd")
+}
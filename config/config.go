@@ -0,0 +1,143 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/pylemonorg/gotools/jsonutil"
+)
+
+// 配置加载相关的哨兵错误。
+var (
+	ErrNotStructPointer = errors.New("config: 目标必须是非 nil 的结构体指针")
+	ErrMissingRequired  = errors.New("config: 缺少必填配置项")
+)
+
+// Validator 由配置结构体实现，Load 在各层合并完成后调用，如 obsutil.ObsConfig。
+type Validator interface {
+	Validate() error
+}
+
+// Option 配置 Load 的数据来源。
+type Option func(*options)
+
+type options struct {
+	file         string
+	fileOptional bool
+	envPrefix    string
+	autoEnv      bool
+	lookupEnv    func(string) (string, bool)
+}
+
+// WithFile 指定配置文件，按扩展名识别格式：.yaml / .yml 为 YAML，其余按 JSON 解析
+// （允许注释和末尾逗号，规则同 jsonutil.ReadConfigFile）。文件不存在时返回错误。
+func WithFile(path string) Option {
+	return func(o *options) {
+		o.file = path
+		o.fileOptional = false
+	}
+}
+
+// WithOptionalFile 同 WithFile，但文件不存在时跳过该层。
+func WithOptionalFile(path string) Option {
+	return func(o *options) {
+		o.file = path
+		o.fileOptional = true
+	}
+}
+
+// WithEnvPrefix 为 env 标签的变量名加前缀，并为未设置 env 标签的字段自动生成变量名：
+// 前缀 + 字段路径的大写蛇形（如 prefix "OBS_" + 字段 AccessKeyID → OBS_ACCESS_KEY_ID，
+// 嵌套字段 Redis.Host → OBS_REDIS_HOST）。
+func WithEnvPrefix(prefix string) Option {
+	return func(o *options) {
+		o.envPrefix = prefix
+		o.autoEnv = true
+	}
+}
+
+// WithLookupEnv 替换环境变量读取函数（默认 os.LookupEnv），便于测试或从其他来源注入。
+func WithLookupEnv(fn func(key string) (string, bool)) Option {
+	return func(o *options) { o.lookupEnv = fn }
+}
+
+// Load 按 默认值 → 配置文件 → 环境变量 的顺序逐层加载配置到 v（后者覆盖前者），
+// 然后校验 required 字段，若 v 实现了 Validator 再调用其 Validate。
+//
+// 支持的结构体标签：
+//   - default:"8080"     字段为零值时使用的默认值
+//   - env:"PORT"         从环境变量读取（WithEnvPrefix 时会加前缀）
+//   - required:"true"    合并后仍为零值则报错
+//
+// 字段类型支持 string、bool、整数、浮点数、time.Duration（如 "30s"）及其切片（逗号分隔）。
+//
+// 用法：
+//
+//	type AppConfig struct {
+//	    Port    int               `json:"port" yaml:"port" default:"8080" env:"PORT"`
+//	    Timeout time.Duration     `json:"timeout" yaml:"timeout" default:"30s"`
+//	    OBS     obsutil.ObsConfig `json:"obs" yaml:"obs"`
+//	}
+//	var cfg AppConfig
+//	err := config.Load(&cfg, config.WithFile("config.yaml"), config.WithEnvPrefix("APP_"))
+func Load(v any, opts ...Option) error {
+	o := &options{lookupEnv: os.LookupEnv}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return ErrNotStructPointer
+	}
+
+	if err := applyDefaults(rv.Elem()); err != nil {
+		return err
+	}
+	if o.file != "" {
+		if err := loadFile(o.file, o.fileOptional, v); err != nil {
+			return err
+		}
+	}
+	if err := applyEnv(rv.Elem(), o); err != nil {
+		return err
+	}
+	if err := checkRequired(rv.Elem()); err != nil {
+		return err
+	}
+	if val, ok := v.(Validator); ok {
+		if err := val.Validate(); err != nil {
+			return fmt.Errorf("config: 校验失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// loadFile 按扩展名解析配置文件到 v。
+func loadFile(path string, optional bool, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if optional && errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("config: 读取文件 [%s] 失败: %w", path, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, v)
+	default:
+		err = json.Unmarshal(jsonutil.StripJSONC(data), v)
+	}
+	if err != nil {
+		return fmt.Errorf("config: 解析文件 [%s] 失败: %w", path, err)
+	}
+	return nil
+}
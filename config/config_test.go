@@ -0,0 +1,168 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type dbConfig struct {
+	Host string `json:"host" yaml:"host" required:"true"`
+	Port int    `json:"port" yaml:"port" default:"5432"`
+}
+
+type appConfig struct {
+	Name    string        `json:"name" yaml:"name" default:"app"`
+	Port    int           `json:"port" yaml:"port" default:"8080" env:"PORT"`
+	Debug   bool          `json:"debug" yaml:"debug"`
+	Timeout time.Duration `json:"timeout" yaml:"timeout" default:"30s"`
+	Tags    []string      `json:"tags" yaml:"tags"`
+	DB      dbConfig      `json:"db" yaml:"db"`
+}
+
+type validatedConfig struct {
+	Level string `json:"level"`
+}
+
+func (c *validatedConfig) Validate() error {
+	if c.Level != "info" && c.Level != "debug" {
+		return errors.New("invalid level")
+	}
+	return nil
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func envMap(m map[string]string) Option {
+	return WithLookupEnv(func(key string) (string, bool) {
+		v, ok := m[key]
+		return v, ok
+	})
+}
+
+func TestLoadDefaultsFileEnv(t *testing.T) {
+	path := writeFile(t, "app.json", `{
+		// 注释
+		"port": 9000,
+		"debug": true,
+		"db": {"host": "file-host"},
+	}`)
+
+	var cfg appConfig
+	err := Load(&cfg, WithFile(path), WithEnvPrefix("APP_"), envMap(map[string]string{
+		"APP_PORT":    "9100",
+		"APP_DB_HOST": "env-host",
+		"APP_TAGS":    "a, b ,c",
+	}))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	want := appConfig{
+		Name:    "app",
+		Port:    9100,
+		Debug:   true,
+		Timeout: 30 * time.Second,
+		Tags:    []string{"a", "b", "c"},
+		DB:      dbConfig{Host: "env-host", Port: 5432},
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("Load = %+v, want %+v", cfg, want)
+	}
+}
+
+func TestLoadYAML(t *testing.T) {
+	path := writeFile(t, "app.yaml", "name: svc\ntimeout: 5s\ndb:\n  host: yaml-host\n  port: 6543\n")
+
+	var cfg appConfig
+	if err := Load(&cfg, WithFile(path), envMap(nil)); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Name != "svc" || cfg.Timeout != 5*time.Second || cfg.DB.Host != "yaml-host" || cfg.DB.Port != 6543 {
+		t.Errorf("Load yaml = %+v", cfg)
+	}
+}
+
+func TestLoadRequiredAndValidate(t *testing.T) {
+	var cfg appConfig
+	err := Load(&cfg, envMap(nil))
+	if !errors.Is(err, ErrMissingRequired) {
+		t.Errorf("Load without DB.Host = %v, want ErrMissingRequired", err)
+	}
+
+	var vc validatedConfig
+	if err := Load(&vc, WithFile(writeFile(t, "v.json", `{"level": "trace"}`))); err == nil {
+		t.Error("Load should call Validate and return its error")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	var cfg appConfig
+	if err := Load(cfg); !errors.Is(err, ErrNotStructPointer) {
+		t.Errorf("Load(non-pointer) = %v, want ErrNotStructPointer", err)
+	}
+	if err := Load(&cfg, WithFile(filepath.Join(t.TempDir(), "missing.json"))); err == nil {
+		t.Error("Load with missing required file should fail")
+	}
+	err := Load(&cfg, WithOptionalFile(filepath.Join(t.TempDir(), "missing.json")), envMap(map[string]string{"DB_HOST": "h"}), WithEnvPrefix(""))
+	if err != nil {
+		t.Errorf("Load with missing optional file: %v", err)
+	}
+	if err := Load(&cfg, envMap(map[string]string{"PORT": "abc"})); err == nil {
+		t.Error("Load with invalid env value should fail")
+	}
+}
+
+func TestWatch(t *testing.T) {
+	path := writeFile(t, "app.json", `{"db": {"host": "v1"}}`)
+
+	var mu sync.Mutex
+	var got []string
+	stop, err := Watch(10*time.Millisecond, func(cfg *appConfig, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			got = append(got, "error")
+			return
+		}
+		got = append(got, cfg.DB.Host)
+	}, WithFile(path), envMap(nil))
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	defer stop()
+
+	time.Sleep(20 * time.Millisecond)
+	if err := os.WriteFile(path, []byte(`{"db": {"host": "version2"}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		n := len(got)
+		mu.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	stop()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) == 0 || got[len(got)-1] != "version2" {
+		t.Errorf("Watch callbacks = %v, want last to be version2", got)
+	}
+}
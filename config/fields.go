@@ -0,0 +1,179 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pylemonorg/gotools/strutil"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// field 描述结构体中的一个可配置字段。
+type field struct {
+	sf    reflect.StructField
+	value reflect.Value
+	path  []string // 从根结构体到该字段的字段名路径
+}
+
+// walkFields 深度优先遍历结构体的导出叶子字段，递归进入嵌套结构体和非 nil 的结构体指针。
+func walkFields(v reflect.Value, path []string, fn func(f field) error) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+		fpath := append(path[:len(path):len(path)], sf.Name)
+
+		inner := fv
+		if inner.Kind() == reflect.Pointer && !inner.IsNil() {
+			inner = inner.Elem()
+		}
+		if inner.Kind() == reflect.Struct && inner.Type() != reflect.TypeOf(time.Time{}) {
+			if err := walkFields(inner, fpath, fn); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := fn(field{sf: sf, value: fv, path: fpath}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyDefaults 为零值字段填充 default 标签。
+func applyDefaults(v reflect.Value) error {
+	return walkFields(v, nil, func(f field) error {
+		def, ok := f.sf.Tag.Lookup("default")
+		if !ok || !f.value.IsZero() {
+			return nil
+		}
+		if err := setFromString(f.value, def); err != nil {
+			return fmt.Errorf("config: 字段 %s 的默认值 %q 无效: %w", strings.Join(f.path, "."), def, err)
+		}
+		return nil
+	})
+}
+
+// applyEnv 用环境变量覆盖字段。
+func applyEnv(v reflect.Value, o *options) error {
+	return walkFields(v, nil, func(f field) error {
+		name := envName(f, o)
+		if name == "" {
+			return nil
+		}
+		raw, ok := o.lookupEnv(name)
+		if !ok {
+			return nil
+		}
+		if err := setFromString(f.value, raw); err != nil {
+			return fmt.Errorf("config: 环境变量 %s=%q 无法赋值给字段 %s: %w", name, raw, strings.Join(f.path, "."), err)
+		}
+		return nil
+	})
+}
+
+// envName 返回字段对应的环境变量名，不读取环境变量时返回空串。
+func envName(f field, o *options) string {
+	tag := f.sf.Tag.Get("env")
+	if tag == "-" {
+		return ""
+	}
+	if tag != "" {
+		return o.envPrefix + tag
+	}
+	if !o.autoEnv {
+		return ""
+	}
+	parts := make([]string, len(f.path))
+	for i, p := range f.path {
+		parts[i] = strings.ToUpper(strutil.ToSnakeCase(p))
+	}
+	return o.envPrefix + strings.Join(parts, "_")
+}
+
+// checkRequired 校验 required:"true" 的字段非零值，汇总列出所有缺失项。
+func checkRequired(v reflect.Value) error {
+	var missing []string
+	_ = walkFields(v, nil, func(f field) error {
+		if f.sf.Tag.Get("required") == "true" && f.value.IsZero() {
+			missing = append(missing, strings.Join(f.path, "."))
+		}
+		return nil
+	})
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrMissingRequired, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// setFromString 将字符串解析为字段类型并赋值。
+func setFromString(v reflect.Value, s string) error {
+	if v.Kind() == reflect.Pointer {
+		elem := reflect.New(v.Type().Elem())
+		if err := setFromString(elem.Elem(), s); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(strings.TrimSpace(s))
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(s))
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(strings.TrimSpace(s), 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(strings.TrimSpace(s), 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		parts := strings.Split(s, ",")
+		if strings.TrimSpace(s) == "" {
+			parts = nil
+		}
+		out := reflect.MakeSlice(v.Type(), len(parts), len(parts))
+		for i, p := range parts {
+			if err := setFromString(out.Index(i), strings.TrimSpace(p)); err != nil {
+				return err
+			}
+		}
+		v.Set(out)
+	default:
+		return fmt.Errorf("不支持的字段类型 %s", v.Type())
+	}
+	return nil
+}
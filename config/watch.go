@@ -0,0 +1,100 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Watch 监听配置文件变化（按修改时间与大小轮询），变化后按相同 opts 重新 Load 一份新的 *T 并调用 onChange。
+// 加载失败时 cfg 为 nil、err 非 nil，调用方应继续使用旧配置。
+// opts 中必须包含 WithFile / WithOptionalFile；interval <= 0 时默认 5s。返回的 stop 用于停止监听。
+//
+// 用法：
+//
+//	var cfg atomic.Pointer[AppConfig]
+//	stop, err := config.Watch(5*time.Second, func(c *AppConfig, err error) {
+//	    if err != nil {
+//	        logger.Errorf("重新加载配置失败: %v", err)
+//	        return
+//	    }
+//	    cfg.Store(c)
+//	}, config.WithFile("config.yaml"), config.WithEnvPrefix("APP_"))
+//	defer stop()
+func Watch[T any](interval time.Duration, onChange func(cfg *T, err error), opts ...Option) (stop func(), err error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.file == "" {
+		return nil, errors.New("config: Watch 需要通过 WithFile 指定配置文件")
+	}
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	last, err := statFile(o.file)
+	if err != nil && !(o.fileOptional && errors.Is(err, os.ErrNotExist)) {
+		return nil, fmt.Errorf("config: 读取文件 [%s] 信息失败: %w", o.file, err)
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+
+			cur, err := statFile(o.file)
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			if cur.equal(last) {
+				continue
+			}
+			last = cur
+
+			cfg := new(T)
+			if err := Load(cfg, opts...); err != nil {
+				onChange(nil, err)
+				continue
+			}
+			onChange(cfg, nil)
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			wg.Wait()
+		})
+	}, nil
+}
+
+// fileStamp 用于判断文件是否变化。
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func (s fileStamp) equal(o fileStamp) bool {
+	return s.modTime.Equal(o.modTime) && s.size == o.size
+}
+
+func statFile(path string) (fileStamp, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}, err
+	}
+	return fileStamp{modTime: fi.ModTime(), size: fi.Size()}, nil
+}
@@ -0,0 +1,129 @@
+package csvutil
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type Base struct {
+	ID int64 `csv:"id"`
+}
+
+type record struct {
+	Base
+	Name    string        `csv:"name"`
+	Score   float64       `csv:"score"`
+	Active  bool          `csv:"active"`
+	Created time.Time     `csv:"created_at"`
+	Timeout time.Duration `csv:"timeout"`
+	Note    *string       `csv:"note"`
+	Ignored string        `csv:"-"`
+}
+
+func TestReadAll(t *testing.T) {
+	input := "\xef\xbb\xbfID,Name,score,active,created_at,timeout,note,extra\n" +
+		"1,张三,9.5,true,2024-01-02 03:04:05,1m30s,hi,x\n" +
+		"2,\"Li, Si\",0,false,,,,\n"
+
+	got, err := ReadAll[record](strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("ReadAll returned %d rows, want 2", len(got))
+	}
+	r0 := got[0]
+	if r0.ID != 1 || r0.Name != "张三" || r0.Score != 9.5 || !r0.Active || r0.Timeout != 90*time.Second {
+		t.Errorf("row 0 = %+v", r0)
+	}
+	if r0.Created.Year() != 2024 || r0.Created.Hour() != 3 {
+		t.Errorf("row 0 Created = %v", r0.Created)
+	}
+	if r0.Note == nil || *r0.Note != "hi" {
+		t.Errorf("row 0 Note = %v, want hi", r0.Note)
+	}
+	if got[1].Name != "Li, Si" || got[1].Note != nil || !got[1].Created.IsZero() {
+		t.Errorf("row 1 = %+v", got[1])
+	}
+}
+
+func TestReadParseErrorLine(t *testing.T) {
+	input := "id,name\n1,a\nabc,b\n"
+	_, err := ReadAll[record](strings.NewReader(input))
+
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("ReadAll = %v, want *ParseError", err)
+	}
+	if pe.Line != 3 || pe.Column != "id" || pe.Value != "abc" {
+		t.Errorf("ParseError = %+v, want line 3 column id value abc", pe)
+	}
+}
+
+func TestReaderStreaming(t *testing.T) {
+	input := "id\n1\n2\n3\n"
+	r, err := NewReader[record](strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("NewReader: %v", err)
+	}
+	var ids []int64
+	for {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Read: %v", err)
+		}
+		ids = append(ids, rec.ID)
+	}
+	if !reflect.DeepEqual(ids, []int64{1, 2, 3}) {
+		t.Errorf("ids = %v, want [1 2 3]", ids)
+	}
+}
+
+func TestStrictHeader(t *testing.T) {
+	_, err := NewReader[record](strings.NewReader("id,name\n"), WithStrict())
+	if !errors.Is(err, ErrHeaderMismatch) {
+		t.Errorf("NewReader strict = %v, want ErrHeaderMismatch", err)
+	}
+	if _, err := NewReader[record](strings.NewReader("")); err == nil {
+		t.Error("NewReader on empty input should fail")
+	}
+}
+
+func TestWriteReadRoundTrip(t *testing.T) {
+	note := "备注"
+	created := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	records := []record{
+		{Base: Base{ID: 1}, Name: "a,b", Score: 1.25, Active: true, Created: created, Timeout: time.Second, Note: &note, Ignored: "x"},
+		{Base: Base{ID: 2}, Name: "line\nbreak"},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, records, WithBOM()); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte(utf8BOM+"id,name,score,active,created_at,timeout,note\n")) {
+		t.Errorf("unexpected header: %q", buf.String())
+	}
+
+	path := filepath.Join(t.TempDir(), "out.tsv")
+	if err := WriteFile(path, records, WithComma('\t')); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	got, err := ReadFileAs[record](path, WithComma('\t'), WithStrict())
+	if err != nil {
+		t.Fatalf("ReadFileAs: %v", err)
+	}
+	records[0].Ignored = ""
+	if !reflect.DeepEqual(got, records) {
+		t.Errorf("round trip = %+v, want %+v", got, records)
+	}
+}
@@ -0,0 +1,217 @@
+package csvutil
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pylemonorg/gotools/timeutil"
+)
+
+// utf8BOM 为 UTF-8 字节序标记。
+const utf8BOM = "\xef\xbb\xbf"
+
+// Option 配置 CSV 读写。
+type Option func(*options)
+
+type options struct {
+	comma      rune
+	timeLayout string
+	strict     bool
+	writeBOM   bool
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{comma: ','}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithComma 设置字段分隔符，默认 ','（如 '\t' 读写 TSV）。
+func WithComma(r rune) Option {
+	return func(o *options) { o.comma = r }
+}
+
+// WithTimeLayout 设置 time.Time 字段的格式。
+// 读取时默认使用 timeutil.ParseAny 自动识别常见格式，写入时默认 RFC 3339。
+func WithTimeLayout(layout string) Option {
+	return func(o *options) { o.timeLayout = layout }
+}
+
+// WithStrict 严格模式：读取时表头必须包含结构体的所有列，且不允许出现未知列。
+func WithStrict() Option {
+	return func(o *options) { o.strict = true }
+}
+
+// WithBOM 写入时在文件开头输出 UTF-8 BOM，使 Excel 正确识别中文。读取时总会自动跳过 BOM。
+func WithBOM() Option {
+	return func(o *options) { o.writeBOM = true }
+}
+
+// column 描述结构体字段与 CSV 列的映射。
+type column struct {
+	name  string
+	index []int
+}
+
+// columnsOf 解析结构体 T 的列定义：优先使用 csv 标签，"-" 表示忽略，未设置时使用字段名。
+// 嵌入的匿名结构体字段会被展开。
+func columnsOf(t reflect.Type) ([]column, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("csvutil: 仅支持结构体类型，实际为 %s", t)
+	}
+	var cols []column
+	var walk func(t reflect.Type, prefix []int)
+	walk = func(t reflect.Type, prefix []int) {
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			index := append(prefix[:len(prefix):len(prefix)], i)
+			tag := sf.Tag.Get("csv")
+			if sf.Anonymous && tag == "" && sf.Type.Kind() == reflect.Struct {
+				walk(sf.Type, index)
+				continue
+			}
+			if !sf.IsExported() || tag == "-" {
+				continue
+			}
+			name, _, _ := strings.Cut(tag, ",")
+			if name == "" {
+				name = sf.Name
+			}
+			cols = append(cols, column{name: name, index: index})
+		}
+	}
+	walk(t, nil)
+	return cols, nil
+}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// setField 将单元格字符串转换为字段类型并赋值，空串保持零值（指针为 nil）。
+func setField(v reflect.Value, s string, o *options) error {
+	if s == "" {
+		v.SetZero()
+		return nil
+	}
+	if v.Kind() == reflect.Pointer {
+		elem := reflect.New(v.Type().Elem())
+		if err := setField(elem.Elem(), s, o); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+	if reflect.PointerTo(v.Type()).Implements(textUnmarshalerType) && v.Type() != timeType {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	switch v.Type() {
+	case timeType:
+		var t time.Time
+		var err error
+		if o.timeLayout != "" {
+			t, err = time.Parse(o.timeLayout, s)
+		} else {
+			t, err = timeutil.ParseAny(s)
+		}
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	case durationType:
+		d, err := timeutil.ParseDurationExtended(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	s = strings.TrimSpace(s)
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("不支持的字段类型 %s", v.Type())
+	}
+	return nil
+}
+
+// formatField 将字段值格式化为单元格字符串，nil 指针输出空串。
+func formatField(v reflect.Value, o *options) (string, error) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Type() {
+	case timeType:
+		t := v.Interface().(time.Time)
+		if t.IsZero() {
+			return "", nil
+		}
+		layout := o.timeLayout
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		return t.Format(layout), nil
+	case durationType:
+		return time.Duration(v.Int()).String(), nil
+	}
+	if v.Type().Implements(textMarshalerType) {
+		b, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(b), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), nil
+	default:
+		return "", fmt.Errorf("不支持的字段类型 %s", v.Type())
+	}
+}
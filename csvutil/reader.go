@@ -0,0 +1,196 @@
+package csvutil
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// ErrHeaderMismatch 表示严格模式下表头与结构体列不一致。
+var ErrHeaderMismatch = errors.New("csvutil: 表头与结构体列不一致")
+
+// ParseError 单元格类型转换失败，包含行号（从 1 开始，表头为第 1 行）和列名。
+type ParseError struct {
+	Line   int
+	Column string
+	Value  string
+	Err    error
+}
+
+// Error 实现 error 接口。
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("csvutil: 第 %d 行列 %q 的值 %q 无效: %v", e.Line, e.Column, e.Value, e.Err)
+}
+
+// Unwrap 支持 errors.Is / errors.As。
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Reader 逐行读取 CSV 并转换为 T，适合处理无法一次性读入内存的大文件。
+// 第一行必须是表头，按列名（忽略大小写和首尾空白）匹配结构体字段。
+//
+// 用法：
+//
+//	f, _ := os.Open("orders.csv")
+//	defer f.Close()
+//	r, err := csvutil.NewReader[Order](f)
+//	for {
+//	    order, err := r.Read()
+//	    if err == io.EOF {
+//	        break
+//	    }
+//	    if err != nil {
+//	        return err // *csvutil.ParseError 含行号
+//	    }
+//	    handle(order)
+//	}
+type Reader[T any] struct {
+	cr      *csv.Reader
+	opts    *options
+	header  []string
+	mapping []*column // 第 i 列对应的字段，nil 表示忽略该列
+}
+
+// NewReader 读取表头并创建 Reader。T 必须是结构体类型。
+func NewReader[T any](r io.Reader, opts ...Option) (*Reader[T], error) {
+	o := newOptions(opts)
+	cols, err := columnsOf(reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
+
+	cr := csv.NewReader(skipBOM(r))
+	cr.Comma = o.comma
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("csvutil: 缺少表头: %w", io.ErrUnexpectedEOF)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("csvutil: 读取表头失败: %w", err)
+	}
+	header = append([]string(nil), header...)
+
+	byName := make(map[string]*column, len(cols))
+	for i := range cols {
+		byName[normalizeName(cols[i].name)] = &cols[i]
+	}
+	mapping := make([]*column, len(header))
+	var unknown []string
+	seen := make(map[string]bool)
+	for i, h := range header {
+		key := normalizeName(h)
+		if c, ok := byName[key]; ok {
+			mapping[i] = c
+			seen[key] = true
+		} else {
+			unknown = append(unknown, h)
+		}
+	}
+
+	if o.strict {
+		var missing []string
+		for _, c := range cols {
+			if !seen[normalizeName(c.name)] {
+				missing = append(missing, c.name)
+			}
+		}
+		if len(missing) > 0 || len(unknown) > 0 {
+			return nil, fmt.Errorf("%w: 缺少列 %v，未知列 %v", ErrHeaderMismatch, missing, unknown)
+		}
+	}
+	return &Reader[T]{cr: cr, opts: o, header: header, mapping: mapping}, nil
+}
+
+// Header 返回原始表头。
+func (r *Reader[T]) Header() []string { return r.header }
+
+// Read 读取下一行，没有更多数据时返回 io.EOF。类型转换失败时返回 *ParseError。
+func (r *Reader[T]) Read() (T, error) {
+	var v T
+	record, err := r.cr.Read()
+	if err != nil {
+		if err == io.EOF {
+			return v, io.EOF
+		}
+		return v, fmt.Errorf("csvutil: 读取 CSV 失败: %w", err)
+	}
+	line, _ := r.cr.FieldPos(0)
+
+	rv := reflect.ValueOf(&v).Elem()
+	for i, cell := range record {
+		if i >= len(r.mapping) || r.mapping[i] == nil {
+			continue
+		}
+		c := r.mapping[i]
+		if err := setField(rv.FieldByIndex(c.index), cell, r.opts); err != nil {
+			return v, &ParseError{Line: line, Column: c.name, Value: cell, Err: err}
+		}
+	}
+	return v, nil
+}
+
+// ReadAll 读取全部 CSV 行为 []T。
+func ReadAll[T any](r io.Reader, opts ...Option) ([]T, error) {
+	cr, err := NewReader[T](r, opts...)
+	if err != nil {
+		return nil, err
+	}
+	var out []T
+	for {
+		v, err := cr.Read()
+		if err == io.EOF {
+			return out, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+}
+
+// ReadFileAs 读取 CSV 文件为 []T，opts 同 NewReader。
+//
+// 用法：
+//
+//	type User struct {
+//	    ID      int64     `csv:"id"`
+//	    Name    string    `csv:"name"`
+//	    Created time.Time `csv:"created_at"`
+//	}
+//	users, err := csvutil.ReadFileAs[User]("users.csv")
+func ReadFileAs[T any](path string, opts ...Option) ([]T, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("csvutil: 打开文件 [%s] 失败: %w", path, err)
+	}
+	defer f.Close()
+
+	out, err := ReadAll[T](bufio.NewReader(f), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("csvutil: 读取文件 [%s] 失败: %w", path, err)
+	}
+	return out, nil
+}
+
+// normalizeName 列名匹配时忽略大小写和首尾空白。
+func normalizeName(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// skipBOM 跳过开头的 UTF-8 BOM。
+func skipBOM(r io.Reader) io.Reader {
+	br := bufio.NewReader(r)
+	if b, err := br.Peek(3); err == nil && string(b) == utf8BOM {
+		br.Discard(3)
+	}
+	return br
+}
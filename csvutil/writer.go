@@ -0,0 +1,73 @@
+package csvutil
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+)
+
+// Write 将 records 写为 CSV（首行为表头），列顺序与结构体字段顺序一致。
+func Write[T any](w io.Writer, records []T, opts ...Option) error {
+	o := newOptions(opts)
+	cols, err := columnsOf(reflect.TypeFor[T]())
+	if err != nil {
+		return err
+	}
+
+	if o.writeBOM {
+		if _, err := io.WriteString(w, utf8BOM); err != nil {
+			return fmt.Errorf("csvutil: 写入 BOM 失败: %w", err)
+		}
+	}
+	cw := csv.NewWriter(w)
+	cw.Comma = o.comma
+
+	row := make([]string, len(cols))
+	for i, c := range cols {
+		row[i] = c.name
+	}
+	if err := cw.Write(row); err != nil {
+		return fmt.Errorf("csvutil: 写入表头失败: %w", err)
+	}
+
+	for n, rec := range records {
+		rv := reflect.ValueOf(rec)
+		for i, c := range cols {
+			s, err := formatField(rv.FieldByIndex(c.index), o)
+			if err != nil {
+				return fmt.Errorf("csvutil: 第 %d 条记录列 %q 格式化失败: %w", n+1, c.name, err)
+			}
+			row[i] = s
+		}
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("csvutil: 写入第 %d 条记录失败: %w", n+1, err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("csvutil: 写入 CSV 失败: %w", err)
+	}
+	return nil
+}
+
+// WriteFile 将 records 写入 CSV 文件（覆盖已有文件），opts 同 Write。
+//
+// 用法：
+//
+//	err := csvutil.WriteFile("users.csv", users, csvutil.WithBOM())
+func WriteFile[T any](path string, records []T, opts ...Option) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("csvutil: 创建文件 [%s] 失败: %w", path, err)
+	}
+	if err := Write(f, records, opts...); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("csvutil: 关闭文件 [%s] 失败: %w", path, err)
+	}
+	return nil
+}
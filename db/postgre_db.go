@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -18,6 +19,9 @@ var (
 	ErrPgNotInit   = errors.New("postgres: 连接未初始化")
 )
 
+// pgLog PostgreSQL 模块日志，可通过 logger.SetModuleLevel("postgres", ...) 单独控制级别。
+var pgLog = logger.Module("postgres")
+
 // maxBatchErrors 批量操作中最多记录的错误数，防止内存膨胀。
 const maxBatchErrors = 10
 
@@ -25,6 +29,7 @@ const maxBatchErrors = 10
 type PostgresClient struct {
 	db     *sql.DB
 	params *PostgresParams
+	trace  QueryTraceHook // 见 WithTrace
 }
 
 // PostgresParams 定义 PostgreSQL 连接所需的参数。
@@ -101,23 +106,34 @@ func NewPostgresClient(params *PostgresParams) (*PostgresClient, error) {
 		return nil, fmt.Errorf("postgres: 连接测试失败: %w", err)
 	}
 
-	logger.Infof("postgres: 连接成功 %s:%d/%s", params.Host, params.Port, params.DBName)
+	pgLog.Infof("postgres: 连接成功 %s:%d/%s", params.Host, params.Port, params.DBName)
 	return &PostgresClient{db: db, params: params}, nil
 }
 
 // GetDB 返回底层 *sql.DB，可用于执行未封装的高级操作。
 func (c *PostgresClient) GetDB() *sql.DB { return c.db }
 
+// HealthCheck 使用 ctx 测试数据库连通性（连接池无空闲连接时会新建连接），用于就绪 / 存活探针（实现 healthcheck.Checker）。
+func (c *PostgresClient) HealthCheck(ctx context.Context) error {
+	if c.db == nil {
+		return ErrPgNotInit
+	}
+	if err := c.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("postgres: 健康检查失败: %w", err)
+	}
+	return nil
+}
+
 // Close 关闭数据库连接。
 func (c *PostgresClient) Close() error {
 	if c.db == nil {
 		return nil
 	}
 	if err := c.db.Close(); err != nil {
-		logger.Warnf("postgres: 关闭连接失败: %v", err)
+		pgLog.Warnf("postgres: 关闭连接失败: %v", err)
 		return err
 	}
-	logger.Infof("postgres: 连接已关闭")
+	pgLog.Infof("postgres: 连接已关闭")
 	return nil
 }
 
@@ -147,7 +163,7 @@ func EnsureDatabaseExists(params *PostgresParams) error {
 		return fmt.Errorf("postgres: 查询数据库是否存在失败: %w", err)
 	}
 	if exists {
-		logger.Infof("postgres: 数据库 [%s] 已存在", params.DBName)
+		pgLog.Infof("postgres: 数据库 [%s] 已存在", params.DBName)
 		return nil
 	}
 
@@ -156,7 +172,7 @@ func EnsureDatabaseExists(params *PostgresParams) error {
 		return fmt.Errorf("postgres: 创建数据库 [%s] 失败: %w", params.DBName, err)
 	}
 
-	logger.Infof("postgres: 数据库 [%s] 创建成功", params.DBName)
+	pgLog.Infof("postgres: 数据库 [%s] 创建成功", params.DBName)
 	return nil
 }
 
@@ -184,17 +200,22 @@ func (c *PostgresClient) Insert(query string, args ...any) (int64, error) {
 		return 0, ErrPgNotInit
 	}
 
+	trace := c.startTrace("Insert", query)
 	var lastInsertID int64
 	err := c.db.QueryRow(query+" RETURNING id", args...).Scan(&lastInsertID)
 	if err == nil {
+		trace(1, nil)
 		return lastInsertID, nil
 	}
 
 	// RETURNING id 失败，回退到普通插入
 	result, execErr := c.db.Exec(query, args...)
 	if execErr != nil {
+		trace(0, execErr)
 		return 0, fmt.Errorf("postgres: 插入失败: %w", execErr)
 	}
+	n, _ := result.RowsAffected()
+	trace(n, nil)
 	lastInsertID, _ = result.LastInsertId()
 	return lastInsertID, nil
 }
@@ -204,7 +225,10 @@ func (c *PostgresClient) InsertWithReturning(query string, dest any, args ...any
 	if c.db == nil {
 		return ErrPgNotInit
 	}
-	if err := c.db.QueryRow(query, args...).Scan(dest); err != nil {
+	trace := c.startTrace("InsertWithReturning", query)
+	err := c.db.QueryRow(query, args...).Scan(dest)
+	trace(-1, err)
+	if err != nil {
 		return fmt.Errorf("postgres: 插入失败: %w", err)
 	}
 	return nil
@@ -215,7 +239,9 @@ func (c *PostgresClient) Query(query string, args ...any) (*sql.Rows, error) {
 	if c.db == nil {
 		return nil, ErrPgNotInit
 	}
+	trace := c.startTrace("Query", query)
 	rows, err := c.db.Query(query, args...)
+	trace(-1, err)
 	if err != nil {
 		return nil, fmt.Errorf("postgres: 查询失败: %w", err)
 	}
@@ -235,7 +261,14 @@ func (c *PostgresClient) QueryOne(query string, dest any, args ...any) error {
 	if c.db == nil {
 		return ErrPgNotInit
 	}
-	if err := c.db.QueryRow(query, args...).Scan(dest); err != nil {
+	trace := c.startTrace("QueryOne", query)
+	err := c.db.QueryRow(query, args...).Scan(dest)
+	if errors.Is(err, sql.ErrNoRows) {
+		trace(0, nil)
+	} else {
+		trace(1, err)
+	}
+	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return sql.ErrNoRows
 		}
@@ -249,10 +282,17 @@ func (c *PostgresClient) Exec(query string, args ...any) (sql.Result, error) {
 	if c.db == nil {
 		return nil, ErrPgNotInit
 	}
+	trace := c.startTrace("Exec", query)
 	result, err := c.db.Exec(query, args...)
 	if err != nil {
+		trace(-1, err)
 		return nil, fmt.Errorf("postgres: 执行 SQL 失败: %w", err)
 	}
+	n, rowsErr := result.RowsAffected()
+	if rowsErr != nil {
+		n = -1
+	}
+	trace(n, nil)
 	return result, nil
 }
 
@@ -304,9 +344,25 @@ type BatchInsertResult struct {
 	Errors       []error // 错误列表（最多记录 maxBatchErrors 条）
 }
 
+// successRows 返回成功插入的行数，r 为 nil 时返回 0。
+func (r *BatchInsertResult) successRows() int64 {
+	if r == nil {
+		return 0
+	}
+	return r.SuccessCount
+}
+
 // BatchInsert 在单个事务中批量插入数据（严格模式）。
 // 任意一条失败则整个事务回滚，所有数据都不会插入。
 func (c *PostgresClient) BatchInsert(query string, dataList [][]any) (int64, error) {
+	trace := c.startTrace("BatchInsert", query)
+	n, err := c.batchInsert(query, dataList)
+	trace(n, err)
+	return n, err
+}
+
+// batchInsert BatchInsert 的实现。
+func (c *PostgresClient) batchInsert(query string, dataList [][]any) (int64, error) {
 	if c.db == nil {
 		return 0, ErrPgNotInit
 	}
@@ -342,6 +398,14 @@ func (c *PostgresClient) BatchInsert(query string, dataList [][]any) (int64, err
 // BatchInsertTolerant 逐条插入数据（容错模式，无事务）。
 // 单条失败不影响其他条目，最终返回成功/失败统计。
 func (c *PostgresClient) BatchInsertTolerant(query string, dataList [][]any) (*BatchInsertResult, error) {
+	trace := c.startTrace("BatchInsertTolerant", query)
+	res, err := c.batchInsertTolerant(query, dataList)
+	trace(res.successRows(), err)
+	return res, err
+}
+
+// batchInsertTolerant BatchInsertTolerant 的实现。
+func (c *PostgresClient) batchInsertTolerant(query string, dataList [][]any) (*BatchInsertResult, error) {
 	if c.db == nil {
 		return nil, ErrPgNotInit
 	}
@@ -377,6 +441,14 @@ func (c *PostgresClient) BatchInsertTolerant(query string, dataList [][]any) (*B
 // 将 dataList 按 batchSize 分批，每批使用独立事务；
 // 单批失败不影响其他批次。batchSize <= 0 时默认 100。
 func (c *PostgresClient) BatchInsertTolerantWithTx(query string, dataList [][]any, batchSize int) (*BatchInsertResult, error) {
+	trace := c.startTrace("BatchInsertTolerantWithTx", query)
+	res, err := c.batchInsertTolerantWithTx(query, dataList, batchSize)
+	trace(res.successRows(), err)
+	return res, err
+}
+
+// batchInsertTolerantWithTx BatchInsertTolerantWithTx 的实现。
+func (c *PostgresClient) batchInsertTolerantWithTx(query string, dataList [][]any, batchSize int) (*BatchInsertResult, error) {
 	if c.db == nil {
 		return nil, ErrPgNotInit
 	}
@@ -436,7 +508,7 @@ func (c *PostgresClient) execBatch(query string, batchData [][]any, batchNum int
 				return 0, 0, fmt.Errorf("批次 %d 第 %d 条死锁，批次已回滚: %w", batchNum, i+1, err)
 			}
 			failCount++
-			logger.Warnf("postgres: 批次 %d 第 %d 条插入失败: %v", batchNum, i+1, err)
+			pgLog.Warnf("postgres: 批次 %d 第 %d 条插入失败: %v", batchNum, i+1, err)
 			continue
 		}
 		n, _ := execResult.RowsAffected()
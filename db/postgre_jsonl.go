@@ -0,0 +1,162 @@
+package db
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// ---------------------------------------------------------------------------
+// 小表导出 / 导入（JSON Lines）
+// ---------------------------------------------------------------------------
+//
+// 用于在环境之间迁移字典表、配置表等小表（无需 pg_dump 权限）。每行一个 JSON 对象，key 为列名；
+// 时间以 RFC 3339 格式保存，numeric / json / text 等列以字符串保存，bytea 列以 PostgreSQL 的十六进制格式（"\x..."）保存，
+// 导入时由 PostgreSQL 按目标列类型转换。
+
+// ExportTableJSONL 将表的全部行以 JSON Lines 格式写入 w，返回导出的行数。table 可带 schema，如 "public.dict_city"。
+//
+// 用法：
+//
+//	f, _ := os.Create("dict_city.jsonl")
+//	defer f.Close()
+//	n, err := pg.ExportTableJSONL("dict_city", f)
+func (c *PostgresClient) ExportTableJSONL(table string, w io.Writer) (int64, error) {
+	schema, name := splitTableName(table)
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	n, err := c.QueryEachMap("SELECT * FROM "+qualifiedIdent(schema, name), func(row map[string]any) error {
+		for k, v := range row {
+			if b, ok := v.([]byte); ok {
+				row[k] = `\x` + hex.EncodeToString(b) // bytea 的文本输入格式，导入时原样还原
+			}
+		}
+		return enc.Encode(row)
+	})
+	if err != nil {
+		return n, fmt.Errorf("postgres: 导出表 %s 失败: %w", table, err)
+	}
+	return n, nil
+}
+
+// ImportTableJSONL 读取 ExportTableJSONL 格式的数据并插入表中，返回插入的行数。
+// 列以第一行的 key 为准，后续行缺少的列插入 NULL，出现未知列时报错。
+// 每 batchSize 行（<= 0 时默认 500）一个事务，通过 BatchInsert 插入；某批失败时返回错误，之前的批次已提交。
+//
+// 用法：
+//
+//	f, _ := os.Open("dict_city.jsonl")
+//	defer f.Close()
+//	n, err := pg.ImportTableJSONL("dict_city", f, 0)
+func (c *PostgresClient) ImportTableJSONL(table string, r io.Reader, batchSize int) (int64, error) {
+	if c.db == nil {
+		return 0, ErrPgNotInit
+	}
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	var (
+		cols  []string
+		index map[string]int
+		query string
+		batch [][]any
+		total int64
+		line  int
+	)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		n, err := c.BatchInsert(query, batch)
+		if err != nil {
+			return fmt.Errorf("postgres: 导入表 %s 失败（已导入 %d 行）: %w", table, total, err)
+		}
+		total += n
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		var row map[string]any
+		err := dec.Decode(&row)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line++
+		if err != nil {
+			return total, fmt.Errorf("postgres: 解析第 %d 行失败: %w", line, err)
+		}
+
+		if cols == nil {
+			cols, index, query = jsonlInsertQuery(table, row)
+		}
+		args := make([]any, len(cols))
+		for k, v := range row {
+			i, ok := index[k]
+			if !ok {
+				return total, fmt.Errorf("postgres: 第 %d 行包含未知列 %q", line, k)
+			}
+			if args[i], err = jsonlValue(v); err != nil {
+				return total, fmt.Errorf("postgres: 第 %d 行列 %q: %w", line, k, err)
+			}
+		}
+
+		batch = append(batch, args)
+		if len(batch) >= batchSize {
+			if err := flush(); err != nil {
+				return total, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return total, err
+	}
+	return total, nil
+}
+
+// jsonlInsertQuery 以 row 的 key（按字典序）为列构建 INSERT 语句，返回列名、列名到下标的映射和语句。
+func jsonlInsertQuery(table string, row map[string]any) ([]string, map[string]int, string) {
+	cols := make([]string, 0, len(row))
+	for k := range row {
+		cols = append(cols, k)
+	}
+	slices.Sort(cols)
+
+	index := make(map[string]int, len(cols))
+	quoted := make([]string, len(cols))
+	placeholders := make([]string, len(cols))
+	for i, col := range cols {
+		index[col] = i
+		quoted[i] = quoteIdent(col)
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+	schema, name := splitTableName(table)
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		qualifiedIdent(schema, name), strings.Join(quoted, ", "), strings.Join(placeholders, ", "))
+	return cols, index, query
+}
+
+// jsonlValue 将 JSON 解码出的值转换为 SQL 参数：数字保持原始文本，对象 / 数组重新编码为 JSON 字符串。
+func jsonlValue(v any) (any, error) {
+	switch t := v.(type) {
+	case json.Number:
+		return t.String(), nil
+	case map[string]any, []any:
+		b, err := json.Marshal(t)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	default:
+		return v, nil
+	}
+}
@@ -0,0 +1,137 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// 按月分区管理
+// ---------------------------------------------------------------------------
+
+// monthlyPartitionLayout 月分区表名后缀格式，如 metrics_202610。
+const monthlyPartitionLayout = "200601"
+
+// EnsureMonthlyPartition 确保 parentTable 包含 t 所在月份的分区，不存在则创建。
+// 分区表名为 "<父表名>_YYYYMM"，与父表位于同一 schema，范围为 [当月 1 日, 次月 1 日)。
+// 月份按 t 自身的时区计算；分区键为 timestamptz 时边界按数据库会话时区解释。
+// parentTable 支持 "schema.table" 形式，父表需以 PARTITION BY RANGE (时间列) 创建。
+//
+// 用法：
+//
+//	// 定时任务中提前创建本月和下月的分区
+//	now := time.Now()
+//	_ = pg.EnsureMonthlyPartition("metrics", now)
+//	_ = pg.EnsureMonthlyPartition("metrics", now.AddDate(0, 1, 0))
+func (c *PostgresClient) EnsureMonthlyPartition(parentTable string, t time.Time) error {
+	if c.db == nil {
+		return ErrPgNotInit
+	}
+	schema, table := splitTableName(parentTable)
+	if table == "" {
+		return fmt.Errorf("postgres: 父表名不能为空")
+	}
+
+	from := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	to := from.AddDate(0, 1, 0)
+	partition := table + "_" + from.Format(monthlyPartitionLayout)
+
+	// DDL 不支持参数化查询，表名统一经 quoteIdent 转义
+	query := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s PARTITION OF %s FOR VALUES FROM ('%s') TO ('%s')",
+		qualifiedIdent(schema, partition), qualifiedIdent(schema, table),
+		from.Format(time.DateOnly), to.Format(time.DateOnly))
+	if _, err := c.db.Exec(query); err != nil {
+		return fmt.Errorf("postgres: 创建分区 [%s] 失败: %w", partition, err)
+	}
+
+	pgLog.Debugf("postgres: 分区 [%s] 已就绪，范围 [%s, %s)", partition, from.Format(time.DateOnly), to.Format(time.DateOnly))
+	return nil
+}
+
+// DropPartitionsOlderThan 删除 parentTable 下整月数据都早于 cutoff 的月分区（即次月 1 日 <= cutoff），
+// 返回被删除的分区表名。只处理符合 "<父表名>_YYYYMM" 命名的分区，默认分区及其他分区不受影响。
+// 任一分区删除失败时立即返回，已删除的分区名仍会返回。
+//
+// 用法：
+//
+//	// 只保留最近 6 个月的数据
+//	dropped, err := pg.DropPartitionsOlderThan("metrics", time.Now().AddDate(0, -6, 0))
+func (c *PostgresClient) DropPartitionsOlderThan(parentTable string, cutoff time.Time) ([]string, error) {
+	if c.db == nil {
+		return nil, ErrPgNotInit
+	}
+	schema, table := splitTableName(parentTable)
+	if table == "" {
+		return nil, fmt.Errorf("postgres: 父表名不能为空")
+	}
+
+	const query = `SELECT n.nspname, c.relname
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE i.inhparent = $1::regclass
+		ORDER BY c.relname`
+	rows, err := c.db.Query(query, qualifiedIdent(schema, table))
+	if err != nil {
+		return nil, fmt.Errorf("postgres: 查询 [%s] 的分区失败: %w", parentTable, err)
+	}
+
+	type partition struct{ schema, name string }
+	var expired []partition
+	prefix := table + "_"
+	for rows.Next() {
+		var p partition
+		if err = rows.Scan(&p.schema, &p.name); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("postgres: 读取分区信息失败: %w", err)
+		}
+		suffix, ok := strings.CutPrefix(p.name, prefix)
+		if !ok || len(suffix) != len(monthlyPartitionLayout) {
+			continue
+		}
+		month, parseErr := time.ParseInLocation(monthlyPartitionLayout, suffix, cutoff.Location())
+		if parseErr != nil {
+			continue
+		}
+		if !month.AddDate(0, 1, 0).After(cutoff) {
+			expired = append(expired, p)
+		}
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgres: 遍历分区失败: %w", err)
+	}
+
+	dropped := make([]string, 0, len(expired))
+	for _, p := range expired {
+		if _, err = c.db.Exec("DROP TABLE IF EXISTS " + qualifiedIdent(p.schema, p.name)); err != nil {
+			return dropped, fmt.Errorf("postgres: 删除分区 [%s] 失败: %w", p.name, err)
+		}
+		pgLog.Infof("postgres: 已删除过期分区 [%s.%s]", p.schema, p.name)
+		dropped = append(dropped, p.name)
+	}
+	return dropped, nil
+}
+
+// splitTableName 将 "schema.table" 拆分为 schema 和表名，未指定 schema 时返回空 schema。
+func splitTableName(name string) (schema, table string) {
+	name = strings.TrimSpace(name)
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return "", name
+}
+
+// qualifiedIdent 生成带引号的（可选 schema 限定的）标识符。
+func qualifiedIdent(schema, name string) string {
+	if schema == "" {
+		return quoteIdent(name)
+	}
+	return quoteIdent(schema) + "." + quoteIdent(name)
+}
+
+// quoteIdent 按 PostgreSQL 规则为标识符加双引号，内部的双引号转义为两个。
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,195 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// 运行状态检查（基于 pg_stat_* 视图，便于排查问题时直接调用）
+// ---------------------------------------------------------------------------
+
+// TableSizeInfo 描述一张用户表的空间占用与死元组情况。
+type TableSizeInfo struct {
+	Schema      string
+	Table       string
+	TotalBytes  int64     // 表 + 索引 + TOAST 总大小
+	TableBytes  int64     // 表数据大小（不含索引）
+	IndexBytes  int64     // 索引总大小
+	LiveRows    int64     // 估算的存活行数
+	DeadRows    int64     // 估算的死元组数
+	LastVacuum  time.Time // 最近一次 VACUUM（手动或自动），从未执行时为零值
+	LastAnalyze time.Time // 最近一次 ANALYZE（手动或自动），从未执行时为零值
+}
+
+// DeadRatio 返回死元组占比（0~1），用于粗略判断表膨胀程度。
+func (t TableSizeInfo) DeadRatio() float64 {
+	total := t.LiveRows + t.DeadRows
+	if total <= 0 {
+		return 0
+	}
+	return float64(t.DeadRows) / float64(total)
+}
+
+// IndexUsageInfo 描述一个用户索引的大小与使用情况。
+type IndexUsageInfo struct {
+	Schema     string
+	Table      string
+	Index      string
+	SizeBytes  int64
+	Scans      int64 // 索引扫描次数，长期为 0 说明索引可能无用
+	TupRead    int64 // 通过索引扫描返回的索引项数
+	TupFetched int64 // 通过索引扫描取到的存活表行数
+	Unique     bool  // 是否唯一索引（含主键），此类索引即使未被扫描也不应删除
+}
+
+// QueryInfo 描述一条正在执行的查询。
+type QueryInfo struct {
+	PID             int
+	User            string
+	Database        string
+	ApplicationName string
+	ClientAddr      string
+	State           string
+	WaitEventType   string
+	WaitEvent       string
+	QueryStart      time.Time
+	Duration        time.Duration
+	Query           string
+}
+
+// TableSizes 返回当前数据库所有用户表的空间占用，按总大小降序排列。
+//
+// 用法：
+//
+//	tables, _ := pg.TableSizes()
+//	for _, t := range tables[:min(10, len(tables))] {
+//	    fmt.Printf("%s.%s total=%d dead=%.1f%%\n", t.Schema, t.Table, t.TotalBytes, t.DeadRatio()*100)
+//	}
+func (c *PostgresClient) TableSizes() ([]TableSizeInfo, error) {
+	if c.db == nil {
+		return nil, ErrPgNotInit
+	}
+
+	const query = `SELECT schemaname, relname,
+			pg_total_relation_size(relid),
+			pg_relation_size(relid),
+			pg_indexes_size(relid),
+			n_live_tup, n_dead_tup,
+			GREATEST(last_vacuum, last_autovacuum),
+			GREATEST(last_analyze, last_autoanalyze)
+		FROM pg_stat_user_tables
+		ORDER BY pg_total_relation_size(relid) DESC`
+	rows, err := c.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: 查询表大小失败: %w", err)
+	}
+	defer rows.Close()
+
+	var result []TableSizeInfo
+	for rows.Next() {
+		var (
+			t               TableSizeInfo
+			vacuum, analyze sql.NullTime
+		)
+		if err = rows.Scan(&t.Schema, &t.Table, &t.TotalBytes, &t.TableBytes, &t.IndexBytes,
+			&t.LiveRows, &t.DeadRows, &vacuum, &analyze); err != nil {
+			return nil, fmt.Errorf("postgres: 读取表大小失败: %w", err)
+		}
+		t.LastVacuum = vacuum.Time
+		t.LastAnalyze = analyze.Time
+		result = append(result, t)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgres: 遍历表大小失败: %w", err)
+	}
+	return result, nil
+}
+
+// IndexUsageStats 返回当前数据库所有用户索引的使用统计，按扫描次数升序、大小降序排列，
+// 排在最前面的是"占空间又没人用"的索引。统计值自上次 pg_stat_reset 起累计。
+func (c *PostgresClient) IndexUsageStats() ([]IndexUsageInfo, error) {
+	if c.db == nil {
+		return nil, ErrPgNotInit
+	}
+
+	const query = `SELECT s.schemaname, s.relname, s.indexrelname,
+			pg_relation_size(s.indexrelid),
+			s.idx_scan, s.idx_tup_read, s.idx_tup_fetch,
+			i.indisunique
+		FROM pg_stat_user_indexes s
+		JOIN pg_index i ON i.indexrelid = s.indexrelid
+		ORDER BY s.idx_scan ASC, pg_relation_size(s.indexrelid) DESC`
+	rows, err := c.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: 查询索引使用情况失败: %w", err)
+	}
+	defer rows.Close()
+
+	var result []IndexUsageInfo
+	for rows.Next() {
+		var idx IndexUsageInfo
+		if err = rows.Scan(&idx.Schema, &idx.Table, &idx.Index, &idx.SizeBytes,
+			&idx.Scans, &idx.TupRead, &idx.TupFetched, &idx.Unique); err != nil {
+			return nil, fmt.Errorf("postgres: 读取索引使用情况失败: %w", err)
+		}
+		result = append(result, idx)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgres: 遍历索引使用情况失败: %w", err)
+	}
+	return result, nil
+}
+
+// LongRunningQueries 返回执行时间超过 threshold 的非空闲查询（不含当前连接），按耗时降序排列。
+// 需要 pg_read_all_stats 权限或超级用户才能看到其他用户的查询文本。
+//
+// 用法：
+//
+//	queries, _ := pg.LongRunningQueries(30 * time.Second)
+//	for _, q := range queries {
+//	    fmt.Printf("pid=%d %s %s\n", q.PID, q.Duration, q.Query)
+//	}
+func (c *PostgresClient) LongRunningQueries(threshold time.Duration) ([]QueryInfo, error) {
+	if c.db == nil {
+		return nil, ErrPgNotInit
+	}
+
+	const query = `SELECT pid,
+			COALESCE(usename, ''), COALESCE(datname, ''), COALESCE(application_name, ''),
+			COALESCE(host(client_addr), ''), COALESCE(state, ''),
+			COALESCE(wait_event_type, ''), COALESCE(wait_event, ''),
+			query_start,
+			EXTRACT(EPOCH FROM (now() - query_start))::float8,
+			COALESCE(query, '')
+		FROM pg_stat_activity
+		WHERE state IS NOT NULL AND state <> 'idle'
+			AND pid <> pg_backend_pid()
+			AND query_start IS NOT NULL
+			AND now() - query_start > make_interval(secs => $1)
+		ORDER BY query_start ASC`
+	rows, err := c.db.Query(query, threshold.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("postgres: 查询长时间运行的 SQL 失败: %w", err)
+	}
+	defer rows.Close()
+
+	var result []QueryInfo
+	for rows.Next() {
+		var (
+			q       QueryInfo
+			seconds float64
+		)
+		if err = rows.Scan(&q.PID, &q.User, &q.Database, &q.ApplicationName, &q.ClientAddr, &q.State,
+			&q.WaitEventType, &q.WaitEvent, &q.QueryStart, &seconds, &q.Query); err != nil {
+			return nil, fmt.Errorf("postgres: 读取查询信息失败: %w", err)
+		}
+		q.Duration = time.Duration(seconds * float64(time.Second))
+		result = append(result, q)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgres: 遍历查询信息失败: %w", err)
+	}
+	return result, nil
+}
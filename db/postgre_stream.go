@@ -0,0 +1,183 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+)
+
+// ---------------------------------------------------------------------------
+// 流式查询（逐行处理，不在内存中保留整个结果集）
+// ---------------------------------------------------------------------------
+
+// ErrStopEach 由 QueryEach 的回调返回，表示提前结束遍历；QueryEach 此时返回 nil 错误。
+var ErrStopEach = errors.New("postgres: 停止遍历")
+
+// QueryEach 执行查询并逐行调用 fn，fn 通过 scan 将当前行扫描到目标变量，返回处理的行数。
+// 结果按行从连接中读取，适合导出百万行级别的数据；fn 返回 ErrStopEach 时提前结束，返回其他错误时中止并返回该错误。
+// 遍历结束后会检查 rows.Err()，网络中断等导致的结果不完整不会被当作正常结束。
+//
+// 用法：
+//
+//	n, err := pg.QueryEach("SELECT id, name FROM users WHERE created_at > $1", func(scan func(dest ...any) error) error {
+//	    var id int64
+//	    var name string
+//	    if err := scan(&id, &name); err != nil {
+//	        return err
+//	    }
+//	    return csvWriter.Write([]string{strconv.FormatInt(id, 10), name})
+//	}, since)
+func (c *PostgresClient) QueryEach(query string, fn func(scan func(dest ...any) error) error, args ...any) (int64, error) {
+	trace := c.startTrace("QueryEach", query)
+	n, err := c.queryEach(query, fn, args...)
+	trace(n, err)
+	return n, err
+}
+
+// queryEach QueryEach 的实现。
+func (c *PostgresClient) queryEach(query string, fn func(scan func(dest ...any) error) error, args ...any) (int64, error) {
+	if c.db == nil {
+		return 0, ErrPgNotInit
+	}
+	rows, err := c.db.Query(query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("postgres: 查询失败: %w", err)
+	}
+	defer rows.Close()
+
+	var n int64
+	for rows.Next() {
+		if err := fn(rows.Scan); err != nil {
+			if errors.Is(err, ErrStopEach) {
+				return n, nil
+			}
+			return n, fmt.Errorf("postgres: 处理第 %d 行失败: %w", n+1, err)
+		}
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return n, fmt.Errorf("postgres: 读取查询结果失败（已处理 %d 行）: %w", n, err)
+	}
+	return n, nil
+}
+
+// QueryEachMap 同 QueryEach，每行以 列名 → 值 的 map 传给 fn，适合列不固定的导出。
+// 驱动以 []byte 返回的文本类值（如 json、numeric、uuid 列）转换为 string，bytea 列保持 []byte。
+func (c *PostgresClient) QueryEachMap(query string, fn func(row map[string]any) error, args ...any) (int64, error) {
+	trace := c.startTrace("QueryEachMap", query)
+	n, err := c.queryEachMap(query, fn, args...)
+	trace(n, err)
+	return n, err
+}
+
+// queryEachMap QueryEachMap 的实现。
+func (c *PostgresClient) queryEachMap(query string, fn func(row map[string]any) error, args ...any) (int64, error) {
+	if c.db == nil {
+		return 0, ErrPgNotInit
+	}
+	rows, err := c.db.Query(query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("postgres: 查询失败: %w", err)
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return 0, fmt.Errorf("postgres: 获取列信息失败: %w", err)
+	}
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return 0, fmt.Errorf("postgres: 获取列信息失败: %w", err)
+	}
+	binary := make([]bool, len(cols)) // bytea 列，值为二进制数据，不转换为 string
+	for i, t := range types {
+		binary[i] = t.DatabaseTypeName() == "BYTEA"
+	}
+	values := make([]any, len(cols))
+	ptrs := make([]any, len(cols))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+
+	var n int64
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return n, fmt.Errorf("postgres: 扫描第 %d 行失败: %w", n+1, err)
+		}
+		row := make(map[string]any, len(cols))
+		for i, col := range cols {
+			if b, ok := values[i].([]byte); ok && !binary[i] {
+				row[col] = string(b)
+			} else {
+				row[col] = values[i]
+			}
+		}
+		if err := fn(row); err != nil {
+			if errors.Is(err, ErrStopEach) {
+				return n, nil
+			}
+			return n, fmt.Errorf("postgres: 处理第 %d 行失败: %w", n+1, err)
+		}
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return n, fmt.Errorf("postgres: 读取查询结果失败（已处理 %d 行）: %w", n, err)
+	}
+	return n, nil
+}
+
+// QueryRows 执行查询并返回逐行产出的迭代器，scan 负责将一行转换为 T。
+// 出错时产出一次零值与错误后结束；循环中 break 会自动关闭结果集。
+//
+// 用法：
+//
+//	for u, err := range db.QueryRows(pg, "SELECT id, name FROM users", func(scan func(dest ...any) error) (User, error) {
+//	    var u User
+//	    err := scan(&u.ID, &u.Name)
+//	    return u, err
+//	}) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    process(u)
+//	}
+func QueryRows[T any](c *PostgresClient, query string, scan func(scan func(dest ...any) error) (T, error), args ...any) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		if c.db == nil {
+			yield(zero, ErrPgNotInit)
+			return
+		}
+		var (
+			n       int64
+			iterErr error
+		)
+		trace := c.startTrace("QueryRows", query)
+		defer func() { trace(n, iterErr) }()
+
+		rows, err := c.db.Query(query, args...)
+		if err != nil {
+			iterErr = fmt.Errorf("postgres: 查询失败: %w", err)
+			yield(zero, iterErr)
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			v, err := scan(rows.Scan)
+			if err != nil {
+				iterErr = fmt.Errorf("postgres: 扫描第 %d 行失败: %w", n+1, err)
+				yield(zero, iterErr)
+				return
+			}
+			if !yield(v, nil) {
+				return
+			}
+			n++
+		}
+		if err := rows.Err(); err != nil {
+			iterErr = fmt.Errorf("postgres: 读取查询结果失败（已处理 %d 行）: %w", n, err)
+			yield(zero, iterErr)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pylemonorg/gotools/strutil"
+)
+
+// ---------------------------------------------------------------------------
+// SQL 调用追踪
+// ---------------------------------------------------------------------------
+
+// QueryTrace 一次 SQL 调用的追踪信息。
+type QueryTrace struct {
+	Op       string        // 方法名，如 "Query"、"Exec"、"BatchInsert"、"QueryEach"
+	Query    string        // SQL 语句（不含参数值）
+	Duration time.Duration // 调用耗时；QueryEach / QueryRows 等流式方法包含逐行处理的时间
+	Rows     int64         // 影响或处理的行数，未知（如 Query 返回 *sql.Rows）时为 -1
+	Err      error         // 调用失败时的错误
+}
+
+// String 返回单行描述，如 "Exec rows=3 12ms: UPDATE users SET ..."（SQL 超过 200 个字符时截断）。
+func (t QueryTrace) String() string {
+	var sb strings.Builder
+	sb.WriteString(t.Op)
+	if t.Rows >= 0 {
+		fmt.Fprintf(&sb, " rows=%d", t.Rows)
+	}
+	fmt.Fprintf(&sb, " %v", t.Duration.Round(time.Millisecond))
+	if t.Err != nil {
+		fmt.Fprintf(&sb, " err=%v", t.Err)
+	}
+	query := strings.Join(strings.Fields(t.Query), " ")
+	if utf8.RuneCountInString(query) > 200 {
+		query = strutil.TruncateRunes(query, 200) + "..."
+	}
+	sb.WriteString(": " + query)
+	return sb.String()
+}
+
+// QueryTraceHook 接收每次 SQL 调用的追踪信息，在调用返回后同步执行，应避免阻塞。
+type QueryTraceHook func(QueryTrace)
+
+// LogQueryTrace 以 debug 级别记录追踪信息的 QueryTraceHook，可通过 logger.SetModuleLevel("postgres", logger.LevelDebug) 打开。
+func LogQueryTrace(t QueryTrace) {
+	pgLog.Debugf("postgres: %s", t)
+}
+
+// WithTrace 返回对每次 SQL 调用执行 hook 的 PostgresClient 副本（共享连接池），hook 为 nil 时关闭追踪。
+// 覆盖 Insert / InsertWithReturning / Query / QueryOne / Exec（含 Update / Delete）/ BatchInsert* 与
+// QueryEach / QueryEachMap / QueryRows；GetDB 与 BeginTx 返回的对象上的调用不会被追踪。
+//
+// 用法：
+//
+//	pg = pg.WithTrace(db.LogQueryTrace)
+//
+//	// 或接入自定义的指标 / 追踪系统
+//	pg = pg.WithTrace(func(t db.QueryTrace) {
+//	    metrics.ObserveSQL(t.Op, t.Err == nil, t.Duration)
+//	})
+func (c *PostgresClient) WithTrace(hook QueryTraceHook) *PostgresClient {
+	cp := *c
+	cp.trace = hook
+	return &cp
+}
+
+// startTrace 开始记录一次 SQL 调用，返回在调用结束后传入行数与错误的函数；未启用追踪时返回空操作。
+func (c *PostgresClient) startTrace(op, query string) func(rows int64, err error) {
+	if c.trace == nil {
+		return func(int64, error) {}
+	}
+	start := time.Now()
+	return func(rows int64, err error) {
+		c.trace(QueryTrace{Op: op, Query: query, Duration: time.Since(start), Rows: rows, Err: err})
+	}
+}
@@ -0,0 +1,145 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ---------------------------------------------------------------------------
+// 时间窗口计数器
+// ---------------------------------------------------------------------------
+//
+// 按时间窗口（分钟 / 小时 / 天等）分桶计数，每个桶一个 key：<prefix>:<桶起始时间>，如
+// "stats:pv:2026021615"（小时桶）、"stats:pv:20260216"（天桶）。桶按本地时区（time.Local）对齐，
+// 天桶从本地 00:00 开始。自增与设置过期时间在同一个 Lua 脚本中完成，不会出现 key 永不过期的竞态。
+
+// defaultWindowRetain 窗口计数 key 默认保留的窗口数（小时桶约 2 天，天桶约 48 天）。
+const defaultWindowRetain = 48
+
+// WindowCount 一个时间窗口的计数。
+type WindowCount struct {
+	Start time.Time // 窗口起始时间（本地时区）
+	Count int64
+}
+
+// incrWindowScript 自增并在 key 没有过期时间时设置过期时间（毫秒）。
+var incrWindowScript = redis.NewScript(`
+local v = redis.call('INCRBY', KEYS[1], ARGV[1])
+if redis.call('PTTL', KEYS[1]) < 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return v
+`)
+
+// IncrWindow 将当前时间窗口的计数加 1，返回加后的值。key 在窗口结束后保留 48 个窗口再过期。
+//
+// 用法：
+//
+//	n, err := redisClient.IncrWindow("stats:api:calls", time.Hour)               // 本小时调用次数
+//	counts, err := redisClient.GetWindowCounts("stats:api:calls", time.Hour, 24) // 最近 24 小时
+func (rc *RedisClient) IncrWindow(keyPrefix string, window time.Duration) (int64, error) {
+	return rc.IncrWindowBy(keyPrefix, window, 1, 0)
+}
+
+// IncrWindowBy 将当前时间窗口的计数加 n，返回加后的值。window 必须为整秒（如 time.Minute、24*time.Hour）。
+// key 在窗口结束后再保留 retain 个窗口后过期（retain <= 0 时默认 48），GetWindowCounts 只能查到保留期内的窗口。
+func (rc *RedisClient) IncrWindowBy(keyPrefix string, window time.Duration, n int64, retain int) (int64, error) {
+	if rc.GetClient() == nil {
+		return 0, ErrRedisNotInit
+	}
+	if err := checkWindow(window); err != nil {
+		return 0, err
+	}
+	if retain <= 0 {
+		retain = defaultWindowRetain
+	}
+
+	now := time.Now()
+	start := windowStart(now, window)
+	ttl := start.Add(window * time.Duration(retain+1)).Sub(now)
+	key := WindowKey(keyPrefix, window, now)
+
+	v, err := incrWindowScript.Run(rc.ctx, rc.GetClient(), []string{key}, n, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("redis: 窗口计数 [%s] 自增失败: %w", key, err)
+	}
+	return v, nil
+}
+
+// GetWindowCounts 返回包含当前窗口在内的最近 lastN 个窗口的计数（按时间升序），没有数据的窗口计数为 0。
+func (rc *RedisClient) GetWindowCounts(keyPrefix string, window time.Duration, lastN int) ([]WindowCount, error) {
+	if rc.GetClient() == nil {
+		return nil, ErrRedisNotInit
+	}
+	if err := checkWindow(window); err != nil {
+		return nil, err
+	}
+	if lastN <= 0 {
+		return nil, nil
+	}
+
+	current := windowStart(time.Now(), window)
+	counts := make([]WindowCount, lastN)
+	keys := make([]string, lastN)
+	for i := range counts {
+		start := current.Add(-window * time.Duration(lastN-1-i))
+		counts[i].Start = start
+		keys[i] = WindowKey(keyPrefix, window, start)
+	}
+
+	values, err := rc.GetClient().MGet(rc.ctx, keys...).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("redis: 读取窗口计数 [%s] 失败: %w", keyPrefix, err)
+	}
+	for i, v := range values {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		if counts[i].Count, err = strconv.ParseInt(s, 10, 64); err != nil {
+			return nil, fmt.Errorf("redis: 窗口计数 [%s] 不是整数: %q", keys[i], s)
+		}
+	}
+	return counts, nil
+}
+
+// checkWindow 校验计数窗口：必须为整秒，不足 1 秒或带小数秒的窗口会与相邻窗口共用同一个秒级 key。
+func checkWindow(window time.Duration) error {
+	if window < time.Second || window%time.Second != 0 {
+		return fmt.Errorf("redis: 计数窗口必须为整秒且不小于 1s: %v", window)
+	}
+	return nil
+}
+
+// WindowKey 返回 t 所在时间窗口的计数 key。时间部分的精度随窗口大小变化：
+// 整天 "20060102"，整小时 "2006010215"，整分钟 "200601021504"，整秒 "20060102150405"，
+// 其他（IncrWindow 等不接受，仅直接调用时）"20060102150405.000000000"。
+func WindowKey(keyPrefix string, window time.Duration, t time.Time) string {
+	start := windowStart(t, window)
+	var layout string
+	switch {
+	case window%(24*time.Hour) == 0:
+		layout = "20060102"
+	case window%time.Hour == 0:
+		layout = "2006010215"
+	case window%time.Minute == 0:
+		layout = "200601021504"
+	case window%time.Second == 0:
+		layout = "20060102150405"
+	default:
+		layout = "20060102150405.000000000"
+	}
+	return keyPrefix + ":" + start.Format(layout)
+}
+
+// windowStart 返回 t 所在窗口的起始时间，按本地时区对齐（天窗口从本地 00:00 开始）。
+func windowStart(t time.Time, window time.Duration) time.Time {
+	t = t.In(time.Local)
+	_, offset := t.Zone()
+	shift := time.Duration(offset) * time.Second
+	return t.Add(shift).Truncate(window).Add(-shift)
+}
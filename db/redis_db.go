@@ -2,13 +2,17 @@ package db
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
-	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pylemonorg/gotools/logger"
+	"github.com/pylemonorg/gotools/retry"
+	"github.com/pylemonorg/gotools/versionutil"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -19,6 +23,9 @@ var (
 	ErrRedisNoParams  = errors.New("redis: 连接参数未设置，无法重连")
 )
 
+// redisLog Redis 模块日志，可通过 logger.SetModuleLevel("redis", ...) 单独控制级别。
+var redisLog = logger.Module("redis")
+
 // connectionKeywords 用于判断连接类错误的关键词。
 var connectionKeywords = []string{
 	"connection", "timeout", "eof", "broken pipe",
@@ -28,18 +35,49 @@ var connectionKeywords = []string{
 }
 
 // RedisClient 封装了 go-redis 客户端，内部管理 context，提供便捷的 Redis 操作方法。
+// 当前连接保存在 atomic.Pointer 中，Reconnect 建立新连接后原子替换，并发调用 GetClient 的操作不会取到 nil。
 type RedisClient struct {
-	client *redis.Client
+	client atomic.Pointer[redis.Client]
 	ctx    context.Context
 	params *RedisParams
+	shared *sharedRedis // 非 nil 表示由 GetOrCreateClient 创建的共享客户端
+
+	mu    sync.Mutex   // 保护 hooks，并串行化 Reconnect
+	hooks []redis.Hook // 通过 AddHook 添加的钩子，重连后重新添加到新连接
 }
 
 // RedisParams 定义 Redis 连接所需的参数。
+//
+// 连接云托管 Redis（要求 TLS + ACL 用户）：
+//
+//	client, err := db.NewRedisClient(&db.RedisParams{
+//	    Host:       "r-xxx.redis.rds.example.com",
+//	    Port:       6380,
+//	    Username:   "app",
+//	    Password:   os.Getenv("REDIS_PASSWORD"),
+//	    EnableTLS:  true,
+//	    ClientName: "order-service",
+//	})
 type RedisParams struct {
-	Host     string // 主机地址
-	Port     int    // 端口号
-	Password string // 密码（无密码传空串）
-	DB       int    // 数据库编号
+	Host       string      // 主机地址
+	Port       int         // 端口号
+	Username   string      // ACL 用户名（Redis 6+），为空时使用 default 用户
+	Password   string      // 密码（无密码传空串）
+	DB         int         // 数据库编号
+	EnableTLS  bool        // 启用 TLS，使用默认配置并按 Host 校验服务端证书
+	TLSConfig  *tls.Config // 自定义 TLS 配置（自签名 CA、客户端证书等），非 nil 时隐含启用 TLS
+	ClientName string      // 连接名（CLIENT SETNAME），便于在 CLIENT LIST 中识别来源服务
+}
+
+// tlsConfig 返回连接使用的 TLS 配置，未启用 TLS 时返回 nil。
+func (p *RedisParams) tlsConfig() *tls.Config {
+	if p.TLSConfig != nil {
+		return p.TLSConfig.Clone()
+	}
+	if p.EnableTLS {
+		return &tls.Config{ServerName: p.Host, MinVersion: tls.VersionTLS12}
+	}
+	return nil
 }
 
 // validateRedisParams 校验 Redis 连接参数的必填项。
@@ -63,8 +101,11 @@ func dialRedis(params *RedisParams) (*redis.Client, error) {
 
 	client := redis.NewClient(&redis.Options{
 		Addr:         addr,
+		Username:     params.Username,
 		Password:     params.Password,
 		DB:           params.DB,
+		ClientName:   params.ClientName,
+		TLSConfig:    params.tlsConfig(),
 		DialTimeout:  30 * time.Second,
 		ReadTimeout:  30 * time.Second,
 		WriteTimeout: 30 * time.Second,
@@ -92,16 +133,18 @@ func NewRedisClient(params *RedisParams) (*RedisClient, error) {
 		return nil, err
 	}
 
-	logger.Infof("redis: 连接成功 %s:%d db=%d", params.Host, params.Port, params.DB)
-	return &RedisClient{
-		client: client,
+	redisLog.Infof("redis: 连接成功 %s:%d db=%d tls=%t", params.Host, params.Port, params.DB, params.EnableTLS || params.TLSConfig != nil)
+	rc := &RedisClient{
 		ctx:    context.Background(),
 		params: params,
-	}, nil
+	}
+	rc.client.Store(client)
+	return rc, nil
 }
 
 // GetClient 返回底层 redis.Client，可用于执行未封装的高级操作。
-func (rc *RedisClient) GetClient() *redis.Client { return rc.client }
+// Reconnect 后返回新连接，长期持有 RedisClient 的模块应在每次操作时调用 GetClient，而不是缓存其返回值。
+func (rc *RedisClient) GetClient() *redis.Client { return rc.client.Load() }
 
 // GetContext 返回当前使用的 context。
 func (rc *RedisClient) GetContext() context.Context { return rc.ctx }
@@ -109,27 +152,55 @@ func (rc *RedisClient) GetContext() context.Context { return rc.ctx }
 // SetContext 替换内部 context（如需要超时控制等场景）。
 func (rc *RedisClient) SetContext(ctx context.Context) { rc.ctx = ctx }
 
+// AddHook 为底层客户端添加 go-redis 钩子（如追踪、指标），Reconnect 后自动添加到新连接。
+// 直接对 GetClient() 调用 AddHook 添加的钩子在重连后会丢失。
+func (rc *RedisClient) AddHook(hook redis.Hook) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.hooks = append(rc.hooks, hook)
+	if client := rc.GetClient(); client != nil {
+		client.AddHook(hook)
+	}
+}
+
 // GetParams 返回创建时使用的连接参数。
 func (rc *RedisClient) GetParams() *RedisParams { return rc.params }
 
-// Close 关闭 Redis 连接。
+// Close 关闭 Redis 连接。GetOrCreateClient 返回的共享客户端只减少引用计数，最后一个使用者 Close 时才断开。
 func (rc *RedisClient) Close() error {
-	if rc.client == nil {
-		return nil
+	if rc.shared != nil {
+		return releaseShared(rc)
+	}
+	if client := rc.GetClient(); client != nil {
+		return client.Close()
 	}
-	return rc.client.Close()
+	return nil
 }
 
 // Ping 测试当前连接是否可用。
 func (rc *RedisClient) Ping() error {
-	if rc.client == nil {
+	client := rc.GetClient()
+	if client == nil {
 		return ErrRedisNotInit
 	}
-	_, err := rc.client.Ping(rc.ctx).Result()
+	_, err := client.Ping(rc.ctx).Result()
 	return err
 }
 
-// Reconnect 关闭旧连接并使用原始参数重新建立连接。
+// HealthCheck 使用 ctx 执行 PING，用于就绪 / 存活探针（实现 healthcheck.Checker）。
+func (rc *RedisClient) HealthCheck(ctx context.Context) error {
+	client := rc.GetClient()
+	if client == nil {
+		return ErrRedisNotInit
+	}
+	if err := client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("redis: 健康检查失败: %w", err)
+	}
+	return nil
+}
+
+// Reconnect 使用原始参数重新建立连接，成功后替换当前连接并关闭旧连接；失败时保留旧连接不变。
+// 可与其它操作并发调用，多个 Reconnect 串行执行。
 // maxRetries <= 0 时默认 3 次，retryDelay <= 0 时默认 1s。
 func (rc *RedisClient) Reconnect(maxRetries int, retryDelay time.Duration) error {
 	if rc.params == nil {
@@ -142,28 +213,31 @@ func (rc *RedisClient) Reconnect(maxRetries int, retryDelay time.Duration) error
 		retryDelay = time.Second
 	}
 
-	// 关闭旧连接
-	if rc.client != nil {
-		rc.client.Close()
-		rc.client = nil
-	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
 
-	var lastErr error
-	for i := 0; i < maxRetries; i++ {
-		logger.Warnf("redis: 正在重连 (%d/%d)...", i+1, maxRetries)
-		newClient, err := dialRedis(rc.params)
-		if err != nil {
-			lastErr = err
-			if i < maxRetries-1 {
-				time.Sleep(retryDelay)
-			}
-			continue
-		}
-		rc.client = newClient
-		logger.Infof("redis: 重连成功")
-		return nil
+	attempts := 0
+	newClient, err := retry.DoValue(rc.ctx, &retry.Policy{
+		MaxAttempts:     maxRetries,
+		InitialInterval: retryDelay,
+		Multiplier:      1,
+	}, func(ctx context.Context) (*redis.Client, error) {
+		attempts++
+		redisLog.Warnf("redis: 正在重连 (%d/%d)...", attempts, maxRetries)
+		return dialRedis(rc.params)
+	})
+	if err != nil {
+		return fmt.Errorf("redis: 重连失败: %w", err)
+	}
+	for _, hook := range rc.hooks {
+		newClient.AddHook(hook)
 	}
-	return fmt.Errorf("redis: 重连失败（已重试 %d 次）: %w", maxRetries, lastErr)
+	// 先换上新连接再关闭旧连接，正在使用旧连接的操作返回连接已关闭错误，由调用方重试
+	if old := rc.client.Swap(newClient); old != nil {
+		old.Close()
+	}
+	redisLog.Infof("redis: 重连成功")
+	return nil
 }
 
 // ExecuteWithRetry 执行操作函数，遇到连接错误时自动重连并重试。
@@ -176,41 +250,35 @@ func (rc *RedisClient) ExecuteWithRetry(operation func() (any, error), maxRetrie
 		retryDelay = time.Second
 	}
 
-	var lastErr error
-	for i := 0; i < maxRetries; i++ {
+	policy := &retry.Policy{
+		MaxAttempts:     maxRetries,
+		InitialInterval: retryDelay,
+		Multiplier:      1,
+		// 非连接错误（含 redis.Nil）原样返回
+		RetryIf: isConnectionError,
+	}
+	result, err := retry.DoValue(rc.ctx, policy, func(ctx context.Context) (any, error) {
 		result, err := operation()
 		if err == nil {
 			return result, nil
 		}
-		// 非连接错误直接返回
 		if !isConnectionError(err) {
 			return nil, err
 		}
-		lastErr = err
-		logger.Warnf("redis: 操作遇到连接错误，尝试重连: %v", err)
+		redisLog.Warnf("redis: 操作遇到连接错误，尝试重连: %v", err)
 		if reconnErr := rc.Reconnect(maxRetries, retryDelay); reconnErr != nil {
-			return nil, fmt.Errorf("redis: 操作失败且重连失败: %w (重连: %v)", err, reconnErr)
-		}
-		if i < maxRetries-1 {
-			time.Sleep(retryDelay)
+			return nil, retry.Permanent(fmt.Errorf("redis: 操作失败且重连失败: %w (重连: %v)", err, reconnErr))
 		}
+		return nil, err
+	})
+	if errors.Is(err, retry.ErrRetriesExhausted) {
+		return nil, fmt.Errorf("redis: 操作失败: %w", err)
 	}
-	return nil, fmt.Errorf("redis: 操作失败（已重试 %d 次）: %w", maxRetries, lastErr)
+	return result, err
 }
 
-// isConnectionError 判断 err 是否为连接类错误。
-func isConnectionError(err error) bool {
-	if err == nil {
-		return false
-	}
-	errStr := strings.ToLower(err.Error())
-	for _, kw := range connectionKeywords {
-		if strings.Contains(errStr, kw) {
-			return true
-		}
-	}
-	return false
-}
+// isConnectionError 判断 err 是否为连接类错误（关键词不区分大小写）。
+var isConnectionError = retry.ErrorContains(connectionKeywords...)
 
 // ---------------------------------------------------------------------------
 // 基本操作
@@ -218,33 +286,33 @@ func isConnectionError(err error) bool {
 
 // Set 设置键值对，expiration 为 0 表示不过期。
 func (rc *RedisClient) Set(key string, value any, expiration time.Duration) error {
-	return rc.client.Set(rc.ctx, key, value, expiration).Err()
+	return rc.GetClient().Set(rc.ctx, key, value, expiration).Err()
 }
 
 // Get 获取 key 对应的值。
 func (rc *RedisClient) Get(key string) (string, error) {
-	return rc.client.Get(rc.ctx, key).Result()
+	return rc.GetClient().Get(rc.ctx, key).Result()
 }
 
 // Del 删除一个或多个 key，返回实际删除的数量。
 func (rc *RedisClient) Del(keys ...string) (int64, error) {
-	return rc.client.Del(rc.ctx, keys...).Result()
+	return rc.GetClient().Del(rc.ctx, keys...).Result()
 }
 
 // Exists 检查 key 是否存在，返回存在的数量。
 func (rc *RedisClient) Exists(keys ...string) (int64, error) {
-	return rc.client.Exists(rc.ctx, keys...).Result()
+	return rc.GetClient().Exists(rc.ctx, keys...).Result()
 }
 
 // Expire 为 key 设置过期时间。
 func (rc *RedisClient) Expire(key string, expiration time.Duration) (bool, error) {
-	return rc.client.Expire(rc.ctx, key, expiration).Result()
+	return rc.GetClient().Expire(rc.ctx, key, expiration).Result()
 }
 
 // TTL 获取 key 的剩余过期时间。
 // 返回 -1 表示永不过期，-2 表示 key 不存在。
 func (rc *RedisClient) TTL(key string) (time.Duration, error) {
-	return rc.client.TTL(rc.ctx, key).Result()
+	return rc.GetClient().TTL(rc.ctx, key).Result()
 }
 
 // ExpireIfNotSet 仅在 key 没有过期时间时设置（兼容所有 Redis 版本，需两次调用）。
@@ -261,7 +329,7 @@ func (rc *RedisClient) ExpireIfNotSet(key string, expiration time.Duration) (boo
 
 // ExpireNX 仅在 key 没有过期时间时设置（需要 Redis 7.0+，单次调用）。
 func (rc *RedisClient) ExpireNX(key string, expiration time.Duration) (bool, error) {
-	return rc.client.ExpireNX(rc.ctx, key, expiration).Result()
+	return rc.GetClient().ExpireNX(rc.ctx, key, expiration).Result()
 }
 
 // ---------------------------------------------------------------------------
@@ -270,22 +338,22 @@ func (rc *RedisClient) ExpireNX(key string, expiration time.Duration) (bool, err
 
 // Incr 将 key 对应的值加 1。
 func (rc *RedisClient) Incr(key string) (int64, error) {
-	return rc.client.Incr(rc.ctx, key).Result()
+	return rc.GetClient().Incr(rc.ctx, key).Result()
 }
 
 // IncrBy 将 key 对应的值加上指定增量。
 func (rc *RedisClient) IncrBy(key string, value int64) (int64, error) {
-	return rc.client.IncrBy(rc.ctx, key, value).Result()
+	return rc.GetClient().IncrBy(rc.ctx, key, value).Result()
 }
 
 // Decr 将 key 对应的值减 1。
 func (rc *RedisClient) Decr(key string) (int64, error) {
-	return rc.client.Decr(rc.ctx, key).Result()
+	return rc.GetClient().Decr(rc.ctx, key).Result()
 }
 
 // DecrBy 将 key 对应的值减去指定值。
 func (rc *RedisClient) DecrBy(key string, value int64) (int64, error) {
-	return rc.client.DecrBy(rc.ctx, key, value).Result()
+	return rc.GetClient().DecrBy(rc.ctx, key, value).Result()
 }
 
 // ---------------------------------------------------------------------------
@@ -294,32 +362,32 @@ func (rc *RedisClient) DecrBy(key string, value int64) (int64, error) {
 
 // SAdd 向集合添加成员，返回新增的成员数。
 func (rc *RedisClient) SAdd(key string, members ...any) (int64, error) {
-	return rc.client.SAdd(rc.ctx, key, members...).Result()
+	return rc.GetClient().SAdd(rc.ctx, key, members...).Result()
 }
 
 // SMembers 获取集合的所有成员。
 func (rc *RedisClient) SMembers(key string) ([]string, error) {
-	return rc.client.SMembers(rc.ctx, key).Result()
+	return rc.GetClient().SMembers(rc.ctx, key).Result()
 }
 
 // SPopN 从集合中随机移除并返回 count 个成员。
 func (rc *RedisClient) SPopN(key string, count int64) ([]string, error) {
-	return rc.client.SPopN(rc.ctx, key, count).Result()
+	return rc.GetClient().SPopN(rc.ctx, key, count).Result()
 }
 
 // SCard 返回集合的成员数量。
 func (rc *RedisClient) SCard(key string) (int64, error) {
-	return rc.client.SCard(rc.ctx, key).Result()
+	return rc.GetClient().SCard(rc.ctx, key).Result()
 }
 
 // SRem 从集合中移除指定成员，返回实际移除的数量。
 func (rc *RedisClient) SRem(key string, members ...any) (int64, error) {
-	return rc.client.SRem(rc.ctx, key, members...).Result()
+	return rc.GetClient().SRem(rc.ctx, key, members...).Result()
 }
 
 // SIsMember 判断 member 是否是集合的成员。
 func (rc *RedisClient) SIsMember(key string, member any) (bool, error) {
-	return rc.client.SIsMember(rc.ctx, key, member).Result()
+	return rc.GetClient().SIsMember(rc.ctx, key, member).Result()
 }
 
 // ---------------------------------------------------------------------------
@@ -328,17 +396,17 @@ func (rc *RedisClient) SIsMember(key string, member any) (bool, error) {
 
 // ZAdd 向有序集合添加一个成员。
 func (rc *RedisClient) ZAdd(key string, score float64, member string) (int64, error) {
-	return rc.client.ZAdd(rc.ctx, key, redis.Z{Score: score, Member: member}).Result()
+	return rc.GetClient().ZAdd(rc.ctx, key, redis.Z{Score: score, Member: member}).Result()
 }
 
 // ZAddMulti 向有序集合批量添加成员。
 func (rc *RedisClient) ZAddMulti(key string, members ...redis.Z) (int64, error) {
-	return rc.client.ZAdd(rc.ctx, key, members...).Result()
+	return rc.GetClient().ZAdd(rc.ctx, key, members...).Result()
 }
 
 // ZRangeByScore 按分数范围获取成员（升序）。
 func (rc *RedisClient) ZRangeByScore(key string, min, max float64) ([]string, error) {
-	return rc.client.ZRangeByScore(rc.ctx, key, &redis.ZRangeBy{
+	return rc.GetClient().ZRangeByScore(rc.ctx, key, &redis.ZRangeBy{
 		Min: fmt.Sprintf("%f", min),
 		Max: fmt.Sprintf("%f", max),
 	}).Result()
@@ -346,7 +414,7 @@ func (rc *RedisClient) ZRangeByScore(key string, min, max float64) ([]string, er
 
 // ZRangeByScoreWithScores 按分数范围获取成员及分数（升序）。
 func (rc *RedisClient) ZRangeByScoreWithScores(key string, min, max float64) ([]redis.Z, error) {
-	return rc.client.ZRangeByScoreWithScores(rc.ctx, key, &redis.ZRangeBy{
+	return rc.GetClient().ZRangeByScoreWithScores(rc.ctx, key, &redis.ZRangeBy{
 		Min: fmt.Sprintf("%f", min),
 		Max: fmt.Sprintf("%f", max),
 	}).Result()
@@ -354,22 +422,22 @@ func (rc *RedisClient) ZRangeByScoreWithScores(key string, min, max float64) ([]
 
 // ZRemRangeByScore 按分数范围删除成员，返回删除的数量。
 func (rc *RedisClient) ZRemRangeByScore(key string, min, max float64) (int64, error) {
-	return rc.client.ZRemRangeByScore(rc.ctx, key, fmt.Sprintf("%f", min), fmt.Sprintf("%f", max)).Result()
+	return rc.GetClient().ZRemRangeByScore(rc.ctx, key, fmt.Sprintf("%f", min), fmt.Sprintf("%f", max)).Result()
 }
 
 // ZCard 返回有序集合的成员数量。
 func (rc *RedisClient) ZCard(key string) (int64, error) {
-	return rc.client.ZCard(rc.ctx, key).Result()
+	return rc.GetClient().ZCard(rc.ctx, key).Result()
 }
 
 // ZScore 获取指定成员的分数。
 func (rc *RedisClient) ZScore(key, member string) (float64, error) {
-	return rc.client.ZScore(rc.ctx, key, member).Result()
+	return rc.GetClient().ZScore(rc.ctx, key, member).Result()
 }
 
 // ZRem 删除有序集合中的指定成员。
 func (rc *RedisClient) ZRem(key string, members ...any) (int64, error) {
-	return rc.client.ZRem(rc.ctx, key, members...).Result()
+	return rc.GetClient().ZRem(rc.ctx, key, members...).Result()
 }
 
 // ---------------------------------------------------------------------------
@@ -378,32 +446,32 @@ func (rc *RedisClient) ZRem(key string, members ...any) (int64, error) {
 
 // HSet 设置哈希表中的字段值。
 func (rc *RedisClient) HSet(key string, values ...any) (int64, error) {
-	return rc.client.HSet(rc.ctx, key, values...).Result()
+	return rc.GetClient().HSet(rc.ctx, key, values...).Result()
 }
 
 // HGet 获取哈希表中指定字段的值。
 func (rc *RedisClient) HGet(key, field string) (string, error) {
-	return rc.client.HGet(rc.ctx, key, field).Result()
+	return rc.GetClient().HGet(rc.ctx, key, field).Result()
 }
 
 // HGetAll 获取哈希表中所有字段和值。
 func (rc *RedisClient) HGetAll(key string) (map[string]string, error) {
-	return rc.client.HGetAll(rc.ctx, key).Result()
+	return rc.GetClient().HGetAll(rc.ctx, key).Result()
 }
 
 // HDel 删除哈希表中的指定字段。
 func (rc *RedisClient) HDel(key string, fields ...string) (int64, error) {
-	return rc.client.HDel(rc.ctx, key, fields...).Result()
+	return rc.GetClient().HDel(rc.ctx, key, fields...).Result()
 }
 
 // HExists 判断哈希表中字段是否存在。
 func (rc *RedisClient) HExists(key, field string) (bool, error) {
-	return rc.client.HExists(rc.ctx, key, field).Result()
+	return rc.GetClient().HExists(rc.ctx, key, field).Result()
 }
 
 // HIncrBy 为哈希表中指定字段的值加上增量。
 func (rc *RedisClient) HIncrBy(key, field string, incr int64) (int64, error) {
-	return rc.client.HIncrBy(rc.ctx, key, field, incr).Result()
+	return rc.GetClient().HIncrBy(rc.ctx, key, field, incr).Result()
 }
 
 // ---------------------------------------------------------------------------
@@ -412,32 +480,32 @@ func (rc *RedisClient) HIncrBy(key, field string, incr int64) (int64, error) {
 
 // LPush 从列表左侧推入元素，返回列表长度。
 func (rc *RedisClient) LPush(key string, values ...any) (int64, error) {
-	return rc.client.LPush(rc.ctx, key, values...).Result()
+	return rc.GetClient().LPush(rc.ctx, key, values...).Result()
 }
 
 // RPush 从列表右侧推入元素，返回列表长度。
 func (rc *RedisClient) RPush(key string, values ...any) (int64, error) {
-	return rc.client.RPush(rc.ctx, key, values...).Result()
+	return rc.GetClient().RPush(rc.ctx, key, values...).Result()
 }
 
 // LPop 从列表左侧弹出一个元素。
 func (rc *RedisClient) LPop(key string) (string, error) {
-	return rc.client.LPop(rc.ctx, key).Result()
+	return rc.GetClient().LPop(rc.ctx, key).Result()
 }
 
 // RPop 从列表右侧弹出一个元素。
 func (rc *RedisClient) RPop(key string) (string, error) {
-	return rc.client.RPop(rc.ctx, key).Result()
+	return rc.GetClient().RPop(rc.ctx, key).Result()
 }
 
 // LLen 返回列表的长度。
 func (rc *RedisClient) LLen(key string) (int64, error) {
-	return rc.client.LLen(rc.ctx, key).Result()
+	return rc.GetClient().LLen(rc.ctx, key).Result()
 }
 
 // LRange 返回列表中指定范围的元素。start 和 stop 为 0-based 索引，支持负数（-1 表示最后一个）。
 func (rc *RedisClient) LRange(key string, start, stop int64) ([]string, error) {
-	return rc.client.LRange(rc.ctx, key, start, stop).Result()
+	return rc.GetClient().LRange(rc.ctx, key, start, stop).Result()
 }
 
 // ---------------------------------------------------------------------------
@@ -446,7 +514,7 @@ func (rc *RedisClient) LRange(key string, start, stop int64) ([]string, error) {
 
 // MemoryUsage 返回指定 key 的内存占用（字节），使用 MEMORY USAGE 命令。
 func (rc *RedisClient) MemoryUsage(key string) (int64, error) {
-	result, err := rc.client.Do(rc.ctx, "MEMORY", "USAGE", key).Result()
+	result, err := rc.GetClient().Do(rc.ctx, "MEMORY", "USAGE", key).Result()
 	if err != nil {
 		return 0, err
 	}
@@ -458,7 +526,7 @@ func (rc *RedisClient) MemoryUsage(key string) (int64, error) {
 
 // GetRedisVersion 获取 Redis 服务器版本号（如 "7.0.5"）。
 func (rc *RedisClient) GetRedisVersion() (string, error) {
-	info, err := rc.client.Info(rc.ctx, "server").Result()
+	info, err := rc.GetClient().Info(rc.ctx, "server").Result()
 	if err != nil {
 		return "", fmt.Errorf("redis: 获取 server info 失败: %w", err)
 	}
@@ -476,15 +544,11 @@ func (rc *RedisClient) IsRedis7OrAbove() bool {
 	if err != nil {
 		return false
 	}
-	parts := strings.Split(version, ".")
-	if len(parts) == 0 {
-		return false
-	}
-	major, err := strconv.Atoi(parts[0])
+	v, err := versionutil.Parse(version)
 	if err != nil {
 		return false
 	}
-	return major >= 7
+	return v.Major >= 7
 }
 
 // ---------------------------------------------------------------------------
@@ -493,12 +557,12 @@ func (rc *RedisClient) IsRedis7OrAbove() bool {
 
 // Pipeline 创建一个管道，用于批量发送命令。
 func (rc *RedisClient) Pipeline() redis.Pipeliner {
-	return rc.client.Pipeline()
+	return rc.GetClient().Pipeline()
 }
 
 // TxPipeline 创建一个事务管道（MULTI/EXEC）。
 func (rc *RedisClient) TxPipeline() redis.Pipeliner {
-	return rc.client.TxPipeline()
+	return rc.GetClient().TxPipeline()
 }
 
 // ExecPipeline 执行管道中缓冲的所有命令。
@@ -511,6 +575,55 @@ func (rc *RedisClient) PipeHIncrBy(pipe redis.Pipeliner, key, field string, incr
 	pipe.HIncrBy(rc.ctx, key, field, incr)
 }
 
+// WatchTx 执行基于 WATCH 的乐观锁事务：监视 keys 后调用 fn，fn 中先读取数据，
+// 再通过 tx.TxPipelined 提交写操作（MULTI/EXEC）。提交前 keys 被其他客户端修改时 EXEC 失败（redis.TxFailedErr），
+// 自动以短暂随机退避重试整个 fn，最多重试 maxRetries 次（<= 0 时默认 10 次）。
+// fn 返回的其他错误直接返回，不重试；重试耗尽时返回的错误满足 errors.Is(err, redis.TxFailedErr)。
+//
+// 用法：
+//
+//	// 原子地扣减库存（读-改-写）
+//	err := redisClient.WatchTx([]string{"stock:1001"}, func(tx *redis.Tx) error {
+//	    n, err := tx.Get(ctx, "stock:1001").Int()
+//	    if err != nil {
+//	        return err
+//	    }
+//	    if n <= 0 {
+//	        return ErrSoldOut
+//	    }
+//	    _, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+//	        pipe.Set(ctx, "stock:1001", n-1, 0)
+//	        return nil
+//	    })
+//	    return err
+//	}, 0)
+func (rc *RedisClient) WatchTx(keys []string, fn func(tx *redis.Tx) error, maxRetries int) error {
+	if rc.GetClient() == nil {
+		return ErrRedisNotInit
+	}
+	if maxRetries <= 0 {
+		maxRetries = 10
+	}
+
+	policy := &retry.Policy{
+		MaxAttempts:     maxRetries + 1,
+		InitialInterval: 5 * time.Millisecond,
+		MaxInterval:     200 * time.Millisecond,
+		Multiplier:      2,
+		Jitter:          0.5,
+		RetryIf: func(err error) bool {
+			return errors.Is(err, redis.TxFailedErr)
+		},
+	}
+	err := retry.Do(rc.ctx, policy, func(ctx context.Context) error {
+		return rc.GetClient().Watch(ctx, fn, keys...)
+	})
+	if err != nil && errors.Is(err, redis.TxFailedErr) {
+		return fmt.Errorf("redis: WATCH %v 事务冲突: %w", keys, err)
+	}
+	return err
+}
+
 // ---------------------------------------------------------------------------
 // 带重试的操作（连接异常时自动重连）
 // ---------------------------------------------------------------------------
@@ -0,0 +1,190 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DelayedQueue 基于有序集合的延迟队列：成员为任务标识（如订单号、序列化后的任务），
+// score 为执行时间（Unix 毫秒）。同一成员重复 Push 时只保留最后一次的执行时间。
+// 到期任务通过 Lua 脚本原子地取出并删除，多个消费者并发 PopDue 时每个任务只会被取出一次。
+//
+// 取出后的任务只在内存中，消费者进程崩溃时会丢失；需要确认 / 重试 / 死信等完整语义时使用 queue 包。
+//
+// 用法：
+//
+//	dq := redisClient.DelayedQueue("delay:order:cancel")
+//	_ = dq.PushIn(orderID, 30*time.Minute)
+//
+//	go dq.Consume(ctx, func(orderID string) error {
+//	    return cancelUnpaidOrder(orderID)
+//	}, &db.DelayedConsumeOptions{RetryDelay: time.Minute})
+type DelayedQueue struct {
+	rc  *RedisClient
+	key string
+}
+
+// DelayedConsumeOptions Consume 的选项，零值字段使用默认值，nil 等同于全部默认。
+type DelayedConsumeOptions struct {
+	PollInterval time.Duration // 没有到期任务时的轮询间隔，<= 0 时默认 1s
+	BatchSize    int64         // 每次最多取出的任务数，<= 0 时默认 100
+	RetryDelay   time.Duration // handler 返回错误（或 panic）时重新入队的延迟，<= 0 时不重试，只记录日志
+}
+
+// DelayedQueue 返回以 key 为有序集合的延迟队列。
+func (rc *RedisClient) DelayedQueue(key string) *DelayedQueue {
+	return &DelayedQueue{rc: rc, key: key}
+}
+
+// Key 返回队列使用的 Redis key。
+func (q *DelayedQueue) Key() string { return q.key }
+
+// Push 添加任务，在 executeAt 时刻到期；成员已存在时更新执行时间。
+func (q *DelayedQueue) Push(member string, executeAt time.Time) error {
+	if q.rc.GetClient() == nil {
+		return ErrRedisNotInit
+	}
+	z := redis.Z{Score: float64(executeAt.UnixMilli()), Member: member}
+	if err := q.rc.GetClient().ZAdd(q.rc.ctx, q.key, z).Err(); err != nil {
+		return fmt.Errorf("redis: 延迟队列 [%s] 添加任务失败: %w", q.key, err)
+	}
+	return nil
+}
+
+// PushIn 添加任务，在 delay 之后到期。
+func (q *DelayedQueue) PushIn(member string, delay time.Duration) error {
+	return q.Push(member, time.Now().Add(delay))
+}
+
+// popDueScript 原子地取出并删除 score <= ARGV[1] 的最多 ARGV[2] 个成员（按执行时间升序）。
+var popDueScript = redis.NewScript(`
+local members = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+if #members > 0 then
+	redis.call('ZREM', KEYS[1], unpack(members))
+end
+return members
+`)
+
+// PopDue 取出并删除最多 limit 个已到期的任务（按执行时间升序），没有到期任务时返回空切片。
+// limit <= 0 时默认 100。
+func (q *DelayedQueue) PopDue(limit int64) ([]string, error) {
+	if q.rc.GetClient() == nil {
+		return nil, ErrRedisNotInit
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	members, err := popDueScript.Run(q.rc.ctx, q.rc.GetClient(), []string{q.key}, now, limit).StringSlice()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("redis: 延迟队列 [%s] 取出到期任务失败: %w", q.key, err)
+	}
+	return members, nil
+}
+
+// Remove 取消任务，返回实际删除的数量。
+func (q *DelayedQueue) Remove(members ...string) (int64, error) {
+	if q.rc.GetClient() == nil {
+		return 0, ErrRedisNotInit
+	}
+	args := make([]any, len(members))
+	for i, m := range members {
+		args[i] = m
+	}
+	return q.rc.GetClient().ZRem(q.rc.ctx, q.key, args...).Result()
+}
+
+// Len 返回队列中的任务数（含未到期的）。
+func (q *DelayedQueue) Len() (int64, error) {
+	if q.rc.GetClient() == nil {
+		return 0, ErrRedisNotInit
+	}
+	return q.rc.GetClient().ZCard(q.rc.ctx, q.key).Result()
+}
+
+// ExecuteAt 返回任务的执行时间，任务不存在时返回 false。
+func (q *DelayedQueue) ExecuteAt(member string) (time.Time, bool, error) {
+	if q.rc.GetClient() == nil {
+		return time.Time{}, false, ErrRedisNotInit
+	}
+	score, err := q.rc.GetClient().ZScore(q.rc.ctx, q.key, member).Result()
+	if errors.Is(err, redis.Nil) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return time.UnixMilli(int64(score)), true, nil
+}
+
+// Consume 轮询到期任务并逐个调用 handler，阻塞直到 ctx 取消（返回 nil）。
+// 一批取出的任务处理完后立即取下一批，没有到期任务时等待 PollInterval。
+// handler 返回错误或 panic 时，设置了 RetryDelay 则在 RetryDelay 后重新入队，否则丢弃并记录日志。
+// 可在多个进程中同时运行，每个任务只会被其中一个取出。
+func (q *DelayedQueue) Consume(ctx context.Context, handler func(member string) error, opts *DelayedConsumeOptions) error {
+	if q.rc.GetClient() == nil {
+		return ErrRedisNotInit
+	}
+	var o DelayedConsumeOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.PollInterval <= 0 {
+		o.PollInterval = time.Second
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = 100
+	}
+
+	for {
+		members, err := q.PopDue(o.BatchSize)
+		if err != nil {
+			redisLog.Warnf("%v", err)
+		}
+		for _, m := range members {
+			if err := callDelayedHandler(handler, m); err != nil {
+				q.handleFailure(m, err, o.RetryDelay)
+			}
+		}
+		if len(members) > 0 && int64(len(members)) == o.BatchSize {
+			if ctx.Err() != nil {
+				return nil
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(o.PollInterval):
+		}
+	}
+}
+
+// handleFailure 处理失败的任务：设置了 retryDelay 时重新入队，否则记录日志后丢弃。
+func (q *DelayedQueue) handleFailure(member string, cause error, retryDelay time.Duration) {
+	if retryDelay <= 0 {
+		redisLog.Errorf("redis: 延迟队列 [%s] 任务 [%s] 处理失败，已丢弃: %v", q.key, member, cause)
+		return
+	}
+	if err := q.PushIn(member, retryDelay); err != nil {
+		redisLog.Errorf("redis: 延迟队列 [%s] 任务 [%s] 处理失败且重新入队失败: %v（原因: %v）", q.key, member, err, cause)
+		return
+	}
+	redisLog.Warnf("redis: 延迟队列 [%s] 任务 [%s] 处理失败，%v 后重试: %v", q.key, member, retryDelay, cause)
+}
+
+// callDelayedHandler 调用 handler 并将 panic 转为错误，避免中断消费循环。
+func callDelayedHandler(handler func(member string) error, member string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handler(member)
+}
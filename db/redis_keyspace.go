@@ -0,0 +1,202 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// SubscribeExpired 订阅 key 过期事件，对匹配 pattern（Redis glob 语法，空串或 "*" 表示全部）的
+// 已过期 key 调用 handler，阻塞直到 ctx 取消（返回 nil）。适合用 key 过期实现延迟任务的消费端。
+//
+// 启动时以及每次断线重连后会检查 notify-keyspace-events 配置，缺少过期事件（Ex）时自动追加；
+// 托管实例禁用 CONFIG 命令时只输出警告，需在控制台手动开启。
+// 注意：过期事件由 Redis 在删除 key 时发出，可能晚于 TTL 到期；断线期间的事件会丢失，
+// 对可靠性要求高的场景应配合定期扫描兜底。handler 在订阅协程中串行执行，耗时操作应自行异步处理。
+//
+// 用法：
+//
+//	go func() {
+//	    err := redisClient.SubscribeExpired(ctx, "delay:order:*", func(key string) {
+//	        orderID := strings.TrimPrefix(key, "delay:order:")
+//	        cancelUnpaidOrder(orderID)
+//	    })
+//	    if err != nil {
+//	        log.Errorf("订阅过期事件失败: %v", err)
+//	    }
+//	}()
+func (rc *RedisClient) SubscribeExpired(ctx context.Context, pattern string, handler func(key string)) error {
+	if rc.GetClient() == nil {
+		return ErrRedisNotInit
+	}
+	channel := fmt.Sprintf("__keyevent@%d__:expired", rc.GetClient().Options().DB)
+
+	pubsub := rc.GetClient().Subscribe(ctx, channel)
+	defer pubsub.Close()
+
+	backoff := time.Second
+	for {
+		msg, err := pubsub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			// go-redis 会在下一次 Receive 时自动重连并重新订阅
+			redisLog.Warnf("redis: 接收过期事件失败，%v 后重试: %v", backoff, err)
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, 30*time.Second)
+			continue
+		}
+		backoff = time.Second
+
+		switch m := msg.(type) {
+		case *redis.Subscription:
+			// 首次订阅和重连后重新订阅时都会收到，借此确保服务端配置（服务端重启会丢失 CONFIG SET）
+			if m.Kind == "subscribe" {
+				rc.ensureExpiredEvents(ctx)
+				redisLog.Infof("redis: 已订阅过期事件 %s pattern=%q", channel, pattern)
+			}
+		case *redis.Message:
+			if pattern == "" || pattern == "*" || matchGlob(pattern, m.Payload) {
+				callExpiredHandler(handler, m.Payload)
+			}
+		}
+	}
+}
+
+// ensureExpiredEvents 确保 notify-keyspace-events 包含 keyevent（E）和过期（x 或 A）事件。
+func (rc *RedisClient) ensureExpiredEvents(ctx context.Context) {
+	cfg, err := rc.GetClient().ConfigGet(ctx, "notify-keyspace-events").Result()
+	if err != nil {
+		redisLog.Warnf("redis: 读取 notify-keyspace-events 失败（托管实例可能禁用 CONFIG，请确认已开启 Ex）: %v", err)
+		return
+	}
+	flags := cfg["notify-keyspace-events"]
+	want := flags
+	if !strings.Contains(want, "E") {
+		want += "E"
+	}
+	if !strings.ContainsAny(want, "xA") {
+		want += "x"
+	}
+	if want == flags {
+		return
+	}
+	if err := rc.GetClient().ConfigSet(ctx, "notify-keyspace-events", want).Err(); err != nil {
+		redisLog.Warnf("redis: 设置 notify-keyspace-events=%s 失败（请手动开启 Ex）: %v", want, err)
+		return
+	}
+	redisLog.Infof("redis: notify-keyspace-events 已由 %q 调整为 %q", flags, want)
+}
+
+// callExpiredHandler 调用 handler 并捕获 panic，避免中断订阅循环。
+func callExpiredHandler(handler func(key string), key string) {
+	defer func() {
+		if r := recover(); r != nil {
+			redisLog.Errorf("redis: 处理过期 key [%s] panic: %v", key, r)
+		}
+	}()
+	handler(key)
+}
+
+// matchGlob 按 Redis glob 语法匹配：* 任意串，? 单个字符，[abc] / [^a] / [a-z] 字符集，\ 转义。
+func matchGlob(pattern, s string) bool {
+	p, str := []rune(pattern), []rune(s)
+	for len(p) > 0 {
+		switch p[0] {
+		case '*':
+			for len(p) > 1 && p[1] == '*' {
+				p = p[1:]
+			}
+			if len(p) == 1 {
+				return true
+			}
+			for i := 0; i <= len(str); i++ {
+				if matchGlob(string(p[1:]), string(str[i:])) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(str) == 0 {
+				return false
+			}
+			str = str[1:]
+			p = p[1:]
+		case '[':
+			if len(str) == 0 {
+				return false
+			}
+			end := 1
+			for end < len(p) && p[end] != ']' {
+				if p[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(p) {
+				// 没有闭合的 ]，按字面量匹配
+				if str[0] != '[' {
+					return false
+				}
+				str = str[1:]
+				p = p[1:]
+				continue
+			}
+			if !matchClass(p[1:end], str[0]) {
+				return false
+			}
+			str = str[1:]
+			p = p[end+1:]
+		case '\\':
+			if len(p) > 1 {
+				p = p[1:]
+			}
+			fallthrough
+		default:
+			if len(str) == 0 || str[0] != p[0] {
+				return false
+			}
+			str = str[1:]
+			p = p[1:]
+		}
+	}
+	return len(str) == 0
+}
+
+// matchClass 判断 c 是否属于字符集 class（不含两侧方括号）。
+func matchClass(class []rune, c rune) bool {
+	negate := len(class) > 0 && class[0] == '^'
+	if negate {
+		class = class[1:]
+	}
+	matched := false
+	for i := 0; i < len(class); i++ {
+		switch {
+		case class[i] == '\\' && i+1 < len(class):
+			i++
+			if class[i] == c {
+				matched = true
+			}
+		case i+2 < len(class) && class[i+1] == '-':
+			lo, hi := class[i], class[i+2]
+			if lo > hi {
+				lo, hi = hi, lo
+			}
+			if c >= lo && c <= hi {
+				matched = true
+			}
+			i += 2
+		case class[i] == c:
+			matched = true
+		}
+	}
+	return matched != negate
+}
@@ -0,0 +1,160 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// sharedRedis 共享客户端的注册信息。
+type sharedRedis struct {
+	key  string
+	refs int
+}
+
+// redisRegistry 按归一化连接参数缓存的共享客户端。
+var redisRegistry = struct {
+	mu      sync.Mutex
+	clients map[string]*RedisClient
+}{clients: map[string]*RedisClient{}}
+
+// GetOrCreateClient 返回 params 对应的共享 RedisClient：连接参数相同（Host 忽略大小写与首尾空白）的调用
+// 返回同一个实例并增加引用计数，避免各模块各自连接同一个 Redis。
+// 共享客户端的 Close 只减少引用计数，最后一个使用者 Close 时才真正断开连接，因此每次 GetOrCreateClient 都应对应一次 Close。
+//
+// 共享客户端被多个模块同时使用：SetContext、AddHook 会影响所有使用者，需要独立配置时使用 NewRedisClient。
+// 自定义 TLSConfig 按指针区分，只有传入同一个 *tls.Config 才会共享。
+//
+// 用法：
+//
+//	// 模块 A、模块 B 各自调用，得到同一个连接
+//	rc, err := db.GetOrCreateClient(params)
+//	if err != nil { ... }
+//	defer rc.Close()
+func GetOrCreateClient(params *RedisParams) (*RedisClient, error) {
+	if params == nil {
+		return nil, ErrRedisNilParams
+	}
+	if err := validateRedisParams(params); err != nil {
+		return nil, err
+	}
+	key := redisParamsKey(params)
+
+	redisRegistry.mu.Lock()
+	if rc, ok := redisRegistry.clients[key]; ok {
+		rc.shared.refs++
+		redisRegistry.mu.Unlock()
+		return rc, nil
+	}
+	redisRegistry.mu.Unlock()
+
+	// 建立连接可能较慢，不持有全局锁；并发创建同一参数时保留先注册的实例
+	rc, err := NewRedisClient(params)
+	if err != nil {
+		return nil, err
+	}
+
+	redisRegistry.mu.Lock()
+	defer redisRegistry.mu.Unlock()
+	if existing, ok := redisRegistry.clients[key]; ok {
+		existing.shared.refs++
+		rc.GetClient().Close()
+		return existing, nil
+	}
+	rc.shared = &sharedRedis{key: key, refs: 1}
+	redisRegistry.clients[key] = rc
+	return rc, nil
+}
+
+// releaseShared 减少共享客户端的引用计数，归零时从注册表移除并关闭连接。
+func releaseShared(rc *RedisClient) error {
+	redisRegistry.mu.Lock()
+	if rc.shared.refs <= 0 {
+		redisRegistry.mu.Unlock()
+		return nil
+	}
+	rc.shared.refs--
+	if rc.shared.refs > 0 {
+		redisRegistry.mu.Unlock()
+		return nil
+	}
+	delete(redisRegistry.clients, rc.shared.key)
+	redisRegistry.mu.Unlock()
+
+	redisLog.Infof("redis: 共享连接已关闭 %s:%d db=%d", rc.params.Host, rc.params.Port, rc.params.DB)
+	if rc.GetClient() == nil {
+		return nil
+	}
+	return rc.GetClient().Close()
+}
+
+// redisParamsKey 返回归一化的连接参数 key，密码只参与哈希。
+func redisParamsKey(p *RedisParams) string {
+	pass := sha256.Sum256([]byte(p.Password))
+	return fmt.Sprintf("%s:%d/%d user=%s pass=%x tls=%t/%p name=%s",
+		strings.ToLower(strings.TrimSpace(p.Host)), p.Port, p.DB, p.Username, pass[:8],
+		p.EnableTLS, p.TLSConfig, p.ClientName)
+}
+
+// SharedRedisInfo 一个共享客户端的概况。
+type SharedRedisInfo struct {
+	Addr       string // host:port
+	DB         int
+	ClientName string
+	Refs       int // 当前引用计数（未 Close 的使用者数量）
+}
+
+// SharedRedisClients 返回当前所有共享客户端的概况（按地址排序），用于排查连接数。
+func SharedRedisClients() []SharedRedisInfo {
+	redisRegistry.mu.Lock()
+	defer redisRegistry.mu.Unlock()
+	out := make([]SharedRedisInfo, 0, len(redisRegistry.clients))
+	for _, rc := range redisRegistry.clients {
+		out = append(out, SharedRedisInfo{
+			Addr:       fmt.Sprintf("%s:%d", rc.params.Host, rc.params.Port),
+			DB:         rc.params.DB,
+			ClientName: rc.params.ClientName,
+			Refs:       rc.shared.refs,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Addr != out[j].Addr {
+			return out[i].Addr < out[j].Addr
+		}
+		return out[i].DB < out[j].DB
+	})
+	return out
+}
+
+// SharedRedisHealthCheck 并发对每个共享客户端执行一次 HealthCheck（无论被多少模块使用），汇总所有失败。
+// 可直接注册到健康检查，代替各模块分别注册自己的 Redis 检查（实现 healthcheck.Checker 的函数形式）。
+//
+// 用法：
+//
+//	h.AddFunc("redis", db.SharedRedisHealthCheck)
+func SharedRedisHealthCheck(ctx context.Context) error {
+	redisRegistry.mu.Lock()
+	clients := make([]*RedisClient, 0, len(redisRegistry.clients))
+	for _, rc := range redisRegistry.clients {
+		clients = append(clients, rc)
+	}
+	redisRegistry.mu.Unlock()
+
+	errs := make([]error, len(clients))
+	var wg sync.WaitGroup
+	for i, rc := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := rc.HealthCheck(ctx); err != nil {
+				errs[i] = fmt.Errorf("%s:%d db=%d: %w", rc.params.Host, rc.params.Port, rc.params.DB, err)
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,179 @@
+// Package distlock 提供统一的分布式互斥锁接口，后端可选 Redis、PostgreSQL advisory lock 或 OBS 条件写入，
+// 应用只依赖 Locker / Lock 接口，切换后端时无需修改业务代码。leader 包的分布式单例任务也基于这里的锁实现。
+package distlock
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pylemonorg/gotools/logger"
+)
+
+// log distlock 模块日志，可通过 logger.SetModuleLevel("distlock", ...) 单独控制级别。
+var log = logger.Module("distlock")
+
+// 锁相关的哨兵错误。
+var (
+	ErrNotAcquired = errors.New("distlock: 锁已被其他持有者占用")
+	ErrLockLost    = errors.New("distlock: 锁已失效（过期或被他人获取）")
+)
+
+// 默认配置。
+const (
+	defaultRetryInterval = 100 * time.Millisecond
+	releaseTimeout       = 5 * time.Second
+)
+
+// Locker 某个锁资源的获取入口，每个 Locker 对应一个锁 key，并发安全。
+type Locker interface {
+	// Acquire 尝试获取锁（不阻塞），ttl 为锁的有效期；锁被他人持有时返回 ErrNotAcquired。
+	Acquire(ctx context.Context, ttl time.Duration) (Lock, error)
+}
+
+// Lock 一次成功获取的锁。
+type Lock interface {
+	// Key 返回锁的 key。
+	Key() string
+	// Renew 将有效期延长为从现在起 ttl；锁已过期或被他人获取时返回 ErrLockLost。
+	Renew(ctx context.Context, ttl time.Duration) error
+	// Release 释放锁；锁在释放前已失效时返回 ErrLockLost（临界区可能已与其他持有者重叠）。
+	Release(ctx context.Context) error
+}
+
+// Fenced 由能提供 fencing token 的 Lock 实现（目前为 RedisLocker）。
+// token 随每次成功获取单调递增，下游存储应拒绝比已见过的更小的 token，防止旧持有者在锁过期后继续写入。
+type Fenced interface {
+	FencingToken() int64
+}
+
+// fencingTokenKey WithLock 传给 fn 的 ctx 中保存 fencing token 的 key。
+type fencingTokenKey struct{}
+
+// FencingToken 返回 WithLock 传给 fn 的 ctx 中的 fencing token，锁未实现 Fenced 时返回 false。
+//
+// 用法：
+//
+//	err := distlock.WithLock(ctx, locker, 30*time.Second, func(ctx context.Context) error {
+//	    token, _ := distlock.FencingToken(ctx)
+//	    return store.WriteIfNewer(ctx, token, report)
+//	})
+func FencingToken(ctx context.Context) (int64, bool) {
+	token, ok := ctx.Value(fencingTokenKey{}).(int64)
+	return token, ok
+}
+
+// AcquireWait 每隔 interval（<= 0 时为 100ms）重试获取锁，直到成功、出现 ErrNotAcquired 以外的错误或 ctx 结束。
+//
+// 用法：
+//
+//	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+//	defer cancel()
+//	lock, err := distlock.AcquireWait(ctx, locker, 30*time.Second, 0)
+func AcquireWait(ctx context.Context, l Locker, ttl, interval time.Duration) (Lock, error) {
+	if interval <= 0 {
+		interval = defaultRetryInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		lock, err := l.Acquire(ctx, ttl)
+		if !errors.Is(err, ErrNotAcquired) {
+			return lock, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("distlock: 等待锁超时: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// WithLock 获取锁（不等待）后执行 fn，执行期间每 ttl/3 自动续期，fn 返回后释放锁。
+// 续期确认锁已丢失、或连续续期失败超过 ttl 时取消传给 fn 的 ctx；此时 fn 未返回错误则返回 ErrLockLost。
+// 锁被他人持有时直接返回 ErrNotAcquired，需要等待时先用 AcquireWait 获取锁再自行管理。
+// 锁实现了 Fenced 时，fn 可通过 FencingToken(ctx) 取得本次持有的 fencing token。
+//
+// 用法：
+//
+//	err := distlock.WithLock(ctx, locker, 30*time.Second, func(ctx context.Context) error {
+//	    return runDailyReport(ctx) // 应在 ctx 取消时尽快退出
+//	})
+//	if errors.Is(err, distlock.ErrNotAcquired) {
+//	    return nil // 其他实例正在执行
+//	}
+func WithLock(ctx context.Context, l Locker, ttl time.Duration, fn func(ctx context.Context) error) error {
+	lock, err := l.Acquire(ctx, ttl)
+	if err != nil {
+		return err
+	}
+
+	if f, ok := lock.(Fenced); ok {
+		ctx = context.WithValue(ctx, fencingTokenKey{}, f.FencingToken())
+	}
+	fnCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	done := make(chan struct{})
+	go keepAlive(fnCtx, lock, ttl, cancel, done)
+
+	err = fn(fnCtx)
+	close(done)
+
+	relCtx, relCancel := context.WithTimeout(context.WithoutCancel(ctx), releaseTimeout)
+	defer relCancel()
+	relErr := lock.Release(relCtx)
+	if relErr != nil && !errors.Is(relErr, ErrLockLost) {
+		log.Warnf("distlock: 释放锁 [%s] 失败: %v", lock.Key(), relErr)
+	}
+
+	if err == nil && (errors.Is(context.Cause(fnCtx), ErrLockLost) || errors.Is(relErr, ErrLockLost)) {
+		return ErrLockLost
+	}
+	return err
+}
+
+// keepAlive 定期续期直到 done 关闭；锁丢失时以 ErrLockLost 取消 fn 的 ctx。
+func keepAlive(ctx context.Context, lock Lock, ttl time.Duration, cancel context.CancelCauseFunc, done <-chan struct{}) {
+	interval := ttl / 3
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastOK := time.Now()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		err := lock.Renew(ctx, ttl)
+		switch {
+		case err == nil:
+			lastOK = time.Now()
+		case errors.Is(err, ErrLockLost):
+			log.Warnf("distlock: 锁 [%s] 已丢失，取消执行", lock.Key())
+			cancel(ErrLockLost)
+			return
+		case time.Since(lastOK) >= ttl:
+			log.Warnf("distlock: 锁 [%s] 续期持续失败超过 %v，视为已丢失: %v", lock.Key(), ttl, err)
+			cancel(ErrLockLost)
+			return
+		default:
+			log.Warnf("distlock: 锁 [%s] 续期失败，稍后重试: %v", lock.Key(), err)
+		}
+	}
+}
+
+// newToken 生成随机的持有者标识，用于区分同一 key 的不同持有者。
+func newToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
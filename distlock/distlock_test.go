@@ -0,0 +1,329 @@
+package distlock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pylemonorg/gotools/db"
+	"github.com/pylemonorg/gotools/internal/redistest"
+	"github.com/pylemonorg/gotools/obsutil"
+)
+
+// 编译期检查各后端实现了接口。
+var (
+	_ Locker = NewRedisLocker((*db.RedisClient)(nil), "")
+	_ Locker = (*PostgresLocker)(nil)
+	_ Locker = NewOBSLocker((*obsutil.ObsClient)(nil), "")
+)
+
+// memLocker 内存实现，用于测试 WithLock / AcquireWait。
+type memLocker struct {
+	mu       sync.Mutex
+	holder   string
+	renewErr error // 非 nil 时 Renew 返回该错误
+	renews   atomic.Int32
+}
+
+func (m *memLocker) Acquire(_ context.Context, _ time.Duration) (Lock, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.holder != "" {
+		return nil, ErrNotAcquired
+	}
+	m.holder = newToken()
+	return &memLock{m: m, token: m.holder}, nil
+}
+
+type memLock struct {
+	m     *memLocker
+	token string
+}
+
+func (l *memLock) Key() string { return "mem" }
+
+func (l *memLock) Renew(context.Context, time.Duration) error {
+	l.m.renews.Add(1)
+	l.m.mu.Lock()
+	defer l.m.mu.Unlock()
+	if l.m.renewErr != nil {
+		return l.m.renewErr
+	}
+	if l.m.holder != l.token {
+		return ErrLockLost
+	}
+	return nil
+}
+
+func (l *memLock) Release(context.Context) error {
+	l.m.mu.Lock()
+	defer l.m.mu.Unlock()
+	if l.m.holder != l.token {
+		return ErrLockLost
+	}
+	l.m.holder = ""
+	return nil
+}
+
+func TestWithLock(t *testing.T) {
+	m := &memLocker{}
+	err := WithLock(context.Background(), m, 30*time.Millisecond, func(ctx context.Context) error {
+		if _, err := m.Acquire(ctx, time.Second); !errors.Is(err, ErrNotAcquired) {
+			t.Errorf("持有期间再次获取应返回 ErrNotAcquired，实际 %v", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithLock: %v", err)
+	}
+	if m.renews.Load() == 0 {
+		t.Error("期望执行期间自动续期")
+	}
+	if m.holder != "" {
+		t.Error("期望执行结束后释放锁")
+	}
+
+	fnErr := errors.New("boom")
+	if err := WithLock(context.Background(), m, time.Second, func(context.Context) error { return fnErr }); err != fnErr {
+		t.Errorf("期望返回 fn 的错误，实际 %v", err)
+	}
+}
+
+func TestWithLockNotAcquired(t *testing.T) {
+	m := &memLocker{holder: "other"}
+	called := false
+	err := WithLock(context.Background(), m, time.Second, func(context.Context) error {
+		called = true
+		return nil
+	})
+	if !errors.Is(err, ErrNotAcquired) || called {
+		t.Errorf("err = %v, called = %v, 期望 ErrNotAcquired 且不执行 fn", err, called)
+	}
+}
+
+func TestWithLockLost(t *testing.T) {
+	m := &memLocker{}
+	err := WithLock(context.Background(), m, 30*time.Millisecond, func(ctx context.Context) error {
+		m.mu.Lock()
+		m.holder = "other" // 模拟锁过期后被他人获取
+		m.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Second):
+			t.Error("锁丢失后期望取消 ctx")
+			return nil
+		}
+	})
+	if !errors.Is(err, ErrLockLost) {
+		t.Errorf("期望 ErrLockLost，实际 %v", err)
+	}
+}
+
+func TestWithLockRenewFailing(t *testing.T) {
+	m := &memLocker{renewErr: errors.New("网络错误")}
+	start := time.Now()
+	err := WithLock(context.Background(), m, 30*time.Millisecond, func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+	if !errors.Is(err, ErrLockLost) {
+		t.Errorf("期望续期持续失败后返回 ErrLockLost，实际 %v", err)
+	}
+	if d := time.Since(start); d < 30*time.Millisecond {
+		t.Errorf("期望至少等待一个 ttl 才放弃，实际 %v", d)
+	}
+}
+
+// fencedLocker 为 memLocker 获取的锁附加 fencing token。
+type fencedLocker struct{ *memLocker }
+
+type fencedLock struct{ *memLock }
+
+func (fencedLock) FencingToken() int64 { return 42 }
+
+func (f fencedLocker) Acquire(ctx context.Context, ttl time.Duration) (Lock, error) {
+	lock, err := f.memLocker.Acquire(ctx, ttl)
+	if err != nil {
+		return nil, err
+	}
+	return fencedLock{lock.(*memLock)}, nil
+}
+
+func TestWithLockFencingToken(t *testing.T) {
+	_ = WithLock(context.Background(), &memLocker{}, time.Second, func(ctx context.Context) error {
+		if _, ok := FencingToken(ctx); ok {
+			t.Error("未实现 Fenced 的锁不应提供 fencing token")
+		}
+		return nil
+	})
+	_ = WithLock(context.Background(), fencedLocker{&memLocker{}}, time.Second, func(ctx context.Context) error {
+		if token, ok := FencingToken(ctx); !ok || token != 42 {
+			t.Errorf("FencingToken = %d, %v, 期望 42", token, ok)
+		}
+		return nil
+	})
+}
+
+func TestAcquireWait(t *testing.T) {
+	m := &memLocker{holder: "other"}
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		m.mu.Lock()
+		m.holder = ""
+		m.mu.Unlock()
+	}()
+	lock, err := AcquireWait(context.Background(), m, time.Second, 5*time.Millisecond)
+	if err != nil {
+		t.Fatalf("AcquireWait: %v", err)
+	}
+	if err := lock.Release(context.Background()); err != nil {
+		t.Errorf("Release: %v", err)
+	}
+
+	m.holder = "other"
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := AcquireWait(ctx, m, time.Second, 5*time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("期望超时错误，实际 %v", err)
+	}
+}
+
+func TestAdvisoryKey(t *testing.T) {
+	if AdvisoryKey("a") != AdvisoryKey("a") {
+		t.Error("同一 key 的锁号应相同")
+	}
+	if AdvisoryKey("a") == AdvisoryKey("b") {
+		t.Error("不同 key 的锁号不应相同")
+	}
+}
+
+func TestOBSLockContentHeld(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		c    obsLockContent
+		want bool
+	}{
+		{obsLockContent{Token: "t", ExpiresAt: now.Add(time.Second)}, true},
+		{obsLockContent{Token: "t", ExpiresAt: now.Add(-time.Second)}, false},
+		{obsLockContent{ExpiresAt: now.Add(time.Second)}, false}, // 已释放
+		{obsLockContent{}, false},
+	}
+	for i, tc := range cases {
+		if got := tc.c.heldAt(now); got != tc.want {
+			t.Errorf("case %d: heldAt = %v, 期望 %v", i, got, tc.want)
+		}
+	}
+}
+
+// redisLockServer 返回在内存中模拟 acquireScript / renewScript / releaseScript 的测试服务端（忽略过期时间）。
+func redisLockServer(t *testing.T) *redistest.Server {
+	t.Helper()
+	holders := map[string]string{}
+	fences := map[string]int64{}
+	srv := redistest.NewServer(t)
+	srv.Handle("EVALSHA", func(args []string) any {
+		// EVALSHA sha numkeys key [key ...] arg [arg ...]，三个脚本的 KEYS[1] 均为锁 key，ARGV[1] 均为 token
+		n, _ := strconv.Atoi(args[2])
+		key, token := args[3], args[3+n]
+		switch args[1] {
+		case acquireScript.Hash():
+			if holders[key] != "" {
+				return 0
+			}
+			holders[key] = token
+			fences[key]++
+			return fences[key]
+		case renewScript.Hash():
+			if holders[key] != token {
+				return 0
+			}
+			return 1
+		case releaseScript.Hash():
+			if holders[key] != token {
+				return 0
+			}
+			delete(holders, key)
+			return 1
+		}
+		return redistest.Error("NOSCRIPT No matching script")
+	})
+	return srv
+}
+
+func TestRedisLocker(t *testing.T) {
+	ctx := context.Background()
+	rc := redisLockServer(t).Client(t)
+	locker := NewRedisLocker(rc, "lock:test")
+
+	lock, err := locker.Acquire(ctx, time.Minute)
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	if f := lock.(Fenced).FencingToken(); f != 1 {
+		t.Errorf("FencingToken = %d, 期望 1", f)
+	}
+	if _, err := locker.Acquire(ctx, time.Minute); !errors.Is(err, ErrNotAcquired) {
+		t.Errorf("重复 Acquire = %v, 期望 ErrNotAcquired", err)
+	}
+
+	// Reconnect 后锁对象仍使用新连接
+	if err := rc.Reconnect(1, time.Millisecond); err != nil {
+		t.Fatalf("Reconnect: %v", err)
+	}
+	if err := lock.Renew(ctx, time.Minute); err != nil {
+		t.Errorf("Renew: %v", err)
+	}
+	if err := lock.Release(ctx); err != nil {
+		t.Errorf("Release: %v", err)
+	}
+	if err := lock.Release(ctx); !errors.Is(err, ErrLockLost) {
+		t.Errorf("重复 Release = %v, 期望 ErrLockLost", err)
+	}
+
+	lock, err = locker.Acquire(ctx, time.Minute)
+	if err != nil {
+		t.Fatalf("释放后 Acquire: %v", err)
+	}
+	if f := lock.(Fenced).FencingToken(); f != 2 {
+		t.Errorf("FencingToken = %d, 期望 2", f)
+	}
+}
+
+// 加锁、续期、释放与 Reconnect 并发执行时不应 panic 或出现数据竞争（配合 -race 运行）。
+func TestRedisLockerReconnect(t *testing.T) {
+	rc := redisLockServer(t).Client(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			locker := NewRedisLocker(rc, fmt.Sprintf("lock:%d", i))
+			for ctx.Err() == nil {
+				// 使用被替换的旧连接时可能返回连接已关闭错误，这里只关心不 panic
+				lock, err := locker.Acquire(ctx, time.Minute)
+				if err != nil {
+					continue
+				}
+				_ = lock.Renew(ctx, time.Minute)
+				_ = lock.Release(ctx)
+			}
+		}()
+	}
+	for range 5 {
+		if err := rc.Reconnect(1, time.Millisecond); err != nil {
+			t.Errorf("Reconnect: %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	wg.Wait()
+}
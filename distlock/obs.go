@@ -0,0 +1,131 @@
+package distlock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pylemonorg/gotools/obsutil"
+)
+
+// obsLockContent OBS 锁对象内容。释放后 Token 为空，对象保留以便下次通过 If-Match 接管。
+type obsLockContent struct {
+	Token     string    `json:"token,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// heldAt 判断锁在 now 时是否仍被持有；内容损坏视为未持有，允许覆盖。
+func (c *obsLockContent) heldAt(now time.Time) bool {
+	return c.Token != "" && now.Before(c.ExpiresAt)
+}
+
+// OBSLocker 基于 OBS 条件写入（If-None-Match / If-Match）的锁：锁对象不存在时创建，已过期或已释放时
+// 按 ETag 覆盖，并发竞争中只有一个写入成功，要求桶支持条件请求。
+// 过期时间按本机时钟判断，各实例之间的时钟偏差应远小于 ttl；没有 Redis / PostgreSQL 时使用。
+//
+// 与 obsutil.ObsClient.TryCreateLock（写入后回读校验，竞争激烈时可能短暂出现多个持有者）不同，这里依赖服务端的原子条件写入。
+type OBSLocker struct {
+	client *obsutil.ObsClient
+	key    string
+}
+
+// NewOBSLocker 创建 key（对象路径）对应的 OBS 锁。
+//
+// 用法：
+//
+//	locker := distlock.NewOBSLocker(obsClient, "locks/daily-report.json")
+func NewOBSLocker(client *obsutil.ObsClient, key string) *OBSLocker {
+	return &OBSLocker{client: client, key: key}
+}
+
+// Acquire 实现 Locker。
+func (l *OBSLocker) Acquire(ctx context.Context, ttl time.Duration) (Lock, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	content := obsLockContent{Token: newToken(), ExpiresAt: time.Now().Add(ttl)}
+	data, err := json.Marshal(content)
+	if err != nil {
+		return nil, fmt.Errorf("distlock: 序列化锁内容失败: %w", err)
+	}
+
+	cur, etag, err := l.client.GetObjectWithETag(l.key)
+	var newETag string
+	switch {
+	case obsutil.StatusCode(err) == 404:
+		newETag, err = l.client.PutBytesIfAbsent(l.key, data)
+		if errors.Is(err, obsutil.ErrObjectAlreadyExists) {
+			return nil, ErrNotAcquired
+		}
+	case err != nil:
+		return nil, fmt.Errorf("distlock: 读取 OBS 锁 [%s] 失败: %w", l.key, err)
+	default:
+		var c obsLockContent
+		if json.Unmarshal(cur, &c) == nil && c.heldAt(time.Now()) {
+			return nil, ErrNotAcquired
+		}
+		newETag, err = l.client.PutBytesIfMatch(l.key, data, etag)
+		if errors.Is(err, obsutil.ErrPreconditionFailed) {
+			return nil, ErrNotAcquired
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("distlock: 获取 OBS 锁 [%s] 失败: %w", l.key, err)
+	}
+	return &obsLock{client: l.client, key: l.key, token: content.Token, etag: newETag}, nil
+}
+
+// obsLock OBSLocker 获取的锁，etag 为最近一次写入后锁对象的 ETag。
+type obsLock struct {
+	client *obsutil.ObsClient
+	key    string
+	token  string
+
+	mu   sync.Mutex
+	etag string // 释放后为空
+}
+
+// Key 实现 Lock。
+func (l *obsLock) Key() string { return l.key }
+
+// Renew 实现 Lock。
+func (l *obsLock) Renew(ctx context.Context, ttl time.Duration) error {
+	return l.write(ctx, obsLockContent{Token: l.token, ExpiresAt: time.Now().Add(ttl)}, "续期")
+}
+
+// Release 实现 Lock：以 If-Match 写入已释放的内容，不删除对象。
+func (l *obsLock) Release(ctx context.Context) error {
+	return l.write(ctx, obsLockContent{}, "释放")
+}
+
+// write 以 If-Match 覆盖锁对象；ETag 不匹配说明锁已被他人接管。
+func (l *obsLock) write(ctx context.Context, c obsLockContent, op string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.etag == "" {
+		return ErrLockLost
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("distlock: 序列化锁内容失败: %w", err)
+	}
+	etag, err := l.client.PutBytesIfMatch(l.key, data, l.etag)
+	if errors.Is(err, obsutil.ErrPreconditionFailed) {
+		l.etag = ""
+		return ErrLockLost
+	}
+	if err != nil {
+		return fmt.Errorf("distlock: %s OBS 锁 [%s] 失败: %w", op, l.key, err)
+	}
+	if c.Token == "" {
+		etag = ""
+	}
+	l.etag = etag
+	return nil
+}
@@ -0,0 +1,117 @@
+package distlock
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/pylemonorg/gotools/db"
+)
+
+// PostgresLocker 基于 PostgreSQL 会话级 advisory lock（pg_try_advisory_lock）的锁。
+// 持有期间独占连接池中的一个连接，key 经 FNV-64a 哈希为 bigint 锁号。
+//
+// advisory lock 没有过期时间：ttl 被忽略，锁一直保持到 Release 或连接断开（数据库自动释放），
+// 因此进程崩溃不会遗留锁；Renew 通过 ping 持有锁的连接确认会话仍然存活。
+type PostgresLocker struct {
+	db  *sql.DB
+	key string
+	id  int64
+}
+
+// NewPostgresLocker 创建 key 对应的 PostgreSQL advisory lock。
+// 同一数据库中其他直接使用 advisory lock 的代码应避免与 AdvisoryKey(key) 冲突。
+//
+// 用法：
+//
+//	locker := distlock.NewPostgresLocker(pgClient, "migrate:orders")
+//	lock, err := locker.Acquire(ctx, 0)
+func NewPostgresLocker(client *db.PostgresClient, key string) *PostgresLocker {
+	return &PostgresLocker{db: client.GetDB(), key: key, id: AdvisoryKey(key)}
+}
+
+// AdvisoryKey 返回 key 对应的 advisory lock 锁号（FNV-64a 哈希），便于在 SQL 中排查 pg_locks。
+func AdvisoryKey(key string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return int64(h.Sum64())
+}
+
+// Acquire 实现 Locker，ttl 被忽略。
+func (l *PostgresLocker) Acquire(ctx context.Context, _ time.Duration) (Lock, error) {
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("distlock: 获取 PostgreSQL 连接失败: %w", err)
+	}
+	var ok bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", l.id).Scan(&ok); err != nil {
+		discardConn(conn)
+		return nil, fmt.Errorf("distlock: 获取 PostgreSQL 锁 [%s] 失败: %w", l.key, err)
+	}
+	if !ok {
+		conn.Close()
+		return nil, ErrNotAcquired
+	}
+	return &postgresLock{conn: conn, key: l.key, id: l.id}, nil
+}
+
+// postgresLock PostgresLocker 获取的锁，持有专用连接。
+type postgresLock struct {
+	mu   sync.Mutex
+	conn *sql.Conn // 释放后为 nil
+	key  string
+	id   int64
+}
+
+// Key 实现 Lock。
+func (l *postgresLock) Key() string { return l.key }
+
+// Renew 实现 Lock：ping 持有锁的连接，连接已断开（锁已被数据库释放）时返回 ErrLockLost。ttl 被忽略。
+func (l *postgresLock) Renew(ctx context.Context, _ time.Duration) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conn == nil {
+		return ErrLockLost
+	}
+	if err := l.conn.PingContext(ctx); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("distlock: 检查 PostgreSQL 锁 [%s] 失败: %w", l.key, err)
+		}
+		discardConn(l.conn)
+		l.conn = nil
+		return fmt.Errorf("%w: %v", ErrLockLost, err)
+	}
+	return nil
+}
+
+// Release 实现 Lock。解锁失败时丢弃连接，由数据库在会话结束时释放锁，避免带锁的连接回到连接池。
+func (l *postgresLock) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conn == nil {
+		return ErrLockLost
+	}
+	conn := l.conn
+	l.conn = nil
+
+	var ok bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_advisory_unlock($1)", l.id).Scan(&ok); err != nil {
+		discardConn(conn)
+		return fmt.Errorf("distlock: 释放 PostgreSQL 锁 [%s] 失败: %w", l.key, err)
+	}
+	conn.Close()
+	if !ok {
+		return ErrLockLost
+	}
+	return nil
+}
+
+// discardConn 关闭底层连接而不是放回连接池（Raw 回调返回 driver.ErrBadConn 时连接被丢弃）。
+func discardConn(conn *sql.Conn) {
+	_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+	conn.Close()
+}
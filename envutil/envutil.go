@@ -0,0 +1,293 @@
+// Package envutil 提供带类型转换、默认值与校验的环境变量读取，并记录所有读取过的变量，
+// 便于启动时通过 LogReport 统一输出实际生效的配置（敏感变量自动脱敏）。
+//
+// 空字符串视为未设置。需要从结构体批量加载配置时使用 config 包。
+package envutil
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pylemonorg/gotools/logger"
+)
+
+// log envutil 模块日志，可通过 logger.SetModuleLevel("envutil", ...) 单独控制级别。
+var log = logger.Module("envutil")
+
+// Value Get / MustGet 支持的类型：字符串、布尔、整数、浮点数及以它们为底层类型的自定义类型，
+// time.Duration 按 time.ParseDuration 解析（如 "30s"、"1m30s"）。
+type Value interface {
+	~string | ~bool |
+		~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// Check 值的校验函数，返回非 nil 表示不合法。
+type Check[T any] func(v T) error
+
+// Range 校验值在 [lo, hi] 范围内。
+func Range[T cmp.Ordered](lo, hi T) Check[T] {
+	return func(v T) error {
+		if v < lo || v > hi {
+			return fmt.Errorf("应在 [%v, %v] 范围内", lo, hi)
+		}
+		return nil
+	}
+}
+
+// OneOf 校验值为 allowed 之一。
+func OneOf[T comparable](allowed ...T) Check[T] {
+	return func(v T) error {
+		if !slices.Contains(allowed, v) {
+			return fmt.Errorf("应为 %v 之一", allowed)
+		}
+		return nil
+	}
+}
+
+// Lookup 读取并解析环境变量 name。未设置时返回零值、false、nil；解析或校验失败时返回错误。
+func Lookup[T Value](name string, checks ...Check[T]) (T, bool, error) {
+	v, ok, err := lookup(name, checks)
+	record(name, os.Getenv(name), ok, false, err)
+	return v, ok, err
+}
+
+// Get 读取并解析环境变量 name，未设置时返回 def。
+// 值无法解析或未通过校验时记录警告并返回 def，启动流程不因可选配置写错而中断；必填项使用 MustGet。
+//
+// 用法：
+//
+//	port := envutil.Get("HTTP_PORT", 8080, envutil.Range(1, 65535))
+//	timeout := envutil.GetDuration("HTTP_TIMEOUT", 30*time.Second)
+//	mode := envutil.Get("APP_MODE", "prod", envutil.OneOf("dev", "test", "prod"))
+func Get[T Value](name string, def T, checks ...Check[T]) T {
+	v, ok, err := lookup(name, checks)
+	if err != nil {
+		log.Warnf("envutil: %v，使用默认值 %v", err, def)
+		record(name, os.Getenv(name), true, true, err)
+		return def
+	}
+	if !ok {
+		record(name, fmt.Sprint(def), false, true, nil)
+		return def
+	}
+	record(name, os.Getenv(name), true, false, nil)
+	return v
+}
+
+// MustGet 读取并解析必填的环境变量 name，未设置、无法解析或未通过校验时 panic。
+//
+// 用法：
+//
+//	dsn := envutil.MustGet[string]("DATABASE_URL")
+//	workers := envutil.MustGet("WORKERS", envutil.Range(1, 64))
+func MustGet[T Value](name string, checks ...Check[T]) T {
+	v, ok, err := Lookup(name, checks...)
+	if err != nil {
+		panic(err.Error())
+	}
+	if !ok {
+		panic(fmt.Sprintf("envutil: 缺少必填环境变量 %s", name))
+	}
+	return v
+}
+
+// GetDuration 读取 time.Duration 类型的环境变量（如 "30s"），等同于 Get(name, def)。
+func GetDuration(name string, def time.Duration) time.Duration {
+	return Get(name, def)
+}
+
+// GetBool 读取布尔类型的环境变量（1 / t / true / 0 / f / false 等，同 strconv.ParseBool），等同于 Get(name, def)。
+func GetBool(name string, def bool) bool {
+	return Get(name, def)
+}
+
+// GetSlice 按 sep（为空时为 ","）拆分环境变量，去除各项首尾空白并忽略空项；未设置时返回 nil。
+//
+// 用法：
+//
+//	brokers := envutil.GetSlice("KAFKA_BROKERS", ",")
+func GetSlice(name, sep string) []string {
+	if sep == "" {
+		sep = ","
+	}
+	s := os.Getenv(name)
+	var out []string
+	for _, p := range strings.Split(s, sep) {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	record(name, s, s != "", false, nil)
+	return out
+}
+
+// lookup 读取并解析环境变量，不记录。
+func lookup[T Value](name string, checks []Check[T]) (T, bool, error) {
+	var v T
+	s := os.Getenv(name)
+	if s == "" {
+		return v, false, nil
+	}
+	if err := parse(reflect.ValueOf(&v).Elem(), s); err != nil {
+		return v, true, fmt.Errorf("envutil: 环境变量 %s=%q 无法解析为 %T: %w", name, maskValue(name, s), v, err)
+	}
+	for _, check := range checks {
+		if check == nil {
+			continue
+		}
+		if err := check(v); err != nil {
+			return v, true, fmt.Errorf("envutil: 环境变量 %s=%q 不合法: %w", name, maskValue(name, s), err)
+		}
+	}
+	return v, true, nil
+}
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+// parse 将字符串解析为 v 的类型并赋值。
+func parse(v reflect.Value, s string) error {
+	s = strings.TrimSpace(s)
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	}
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// 读取记录
+// ---------------------------------------------------------------------------
+
+// Var 一个被读取过的环境变量。
+type Var struct {
+	Name        string `json:"name"`
+	Value       string `json:"value"`           // 环境变量的值，未设置时为使用的默认值；敏感变量已脱敏
+	Set         bool   `json:"set"`             // 环境变量是否已设置（非空）
+	UsedDefault bool   `json:"used_default"`    // 是否使用了默认值（未设置或值不合法）
+	Error       string `json:"error,omitempty"` // 解析或校验失败的原因
+}
+
+var (
+	recordMu sync.Mutex
+	records  = map[string]Var{}
+)
+
+// record 记录一次读取，同名变量以最后一次读取为准。
+func record(name, value string, set, usedDefault bool, err error) {
+	v := Var{Name: name, Value: maskValue(name, value), Set: set, UsedDefault: usedDefault}
+	if err != nil {
+		v.Error = err.Error()
+	}
+	recordMu.Lock()
+	records[name] = v
+	recordMu.Unlock()
+}
+
+// Report 返回所有通过本包读取过的环境变量（按名称排序），用于启动时输出实际生效的配置。
+func Report() []Var {
+	recordMu.Lock()
+	defer recordMu.Unlock()
+	out := make([]Var, 0, len(records))
+	for _, v := range records {
+		out = append(out, v)
+	}
+	slices.SortFunc(out, func(a, b Var) int { return strings.Compare(a.Name, b.Name) })
+	return out
+}
+
+// LogReport 以 info 级别逐行输出 Report()，值不合法的变量以 warn 级别输出。
+//
+// 用法：
+//
+//	cfg := loadConfig() // 内部使用 envutil.Get ...
+//	envutil.LogReport()
+func LogReport() {
+	for _, v := range Report() {
+		note := ""
+		switch {
+		case v.Error != "":
+			log.Warnf("envutil: %s=%q（值不合法，使用默认值）: %s", v.Name, v.Value, v.Error)
+			continue
+		case !v.Set && v.UsedDefault:
+			note = "（默认值）"
+		case !v.Set:
+			note = "（未设置）"
+		}
+		log.Infof("envutil: %s=%q%s", v.Name, v.Value, note)
+	}
+}
+
+// SecretWords 变量名（忽略大小写和下划线）包含其中任一词时视为敏感变量，Report / 日志中值显示为 "***"。
+var SecretWords = []string{
+	"password", "passwd", "secret", "token", "apikey", "accesskey", "privatekey", "credential", "dsn",
+}
+
+// secretSegments 变量名按下划线拆分后某一段与之相同时也视为敏感（如 OBS_AK、OBS_SK），短词不做包含匹配以免误判。
+var secretSegments = []string{"ak", "sk", "pwd"}
+
+// IsSecret 判断变量名是否为敏感变量，见 SecretWords。
+func IsSecret(name string) bool {
+	lower := strings.ToLower(name)
+	compact := strings.ReplaceAll(lower, "_", "")
+	for _, w := range SecretWords {
+		if strings.Contains(compact, w) {
+			return true
+		}
+	}
+	for _, seg := range strings.Split(lower, "_") {
+		if slices.Contains(secretSegments, seg) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskValue 敏感变量的非空值替换为 "***"。
+func maskValue(name, value string) string {
+	if value != "" && IsSecret(name) {
+		return "***"
+	}
+	return value
+}
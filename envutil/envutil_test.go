@@ -0,0 +1,155 @@
+package envutil
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// resetRecords 清空读取记录，避免测试之间互相影响。
+func resetRecords() {
+	recordMu.Lock()
+	records = map[string]Var{}
+	recordMu.Unlock()
+}
+
+func TestGet(t *testing.T) {
+	t.Setenv("ENVUTIL_PORT", " 9090 ")
+	t.Setenv("ENVUTIL_RATIO", "0.5")
+	t.Setenv("ENVUTIL_NAME", "svc")
+	t.Setenv("ENVUTIL_TIMEOUT", "1m30s")
+	t.Setenv("ENVUTIL_DEBUG", "true")
+	t.Setenv("ENVUTIL_EMPTY", "")
+
+	if got := Get("ENVUTIL_PORT", 8080); got != 9090 {
+		t.Errorf("port = %d, 期望 9090", got)
+	}
+	if got := Get("ENVUTIL_RATIO", 1.0); got != 0.5 {
+		t.Errorf("ratio = %v, 期望 0.5", got)
+	}
+	if got := Get("ENVUTIL_NAME", "x"); got != "svc" {
+		t.Errorf("name = %q, 期望 svc", got)
+	}
+	if got := GetDuration("ENVUTIL_TIMEOUT", time.Second); got != 90*time.Second {
+		t.Errorf("timeout = %v, 期望 1m30s", got)
+	}
+	if !GetBool("ENVUTIL_DEBUG", false) {
+		t.Error("debug 期望为 true")
+	}
+	if got := Get("ENVUTIL_EMPTY", 7); got != 7 {
+		t.Errorf("空值应使用默认值，实际 %d", got)
+	}
+	if got := Get("ENVUTIL_MISSING", uint16(3)); got != 3 {
+		t.Errorf("未设置应使用默认值，实际 %d", got)
+	}
+
+	type mode string
+	t.Setenv("ENVUTIL_MODE", "dev")
+	if got := Get("ENVUTIL_MODE", mode("prod")); got != "dev" {
+		t.Errorf("自定义类型 = %q, 期望 dev", got)
+	}
+}
+
+func TestGetInvalid(t *testing.T) {
+	t.Setenv("ENVUTIL_PORT", "abc")
+	if got := Get("ENVUTIL_PORT", 8080); got != 8080 {
+		t.Errorf("无法解析时期望默认值，实际 %d", got)
+	}
+	t.Setenv("ENVUTIL_PORT", "70000")
+	if got := Get("ENVUTIL_PORT", 8080, Range(1, 65535)); got != 8080 {
+		t.Errorf("未通过校验时期望默认值，实际 %d", got)
+	}
+	t.Setenv("ENVUTIL_LEVEL", "verbose")
+	if got := Get("ENVUTIL_LEVEL", "info", OneOf("debug", "info")); got != "info" {
+		t.Errorf("未通过校验时期望默认值，实际 %q", got)
+	}
+	t.Setenv("ENVUTIL_SMALL", "300")
+	if _, _, err := Lookup[int8]("ENVUTIL_SMALL"); err == nil {
+		t.Error("超出 int8 范围期望返回错误")
+	}
+}
+
+func TestMustGet(t *testing.T) {
+	t.Setenv("ENVUTIL_WORKERS", "4")
+	if got := MustGet("ENVUTIL_WORKERS", Range(1, 64)); got != 4 {
+		t.Errorf("workers = %d, 期望 4", got)
+	}
+
+	mustPanic := func(name string, fn func()) {
+		t.Helper()
+		defer func() {
+			if recover() == nil {
+				t.Errorf("%s: 期望 panic", name)
+			}
+		}()
+		fn()
+	}
+	mustPanic("未设置", func() { MustGet[string]("ENVUTIL_MISSING") })
+	t.Setenv("ENVUTIL_WORKERS", "100")
+	mustPanic("校验失败", func() { MustGet("ENVUTIL_WORKERS", Range(1, 64)) })
+}
+
+func TestGetSlice(t *testing.T) {
+	t.Setenv("ENVUTIL_HOSTS", " a, b,,c ")
+	got := GetSlice("ENVUTIL_HOSTS", "")
+	if strings.Join(got, "|") != "a|b|c" {
+		t.Errorf("GetSlice = %q", got)
+	}
+	t.Setenv("ENVUTIL_PATHS", "/x;/y")
+	if got := GetSlice("ENVUTIL_PATHS", ";"); len(got) != 2 || got[1] != "/y" {
+		t.Errorf("GetSlice(;) = %q", got)
+	}
+	if got := GetSlice("ENVUTIL_MISSING", ","); got != nil {
+		t.Errorf("未设置期望 nil，实际 %q", got)
+	}
+}
+
+func TestReport(t *testing.T) {
+	resetRecords()
+	t.Setenv("ENVUTIL_PORT", "9090")
+	t.Setenv("ENVUTIL_DB_PASSWORD", "hunter2")
+	t.Setenv("ENVUTIL_BAD", "x")
+
+	Get("ENVUTIL_PORT", 8080)
+	Get("ENVUTIL_DB_PASSWORD", "")
+	Get("ENVUTIL_TIMEOUT", 5*time.Second)
+	Get("ENVUTIL_BAD", 1)
+
+	vars := Report()
+	if len(vars) != 4 {
+		t.Fatalf("期望 4 个变量，实际 %d: %+v", len(vars), vars)
+	}
+	byName := map[string]Var{}
+	for _, v := range vars {
+		byName[v.Name] = v
+	}
+	if v := byName["ENVUTIL_PORT"]; v.Value != "9090" || !v.Set || v.UsedDefault {
+		t.Errorf("PORT = %+v", v)
+	}
+	if v := byName["ENVUTIL_DB_PASSWORD"]; v.Value != "***" {
+		t.Errorf("敏感变量期望脱敏，实际 %+v", v)
+	}
+	if v := byName["ENVUTIL_TIMEOUT"]; v.Value != "5s" || v.Set || !v.UsedDefault {
+		t.Errorf("TIMEOUT = %+v", v)
+	}
+	if v := byName["ENVUTIL_BAD"]; v.Error == "" || !v.UsedDefault {
+		t.Errorf("BAD = %+v", v)
+	}
+	if vars[0].Name != "ENVUTIL_BAD" {
+		t.Errorf("期望按名称排序，首个为 %s", vars[0].Name)
+	}
+	LogReport()
+}
+
+func TestIsSecret(t *testing.T) {
+	for _, name := range []string{"DB_PASSWORD", "OBS_AK", "OBS_SK", "SecretAccessKey", "AccessKeyID", "GITHUB_TOKEN", "STRIPE_API_KEY", "DATABASE_DSN"} {
+		if !IsSecret(name) {
+			t.Errorf("%s 期望为敏感变量", name)
+		}
+	}
+	for _, name := range []string{"HTTP_PORT", "OBS_BUCKET", "TASK_QUEUE", "KEY_PREFIX"} {
+		if IsSecret(name) {
+			t.Errorf("%s 不应为敏感变量", name)
+		}
+	}
+}
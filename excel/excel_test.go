@@ -0,0 +1,91 @@
+package excel
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type Base struct {
+	ID int64 `excel:"编号"`
+}
+
+type order struct {
+	Base
+	Customer string        `excel:"客户,width=20"`
+	Amount   float64       `excel:"金额,format=0.00"`
+	Paid     bool          `excel:"已支付"`
+	Created  time.Time     `excel:"下单时间"`
+	Timeout  time.Duration `excel:"超时"`
+	Note     *string       `excel:"备注"`
+	Ignored  string        `excel:"-"`
+}
+
+func TestWriteReadRoundTrip(t *testing.T) {
+	note := "加急"
+	orders := []order{
+		{Base: Base{ID: 1}, Customer: "张三", Amount: 12.5, Paid: true,
+			Created: time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC), Timeout: 90 * time.Second, Note: &note, Ignored: "x"},
+		{Base: Base{ID: 2}, Customer: "Li Si"},
+	}
+
+	path := filepath.Join(t.TempDir(), "orders.xlsx")
+	if err := WriteFile(path, "订单", orders, WithAutoFilter()); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	got, err := ReadSheetAs[order](path, "订单", WithStrict())
+	if err != nil {
+		t.Fatalf("ReadSheetAs: %v", err)
+	}
+	orders[0].Ignored = ""
+	if !reflect.DeepEqual(got, orders) {
+		t.Errorf("round trip = %+v, want %+v", got, orders)
+	}
+
+	// sheet 为空时读取第一个工作表
+	if got, err := ReadSheetAs[order](path, ""); err != nil || len(got) != 2 {
+		t.Errorf("ReadSheetAs default sheet = %d rows, %v", len(got), err)
+	}
+	if _, err := ReadSheetAs[order](path, "missing"); !errors.Is(err, ErrSheetNotFound) {
+		t.Errorf("ReadSheetAs missing sheet = %v, want ErrSheetNotFound", err)
+	}
+}
+
+func TestWorkbookMultipleSheets(t *testing.T) {
+	wb := NewWorkbook()
+	defer wb.Close()
+
+	if err := WriteSheet(wb, "A", []order{{Base: Base{ID: 1}}}); err != nil {
+		t.Fatalf("WriteSheet A: %v", err)
+	}
+	cols := []Column{{Name: "编号"}, {Name: "客户"}}
+	rows := [][]any{{1, "x"}, {"bad", "y"}}
+	if err := wb.WriteRows("B", cols, rows, WithPlain()); err != nil {
+		t.Fatalf("WriteRows B: %v", err)
+	}
+	if sheets := wb.File().GetSheetList(); !reflect.DeepEqual(sheets, []string{"A", "B"}) {
+		t.Errorf("sheets = %v, want [A B]", sheets)
+	}
+
+	var buf bytes.Buffer
+	if _, err := wb.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+
+	_, err := ReadSheet[order](bytes.NewReader(buf.Bytes()), "B")
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("ReadSheet = %v, want *ParseError", err)
+	}
+	if pe.Row != 3 || pe.Column != "编号" || pe.Value != "bad" {
+		t.Errorf("ParseError = %+v, want row 3 column 编号 value bad", pe)
+	}
+
+	_, err = ReadSheet[order](bytes.NewReader(buf.Bytes()), "B", WithStrict())
+	if !errors.Is(err, ErrHeaderMismatch) {
+		t.Errorf("ReadSheet strict = %v, want ErrHeaderMismatch", err)
+	}
+}
@@ -0,0 +1,244 @@
+package excel
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/pylemonorg/gotools/timeutil"
+)
+
+// Column 描述工作表中的一列。
+type Column struct {
+	Name   string  // 表头名称
+	Width  float64 // 列宽（字符数），0 表示按内容自动计算
+	Format string  // Excel 数字格式，如 "0.00"、"0.00%"、"yyyy-mm-dd"，为空时使用默认格式
+}
+
+// Option 配置工作表读写。
+type Option func(*options)
+
+type options struct {
+	strict     bool
+	dateFormat string
+	plain      bool
+	autoFilter bool
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{dateFormat: "yyyy-mm-dd hh:mm:ss"}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithStrict 严格模式：读取时表头必须包含结构体的所有列，且不允许出现未知列。
+func WithStrict() Option {
+	return func(o *options) { o.strict = true }
+}
+
+// WithDateFormat 设置写入 time.Time 列的默认 Excel 数字格式，默认 "yyyy-mm-dd hh:mm:ss"。
+func WithDateFormat(format string) Option {
+	return func(o *options) { o.dateFormat = format }
+}
+
+// WithPlain 写入时不做任何样式处理（表头加粗、冻结首行、自动列宽）。
+func WithPlain() Option {
+	return func(o *options) { o.plain = true }
+}
+
+// WithAutoFilter 写入时为表头开启筛选。
+func WithAutoFilter() Option {
+	return func(o *options) { o.autoFilter = true }
+}
+
+// field 描述结构体字段与列的映射。
+type field struct {
+	Column
+	index []int
+}
+
+// fieldsOf 解析结构体 T 的列定义。标签格式为 `excel:"列名,width=20,format=0.00"`，
+// "-" 表示忽略，列名为空时使用字段名。嵌入的匿名结构体字段会被展开。
+func fieldsOf(t reflect.Type) ([]field, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("excel: 仅支持结构体类型，实际为 %s", t)
+	}
+	var fields []field
+	var walk func(t reflect.Type, prefix []int) error
+	walk = func(t reflect.Type, prefix []int) error {
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			index := append(prefix[:len(prefix):len(prefix)], i)
+			tag := sf.Tag.Get("excel")
+			if sf.Anonymous && tag == "" && sf.Type.Kind() == reflect.Struct {
+				if err := walk(sf.Type, index); err != nil {
+					return err
+				}
+				continue
+			}
+			if !sf.IsExported() || tag == "-" {
+				continue
+			}
+			parts := strings.Split(tag, ",")
+			f := field{Column: Column{Name: parts[0]}, index: index}
+			if f.Name == "" {
+				f.Name = sf.Name
+			}
+			for _, p := range parts[1:] {
+				k, v, _ := strings.Cut(p, "=")
+				switch k {
+				case "width":
+					w, err := strconv.ParseFloat(v, 64)
+					if err != nil {
+						return fmt.Errorf("excel: 字段 %s 的 width 无效: %w", sf.Name, err)
+					}
+					f.Width = w
+				case "format":
+					f.Format = v
+				}
+			}
+			fields = append(fields, f)
+		}
+		return nil
+	}
+	if err := walk(t, nil); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// setField 将单元格原始值转换为字段类型并赋值，空串保持零值（指针为 nil）。
+// 日期单元格的原始值为序列号，会按 1900 日期系统转换为 time.Time。
+func setField(v reflect.Value, s string) error {
+	if s == "" {
+		v.SetZero()
+		return nil
+	}
+	if v.Kind() == reflect.Pointer {
+		elem := reflect.New(v.Type().Elem())
+		if err := setField(elem.Elem(), s); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+	if reflect.PointerTo(v.Type()).Implements(textUnmarshalerType) && v.Type() != timeType {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	switch v.Type() {
+	case timeType:
+		var t time.Time
+		var err error
+		if serial, perr := strconv.ParseFloat(s, 64); perr == nil {
+			t, err = excelize.ExcelDateToTime(serial, false)
+		} else {
+			t, err = timeutil.ParseAny(s)
+		}
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	case durationType:
+		d, err := timeutil.ParseDurationExtended(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	s = strings.TrimSpace(s)
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			// Excel 中的整数可能以 "3.0" 等浮点形式存储
+			f, ferr := strconv.ParseFloat(s, 64)
+			if ferr != nil || f != float64(int64(f)) {
+				return err
+			}
+			n = int64(f)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("不支持的字段类型 %s", v.Type())
+	}
+	return nil
+}
+
+// cellValue 将字段值转换为写入单元格的值：数值、布尔和时间保持原生类型，
+// 以便在 Excel 中参与计算；nil 指针和零值时间写为空单元格。
+func cellValue(v reflect.Value) (any, error) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Type() {
+	case timeType:
+		t := v.Interface().(time.Time)
+		if t.IsZero() {
+			return nil, nil
+		}
+		return t, nil
+	case durationType:
+		return time.Duration(v.Int()).String(), nil
+	}
+	if v.Type().Implements(textMarshalerType) {
+		b, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(b), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	default:
+		return nil, fmt.Errorf("不支持的字段类型 %s", v.Type())
+	}
+}
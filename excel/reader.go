@@ -0,0 +1,180 @@
+package excel
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var (
+	// ErrHeaderMismatch 表示严格模式下表头与结构体列不一致。
+	ErrHeaderMismatch = errors.New("excel: 表头与结构体列不一致")
+	// ErrSheetNotFound 表示指定的工作表不存在。
+	ErrSheetNotFound = errors.New("excel: 工作表不存在")
+)
+
+// ParseError 单元格类型转换失败，包含行号（从 1 开始，表头为第 1 行）和列名。
+type ParseError struct {
+	Sheet  string
+	Row    int
+	Column string
+	Value  string
+	Err    error
+}
+
+// Error 实现 error 接口。
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("excel: 工作表 [%s] 第 %d 行列 %q 的值 %q 无效: %v", e.Sheet, e.Row, e.Column, e.Value, e.Err)
+}
+
+// Unwrap 支持 errors.Is / errors.As。
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ReadSheetAs 读取 xlsx 文件中工作表 sheet 的数据为 []T，sheet 为空时读取第一个工作表。
+// 第一行必须是表头，按列名（忽略大小写和首尾空白）匹配结构体字段的 excel 标签，
+// 类型转换失败时返回 *ParseError。
+//
+// 用法：
+//
+//	type Order struct {
+//	    ID      int64     `excel:"订单号"`
+//	    Amount  float64   `excel:"金额"`
+//	    Created time.Time `excel:"下单时间"`
+//	}
+//	orders, err := excel.ReadSheetAs[Order]("orders.xlsx", "")
+func ReadSheetAs[T any](path, sheet string, opts ...Option) ([]T, error) {
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("excel: 打开文件 [%s] 失败: %w", path, err)
+	}
+	defer f.Close()
+
+	out, err := readSheet[T](f, sheet, newOptions(opts))
+	if err != nil {
+		return nil, fmt.Errorf("excel: 读取文件 [%s] 失败: %w", path, err)
+	}
+	return out, nil
+}
+
+// ReadSheet 从 r 读取 xlsx 内容中工作表 sheet 的数据为 []T，规则同 ReadSheetAs。
+func ReadSheet[T any](r io.Reader, sheet string, opts ...Option) ([]T, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("excel: 解析 xlsx 失败: %w", err)
+	}
+	defer f.Close()
+	return readSheet[T](f, sheet, newOptions(opts))
+}
+
+// readSheet 逐行迭代工作表并转换为 T，避免一次性加载全部单元格。
+func readSheet[T any](f *excelize.File, sheet string, o *options) ([]T, error) {
+	fields, err := fieldsOf(reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
+	if sheet == "" {
+		sheet = f.GetSheetName(0)
+	}
+	if idx, _ := f.GetSheetIndex(sheet); idx < 0 {
+		return nil, fmt.Errorf("%w: %s", ErrSheetNotFound, sheet)
+	}
+
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		return nil, fmt.Errorf("excel: 读取工作表 [%s] 失败: %w", sheet, err)
+	}
+	defer rows.Close()
+
+	raw := excelize.Options{RawCellValue: true}
+	var mapping []*field
+	var out []T
+	for line := 1; rows.Next(); line++ {
+		cells, err := rows.Columns(raw)
+		if err != nil {
+			return nil, fmt.Errorf("excel: 读取工作表 [%s] 第 %d 行失败: %w", sheet, line, err)
+		}
+		if mapping == nil {
+			if mapping, err = mapHeader(fields, cells, o); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if isBlank(cells) {
+			continue
+		}
+
+		var v T
+		rv := reflect.ValueOf(&v).Elem()
+		for i, cell := range cells {
+			if i >= len(mapping) || mapping[i] == nil {
+				continue
+			}
+			fd := mapping[i]
+			if err := setField(rv.FieldByIndex(fd.index), cell); err != nil {
+				return nil, &ParseError{Sheet: sheet, Row: line, Column: fd.Name, Value: cell, Err: err}
+			}
+		}
+		out = append(out, v)
+	}
+	if err := rows.Error(); err != nil {
+		return nil, fmt.Errorf("excel: 读取工作表 [%s] 失败: %w", sheet, err)
+	}
+	if mapping == nil {
+		return nil, fmt.Errorf("excel: 工作表 [%s] 缺少表头: %w", sheet, io.ErrUnexpectedEOF)
+	}
+	return out, nil
+}
+
+// mapHeader 按表头建立第 i 列到字段的映射，nil 表示忽略该列。
+func mapHeader(fields []field, header []string, o *options) ([]*field, error) {
+	byName := make(map[string]*field, len(fields))
+	for i := range fields {
+		byName[normalizeName(fields[i].Name)] = &fields[i]
+	}
+	mapping := make([]*field, len(header))
+	var unknown []string
+	seen := make(map[string]bool)
+	for i, h := range header {
+		key := normalizeName(h)
+		if fd, ok := byName[key]; ok {
+			mapping[i] = fd
+			seen[key] = true
+		} else if key != "" {
+			unknown = append(unknown, h)
+		}
+	}
+
+	if o.strict {
+		var missing []string
+		for _, fd := range fields {
+			if !seen[normalizeName(fd.Name)] {
+				missing = append(missing, fd.Name)
+			}
+		}
+		if len(missing) > 0 || len(unknown) > 0 {
+			return nil, fmt.Errorf("%w: 缺少列 %v，未知列 %v", ErrHeaderMismatch, missing, unknown)
+		}
+	}
+	return mapping, nil
+}
+
+// normalizeName 列名匹配时忽略大小写和首尾空白。
+func normalizeName(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// isBlank 判断是否为空行（所有单元格均为空白）。
+func isBlank(cells []string) bool {
+	for _, c := range cells {
+		if strings.TrimSpace(c) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,280 @@
+package excel
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/pylemonorg/gotools/strutil"
+)
+
+// 自动列宽的上下限（字符数）。
+const (
+	minAutoWidth = 8
+	maxAutoWidth = 60
+)
+
+// Workbook 封装 excelize.File，用于将多个工作表写入同一个 xlsx 文件。
+//
+// 用法：
+//
+//	wb := excel.NewWorkbook()
+//	defer wb.Close()
+//	if err := excel.WriteSheet(wb, "订单", orders); err != nil {
+//	    return err
+//	}
+//	if err := excel.WriteSheet(wb, "退款", refunds, excel.WithAutoFilter()); err != nil {
+//	    return err
+//	}
+//	err := wb.SaveAs("report.xlsx")
+type Workbook struct {
+	f     *excelize.File
+	fresh bool // 仍是 NewFile 创建的默认空工作表，首次写入时直接重命名
+}
+
+// NewWorkbook 创建一个空工作簿。
+func NewWorkbook() *Workbook {
+	return &Workbook{f: excelize.NewFile(), fresh: true}
+}
+
+// File 返回底层的 excelize.File，用于本包未覆盖的高级操作（图表、合并单元格等）。
+func (wb *Workbook) File() *excelize.File { return wb.f }
+
+// SaveAs 保存为 xlsx 文件。
+func (wb *Workbook) SaveAs(path string) error {
+	if err := wb.f.SaveAs(path); err != nil {
+		return fmt.Errorf("excel: 保存文件 [%s] 失败: %w", path, err)
+	}
+	return nil
+}
+
+// WriteTo 将工作簿写入 w，实现 io.WriterTo。
+func (wb *Workbook) WriteTo(w io.Writer) (int64, error) {
+	n, err := wb.f.WriteTo(w)
+	if err != nil {
+		return n, fmt.Errorf("excel: 写入工作簿失败: %w", err)
+	}
+	return n, nil
+}
+
+// Close 释放工作簿占用的临时文件。
+func (wb *Workbook) Close() error {
+	return wb.f.Close()
+}
+
+// WriteRows 将表头 cols 和数据 rows 写入工作表 sheet（工作表不存在时自动创建，已存在时从 A1 开始覆盖写入）。
+// rows 中的值按 excelize.SetCellValue 的规则写入，nil 为空单元格。
+// 默认样式：表头加粗并填充底色、冻结首行、按内容自动列宽，可用 WithPlain 关闭。
+func (wb *Workbook) WriteRows(sheet string, cols []Column, rows [][]any, opts ...Option) error {
+	o := newOptions(opts)
+	if err := wb.prepareSheet(sheet); err != nil {
+		return err
+	}
+	f := wb.f
+
+	header := make([]any, len(cols))
+	widths := make([]int, len(cols))
+	for i, c := range cols {
+		header[i] = c.Name
+		widths[i] = strutil.DisplayWidth(c.Name)
+	}
+	if err := f.SetSheetRow(sheet, "A1", &header); err != nil {
+		return fmt.Errorf("excel: 写入表头失败: %w", err)
+	}
+
+	for n, row := range rows {
+		cell, _ := excelize.CoordinatesToCellName(1, n+2)
+		if err := f.SetSheetRow(sheet, cell, &row); err != nil {
+			return fmt.Errorf("excel: 写入第 %d 行失败: %w", n+2, err)
+		}
+		if o.plain {
+			continue
+		}
+		for i, v := range row {
+			if i < len(widths) {
+				widths[i] = max(widths[i], displayWidth(v))
+			}
+		}
+	}
+
+	if err := wb.applyFormats(sheet, cols, rows, o); err != nil {
+		return err
+	}
+	if !o.plain {
+		if err := wb.applyStyle(sheet, cols, widths); err != nil {
+			return err
+		}
+	}
+	if o.autoFilter && len(cols) > 0 {
+		last, _ := excelize.CoordinatesToCellName(len(cols), len(rows)+1)
+		if err := f.AutoFilter(sheet, "A1:"+last, nil); err != nil {
+			return fmt.Errorf("excel: 设置筛选失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// WriteSheet 将 records 写入工作簿的工作表 sheet，首行为表头，列顺序与结构体字段顺序一致。
+// 列名、列宽和数字格式由 excel 标签指定，如 `excel:"金额,width=12,format=0.00"`。
+func WriteSheet[T any](wb *Workbook, sheet string, records []T, opts ...Option) error {
+	fields, err := fieldsOf(reflect.TypeFor[T]())
+	if err != nil {
+		return err
+	}
+	cols := make([]Column, len(fields))
+	for i, f := range fields {
+		cols[i] = f.Column
+	}
+
+	rows := make([][]any, len(records))
+	for n, rec := range records {
+		rv := reflect.ValueOf(rec)
+		row := make([]any, len(fields))
+		for i, f := range fields {
+			v, err := cellValue(rv.FieldByIndex(f.index))
+			if err != nil {
+				return fmt.Errorf("excel: 第 %d 条记录列 %q 转换失败: %w", n+1, f.Name, err)
+			}
+			row[i] = v
+		}
+		rows[n] = row
+	}
+	return wb.WriteRows(sheet, cols, rows, opts...)
+}
+
+// WriteFile 将 records 写入新的 xlsx 文件（覆盖已有文件），sheet 为工作表名。
+//
+// 用法：
+//
+//	err := excel.WriteFile("users.xlsx", "用户", users)
+func WriteFile[T any](path, sheet string, records []T, opts ...Option) error {
+	wb := NewWorkbook()
+	defer wb.Close()
+	if err := WriteSheet(wb, sheet, records, opts...); err != nil {
+		return err
+	}
+	return wb.SaveAs(path)
+}
+
+// ---------------------------------------------------------------------------
+// 内部实现
+// ---------------------------------------------------------------------------
+
+// prepareSheet 确保工作表存在：首次写入时重命名默认工作表，否则新建。
+func (wb *Workbook) prepareSheet(sheet string) error {
+	if wb.fresh {
+		wb.fresh = false
+		if def := wb.f.GetSheetName(0); def != sheet {
+			if err := wb.f.SetSheetName(def, sheet); err != nil {
+				return fmt.Errorf("excel: 创建工作表 [%s] 失败: %w", sheet, err)
+			}
+		}
+		return nil
+	}
+	if idx, _ := wb.f.GetSheetIndex(sheet); idx >= 0 {
+		return nil
+	}
+	if _, err := wb.f.NewSheet(sheet); err != nil {
+		return fmt.Errorf("excel: 创建工作表 [%s] 失败: %w", sheet, err)
+	}
+	return nil
+}
+
+// applyFormats 为数据区域设置数字格式：列显式指定的 Format 优先，时间列使用默认日期格式。
+func (wb *Workbook) applyFormats(sheet string, cols []Column, rows [][]any, o *options) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	for i, c := range cols {
+		format := c.Format
+		if format == "" && isTimeColumn(rows, i) {
+			format = o.dateFormat
+		}
+		if format == "" {
+			continue
+		}
+		style, err := wb.f.NewStyle(&excelize.Style{CustomNumFmt: &format})
+		if err != nil {
+			return fmt.Errorf("excel: 创建列 %q 的格式失败: %w", c.Name, err)
+		}
+		top, _ := excelize.CoordinatesToCellName(i+1, 2)
+		bottom, _ := excelize.CoordinatesToCellName(i+1, len(rows)+1)
+		if err := wb.f.SetCellStyle(sheet, top, bottom, style); err != nil {
+			return fmt.Errorf("excel: 设置列 %q 的格式失败: %w", c.Name, err)
+		}
+	}
+	return nil
+}
+
+// applyStyle 设置表头样式、冻结首行和列宽。
+func (wb *Workbook) applyStyle(sheet string, cols []Column, widths []int) error {
+	if len(cols) == 0 {
+		return nil
+	}
+	f := wb.f
+	style, err := f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true},
+		Fill:      excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"#D9E1F2"}},
+		Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center"},
+		Border: []excelize.Border{
+			{Type: "bottom", Color: "#8EA9DB", Style: 1},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("excel: 创建表头样式失败: %w", err)
+	}
+	last, _ := excelize.CoordinatesToCellName(len(cols), 1)
+	if err := f.SetCellStyle(sheet, "A1", last, style); err != nil {
+		return fmt.Errorf("excel: 设置表头样式失败: %w", err)
+	}
+
+	if err := f.SetPanes(sheet, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	}); err != nil {
+		return fmt.Errorf("excel: 冻结首行失败: %w", err)
+	}
+
+	for i, c := range cols {
+		width := c.Width
+		if width == 0 {
+			width = float64(min(max(widths[i], minAutoWidth), maxAutoWidth) + 2)
+		}
+		name, _ := excelize.ColumnNumberToName(i + 1)
+		if err := f.SetColWidth(sheet, name, name, width); err != nil {
+			return fmt.Errorf("excel: 设置列 %q 宽度失败: %w", c.Name, err)
+		}
+	}
+	return nil
+}
+
+// isTimeColumn 判断第 i 列是否为时间列（取第一个非空值判断）。
+func isTimeColumn(rows [][]any, i int) bool {
+	for _, row := range rows {
+		if i >= len(row) || row[i] == nil {
+			continue
+		}
+		_, ok := row[i].(time.Time)
+		return ok
+	}
+	return false
+}
+
+// displayWidth 估算单元格值的显示宽度，用于自动列宽。
+func displayWidth(v any) int {
+	switch x := v.(type) {
+	case nil:
+		return 0
+	case string:
+		return strutil.DisplayWidth(x)
+	case time.Time:
+		return 19
+	default:
+		return strutil.DisplayWidth(fmt.Sprint(x))
+	}
+}
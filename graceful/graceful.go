@@ -0,0 +1,317 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/pylemonorg/gotools/logger"
+)
+
+var log = logger.Module("graceful")
+
+// ErrShutdownTimeout 表示关闭过程中有任务或钩子未在超时前结束。
+var ErrShutdownTimeout = errors.New("graceful: 关闭超时")
+
+// osExit 收到第二次信号时强制退出，测试中可替换。
+var osExit = os.Exit
+
+// Option 配置 Manager。
+type Option func(*Manager)
+
+// WithSignals 设置触发关闭的信号，默认 SIGINT、SIGTERM。
+func WithSignals(sigs ...os.Signal) Option {
+	return func(m *Manager) { m.signals = sigs }
+}
+
+// WithTimeout 设置整个关闭过程（等待任务 + 执行钩子）的超时，默认 30s。
+func WithTimeout(d time.Duration) Option {
+	return func(m *Manager) { m.timeout = d }
+}
+
+// WithHookTimeout 设置单个钩子的默认超时，默认 10s。
+func WithHookTimeout(d time.Duration) Option {
+	return func(m *Manager) { m.hookTimeout = d }
+}
+
+// HookOption 钩子选项。
+type HookOption func(*hook)
+
+// Timeout 设置该钩子的超时，覆盖 WithHookTimeout。
+func Timeout(d time.Duration) HookOption {
+	return func(h *hook) { h.timeout = d }
+}
+
+// hook 已注册的关闭钩子。
+type hook struct {
+	name    string
+	fn      func(ctx context.Context) error
+	timeout time.Duration
+}
+
+// task 通过 Go 启动的后台任务。
+type task struct {
+	name string
+	done chan struct{}
+}
+
+// Manager 应用生命周期管理：监听退出信号，关闭时先取消 Context 并等待后台任务退出，
+// 再按注册的逆序（同 defer）逐个执行关闭钩子，每个钩子有独立超时，超时未结束的任务和钩子会被报告。
+// 收到第二次信号时立即强制退出。
+//
+// 用法：
+//
+//	func main() {
+//	    g := graceful.New()
+//	    g.AddFunc("logger", logger.Close) // 最先注册，最后关闭
+//
+//	    rdb, _ := db.NewRedisClient(cfg)
+//	    g.AddCloser("redis", rdb)
+//	    pg, _ := db.NewPostgresClient(pgCfg)
+//	    g.AddCloser("postgres", pg)
+//	    obs, _ := obsutil.NewObsClient(obsCfg)
+//	    g.AddFunc("obs", obs.Close)
+//
+//	    mon, _ := monitor.NewResourceMonitor(nil)
+//	    mon.Start()
+//	    g.AddFunc("monitor", mon.Stop)
+//
+//	    g.Go("http", func(ctx context.Context) error { return serve(ctx) })
+//	    if err := g.Wait(); err != nil {
+//	        fmt.Fprintln(os.Stderr, err)
+//	        os.Exit(1)
+//	    }
+//	}
+type Manager struct {
+	signals     []os.Signal
+	timeout     time.Duration
+	hookTimeout time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	sigCh  chan os.Signal
+
+	mu       sync.Mutex
+	hooks    []*hook
+	tasks    []*task
+	taskErrs []error
+
+	once sync.Once
+	done chan struct{}
+	err  error
+}
+
+// New 创建 Manager 并开始监听退出信号。
+func New(opts ...Option) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &Manager{
+		signals:     []os.Signal{syscall.SIGINT, syscall.SIGTERM},
+		timeout:     30 * time.Second,
+		hookTimeout: 10 * time.Second,
+		ctx:         ctx,
+		cancel:      cancel,
+		sigCh:       make(chan os.Signal, 2),
+		done:        make(chan struct{}),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(m)
+		}
+	}
+	signal.Notify(m.sigCh, m.signals...)
+	go m.watchSignals()
+	return m
+}
+
+// Context 返回应用级 Context，开始关闭时取消。
+func (m *Manager) Context() context.Context { return m.ctx }
+
+// Add 注册关闭钩子 fn，ctx 在钩子超时后取消。
+func (m *Manager) Add(name string, fn func(ctx context.Context) error, opts ...HookOption) {
+	h := &hook{name: name, fn: fn, timeout: m.hookTimeout}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(h)
+		}
+	}
+	m.mu.Lock()
+	m.hooks = append(m.hooks, h)
+	m.mu.Unlock()
+}
+
+// AddCloser 注册 io.Closer（如 *db.RedisClient、*db.PostgresClient、*notify.LogHook）。
+func (m *Manager) AddCloser(name string, c io.Closer, opts ...HookOption) {
+	m.Add(name, func(context.Context) error { return c.Close() }, opts...)
+}
+
+// AddFunc 注册无返回值的关闭函数（如 (*obsutil.ObsClient).Close、(*monitor.ResourceMonitor).Stop、logger.Close）。
+func (m *Manager) AddFunc(name string, fn func(), opts ...HookOption) {
+	m.Add(name, func(context.Context) error { fn(); return nil }, opts...)
+}
+
+// Go 启动后台任务 fn，ctx 即 Context()。关闭时先等待所有任务退出再执行钩子；
+// 任务返回非 nil 错误（context.Canceled 除外）时触发关闭。
+func (m *Manager) Go(name string, fn func(ctx context.Context) error) {
+	t := &task{name: name, done: make(chan struct{})}
+	m.mu.Lock()
+	m.tasks = append(m.tasks, t)
+	m.mu.Unlock()
+
+	go func() {
+		defer close(t.done)
+		err := safeCall(func() error { return fn(m.ctx) })
+		if err == nil || errors.Is(err, context.Canceled) {
+			return
+		}
+		log.Errorf("graceful: 任务 [%s] 异常退出，开始关闭: %v", name, err)
+		m.mu.Lock()
+		m.taskErrs = append(m.taskErrs, fmt.Errorf("graceful: 任务 [%s]: %w", name, err))
+		m.mu.Unlock()
+		go m.Shutdown()
+	}()
+}
+
+// Run 以 fn 作为主任务运行，fn 返回或收到退出信号后执行关闭，返回 fn 和关闭过程的错误。
+//
+// 用法：
+//
+//	err := g.Run(func(ctx context.Context) error {
+//	    return worker.Start(ctx)
+//	})
+func (m *Manager) Run(fn func(ctx context.Context) error) error {
+	m.Go("main", func(ctx context.Context) error {
+		err := fn(ctx)
+		if err == nil {
+			go m.Shutdown()
+		}
+		return err
+	})
+	return m.Wait()
+}
+
+// Wait 阻塞直到关闭完成（由信号、任务错误或 Shutdown 触发），返回关闭过程的错误。
+func (m *Manager) Wait() error {
+	<-m.done
+	return m.err
+}
+
+// Shutdown 立即开始关闭并等待完成，可重复、并发调用，均返回同一结果。
+func (m *Manager) Shutdown() error {
+	m.once.Do(func() {
+		m.err = m.shutdown()
+		signal.Stop(m.sigCh)
+		close(m.done)
+	})
+	<-m.done
+	return m.err
+}
+
+// ---------------------------------------------------------------------------
+// 内部实现
+// ---------------------------------------------------------------------------
+
+// watchSignals 第一次信号触发关闭，关闭期间再次收到信号时强制退出。
+func (m *Manager) watchSignals() {
+	select {
+	case sig := <-m.sigCh:
+		log.Infof("graceful: 收到信号 %v，开始关闭（再次发送信号将强制退出）", sig)
+		go m.Shutdown()
+	case <-m.done:
+		return
+	}
+	select {
+	case sig := <-m.sigCh:
+		log.Errorf("graceful: 再次收到信号 %v，强制退出", sig)
+		logger.Flush()
+		osExit(1)
+	case <-m.done:
+	}
+}
+
+// shutdown 取消 Context、等待任务、逆序执行钩子，汇总错误和未结束项。
+func (m *Manager) shutdown() error {
+	start := time.Now()
+	m.cancel()
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+
+	m.mu.Lock()
+	tasks := append([]*task(nil), m.tasks...)
+	hooks := append([]*hook(nil), m.hooks...)
+	m.mu.Unlock()
+
+	var stragglers []string
+	for _, t := range tasks {
+		select {
+		case <-t.done:
+		case <-ctx.Done():
+			stragglers = append(stragglers, "任务 "+t.name)
+		}
+	}
+	if len(stragglers) > 0 {
+		log.Warnf("graceful: 以下任务在超时前未退出: %s", strings.Join(stragglers, ", "))
+	}
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		h := hooks[i]
+		if ctx.Err() != nil {
+			stragglers = append(stragglers, "钩子 "+h.name+"（未执行）")
+			continue
+		}
+		hookStart := time.Now()
+		err := m.runHook(ctx, h)
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			log.Warnf("graceful: 关闭 [%s] 超时（%v），继续关闭其余资源", h.name, h.timeout)
+			stragglers = append(stragglers, "钩子 "+h.name)
+		case err != nil:
+			log.Errorf("graceful: 关闭 [%s] 失败: %v", h.name, err)
+			errs = append(errs, fmt.Errorf("graceful: 关闭 [%s]: %w", h.name, err))
+		default:
+			log.Infof("graceful: 已关闭 [%s]，耗时 %v", h.name, time.Since(hookStart).Round(time.Millisecond))
+		}
+	}
+
+	m.mu.Lock()
+	errs = append(m.taskErrs, errs...)
+	m.mu.Unlock()
+	if len(stragglers) > 0 {
+		errs = append(errs, fmt.Errorf("%w: %s", ErrShutdownTimeout, strings.Join(stragglers, ", ")))
+	}
+	log.Infof("graceful: 关闭完成，耗时 %v", time.Since(start).Round(time.Millisecond))
+	return errors.Join(errs...)
+}
+
+// runHook 在独立协程中执行钩子，超时后不再等待（钩子协程仍在后台运行）。
+func (m *Manager) runHook(parent context.Context, h *hook) error {
+	ctx, cancel := context.WithTimeout(parent, h.timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- safeCall(func() error { return h.fn(ctx) }) }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return context.DeadlineExceeded
+	}
+}
+
+// safeCall 执行 fn 并将 panic 转换为错误。
+func safeCall(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	return fn()
+}
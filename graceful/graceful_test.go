@@ -0,0 +1,145 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestShutdownOrder(t *testing.T) {
+	m := New(WithSignals(syscall.SIGUSR1))
+
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) func() {
+		return func() {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, name)
+		}
+	}
+	m.AddFunc("logger", record("logger"))
+	m.AddFunc("redis", record("redis"))
+	m.Go("worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		record("worker")()
+		return ctx.Err()
+	})
+
+	if err := m.Shutdown(); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	want := []string{"worker", "redis", "logger"}
+	if strings.Join(order, ",") != strings.Join(want, ",") {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+	if m.Context().Err() == nil {
+		t.Error("Context should be canceled after Shutdown")
+	}
+	// 重复调用返回同一结果，钩子不会重复执行
+	if err := m.Shutdown(); err != nil || len(order) != 3 {
+		t.Errorf("second Shutdown = %v, order = %v", err, order)
+	}
+}
+
+func TestHookTimeoutAndErrors(t *testing.T) {
+	m := New(WithSignals(syscall.SIGUSR1), WithHookTimeout(time.Second))
+	boom := errors.New("boom")
+
+	var lastRan bool
+	m.AddFunc("last", func() { lastRan = true })
+	m.Add("slow", func(ctx context.Context) error {
+		time.Sleep(time.Hour)
+		return nil
+	}, Timeout(20*time.Millisecond))
+	m.Add("failing", func(context.Context) error { return boom })
+	m.AddFunc("panicking", func() { panic("oops") })
+
+	err := m.Shutdown()
+	if !errors.Is(err, boom) {
+		t.Errorf("Shutdown = %v, want boom", err)
+	}
+	if !errors.Is(err, ErrShutdownTimeout) || !strings.Contains(err.Error(), "钩子 slow") {
+		t.Errorf("Shutdown = %v, want ErrShutdownTimeout naming slow", err)
+	}
+	if !strings.Contains(err.Error(), "panic: oops") {
+		t.Errorf("Shutdown = %v, want panic error", err)
+	}
+	if !lastRan {
+		t.Error("hooks after a timed-out hook should still run")
+	}
+}
+
+func TestTaskStraggler(t *testing.T) {
+	m := New(WithSignals(syscall.SIGUSR1), WithTimeout(50*time.Millisecond))
+	var hookRan bool
+	m.AddFunc("redis", func() { hookRan = true })
+	m.Go("stuck", func(context.Context) error {
+		time.Sleep(time.Hour)
+		return nil
+	})
+
+	err := m.Shutdown()
+	if !errors.Is(err, ErrShutdownTimeout) || !strings.Contains(err.Error(), "任务 stuck") {
+		t.Errorf("Shutdown = %v, want straggler stuck", err)
+	}
+	if hookRan || !strings.Contains(err.Error(), "钩子 redis（未执行）") {
+		t.Errorf("hook should be skipped after total timeout, err = %v", err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	m := New(WithSignals(syscall.SIGUSR1))
+	var closed bool
+	m.AddFunc("db", func() { closed = true })
+
+	boom := errors.New("boom")
+	err := m.Run(func(context.Context) error { return boom })
+	if !errors.Is(err, boom) {
+		t.Errorf("Run = %v, want boom", err)
+	}
+	if !closed {
+		t.Error("hooks should run after the main task fails")
+	}
+
+	m = New(WithSignals(syscall.SIGUSR1))
+	if err := m.Run(func(context.Context) error { return nil }); err != nil {
+		t.Errorf("Run = %v, want nil", err)
+	}
+}
+
+func TestSignal(t *testing.T) {
+	exited := make(chan int, 1)
+	prev := osExit
+	osExit = func(code int) { exited <- code }
+	defer func() { osExit = prev }()
+
+	m := New(WithSignals(syscall.SIGUSR1))
+	release := make(chan struct{})
+	m.AddFunc("slow", func() { <-release })
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+	select {
+	case <-m.Context().Done():
+	case <-time.After(time.Second):
+		t.Fatal("signal did not start shutdown")
+	}
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+	select {
+	case code := <-exited:
+		if code != 1 {
+			t.Errorf("exit code = %d, want 1", code)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("second signal did not force exit")
+	}
+	close(release)
+	if err := m.Wait(); err != nil {
+		t.Errorf("Wait = %v", err)
+	}
+}
@@ -0,0 +1,198 @@
+package hashutil
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// 内容寻址 key 的默认值。
+const (
+	contentKeyAlgo         = "sha256"
+	defaultContentKeyDepth = 2
+	maxContentKeyDepth     = 8
+)
+
+// ErrInvalidContentKey 表示 ParseContentKey 的参数不是 ContentKey 生成的格式。
+var ErrInvalidContentKey = errors.New("hashutil: invalid content key")
+
+// ContentKeyOption ContentKey 系列函数的可选配置。
+type ContentKeyOption func(*contentKeyOptions)
+
+// contentKeyOptions 内容寻址 key 的配置。
+type contentKeyOptions struct {
+	prefix string
+	depth  int
+	size   bool
+}
+
+// WithFanOut 设置分层目录数（每层取摘要的 2 个十六进制字符，即 256 个子目录），默认 2 层，0 表示不分层，最多 8 层。
+func WithFanOut(depth int) ContentKeyOption {
+	return func(o *contentKeyOptions) {
+		o.depth = min(max(depth, 0), maxContentKeyDepth)
+	}
+}
+
+// WithKeyPrefix 设置 key 前缀（如 "blobs"），首尾的 "/" 会被去掉。
+func WithKeyPrefix(prefix string) ContentKeyOption {
+	return func(o *contentKeyOptions) {
+		o.prefix = strings.Trim(prefix, "/")
+	}
+}
+
+// WithSize 在文件名中附加内容字节数（"<摘要>-<大小><扩展名>"），无需读取对象即可知道大小，
+// 也能区分极端情况下摘要相同但长度不同的内容。
+func WithSize() ContentKeyOption {
+	return func(o *contentKeyOptions) {
+		o.size = true
+	}
+}
+
+// ContentKey 返回 data 的内容寻址 key：相同内容始终得到相同 key，适合在 OBS 中存放去重后的文件。
+// 格式为 "[prefix/]sha256/<分层目录>/<摘要>[-<大小>]<扩展名>"，ext 可带或不带 "."，统一转为小写，为空时不加扩展名。
+//
+// 用法：
+//
+//	key := hashutil.ContentKey(data, ".jpg") // "sha256/ab/cd/abcd....jpg"
+//	key = hashutil.ContentKey(data, "jpg", hashutil.WithKeyPrefix("blobs"), hashutil.WithFanOut(1), hashutil.WithSize())
+//	// "blobs/sha256/ab/abcd...-20480.jpg"
+//	_, err := obsClient.PutBytesIfAbsent(key, data) // 已存在（obsutil.ErrObjectAlreadyExists）说明内容相同，无需重复上传
+func ContentKey(data []byte, ext string, opts ...ContentKeyOption) string {
+	sum := sha256.Sum256(data)
+	return buildContentKey(hex.EncodeToString(sum[:]), int64(len(data)), ext, opts)
+}
+
+// ContentKeyReader 流式计算 r 中全部数据的内容寻址 key，同时返回读取的字节数，不会把数据整体读入内存。
+func ContentKeyReader(r io.Reader, ext string, opts ...ContentKeyOption) (string, int64, error) {
+	h := sha256.New()
+	n, err := io.Copy(h, r)
+	if err != nil {
+		return "", 0, fmt.Errorf("hashutil: content key read: %w", err)
+	}
+	return buildContentKey(hex.EncodeToString(h.Sum(nil)), n, ext, opts), n, nil
+}
+
+// ContentKeyFile 流式计算文件的内容寻址 key（扩展名取自文件名），同时返回文件大小。
+//
+// 用法：
+//
+//	key, size, err := hashutil.ContentKeyFile("/data/cover.JPG") // "sha256/ab/cd/abcd....jpg"
+func ContentKeyFile(path string, opts ...ContentKeyOption) (string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, fmt.Errorf("hashutil: open %s: %w", path, err)
+	}
+	defer f.Close()
+	return ContentKeyReader(f, filepath.Ext(path), opts...)
+}
+
+// buildContentKey 按配置拼接 key。
+func buildContentKey(sum string, size int64, ext string, opts []ContentKeyOption) string {
+	o := contentKeyOptions{depth: defaultContentKeyDepth}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+
+	var b strings.Builder
+	if o.prefix != "" {
+		b.WriteString(o.prefix)
+		b.WriteByte('/')
+	}
+	b.WriteString(contentKeyAlgo)
+	b.WriteByte('/')
+	for i := range o.depth {
+		b.WriteString(sum[2*i : 2*i+2])
+		b.WriteByte('/')
+	}
+	b.WriteString(sum)
+	if o.size {
+		b.WriteByte('-')
+		b.WriteString(strconv.FormatInt(size, 10))
+	}
+	b.WriteString(normalizeExt(ext))
+	return b.String()
+}
+
+// normalizeExt 将扩展名统一为小写并带 "."，去掉其中的路径分隔符。
+func normalizeExt(ext string) string {
+	ext = strings.ToLower(strings.TrimSpace(ext))
+	ext = strings.NewReplacer("/", "", "\\", "").Replace(ext)
+	ext = strings.TrimLeft(ext, ".")
+	if ext == "" {
+		return ""
+	}
+	return "." + ext
+}
+
+// ContentKeyInfo ParseContentKey 的解析结果。
+type ContentKeyInfo struct {
+	Hash string // SHA-256 十六进制摘要
+	Size int64  // 内容字节数，key 中未包含大小时为 -1
+	Ext  string // 扩展名（含 "."），没有时为空
+}
+
+// ParseContentKey 从 ContentKey 生成的 key 中解析摘要、大小和扩展名，并校验分层目录与摘要一致。
+// 可用于下载后校验内容（对比 Hash），或在列举对象时直接得到大小。
+func ParseContentKey(key string) (ContentKeyInfo, error) {
+	segs := strings.Split(key, "/")
+	algo := -1
+	for i := len(segs) - 2; i >= 0; i-- {
+		if segs[i] == contentKeyAlgo {
+			algo = i
+			break
+		}
+	}
+	if algo < 0 {
+		return ContentKeyInfo{}, fmt.Errorf("%w: %s", ErrInvalidContentKey, key)
+	}
+
+	name := segs[len(segs)-1]
+	info := ContentKeyInfo{Size: -1}
+	if i := strings.IndexByte(name, '.'); i >= 0 {
+		name, info.Ext = name[:i], name[i:]
+	}
+	if hashPart, sizePart, ok := strings.Cut(name, "-"); ok {
+		size, err := strconv.ParseInt(sizePart, 10, 64)
+		if err != nil || size < 0 {
+			return ContentKeyInfo{}, fmt.Errorf("%w: %s", ErrInvalidContentKey, key)
+		}
+		name, info.Size = hashPart, size
+	}
+	if !isSHA256Hex(name) {
+		return ContentKeyInfo{}, fmt.Errorf("%w: %s", ErrInvalidContentKey, key)
+	}
+	info.Hash = name
+
+	dirs := segs[algo+1 : len(segs)-1]
+	if len(dirs) > maxContentKeyDepth {
+		return ContentKeyInfo{}, fmt.Errorf("%w: %s", ErrInvalidContentKey, key)
+	}
+	for i, d := range dirs {
+		if d != name[2*i:2*i+2] {
+			return ContentKeyInfo{}, fmt.Errorf("%w: %s", ErrInvalidContentKey, key)
+		}
+	}
+	return info, nil
+}
+
+// isSHA256Hex 判断 s 是否为 64 位小写十六进制字符串。
+func isSHA256Hex(s string) bool {
+	if len(s) != sha256.Size*2 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,123 @@
+package hashutil
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestContentKey(t *testing.T) {
+	data := []byte("hello")
+	sum := sha256Hex(data)
+	tests := []struct {
+		ext  string
+		opts []ContentKeyOption
+		want string
+	}{
+		{".jpg", nil, "sha256/" + sum[:2] + "/" + sum[2:4] + "/" + sum + ".jpg"},
+		{"JPG", nil, "sha256/" + sum[:2] + "/" + sum[2:4] + "/" + sum + ".jpg"},
+		{"", []ContentKeyOption{WithFanOut(0)}, "sha256/" + sum},
+		{"../x/.png", []ContentKeyOption{WithFanOut(0)}, "sha256/" + sum + ".x.png"},
+		{"tar.gz", []ContentKeyOption{WithFanOut(1), WithKeyPrefix("/blobs/"), WithSize()}, "blobs/sha256/" + sum[:2] + "/" + sum + "-5.tar.gz"},
+		{"", []ContentKeyOption{WithFanOut(-1), nil}, "sha256/" + sum},
+	}
+	for _, tt := range tests {
+		if got := ContentKey(data, tt.ext, tt.opts...); got != tt.want {
+			t.Errorf("ContentKey(%q) = %q, want %q", tt.ext, got, tt.want)
+		}
+	}
+
+	if got := ContentKey(data, "", WithFanOut(100)); strings.Count(got, "/") != 1+maxContentKeyDepth {
+		t.Errorf("WithFanOut(100) = %q, want %d levels", got, maxContentKeyDepth)
+	}
+}
+
+func TestContentKeyReaderFile(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 1000)
+	want := ContentKey(data, ".bin", WithSize())
+
+	key, n, err := ContentKeyReader(bytes.NewReader(data), ".bin", WithSize())
+	if err != nil || key != want || n != int64(len(data)) {
+		t.Errorf("ContentKeyReader = %q, %d, %v, want %q", key, n, err, want)
+	}
+
+	path := filepath.Join(t.TempDir(), "data.BIN")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	key, n, err = ContentKeyFile(path, WithSize())
+	if err != nil || key != want || n != int64(len(data)) {
+		t.Errorf("ContentKeyFile = %q, %d, %v, want %q", key, n, err, want)
+	}
+	if _, _, err := ContentKeyFile(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("ContentKeyFile on missing file should fail")
+	}
+}
+
+func TestParseContentKeyRoundTrip(t *testing.T) {
+	for _, data := range [][]byte{nil, []byte("hello"), bytes.Repeat([]byte{0xff}, 4096)} {
+		sum := sha256Hex(data)
+		for _, tt := range []struct {
+			ext  string
+			opts []ContentKeyOption
+		}{
+			{"", nil},
+			{".jpg", nil},
+			{"tar.gz", []ContentKeyOption{WithSize()}},
+			{"png", []ContentKeyOption{WithFanOut(0), WithSize()}},
+			{"", []ContentKeyOption{WithFanOut(maxContentKeyDepth), WithKeyPrefix("a/b")}},
+			{".txt", []ContentKeyOption{WithKeyPrefix("data/sha256"), WithFanOut(3), WithSize()}}, // 前缀中包含算法名
+		} {
+			key := ContentKey(data, tt.ext, tt.opts...)
+			info, err := ParseContentKey(key)
+			if err != nil {
+				t.Errorf("ParseContentKey(%q) error: %v", key, err)
+				continue
+			}
+			wantSize := int64(-1)
+			if strings.Contains(key, "-") {
+				wantSize = int64(len(data))
+			}
+			if info.Hash != sum || info.Size != wantSize || info.Ext != normalizeExt(tt.ext) {
+				t.Errorf("ParseContentKey(%q) = %+v, want hash %s size %d ext %q", key, info, sum, wantSize, normalizeExt(tt.ext))
+			}
+		}
+	}
+}
+
+func TestParseContentKeyInvalid(t *testing.T) {
+	sum := sha256Hex([]byte("hello"))
+	for _, key := range []string{
+		"",
+		sum,
+		"sha256",
+		"sha256/",
+		"md5/" + sum,
+		"sha256/" + sum[:63],
+		"sha256/" + sum + "0",
+		"sha256/" + strings.ToUpper(sum),
+		"sha256/" + strings.Replace(sum, sum[:1], "g", 1),
+		"sha256/" + sum + "-",
+		"sha256/" + sum + "-x.jpg",
+		"sha256/" + sum + "--1",
+		"sha256/" + sum + "-1-2",
+		"sha256/zz/" + sum,
+		"sha256/" + sum[2:4] + "/" + sum[:2] + "/" + sum, // 分层目录顺序错误
+		"sha256/" + sum[:2] + "/" + sum[2:4] + "/" + sum + "/extra",
+		"sha256/" + strings.Repeat(sum[:2]+"/", maxContentKeyDepth+1) + sum,
+	} {
+		if info, err := ParseContentKey(key); !errors.Is(err, ErrInvalidContentKey) {
+			t.Errorf("ParseContentKey(%q) = %+v, %v, want ErrInvalidContentKey", key, info, err)
+		}
+	}
+}
@@ -31,13 +31,15 @@ func SHA256(input string) (string, error) {
 // BucketKey 使用 xxhash 生成一致性分桶 key。
 // 格式："{prefix}_{xxhash(value) % buckets}"。
 // xxhash 比 MD5 快 5-10 倍，适合大量 key 的分桶场景。
+// 需要其他算法时使用 BucketKeyWith。
 func BucketKey(prefix, value string, buckets uint64) string {
 	n := xxhash.Sum64String(value)
 	return fmt.Sprintf("%s_%d", prefix, n%buckets)
 }
 
 // RandomString 基于纳秒时间戳的 xxhash 生成指定长度的随机十六进制字符串。
-// 注意：不适用于安全场景，仅用于生成唯一标识。
+// 注意：不适用于安全场景，且在紧密循环中调用可能重复；
+// 需要安全或唯一的标识请使用 SecureRandomString、RandomHex、NewUUIDv4 或 NewULID。
 func RandomString(length int) string {
 	hash := fmt.Sprintf("%x", xxhash.Sum64String(fmt.Sprintf("%d", time.Now().UnixNano())))
 	if len(hash) >= length {
@@ -0,0 +1,196 @@
+package hashutil
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/bits"
+	"sort"
+	"sync"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// 内置 Hasher 名称。
+const (
+	HasherXXHash  = "xxhash"
+	HasherFNV1a   = "fnv1a"
+	HasherMurmur3 = "murmur3"
+	HasherSHA256  = "sha256"
+)
+
+// ErrUnknownHasher 表示按名称找不到对应的 Hasher。
+var ErrUnknownHasher = errors.New("hashutil: unknown hasher")
+
+// Hasher 是 64 位非加密摘要的统一接口，用于分桶、去重等场景按需切换算法。
+type Hasher interface {
+	// Name 返回算法名称，即 GetHasher 使用的注册名。
+	Name() string
+	// Sum64 计算 data 的 64 位摘要。
+	Sum64(data []byte) uint64
+	// Sum64String 计算字符串的 64 位摘要，结果与 Sum64([]byte(s)) 一致。
+	Sum64String(s string) uint64
+}
+
+var (
+	hashersMu sync.RWMutex
+	hashers   = map[string]Hasher{
+		HasherXXHash:  xxhashHasher{},
+		HasherFNV1a:   fnv1aHasher{},
+		HasherMurmur3: murmur3Hasher{},
+		HasherSHA256:  sha256Hasher{},
+	}
+)
+
+// GetHasher 按名称获取 Hasher，内置 xxhash、fnv1a、murmur3、sha256（截断为前 8 字节）。
+//
+// 用法：
+//
+//	h, err := hashutil.GetHasher(cfg.HashAlgo)
+//	key := hashutil.BucketKeyWith(h, "user", uid, 1024)
+func GetHasher(name string) (Hasher, error) {
+	hashersMu.RLock()
+	h, ok := hashers[name]
+	hashersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownHasher, name)
+	}
+	return h, nil
+}
+
+// RegisterHasher 注册自定义 Hasher，同名时覆盖已有实现。
+func RegisterHasher(h Hasher) {
+	hashersMu.Lock()
+	hashers[h.Name()] = h
+	hashersMu.Unlock()
+}
+
+// HasherNames 返回已注册的 Hasher 名称（按字典序）。
+func HasherNames() []string {
+	hashersMu.RLock()
+	names := make([]string, 0, len(hashers))
+	for name := range hashers {
+		names = append(names, name)
+	}
+	hashersMu.RUnlock()
+	sort.Strings(names)
+	return names
+}
+
+// BucketKeyWith 与 BucketKey 相同，但使用指定的 Hasher 计算分桶。
+// 格式："{prefix}_{h(value) % buckets}"。
+func BucketKeyWith(h Hasher, prefix, value string, buckets uint64) string {
+	return fmt.Sprintf("%s_%d", prefix, h.Sum64String(value)%buckets)
+}
+
+// ---------------------------------------------------------------------------
+// 内置实现
+// ---------------------------------------------------------------------------
+
+type xxhashHasher struct{}
+
+func (xxhashHasher) Name() string                { return HasherXXHash }
+func (xxhashHasher) Sum64(data []byte) uint64    { return xxhash.Sum64(data) }
+func (xxhashHasher) Sum64String(s string) uint64 { return xxhash.Sum64String(s) }
+
+type fnv1aHasher struct{}
+
+func (fnv1aHasher) Name() string { return HasherFNV1a }
+
+func (fnv1aHasher) Sum64(data []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(data)
+	return h.Sum64()
+}
+
+func (h fnv1aHasher) Sum64String(s string) uint64 { return h.Sum64([]byte(s)) }
+
+type sha256Hasher struct{}
+
+func (sha256Hasher) Name() string { return HasherSHA256 }
+
+func (sha256Hasher) Sum64(data []byte) uint64 {
+	sum := sha256.Sum256(data)
+	return binary.BigEndian.Uint64(sum[:8])
+}
+
+func (h sha256Hasher) Sum64String(s string) uint64 { return h.Sum64([]byte(s)) }
+
+// murmur3Hasher 实现 MurmurHash3 x64_128（seed 0），取前 64 位 h1。
+type murmur3Hasher struct{}
+
+func (murmur3Hasher) Name() string { return HasherMurmur3 }
+
+func (h murmur3Hasher) Sum64String(s string) uint64 { return h.Sum64([]byte(s)) }
+
+func (murmur3Hasher) Sum64(data []byte) uint64 {
+	const (
+		c1 = 0x87c37b91114253d5
+		c2 = 0x4cf5ad432745937f
+	)
+	var h1, h2 uint64
+	n := len(data)
+
+	nblocks := n / 16
+	for i := 0; i < nblocks; i++ {
+		k1 := binary.LittleEndian.Uint64(data[i*16:])
+		k2 := binary.LittleEndian.Uint64(data[i*16+8:])
+
+		k1 *= c1
+		k1 = bits.RotateLeft64(k1, 31)
+		k1 *= c2
+		h1 ^= k1
+		h1 = bits.RotateLeft64(h1, 27)
+		h1 += h2
+		h1 = h1*5 + 0x52dce729
+
+		k2 *= c2
+		k2 = bits.RotateLeft64(k2, 33)
+		k2 *= c1
+		h2 ^= k2
+		h2 = bits.RotateLeft64(h2, 31)
+		h2 += h1
+		h2 = h2*5 + 0x38495ab5
+	}
+
+	tail := data[nblocks*16:]
+	var k1, k2 uint64
+	for i := len(tail) - 1; i >= 8; i-- {
+		k2 ^= uint64(tail[i]) << (uint(i-8) * 8)
+	}
+	if len(tail) > 8 {
+		k2 *= c2
+		k2 = bits.RotateLeft64(k2, 33)
+		k2 *= c1
+		h2 ^= k2
+	}
+	for i := min(len(tail), 8) - 1; i >= 0; i-- {
+		k1 ^= uint64(tail[i]) << (uint(i) * 8)
+	}
+	if len(tail) > 0 {
+		k1 *= c1
+		k1 = bits.RotateLeft64(k1, 31)
+		k1 *= c2
+		h1 ^= k1
+	}
+
+	h1 ^= uint64(n)
+	h2 ^= uint64(n)
+	h1 += h2
+	h2 += h1
+	h1 = fmix64(h1)
+	h2 = fmix64(h2)
+	h1 += h2
+	return h1
+}
+
+func fmix64(k uint64) uint64 {
+	k ^= k >> 33
+	k *= 0xff51afd7ed558ccd
+	k ^= k >> 33
+	k *= 0xc4ceb9fe1a85ec53
+	k ^= k >> 33
+	return k
+}
@@ -0,0 +1,75 @@
+package hashutil
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// HMACSHA256 计算 msg 的 HMAC-SHA256，返回原始字节。
+func HMACSHA256(key, msg []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(msg)
+	return mac.Sum(nil)
+}
+
+// HMACSHA256Hex 计算 msg 的 HMAC-SHA256，返回小写十六进制字符串。
+func HMACSHA256Hex(key, msg []byte) string {
+	return hex.EncodeToString(HMACSHA256(key, msg))
+}
+
+// VerifyHMAC 以常量时间校验十六进制签名 sig 是否为 msg 的 HMAC-SHA256。
+// sig 不区分大小写；非法的十六进制直接返回 false。
+//
+// 用法：
+//
+//	if !hashutil.VerifyHMAC(secret, body, r.Header.Get("X-Signature")) {
+//	    http.Error(w, "invalid signature", http.StatusUnauthorized)
+//	}
+func VerifyHMAC(key, msg []byte, sig string) bool {
+	got, err := hex.DecodeString(strings.TrimSpace(sig))
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(got, HMACSHA256(key, msg))
+}
+
+// CanonicalQuery 按 key 字典序拼接参数为 k1=v1&k2=v2 形式，key 与 value 均做 URL 编码。
+// 这是 SignQuery 的待签名串，单独暴露便于与对端排查签名不一致。
+func CanonicalQuery(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			sb.WriteByte('&')
+		}
+		sb.WriteString(url.QueryEscape(k))
+		sb.WriteByte('=')
+		sb.WriteString(url.QueryEscape(params[k]))
+	}
+	return sb.String()
+}
+
+// SignQuery 对请求参数签名：先按 CanonicalQuery 规则拼接，再计算 HMAC-SHA256 十六进制。
+// 签名字段本身（如 sign）应在调用前从 params 中剔除。
+//
+// 用法：
+//
+//	params := map[string]string{"appid": "x", "ts": "1700000000", "nonce": "abc"}
+//	params["sign"] = hashutil.SignQuery(params, secret)
+func SignQuery(params map[string]string, secret string) string {
+	return HMACSHA256Hex([]byte(secret), []byte(CanonicalQuery(params)))
+}
+
+// VerifyQuery 以常量时间校验参数签名，规则与 SignQuery 一致。
+func VerifyQuery(params map[string]string, secret, sig string) bool {
+	return VerifyHMAC([]byte(secret), []byte(CanonicalQuery(params)), sig)
+}
@@ -0,0 +1,90 @@
+package hashutil
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// alphanumeric 为 SecureRandomString 使用的字符集。
+const alphanumeric = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// crockford 为 ULID 使用的 Crockford Base32 字符集（不含 I、L、O、U）。
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// SecureRandomString 基于 crypto/rand 生成长度为 n 的随机字母数字串（[0-9A-Za-z]），
+// 各字符均匀分布，可用于 token、邀请码等安全场景。n <= 0 时返回空串。
+func SecureRandomString(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	limit := big.NewInt(int64(len(alphanumeric)))
+	b := make([]byte, n)
+	for i := range b {
+		idx, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			// crypto/rand 在受支持的平台上不会失败
+			panic(fmt.Sprintf("hashutil: crypto/rand: %v", err))
+		}
+		b[i] = alphanumeric[idx.Int64()]
+	}
+	return string(b)
+}
+
+// RandomHex 基于 crypto/rand 生成长度为 n 的随机小写十六进制字符串。n <= 0 时返回空串。
+func RandomHex(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	b := make([]byte, (n+1)/2)
+	rand.Read(b)
+	return hex.EncodeToString(b)[:n]
+}
+
+// NewUUIDv4 生成 RFC 4122 第 4 版随机 UUID，格式 xxxxxxxx-xxxx-4xxx-yxxx-xxxxxxxxxxxx。
+func NewUUIDv4() string {
+	var u [16]byte
+	rand.Read(u[:])
+	u[6] = (u[6] & 0x0f) | 0x40 // version 4
+	u[8] = (u[8] & 0x3f) | 0x80 // variant RFC 4122
+
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
+
+// NewULID 生成 26 位 ULID（48 位毫秒时间戳 + 80 位随机数，Crockford Base32 编码）。
+// ULID 按字典序大致对应生成时间，适合作为数据库主键或对象存储 key。
+// 注意：同一毫秒内生成的多个 ULID 之间不保证有序。
+func NewULID() string {
+	var u [16]byte
+	ms := uint64(time.Now().UnixMilli())
+	for i := 5; i >= 0; i-- {
+		u[i] = byte(ms)
+		ms >>= 8
+	}
+	rand.Read(u[6:])
+
+	// 128 位按 5 位一组编码为 26 个字符，首字符仅占 3 位
+	var out [26]byte
+	hi := uint64(u[0])<<56 | uint64(u[1])<<48 | uint64(u[2])<<40 | uint64(u[3])<<32 |
+		uint64(u[4])<<24 | uint64(u[5])<<16 | uint64(u[6])<<8 | uint64(u[7])
+	lo := uint64(u[8])<<56 | uint64(u[9])<<48 | uint64(u[10])<<40 | uint64(u[11])<<32 |
+		uint64(u[12])<<24 | uint64(u[13])<<16 | uint64(u[14])<<8 | uint64(u[15])
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
@@ -0,0 +1,66 @@
+package hashutil
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// hashReader 流式计算 r 的摘要并返回十六进制字符串。
+func hashReader(h hash.Hash, r io.Reader, name string) (string, error) {
+	if _, err := io.Copy(h, r); err != nil {
+		return "", fmt.Errorf("hashutil: %s read: %w", name, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashFile 流式计算文件的摘要。
+func hashFile(h hash.Hash, path, name string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("hashutil: open %s: %w", path, err)
+	}
+	defer f.Close()
+	return hashReader(h, f, name)
+}
+
+// MD5Reader 流式计算 r 中全部数据的 MD5 十六进制摘要，不会把数据整体读入内存。
+func MD5Reader(r io.Reader) (string, error) {
+	return hashReader(md5.New(), r, "md5")
+}
+
+// SHA256Reader 流式计算 r 中全部数据的 SHA-256 十六进制摘要。
+func SHA256Reader(r io.Reader) (string, error) {
+	return hashReader(sha256.New(), r, "sha256")
+}
+
+// XXHashReader 流式计算 r 中全部数据的 xxhash64 十六进制摘要（16 位，大端序）。
+// 比 MD5 / SHA-256 快得多，适合非安全场景的大文件校验。
+func XXHashReader(r io.Reader) (string, error) {
+	return hashReader(xxhash.New(), r, "xxhash")
+}
+
+// MD5File 流式计算文件的 MD5 十六进制摘要，适合 GB 级文件。
+//
+// 用法：
+//
+//	sum, err := hashutil.MD5File("/data/dump.tar.gz")
+func MD5File(path string) (string, error) {
+	return hashFile(md5.New(), path, "md5")
+}
+
+// SHA256File 流式计算文件的 SHA-256 十六进制摘要。
+func SHA256File(path string) (string, error) {
+	return hashFile(sha256.New(), path, "sha256")
+}
+
+// XXHashFile 流式计算文件的 xxhash64 十六进制摘要。
+func XXHashFile(path string) (string, error) {
+	return hashFile(xxhash.New(), path, "xxhash")
+}
@@ -0,0 +1,45 @@
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// ReadinessHandler 返回就绪探针的 http.Handler：执行 CheckReadiness，状态为 down 时返回 503，
+// 否则（up / degraded）返回 200；响应体为 Report 的 JSON，包含每个依赖的状态与耗时。
+//
+// 用法：
+//
+//	mux.Handle("/readyz", h.ReadinessHandler())
+//
+//	// curl http://pod-ip:8080/readyz
+//	// {"status": "up", "checks": [{"name": "redis", "status": "up", "latency_ms": 0.8, ...}, ...]}
+func (h *Health) ReadinessHandler() http.Handler {
+	return reportHandler(h.CheckReadiness)
+}
+
+// LivenessHandler 返回存活探针的 http.Handler：执行 CheckLiveness，规则同 ReadinessHandler。
+func (h *Health) LivenessHandler() http.Handler {
+	return reportHandler(h.CheckLiveness)
+}
+
+// reportHandler 执行 check 并以 JSON 输出结果。
+func reportHandler(check func(ctx context.Context) *Report) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := check(r.Context())
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		if report.Status == StatusDown {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		} else {
+			w.WriteHeader(http.StatusOK)
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			log.Debugf("healthcheck: 输出检查结果失败: %v", err)
+		}
+	})
+}
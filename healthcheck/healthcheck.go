@@ -0,0 +1,299 @@
+// Package healthcheck 组合多个依赖（Redis / PostgreSQL / OBS 等）的健康检查，
+// 提供 Kubernetes 就绪（readiness）/ 存活（liveness）探针使用的 http.Handler。
+//
+// db.RedisClient、db.PostgresClient、obsutil.ObsClient 均实现了 Checker，可直接注册。
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pylemonorg/gotools/logger"
+)
+
+// log healthcheck 模块日志，可通过 logger.SetModuleLevel("healthcheck", ...) 单独控制级别。
+var log = logger.Module("healthcheck")
+
+// 默认配置。
+const (
+	defaultTimeout = 3 * time.Second
+)
+
+// Checker 健康检查接口，返回 nil 表示健康。实现应遵守 ctx 的超时。
+type Checker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// CheckerFunc 将普通函数适配为 Checker。
+type CheckerFunc func(ctx context.Context) error
+
+// HealthCheck 调用 f(ctx)。
+func (f CheckerFunc) HealthCheck(ctx context.Context) error { return f(ctx) }
+
+// Status 健康状态。
+type Status string
+
+const (
+	StatusUp       Status = "up"       // 全部检查通过
+	StatusDegraded Status = "degraded" // 仅非关键检查失败，仍视为就绪
+	StatusDown     Status = "down"     // 有关键检查失败或正在关闭
+)
+
+// CheckResult 单个依赖的检查结果。
+type CheckResult struct {
+	Name      string        `json:"name"`
+	Status    Status        `json:"status"` // up / down
+	Critical  bool          `json:"critical"`
+	Latency   time.Duration `json:"-"`
+	LatencyMs float64       `json:"latency_ms"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// Report 一次检查的汇总结果。
+type Report struct {
+	Status    Status        `json:"status"`
+	CheckedAt time.Time     `json:"checked_at"`
+	Checks    []CheckResult `json:"checks"`
+}
+
+// Option Health 的配置项。
+type Option func(*Health)
+
+// WithTimeout 设置每个检查的默认超时，默认 3s；可通过 Timeout 为单个检查单独设置。
+func WithTimeout(d time.Duration) Option {
+	return func(h *Health) { h.timeout = d }
+}
+
+// WithCacheTTL 设置就绪检查结果的缓存时长，ttl 内的重复请求直接返回上次结果，
+// 避免多个副本、高频探针频繁访问依赖。默认 0（不缓存）。
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(h *Health) { h.cacheTTL = ttl }
+}
+
+// CheckOption 单个检查的配置项。
+type CheckOption func(*check)
+
+// Timeout 设置该检查的超时，覆盖 WithTimeout。
+func Timeout(d time.Duration) CheckOption {
+	return func(c *check) { c.timeout = d }
+}
+
+// NonCritical 标记为非关键依赖：失败时整体状态为 degraded，就绪探针仍返回 200。
+// 适用于有降级方案的依赖（如缓存）。
+func NonCritical() CheckOption {
+	return func(c *check) { c.critical = false }
+}
+
+// Liveness 同时用于存活检查。存活检查失败会导致容器被重启，只应包含进程自身的问题
+// （如死锁检测、关键 goroutine 退出），不应包含外部依赖。
+func Liveness() CheckOption {
+	return func(c *check) { c.liveness = true }
+}
+
+// check 注册的检查项。
+type check struct {
+	name     string
+	checker  Checker
+	timeout  time.Duration
+	critical bool
+	liveness bool
+
+	lastStatus Status // 上次的状态，用于记录状态变化日志
+}
+
+// Health 健康检查集合，并发安全。
+//
+// 用法：
+//
+//	h := healthcheck.New(healthcheck.WithTimeout(2 * time.Second))
+//	h.Add("redis", redisClient)
+//	h.Add("postgres", pgClient)
+//	h.Add("obs", obsClient, healthcheck.Timeout(5*time.Second), healthcheck.NonCritical())
+//
+//	mux.Handle("/healthz", h.LivenessHandler())
+//	mux.Handle("/readyz", h.ReadinessHandler())
+//
+//	// 收到退出信号后先让就绪探针失败，负载均衡摘除流量后再关闭连接
+//	g.AddFunc("healthcheck", h.MarkShuttingDown)
+type Health struct {
+	timeout  time.Duration
+	cacheTTL time.Duration
+
+	mu     sync.Mutex
+	checks []*check
+
+	shuttingDown atomic.Bool
+
+	cacheMu  sync.Mutex
+	cached   *Report
+	cachedAt time.Time
+}
+
+// New 创建 Health。
+func New(opts ...Option) *Health {
+	h := &Health{timeout: defaultTimeout}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(h)
+		}
+	}
+	if h.timeout <= 0 {
+		h.timeout = defaultTimeout
+	}
+	return h
+}
+
+// Add 注册一个检查，默认为关键依赖、仅用于就绪检查。name 重复时替换原有检查。
+func (h *Health) Add(name string, checker Checker, opts ...CheckOption) {
+	c := &check{name: name, checker: checker, timeout: h.timeout, critical: true}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(c)
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i, old := range h.checks {
+		if old.name == name {
+			h.checks[i] = c
+			return
+		}
+	}
+	h.checks = append(h.checks, c)
+}
+
+// AddFunc 注册一个函数形式的检查，等同于 Add(name, CheckerFunc(fn), opts...)。
+func (h *Health) AddFunc(name string, fn func(ctx context.Context) error, opts ...CheckOption) {
+	h.Add(name, CheckerFunc(fn), opts...)
+}
+
+// Remove 移除检查。
+func (h *Health) Remove(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i, c := range h.checks {
+		if c.name == name {
+			h.checks = append(h.checks[:i], h.checks[i+1:]...)
+			return
+		}
+	}
+}
+
+// MarkShuttingDown 标记应用正在关闭，此后就绪检查直接返回 down（不再执行检查），存活检查不受影响。
+func (h *Health) MarkShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+// CheckReadiness 并发执行全部检查并汇总：有关键检查失败或正在关闭时为 down，仅非关键检查失败时为 degraded。
+// 设置了 WithCacheTTL 时，ttl 内返回缓存的结果。
+func (h *Health) CheckReadiness(ctx context.Context) *Report {
+	if h.shuttingDown.Load() {
+		return &Report{Status: StatusDown, CheckedAt: time.Now(), Checks: []CheckResult{
+			{Name: "shutdown", Status: StatusDown, Critical: true, Error: "应用正在关闭"},
+		}}
+	}
+	if h.cacheTTL <= 0 {
+		return h.run(ctx, false)
+	}
+
+	h.cacheMu.Lock()
+	defer h.cacheMu.Unlock()
+	if h.cached != nil && time.Since(h.cachedAt) < h.cacheTTL {
+		return h.cached
+	}
+	h.cached = h.run(ctx, false)
+	h.cachedAt = time.Now()
+	return h.cached
+}
+
+// CheckLiveness 并发执行标记了 Liveness 的检查并汇总；没有此类检查时始终为 up。
+func (h *Health) CheckLiveness(ctx context.Context) *Report {
+	return h.run(ctx, true)
+}
+
+// run 并发执行检查，livenessOnly 为 true 时只执行存活检查。
+func (h *Health) run(ctx context.Context, livenessOnly bool) *Report {
+	h.mu.Lock()
+	var checks []*check
+	for _, c := range h.checks {
+		if !livenessOnly || c.liveness {
+			checks = append(checks, c)
+		}
+	}
+	h.mu.Unlock()
+
+	report := &Report{Status: StatusUp, CheckedAt: time.Now(), Checks: make([]CheckResult, len(checks))}
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Checks[i] = runCheck(ctx, c)
+		}()
+	}
+	wg.Wait()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i, c := range checks {
+		r := report.Checks[i]
+		if r.Status == StatusDown {
+			if c.critical {
+				report.Status = StatusDown
+			} else if report.Status == StatusUp {
+				report.Status = StatusDegraded
+			}
+		}
+		if c.lastStatus != "" && c.lastStatus != r.Status {
+			if r.Status == StatusDown {
+				log.Warnf("healthcheck: [%s] 变为不可用: %s", c.name, r.Error)
+			} else {
+				log.Infof("healthcheck: [%s] 已恢复 (%v)", c.name, r.Latency.Round(time.Millisecond))
+			}
+		}
+		c.lastStatus = r.Status
+	}
+	return report
+}
+
+// runCheck 在超时内执行单个检查；检查未遵守 ctx 时超时后直接返回失败（检查本身在后台继续执行完），panic 视为失败。
+func runCheck(parent context.Context, c *check) CheckResult {
+	ctx, cancel := context.WithTimeout(parent, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				errCh <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		errCh <- c.checker.HealthCheck(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = fmt.Errorf("超时（%v）: %w", c.timeout, ctx.Err())
+	}
+
+	latency := time.Since(start)
+	r := CheckResult{
+		Name:      c.name,
+		Status:    StatusUp,
+		Critical:  c.critical,
+		Latency:   latency,
+		LatencyMs: float64(latency.Microseconds()) / 1000,
+	}
+	if err != nil {
+		r.Status = StatusDown
+		r.Error = err.Error()
+	}
+	return r
+}
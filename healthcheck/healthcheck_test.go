@@ -0,0 +1,156 @@
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pylemonorg/gotools/db"
+	"github.com/pylemonorg/gotools/obsutil"
+)
+
+// 客户端可直接注册为检查项。
+var (
+	_ Checker = (*db.RedisClient)(nil)
+	_ Checker = (*db.PostgresClient)(nil)
+	_ Checker = (*obsutil.ObsClient)(nil)
+)
+
+func ok(context.Context) error { return nil }
+
+func fail(context.Context) error { return errors.New("connection refused") }
+
+func TestCheckReadiness(t *testing.T) {
+	tests := []struct {
+		name   string
+		setup  func(h *Health)
+		status Status
+	}{
+		{"全部通过", func(h *Health) {
+			h.AddFunc("redis", ok)
+			h.AddFunc("postgres", ok)
+		}, StatusUp},
+		{"非关键失败", func(h *Health) {
+			h.AddFunc("redis", ok)
+			h.AddFunc("cache", fail, NonCritical())
+		}, StatusDegraded},
+		{"关键失败", func(h *Health) {
+			h.AddFunc("postgres", fail)
+			h.AddFunc("cache", fail, NonCritical())
+		}, StatusDown},
+		{"无检查", func(*Health) {}, StatusUp},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := New()
+			tt.setup(h)
+			if got := h.CheckReadiness(context.Background()); got.Status != tt.status {
+				t.Errorf("Status = %s, 期望 %s (%+v)", got.Status, tt.status, got.Checks)
+			}
+		})
+	}
+}
+
+func TestCheckResultOrderAndError(t *testing.T) {
+	h := New()
+	h.AddFunc("a", ok)
+	h.AddFunc("b", fail)
+	h.AddFunc("a", fail) // 同名替换
+
+	r := h.CheckReadiness(context.Background())
+	if len(r.Checks) != 2 || r.Checks[0].Name != "a" || r.Checks[1].Name != "b" {
+		t.Fatalf("Checks = %+v, 期望按注册顺序 a、b", r.Checks)
+	}
+	if r.Checks[0].Status != StatusDown || r.Checks[1].Error != "connection refused" {
+		t.Errorf("Checks = %+v", r.Checks)
+	}
+
+	h.Remove("a")
+	if r := h.CheckReadiness(context.Background()); len(r.Checks) != 1 {
+		t.Errorf("Remove 后 Checks = %+v", r.Checks)
+	}
+}
+
+func TestCheckTimeoutAndPanic(t *testing.T) {
+	h := New(WithTimeout(20 * time.Millisecond))
+	// 不遵守 ctx 的检查也会按超时返回
+	h.AddFunc("slow", func(context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+	h.AddFunc("panic", func(context.Context) error { panic("boom") })
+
+	start := time.Now()
+	r := h.CheckReadiness(context.Background())
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("耗时 %v, 期望按超时返回", elapsed)
+	}
+	for _, c := range r.Checks {
+		if c.Status != StatusDown || c.Error == "" {
+			t.Errorf("%s = %+v, 期望失败", c.Name, c)
+		}
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	var calls atomic.Int32
+	h := New(WithCacheTTL(time.Hour))
+	h.AddFunc("redis", func(context.Context) error {
+		calls.Add(1)
+		return nil
+	})
+	h.CheckReadiness(context.Background())
+	h.CheckReadiness(context.Background())
+	if n := calls.Load(); n != 1 {
+		t.Errorf("检查执行 %d 次, 期望缓存后 1 次", n)
+	}
+}
+
+func TestLivenessAndShutdown(t *testing.T) {
+	h := New()
+	h.AddFunc("postgres", fail)
+	h.AddFunc("worker", ok, Liveness())
+
+	live := h.CheckLiveness(context.Background())
+	if live.Status != StatusUp || len(live.Checks) != 1 || live.Checks[0].Name != "worker" {
+		t.Errorf("Liveness = %+v, 期望只包含 worker", live)
+	}
+
+	h.MarkShuttingDown()
+	if r := h.CheckReadiness(context.Background()); r.Status != StatusDown || r.Checks[0].Name != "shutdown" {
+		t.Errorf("关闭中 Readiness = %+v", r)
+	}
+	if r := h.CheckLiveness(context.Background()); r.Status != StatusUp {
+		t.Errorf("关闭中 Liveness = %+v, 期望不受影响", r)
+	}
+}
+
+func TestHandlers(t *testing.T) {
+	h := New()
+	h.AddFunc("redis", ok)
+	h.AddFunc("postgres", fail)
+
+	rec := httptest.NewRecorder()
+	h.ReadinessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("readyz 状态码 = %d, 期望 503", rec.Code)
+	}
+	var report Report
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Status != StatusDown || len(report.Checks) != 2 || report.Checks[1].Error == "" {
+		t.Errorf("report = %+v", report)
+	}
+
+	rec = httptest.NewRecorder()
+	h.LivenessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("healthz 状态码 = %d, 期望 200", rec.Code)
+	}
+}
@@ -0,0 +1,210 @@
+// Package redistest 提供测试用的内存 RESP 服务端：只响应测试注册的命令，无需真实 Redis。
+//
+// 用法：
+//
+//	srv := redistest.NewServer(t)
+//	srv.Handle("GET", func(args []string) any { return nil }) // 总是未命中
+//	rc := srv.Client(t)
+package redistest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pylemonorg/gotools/db"
+)
+
+// Handler 处理一条命令，args[0] 为命令名。返回值按类型编码为 RESP 回复：
+// nil 为空回复，string 为 bulk string，Status 为 simple string，Error 为错误，
+// int / int64 为整数，[]string / []any 为数组。
+type Handler func(args []string) any
+
+// Status simple string 回复，如 OK、PONG。
+type Status string
+
+// Error 错误回复，内容需以错误类型开头，如 "ERR unknown command"。
+type Error string
+
+// OK 常用的成功回复。
+const OK = Status("OK")
+
+// Server 测试用 Redis 服务端，所有命令串行执行，Handler 内无需再加锁。
+type Server struct {
+	ln       net.Listener
+	mu       sync.Mutex
+	handlers map[string]Handler
+}
+
+// NewServer 启动服务端，测试结束时关闭。默认响应 PING、CLIENT、SELECT，
+// HELLO 返回错误使客户端回退到 RESP2。
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("redistest: 监听失败: %v", err)
+	}
+	s := &Server{ln: ln, handlers: make(map[string]Handler)}
+	s.Handle("HELLO", func([]string) any { return Error("ERR unknown command 'HELLO'") })
+	s.Handle("PING", func([]string) any { return Status("PONG") })
+	s.Handle("CLIENT", func([]string) any { return OK })
+	s.Handle("SELECT", func([]string) any { return OK })
+
+	var wg sync.WaitGroup
+	var connMu sync.Mutex
+	conns := make(map[net.Conn]struct{})
+	t.Cleanup(func() {
+		ln.Close()
+		connMu.Lock()
+		for c := range conns {
+			c.Close()
+		}
+		connMu.Unlock()
+		wg.Wait()
+	})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			connMu.Lock()
+			conns[conn] = struct{}{}
+			connMu.Unlock()
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.serve(conn)
+				connMu.Lock()
+				delete(conns, conn)
+				connMu.Unlock()
+			}()
+		}
+	}()
+	return s
+}
+
+// Handle 注册命令（不区分大小写）的处理函数，重复注册时覆盖。
+func (s *Server) Handle(cmd string, h Handler) {
+	s.mu.Lock()
+	s.handlers[strings.ToUpper(cmd)] = h
+	s.mu.Unlock()
+}
+
+// Params 返回连接该服务端的参数。
+func (s *Server) Params() *db.RedisParams {
+	return &db.RedisParams{Host: "127.0.0.1", Port: s.ln.Addr().(*net.TCPAddr).Port}
+}
+
+// Client 创建连接该服务端的 RedisClient，测试结束时关闭。
+func (s *Server) Client(t testing.TB) *db.RedisClient {
+	t.Helper()
+	rc, err := db.NewRedisClient(s.Params())
+	if err != nil {
+		t.Fatalf("redistest: 连接失败: %v", err)
+	}
+	t.Cleanup(func() { rc.Close() })
+	return rc
+}
+
+// serve 处理一个连接上的命令，直到连接关闭或出错。
+func (s *Server) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		h, ok := s.handlers[strings.ToUpper(args[0])]
+		var reply any = Error(fmt.Sprintf("ERR unknown command '%s'", args[0]))
+		if ok {
+			reply = h(args)
+		}
+		s.mu.Unlock()
+
+		writeReply(w, reply)
+		// 客户端可能流水线发送多条命令，读完缓冲中的命令再一起写出
+		if r.Buffered() == 0 {
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readCommand 读取一条 RESP 数组形式的命令。
+func readCommand(r *bufio.Reader) ([]string, error) {
+	n, err := readLength(r, '*')
+	if err != nil {
+		return nil, err
+	}
+	if n <= 0 {
+		return nil, fmt.Errorf("redistest: 空命令")
+	}
+	args := make([]string, n)
+	for i := range args {
+		size, err := readLength(r, '$')
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+// readLength 读取形如 "*3\r\n" / "$5\r\n" 的长度行。
+func readLength(r *bufio.Reader, prefix byte) (int, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return 0, err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if len(line) == 0 || line[0] != prefix {
+		return 0, fmt.Errorf("redistest: 无法解析 %q", line)
+	}
+	return strconv.Atoi(line[1:])
+}
+
+// writeReply 按类型编码回复。
+func writeReply(w *bufio.Writer, v any) {
+	switch v := v.(type) {
+	case nil:
+		w.WriteString("$-1\r\n")
+	case string:
+		fmt.Fprintf(w, "$%d\r\n%s\r\n", len(v), v)
+	case Status:
+		fmt.Fprintf(w, "+%s\r\n", v)
+	case Error:
+		fmt.Fprintf(w, "-%s\r\n", v)
+	case int:
+		fmt.Fprintf(w, ":%d\r\n", v)
+	case int64:
+		fmt.Fprintf(w, ":%d\r\n", v)
+	case []string:
+		fmt.Fprintf(w, "*%d\r\n", len(v))
+		for _, s := range v {
+			writeReply(w, s)
+		}
+	case []any:
+		fmt.Fprintf(w, "*%d\r\n", len(v))
+		for _, e := range v {
+			writeReply(w, e)
+		}
+	default:
+		panic(fmt.Sprintf("redistest: 不支持的回复类型 %T", v))
+	}
+}
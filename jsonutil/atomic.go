@@ -0,0 +1,152 @@
+package jsonutil
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// WriteOptions WriteFileAtomic 选项。
+type WriteOptions struct {
+	Backup bool        // 覆盖前将旧文件保存为 path.bak
+	Sync   bool        // 重命名前 fsync 文件，重命名后 fsync 目录（掉电也不丢数据，但更慢）
+	Perm   fs.FileMode // 文件权限，为 0 时沿用已有文件的权限，新文件为 0644
+
+	MarshalOptions // 序列化选项，Indent 为空时使用两个空格缩进
+}
+
+// fileLocks 按路径串行化同一进程内对同一文件的写入。
+var fileLocks sync.Map // map[string]*sync.Mutex
+
+// lockPath 获取 path 的写锁，返回解锁函数。
+func lockPath(path string) func() {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	v, _ := fileLocks.LoadOrStore(path, &sync.Mutex{})
+	mu := v.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
+// WriteFileAtomic 将任意值序列化为带缩进的 JSON 并原子地写入文件：
+// 先写入同目录下的临时文件，再重命名覆盖目标文件，写入过程中崩溃不会留下半截文件。
+// 同一进程内对同一路径的并发调用会串行执行。opts 为 nil 时不备份、不 fsync。
+//
+// 用法：
+//
+//	err := jsonutil.WriteFileAtomic("config.json", cfg, &jsonutil.WriteOptions{Backup: true, Sync: true})
+func WriteFileAtomic(path string, v any, opts *WriteOptions) error {
+	if opts == nil {
+		opts = &WriteOptions{}
+	}
+	data, err := marshalFile(v, []MarshalOptions{opts.MarshalOptions})
+	if err != nil {
+		return log.ErrorfE("jsonutil: 序列化失败: %v", err)
+	}
+	data = append(data, '\n')
+
+	unlock := lockPath(path)
+	defer unlock()
+
+	perm := opts.Perm
+	old, statErr := os.Stat(path)
+	if perm == 0 {
+		perm = 0644
+		if statErr == nil {
+			perm = old.Mode().Perm()
+		}
+	}
+
+	if opts.Backup && statErr == nil {
+		prev, err := os.ReadFile(path)
+		if err != nil {
+			return log.ErrorfE("jsonutil: 读取旧文件 [%s] 失败: %v", path, err)
+		}
+		if err := writeAtomic(path+".bak", prev, perm, opts.Sync); err != nil {
+			return log.ErrorfE("jsonutil: 备份文件 [%s] 失败: %v", path, err)
+		}
+	}
+
+	if err := writeAtomic(path, data, perm, opts.Sync); err != nil {
+		return log.ErrorfE("jsonutil: 写入文件 [%s] 失败: %v", path, err)
+	}
+	return nil
+}
+
+// writeAtomic 写临时文件后重命名为 path。
+func writeAtomic(path string, data []byte, perm fs.FileMode, doSync bool) (err error) {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		return err
+	}
+	if err = tmp.Chmod(perm); err != nil {
+		return err
+	}
+	if doSync {
+		if err = tmp.Sync(); err != nil {
+			return err
+		}
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	if doSync {
+		syncDir(dir)
+	}
+	return nil
+}
+
+// syncDir fsync 目录以持久化重命名操作（部分平台不支持，忽略错误）。
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	d.Sync()
+	d.Close()
+}
+
+// ReadOrCreateFile 读取 JSON 配置文件到 v；文件不存在时将 v（调用方预先填好的默认值）原子写入文件。
+// 文件存在时按 JSON 覆盖 v 的字段，文件中缺失的字段保留默认值。返回是否新建了文件。
+//
+// 用法：
+//
+//	cfg := Config{Port: 8080, Workers: 4} // 默认值
+//	created, err := jsonutil.ReadOrCreateFile("config.json", &cfg)
+func ReadOrCreateFile(path string, v any) (created bool, err error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return false, log.ErrorfE("jsonutil: 创建目录失败: %v", err)
+		}
+		if err := WriteFileAtomic(path, v, nil); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	if err != nil {
+		return false, log.ErrorfE("jsonutil: 读取文件 [%s] 失败: %v", path, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, log.ErrorfE("jsonutil: 解析文件 [%s] 失败: %v", path, err)
+	}
+	return false, nil
+}
@@ -0,0 +1,107 @@
+package jsonutil
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// WriteFileAtomic / ReadOrCreateFile
+// ---------------------------------------------------------------------------
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+
+	if err := WriteFileAtomic(path, map[string]int{"v": 1}, nil); err != nil {
+		t.Fatalf("WriteFileAtomic: %v", err)
+	}
+	if err := WriteFileAtomic(path, map[string]int{"v": 2}, &WriteOptions{Backup: true, Sync: true}); err != nil {
+		t.Fatalf("WriteFileAtomic with backup: %v", err)
+	}
+
+	var cur, bak map[string]int
+	if err := ReadFile(path, &cur); err != nil || cur["v"] != 2 {
+		t.Errorf("current file = %v (err=%v), want v=2", cur, err)
+	}
+	if err := ReadFile(path+".bak", &bak); err != nil || bak["v"] != 1 {
+		t.Errorf("backup file = %v (err=%v), want v=1", bak, err)
+	}
+
+	// 不应残留临时文件
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		names := make([]string, 0, len(entries))
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Errorf("unexpected files in dir: %v", names)
+	}
+}
+
+func TestWriteFileAtomicKeepsPerm(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secret.json")
+	if err := WriteFileAtomic(path, 1, &WriteOptions{Perm: 0600}); err != nil {
+		t.Fatalf("WriteFileAtomic: %v", err)
+	}
+	if err := WriteFileAtomic(path, 2, nil); err != nil {
+		t.Fatalf("WriteFileAtomic: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("perm = %o, want 600", perm)
+	}
+}
+
+func TestWriteFileAtomicConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "concurrent.json")
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := WriteFileAtomic(path, map[string]int{"i": i}, &WriteOptions{Backup: true}); err != nil {
+				t.Errorf("WriteFileAtomic: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	var m map[string]int
+	if err := ReadFile(path, &m); err != nil {
+		t.Fatalf("file corrupted after concurrent writes: %v", err)
+	}
+}
+
+func TestReadOrCreateFile(t *testing.T) {
+	type Config struct {
+		Port    int    `json:"port"`
+		Workers int    `json:"workers"`
+		Name    string `json:"name"`
+	}
+	path := filepath.Join(t.TempDir(), "sub", "config.json")
+
+	cfg := Config{Port: 8080, Workers: 4}
+	created, err := ReadOrCreateFile(path, &cfg)
+	if err != nil || !created {
+		t.Fatalf("first ReadOrCreateFile: created=%v err=%v", created, err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"port":9090,"name":"svc"}`), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	cfg = Config{Port: 8080, Workers: 4}
+	created, err = ReadOrCreateFile(path, &cfg)
+	if err != nil || created {
+		t.Fatalf("second ReadOrCreateFile: created=%v err=%v", created, err)
+	}
+	want := Config{Port: 9090, Workers: 4, Name: "svc"}
+	if cfg != want {
+		t.Errorf("cfg = %+v, want %+v", cfg, want)
+	}
+}
@@ -0,0 +1,102 @@
+package jsonutil
+
+import (
+	"bytes"
+	"os"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// ---------------------------------------------------------------------------
+// MessagePack 二进制编解码
+// ---------------------------------------------------------------------------
+//
+// 与 JSON 系列函数用法一致，底层使用 MessagePack：体积更小、编解码更快，适合热点路径
+// （如大量采样数据写入 Redis）。结构体沿用 json 标签（含 omitempty、"-"），无需额外声明 msgpack 标签。
+// 整数按实际大小压缩编码，time.Time 使用 MessagePack 时间扩展类型，精度不丢失。
+
+// EncodeBinary 将任意值编码为 MessagePack 字节切片。
+//
+// 用法：
+//
+//	data, err := jsonutil.EncodeBinary(stats)
+//	_ = redisClient.Set(key, data, 0)
+func EncodeBinary(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	enc.UseCompactInts(true)
+	if err := enc.Encode(v); err != nil {
+		return nil, log.ErrorfE("jsonutil: 二进制编码失败: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// EncodeBinaryString 将任意值编码为 MessagePack，以 string 返回（内容为二进制，便于直接写入 Redis 等字符串接口）。
+func EncodeBinaryString(v any) (string, error) {
+	data, err := EncodeBinary(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// DecodeBinary 将 MessagePack 字节切片解码到目标对象。
+// 解码到 any / map[string]any 时，对象解码为 map[string]any。
+func DecodeBinary(data []byte, v any) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	if err := dec.Decode(v); err != nil {
+		return log.ErrorfE("jsonutil: 二进制解码失败: %v", err)
+	}
+	return nil
+}
+
+// DecodeBinaryString 将 EncodeBinaryString 的结果解码到目标对象。
+func DecodeBinaryString(s string, v any) error {
+	return DecodeBinary([]byte(s), v)
+}
+
+// DecodeBinaryAs 将 MessagePack 字节切片解码为 T 类型。
+//
+// 用法：
+//
+//	stats, err := jsonutil.DecodeBinaryAs[[]monitor.ResourceStats](data)
+func DecodeBinaryAs[T any](data []byte) (T, error) {
+	var v T
+	err := DecodeBinary(data, &v)
+	return v, err
+}
+
+// DecodeBinaryStringAs 将 EncodeBinaryString 的结果解码为 T 类型。
+func DecodeBinaryStringAs[T any](s string) (T, error) {
+	return DecodeBinaryAs[T]([]byte(s))
+}
+
+// ReadBinaryFile 读取 MessagePack 文件并解码到目标对象。
+func ReadBinaryFile(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return log.ErrorfE("jsonutil: 读取文件 [%s] 失败: %v", path, err)
+	}
+	return DecodeBinary(data, v)
+}
+
+// ReadBinaryFileAs 读取 MessagePack 文件并解码为 T 类型。
+func ReadBinaryFileAs[T any](path string) (T, error) {
+	var v T
+	err := ReadBinaryFile(path, &v)
+	return v, err
+}
+
+// WriteBinaryFile 将任意值编码为 MessagePack 并写入文件（权限 0644，已存在则覆盖）。
+func WriteBinaryFile(path string, v any) error {
+	data, err := EncodeBinary(v)
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(path, data, 0644); err != nil {
+		return log.ErrorfE("jsonutil: 写入文件 [%s] 失败: %v", path, err)
+	}
+	return nil
+}
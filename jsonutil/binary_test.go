@@ -0,0 +1,84 @@
+package jsonutil
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type binarySample struct {
+	Name    string            `json:"name"`
+	Count   int64             `json:"count"`
+	Ratio   float64           `json:"ratio"`
+	Tags    []string          `json:"tags,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	At      time.Time         `json:"at"`
+	Ignored string            `json:"-"`
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	in := binarySample{
+		Name:    "worker",
+		Count:   1 << 40,
+		Ratio:   0.25,
+		Tags:    []string{"a", "b"},
+		Labels:  map[string]string{"app": "crawler"},
+		At:      time.Date(2026, 2, 16, 15, 4, 5, 123456789, time.UTC),
+		Ignored: "secret",
+	}
+
+	data, err := EncodeBinary(in)
+	if err != nil {
+		t.Fatalf("EncodeBinary: %v", err)
+	}
+	jsonData, _ := json.Marshal(in)
+	if len(data) >= len(jsonData) {
+		t.Errorf("binary size %d should be smaller than JSON size %d", len(data), len(jsonData))
+	}
+
+	out, err := DecodeBinaryAs[binarySample](data)
+	if err != nil {
+		t.Fatalf("DecodeBinaryAs: %v", err)
+	}
+	if out.Name != in.Name || out.Count != in.Count || out.Ratio != in.Ratio ||
+		len(out.Tags) != 2 || out.Labels["app"] != "crawler" || !out.At.Equal(in.At) {
+		t.Errorf("round trip mismatch: %+v", out)
+	}
+	if out.Ignored != "" {
+		t.Errorf(`json:"-" field should not be encoded, got %q`, out.Ignored)
+	}
+
+	// 解码到 map 时使用 json 标签作为 key
+	m, err := DecodeBinaryAs[map[string]any](data)
+	if err != nil {
+		t.Fatalf("DecodeBinaryAs map: %v", err)
+	}
+	if m["name"] != "worker" {
+		t.Errorf("map[name] = %v", m["name"])
+	}
+}
+
+func TestBinaryStringAndFile(t *testing.T) {
+	s, err := EncodeBinaryString([]int{1, 2, 3})
+	if err != nil {
+		t.Fatalf("EncodeBinaryString: %v", err)
+	}
+	got, err := DecodeBinaryStringAs[[]int](s)
+	if err != nil || len(got) != 3 || got[2] != 3 {
+		t.Fatalf("DecodeBinaryStringAs = %v, %v", got, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "data.msgpack")
+	if err = WriteBinaryFile(path, map[string]int{"a": 1}); err != nil {
+		t.Fatalf("WriteBinaryFile: %v", err)
+	}
+	m, err := ReadBinaryFileAs[map[string]int](path)
+	if err != nil || m["a"] != 1 {
+		t.Fatalf("ReadBinaryFileAs = %v, %v", m, err)
+	}
+
+	if err = DecodeBinary([]byte{0xc1}, &m); err == nil {
+		t.Error("expected error for invalid data")
+	}
+}
@@ -0,0 +1,97 @@
+package jsonutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strconv"
+
+	"github.com/pylemonorg/gotools/hashutil"
+)
+
+// Canonicalize 将 JSON 规范化：对象 key 按字典序排序、去除多余空白、不转义 HTML 字符、
+// 数字统一格式（1.0 / 1e0 → 1），语义相同的文档输出字节完全一致。
+//
+// 用法：
+//
+//	a, _ := jsonutil.Canonicalize([]byte(`{"b": 1.0, "a": [1, 2]}`))
+//	// {"a":[1,2],"b":1}
+func Canonicalize(data []byte) ([]byte, error) {
+	v, err := decodeUseNumber(data)
+	if err != nil {
+		return nil, log.ErrorfE("jsonutil: 规范化解析失败: %v", err)
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(canonicalValue(v)); err != nil {
+		return nil, log.ErrorfE("jsonutil: 规范化序列化失败: %v", err)
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// canonicalValue 递归规范化数字格式（map 的 key 排序由 encoding/json 完成）。
+func canonicalValue(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, item := range t {
+			t[k] = canonicalValue(item)
+		}
+		return t
+	case []any:
+		for i, item := range t {
+			t[i] = canonicalValue(item)
+		}
+		return t
+	case json.Number:
+		return canonicalNumber(t)
+	default:
+		return v
+	}
+}
+
+// canonicalNumber 整数保持整数形式，其余数字按 float64 的最短表示输出。
+func canonicalNumber(n json.Number) json.Number {
+	if i, err := strconv.ParseInt(n.String(), 10, 64); err == nil {
+		return json.Number(strconv.FormatInt(i, 10))
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return n
+	}
+	if f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+		return json.Number(strconv.FormatInt(int64(f), 10))
+	}
+	return json.Number(strconv.FormatFloat(f, 'g', -1, 64))
+}
+
+// HashJSON 计算 JSON 的稳定哈希（规范化后的 SHA-256 十六进制串），语义相同的文档哈希相同。
+// v 为 []byte / json.RawMessage 时视为 JSON 原文，其余类型先序列化。
+// 适用于抓取数据的去重和变更检测。
+//
+// 用法：
+//
+//	h1, _ := jsonutil.HashJSON([]byte(`{"a":1,"b":2}`))
+//	h2, _ := jsonutil.HashJSON(map[string]any{"b": 2, "a": 1.0})
+//	// h1 == h2
+func HashJSON(v any) (string, error) {
+	var data []byte
+	switch t := v.(type) {
+	case []byte:
+		data = t
+	case json.RawMessage:
+		data = t
+	default:
+		var err error
+		if data, err = Marshal(v); err != nil {
+			return "", err
+		}
+	}
+
+	canon, err := Canonicalize(data)
+	if err != nil {
+		return "", err
+	}
+	return hashutil.SHA256(string(canon))
+}
@@ -0,0 +1,56 @@
+package jsonutil
+
+import "testing"
+
+// ---------------------------------------------------------------------------
+// Canonicalize / HashJSON
+// ---------------------------------------------------------------------------
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{`{"b": 1, "a": 2}`, `{"a":2,"b":1}`},
+		{"{\n  \"z\": {\"y\": [3, 2, 1], \"x\": null}\n}", `{"z":{"x":null,"y":[3,2,1]}}`},
+		{`{"n": 1.0, "m": 1e2, "f": 0.50, "big": 9007199254740993}`, `{"big":9007199254740993,"f":0.5,"m":100,"n":1}`},
+		{`{"html": "<a&b>"}`, `{"html":"<a&b>"}`},
+		{`[true, false, "s"]`, `[true,false,"s"]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := Canonicalize([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("Canonicalize: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Canonicalize([]byte(`{bad`)); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestHashJSON(t *testing.T) {
+	h1, err := HashJSON([]byte(`{"a":1,"b":[1,2]}`))
+	if err != nil {
+		t.Fatalf("HashJSON: %v", err)
+	}
+	h2, err := HashJSON(map[string]any{"b": []int{1, 2}, "a": 1.0})
+	if err != nil {
+		t.Fatalf("HashJSON: %v", err)
+	}
+	if h1 != h2 {
+		t.Errorf("semantically equal documents hash differently: %s vs %s", h1, h2)
+	}
+	if len(h1) != 64 {
+		t.Errorf("hash length = %d, want 64", len(h1))
+	}
+
+	h3, _ := HashJSON([]byte(`{"a":1,"b":[2,1]}`))
+	if h1 == h3 {
+		t.Error("different array order should hash differently")
+	}
+}
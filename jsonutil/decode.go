@@ -0,0 +1,88 @@
+package jsonutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// DecodeOption 反序列化选项，用于 ReadFile 等函数。
+type DecodeOption func(*decodeOptions)
+
+// decodeOptions 反序列化选项集合。
+type decodeOptions struct {
+	strict    bool
+	useNumber bool
+}
+
+// WithStrict 严格模式：JSON 中出现目标结构体没有的字段，或 JSON 值之后还有多余内容时报错。
+// 用于及早发现配置文件中的字段拼写错误。
+func WithStrict() DecodeOption {
+	return func(o *decodeOptions) {
+		o.strict = true
+	}
+}
+
+// WithUseNumber 数字解析为 json.Number 而不是 float64（目标为 any / map[string]any 时生效），
+// 避免超过 2^53 的 int64 ID 丢失精度。
+func WithUseNumber() DecodeOption {
+	return func(o *decodeOptions) {
+		o.useNumber = true
+	}
+}
+
+// decodeWith 按选项反序列化 data 到 v。
+func decodeWith(data []byte, v any, opts []DecodeOption) error {
+	var o decodeOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	if !o.strict && !o.useNumber {
+		return json.Unmarshal(data, v)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if o.strict {
+		dec.DisallowUnknownFields()
+	}
+	if o.useNumber {
+		dec.UseNumber()
+	}
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if o.strict {
+		if _, err := dec.Token(); err != io.EOF {
+			return fmt.Errorf("JSON 值之后存在多余内容")
+		}
+	}
+	return nil
+}
+
+// UnmarshalStrict 严格反序列化：出现未知字段或多余内容时返回错误。
+//
+// 用法：
+//
+//	var cfg Config
+//	if err := jsonutil.UnmarshalStrict(data, &cfg); err != nil {
+//	    // json: unknown field "prot"
+//	}
+func UnmarshalStrict(data []byte, v any) error {
+	if err := decodeWith(data, v, []DecodeOption{WithStrict()}); err != nil {
+		return log.ErrorfE("jsonutil: 严格 unmarshal 失败: %v", err)
+	}
+	return nil
+}
+
+// ToMapUseNumber 将 JSON 字节切片反序列化为 map[string]any，数字保留为 json.Number。
+// GetInt / GetFloat64 / Get*Path 均支持 json.Number，大整数可通过 json.Number.Int64() 无损取出。
+func ToMapUseNumber(data []byte) (map[string]any, error) {
+	var m map[string]any
+	if err := decodeWith(data, &m, []DecodeOption{WithUseNumber()}); err != nil {
+		return nil, log.ErrorfE("jsonutil: 解析为 map 失败: %v", err)
+	}
+	return m, nil
+}
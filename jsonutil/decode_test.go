@@ -0,0 +1,82 @@
+package jsonutil
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// UnmarshalStrict / ToMapUseNumber / ReadFile options
+// ---------------------------------------------------------------------------
+
+type strictConfig struct {
+	Port int    `json:"port"`
+	Host string `json:"host"`
+}
+
+func TestUnmarshalStrict(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"valid", `{"port":80,"host":"a"}`, false},
+		{"unknown field", `{"prot":80}`, true},
+		{"trailing data", `{"port":80} {"port":81}`, true},
+		{"trailing whitespace", "{\"port\":80}\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg strictConfig
+			err := UnmarshalStrict([]byte(tt.input), &cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UnmarshalStrict(%s) err = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestToMapUseNumber(t *testing.T) {
+	m, err := ToMapUseNumber([]byte(`{"id":9007199254740993,"nested":{"n":1.5}}`))
+	if err != nil {
+		t.Fatalf("ToMapUseNumber: %v", err)
+	}
+	n, ok := m["id"].(json.Number)
+	if !ok {
+		t.Fatalf("id type = %T, want json.Number", m["id"])
+	}
+	if id, _ := n.Int64(); id != 9007199254740993 {
+		t.Errorf("id = %d, want 9007199254740993", id)
+	}
+	if got := GetInt(m, "id"); got != 9007199254740993 {
+		t.Errorf("GetInt = %d", got)
+	}
+	if got := GetFloat64Path(m, "nested.n"); got != 1.5 {
+		t.Errorf("GetFloat64Path = %v, want 1.5", got)
+	}
+}
+
+func TestReadFileOptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"port":80,"hots":"typo"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg strictConfig
+	if err := ReadFile(path, &cfg); err != nil {
+		t.Fatalf("ReadFile without options: %v", err)
+	}
+	if err := ReadFile(path, &cfg, WithStrict()); err == nil {
+		t.Fatal("expected error for unknown field in strict mode")
+	}
+
+	var m map[string]any
+	if err := ReadFile(path, &m, WithUseNumber()); err != nil {
+		t.Fatalf("ReadFile WithUseNumber: %v", err)
+	}
+	if _, ok := m["port"].(json.Number); !ok {
+		t.Errorf("port type = %T, want json.Number", m["port"])
+	}
+}
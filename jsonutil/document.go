@@ -0,0 +1,437 @@
+package jsonutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// ErrPathNotFound Document.Delete 的路径不存在。
+var ErrPathNotFound = errors.New("jsonutil: 路径不存在")
+
+// Document 按需解析的 JSON 文档：只在原始字节上定位路径，不构建完整的 map 树，
+// Set / Delete 只替换目标值所在的字节片段，其余内容（key 顺序、空白、数字格式）原样保留。
+// 适合只读取或修改大文档中少数几个字段的场景。可并发使用，零值等价于 JSON null。
+//
+// 路径语法与 GetPath 相同："a.b[2].c" 或 "a.b.2.c"，可选的 "$." 前缀会被忽略，空路径表示根节点。
+//
+// 用法：
+//
+//	doc, err := jsonutil.NewDocument(payload)
+//	if err != nil { ... }
+//	name := doc.Get("data.items[0].name").String()
+//	_ = doc.Set("meta.processed_at", time.Now())
+//	_ = doc.Delete("data.debug")
+//	out := doc.Bytes()
+type Document struct {
+	mu   sync.RWMutex
+	data []byte // 为空（零值）或合法 JSON；修改时整体替换为新切片，从不原地写入
+}
+
+// jsonNull 零值 Document 的内容。
+var jsonNull = []byte("null")
+
+// raw 返回文档内容，零值时返回 null。调用方持有锁。
+func (d *Document) raw() []byte {
+	if len(d.data) == 0 {
+		return jsonNull
+	}
+	return d.data
+}
+
+// NewDocument 校验 data 并创建 Document（复制 data，之后修改 data 不影响文档）。
+func NewDocument(data []byte) (*Document, error) {
+	if err := validJSON(data); err != nil {
+		return nil, log.ErrorfE("jsonutil: 解析文档失败: %v", err)
+	}
+	return &Document{data: bytes.Clone(data)}, nil
+}
+
+// NewDocumentString 从字符串创建 Document。
+func NewDocumentString(s string) (*Document, error) {
+	return NewDocument([]byte(s))
+}
+
+// validJSON 校验 data 是单个合法的 JSON 值，返回具体的语法错误（不构建值）。
+func validJSON(data []byte) error {
+	var raw json.RawMessage
+	return json.Unmarshal(data, &raw)
+}
+
+// Get 返回 path 处的值，路径不存在时 Result.Exists 为 false。
+func (d *Document) Get(path string) Result {
+	d.mu.RLock()
+	data := d.raw()
+	d.mu.RUnlock()
+
+	start, end, ok := locate(data, splitPath(path))
+	if !ok {
+		return Result{}
+	}
+	return Result{raw: data[start:end:end]}
+}
+
+// Set 将 path 处的值设置为 v 的 JSON 序列化结果（json.RawMessage 校验后去除空白写入）。
+// 路径中不存在的对象字段会自动创建（中间层级创建为对象），数组只能修改已有下标或以下标 len 追加到末尾。
+// 路径经过字符串、数字等非容器值时返回错误。
+func (d *Document) Set(path string, v any) error {
+	val, err := json.Marshal(v)
+	if err != nil {
+		return log.ErrorfE("jsonutil: Set %q 序列化失败: %v", path, err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	out, err := setRaw(d.raw(), splitPath(path), val)
+	if err != nil {
+		return fmt.Errorf("jsonutil: Set %q: %w", path, err)
+	}
+	d.data = out
+	return nil
+}
+
+// Delete 删除 path 处的对象字段或数组元素，路径不存在时返回 ErrPathNotFound，不能删除根节点。
+func (d *Document) Delete(path string) error {
+	segs := splitPath(path)
+	if len(segs) == 0 {
+		return fmt.Errorf("jsonutil: 不能删除根节点")
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	data := d.raw()
+	parent, ok := locateSegs(data, segs[:len(segs)-1])
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrPathNotFound, path)
+	}
+	m, ok := findMember(data, parent, segs[len(segs)-1])
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrPathNotFound, path)
+	}
+
+	// 优先连同后面的逗号一起删除，最后一个成员则连同前面的逗号删除
+	from, to := m.start, m.end
+	if next := skipSpace(data, m.end); data[next] == ',' {
+		to = skipSpace(data, next+1)
+	} else if m.prevEnd >= 0 {
+		from = m.prevEnd
+	}
+	d.data = splice(data, from, to, nil)
+	return nil
+}
+
+// Bytes 返回当前文档的 JSON（副本）。
+func (d *Document) Bytes() []byte {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return bytes.Clone(d.raw())
+}
+
+// String 返回当前文档的 JSON 字符串。
+func (d *Document) String() string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return string(d.raw())
+}
+
+// MarshalJSON 实现 json.Marshaler，Document 可直接作为结构体字段。
+func (d *Document) MarshalJSON() ([]byte, error) {
+	return d.Bytes(), nil
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler。
+func (d *Document) UnmarshalJSON(data []byte) error {
+	if err := validJSON(data); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	d.data = bytes.Clone(data)
+	d.mu.Unlock()
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// Result
+// ---------------------------------------------------------------------------
+
+// Result Document.Get 的结果，持有目标值的原始 JSON，之后对文档的修改不影响已取得的 Result。
+// 类型不匹配时各取值方法返回零值，与 GetStringPath 等函数一致。
+type Result struct {
+	raw []byte
+}
+
+// Exists 路径是否存在（值为 null 时也返回 true）。
+func (r Result) Exists() bool {
+	return r.raw != nil
+}
+
+// Raw 返回原始 JSON，路径不存在时为 nil。
+func (r Result) Raw() json.RawMessage {
+	return json.RawMessage(r.raw)
+}
+
+// IsNull 值是否为 null。
+func (r Result) IsNull() bool {
+	return string(r.raw) == "null"
+}
+
+// String 字符串返回去掉引号、处理转义后的内容，null 或路径不存在返回空串，其他类型返回原始 JSON 文本。
+func (r Result) String() string {
+	if len(r.raw) == 0 || r.IsNull() {
+		return ""
+	}
+	if r.raw[0] != '"' {
+		return string(r.raw)
+	}
+	var s string
+	if err := json.Unmarshal(r.raw, &s); err != nil {
+		return ""
+	}
+	return s
+}
+
+// Int 返回整数值（小数截断），不是数字时返回 0。
+func (r Result) Int() int64 {
+	if n, err := strconv.ParseInt(string(r.raw), 10, 64); err == nil {
+		return n
+	}
+	return int64(r.Float())
+}
+
+// Float 返回 float64 值，不是数字时返回 0。
+func (r Result) Float() float64 {
+	if len(r.raw) == 0 || !isNumberStart(r.raw[0]) {
+		return 0
+	}
+	f, _ := strconv.ParseFloat(string(r.raw), 64)
+	return f
+}
+
+// Bool 返回 bool 值，不是 true 时返回 false。
+func (r Result) Bool() bool {
+	return string(r.raw) == "true"
+}
+
+// Value 将值解析为 any（对象为 map[string]any，数字为 json.Number），路径不存在时返回 nil。
+func (r Result) Value() any {
+	if r.raw == nil {
+		return nil
+	}
+	v, _ := decodeUseNumber(r.raw)
+	return v
+}
+
+// Decode 将值反序列化到 v，路径不存在时返回 ErrPathNotFound。
+func (r Result) Decode(v any) error {
+	if r.raw == nil {
+		return ErrPathNotFound
+	}
+	return json.Unmarshal(r.raw, v)
+}
+
+// ---------------------------------------------------------------------------
+// 原始字节扫描（data 均为已校验的合法 JSON）
+// ---------------------------------------------------------------------------
+
+// member 对象字段或数组元素在 data 中的位置。
+type member struct {
+	start    int // 字段的 key 起始位置（数组元素为值的起始位置）
+	valStart int // 值的起始位置
+	end      int // 值的结束位置（不含）
+	prevEnd  int // 前一个成员值的结束位置，没有前一个成员时为 -1
+}
+
+// locate 返回 segs 指向的值在 data 中的 [start, end)。
+func locate(data []byte, segs []string) (int, int, bool) {
+	start, ok := locateSegs(data, segs)
+	if !ok {
+		return 0, 0, false
+	}
+	return start, valueEnd(data, start), true
+}
+
+// locateSegs 返回 segs 指向的值的起始位置。
+func locateSegs(data []byte, segs []string) (int, bool) {
+	pos := skipSpace(data, 0)
+	for _, seg := range segs {
+		m, ok := findMember(data, pos, seg)
+		if !ok {
+			return 0, false
+		}
+		pos = m.valStart
+	}
+	return pos, true
+}
+
+// findMember 在 data[pos] 处的对象或数组中查找 seg 对应的成员，pos 处不是容器时返回 false。
+func findMember(data []byte, pos int, seg string) (member, bool) {
+	var index int
+	switch data[pos] {
+	case '{':
+	case '[':
+		i, err := strconv.Atoi(seg)
+		if err != nil || i < 0 {
+			return member{}, false
+		}
+		index = i
+	default:
+		return member{}, false
+	}
+
+	isObject := data[pos] == '{'
+	prevEnd := -1
+	i := skipSpace(data, pos+1)
+	for n := 0; data[i] != '}' && data[i] != ']'; n++ {
+		m := member{start: i, prevEnd: prevEnd}
+		var match bool
+		if isObject {
+			keyEnd := skipString(data, i) + 1
+			match = decodeKey(data[i:keyEnd]) == seg
+			i = skipSpace(data, keyEnd) + 1 // 跳过 ':'
+			i = skipSpace(data, i)
+		} else {
+			match = n == index
+		}
+		m.valStart, m.end = i, valueEnd(data, i)
+		if match {
+			return m, true
+		}
+		prevEnd = m.end
+		i = skipSpace(data, m.end)
+		if data[i] == ',' {
+			i = skipSpace(data, i+1)
+		}
+	}
+	return member{}, false
+}
+
+// setRaw 返回将 segs 处的值替换（或新建）为 val 后的新文档。
+func setRaw(data []byte, segs []string, val []byte) ([]byte, error) {
+	pos := skipSpace(data, 0)
+	for i, seg := range segs {
+		m, ok := findMember(data, pos, seg)
+		if ok {
+			pos = m.valStart
+			continue
+		}
+		if data[pos] != '{' && data[pos] != '[' {
+			return nil, fmt.Errorf("路径 %q 的父节点不是对象或数组", seg)
+		}
+		return insertMember(data, pos, seg, nestValue(segs[i+1:], val))
+	}
+	return splice(data, pos, valueEnd(data, pos), val), nil
+}
+
+// insertMember 在 data[pos] 处的容器末尾追加成员：对象追加字段 seg，数组要求 seg 等于当前长度。
+func insertMember(data []byte, pos int, seg string, val []byte) ([]byte, error) {
+	end := valueEnd(data, pos) - 1 // 结尾的 '}' 或 ']'
+	var item []byte
+	if data[pos] == '{' {
+		key, _ := json.Marshal(seg)
+		item = append(append(key, ':'), val...)
+	} else {
+		n := countElements(data, pos)
+		if seg != strconv.Itoa(n) {
+			return nil, fmt.Errorf("数组下标 %s 越界（长度 %d，只能追加下标 %d）", seg, n, n)
+		}
+		item = val
+	}
+
+	// 插在最后一个成员之后，保留结尾括号前的空白
+	last := end - 1
+	for last > pos && isSpace(data[last]) {
+		last--
+	}
+	if last == pos {
+		return splice(data, pos+1, pos+1, item), nil
+	}
+	return splice(data, last+1, last+1, append([]byte{','}, item...)), nil
+}
+
+// nestValue 将 val 按 segs 逐层包装为对象：["a", "b"], 1 → {"a":{"b":1}}。
+func nestValue(segs []string, val []byte) []byte {
+	for i := len(segs) - 1; i >= 0; i-- {
+		key, _ := json.Marshal(segs[i])
+		val = append(append(append(append([]byte{'{'}, key...), ':'), val...), '}')
+	}
+	return val
+}
+
+// countElements 返回 data[pos] 处数组的元素个数。
+func countElements(data []byte, pos int) int {
+	n := 0
+	for i := skipSpace(data, pos+1); data[i] != ']'; n++ {
+		i = skipSpace(data, valueEnd(data, i))
+		if data[i] == ',' {
+			i = skipSpace(data, i+1)
+		}
+	}
+	return n
+}
+
+// splice 返回将 data[from:to] 替换为 repl 后的新切片（不修改 data）。
+func splice(data []byte, from, to int, repl []byte) []byte {
+	out := make([]byte, 0, len(data)-(to-from)+len(repl))
+	out = append(out, data[:from]...)
+	out = append(out, repl...)
+	return append(out, data[to:]...)
+}
+
+// valueEnd 返回从 data[i]（值的第一个字节）开始的值的结束位置（不含）。
+func valueEnd(data []byte, i int) int {
+	switch data[i] {
+	case '"':
+		return skipString(data, i) + 1
+	case '{', '[':
+		depth := 0
+		for ; i < len(data); i++ {
+			switch data[i] {
+			case '"':
+				i = skipString(data, i)
+			case '{', '[':
+				depth++
+			case '}', ']':
+				if depth--; depth == 0 {
+					return i + 1
+				}
+			}
+		}
+		return len(data)
+	default:
+		for i < len(data) && !isSpace(data[i]) && data[i] != ',' && data[i] != '}' && data[i] != ']' {
+			i++
+		}
+		return i
+	}
+}
+
+// decodeKey 解析对象 key（含引号），没有转义字符时直接截取。
+func decodeKey(raw []byte) string {
+	if bytes.IndexByte(raw, '\\') < 0 {
+		return string(raw[1 : len(raw)-1])
+	}
+	var s string
+	_ = json.Unmarshal(raw, &s)
+	return s
+}
+
+// skipSpace 返回 i 之后第一个非空白字符的位置。
+func skipSpace(data []byte, i int) int {
+	for i < len(data) && isSpace(data[i]) {
+		i++
+	}
+	return i
+}
+
+// isSpace 判断是否为 JSON 空白字符。
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// isNumberStart 判断是否为 JSON 数字的首字符。
+func isNumberStart(c byte) bool {
+	return c == '-' || (c >= '0' && c <= '9')
+}
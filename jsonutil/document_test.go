@@ -0,0 +1,248 @@
+package jsonutil
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// Document
+// ---------------------------------------------------------------------------
+
+const documentJSON = `{
+  "data": {
+    "total": 2,
+    "ratio": 0.5,
+    "ok": true,
+    "none": null,
+    "items": [
+      {"name": "alice", "tags": ["x", "y"]},
+      {"name": "b\"ob", "score": 1e2}
+    ]
+  },
+  "a\"b": 1
+}`
+
+func TestDocumentGet(t *testing.T) {
+	doc, err := NewDocumentString(documentJSON)
+	if err != nil {
+		t.Fatalf("NewDocumentString: %v", err)
+	}
+
+	if got := doc.Get("data.items[0].name").String(); got != "alice" {
+		t.Errorf("name = %q, 期望 alice", got)
+	}
+	if got := doc.Get("$.data.items.1.name").String(); got != `b"ob` {
+		t.Errorf("转义字符串 = %q", got)
+	}
+	if got := doc.Get("data.items[0].tags[1]").String(); got != "y" {
+		t.Errorf("tags[1] = %q, 期望 y", got)
+	}
+	if got := doc.Get("data.total").Int(); got != 2 {
+		t.Errorf("total = %d, 期望 2", got)
+	}
+	if got := doc.Get("data.items[1].score").Int(); got != 100 {
+		t.Errorf("score = %d, 期望 100", got)
+	}
+	if got := doc.Get("data.ratio").Float(); got != 0.5 {
+		t.Errorf("ratio = %v, 期望 0.5", got)
+	}
+	if !doc.Get("data.ok").Bool() {
+		t.Error("ok 期望为 true")
+	}
+	if r := doc.Get("data.none"); !r.Exists() || !r.IsNull() || r.String() != "" {
+		t.Errorf("null 值 = %q", r.Raw())
+	}
+	if got := doc.Get(`a"b`).Int(); got != 1 {
+		t.Errorf("含转义的 key = %d, 期望 1", got)
+	}
+	if got := doc.Get("data.items[0].tags").String(); got != `["x", "y"]` {
+		t.Errorf("数组原始文本 = %q", got)
+	}
+
+	for _, path := range []string{"data.missing", "data.items[2]", "data.items.-1", "data.total.x", "data.items.name"} {
+		if doc.Get(path).Exists() {
+			t.Errorf("Get(%q) 期望不存在", path)
+		}
+	}
+	if got := doc.Get("data.items[0].name").Int(); got != 0 {
+		t.Errorf("字符串按 Int 取值 = %d, 期望 0", got)
+	}
+
+	var item struct {
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+	}
+	if err := doc.Get("data.items[0]").Decode(&item); err != nil || item.Name != "alice" || len(item.Tags) != 2 {
+		t.Errorf("Decode = %+v, %v", item, err)
+	}
+	if err := doc.Get("missing").Decode(&item); !errors.Is(err, ErrPathNotFound) {
+		t.Errorf("路径不存在时期望 ErrPathNotFound，实际 %v", err)
+	}
+	if _, ok := doc.Get("data").Value().(map[string]any); !ok {
+		t.Error("Value 期望为 map[string]any")
+	}
+}
+
+func TestDocumentSet(t *testing.T) {
+	doc, _ := NewDocumentString(`{"b": 1, "a": {"x": [1, 2]}}`)
+
+	steps := []struct {
+		path string
+		v    any
+		want string
+	}{
+		{"b", "two", `{"b": "two", "a": {"x": [1, 2]}}`},
+		{"a.x[1]", 20, `{"b": "two", "a": {"x": [1, 20]}}`},
+		{"a.x[2]", 3, `{"b": "two", "a": {"x": [1, 20,3]}}`},
+		{"a.y", true, `{"b": "two", "a": {"x": [1, 20,3],"y":true}}`},
+		{"c.d.e", json.RawMessage(` [ 1 ] `), `{"b": "two", "a": {"x": [1, 20,3],"y":true},"c":{"d":{"e":[1]}}}`},
+	}
+	for _, s := range steps {
+		if err := doc.Set(s.path, s.v); err != nil {
+			t.Fatalf("Set(%q): %v", s.path, err)
+		}
+		if got := doc.String(); got != s.want {
+			t.Fatalf("Set(%q) 后 = %s, 期望 %s", s.path, got, s.want)
+		}
+	}
+	if !IsValid(doc.Bytes()) {
+		t.Fatalf("修改后不是合法 JSON: %s", doc)
+	}
+
+	empty, _ := NewDocumentString(`{ }`)
+	if err := empty.Set("k", 1); err != nil || empty.String() != `{"k":1 }` {
+		t.Errorf("空对象 Set = %s, %v", empty, err)
+	}
+	arr, _ := NewDocumentString(`[]`)
+	if err := arr.Set("0", "v"); err != nil || arr.String() != `["v"]` {
+		t.Errorf("空数组追加 = %s, %v", arr, err)
+	}
+
+	for _, path := range []string{"b.x", "a.x[5]", "a.x.k"} {
+		if err := doc.Set(path, 1); err == nil {
+			t.Errorf("Set(%q) 期望返回错误", path)
+		}
+	}
+	if err := doc.Set("", map[string]int{"root": 1}); err != nil || doc.String() != `{"root":1}` {
+		t.Errorf("替换根节点 = %s, %v", doc, err)
+	}
+}
+
+func TestDocumentDelete(t *testing.T) {
+	doc, _ := NewDocumentString(documentJSON)
+	before := doc.Get("data.items[1]")
+
+	for _, path := range []string{"data.items[0]", "a\"b", "data.none", "data.ratio"} {
+		if err := doc.Delete(path); err != nil {
+			t.Fatalf("Delete(%q): %v", path, err)
+		}
+		if !IsValid(doc.Bytes()) {
+			t.Fatalf("Delete(%q) 后不是合法 JSON: %s", path, doc)
+		}
+		if doc.Get(path).Exists() && path != "data.items[0]" {
+			t.Errorf("Delete(%q) 后仍存在", path)
+		}
+	}
+	if got := doc.Get("data.items[0].name").String(); got != `b"ob` {
+		t.Errorf("删除首元素后 items[0].name = %q", got)
+	}
+	// 修改前取得的 Result 不受之后修改的影响
+	if got := before.String(); got != `{"name": "b\"ob", "score": 1e2}` {
+		t.Errorf("修改前取得的 Result = %s", got)
+	}
+	if got := doc.Get("data.total").Int(); got != 2 {
+		t.Errorf("其余字段 total = %d, 期望 2", got)
+	}
+
+	single, _ := NewDocumentString(`{"a": [1]}`)
+	if err := single.Delete("a[0]"); err != nil || single.String() != `{"a": []}` {
+		t.Errorf("删除唯一元素 = %s, %v", single, err)
+	}
+	if err := single.Delete("a"); err != nil || single.String() != `{}` {
+		t.Errorf("删除唯一字段 = %s, %v", single, err)
+	}
+	if err := single.Delete("a"); !errors.Is(err, ErrPathNotFound) {
+		t.Errorf("期望 ErrPathNotFound，实际 %v", err)
+	}
+	if err := single.Delete(""); err == nil {
+		t.Error("删除根节点期望返回错误")
+	}
+}
+
+func TestDocumentInvalid(t *testing.T) {
+	for _, s := range []string{"", "{", `{"a":1} x`, "[1,]"} {
+		if _, err := NewDocumentString(s); err == nil {
+			t.Errorf("NewDocumentString(%q) 期望返回错误", s)
+		}
+	}
+}
+
+func TestDocumentJSON(t *testing.T) {
+	var v struct {
+		Payload *Document `json:"payload"`
+	}
+	if err := Unmarshal([]byte(`{"payload": {"k": [1, 2]}}`), &v); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got := v.Payload.Get("k[1]").Int(); got != 2 {
+		t.Errorf("k[1] = %d, 期望 2", got)
+	}
+	_ = v.Payload.Set("k[0]", 9)
+	if got := MustMarshalString(v); got != `{"payload":{"k":[9,2]}}` {
+		t.Errorf("Marshal = %s", got)
+	}
+}
+
+func TestDocumentConcurrent(t *testing.T) {
+	doc, _ := NewDocumentString(`{"n": 0, "items": []}`)
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_ = doc.Set("n", i)
+		}()
+		go func() {
+			defer wg.Done()
+			_ = doc.Get("n").Int()
+		}()
+	}
+	wg.Wait()
+	if !IsValid(doc.Bytes()) {
+		t.Fatalf("并发修改后不是合法 JSON: %s", doc)
+	}
+}
+
+func TestDocumentZeroValue(t *testing.T) {
+	var s struct{ D Document }
+	if err := Unmarshal([]byte(`{"A":1}`), &s); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if r := s.D.Get("x"); r.Exists() {
+		t.Errorf("零值 Get(x) 期望不存在，实际 %s", r.Raw())
+	}
+	if r := s.D.Get(""); !r.IsNull() {
+		t.Errorf("零值根节点期望为 null，实际 %s", r.Raw())
+	}
+	if got := s.D.String(); got != "null" {
+		t.Errorf("零值 String = %q, 期望 null", got)
+	}
+	if got, err := s.D.MarshalJSON(); err != nil || string(got) != "null" {
+		t.Errorf("零值 MarshalJSON = %s, %v", got, err)
+	}
+	if got := MustMarshalString(&s); got != `{"D":null}` {
+		t.Errorf("Marshal = %s", got)
+	}
+	if err := s.D.Delete("x"); !errors.Is(err, ErrPathNotFound) {
+		t.Errorf("零值 Delete 期望 ErrPathNotFound，实际 %v", err)
+	}
+	if err := s.D.Set("x", 1); err == nil {
+		t.Error("零值 Set(x) 期望返回错误")
+	}
+	if err := s.D.Set("", map[string]int{"x": 1}); err != nil || s.D.Get("x").Int() != 1 {
+		t.Errorf("零值替换根节点 = %s, %v", s.D.String(), err)
+	}
+}
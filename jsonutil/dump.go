@@ -0,0 +1,154 @@
+package jsonutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// DumpMaxBytes Dump / Sdump 输出的最大字节数，超出部分截断并注明总长度；<= 0 时不截断。
+var DumpMaxBytes = 16 << 10
+
+// ANSI 颜色，用于 DumpColored / SdumpColored。
+const (
+	colorReset  = "\x1b[0m"
+	colorKey    = "\x1b[34m" // 蓝色：对象 key
+	colorString = "\x1b[32m" // 绿色：字符串
+	colorNumber = "\x1b[36m" // 青色：数字
+	colorBool   = "\x1b[33m" // 黄色：true / false
+	colorNull   = "\x1b[90m" // 灰色：null
+)
+
+// Dump 以 debug 级别输出 v 的缩进 JSON（含 Go 类型），用于调试时代替 MarshalIndentString + Infof。
+// 通过 logger.SetModuleLevel("jsonutil", "debug") 开启；未开启时不做序列化，无额外开销。
+// 超过 DumpMaxBytes 的内容会被截断。
+//
+// 用法：
+//
+//	logger.SetModuleLevel("jsonutil", "debug")
+//	jsonutil.Dump(resp)
+//	jsonutil.Dump(json.RawMessage(body)) // []byte / string 形式的 JSON 文档同样会格式化
+func Dump(v any) {
+	dump(v, false)
+}
+
+// DumpColored 同 Dump，key、字符串、数字等按 ANSI 颜色高亮，适合在终端（pretty 模式）中查看。
+func DumpColored(v any) {
+	dump(v, true)
+}
+
+// dump Dump / DumpColored 的实现。
+func dump(v any, colored bool) {
+	ev := log.Debug()
+	if !ev.Enabled() {
+		return
+	}
+	var s string
+	if colored {
+		s = SdumpColored(v)
+	} else {
+		s = Sdump(v)
+	}
+	ev.Msgf("jsonutil: dump %T:\n%s", v, s)
+}
+
+// Sdump 返回 v 的缩进 JSON 字符串（不转义 HTML 字符），超过 DumpMaxBytes 时截断。
+// v 为 []byte / string 且内容是合法 JSON 时按 JSON 文档格式化；无法序列化时返回错误说明，不会 panic。
+func Sdump(v any) string {
+	s, truncated := dumpText(v)
+	return s + truncated
+}
+
+// SdumpColored 同 Sdump，输出带 ANSI 颜色。
+func SdumpColored(v any) string {
+	s, truncated := dumpText(v)
+	return colorizeJSON(s) + truncated
+}
+
+// dumpText 返回截断后的缩进 JSON 与截断说明（未截断时为空）。
+func dumpText(v any) (string, string) {
+	switch t := v.(type) {
+	case []byte:
+		if json.Valid(t) {
+			v = json.RawMessage(t)
+		}
+	case string:
+		if json.Valid([]byte(t)) {
+			v = json.RawMessage(t)
+		}
+	}
+
+	data, err := marshalWith(v, MarshalOptions{Indent: "  ", DisableHTMLEscape: true})
+	if err != nil {
+		return fmt.Sprintf("<无法序列化 %T: %v>", v, err), ""
+	}
+	if DumpMaxBytes <= 0 || len(data) <= DumpMaxBytes {
+		return string(data), ""
+	}
+	cut := DumpMaxBytes
+	for cut > 0 && !utf8.RuneStart(data[cut]) {
+		cut--
+	}
+	return string(data[:cut]), fmt.Sprintf("\n... (已截断，共 %d 字节)", len(data))
+}
+
+// colorizeJSON 为 JSON 文本加上 ANSI 颜色。s 可以是被截断的不完整 JSON，未闭合的字符串着色到末尾。
+func colorizeJSON(s string) string {
+	var b strings.Builder
+	b.Grow(len(s) * 2)
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '"':
+			end := stringEnd(s, i)
+			color := colorString
+			if isKey(s, end) {
+				color = colorKey
+			}
+			b.WriteString(color + s[i:end] + colorReset)
+			i = end
+		case c == '-' || (c >= '0' && c <= '9'):
+			end := i + 1
+			for end < len(s) && strings.IndexByte("0123456789.eE+-", s[end]) >= 0 {
+				end++
+			}
+			b.WriteString(colorNumber + s[i:end] + colorReset)
+			i = end
+		case strings.HasPrefix(s[i:], "true"), strings.HasPrefix(s[i:], "false"):
+			n := 4
+			if c == 'f' {
+				n = 5
+			}
+			b.WriteString(colorBool + s[i:i+n] + colorReset)
+			i += n
+		case strings.HasPrefix(s[i:], "null"):
+			b.WriteString(colorNull + "null" + colorReset)
+			i += 4
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String()
+}
+
+// stringEnd 返回从 s[start]（引号）开始的 JSON 字符串结束位置（闭合引号之后），未闭合时返回 len(s)。
+func stringEnd(s string, start int) int {
+	for i := start + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// isKey 判断 s[pos:] 跳过空白后是否以冒号开头，即前面的字符串是对象 key。
+func isKey(s string, pos int) bool {
+	rest := strings.TrimLeft(s[pos:], " \t\r\n")
+	return strings.HasPrefix(rest, ":")
+}
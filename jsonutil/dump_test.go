@@ -0,0 +1,71 @@
+package jsonutil
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// Sdump / SdumpColored
+// ---------------------------------------------------------------------------
+
+var ansiRe = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+func TestSdump(t *testing.T) {
+	v := map[string]any{"url": "https://a.com/?x=1&y=2", "n": 1}
+	want := "{\n  \"n\": 1,\n  \"url\": \"https://a.com/?x=1&y=2\"\n}"
+	if got := Sdump(v); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// []byte / string 形式的 JSON 按文档格式化
+	if got := Sdump(`{"a":[1,2]}`); got != "{\n  \"a\": [\n    1,\n    2\n  ]\n}" {
+		t.Errorf("string JSON got %q", got)
+	}
+	if got := Sdump("plain"); got != `"plain"` {
+		t.Errorf("plain string got %q", got)
+	}
+	if got := Sdump(make(chan int)); !strings.HasPrefix(got, "<无法序列化 chan int") {
+		t.Errorf("unsupported got %q", got)
+	}
+}
+
+func TestSdumpTruncate(t *testing.T) {
+	old := DumpMaxBytes
+	defer func() { DumpMaxBytes = old }()
+	DumpMaxBytes = 9
+
+	got := Sdump("中文中文中文中文")
+	if !strings.HasSuffix(got, "... (已截断，共 26 字节)") {
+		t.Errorf("got %q", got)
+	}
+	head, _, _ := strings.Cut(got, "\n")
+	if head != `"中文` {
+		t.Errorf("截断位置 got %q, 期望在字符边界", head)
+	}
+}
+
+func TestSdumpColored(t *testing.T) {
+	v := map[string]any{"name": "a:b", "age": -1.5e3, "ok": true, "x": nil}
+	got := SdumpColored(v)
+	if plain := ansiRe.ReplaceAllString(got, ""); plain != Sdump(v) {
+		t.Errorf("去掉颜色后 got %q, want %q", plain, Sdump(v))
+	}
+	for _, want := range []string{
+		colorKey + `"name"` + colorReset,
+		colorString + `"a:b"` + colorReset,
+		colorNumber + "-1500" + colorReset,
+		colorBool + "true" + colorReset,
+		colorNull + "null" + colorReset,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("缺少 %q: %q", want, got)
+		}
+	}
+
+	// 截断后未闭合的字符串不影响着色
+	if got := colorizeJSON(`{"a": "b\"c`); got != "{"+colorKey+`"a"`+colorReset+": "+colorString+`"b\"c`+colorReset {
+		t.Errorf("unterminated got %q", got)
+	}
+}
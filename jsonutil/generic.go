@@ -0,0 +1,49 @@
+package jsonutil
+
+import "encoding/json"
+
+// UnmarshalAs 将 JSON 字节切片反序列化为 T 并返回，省去先声明变量再传指针。
+//
+// 用法：
+//
+//	user, err := jsonutil.UnmarshalAs[User](data)
+//	ids, err := jsonutil.UnmarshalAs[[]int64](data)
+func UnmarshalAs[T any](data []byte) (T, error) {
+	var v T
+	err := Unmarshal(data, &v)
+	return v, err
+}
+
+// UnmarshalStringAs 将 JSON 字符串反序列化为 T 并返回。
+func UnmarshalStringAs[T any](s string) (T, error) {
+	return UnmarshalAs[T]([]byte(s))
+}
+
+// ReadFileAs 读取 JSON 文件并反序列化为 T，opts 同 ReadFile。
+//
+// 用法：
+//
+//	cfg, err := jsonutil.ReadFileAs[Config]("config.json", jsonutil.WithStrict())
+func ReadFileAs[T any](path string, opts ...DecodeOption) (T, error) {
+	var v T
+	err := ReadFile(path, &v, opts...)
+	return v, err
+}
+
+// MapTo 将 map[string]any（如 ToMap 的结果或其子对象）转换为结构体 T，按 json tag 匹配字段。
+//
+// 用法：
+//
+//	m, _ := jsonutil.ToMap(resp)
+//	item, err := jsonutil.MapTo[Item](jsonutil.GetMapPath(m, "data.item"))
+func MapTo[T any](m map[string]any) (T, error) {
+	var v T
+	data, err := json.Marshal(m)
+	if err != nil {
+		return v, log.ErrorfE("jsonutil: MapTo 序列化失败: %v", err)
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return v, log.ErrorfE("jsonutil: MapTo 转换失败: %v", err)
+	}
+	return v, nil
+}
@@ -0,0 +1,64 @@
+package jsonutil
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// UnmarshalAs / ReadFileAs / MapTo
+// ---------------------------------------------------------------------------
+
+type genericUser struct {
+	Name string   `json:"name"`
+	Age  int      `json:"age"`
+	Tags []string `json:"tags"`
+}
+
+func TestUnmarshalAs(t *testing.T) {
+	u, err := UnmarshalStringAs[genericUser](`{"name":"alice","age":30,"tags":["a"]}`)
+	if err != nil {
+		t.Fatalf("UnmarshalStringAs: %v", err)
+	}
+	if u.Name != "alice" || u.Age != 30 || len(u.Tags) != 1 {
+		t.Errorf("unexpected result: %+v", u)
+	}
+
+	ids, err := UnmarshalAs[[]int64]([]byte(`[1,2,3]`))
+	if err != nil || len(ids) != 3 || ids[2] != 3 {
+		t.Errorf("UnmarshalAs[[]int64] = %v, %v", ids, err)
+	}
+
+	if _, err := UnmarshalAs[genericUser]([]byte(`not json`)); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestReadFileAs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "user.json")
+	if err := WriteFile(path, genericUser{Name: "bob", Age: 20}); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	u, err := ReadFileAs[genericUser](path, WithStrict())
+	if err != nil {
+		t.Fatalf("ReadFileAs: %v", err)
+	}
+	if u.Name != "bob" || u.Age != 20 {
+		t.Errorf("unexpected result: %+v", u)
+	}
+}
+
+func TestMapTo(t *testing.T) {
+	m := mustMap(t, `{"data":{"user":{"name":"carol","age":41,"tags":["x","y"],"extra":1}}}`)
+	u, err := MapTo[genericUser](GetMapPath(m, "data.user"))
+	if err != nil {
+		t.Fatalf("MapTo: %v", err)
+	}
+	if u.Name != "carol" || u.Age != 41 || len(u.Tags) != 2 {
+		t.Errorf("unexpected result: %+v", u)
+	}
+
+	if _, err := MapTo[genericUser](map[string]any{"age": "not a number"}); err == nil {
+		t.Error("expected error for type mismatch")
+	}
+}
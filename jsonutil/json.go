@@ -7,12 +7,15 @@ import (
 	"github.com/pylemonorg/gotools/logger"
 )
 
+// log jsonutil 模块日志，可通过 logger.SetModuleLevel("jsonutil", ...) 单独控制级别。
+var log = logger.Module("jsonutil")
+
 // Marshal 将任意值序列化为 JSON 字节切片。
 // 对 json.Marshal 的简单封装，统一错误格式。
 func Marshal(v any) ([]byte, error) {
 	data, err := json.Marshal(v)
 	if err != nil {
-		return nil, logger.ErrorfE("jsonutil: marshal 失败: %v", err)
+		return nil, log.ErrorfE("jsonutil: marshal 失败: %v", err)
 	}
 	return data, nil
 }
@@ -30,7 +33,7 @@ func MarshalString(v any) (string, error) {
 func MarshalIndent(v any) ([]byte, error) {
 	data, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
-		return nil, logger.ErrorfE("jsonutil: marshal indent 失败: %v", err)
+		return nil, log.ErrorfE("jsonutil: marshal indent 失败: %v", err)
 	}
 	return data, nil
 }
@@ -49,7 +52,7 @@ func MarshalIndentString(v any) (string, error) {
 func MustMarshal(v any) []byte {
 	data, err := json.Marshal(v)
 	if err != nil {
-		logger.Errorf("jsonutil: MustMarshal 失败: %v", err)
+		log.Errorf("jsonutil: MustMarshal 失败: %v", err)
 		return nil
 	}
 	return data
@@ -63,7 +66,7 @@ func MustMarshalString(v any) string {
 // Unmarshal 将 JSON 字节切片反序列化到目标对象。
 func Unmarshal(data []byte, v any) error {
 	if err := json.Unmarshal(data, v); err != nil {
-		return logger.ErrorfE("jsonutil: unmarshal 失败: %v", err)
+		return log.ErrorfE("jsonutil: unmarshal 失败: %v", err)
 	}
 	return nil
 }
@@ -78,7 +81,7 @@ func UnmarshalString(s string, v any) error {
 func ToMap(data []byte) (map[string]any, error) {
 	var m map[string]any
 	if err := json.Unmarshal(data, &m); err != nil {
-		return nil, logger.ErrorfE("jsonutil: 解析为 map 失败: %v", err)
+		return nil, log.ErrorfE("jsonutil: 解析为 map 失败: %v", err)
 	}
 	return m, nil
 }
@@ -89,32 +92,54 @@ func ToMapFromString(s string) (map[string]any, error) {
 }
 
 // ReadFile 读取 JSON 文件并反序列化到目标对象。
-func ReadFile(path string, v any) error {
+// opts 可选 WithStrict（拒绝未知字段）、WithUseNumber（数字保留为 json.Number）。
+//
+// 用法：
+//
+//	err := jsonutil.ReadFile("config.json", &cfg, jsonutil.WithStrict())
+func ReadFile(path string, v any, opts ...DecodeOption) error {
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return logger.ErrorfE("jsonutil: 读取文件 [%s] 失败: %v", path, err)
+		return log.ErrorfE("jsonutil: 读取文件 [%s] 失败: %v", path, err)
 	}
-	if err = json.Unmarshal(data, v); err != nil {
-		return logger.ErrorfE("jsonutil: 解析文件 [%s] 失败: %v", path, err)
+	if err = decodeWith(data, v, opts); err != nil {
+		return log.ErrorfE("jsonutil: 解析文件 [%s] 失败: %v", path, err)
 	}
 	return nil
 }
 
 // WriteFile 将任意值序列化为带缩进的 JSON 并写入文件。
-// 文件权限为 0644，已存在则覆盖。
-func WriteFile(path string, v any) error {
-	data, err := json.MarshalIndent(v, "", "  ")
+// 文件权限为 0644，已存在则原地覆盖；配置文件等不能损坏的场景使用 WriteFileAtomic。
+// opts 可选序列化选项（排序 key、不转义 HTML 等），Indent 为空时使用两个空格缩进。
+//
+// 用法：
+//
+//	err := jsonutil.WriteFile("config.json", cfg, jsonutil.MarshalOptions{SortKeys: true, DisableHTMLEscape: true})
+func WriteFile(path string, v any, opts ...MarshalOptions) error {
+	data, err := marshalFile(v, opts)
 	if err != nil {
-		return logger.ErrorfE("jsonutil: 序列化失败: %v", err)
+		return log.ErrorfE("jsonutil: 序列化失败: %v", err)
 	}
 	// 追加换行符，符合 POSIX 文件规范
 	data = append(data, '\n')
 	if err = os.WriteFile(path, data, 0644); err != nil {
-		return logger.ErrorfE("jsonutil: 写入文件 [%s] 失败: %v", path, err)
+		return log.ErrorfE("jsonutil: 写入文件 [%s] 失败: %v", path, err)
 	}
 	return nil
 }
 
+// marshalFile 按写文件的默认值（两个空格缩进）序列化，opts 只取第一个。
+func marshalFile(v any, opts []MarshalOptions) ([]byte, error) {
+	var o MarshalOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.Indent == "" {
+		o.Indent = "  "
+	}
+	return marshalWith(v, o)
+}
+
 // IsValid 检查字节切片是否为合法的 JSON。
 func IsValid(data []byte) bool {
 	return json.Valid(data)
@@ -143,59 +168,56 @@ func GetString(m map[string]any, key string) string {
 // JSON 数字默认反序列化为 float64，此函数自动处理转换。
 // key 不存在或类型不匹配时返回 0。
 func GetInt(m map[string]any, key string) int {
-	v, ok := m[key]
-	if !ok {
-		return 0
-	}
+	n, _ := toInt(m[key])
+	return n
+}
+
+// GetFloat64 从 map[string]any 中安全取出 float64 值。
+// key 不存在或类型不匹配时返回 0。
+func GetFloat64(m map[string]any, key string) float64 {
+	f, _ := toFloat64(m[key])
+	return f
+}
+
+// GetBool 从 map[string]any 中安全取出 bool 值。
+// key 不存在或类型不匹配时返回 false。
+func GetBool(m map[string]any, key string) bool {
+	b, _ := m[key].(bool)
+	return b
+}
+
+// toInt 将 JSON 数字（float64 / int / json.Number）转换为 int。
+func toInt(v any) (int, bool) {
 	switch n := v.(type) {
 	case float64:
-		return int(n)
+		return int(n), true
 	case int:
-		return n
+		return n, true
 	case json.Number:
 		i, err := n.Int64()
 		if err != nil {
-			return 0
+			return 0, false
 		}
-		return int(i)
+		return int(i), true
 	default:
-		return 0
+		return 0, false
 	}
 }
 
-// GetFloat64 从 map[string]any 中安全取出 float64 值。
-// key 不存在或类型不匹配时返回 0。
-func GetFloat64(m map[string]any, key string) float64 {
-	v, ok := m[key]
-	if !ok {
-		return 0
-	}
+// toFloat64 将 JSON 数字（float64 / int / json.Number）转换为 float64。
+func toFloat64(v any) (float64, bool) {
 	switch n := v.(type) {
 	case float64:
-		return n
+		return n, true
 	case int:
-		return float64(n)
+		return float64(n), true
 	case json.Number:
 		f, err := n.Float64()
 		if err != nil {
-			return 0
+			return 0, false
 		}
-		return f
+		return f, true
 	default:
-		return 0
+		return 0, false
 	}
 }
-
-// GetBool 从 map[string]any 中安全取出 bool 值。
-// key 不存在或类型不匹配时返回 false。
-func GetBool(m map[string]any, key string) bool {
-	v, ok := m[key]
-	if !ok {
-		return false
-	}
-	b, ok := v.(bool)
-	if !ok {
-		return false
-	}
-	return b
-}
@@ -0,0 +1,82 @@
+package jsonutil
+
+import "os"
+
+// ReadConfigFile 读取人工编辑的 JSON 配置文件：允许 // 行注释、/* */ 块注释和对象 / 数组末尾的多余逗号，
+// 预处理后再按 ReadFile 相同的规则反序列化（opts 同 ReadFile，建议配合 WithStrict 检查字段拼写）。
+// 数据文件请继续使用 ReadFile，保持标准 JSON 的严格校验。
+//
+// 用法：
+//
+//	// config.json:
+//	// {
+//	//     // 服务端口
+//	//     "port": 8080,
+//	//     "hosts": ["a", "b",],
+//	// }
+//	err := jsonutil.ReadConfigFile("config.json", &cfg, jsonutil.WithStrict())
+func ReadConfigFile(path string, v any, opts ...DecodeOption) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return log.ErrorfE("jsonutil: 读取文件 [%s] 失败: %v", path, err)
+	}
+	if err = decodeWith(StripJSONC(data), v, opts); err != nil {
+		return log.ErrorfE("jsonutil: 解析配置文件 [%s] 失败: %v", path, err)
+	}
+	return nil
+}
+
+// StripJSONC 将带注释和末尾逗号的 JSON 转换为标准 JSON。
+// 注释和多余逗号替换为空格（保留换行），输出长度与输入相同，解析错误的偏移量仍对应原文位置。
+func StripJSONC(data []byte) []byte {
+	out := make([]byte, len(data))
+	copy(out, data)
+
+	lastComma := -1 // 最近一个尚未确认的逗号位置（其后只有空白 / 注释）
+	for i := 0; i < len(out); i++ {
+		switch c := out[i]; {
+		case c == '"':
+			lastComma = -1
+			i = skipString(out, i)
+		case c == '/' && i+1 < len(out) && out[i+1] == '/':
+			for ; i < len(out) && out[i] != '\n'; i++ {
+				out[i] = ' '
+			}
+		case c == '/' && i+1 < len(out) && out[i+1] == '*':
+			start := i
+			for i += 2; i+1 < len(out) && !(out[i] == '*' && out[i+1] == '/'); i++ {
+			}
+			end := min(i+2, len(out))
+			for j := start; j < end; j++ {
+				if out[j] != '\n' {
+					out[j] = ' '
+				}
+			}
+			i = end - 1
+		case c == ',':
+			lastComma = i
+		case c == '}' || c == ']':
+			if lastComma >= 0 {
+				out[lastComma] = ' '
+			}
+			lastComma = -1
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+		default:
+			lastComma = -1
+		}
+	}
+	return out
+}
+
+// skipString 返回从 data[start]（开头的引号）开始的字符串结尾引号的位置，处理转义字符。
+func skipString(data []byte, start int) int {
+	for i := start + 1; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return len(data) - 1
+}
@@ -0,0 +1,72 @@
+package jsonutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// StripJSONC / ReadConfigFile
+// ---------------------------------------------------------------------------
+
+func TestStripJSONC(t *testing.T) {
+	tests := []struct {
+		name, input, want string
+	}{
+		{"line comment", "{\"a\": 1 // note\n}", `{"a":1}`},
+		{"block comment", `{/* x */"a": /* y */ 1}`, `{"a":1}`},
+		{"trailing comma object", `{"a": 1, "b": 2,}`, `{"a":1,"b":2}`},
+		{"trailing comma array", `[1, 2, ]`, `[1,2]`},
+		{"trailing comma before comment", "{\"a\": [1,], // c\n}", `{"a":[1]}`},
+		{"comment markers in string", `{"url": "http://x/*y*/", "s": "a,]"}`, `{"s":"a,]","url":"http://x/*y*/"}`},
+		{"escaped quote", `{"q": "say \"//hi\"",}`, `{"q":"say \"//hi\""}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stripped := StripJSONC([]byte(tt.input))
+			if len(stripped) != len(tt.input) {
+				t.Errorf("length changed: %d -> %d", len(tt.input), len(stripped))
+			}
+			got, err := Canonicalize(stripped)
+			if err != nil {
+				t.Fatalf("stripped output is not valid JSON: %q: %v", stripped, err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadConfigFile(t *testing.T) {
+	type Config struct {
+		Port  int      `json:"port"`
+		Hosts []string `json:"hosts"`
+	}
+	path := filepath.Join(t.TempDir(), "config.json")
+	content := `{
+	// 服务端口
+	"port": 8080,
+	/* 多行
+	   注释 */
+	"hosts": ["a", "b",],
+}
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg Config
+	if err := ReadConfigFile(path, &cfg, WithStrict()); err != nil {
+		t.Fatalf("ReadConfigFile: %v", err)
+	}
+	if cfg.Port != 8080 || len(cfg.Hosts) != 2 {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+
+	// 数据文件读取保持严格
+	if err := ReadFile(path, &cfg); err == nil {
+		t.Error("ReadFile should reject comments and trailing commas")
+	}
+}
@@ -0,0 +1,92 @@
+package jsonutil
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// MarshalOptions 序列化选项，零值与 Marshal 行为一致（紧凑输出、转义 HTML 字符）。
+type MarshalOptions struct {
+	SortKeys          bool   // 对象 key（含结构体字段）按字典序排序，输出稳定，便于 diff
+	DisableHTMLEscape bool   // 不将 &、<、> 转义为 \u0026 等，URL 等内容保持原样
+	Indent            string // 缩进字符串（如 "  "、"\t"），为空时紧凑输出
+	OmitEmptyMaps     bool   // 递归删除值为空对象 {} 的字段（删除后变空的父对象也会删除）
+}
+
+// MarshalWith 按 opts 将任意值序列化为 JSON 字节切片（末尾不带换行）。
+// SortKeys / OmitEmptyMaps 需要先序列化再解析一次，数字以 json.Number 保留原始精度。
+//
+// 用法：
+//
+//	data, err := jsonutil.MarshalWith(cfg, jsonutil.MarshalOptions{
+//	    SortKeys:          true,
+//	    DisableHTMLEscape: true,
+//	    Indent:            "  ",
+//	})
+func MarshalWith(v any, opts MarshalOptions) ([]byte, error) {
+	data, err := marshalWith(v, opts)
+	if err != nil {
+		return nil, log.ErrorfE("jsonutil: marshal 失败: %v", err)
+	}
+	return data, nil
+}
+
+// MarshalStringWith 按 opts 将任意值序列化为 JSON 字符串。
+func MarshalStringWith(v any, opts MarshalOptions) (string, error) {
+	data, err := MarshalWith(v, opts)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// marshalWith MarshalWith 的实现，不记录日志。
+func marshalWith(v any, opts MarshalOptions) ([]byte, error) {
+	if opts.SortKeys || opts.OmitEmptyMaps {
+		// 经 map 中转：encoding/json 输出 map 时按 key 排序
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		if v, err = decodeUseNumber(raw); err != nil {
+			return nil, err
+		}
+		if opts.OmitEmptyMaps {
+			v = omitEmptyMaps(v)
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(!opts.DisableHTMLEscape)
+	if opts.Indent != "" {
+		enc.SetIndent("", opts.Indent)
+	}
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// omitEmptyMaps 递归删除值为空对象的字段，数组元素保持不变（只处理其内部）。
+func omitEmptyMaps(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, item := range t {
+			item = omitEmptyMaps(item)
+			if m, ok := item.(map[string]any); ok && len(m) == 0 {
+				delete(t, k)
+				continue
+			}
+			t[k] = item
+		}
+		return t
+	case []any:
+		for i, item := range t {
+			t[i] = omitEmptyMaps(item)
+		}
+		return t
+	default:
+		return v
+	}
+}
@@ -0,0 +1,73 @@
+package jsonutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMarshalWith(t *testing.T) {
+	type item struct {
+		Zeta  string         `json:"zeta"`
+		Alpha int            `json:"alpha"`
+		Extra map[string]any `json:"extra"`
+	}
+	v := item{Zeta: "https://a.com/?x=1&y=<2>", Alpha: 1, Extra: map[string]any{"empty": map[string]any{}, "n": 1}}
+
+	tests := []struct {
+		name string
+		opts MarshalOptions
+		want string
+	}{
+		{"default", MarshalOptions{},
+			`{"zeta":"https://a.com/?x=1\u0026y=\u003c2\u003e","alpha":1,"extra":{"empty":{},"n":1}}`},
+		{"sort keys", MarshalOptions{SortKeys: true},
+			`{"alpha":1,"extra":{"empty":{},"n":1},"zeta":"https://a.com/?x=1\u0026y=\u003c2\u003e"}`},
+		{"no html escape", MarshalOptions{DisableHTMLEscape: true},
+			`{"zeta":"https://a.com/?x=1&y=<2>","alpha":1,"extra":{"empty":{},"n":1}}`},
+		{"omit empty maps", MarshalOptions{OmitEmptyMaps: true, DisableHTMLEscape: true},
+			`{"alpha":1,"extra":{"n":1},"zeta":"https://a.com/?x=1&y=<2>"}`},
+		{"indent", MarshalOptions{SortKeys: true, Indent: "\t"},
+			"{\n\t\"alpha\": 1,\n\t\"extra\": {\n\t\t\"empty\": {},\n\t\t\"n\": 1\n\t},\n\t\"zeta\": \"https://a.com/?x=1\\u0026y=\\u003c2\\u003e\"\n}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MarshalStringWith(v, tt.opts)
+			if err != nil {
+				t.Fatalf("MarshalStringWith: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOmitEmptyMapsCascade(t *testing.T) {
+	got, err := MarshalStringWith(map[string]any{"a": map[string]any{"b": map[string]any{}}, "c": []any{map[string]any{}}}, MarshalOptions{OmitEmptyMaps: true})
+	if err != nil {
+		t.Fatalf("MarshalStringWith: %v", err)
+	}
+	if want := `{"c":[{}]}`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestWriteFileWithOptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cfg.json")
+	if err := WriteFile(path, map[string]string{"url": "a?b=1&c=2"}, MarshalOptions{DisableHTMLEscape: true}); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if want := "{\n  \"url\": \"a?b=1&c=2\"\n}\n"; string(data) != want {
+		t.Errorf("file = %q, want %q", data, want)
+	}
+
+	if err := WriteFileAtomic(path, map[string]int{"b": 1, "a": 2}, &WriteOptions{MarshalOptions: MarshalOptions{Indent: "\t"}}); err != nil {
+		t.Fatalf("WriteFileAtomic: %v", err)
+	}
+	data, _ = os.ReadFile(path)
+	if want := "{\n\t\"a\": 2,\n\t\"b\": 1\n}\n"; string(data) != want {
+		t.Errorf("file = %q, want %q", data, want)
+	}
+}
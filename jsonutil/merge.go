@@ -0,0 +1,143 @@
+package jsonutil
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// ArrayMergeStrategy MergeMaps 遇到两侧都是数组时的合并策略。
+type ArrayMergeStrategy int
+
+const (
+	ArrayReplace      ArrayMergeStrategy = iota // src 数组整体替换 dst 数组（默认）
+	ArrayAppend                                 // src 数组元素追加到 dst 数组之后
+	ArrayMergeByIndex                           // 按下标逐项合并，对象元素递归合并，其余以 src 为准
+)
+
+// MergeOptions MergeMaps 选项。
+type MergeOptions struct {
+	Arrays  ArrayMergeStrategy // 数组合并策略
+	SkipNil bool               // 为 true 时 src 中的 null 不覆盖 dst 的值
+}
+
+// MergeMaps 将 src 深度合并到 dst 并返回 dst（dst 为 nil 时新建）。
+// 两侧都是对象时递归合并，数组按 opts.Arrays 处理，其余类型以 src 为准。
+// src 中的值会被深拷贝，合并后修改 dst 不影响 src。opts 为 nil 时使用默认选项。
+//
+// 用法：
+//
+//	base, _ := jsonutil.ToMap(baseJSON)
+//	overlay, _ := jsonutil.ToMap(prodJSON)
+//	cfg := jsonutil.MergeMaps(base, overlay, &jsonutil.MergeOptions{Arrays: jsonutil.ArrayAppend})
+func MergeMaps(dst, src map[string]any, opts *MergeOptions) map[string]any {
+	if opts == nil {
+		opts = &MergeOptions{}
+	}
+	if dst == nil {
+		dst = make(map[string]any, len(src))
+	}
+	for k, sv := range src {
+		if sv == nil && opts.SkipNil {
+			continue
+		}
+		dst[k] = mergeValue(dst[k], sv, opts)
+	}
+	return dst
+}
+
+// mergeValue 合并单个值，返回合并结果。
+func mergeValue(dv, sv any, opts *MergeOptions) any {
+	switch s := sv.(type) {
+	case map[string]any:
+		if d, ok := dv.(map[string]any); ok {
+			return MergeMaps(d, s, opts)
+		}
+	case []any:
+		if d, ok := dv.([]any); ok {
+			switch opts.Arrays {
+			case ArrayAppend:
+				return append(d, deepCopy(s).([]any)...)
+			case ArrayMergeByIndex:
+				for i, item := range s {
+					if i < len(d) {
+						d[i] = mergeValue(d[i], item, opts)
+					} else {
+						d = append(d, deepCopy(item))
+					}
+				}
+				return d
+			}
+		}
+	}
+	return deepCopy(sv)
+}
+
+// ApplyMergePatch 按 RFC 7386 (JSON Merge Patch) 将 patch 应用到 doc，返回新文档。
+// patch 中值为 null 的字段会被删除，对象递归合并，其余值（含数组）整体替换。
+// 数字按原文保留（不经 float64 转换），大整数不会丢失精度。
+//
+// 用法：
+//
+//	out, err := jsonutil.ApplyMergePatch(cfg, []byte(`{"log":{"level":"debug"},"proxy":null}`))
+func ApplyMergePatch(doc, patch []byte) ([]byte, error) {
+	target, err := decodeUseNumber(doc)
+	if err != nil {
+		return nil, log.ErrorfE("jsonutil: 解析原文档失败: %v", err)
+	}
+	p, err := decodeUseNumber(patch)
+	if err != nil {
+		return nil, log.ErrorfE("jsonutil: 解析 merge patch 失败: %v", err)
+	}
+	return Marshal(mergePatch(target, p))
+}
+
+// mergePatch RFC 7386 MergePatch 算法。
+func mergePatch(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = make(map[string]any, len(p))
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = mergePatch(t[k], v)
+	}
+	return t
+}
+
+// decodeUseNumber 解析 JSON，数字解析为 json.Number。
+func decodeUseNumber(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// deepCopy 深拷贝 JSON 值（map[string]any / []any 递归复制，其余类型原样返回）。
+func deepCopy(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(t))
+		for k, item := range t {
+			m[k] = deepCopy(item)
+		}
+		return m
+	case []any:
+		s := make([]any, len(t))
+		for i, item := range t {
+			s[i] = deepCopy(item)
+		}
+		return s
+	default:
+		return v
+	}
+}
@@ -0,0 +1,119 @@
+package jsonutil
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// MergeMaps
+// ---------------------------------------------------------------------------
+
+// mustMap 解析 JSON 为 map，失败时终止测试。
+func mustMap(t *testing.T, s string) map[string]any {
+	t.Helper()
+	m, err := ToMapFromString(s)
+	if err != nil {
+		t.Fatalf("ToMapFromString(%s): %v", s, err)
+	}
+	return m
+}
+
+// assertJSONEqual 比较两个值序列化后的 JSON（map key 有序）。
+func assertJSONEqual(t *testing.T, got any, want string) {
+	t.Helper()
+	var w any
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatalf("invalid want JSON %s: %v", want, err)
+	}
+	gb, _ := json.Marshal(got)
+	wb, _ := json.Marshal(w)
+	if string(gb) != string(wb) {
+		t.Errorf("got %s, want %s", gb, wb)
+	}
+}
+
+func TestMergeMaps(t *testing.T) {
+	base := `{"name":"svc","server":{"port":80,"hosts":["a"]},"tags":["x"],"debug":true}`
+	overlay := `{"server":{"port":8080,"hosts":["b"]},"tags":["y"],"debug":null}`
+
+	tests := []struct {
+		name string
+		opts *MergeOptions
+		want string
+	}{
+		{"replace", nil, `{"name":"svc","server":{"port":8080,"hosts":["b"]},"tags":["y"],"debug":null}`},
+		{"append", &MergeOptions{Arrays: ArrayAppend}, `{"name":"svc","server":{"port":8080,"hosts":["a","b"]},"tags":["x","y"],"debug":null}`},
+		{"skip nil", &MergeOptions{SkipNil: true}, `{"name":"svc","server":{"port":8080,"hosts":["b"]},"tags":["y"],"debug":true}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MergeMaps(mustMap(t, base), mustMap(t, overlay), tt.opts)
+			assertJSONEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestMergeMapsByIndex(t *testing.T) {
+	dst := mustMap(t, `{"items":[{"a":1,"b":2},{"a":3}]}`)
+	src := mustMap(t, `{"items":[{"b":20},{"c":4},{"a":5}]}`)
+	got := MergeMaps(dst, src, &MergeOptions{Arrays: ArrayMergeByIndex})
+	assertJSONEqual(t, got, `{"items":[{"a":1,"b":20},{"a":3,"c":4},{"a":5}]}`)
+}
+
+func TestMergeMapsDeepCopy(t *testing.T) {
+	src := mustMap(t, `{"inner":{"k":"v"}}`)
+	got := MergeMaps(nil, src, nil)
+	got["inner"].(map[string]any)["k"] = "changed"
+	if GetStringPath(src, "inner.k") != "v" {
+		t.Error("modifying merge result changed src")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// ApplyMergePatch (RFC 7386 附录 A 用例)
+// ---------------------------------------------------------------------------
+
+func TestApplyMergePatch(t *testing.T) {
+	tests := []struct {
+		doc, patch, want string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.doc+" + "+tt.patch, func(t *testing.T) {
+			out, err := ApplyMergePatch([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("ApplyMergePatch: %v", err)
+			}
+			var got any
+			if err := json.Unmarshal(out, &got); err != nil {
+				t.Fatalf("invalid output %s: %v", out, err)
+			}
+			assertJSONEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestApplyMergePatchPreservesLargeInt(t *testing.T) {
+	out, err := ApplyMergePatch([]byte(`{"id":9007199254740993}`), []byte(`{"name":"x"}`))
+	if err != nil {
+		t.Fatalf("ApplyMergePatch: %v", err)
+	}
+	if want := `{"id":9007199254740993,"name":"x"}`; string(out) != want {
+		t.Errorf("got %s, want %s", out, want)
+	}
+}
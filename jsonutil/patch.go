@@ -0,0 +1,326 @@
+package jsonutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PatchOp RFC 6902 JSON Patch 中的单个操作。
+type PatchOp struct {
+	Op    string          `json:"op"`             // add / remove / replace / move / copy / test
+	Path  string          `json:"path"`           // JSON Pointer (RFC 6901)
+	From  string          `json:"from,omitempty"` // move / copy 的源路径
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// ApplyJSONPatch 按 RFC 6902 (JSON Patch) 将 patch（操作数组）依次应用到 doc，返回新文档。
+// 任一操作失败（路径不存在、test 不匹配等）时返回错误，doc 不受影响。
+//
+// 用法：
+//
+//	out, err := jsonutil.ApplyJSONPatch(doc, []byte(`[
+//	    {"op": "replace", "path": "/server/port", "value": 8080},
+//	    {"op": "add", "path": "/server/hosts/-", "value": "10.0.0.2"},
+//	    {"op": "remove", "path": "/debug"}
+//	]`))
+func ApplyJSONPatch(doc, patch []byte) ([]byte, error) {
+	var ops []PatchOp
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, log.ErrorfE("jsonutil: 解析 JSON Patch 失败: %v", err)
+	}
+	root, err := decodeUseNumber(doc)
+	if err != nil {
+		return nil, log.ErrorfE("jsonutil: 解析原文档失败: %v", err)
+	}
+
+	for i, op := range ops {
+		if root, err = applyPatchOp(root, op); err != nil {
+			return nil, log.ErrorfE("jsonutil: JSON Patch 第 %d 个操作 (%s %s) 失败: %v", i, op.Op, op.Path, err)
+		}
+	}
+	return Marshal(root)
+}
+
+// applyPatchOp 应用单个操作，返回新的根节点。
+func applyPatchOp(root any, op PatchOp) (any, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, fmt.Errorf("缺少 value")
+		}
+		value, err := decodeUseNumber(op.Value)
+		if err != nil {
+			return nil, fmt.Errorf("解析 value 失败: %w", err)
+		}
+		switch op.Op {
+		case "add":
+			return addAt(root, path, value)
+		case "replace":
+			return replaceAt(root, path, value)
+		default:
+			cur, err := getAt(root, path)
+			if err != nil {
+				return nil, err
+			}
+			if !jsonEqual(cur, value) {
+				return nil, fmt.Errorf("test 不匹配")
+			}
+			return root, nil
+		}
+	case "remove":
+		root, _, err = removeAt(root, path)
+		return root, err
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, fmt.Errorf("from: %w", err)
+		}
+		if op.Op == "copy" {
+			value, err := getAt(root, from)
+			if err != nil {
+				return nil, err
+			}
+			return addAt(root, path, deepCopy(value))
+		}
+		if isPrefix(from, path) && len(from) < len(path) {
+			return nil, fmt.Errorf("不能移动到自身的子路径")
+		}
+		root, value, err := removeAt(root, from)
+		if err != nil {
+			return nil, err
+		}
+		return addAt(root, path, value)
+	default:
+		return nil, fmt.Errorf("不支持的操作 %q", op.Op)
+	}
+}
+
+// parsePointer 解析 RFC 6901 JSON Pointer，"" 表示根节点。
+func parsePointer(p string) ([]string, error) {
+	if p == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(p, "/") {
+		return nil, fmt.Errorf("非法的 JSON Pointer %q", p)
+	}
+	tokens := strings.Split(p[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// getAt 取 path 处的值。
+func getAt(node any, path []string) (any, error) {
+	for _, key := range path {
+		switch n := node.(type) {
+		case map[string]any:
+			v, ok := n[key]
+			if !ok {
+				return nil, fmt.Errorf("路径 %q 不存在", key)
+			}
+			node = v
+		case []any:
+			i, err := arrayIndex(key, len(n))
+			if err != nil {
+				return nil, err
+			}
+			node = n[i]
+		default:
+			return nil, fmt.Errorf("路径 %q 的父节点不是对象或数组", key)
+		}
+	}
+	return node, nil
+}
+
+// modifyAt 定位 path 的父节点并调用 leaf 修改，返回新的根节点（数组插入 / 删除会产生新切片）。
+func modifyAt(node any, path []string, leaf func(parent any, key string) (any, error)) (any, error) {
+	key := path[0]
+	if len(path) == 1 {
+		return leaf(node, key)
+	}
+	switch n := node.(type) {
+	case map[string]any:
+		child, ok := n[key]
+		if !ok {
+			return nil, fmt.Errorf("路径 %q 不存在", key)
+		}
+		c, err := modifyAt(child, path[1:], leaf)
+		if err != nil {
+			return nil, err
+		}
+		n[key] = c
+		return n, nil
+	case []any:
+		i, err := arrayIndex(key, len(n))
+		if err != nil {
+			return nil, err
+		}
+		c, err := modifyAt(n[i], path[1:], leaf)
+		if err != nil {
+			return nil, err
+		}
+		n[i] = c
+		return n, nil
+	default:
+		return nil, fmt.Errorf("路径 %q 的父节点不是对象或数组", key)
+	}
+}
+
+// addAt 在 path 处添加值：对象设置字段，数组在下标处插入（"-" 表示末尾）。
+func addAt(root any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return modifyAt(root, path, func(parent any, key string) (any, error) {
+		switch p := parent.(type) {
+		case map[string]any:
+			p[key] = value
+			return p, nil
+		case []any:
+			i := len(p)
+			if key != "-" {
+				var err error
+				if i, err = arrayIndex(key, len(p)+1); err != nil {
+					return nil, err
+				}
+			}
+			p = append(p, nil)
+			copy(p[i+1:], p[i:])
+			p[i] = value
+			return p, nil
+		default:
+			return nil, fmt.Errorf("路径 %q 的父节点不是对象或数组", key)
+		}
+	})
+}
+
+// replaceAt 替换 path 处已存在的值。
+func replaceAt(root any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return modifyAt(root, path, func(parent any, key string) (any, error) {
+		switch p := parent.(type) {
+		case map[string]any:
+			if _, ok := p[key]; !ok {
+				return nil, fmt.Errorf("路径 %q 不存在", key)
+			}
+			p[key] = value
+			return p, nil
+		case []any:
+			i, err := arrayIndex(key, len(p))
+			if err != nil {
+				return nil, err
+			}
+			p[i] = value
+			return p, nil
+		default:
+			return nil, fmt.Errorf("路径 %q 的父节点不是对象或数组", key)
+		}
+	})
+}
+
+// removeAt 删除 path 处的值，返回新的根节点和被删除的值。
+func removeAt(root any, path []string) (any, any, error) {
+	if len(path) == 0 {
+		return nil, nil, fmt.Errorf("不能删除根节点")
+	}
+	var removed any
+	root, err := modifyAt(root, path, func(parent any, key string) (any, error) {
+		switch p := parent.(type) {
+		case map[string]any:
+			v, ok := p[key]
+			if !ok {
+				return nil, fmt.Errorf("路径 %q 不存在", key)
+			}
+			removed = v
+			delete(p, key)
+			return p, nil
+		case []any:
+			i, err := arrayIndex(key, len(p))
+			if err != nil {
+				return nil, err
+			}
+			removed = p[i]
+			return append(p[:i], p[i+1:]...), nil
+		default:
+			return nil, fmt.Errorf("路径 %q 的父节点不是对象或数组", key)
+		}
+	})
+	return root, removed, err
+}
+
+// arrayIndex 解析数组下标，要求 0 <= i < n 且无前导零。
+func arrayIndex(key string, n int) (int, error) {
+	i, err := strconv.Atoi(key)
+	if err != nil || i < 0 || (len(key) > 1 && key[0] == '0') {
+		return 0, fmt.Errorf("非法的数组下标 %q", key)
+	}
+	if i >= n {
+		return 0, fmt.Errorf("数组下标 %d 越界（长度 %d）", i, n)
+	}
+	return i, nil
+}
+
+// isPrefix 判断 prefix 是否为 path 的前缀。
+func isPrefix(prefix, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// jsonEqual 按 JSON 语义比较两个值，数字按数值比较。
+func jsonEqual(a, b any) bool {
+	switch x := a.(type) {
+	case map[string]any:
+		y, ok := b.(map[string]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, v := range x {
+			w, ok := y[k]
+			if !ok || !jsonEqual(v, w) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		y, ok := b.([]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !jsonEqual(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		y, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		if x == y {
+			return true
+		}
+		fx, err1 := x.Float64()
+		fy, err2 := y.Float64()
+		return err1 == nil && err2 == nil && fx == fy
+	default:
+		return a == b
+	}
+}
@@ -0,0 +1,92 @@
+package jsonutil
+
+import (
+	"strconv"
+	"strings"
+)
+
+// GetPath 按路径从嵌套的 map[string]any / []any 中取值，路径不存在时返回 (nil, false)。
+// 路径以 "." 分隔，数组下标可写作 ".0" 或 "[0]"，可选的 "$." 前缀会被忽略。
+//
+// 用法：
+//
+//	m, _ := jsonutil.ToMapFromString(resp)
+//	v, ok := jsonutil.GetPath(m, "data.items.0.name")
+//	name := jsonutil.GetStringPath(m, "$.data.items[0].name")
+func GetPath(m map[string]any, path string) (any, bool) {
+	var cur any = m
+	for _, seg := range splitPath(path) {
+		switch node := cur.(type) {
+		case map[string]any:
+			v, ok := node[seg]
+			if !ok {
+				return nil, false
+			}
+			cur = v
+		case []any:
+			i, err := strconv.Atoi(seg)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			cur = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// GetStringPath 按路径取 string 值，路径不存在或类型不匹配时返回空串。
+func GetStringPath(m map[string]any, path string) string {
+	v, _ := GetPath(m, path)
+	s, _ := v.(string)
+	return s
+}
+
+// GetIntPath 按路径取整数值，路径不存在或类型不匹配时返回 0。
+func GetIntPath(m map[string]any, path string) int {
+	v, _ := GetPath(m, path)
+	n, _ := toInt(v)
+	return n
+}
+
+// GetFloat64Path 按路径取 float64 值，路径不存在或类型不匹配时返回 0。
+func GetFloat64Path(m map[string]any, path string) float64 {
+	v, _ := GetPath(m, path)
+	f, _ := toFloat64(v)
+	return f
+}
+
+// GetBoolPath 按路径取 bool 值，路径不存在或类型不匹配时返回 false。
+func GetBoolPath(m map[string]any, path string) bool {
+	v, _ := GetPath(m, path)
+	b, _ := v.(bool)
+	return b
+}
+
+// GetMapPath 按路径取嵌套对象，路径不存在或类型不匹配时返回 nil。
+func GetMapPath(m map[string]any, path string) map[string]any {
+	v, _ := GetPath(m, path)
+	sub, _ := v.(map[string]any)
+	return sub
+}
+
+// GetSlicePath 按路径取数组，路径不存在或类型不匹配时返回 nil。
+func GetSlicePath(m map[string]any, path string) []any {
+	v, _ := GetPath(m, path)
+	arr, _ := v.([]any)
+	return arr
+}
+
+// splitPath 将路径拆分为各级 key，"a.b[0].c" → ["a", "b", "0", "c"]。
+func splitPath(path string) []string {
+	path = strings.TrimPrefix(path, "$")
+	path = strings.NewReplacer("[", ".", "]", "").Replace(path)
+	var segs []string
+	for _, seg := range strings.Split(path, ".") {
+		if seg != "" {
+			segs = append(segs, seg)
+		}
+	}
+	return segs
+}
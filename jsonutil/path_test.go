@@ -0,0 +1,78 @@
+package jsonutil
+
+import "testing"
+
+// ---------------------------------------------------------------------------
+// GetPath / Get*Path
+// ---------------------------------------------------------------------------
+
+const pathDoc = `{
+	"data": {
+		"total": 2,
+		"ok": true,
+		"items": [
+			{"name": "alice", "score": 9.5},
+			{"name": "bob", "tags": ["x", "y"]}
+		]
+	}
+}`
+
+func TestGetPath(t *testing.T) {
+	m, err := ToMapFromString(pathDoc)
+	if err != nil {
+		t.Fatalf("ToMapFromString: %v", err)
+	}
+
+	tests := []struct {
+		path   string
+		want   any
+		wantOK bool
+	}{
+		{"data.total", float64(2), true},
+		{"data.items.0.name", "alice", true},
+		{"data.items[1].name", "bob", true},
+		{"$.data.items[1].tags[1]", "y", true},
+		{"data.items.2.name", nil, false},
+		{"data.items.-1", nil, false},
+		{"data.missing", nil, false},
+		{"data.total.x", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, ok := GetPath(m, tt.path)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("GetPath(%q) = (%v, %v), want (%v, %v)", tt.path, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGetPathTyped(t *testing.T) {
+	m, err := ToMapFromString(pathDoc)
+	if err != nil {
+		t.Fatalf("ToMapFromString: %v", err)
+	}
+
+	if got := GetStringPath(m, "data.items.1.name"); got != "bob" {
+		t.Errorf("GetStringPath = %q, want bob", got)
+	}
+	if got := GetIntPath(m, "data.total"); got != 2 {
+		t.Errorf("GetIntPath = %d, want 2", got)
+	}
+	if got := GetFloat64Path(m, "data.items.0.score"); got != 9.5 {
+		t.Errorf("GetFloat64Path = %v, want 9.5", got)
+	}
+	if !GetBoolPath(m, "data.ok") {
+		t.Error("GetBoolPath = false, want true")
+	}
+	if got := GetSlicePath(m, "data.items"); len(got) != 2 {
+		t.Errorf("GetSlicePath len = %d, want 2", len(got))
+	}
+	if got := GetMapPath(m, "data.items.0"); got["name"] != "alice" {
+		t.Errorf("GetMapPath = %v", got)
+	}
+	// 类型不匹配返回零值
+	if got := GetIntPath(m, "data.items.0.name"); got != 0 {
+		t.Errorf("GetIntPath on string = %d, want 0", got)
+	}
+}
@@ -0,0 +1,82 @@
+package jsonutil
+
+import "strings"
+
+// RedactMask 脱敏后替换字段值的占位符。
+const RedactMask = "***"
+
+// DefaultRedactFields 默认脱敏的字段名（Redact / RedactString 的 fields 为 nil 时使用）。
+var DefaultRedactFields = []string{
+	"password", "passwd", "pwd", "secret", "token",
+	"access_token", "refresh_token", "id_token", "api_key", "apikey",
+	"access_key", "secret_key", "sk", "ak", "authorization", "cookie",
+	"private_key", "client_secret", "credential", "credentials",
+}
+
+// Redact 返回 v 脱敏后的副本（map[string]any / []any 等通用结构），用于日志输出，v 本身不受影响。
+// 字段名按大小写不敏感匹配 fields（nil 时使用 DefaultRedactFields），命中的字段值（含嵌套对象）整体替换为 "***"。
+// v 无法序列化时返回 "***"，保证不会泄露原始数据。
+//
+// 用法：
+//
+//	logger.Info().Interface("req", jsonutil.Redact(req, nil)).Msg("收到请求")
+//	jsonutil.Redact(cfg, []string{"password", "dsn"})
+func Redact(v any, fields []string) any {
+	data, err := Marshal(v)
+	if err != nil {
+		return RedactMask
+	}
+	doc, err := decodeUseNumber(data)
+	if err != nil {
+		return RedactMask
+	}
+	return redactValue(doc, redactSet(fields))
+}
+
+// RedactString 对 JSON 字符串脱敏，返回紧凑格式的 JSON 字符串。
+// jsonStr 不是合法 JSON 时返回 "***"。
+func RedactString(jsonStr string, fields []string) string {
+	doc, err := decodeUseNumber([]byte(jsonStr))
+	if err != nil {
+		return RedactMask
+	}
+	s, err := MarshalString(redactValue(doc, redactSet(fields)))
+	if err != nil {
+		return RedactMask
+	}
+	return s
+}
+
+// redactSet 构建小写字段名集合。
+func redactSet(fields []string) map[string]struct{} {
+	if fields == nil {
+		fields = DefaultRedactFields
+	}
+	set := make(map[string]struct{}, len(fields))
+	for _, f := range fields {
+		set[strings.ToLower(f)] = struct{}{}
+	}
+	return set
+}
+
+// redactValue 递归替换命中字段的值（原地修改，调用方传入的是解码出的副本）。
+func redactValue(v any, set map[string]struct{}) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, item := range t {
+			if _, ok := set[strings.ToLower(k)]; ok {
+				t[k] = RedactMask
+				continue
+			}
+			t[k] = redactValue(item, set)
+		}
+		return t
+	case []any:
+		for i, item := range t {
+			t[i] = redactValue(item, set)
+		}
+		return t
+	default:
+		return v
+	}
+}
@@ -0,0 +1,52 @@
+package jsonutil
+
+import "testing"
+
+// ---------------------------------------------------------------------------
+// Redact / RedactString
+// ---------------------------------------------------------------------------
+
+func TestRedactString(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		fields []string
+		want   string
+	}{
+		{"default fields", `{"user":"a","password":"p","Token":"t"}`, nil, `{"Token":"***","password":"***","user":"a"}`},
+		{"nested", `{"db":{"dsn":"x","pool":{"secret":{"k":1}}},"list":[{"api_key":"k","id":1}]}`, nil,
+			`{"db":{"dsn":"x","pool":{"secret":"***"}},"list":[{"api_key":"***","id":1}]}`},
+		{"custom fields", `{"dsn":"x","password":"p"}`, []string{"DSN"}, `{"dsn":"***","password":"p"}`},
+		{"large number kept", `{"id":9007199254740993,"token":"t"}`, nil, `{"id":9007199254740993,"token":"***"}`},
+		{"invalid json", `{oops`, nil, `***`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RedactString(tt.input, tt.fields); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedact(t *testing.T) {
+	type Login struct {
+		User     string            `json:"user"`
+		Password string            `json:"password"`
+		Headers  map[string]string `json:"headers"`
+	}
+	in := Login{User: "alice", Password: "hunter2", Headers: map[string]string{"Authorization": "Bearer x", "Accept": "*/*"}}
+
+	got := MustMarshalString(Redact(in, nil))
+	want := `{"headers":{"Accept":"*/*","Authorization":"***"},"password":"***","user":"alice"}`
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if in.Password != "hunter2" || in.Headers["Authorization"] != "Bearer x" {
+		t.Error("Redact modified the input value")
+	}
+
+	if got := Redact(make(chan int), nil); got != RedactMask {
+		t.Errorf("unserializable value = %v, want %s", got, RedactMask)
+	}
+}
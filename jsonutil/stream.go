@@ -0,0 +1,48 @@
+package jsonutil
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// DecodeArrayStream 逐个元素流式解析顶层 JSON 数组，每个元素以 json.RawMessage 形式交给 fn。
+// 不会把整个数组读入内存，适合处理 GB 级的 API 导出文件。
+// fn 返回错误时立即停止并原样返回该错误；顶层不是数组或格式错误时返回解析错误。
+//
+// 用法：
+//
+//	f, _ := os.Open("dump.json")
+//	defer f.Close()
+//	err := jsonutil.DecodeArrayStream(bufio.NewReaderSize(f, 1<<20), func(raw json.RawMessage) error {
+//	    var item Item
+//	    if err := json.Unmarshal(raw, &item); err != nil {
+//	        return err
+//	    }
+//	    return handle(item)
+//	})
+func DecodeArrayStream(r io.Reader, fn func(json.RawMessage) error) error {
+	dec := json.NewDecoder(r)
+
+	tok, err := dec.Token()
+	if err != nil {
+		return log.ErrorfE("jsonutil: 读取数组起始失败: %v", err)
+	}
+	if d, ok := tok.(json.Delim); !ok || d != '[' {
+		return log.ErrorfE("jsonutil: 顶层不是 JSON 数组: %v", tok)
+	}
+
+	for i := 0; dec.More(); i++ {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return log.ErrorfE("jsonutil: 解析第 %d 个元素失败: %v", i, err)
+		}
+		if err := fn(raw); err != nil {
+			return err
+		}
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return log.ErrorfE("jsonutil: 读取数组结束失败: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package jsonutil
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// DecodeArrayStream
+// ---------------------------------------------------------------------------
+
+func TestDecodeArrayStream(t *testing.T) {
+	input := `[{"id":1},{"id":2,"tags":["a","b"]}, 3, "x", null]`
+	var got []string
+	err := DecodeArrayStream(strings.NewReader(input), func(raw json.RawMessage) error {
+		got = append(got, string(raw))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("DecodeArrayStream: %v", err)
+	}
+	want := []string{`{"id":1}`, `{"id":2,"tags":["a","b"]}`, `3`, `"x"`, `null`}
+	if len(got) != len(want) {
+		t.Fatalf("got %d elements, want %d: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("element %d = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
+
+func TestDecodeArrayStreamEmpty(t *testing.T) {
+	calls := 0
+	err := DecodeArrayStream(strings.NewReader(" [ ] "), func(json.RawMessage) error {
+		calls++
+		return nil
+	})
+	if err != nil || calls != 0 {
+		t.Fatalf("err=%v calls=%d, want nil and 0", err, calls)
+	}
+}
+
+func TestDecodeArrayStreamStop(t *testing.T) {
+	errStop := errors.New("stop")
+	calls := 0
+	err := DecodeArrayStream(strings.NewReader(`[1,2,3]`), func(json.RawMessage) error {
+		calls++
+		if calls == 2 {
+			return errStop
+		}
+		return nil
+	})
+	if !errors.Is(err, errStop) {
+		t.Fatalf("expected errStop, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+}
+
+func TestDecodeArrayStreamInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"object", `{"a":1}`},
+		{"truncated", `[1,2`},
+		{"bad element", `[1,}`},
+		{"empty", ``},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := DecodeArrayStream(strings.NewReader(tt.input), func(json.RawMessage) error { return nil })
+			if err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
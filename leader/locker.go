@@ -0,0 +1,30 @@
+package leader
+
+import (
+	"github.com/pylemonorg/gotools/db"
+	"github.com/pylemonorg/gotools/distlock"
+	"github.com/pylemonorg/gotools/obsutil"
+)
+
+// LockerFunc 按锁 key 创建 distlock.Locker，Runner 为每个任务名创建一个锁。
+// 锁实现了 distlock.Fenced 时，任务可通过 FencingToken 取得本次任期的 fencing token。
+type LockerFunc func(key string) distlock.Locker
+
+// RedisLockers 返回基于 distlock.RedisLocker 的 LockerFunc，fencing token 由 "{key}:fence" 计数器生成。
+func RedisLockers(client *db.RedisClient) LockerFunc {
+	return func(key string) distlock.Locker {
+		return distlock.NewRedisLocker(client, key)
+	}
+}
+
+// OBSLockers 返回基于 distlock.OBSLocker（条件写入）的 LockerFunc，锁 key 即对象路径。
+// OBS 锁不提供 fencing token，没有 Redis 时使用。
+//
+// 用法：
+//
+//	r, _ := leader.New(leader.OBSLockers(obsClient), leader.WithKeyPrefix("locks/leader/"))
+func OBSLockers(client *obsutil.ObsClient) LockerFunc {
+	return func(key string) distlock.Locker {
+		return distlock.NewOBSLocker(client, key)
+	}
+}
@@ -0,0 +1,225 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/pylemonorg/gotools/distlock"
+	"github.com/pylemonorg/gotools/hashutil"
+	"github.com/pylemonorg/gotools/logger"
+)
+
+// log leader 模块日志，可通过 logger.SetModuleLevel("leader", ...) 单独控制级别。
+var log = logger.Module("leader")
+
+// ErrNilLocker 表示未提供 LockerFunc。
+var ErrNilLocker = errors.New("leader: LockerFunc 不能为 nil")
+
+// Option 配置 Runner。
+type Option func(*Runner)
+
+// WithKeyPrefix 设置锁 key 前缀，默认 "leader:"。
+func WithKeyPrefix(prefix string) Option {
+	return func(r *Runner) { r.prefix = prefix }
+}
+
+// WithInstanceID 设置当前实例标识（用于日志），默认 "主机名-PID-随机串"。
+func WithInstanceID(id string) Option {
+	return func(r *Runner) { r.instanceID = id }
+}
+
+// WithLockTTL 设置锁过期时间，默认 30s；每 TTL/3 续期一次，同时也是非 leader 实例的竞选间隔。
+func WithLockTTL(ttl time.Duration) Option {
+	return func(r *Runner) {
+		if ttl > 0 {
+			r.ttl = ttl
+		}
+	}
+}
+
+// WithRunOnElected 成为 leader 后立即执行一次，而不是等待第一个 interval。
+// 注意：故障切换时可能导致同一周期内执行两次。
+func WithRunOnElected() Option {
+	return func(r *Runner) { r.runOnElected = true }
+}
+
+// WithMeter 启用 OpenTelemetry 指标（前缀 "leader."）：
+// 执行次数 leader.runs（按 job / result 区分）、执行耗时 leader.run.duration、
+// 当选次数 leader.elected、失去领导权次数 leader.lost。
+func WithMeter(meter metric.Meter) Option {
+	return func(r *Runner) { r.meter = meter }
+}
+
+// Runner 在多实例部署中保证同一个周期任务同一时刻只有一个实例执行（分布式单例）。
+// 实例通过 distlock 锁竞选 leader 并持续续期（见 distlock.WithLock）；只有 leader 按间隔执行任务，
+// 续期确认锁已丢失、或连续续期失败超过锁 TTL 时取消正在执行的任务。
+type Runner struct {
+	newLocker    LockerFunc
+	prefix       string
+	instanceID   string
+	ttl          time.Duration
+	runOnElected bool
+	meter        metric.Meter
+
+	runs     metric.Int64Counter
+	duration metric.Float64Histogram
+	elected  metric.Int64Counter
+	lost     metric.Int64Counter
+}
+
+// New 创建 Runner，newLocker 通常为 RedisLockers 或 OBSLockers。
+func New(newLocker LockerFunc, opts ...Option) (*Runner, error) {
+	if newLocker == nil {
+		return nil, ErrNilLocker
+	}
+	r := &Runner{newLocker: newLocker, prefix: "leader:", ttl: 30 * time.Second}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.instanceID == "" {
+		host, _ := os.Hostname()
+		r.instanceID = fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hashutil.RandomHex(8))
+	}
+	if r.meter != nil {
+		if err := r.initMetrics(); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// InstanceID 返回当前实例标识。
+func (r *Runner) InstanceID() string { return r.instanceID }
+
+// RunExclusive 阻塞运行周期任务 name，直到 ctx 结束（返回 nil）。
+// 当选 leader 后每隔 interval 执行一次 fn（上一次未结束时跳过该周期）；
+// fn 收到的 ctx 在失去领导权或 ctx 结束时取消，可通过 FencingToken 获取本次任期的 fencing token。
+//
+// 用法：
+//
+//	r, _ := leader.New(leader.RedisLockers(redisClient), leader.WithMeter(otel.Meter("app")))
+//	go r.RunExclusive(ctx, "daily-report", time.Hour, func(ctx context.Context) error {
+//	    token, _ := leader.FencingToken(ctx)
+//	    return buildReport(ctx, token)
+//	})
+func (r *Runner) RunExclusive(ctx context.Context, name string, interval time.Duration, fn func(ctx context.Context) error) error {
+	if interval <= 0 {
+		return fmt.Errorf("leader: 任务 [%s] 的执行间隔必须大于 0", name)
+	}
+	locker := r.newLocker(r.prefix + name)
+	retryEvery := r.ttl / 3
+
+	for {
+		err := distlock.WithLock(ctx, locker, r.ttl, func(leaderCtx context.Context) error {
+			token, _ := FencingToken(leaderCtx)
+			log.Infof("leader: [%s] 当选 leader（实例 %s，token %d）", name, r.instanceID, token)
+			r.add(ctx, r.elected, name)
+			r.lead(leaderCtx, name, interval, fn)
+			return nil
+		})
+		switch {
+		case err == nil, errors.Is(err, distlock.ErrNotAcquired):
+		case errors.Is(err, distlock.ErrLockLost):
+			log.Warnf("leader: [%s] 锁已丢失，失去领导权（实例 %s）", name, r.instanceID)
+			r.add(ctx, r.lost, name)
+		case ctx.Err() == nil:
+			log.Warnf("leader: [%s] 竞选失败: %v", name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(retryEvery):
+		}
+	}
+}
+
+// lead 作为 leader 按间隔执行任务，直到 leaderCtx 取消（失去领导权或 ctx 结束）。续期由 distlock.WithLock 负责。
+func (r *Runner) lead(leaderCtx context.Context, name string, interval time.Duration, fn func(ctx context.Context) error) {
+	if r.runOnElected {
+		r.runOnce(leaderCtx, name, fn)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-leaderCtx.Done():
+			return
+		case <-ticker.C:
+			r.runOnce(leaderCtx, name, fn)
+		}
+	}
+}
+
+// runOnce 执行一次任务，恢复 panic 并记录指标。
+func (r *Runner) runOnce(ctx context.Context, name string, fn func(ctx context.Context) error) {
+	start := time.Now()
+	err := func() (err error) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				err = fmt.Errorf("leader: 任务 panic: %v", rec)
+			}
+		}()
+		return fn(ctx)
+	}()
+
+	result := "ok"
+	if err != nil {
+		result = "error"
+		log.Errorf("leader: [%s] 执行失败: %v", name, err)
+	}
+	if r.meter != nil {
+		attrs := metric.WithAttributes(attribute.String("job", name), attribute.String("result", result))
+		r.runs.Add(context.WithoutCancel(ctx), 1, attrs)
+		r.duration.Record(context.WithoutCancel(ctx), time.Since(start).Seconds(), attrs)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// fencing token
+// ---------------------------------------------------------------------------
+
+// FencingToken 返回任务 ctx 中当前任期的 fencing token（锁未实现 distlock.Fenced 时返回 false）。
+// 下游存储应拒绝比已见过的更小的 token，防止旧 leader 在失去锁后继续写入。
+func FencingToken(ctx context.Context) (int64, bool) {
+	return distlock.FencingToken(ctx)
+}
+
+// ---------------------------------------------------------------------------
+// 指标
+// ---------------------------------------------------------------------------
+
+func (r *Runner) initMetrics() error {
+	var err error
+	if r.runs, err = r.meter.Int64Counter("leader.runs",
+		metric.WithDescription("任务执行次数"), metric.WithUnit("{run}")); err != nil {
+		return fmt.Errorf("leader: 创建指标 leader.runs 失败: %w", err)
+	}
+	if r.duration, err = r.meter.Float64Histogram("leader.run.duration",
+		metric.WithDescription("任务执行耗时"), metric.WithUnit("s")); err != nil {
+		return fmt.Errorf("leader: 创建指标 leader.run.duration 失败: %w", err)
+	}
+	if r.elected, err = r.meter.Int64Counter("leader.elected",
+		metric.WithDescription("当选 leader 次数"), metric.WithUnit("{election}")); err != nil {
+		return fmt.Errorf("leader: 创建指标 leader.elected 失败: %w", err)
+	}
+	if r.lost, err = r.meter.Int64Counter("leader.lost",
+		metric.WithDescription("失去领导权次数"), metric.WithUnit("{loss}")); err != nil {
+		return fmt.Errorf("leader: 创建指标 leader.lost 失败: %w", err)
+	}
+	return nil
+}
+
+// add 计数器 +1（未启用指标时忽略）。
+func (r *Runner) add(ctx context.Context, c metric.Int64Counter, name string) {
+	if r.meter == nil {
+		return
+	}
+	c.Add(context.WithoutCancel(ctx), 1, metric.WithAttributes(attribute.String("job", name)))
+}
@@ -0,0 +1,159 @@
+package leader
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pylemonorg/gotools/distlock"
+)
+
+// memStore 内存实现的锁存储，用于测试。
+type memStore struct {
+	mu      sync.Mutex
+	holder  map[string]string
+	expires map[string]time.Time
+	fence   int64
+}
+
+func newMemStore() *memStore {
+	return &memStore{holder: map[string]string{}, expires: map[string]time.Time{}}
+}
+
+// lockers 返回基于 memStore 的 LockerFunc。
+func (s *memStore) lockers() LockerFunc {
+	return func(key string) distlock.Locker { return &memLocker{s: s, key: key} }
+}
+
+// steal 模拟锁被其他实例抢占。
+func (s *memStore) steal(key string) {
+	s.mu.Lock()
+	s.holder[key] = "intruder"
+	s.expires[key] = time.Now().Add(time.Hour)
+	s.mu.Unlock()
+}
+
+type memLocker struct {
+	s   *memStore
+	key string
+}
+
+func (l *memLocker) Acquire(_ context.Context, ttl time.Duration) (distlock.Lock, error) {
+	s := l.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.holder[l.key] != "" && time.Now().Before(s.expires[l.key]) {
+		return nil, distlock.ErrNotAcquired
+	}
+	s.fence++
+	lock := &memLock{s: s, key: l.key, token: fmt.Sprint(s.fence), fence: s.fence}
+	s.holder[l.key] = lock.token
+	s.expires[l.key] = time.Now().Add(ttl)
+	return lock, nil
+}
+
+type memLock struct {
+	s     *memStore
+	key   string
+	token string
+	fence int64
+}
+
+func (l *memLock) Key() string         { return l.key }
+func (l *memLock) FencingToken() int64 { return l.fence }
+
+func (l *memLock) Renew(_ context.Context, ttl time.Duration) error {
+	l.s.mu.Lock()
+	defer l.s.mu.Unlock()
+	if l.s.holder[l.key] != l.token {
+		return distlock.ErrLockLost
+	}
+	l.s.expires[l.key] = time.Now().Add(ttl)
+	return nil
+}
+
+func (l *memLock) Release(context.Context) error {
+	l.s.mu.Lock()
+	defer l.s.mu.Unlock()
+	if l.s.holder[l.key] != l.token {
+		return distlock.ErrLockLost
+	}
+	delete(l.s.holder, l.key)
+	return nil
+}
+
+func TestRunExclusiveSingleLeader(t *testing.T) {
+	store := newMemStore()
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	var running, overlap, runs atomic.Int32
+	job := func(ctx context.Context) error {
+		if running.Add(1) > 1 {
+			overlap.Add(1)
+		}
+		defer running.Add(-1)
+		runs.Add(1)
+		if _, ok := FencingToken(ctx); !ok {
+			t.Error("job context should carry a fencing token")
+		}
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		r, err := New(store.lockers(), WithLockTTL(60*time.Millisecond))
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.RunExclusive(ctx, "job", 20*time.Millisecond, job)
+		}()
+	}
+	wg.Wait()
+
+	if overlap.Load() != 0 {
+		t.Errorf("job ran concurrently on %d occasions, want 0", overlap.Load())
+	}
+	if runs.Load() == 0 {
+		t.Error("job never ran")
+	}
+}
+
+func TestRunExclusiveLosesLeadership(t *testing.T) {
+	store := newMemStore()
+	r, err := New(store.lockers(), WithLockTTL(30*time.Millisecond), WithKeyPrefix("t:"), WithRunOnElected())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	canceled := make(chan struct{})
+	var once sync.Once
+	go r.RunExclusive(ctx, "job", time.Hour, func(jobCtx context.Context) error {
+		store.steal("t:job")
+		<-jobCtx.Done()
+		once.Do(func() { close(canceled) })
+		return nil
+	})
+
+	select {
+	case <-canceled:
+	case <-ctx.Done():
+		t.Fatal("job context was not canceled after the lock was stolen")
+	}
+}
+
+func TestNewRequiresLocker(t *testing.T) {
+	if _, err := New(nil); err != ErrNilLocker {
+		t.Errorf("New(nil) = %v, want ErrNilLocker", err)
+	}
+}
@@ -0,0 +1,191 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// AsyncConfig 异步写文件配置。
+type AsyncConfig struct {
+	QueueSize     int           // 队列长度（条），默认 10000；队列满时丢弃最旧的日志
+	FlushInterval time.Duration // 丢弃统计的输出间隔，默认 10s
+}
+
+// WithAsync 启用异步写文件（仅对 InitWithFile 的文件输出生效，控制台输出仍为同步）。
+// 日志先进入有界队列，由后台协程写入文件；Fatal 日志、Flush 和 Close 会等待队列写完。
+//
+// 用法：
+//
+//	logger.InitWithFile(logger.LevelInfo, false, "/logs/myapp", logger.WithAsync(logger.AsyncConfig{QueueSize: 50000}))
+//	defer logger.Close() // 退出前写完队列中的日志
+func WithAsync(cfg AsyncConfig) Option {
+	return func(o *options) {
+		o.async = &cfg
+	}
+}
+
+// asyncWriter 有界队列 + 后台写入的 io.Writer，队列满时丢弃最旧的日志。
+type asyncWriter struct {
+	out   io.Writer
+	ch    chan []byte
+	flush chan chan struct{}
+	done  chan struct{}
+
+	mu      sync.RWMutex
+	closed  bool
+	dropped atomic.Int64
+}
+
+// newAsyncWriter 创建并启动异步写入器。
+func newAsyncWriter(out io.Writer, cfg AsyncConfig) *asyncWriter {
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 10000
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = 10 * time.Second
+	}
+	w := &asyncWriter{
+		out:   out,
+		ch:    make(chan []byte, cfg.QueueSize),
+		flush: make(chan chan struct{}),
+		done:  make(chan struct{}),
+	}
+	go w.run(cfg.FlushInterval)
+	return w
+}
+
+// Write 实现 io.Writer，复制 p 后放入队列（zerolog 会复用缓冲区），从不阻塞。
+// 队列满时先丢弃最旧的一条再入队；关闭后直接同步写入。
+func (w *asyncWriter) Write(p []byte) (int, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return w.out.Write(p)
+	}
+
+	buf := make([]byte, len(p))
+	copy(buf, p)
+	for {
+		select {
+		case w.ch <- buf:
+			return len(p), nil
+		default:
+		}
+		// 队列已满，丢弃最旧的一条
+		select {
+		case <-w.ch:
+			w.dropped.Add(1)
+		default:
+		}
+	}
+}
+
+// Flush 等待队列中已有的日志全部写入。
+func (w *asyncWriter) Flush() {
+	w.mu.RLock()
+	if w.closed {
+		w.mu.RUnlock()
+		return
+	}
+	req := make(chan struct{})
+	w.flush <- req
+	w.mu.RUnlock()
+	<-req
+}
+
+// Close 写完队列中的日志后停止后台协程（不关闭底层 Writer）。可重复调用。
+func (w *asyncWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.ch)
+	w.mu.Unlock()
+
+	<-w.done
+	w.reportDropped()
+	return nil
+}
+
+// run 后台写入循环。
+func (w *asyncWriter) run(reportInterval time.Duration) {
+	defer close(w.done)
+
+	ticker := time.NewTicker(reportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case buf, ok := <-w.ch:
+			if !ok {
+				return
+			}
+			w.write(buf)
+		case req := <-w.flush:
+			for drained := false; !drained; {
+				select {
+				case buf, ok := <-w.ch:
+					if !ok { // Flush 等待期间 Close 关闭了队列，此时已全部写完
+						close(req)
+						return
+					}
+					w.write(buf)
+				default:
+					drained = true
+				}
+			}
+			close(req)
+		case <-ticker.C:
+			w.reportDropped()
+		}
+	}
+}
+
+// write 写入底层 Writer，失败时输出到 stderr。
+func (w *asyncWriter) write(buf []byte) {
+	if _, err := w.out.Write(buf); err != nil {
+		fmt.Fprintf(os.Stderr, "logger: 异步写日志失败: %v\n", err)
+	}
+}
+
+// reportDropped 输出并清零丢弃计数（输出到 stderr，避免写回已满的队列）。
+func (w *asyncWriter) reportDropped() {
+	if n := w.dropped.Swap(0); n > 0 {
+		fmt.Fprintf(os.Stderr, "logger: 异步队列已满，丢弃 %d 条最旧的日志\n", n)
+	}
+}
+
+// fatalFlushWriter 作为 root logger 的最后一个输出目标，Fatal / Panic 日志写入后立即 Flush 异步队列，
+// 避免 os.Exit 丢失日志。
+type fatalFlushWriter struct {
+	w *asyncWriter
+}
+
+// Write 实现 io.Writer。
+func (f fatalFlushWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+// WriteLevel 实现 zerolog.LevelWriter。
+func (f fatalFlushWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if level == zerolog.FatalLevel || level == zerolog.PanicLevel {
+		f.w.Flush()
+	}
+	return len(p), nil
+}
+
+// Flush 等待异步队列中的日志写入文件，并投递钩子缓冲中的日志。
+func Flush() {
+	if asyncFile != nil {
+		asyncFile.Flush()
+	}
+	hooks.flushAll()
+}
@@ -0,0 +1,189 @@
+package logger
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// 标准库桥接：让使用标准库 log / slog 的第三方库（http.Server.ErrorLog、数据库驱动等）
+// 的输出经过本包的输出目标、级别过滤和钩子，与业务日志格式一致。
+
+// ==================== 标准库 log ====================
+
+// NewStdLogger 返回输出到全局 logger 的标准库 *log.Logger，每次 Print 记录一条 info 级别日志。
+//
+// 用法：
+//
+//	srv := &http.Server{Addr: ":8080", ErrorLog: logger.NewStdLogger()}
+func NewStdLogger() *log.Logger {
+	return defaultLogger.StdLogger(LevelInfo)
+}
+
+// StdLogger 返回输出到 l 的标准库 *log.Logger（携带 l 的模块名和字段），每次 Print 按 level 记录一条日志。
+// 时间、前缀等由本包输出，返回的 *log.Logger 不带 flags；消息末尾的换行会被去掉。
+//
+// 用法：
+//
+//	srv := &http.Server{ErrorLog: logger.Module("http").StdLogger(logger.LevelWarn)}
+func (l *Logger) StdLogger(level string) *log.Logger {
+	return log.New(stdWriter{l: l, level: parseLevel(level)}, "", 0)
+}
+
+// stdWriter 将标准库 log 的每次输出转为一条日志。
+type stdWriter struct {
+	l     *Logger
+	level zerolog.Level
+}
+
+// Write 实现 io.Writer，标准库 log 每条消息只调用一次 Write。
+func (w stdWriter) Write(p []byte) (int, error) {
+	w.l.zl().WithLevel(w.level).Msg(strings.TrimRight(string(p), "\r\n"))
+	return len(p), nil
+}
+
+// ==================== log/slog ====================
+
+// NewSlogHandler 返回输出到全局 logger 的 slog.Handler，级别跟随全局配置。
+//
+// 用法：
+//
+//	slog.SetDefault(slog.New(logger.NewSlogHandler())) // 标准库 log 与 slog 的输出都转到本包
+//	slog.Info("连接成功", "addr", addr)
+func NewSlogHandler() slog.Handler {
+	return defaultLogger.SlogHandler()
+}
+
+// SlogHandler 返回输出到 l 的 slog.Handler（携带 l 的模块名和字段），级别跟随 l 的生效级别（含 SetModuleLevel）。
+// slog 级别映射为 debug / info / warn / error；属性作为结构化字段输出，分组（WithGroup / slog.Group）的 key 以 "." 连接。
+//
+// 用法：
+//
+//	l := slog.New(logger.Module("pgx").SlogHandler()).With("db", "orders")
+//	l.Warn("慢查询", "sql", query, "cost", cost)
+func (l *Logger) SlogHandler() slog.Handler {
+	return &slogHandler{l: l}
+}
+
+// slogHandler 基于 Logger 的 slog.Handler 实现。
+type slogHandler struct {
+	l      *Logger
+	prefix string      // WithGroup 累积的 key 前缀，如 "req.header."
+	attrs  []slogField // WithAttrs 预先附加的属性
+}
+
+// slogField 带分组前缀的属性。
+type slogField struct {
+	prefix string
+	attr   slog.Attr
+}
+
+// Enabled 实现 slog.Handler。
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	lvl := slogLevel(level)
+	return lvl >= h.l.zl().GetLevel() && lvl >= zerolog.GlobalLevel()
+}
+
+// Handle 实现 slog.Handler。时间由本包生成，忽略 Record.Time。
+func (h *slogHandler) Handle(_ context.Context, r slog.Record) error {
+	e := h.l.zl().WithLevel(slogLevel(r.Level))
+	if e == nil {
+		return nil
+	}
+	for _, f := range h.attrs {
+		appendSlogAttr(e, f.prefix, f.attr)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		appendSlogAttr(e, h.prefix, a)
+		return true
+	})
+	e.Msg(r.Message)
+	return nil
+}
+
+// WithAttrs 实现 slog.Handler。
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	d := *h
+	d.attrs = make([]slogField, len(h.attrs), len(h.attrs)+len(attrs))
+	copy(d.attrs, h.attrs)
+	for _, a := range attrs {
+		d.attrs = append(d.attrs, slogField{prefix: h.prefix, attr: a})
+	}
+	return &d
+}
+
+// WithGroup 实现 slog.Handler。
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	d := *h
+	d.prefix = h.prefix + name + "."
+	return &d
+}
+
+// slogLevel 将 slog 级别映射为 zerolog 级别（自定义级别向下取整到最近的标准级别）。
+func slogLevel(level slog.Level) zerolog.Level {
+	switch {
+	case level < slog.LevelInfo:
+		return zerolog.DebugLevel
+	case level < slog.LevelWarn:
+		return zerolog.InfoLevel
+	case level < slog.LevelError:
+		return zerolog.WarnLevel
+	default:
+		return zerolog.ErrorLevel
+	}
+}
+
+// appendSlogAttr 将属性写入事件，分组展开为 "prefix.group.key" 形式的字段。
+func appendSlogAttr(e *zerolog.Event, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	v := a.Value
+	if v.Kind() == slog.KindGroup {
+		group := v.Group()
+		if len(group) == 0 {
+			return
+		}
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range group {
+			appendSlogAttr(e, prefix, ga)
+		}
+		return
+	}
+
+	key := prefix + a.Key
+	switch v.Kind() {
+	case slog.KindString:
+		e.Str(key, v.String())
+	case slog.KindInt64:
+		e.Int64(key, v.Int64())
+	case slog.KindUint64:
+		e.Uint64(key, v.Uint64())
+	case slog.KindFloat64:
+		e.Float64(key, v.Float64())
+	case slog.KindBool:
+		e.Bool(key, v.Bool())
+	case slog.KindDuration:
+		e.Dur(key, v.Duration())
+	case slog.KindTime:
+		e.Time(key, v.Time())
+	default:
+		if err, ok := v.Any().(error); ok {
+			e.AnErr(key, err)
+			return
+		}
+		e.Interface(key, v.Any())
+	}
+}
@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+
+	"github.com/rs/zerolog"
+)
+
+// WithCaller 在日志中附加调用位置（caller 字段，格式为 "目录/文件.go:行号"）。
+// 自动跳过日志库内部帧，包级函数、链式调用和派生 Logger 都指向业务代码位置。
+//
+// 用法：
+//
+//	logger.Init(logger.LevelInfo, true, logger.WithCaller())
+func WithCaller() Option {
+	return func(o *options) {
+		o.caller = true
+	}
+}
+
+// callerHook 在事件中写入第一个非日志库内部帧的位置。
+type callerHook struct{}
+
+// Run 实现 zerolog.Hook。
+func (callerHook) Run(e *zerolog.Event, _ zerolog.Level, _ string) {
+	if c := findCaller(); c != "" {
+		e.Str(zerolog.CallerFieldName, c)
+	}
+}
+
+// findCaller 返回调用方的 "目录/文件.go:行号"，找不到时返回空字符串。
+func findCaller() string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+		if !skipFrame(f.Function) {
+			dir, file := filepath.Split(f.File)
+			return filepath.Base(dir) + "/" + file + ":" + strconv.Itoa(f.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
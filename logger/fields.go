@@ -0,0 +1,185 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+// Logger 携带固定字段的派生日志器，API 与包级函数一致。
+// 始终跟随全局配置（级别、输出目标等），Init / InitWithFile 之后无需重新派生。
+//
+// 用法：
+//
+//	l := logger.WithFields(map[string]any{"job_id": jobID})
+//	l.Infof("开始处理 %d 条记录", n) // 输出中自动带上 job_id 字段
+type Logger struct {
+	module  string
+	fields  map[string]any
+	sampler zerolog.Sampler // 采样器（Every / Sampled / Throttle），为 nil 时不采样
+	hook    zerolog.Hook    // 随采样器附加的钩子（如 Throttle 的抑制计数）
+	cache   atomic.Pointer[cachedLogger]
+}
+
+// cachedLogger 基于某一版本全局配置构建的 zerolog.Logger。
+type cachedLogger struct {
+	gen uint64
+	zl  zerolog.Logger
+}
+
+// WithFields 基于全局 logger 派生一个携带 fields 的 Logger。
+func WithFields(fields map[string]any) *Logger {
+	return defaultLogger.WithFields(fields)
+}
+
+// WithFields 在当前 Logger 字段的基础上追加 fields（同名字段覆盖），返回新的 Logger（保留模块名和采样设置）。
+func (l *Logger) WithFields(fields map[string]any) *Logger {
+	d := l.derive()
+	for k, v := range fields {
+		d.fields[k] = v
+	}
+	return d
+}
+
+// derive 复制 Logger 的配置（不含缓存），用于派生新的 Logger。
+func (l *Logger) derive() *Logger {
+	d := &Logger{
+		module:  l.module,
+		fields:  make(map[string]any, len(l.fields)),
+		sampler: l.sampler,
+		hook:    l.hook,
+	}
+	for k, v := range l.fields {
+		d.fields[k] = v
+	}
+	return d
+}
+
+// Fields 返回 Logger 携带字段的副本。
+func (l *Logger) Fields() map[string]any {
+	fields := make(map[string]any, len(l.fields))
+	for k, v := range l.fields {
+		fields[k] = v
+	}
+	return fields
+}
+
+// zl 返回基于当前全局配置的 zerolog.Logger，全局配置（输出、级别）变化后自动重建。
+func (l *Logger) zl() *zerolog.Logger {
+	s := cur()
+	if c := l.cache.Load(); c != nil && c.gen == s.gen {
+		return &c.zl
+	}
+
+	ctx := s.root.With()
+	if l.module != "" {
+		ctx = ctx.Str("module", l.module)
+	}
+	zl := ctx.Fields(l.fields).Logger().Level(s.levelFor(l.module))
+	if l.sampler != nil {
+		zl = zl.Sample(l.sampler)
+	}
+	if l.hook != nil {
+		zl = zl.Hook(l.hook)
+	}
+
+	c := &cachedLogger{gen: s.gen, zl: zl}
+	l.cache.Store(c)
+	return &c.zl
+}
+
+// ==================== 简洁风格 ====================
+
+// Debugf 调试日志
+func (l *Logger) Debugf(format string, v ...interface{}) {
+	l.zl().Debug().Msgf(format, v...)
+}
+
+// Infof 信息日志
+func (l *Logger) Infof(format string, v ...interface{}) {
+	l.zl().Info().Msgf(format, v...)
+}
+
+// Warnf 警告日志
+func (l *Logger) Warnf(format string, v ...interface{}) {
+	l.zl().Warn().Msgf(format, v...)
+}
+
+// Errorf 错误日志
+func (l *Logger) Errorf(format string, v ...interface{}) {
+	l.zl().Error().Msgf(format, v...)
+}
+
+// ErrorfE 错误日志并返回 error
+func (l *Logger) ErrorfE(format string, v ...interface{}) error {
+	l.zl().Error().Msgf(format, v...)
+	return fmt.Errorf(format, v...)
+}
+
+// Fatalf 致命错误日志（会调用 os.Exit(1)）
+func (l *Logger) Fatalf(format string, v ...interface{}) {
+	l.zl().Fatal().Msgf(format, v...)
+}
+
+// ==================== 链式风格 ====================
+
+// Debug 调试日志（链式）
+func (l *Logger) Debug() *zerolog.Event {
+	return l.zl().Debug()
+}
+
+// Info 信息日志（链式）
+func (l *Logger) Info() *zerolog.Event {
+	return l.zl().Info()
+}
+
+// Warn 警告日志（链式）
+func (l *Logger) Warn() *zerolog.Event {
+	return l.zl().Warn()
+}
+
+// Error 错误日志（链式）
+func (l *Logger) Error() *zerolog.Event {
+	return l.zl().Error()
+}
+
+// Fatal 致命错误日志（链式，会调用 os.Exit(1)）
+func (l *Logger) Fatal() *zerolog.Event {
+	return l.zl().Fatal()
+}
+
+// ==================== context 传递 ====================
+
+// ctxKey context 中存放 Logger 的 key。
+type ctxKey struct{}
+
+// ContextWithLogger 返回携带 l 的新 context，下游通过 FromContext 取出。
+//
+// 用法：
+//
+//	ctx = logger.ContextWithLogger(ctx, logger.WithFields(map[string]any{"request_id": reqID}))
+//	// ... 深层调用
+//	logger.FromContext(ctx).Infof("处理完成")
+func ContextWithLogger(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// ContextWithFields 在 ctx 中已有 Logger（没有则为全局 logger）的基础上追加字段，返回新的 context。
+func ContextWithFields(ctx context.Context, fields map[string]any) context.Context {
+	return ContextWithLogger(ctx, FromContext(ctx).WithFields(fields))
+}
+
+// FromContext 取出 ctx 中的 Logger；ctx 为 nil 或未设置时返回不带字段的全局 Logger。
+func FromContext(ctx context.Context) *Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(ctxKey{}).(*Logger); ok && l != nil {
+			return l
+		}
+	}
+	return defaultLogger
+}
+
+// defaultLogger 不带字段的全局 Logger。
+var defaultLogger = &Logger{}
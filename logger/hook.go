@@ -0,0 +1,211 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Entry 传递给钩子的一条日志事件。
+type Entry struct {
+	Time    time.Time      `json:"time"`
+	Level   string         `json:"level"`
+	Message string         `json:"message"`
+	Module  string         `json:"module,omitempty"`
+	Fields  map[string]any `json:"fields,omitempty"` // 除 time / level / message / module 外的全部字段
+}
+
+// Hook 日志钩子，每条达到最低级别的日志调用一次 Fire，用于把日志投递到外部（Redis、OBS、Webhook 等）。
+// Fire 在打日志的 goroutine 中同步执行，耗时操作应在实现内部异步 / 批量处理。
+// 实现 io.Closer 时，logger.Close 或 AddHook 返回的 remove 函数会调用 Close 投递剩余日志；
+// 实现 Flusher 时，Fatal 日志在退出前会调用 Flush。
+// 内置实现见 gotools/logger/loghook 包，发送到钉钉 / 企业微信 / 邮件见 notify.NewLogHook。
+type Hook interface {
+	Fire(entry *Entry) error
+}
+
+// Flusher 可选接口：立即投递缓冲中的日志。
+type Flusher interface {
+	Flush() error
+}
+
+// HookFunc 函数形式的 Hook。
+type HookFunc func(entry *Entry) error
+
+// Fire 实现 Hook 接口。
+func (f HookFunc) Fire(entry *Entry) error { return f(entry) }
+
+// AddHook 注册钩子，只有级别 >= minLevel 的日志会触发。
+// 返回的 remove 函数用于移除该钩子（实现了 io.Closer 时同时关闭），可重复调用。
+//
+// 用法：
+//
+//	remove := logger.AddHook(loghook.NewWebhookHook("https://example.com/alerts", nil), logger.LevelError)
+//	defer remove()
+func AddHook(h Hook, minLevel string) (remove func()) {
+	id := hooks.add(h, parseLevel(minLevel))
+	return func() { hooks.remove(id) }
+}
+
+// ---------------------------------------------------------------------------
+// 钩子分发
+// ---------------------------------------------------------------------------
+
+// hooks 全局钩子分发器，作为 root logger 的一个输出目标。
+var hooks = &hookDispatcher{}
+
+// registeredHook 已注册的钩子及其最低级别。
+type registeredHook struct {
+	id       uint64
+	hook     Hook
+	minLevel zerolog.Level
+}
+
+// hookDispatcher 接收 zerolog 输出的 JSON 事件，解析后分发给各钩子。
+type hookDispatcher struct {
+	mu     sync.Mutex
+	nextID uint64
+	list   atomic.Pointer[[]registeredHook] // copy-on-write，写日志路径上无锁读取
+}
+
+// add 注册钩子，返回注册 ID。
+func (d *hookDispatcher) add(h Hook, minLevel zerolog.Level) uint64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.nextID++
+	var next []registeredHook
+	if cur := d.list.Load(); cur != nil {
+		next = append(next, *cur...)
+	}
+	next = append(next, registeredHook{id: d.nextID, hook: h, minLevel: minLevel})
+	d.list.Store(&next)
+	return d.nextID
+}
+
+// remove 按注册 ID 移除钩子并关闭，ID 不存在时忽略。
+func (d *hookDispatcher) remove(id uint64) {
+	d.mu.Lock()
+	var next []registeredHook
+	var removed Hook
+	if cur := d.list.Load(); cur != nil {
+		for _, r := range *cur {
+			if r.id == id {
+				removed = r.hook
+				continue
+			}
+			next = append(next, r)
+		}
+	}
+	d.list.Store(&next)
+	d.mu.Unlock()
+
+	if removed != nil {
+		closeHook(removed)
+	}
+}
+
+// closeAll 移除并关闭全部钩子。
+func (d *hookDispatcher) closeAll() {
+	d.mu.Lock()
+	cur := d.list.Swap(nil)
+	d.mu.Unlock()
+
+	if cur == nil {
+		return
+	}
+	for _, r := range *cur {
+		closeHook(r.hook)
+	}
+}
+
+// flushAll 投递所有实现了 Flusher 的钩子缓冲中的日志。
+func (d *hookDispatcher) flushAll() {
+	cur := d.list.Load()
+	if cur == nil {
+		return
+	}
+	for _, r := range *cur {
+		if f, ok := r.hook.(Flusher); ok {
+			if err := f.Flush(); err != nil {
+				fmt.Fprintf(os.Stderr, "logger: 钩子 Flush 失败: %v\n", err)
+			}
+		}
+	}
+}
+
+// Write 实现 io.Writer（MultiLevelWriter 只调用 WriteLevel，此处为兜底）。
+func (d *hookDispatcher) Write(p []byte) (int, error) {
+	return d.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel 实现 zerolog.LevelWriter，解析事件并分发给级别匹配的钩子。
+// 钩子错误只输出到 stderr，不影响日志本身的写入。
+func (d *hookDispatcher) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	cur := d.list.Load()
+	if cur == nil || len(*cur) == 0 {
+		return len(p), nil
+	}
+
+	var entry *Entry
+	for _, r := range *cur {
+		if level < r.minLevel {
+			continue
+		}
+		if entry == nil {
+			entry = parseEntry(level, p)
+		}
+		if err := r.hook.Fire(entry); err != nil {
+			fmt.Fprintf(os.Stderr, "logger: 钩子执行失败: %v\n", err)
+		}
+		if level == zerolog.FatalLevel || level == zerolog.PanicLevel {
+			// 进程即将退出，立即投递缓冲中的日志
+			if f, ok := r.hook.(Flusher); ok {
+				if err := f.Flush(); err != nil {
+					fmt.Fprintf(os.Stderr, "logger: 钩子 Flush 失败: %v\n", err)
+				}
+			}
+		}
+	}
+	return len(p), nil
+}
+
+// parseEntry 将 zerolog 的 JSON 事件解析为 Entry，解析失败时把原文作为 Message。
+func parseEntry(level zerolog.Level, p []byte) *Entry {
+	entry := &Entry{Time: zerolog.TimestampFunc(), Level: level.String()}
+
+	var fields map[string]any
+	if err := json.Unmarshal(p, &fields); err != nil {
+		entry.Message = string(p)
+		return entry
+	}
+
+	if msg, ok := fields[zerolog.MessageFieldName].(string); ok {
+		entry.Message = msg
+	}
+	if module, ok := fields["module"].(string); ok {
+		entry.Module = module
+	}
+	delete(fields, zerolog.MessageFieldName)
+	delete(fields, zerolog.LevelFieldName)
+	delete(fields, zerolog.TimestampFieldName)
+	delete(fields, "module")
+	if len(fields) > 0 {
+		entry.Fields = fields
+	}
+	return entry
+}
+
+// closeHook 关闭实现了 io.Closer 的钩子。
+func closeHook(h Hook) {
+	if c, ok := h.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "logger: 关闭钩子失败: %v\n", err)
+		}
+	}
+}
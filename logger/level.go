@@ -0,0 +1,127 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+// state 全局日志状态快照。修改时整体替换（copy-on-write），日志调用路径上无锁读取。
+type state struct {
+	root    zerolog.Logger           // 未设置级别的根 logger（输出目标、格式）
+	std     zerolog.Logger           // 按默认级别过滤的 logger，包级函数使用
+	base    zerolog.Level            // 默认级别
+	modules map[string]zerolog.Level // 模块级别，未设置的模块使用默认级别
+	gen     uint64                   // 版本号，每次修改递增，派生 Logger 据此重建
+}
+
+var (
+	stateMu sync.Mutex
+	current atomic.Pointer[state]
+)
+
+// cur 返回当前全局状态。
+func cur() *state {
+	return current.Load()
+}
+
+// std 返回包级函数使用的 logger。
+func std() *zerolog.Logger {
+	return &cur().std
+}
+
+// levelFor 返回模块的生效级别，module 为空或未单独设置时返回默认级别。
+func (s *state) levelFor(module string) zerolog.Level {
+	if lvl, ok := s.modules[module]; ok && module != "" {
+		return lvl
+	}
+	return s.base
+}
+
+// update 基于当前状态的副本执行 fn 并发布新状态。
+// zerolog 全局级别设为所有级别中的最低值，实际过滤由各 logger 自身的级别完成。
+func update(fn func(s *state)) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	next := &state{modules: make(map[string]zerolog.Level)}
+	if old := current.Load(); old != nil {
+		next.root, next.base, next.gen = old.root, old.base, old.gen
+		for k, v := range old.modules {
+			next.modules[k] = v
+		}
+	}
+	fn(next)
+
+	next.std = next.root.Level(next.base)
+	next.gen++
+
+	minLevel := next.base
+	for _, lvl := range next.modules {
+		if lvl < minLevel {
+			minLevel = lvl
+		}
+	}
+	zerolog.SetGlobalLevel(minLevel)
+	current.Store(next)
+}
+
+// parseLevel 将级别字符串转换为 zerolog.Level，无法识别时返回 DebugLevel。
+func parseLevel(level string) zerolog.Level {
+	switch level {
+	case LevelDebug:
+		return zerolog.DebugLevel
+	case LevelInfo:
+		return zerolog.InfoLevel
+	case LevelWarn:
+		return zerolog.WarnLevel
+	case LevelError:
+		return zerolog.ErrorLevel
+	default:
+		return zerolog.DebugLevel
+	}
+}
+
+// ==================== 模块级别 ====================
+
+// Module 返回带模块名的 Logger，输出中附带 module 字段，级别可通过 SetModuleLevel 单独控制。
+// 适合作为包级变量使用。
+//
+// 用法：
+//
+//	var log = logger.Module("obsutil")
+//
+//	log.Warnf("重试上传 key=%s", key)
+func Module(name string) *Logger {
+	return &Logger{module: name}
+}
+
+// SetModuleLevel 单独设置某个模块的日志级别，不影响其他模块和包级函数。
+//
+// 用法：
+//
+//	logger.SetModuleLevel("obsutil", logger.LevelWarn) // 屏蔽 obsutil 的重试日志
+//	logger.SetModuleLevel("redis", logger.LevelError)  // 屏蔽 redis 重连日志
+func SetModuleLevel(module, level string) {
+	update(func(s *state) {
+		s.modules[module] = parseLevel(level)
+	})
+}
+
+// ResetModuleLevel 取消模块的单独级别，恢复使用默认级别。
+func ResetModuleLevel(module string) {
+	update(func(s *state) {
+		delete(s.modules, module)
+	})
+}
+
+// ModuleLevels 返回当前单独设置了级别的模块及其级别。
+func ModuleLevels() map[string]string {
+	s := cur()
+	levels := make(map[string]string, len(s.modules))
+	for k, v := range s.modules {
+		levels[k] = v.String()
+	}
+	return levels
+}
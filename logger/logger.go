@@ -4,17 +4,16 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"path/filepath"
 	"time"
 
 	"github.com/rs/zerolog"
 )
 
-// 全局 logger
-var log zerolog.Logger
+// 日志文件写入器（用于关闭）
+var logFile *rotateWriter
 
-// 日志文件句柄（用于关闭）
-var logFile *os.File
+// 异步写文件队列（启用 WithAsync 时非 nil）
+var asyncFile *asyncWriter
 
 // 日志级别常量
 const (
@@ -24,6 +23,12 @@ const (
 	LevelError = "error"
 )
 
+// 日志文件格式常量，用于 WithFileFormat
+const (
+	FormatJSON = "json" // JSON Lines，每行一条 JSON，便于日志采集系统解析
+	FormatText = "text" // 与控制台相同的文本格式（无颜色）
+)
+
 func init() {
 	// 默认初始化为彩色控制台输出（开发模式）
 	Init(LevelDebug, true)
@@ -39,94 +44,213 @@ func init() {
 //	logger.Init(logger.LevelInfo, true)
 //	// 生产模式（JSON 格式）
 //	logger.Init(logger.LevelInfo, false)
-func Init(level string, pretty bool) {
-	initWithWriter(level, pretty, nil)
+//	// 错误日志附带调用栈
+//	logger.Init(logger.LevelInfo, false, logger.WithStack())
+//	// 附带调用位置，使用 UTC 毫秒时间
+//	logger.Init(logger.LevelInfo, false, logger.WithCaller(), logger.WithUTC(), logger.WithTimeFormat("2006-01-02T15:04:05.000Z07:00"))
+func Init(level string, pretty bool, opts ...Option) {
+	initWithWriter(level, pretty, nil, buildOptions(opts))
+}
+
+// Option 日志初始化选项，用于 Init / InitWithFile。
+type Option func(*options)
+
+// options 初始化选项集合。
+type options struct {
+	rotate     RotateConfig
+	async      *AsyncConfig
+	stack      bool
+	caller     bool
+	timeFormat string
+	utc        bool
+	fileFormat string
+}
+
+// defaultTimeFormat 默认时间格式。
+const defaultTimeFormat = "2006/01/02 15:04:05"
+
+// WithTimeFormat 设置时间格式（Go time layout），默认 "2006/01/02 15:04:05"。
+// 控制台输出和 JSON 输出均生效，如需毫秒可使用 "2006/01/02 15:04:05.000"。
+func WithTimeFormat(layout string) Option {
+	return func(o *options) {
+		o.timeFormat = layout
+	}
+}
+
+// WithUTC 使用 UTC 时间（默认本地时区）。
+func WithUTC() Option {
+	return func(o *options) {
+		o.utc = true
+	}
+}
+
+// WithFileFormat 单独设置日志文件的输出格式（FormatJSON / FormatText，仅对 InitWithFile 生效）。
+// 默认跟随 pretty 参数：pretty 为 true 时文件为无颜色的文本，false 时为 JSON。
+// 常用于控制台保持彩色输出，文件输出 JSON Lines 供日志采集：
+//
+//	logger.InitWithFile(logger.LevelInfo, true, "/logs/myapp", logger.WithFileFormat(logger.FormatJSON))
+func WithFileFormat(format string) Option {
+	return func(o *options) {
+		o.fileFormat = format
+	}
+}
+
+// WithRotation 设置日志文件轮转策略（仅对 InitWithFile 生效）。
+//
+// 用法：
+//
+//	logger.InitWithFile(logger.LevelInfo, true, "/logs/myapp", logger.WithRotation(logger.RotateConfig{
+//	    MaxSizeMB:  100, // 单文件超过 100MB 切换新文件
+//	    MaxAgeDays: 7,   // 历史文件保留 7 天
+//	    MaxBackups: 20,  // 最多保留 20 个历史文件
+//	    Compress:   true,
+//	}))
+func WithRotation(cfg RotateConfig) Option {
+	return func(o *options) {
+		o.rotate = cfg
+	}
+}
+
+// buildOptions 应用所有选项。
+func buildOptions(opts []Option) options {
+	o := options{timeFormat: defaultTimeFormat}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
 }
 
 // InitWithFile 初始化 logger 并同时输出到文件
 // logDir: 日志目录路径，如 "/logs/jsonl_packer"
-// 返回日志文件路径
-func InitWithFile(level string, pretty bool, logDir string) (string, error) {
+// opts: 可选配置，如 WithRotation 设置按大小 / 时间轮转和历史文件清理，WithAsync 异步写文件，WithStack 附加错误调用栈
+// 返回日志文件路径（启用轮转时为首个文件路径）
+func InitWithFile(level string, pretty bool, logDir string, opts ...Option) (string, error) {
+	o := buildOptions(opts)
+
 	// 创建日志目录
 	if err := os.MkdirAll(logDir, 0755); err != nil {
 		return "", fmt.Errorf("创建日志目录失败: %w", err)
 	}
 
-	// 生成日志文件名（带时间戳）
-	logFileName := fmt.Sprintf("%s.log", time.Now().Format("20060102_150405"))
-	logPath := filepath.Join(logDir, logFileName)
-
-	// 打开日志文件
-	file, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	// 打开以时间戳命名的日志文件
+	writer, err := newRotateWriter(logDir, o.rotate)
 	if err != nil {
-		return "", fmt.Errorf("打开日志文件失败: %w", err)
+		return "", err
 	}
 
-	// 保存文件句柄
-	logFile = file
+	var fileWriter io.Writer = writer
+	var aw *asyncWriter
+	if o.async != nil {
+		aw = newAsyncWriter(writer, *o.async)
+		fileWriter = aw
+	}
 
-	// 初始化 logger（同时输出到控制台和文件）
-	initWithWriter(level, pretty, file)
+	// 初始化 logger（同时输出到控制台和文件），再关闭之前的日志文件（先写完旧的异步队列）
+	old, oldAsync := logFile, asyncFile
+	logFile, asyncFile = writer, aw
+	initWithWriter(level, pretty, fileWriter, o)
+	if oldAsync != nil {
+		oldAsync.Close()
+	}
+	if old != nil {
+		old.Close()
+	}
 
-	return logPath, nil
+	return writer.Path(), nil
 }
 
 // initWithWriter 内部初始化函数
-func initWithWriter(level string, pretty bool, fileWriter io.Writer) {
-	// 设置日志级别
-	var zeroLevel zerolog.Level
-	switch level {
-	case LevelDebug:
-		zeroLevel = zerolog.DebugLevel
-	case LevelInfo:
-		zeroLevel = zerolog.InfoLevel
-	case LevelWarn:
-		zeroLevel = zerolog.WarnLevel
-	case LevelError:
-		zeroLevel = zerolog.ErrorLevel
-	default:
-		zeroLevel = zerolog.DebugLevel
-	}
-
-	zerolog.SetGlobalLevel(zeroLevel)
+func initWithWriter(level string, pretty bool, fileWriter io.Writer, o options) {
+	var out io.Writer
+
+	// 时间戳时区（全局生效）
+	loc := time.Local
+	zerolog.TimestampFunc = time.Now
+	if o.utc {
+		loc = time.UTC
+		zerolog.TimestampFunc = func() time.Time { return time.Now().UTC() }
+	}
+
+	// 文件格式默认跟随控制台
+	fileJSON := !pretty
+	switch o.fileFormat {
+	case FormatJSON:
+		fileJSON = true
+	case FormatText:
+		fileJSON = false
+	}
 
+	var console io.Writer
 	if pretty {
 		// 彩色控制台输出（开发模式）
-		consoleWriter := zerolog.ConsoleWriter{
-			Out:        os.Stdout,
-			TimeFormat: "2006/01/02 15:04:05",
-			NoColor:    false,
-		}
-
-		if fileWriter != nil {
-			// 同时输出到控制台和文件
-			// 文件使用无颜色的格式
-			fileConsoleWriter := zerolog.ConsoleWriter{
-				Out:        fileWriter,
-				TimeFormat: "2006/01/02 15:04:05",
-				NoColor:    true, // 文件不需要颜色
-			}
-			multiWriter := io.MultiWriter(consoleWriter, fileConsoleWriter)
-			log = zerolog.New(multiWriter).With().Timestamp().Logger()
-		} else {
-			log = zerolog.New(consoleWriter).With().Timestamp().Logger()
-		}
+		console = newConsoleWriter(os.Stdout, false, o.timeFormat, loc)
 	} else {
 		// JSON 输出（生产模式）
-		zerolog.TimeFieldFormat = "2006/01/02 15:04:05"
-
-		if fileWriter != nil {
-			// 同时输出到控制台和文件
-			multiWriter := io.MultiWriter(os.Stdout, fileWriter)
-			log = zerolog.New(multiWriter).With().Timestamp().Logger()
-		} else {
-			log = zerolog.New(os.Stdout).With().Timestamp().Logger()
+		console = os.Stdout
+	}
+	if !pretty || (fileWriter != nil && fileJSON) {
+		// 有 JSON 输出时使用统一的时间格式（文本输出按同一格式解析时间字段）
+		zerolog.TimeFieldFormat = o.timeFormat
+	}
+
+	if fileWriter != nil {
+		// 同时输出到控制台和文件
+		fileOut := fileWriter
+		if !fileJSON {
+			fileOut = newConsoleWriter(fileWriter, true, o.timeFormat, loc) // 文件不需要颜色
 		}
+		out = io.MultiWriter(console, fileOut)
+	} else {
+		out = console
+	}
+
+	// 钩子分发器接收原始 JSON 事件，未注册钩子时不做任何处理
+	writers := []io.Writer{out, hooks}
+	if aw, ok := fileWriter.(*asyncWriter); ok {
+		writers = append(writers, fatalFlushWriter{w: aw})
+	}
+	log := zerolog.New(zerolog.MultiLevelWriter(writers...)).With().Timestamp().Logger()
+	if o.caller {
+		log = log.Hook(callerHook{})
+	}
+	if o.stack {
+		log = log.Hook(stackHook{})
+	}
+	update(func(s *state) {
+		s.root = log
+		s.base = parseLevel(level)
+	})
+}
+
+// newConsoleWriter 创建控制台格式写入器，调用栈字段在日志行下方单独打印。
+func newConsoleWriter(out io.Writer, noColor bool, timeFormat string, loc *time.Location) zerolog.ConsoleWriter {
+	return zerolog.ConsoleWriter{
+		Out:           out,
+		TimeFormat:    timeFormat,
+		TimeLocation:  loc,
+		NoColor:       noColor,
+		FieldsExclude: []string{StackFieldName},
+		FormatExtra:   formatStackExtra,
 	}
 }
 
-// Close 关闭日志文件
+// FilePath 返回当前正在写入的日志文件路径，未通过 InitWithFile 输出到文件或已 Close 时返回空串。
+func FilePath() string {
+	if logFile == nil {
+		return ""
+	}
+	return logFile.Path()
+}
+
+// Close 关闭所有钩子（投递缓冲中的日志）、写完异步队列并关闭日志文件，并等待后台的历史文件压缩 / 清理完成
 func Close() {
+	hooks.closeAll()
+	if asyncFile != nil {
+		asyncFile.Close()
+		asyncFile = nil
+	}
 	if logFile != nil {
 		logFile.Close()
 		logFile = nil
@@ -137,78 +261,72 @@ func Close() {
 
 // Debugf 调试日志
 func Debugf(format string, v ...interface{}) {
-	log.Debug().Msgf(format, v...)
+	std().Debug().Msgf(format, v...)
 }
 
 // Infof 信息日志
 func Infof(format string, v ...interface{}) {
-	log.Info().Msgf(format, v...)
+	std().Info().Msgf(format, v...)
 }
 
 // Warnf 警告日志
 func Warnf(format string, v ...interface{}) {
-	log.Warn().Msgf(format, v...)
+	std().Warn().Msgf(format, v...)
 }
 
 // Errorf 错误日志
 func Errorf(format string, v ...interface{}) {
-	log.Error().Msgf(format, v...)
+	std().Error().Msgf(format, v...)
 }
 
 // ErrorfE 错误日志并返回 error（一行代码同时记录日志和返回错误）
 func ErrorfE(format string, v ...interface{}) error {
-	log.Error().Msgf(format, v...)
+	std().Error().Msgf(format, v...)
 	return fmt.Errorf(format, v...)
 }
 
 // Fatalf 致命错误日志（会调用 os.Exit(1)）
 func Fatalf(format string, v ...interface{}) {
-	log.Fatal().Msgf(format, v...)
+	std().Fatal().Msgf(format, v...)
 }
 
 // ==================== 链式风格（需要结构化字段时使用）====================
 
 // Debug 调试日志（链式）
 func Debug() *zerolog.Event {
-	return log.Debug()
+	return std().Debug()
 }
 
 // Info 信息日志（链式）
 func Info() *zerolog.Event {
-	return log.Info()
+	return std().Info()
 }
 
 // Warn 警告日志（链式）
 func Warn() *zerolog.Event {
-	return log.Warn()
+	return std().Warn()
 }
 
 // Error 错误日志（链式）
 func Error() *zerolog.Event {
-	return log.Error()
+	return std().Error()
 }
 
 // Fatal 致命错误日志（链式，会调用 os.Exit(1)）
 func Fatal() *zerolog.Event {
-	return log.Fatal()
+	return std().Fatal()
 }
 
 // ==================== 工具函数 ====================
 
-// SetLevel 动态设置日志级别
+// SetLevel 动态设置日志级别（默认级别，不影响通过 SetModuleLevel 单独设置的模块）
 func SetLevel(level string) {
-	var zeroLevel zerolog.Level
-	switch level {
-	case LevelDebug:
-		zeroLevel = zerolog.DebugLevel
-	case LevelInfo:
-		zeroLevel = zerolog.InfoLevel
-	case LevelWarn:
-		zeroLevel = zerolog.WarnLevel
-	case LevelError:
-		zeroLevel = zerolog.ErrorLevel
-	default:
-		zeroLevel = zerolog.DebugLevel
-	}
-	zerolog.SetGlobalLevel(zeroLevel)
+	update(func(s *state) {
+		s.base = parseLevel(level)
+	})
+}
+
+// GetLevel 返回当前默认日志级别
+func GetLevel() string {
+	return cur().base.String()
 }
@@ -0,0 +1,177 @@
+// Package loghook 提供 logger.Hook 的内置实现：Redis List、OBS JSONL 批量上传、HTTP Webhook，
+// 以及将轮转下来的日志文件归档到 OBS 的 FileShipper。
+//
+// 所有钩子都在后台异步批量投递，缓冲队列满时丢弃新日志（不阻塞业务）。
+// 投递失败只输出到 stderr，避免通过 logger 记录而再次触发钩子。
+//
+// 用法：
+//
+//	hook := loghook.NewRedisListHook(redisClient, "logs:errors:myapp", 10000, nil)
+//	logger.AddHook(hook, logger.LevelError)
+//	defer logger.Close() // 投递缓冲中剩余的日志
+package loghook
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pylemonorg/gotools/logger"
+)
+
+// Options 批量投递选项，各内置钩子共用，零值字段使用各钩子的默认值。
+type Options struct {
+	BatchSize     int           // 每批最多条数
+	FlushInterval time.Duration // 最长攒批时间
+	BufferSize    int           // 缓冲队列长度，满时丢弃新日志
+}
+
+// withDefaults 填充默认值，opts 为 nil 时全部使用默认值。
+func (o *Options) withDefaults(batchSize int, flushInterval time.Duration) Options {
+	var r Options
+	if o != nil {
+		r = *o
+	}
+	if r.BatchSize <= 0 {
+		r.BatchSize = batchSize
+	}
+	if r.FlushInterval <= 0 {
+		r.FlushInterval = flushInterval
+	}
+	if r.BufferSize <= 0 {
+		r.BufferSize = 10000
+	}
+	return r
+}
+
+// batcher 通用的异步批量投递器，实现 logger.Hook、logger.Flusher 和 io.Closer。
+type batcher struct {
+	name  string
+	opts  Options
+	send  func(entries []*logger.Entry) error
+	ch    chan *logger.Entry
+	flush chan chan struct{}
+	done  chan struct{}
+
+	mu      sync.RWMutex
+	closed  bool
+	dropped atomic.Int64
+}
+
+// newBatcher 创建并启动批量投递器。
+func newBatcher(name string, opts Options, send func(entries []*logger.Entry) error) *batcher {
+	b := &batcher{
+		name:  name,
+		opts:  opts,
+		send:  send,
+		ch:    make(chan *logger.Entry, opts.BufferSize),
+		flush: make(chan chan struct{}),
+		done:  make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+// Fire 实现 logger.Hook，将日志放入缓冲队列，队列满时丢弃。
+func (b *batcher) Fire(entry *logger.Entry) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return nil
+	}
+	select {
+	case b.ch <- entry:
+	default:
+		b.dropped.Add(1)
+	}
+	return nil
+}
+
+// Flush 实现 logger.Flusher，立即投递缓冲中的日志并等待完成。
+func (b *batcher) Flush() error {
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return nil
+	}
+	req := make(chan struct{})
+	b.flush <- req
+	b.mu.RUnlock()
+	<-req
+	return nil
+}
+
+// Close 实现 io.Closer，投递剩余日志后停止后台协程。可重复调用。
+func (b *batcher) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	close(b.ch)
+	b.mu.Unlock()
+
+	<-b.done
+	if n := b.dropped.Load(); n > 0 {
+		fmt.Fprintf(os.Stderr, "loghook: %s 缓冲队列已满，共丢弃 %d 条日志\n", b.name, n)
+	}
+	return nil
+}
+
+// run 后台攒批循环：达到 BatchSize、FlushInterval 到期、收到 Flush 请求或关闭时投递。
+func (b *batcher) run() {
+	defer close(b.done)
+
+	ticker := time.NewTicker(b.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*logger.Entry, 0, b.opts.BatchSize)
+	sendBatch := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := b.send(batch); err != nil {
+			fmt.Fprintf(os.Stderr, "loghook: %s 投递 %d 条日志失败: %v\n", b.name, len(batch), err)
+		}
+		batch = make([]*logger.Entry, 0, b.opts.BatchSize)
+	}
+
+	for {
+		select {
+		case entry, ok := <-b.ch:
+			if !ok {
+				sendBatch()
+				return
+			}
+			batch = append(batch, entry)
+			if len(batch) >= b.opts.BatchSize {
+				sendBatch()
+			}
+		case <-ticker.C:
+			sendBatch()
+		case req := <-b.flush:
+			// 先取完队列中已有的日志
+			for drained := false; !drained; {
+				select {
+				case entry, ok := <-b.ch:
+					if !ok { // Flush 等待期间 Close 关闭了队列
+						sendBatch()
+						close(req)
+						return
+					}
+					batch = append(batch, entry)
+					if len(batch) >= b.opts.BatchSize {
+						sendBatch()
+					}
+				default:
+					drained = true
+				}
+			}
+			sendBatch()
+			close(req)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package loghook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sync/atomic"
+	"time"
+
+	"github.com/pylemonorg/gotools/logger"
+	"github.com/pylemonorg/gotools/obsutil"
+	"github.com/pylemonorg/gotools/retry"
+)
+
+// OBSHook 将日志攒批后以 JSONL 文件上传到 OBS 的钩子。
+// 对象 key 格式：{prefix}/{yyyymmdd}/{hhmmss}_{hostname}_{seq}.jsonl。
+type OBSHook struct {
+	*batcher
+	client *obsutil.ObsClient
+	prefix string
+	host   string
+	seq    atomic.Int64
+}
+
+// NewOBSHook 创建 OBS JSONL 批量上传钩子。opts 默认每批 1000 条、最长 1min。
+func NewOBSHook(client *obsutil.ObsClient, prefix string, opts *Options) *OBSHook {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+	h := &OBSHook{client: client, prefix: prefix, host: host}
+	h.batcher = newBatcher("obs["+prefix+"]", opts.withDefaults(1000, time.Minute), h.send)
+	return h
+}
+
+// send 将一批日志编码为 JSONL 并上传（仅对限流、网络等临时错误重试）。
+// 不使用 PutBytesWithRetry：其重试日志经 logger 输出，会被本钩子再次收集。
+func (h *OBSHook) send(entries []*logger.Entry) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return fmt.Errorf("序列化日志失败: %w", err)
+		}
+	}
+
+	now := time.Now()
+	key := path.Join(h.prefix, now.Format("20060102"),
+		fmt.Sprintf("%s_%s_%d.jsonl", now.Format("150405"), h.host, h.seq.Add(1)))
+	data := buf.Bytes()
+	policy := &retry.Policy{MaxAttempts: 4, InitialInterval: time.Second, RetryIf: obsutil.IsRetryable}
+	err := retry.Do(context.Background(), policy, func(context.Context) error {
+		_, err := h.client.PutBytes(key, data)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("上传 [%s] 失败: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package loghook
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pylemonorg/gotools/db"
+	"github.com/pylemonorg/gotools/logger"
+)
+
+// RedisListHook 将日志以 JSON 形式 RPUSH 到 Redis List 的钩子。
+type RedisListHook struct {
+	*batcher
+	client *db.RedisClient
+	key    string
+	maxLen int64
+}
+
+// NewRedisListHook 创建 Redis List 钩子。
+// maxLen > 0 时每批写入后 LTRIM 只保留最新的 maxLen 条；opts 默认每批 100 条、最长 2s。
+func NewRedisListHook(client *db.RedisClient, key string, maxLen int64, opts *Options) *RedisListHook {
+	h := &RedisListHook{client: client, key: key, maxLen: maxLen}
+	h.batcher = newBatcher("redis["+key+"]", opts.withDefaults(100, 2*time.Second), h.send)
+	return h
+}
+
+// send 批量写入 Redis。
+func (h *RedisListHook) send(entries []*logger.Entry) error {
+	values := make([]any, 0, len(entries))
+	for _, e := range entries {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("序列化日志失败: %w", err)
+		}
+		values = append(values, string(data))
+	}
+
+	if _, err := h.client.RPush(h.key, values...); err != nil {
+		return fmt.Errorf("RPUSH [%s] 失败: %w", h.key, err)
+	}
+	if h.maxLen > 0 {
+		if err := h.client.GetClient().LTrim(h.client.GetContext(), h.key, -h.maxLen, -1).Err(); err != nil {
+			return fmt.Errorf("LTRIM [%s] 失败: %w", h.key, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,258 @@
+package loghook
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pylemonorg/gotools/logger"
+	"github.com/pylemonorg/gotools/obsutil"
+	"github.com/pylemonorg/gotools/retry"
+)
+
+// defaultShipKeyPattern 默认对象 key 模板。
+const defaultShipKeyPattern = "logs/{app}/{date}/{host}/{file}"
+
+// shipMinAge 扫描发现的文件至少在此时间内未被修改才上传，避免与轮转后的压缩冲突。
+const shipMinAge = time.Minute
+
+// ShipperOptions 日志文件归档选项，零值字段使用默认值。
+type ShipperOptions struct {
+	// KeyPattern 对象 key 模板，支持占位符：
+	// {app} 应用名、{date} 日志文件日期（yyyymmdd，取自文件名）、{host} 主机名、{file} 文件名。
+	// 默认 "logs/{app}/{date}/{host}/{file}"。
+	KeyPattern string
+	App        string        // {app} 的值，默认为可执行文件名
+	KeepLocal  bool          // 上传成功后保留本地文件，默认删除
+	Interval   time.Duration // 兜底扫描目录的间隔，默认 5min
+	Retries    int           // 单个文件上传失败后的重试次数，默认 3
+}
+
+// FileShipper 将已切换（轮转）下来的日志文件上传到 OBS，成功后删除本地文件。
+// 通过 RotateConfig.OnRotate 接收刚切换下来的文件，并定期扫描日志目录兜底
+// （包括上次运行遗留、上传失败或队列已满时错过的文件）。正在写入的当前文件不会上传。
+// 上传失败只输出到 stderr，文件保留在本地，下次扫描时重试。
+type FileShipper struct {
+	client *obsutil.ObsClient
+	dir    string
+	opts   ShipperOptions
+	host   string
+
+	ch   chan string
+	stop chan struct{}
+	done chan struct{}
+
+	mu      sync.Mutex
+	pending map[string]bool // 已入队或正在上传的文件，避免重复上传
+	shipped map[string]bool // KeepLocal 时已上传的文件，避免扫描时重复上传
+	closed  bool
+}
+
+// NewOBSShipper 创建日志文件归档器并启动后台上传，dir 为日志目录（与 InitWithFile 的 logDir 相同）。
+// 创建后立即扫描一次目录，上传上次运行遗留的历史文件。
+//
+// 用法：
+//
+//	shipper := loghook.NewOBSShipper(obsClient, "/logs/myapp", &loghook.ShipperOptions{App: "myapp"})
+//	defer shipper.Close() // 在 logger.Close 之后执行：上传剩余文件（含最后一个日志文件）
+//	logger.InitWithFile(logger.LevelInfo, false, "/logs/myapp", logger.WithRotation(logger.RotateConfig{
+//	    MaxSizeMB: 100,
+//	    Compress:  true,
+//	    OnRotate:  shipper.Ship,
+//	}))
+//	defer logger.Close()
+func NewOBSShipper(client *obsutil.ObsClient, dir string, opts *ShipperOptions) *FileShipper {
+	var o ShipperOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.KeyPattern == "" {
+		o.KeyPattern = defaultShipKeyPattern
+	}
+	if o.App == "" {
+		o.App = strings.TrimSuffix(filepath.Base(os.Args[0]), filepath.Ext(os.Args[0]))
+	}
+	if o.Interval <= 0 {
+		o.Interval = 5 * time.Minute
+	}
+	if o.Retries <= 0 {
+		o.Retries = 3
+	}
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+
+	s := &FileShipper{
+		client:  client,
+		dir:     dir,
+		opts:    o,
+		host:    host,
+		ch:      make(chan string, 100),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+		pending: make(map[string]bool),
+		shipped: make(map[string]bool),
+	}
+	go s.run()
+	return s
+}
+
+// Ship 将 path 加入上传队列，不阻塞；队列已满或已关闭时忽略（由下次扫描兜底）。
+// 签名与 RotateConfig.OnRotate 一致，可直接作为轮转回调。
+func (s *FileShipper) Ship(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed || s.pending[path] || s.shipped[path] {
+		return
+	}
+	select {
+	case s.ch <- path:
+		s.pending[path] = true
+	default:
+	}
+}
+
+// Close 停止后台扫描，上传队列中剩余的文件，并最后扫描一次目录。
+// 若 logger 已先 Close（当前没有正在写入的文件），最后一个日志文件也会一并上传。
+func (s *FileShipper) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	close(s.stop)
+	<-s.done
+	return nil
+}
+
+// run 后台上传循环。
+func (s *FileShipper) run() {
+	defer close(s.done)
+
+	s.scan(true)
+	ticker := time.NewTicker(s.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case path := <-s.ch:
+			s.upload(path)
+		case <-ticker.C:
+			s.scan(true)
+		case <-s.stop:
+			for {
+				select {
+				case path := <-s.ch:
+					s.upload(path)
+				default:
+					s.scan(false)
+					return
+				}
+			}
+		}
+	}
+}
+
+// scan 扫描日志目录，上传当前文件以外的所有历史日志文件。
+// checkAge 为 true 时跳过最近仍在修改的文件（可能正在压缩）。
+func (s *FileShipper) scan(checkAge bool) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "loghook: 扫描日志目录 [%s] 失败: %v\n", s.dir, err)
+		return
+	}
+
+	current := logger.FilePath()
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !logger.IsLogFileName(name) {
+			continue
+		}
+		path := filepath.Join(s.dir, name)
+		if current != "" && (path == current || path == current+".gz") {
+			continue
+		}
+		// 压缩进行中或已完成，等待 / 上传压缩后的文件
+		if !strings.HasSuffix(name, ".gz") && (fileExists(path+".gz") || fileExists(path+".gz.tmp")) {
+			continue
+		}
+		if checkAge {
+			info, err := e.Info()
+			if err != nil || time.Since(info.ModTime()) < shipMinAge {
+				continue
+			}
+		}
+
+		s.mu.Lock()
+		skip := s.pending[path] || s.shipped[path]
+		s.mu.Unlock()
+		if !skip {
+			s.upload(path)
+		}
+	}
+}
+
+// upload 上传单个文件（带重试），成功后按配置删除本地文件。
+func (s *FileShipper) upload(path string) {
+	s.mu.Lock()
+	s.pending[path] = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.pending, path)
+		s.mu.Unlock()
+	}()
+
+	if !fileExists(path) {
+		return // 已被清理或已上传
+	}
+
+	key := s.objectKey(filepath.Base(path))
+	policy := &retry.Policy{MaxAttempts: s.opts.Retries + 1, InitialInterval: time.Second}
+	err := retry.Do(context.Background(), policy, func(context.Context) error {
+		_, err := s.client.PutFile(key, path)
+		return err
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "loghook: 上传日志文件 [%s] 到 [%s] 失败: %v\n", path, key, err)
+		return
+	}
+
+	if s.opts.KeepLocal {
+		s.mu.Lock()
+		s.shipped[path] = true
+		s.mu.Unlock()
+		return
+	}
+	if err = os.Remove(path); err != nil && !os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "loghook: 删除已上传的日志文件 [%s] 失败: %v\n", path, err)
+	}
+}
+
+// objectKey 按 KeyPattern 生成文件的对象 key。
+func (s *FileShipper) objectKey(name string) string {
+	date := name
+	if len(date) >= 8 {
+		date = date[:8]
+	}
+	return strings.NewReplacer(
+		"{app}", s.opts.App,
+		"{date}", date,
+		"{host}", s.host,
+		"{file}", name,
+	).Replace(s.opts.KeyPattern)
+}
+
+// fileExists 判断文件是否存在。
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,61 @@
+package loghook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pylemonorg/gotools/logger"
+)
+
+// WebhookHook 将日志攒批后以 JSON 数组 POST 到 HTTP 地址的钩子。
+type WebhookHook struct {
+	*batcher
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// NewWebhookHook 创建 HTTP Webhook 钩子，headers 可用于设置鉴权头。
+// 请求体为 []logger.Entry 的 JSON 数组，非 2xx 响应视为失败。opts 默认每批 50 条、最长 5s。
+func NewWebhookHook(url string, headers map[string]string, opts *Options) *WebhookHook {
+	h := &WebhookHook{
+		url:     url,
+		headers: headers,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+	h.batcher = newBatcher("webhook", opts.withDefaults(50, 5*time.Second), h.send)
+	return h
+}
+
+// send 发送一批日志。
+func (h *WebhookHook) send(entries []*logger.Entry) error {
+	body, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("序列化日志失败: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range h.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求 [%s] 失败: %w", h.url, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("请求 [%s] 返回状态码 %d", h.url, resp.StatusCode)
+	}
+	return nil
+}
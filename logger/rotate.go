@@ -0,0 +1,291 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RotateConfig 日志文件轮转配置，零值表示不轮转（与旧版行为一致：一个进程一个文件）。
+type RotateConfig struct {
+	MaxSizeMB  int           // 单个文件最大大小（MB），超过后切换到新文件，0 表示不限制
+	Interval   time.Duration // 按时间切换文件的间隔（如 24h），0 表示不按时间切换
+	MaxAgeDays int           // 历史文件最长保留天数，0 表示不按时间清理
+	MaxBackups int           // 历史文件最多保留个数（不含当前文件），0 表示不按个数清理
+	Compress   bool          // 是否将切换下来的历史文件 gzip 压缩为 .log.gz
+
+	// OnRotate 历史文件切换（及压缩）完成后在后台调用，path 为最终文件路径（.log 或 .log.gz），
+	// 可用于上传归档（如 loghook.FileShipper.Ship）。回调在清理之前执行，应尽快返回。
+	OnRotate func(path string)
+}
+
+// logFileNameFormat 日志文件名中的时间格式。
+const logFileNameFormat = "20060102_150405"
+
+// logFilePattern 匹配本包生成的日志文件名（含轮转序号和压缩后缀），清理时只处理这些文件。
+var logFilePattern = regexp.MustCompile(`^\d{8}_\d{6}(_\d+)?\.log(\.gz)?$`)
+
+// rotateWriter 按大小 / 时间切换文件的日志写入器，线程安全。
+// 历史文件的压缩和清理在后台进行，Close 时等待其完成。
+type rotateWriter struct {
+	mu       sync.Mutex
+	dir      string
+	cfg      RotateConfig
+	file     *os.File
+	path     string
+	size     int64
+	openedAt time.Time
+	closed   bool
+
+	bg sync.WaitGroup // 后台压缩 / 清理任务
+}
+
+// newRotateWriter 在 dir 下创建（或追加打开）以当前时间命名的日志文件。
+func newRotateWriter(dir string, cfg RotateConfig) (*rotateWriter, error) {
+	w := &rotateWriter{dir: dir, cfg: cfg}
+	path := filepath.Join(dir, time.Now().Format(logFileNameFormat)+".log")
+	if err := w.openFile(path); err != nil {
+		return nil, err
+	}
+	w.cleanupAsync()
+	return w, nil
+}
+
+// Path 返回当前写入的日志文件路径。
+func (w *rotateWriter) Path() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.path
+}
+
+// Write 实现 io.Writer，必要时先切换到新文件再写入。
+func (w *rotateWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+	if w.shouldRotate(len(p)) {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Close 关闭当前文件，并等待后台压缩 / 清理任务完成。
+func (w *rotateWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	err := w.file.Close()
+	w.mu.Unlock()
+
+	w.bg.Wait()
+	return err
+}
+
+// shouldRotate 判断写入 n 字节前是否需要切换文件（需持有锁）。
+func (w *rotateWriter) shouldRotate(n int) bool {
+	if w.size == 0 {
+		return false
+	}
+	if w.cfg.MaxSizeMB > 0 && w.size+int64(n) > int64(w.cfg.MaxSizeMB)*1024*1024 {
+		return true
+	}
+	return w.cfg.Interval > 0 && time.Since(w.openedAt) >= w.cfg.Interval
+}
+
+// rotate 关闭当前文件并打开新文件，旧文件交给后台压缩和清理（需持有锁）。
+func (w *rotateWriter) rotate() error {
+	oldPath := w.path
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("logger: 关闭日志文件失败: %w", err)
+	}
+	if err := w.openFile(w.nextPath()); err != nil {
+		return err
+	}
+
+	w.bg.Add(1)
+	go func() {
+		defer w.bg.Done()
+		finalPath := oldPath
+		if w.cfg.Compress {
+			if err := compressFile(oldPath); err != nil {
+				fmt.Fprintf(os.Stderr, "logger: 压缩日志文件 [%s] 失败: %v\n", oldPath, err)
+			} else {
+				finalPath = oldPath + ".gz"
+			}
+		}
+		if w.cfg.OnRotate != nil {
+			w.cfg.OnRotate(finalPath)
+		}
+		w.cleanup()
+	}()
+	return nil
+}
+
+// nextPath 生成不与已有文件冲突的新文件路径（同一秒内多次切换时追加序号）。
+func (w *rotateWriter) nextPath() string {
+	base := time.Now().Format(logFileNameFormat)
+	path := filepath.Join(w.dir, base+".log")
+	for i := 1; fileExists(path) || fileExists(path+".gz"); i++ {
+		path = filepath.Join(w.dir, fmt.Sprintf("%s_%d.log", base, i))
+	}
+	return path
+}
+
+// openFile 以追加模式打开日志文件（需持有锁或在初始化阶段调用）。
+func (w *rotateWriter) openFile(path string) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("打开日志文件失败: %w", err)
+	}
+	var size int64
+	if info, err := file.Stat(); err == nil {
+		size = info.Size()
+	}
+	w.file, w.path, w.size, w.openedAt = file, path, size, time.Now()
+	return nil
+}
+
+// cleanupAsync 在后台执行一次历史文件清理。
+func (w *rotateWriter) cleanupAsync() {
+	if w.cfg.MaxAgeDays <= 0 && w.cfg.MaxBackups <= 0 {
+		return
+	}
+	w.bg.Add(1)
+	go func() {
+		defer w.bg.Done()
+		w.cleanup()
+	}()
+}
+
+// cleanup 按 MaxAgeDays / MaxBackups 删除历史日志文件（不含当前文件）。
+func (w *rotateWriter) cleanup() {
+	if w.cfg.MaxAgeDays <= 0 && w.cfg.MaxBackups <= 0 {
+		return
+	}
+
+	w.mu.Lock()
+	current := w.path
+	w.mu.Unlock()
+
+	backups, err := listBackups(w.dir, current)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "logger: 列出历史日志文件失败: %v\n", err)
+		return
+	}
+
+	var cutoff time.Time
+	if w.cfg.MaxAgeDays > 0 {
+		cutoff = time.Now().Add(-time.Duration(w.cfg.MaxAgeDays) * 24 * time.Hour)
+	}
+	for i, b := range backups {
+		expired := !cutoff.IsZero() && b.modTime.Before(cutoff)
+		overflow := w.cfg.MaxBackups > 0 && i >= w.cfg.MaxBackups
+		if expired || overflow {
+			if err := os.Remove(b.path); err != nil && !os.IsNotExist(err) {
+				fmt.Fprintf(os.Stderr, "logger: 删除历史日志文件 [%s] 失败: %v\n", b.path, err)
+			}
+		}
+	}
+}
+
+// backupFile 历史日志文件信息。
+type backupFile struct {
+	path    string
+	modTime time.Time
+}
+
+// listBackups 列出 dir 下除 current 外的历史日志文件，按修改时间从新到旧排序。
+// 正在压缩的文件（同时存在 .log 和 .log.gz）只计一次。
+func listBackups(dir, current string) ([]backupFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []backupFile
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !logFilePattern.MatchString(name) {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if path == current || path == current+".gz" {
+			continue
+		}
+		if !strings.HasSuffix(name, ".gz") && fileExists(path+".gz") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backupFile{path: path, modTime: info.ModTime()})
+	}
+
+	sort.Slice(backups, func(i, j int) bool { return backups[i].modTime.After(backups[j].modTime) })
+	return backups, nil
+}
+
+// compressFile 将 path gzip 压缩为 path.gz，成功后删除原文件。
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp := path + ".gz.tmp"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	_, err = io.Copy(gz, src)
+	if cerr := gz.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	if err = os.Rename(tmp, path+".gz"); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Remove(path)
+}
+
+// IsLogFileName 判断 name（不含目录）是否为本包生成的日志文件名，如 "20260216_150405.log"、
+// "20260216_150405_1.log.gz"。
+func IsLogFileName(name string) bool {
+	return logFilePattern.MatchString(name)
+}
+
+// fileExists 判断文件是否存在。
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
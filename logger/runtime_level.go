@@ -0,0 +1,180 @@
+package logger
+
+import (
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RuntimeLevelConfig 运行时级别控制配置，用于在不重启进程的情况下调整日志级别。
+type RuntimeLevelConfig struct {
+	Signal       bool          // 收到 SIGUSR1 时在 debug 和原级别之间切换（仅 Unix 系统）
+	LevelFile    string        // 级别文件路径，内容为 debug / info / warn / error；文件删除后恢复原级别
+	PollInterval time.Duration // 级别文件检查间隔，默认 5s
+	EnvVar       string        // 启动时读取的环境变量名（如 "LOG_LEVEL"），非空且合法时作为默认级别
+}
+
+// EnableRuntimeLevelControl 启用运行时级别控制，返回 stop 函数用于停止监听。
+// cfg 为 nil 时仅启用 SIGUSR1 切换。
+//
+// 用法：
+//
+//	stop := logger.EnableRuntimeLevelControl(&logger.RuntimeLevelConfig{
+//	    Signal:    true,
+//	    LevelFile: "/tmp/myapp.loglevel",
+//	    EnvVar:    "LOG_LEVEL",
+//	})
+//	defer stop()
+//
+//	// 排查卡住的进程：
+//	//   kill -USR1 <pid>                       # 切换到 debug，再次发送恢复
+//	//   echo debug > /tmp/myapp.loglevel       # 或写级别文件，删除文件恢复
+func EnableRuntimeLevelControl(cfg *RuntimeLevelConfig) (stop func()) {
+	if cfg == nil {
+		cfg = &RuntimeLevelConfig{Signal: true}
+	}
+	interval := cfg.PollInterval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	if cfg.EnvVar != "" {
+		if lvl := strings.ToLower(strings.TrimSpace(os.Getenv(cfg.EnvVar))); lvl != "" {
+			if isValidLevel(lvl) {
+				SetLevel(lvl)
+			} else {
+				Warnf("环境变量 %s=%q 不是合法的日志级别，已忽略", cfg.EnvVar, lvl)
+			}
+		}
+	}
+
+	c := &levelController{original: GetLevel()}
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+
+	if cfg.Signal && len(toggleSignals) > 0 {
+		sigCh := make(chan os.Signal, 1)
+		signal.Notify(sigCh, toggleSignals...)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer signal.Stop(sigCh)
+			for {
+				select {
+				case <-sigCh:
+					c.toggle()
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+
+	if cfg.LevelFile != "" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			c.checkFile(cfg.LevelFile)
+			for {
+				select {
+				case <-ticker.C:
+					c.checkFile(cfg.LevelFile)
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			wg.Wait()
+		})
+	}
+}
+
+// levelController 记录运行时调整前的级别，用于恢复。
+type levelController struct {
+	mu       sync.Mutex
+	original string // 启用时的默认级别
+	toggled  bool   // 是否处于信号切换的 debug 状态
+	fileLvl  string // 级别文件当前生效的级别，为空表示文件不存在
+}
+
+// toggle 在 debug 和原级别之间切换。
+func (c *levelController) toggle() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.toggled {
+		c.toggled = false
+		c.apply(c.restoreLevel(), "收到信号，恢复")
+		return
+	}
+	c.toggled = true
+	c.apply(LevelDebug, "收到信号，切换到")
+}
+
+// checkFile 读取级别文件，内容变化时应用；文件删除后恢复。
+func (c *levelController) checkFile(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) && c.fileLvl != "" {
+			c.fileLvl = ""
+			if !c.toggled {
+				c.apply(c.original, "级别文件已删除，恢复")
+			}
+		}
+		return
+	}
+
+	lvl := strings.ToLower(strings.TrimSpace(string(data)))
+	if lvl == c.fileLvl {
+		return
+	}
+	if !isValidLevel(lvl) {
+		Warnf("级别文件 %s 内容 %q 不是合法的日志级别，已忽略", path, lvl)
+		c.fileLvl = lvl // 避免重复告警
+		return
+	}
+	c.fileLvl = lvl
+	if !c.toggled {
+		c.apply(lvl, "级别文件变更，切换到")
+	}
+}
+
+// restoreLevel 信号切换恢复时的目标级别：级别文件生效时使用文件级别，否则使用原级别。
+func (c *levelController) restoreLevel() string {
+	if isValidLevel(c.fileLvl) {
+		return c.fileLvl
+	}
+	return c.original
+}
+
+// apply 设置默认级别并记录变更：通常以 warn 级别记录，切换到 error 时以 error 级别记录，确保变更本身总能被看到。
+func (c *levelController) apply(level, reason string) {
+	SetLevel(level)
+	if level == LevelError {
+		Errorf("[logger] %s %s 级别", reason, level)
+		return
+	}
+	Warnf("[logger] %s %s 级别", reason, level)
+}
+
+// isValidLevel 判断是否为支持的级别字符串。
+func isValidLevel(level string) bool {
+	switch level {
+	case LevelDebug, LevelInfo, LevelWarn, LevelError:
+		return true
+	}
+	return false
+}
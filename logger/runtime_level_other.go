@@ -0,0 +1,8 @@
+//go:build !unix
+
+package logger
+
+import "os"
+
+// toggleSignals 非 Unix 系统不支持 SIGUSR1，仅可使用级别文件控制。
+var toggleSignals []os.Signal
@@ -0,0 +1,11 @@
+//go:build unix
+
+package logger
+
+import (
+	"os"
+	"syscall"
+)
+
+// toggleSignals 切换 debug 级别的信号。
+var toggleSignals = []os.Signal{syscall.SIGUSR1}
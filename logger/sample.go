@@ -0,0 +1,160 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// 采样与限流：用于热循环中的重复日志（如逐对象上传告警），避免刷出海量相同日志。
+// 被丢弃的日志在链式风格下返回 nil *zerolog.Event，后续调用均为空操作，两种风格都可直接使用。
+
+// Sampled 基于全局 logger 派生使用自定义采样器的 Logger（如 zerolog.BurstSampler）。
+func Sampled(sampler zerolog.Sampler) *Logger {
+	return defaultLogger.Sampled(sampler)
+}
+
+// Sampled 派生使用自定义采样器的 Logger，替换原有采样设置。
+func (l *Logger) Sampled(sampler zerolog.Sampler) *Logger {
+	d := l.derive()
+	d.sampler, d.hook = sampler, nil
+	return d
+}
+
+// Every 基于全局 logger 派生每 n 条只输出 1 条的 Logger。
+// 采样计数保存在返回的 Logger 中，需保存为变量复用，不能在循环内每次调用 Every。
+//
+// 用法：
+//
+//	sampled := logger.Every(1000)
+//	for _, obj := range objects {
+//	    if err := upload(obj); err != nil {
+//	        sampled.Warnf("上传失败 key=%s: %v", obj.Key, err)
+//	    }
+//	}
+func Every(n uint32) *Logger {
+	return defaultLogger.Every(n)
+}
+
+// Every 派生每 n 条只输出 1 条的 Logger（n <= 1 时不采样）。
+func (l *Logger) Every(n uint32) *Logger {
+	if n <= 1 {
+		return l.Sampled(nil)
+	}
+	return l.Sampled(&zerolog.BasicSampler{N: n})
+}
+
+// throttleKey 限流 Logger 注册表的 key。
+type throttleKey struct {
+	parent *Logger
+	key    string
+}
+
+// maxThrottles 限流注册表的容量上限。超出时先清理已过限流间隔的条目，仍然超出时清空注册表，
+// 避免 key 含动态内容、或在 With 派生的临时 Logger 上调用 Throttle 时注册表无限增长。
+const maxThrottles = 4096
+
+// throttleEntry 注册表中的限流 Logger 及其限流器。
+type throttleEntry struct {
+	logger *Logger
+	t      *throttler
+}
+
+// throttles 已创建的限流 Logger，同一 (parent, key) 共享限流状态。
+var (
+	throttles   sync.Map   // map[throttleKey]throttleEntry
+	throttlesMu sync.Mutex // 保护新增与清理
+	throttleN   int        // 条目数，由 throttlesMu 保护
+)
+
+// Throttle 按 key 限流：同一 key 在 interval 内最多输出 1 条，可直接在循环中调用。
+// 之后输出的第一条日志附带 suppressed 字段，表示期间被丢弃的条数。
+// 同一 key 的 interval 以第一次调用为准。
+//
+// key 应为固定字符串（如 "upload-failed"），不要拼接对象 key 等动态内容；
+// 注册表最多保留 4096 个 key，超出时会清理限流状态，期间可能多输出少量日志。
+//
+// 用法：
+//
+//	for _, obj := range objects {
+//	    logger.Throttle("upload-failed", 10*time.Second).Warnf("上传失败 key=%s", obj.Key)
+//	}
+func Throttle(key string, interval time.Duration) *Logger {
+	return defaultLogger.Throttle(key, interval)
+}
+
+// Throttle 按 key 限流，同 logger.Throttle，限流状态按 (当前 Logger, key) 隔离。
+func (l *Logger) Throttle(key string, interval time.Duration) *Logger {
+	k := throttleKey{parent: l, key: key}
+	if v, ok := throttles.Load(k); ok {
+		return v.(throttleEntry).logger
+	}
+
+	throttlesMu.Lock()
+	defer throttlesMu.Unlock()
+	if v, ok := throttles.Load(k); ok {
+		return v.(throttleEntry).logger
+	}
+	if throttleN >= maxThrottles {
+		sweepThrottlesLocked(time.Now().UnixNano())
+	}
+
+	t := &throttler{interval: interval}
+	d := l.derive()
+	d.sampler, d.hook = t, t
+	throttles.Store(k, throttleEntry{logger: d, t: t})
+	throttleN++
+	return d
+}
+
+// sweepThrottlesLocked 删除已过限流间隔的条目，仍然满时清空注册表。调用方持有 throttlesMu。
+func sweepThrottlesLocked(now int64) {
+	throttles.Range(func(k, v any) bool {
+		if v.(throttleEntry).t.idle(now) {
+			throttles.Delete(k)
+			throttleN--
+		}
+		return true
+	})
+	if throttleN >= maxThrottles {
+		throttles.Clear()
+		throttleN = 0
+	}
+}
+
+// throttler 按时间间隔限流的采样器，同时作为钩子在放行的日志上附带被抑制的条数。
+type throttler struct {
+	interval   time.Duration
+	last       atomic.Int64 // 上次放行的时间（UnixNano）
+	suppressed atomic.Int64 // 上次放行后被丢弃的条数
+}
+
+// idle 判断距上次放行是否已超过限流间隔：此时下一条日志无论如何都会放行，丢弃限流状态不影响限流效果。
+func (t *throttler) idle(now int64) bool {
+	return now-t.last.Load() >= int64(t.interval)
+}
+
+// Sample 实现 zerolog.Sampler。
+func (t *throttler) Sample(zerolog.Level) bool {
+	now := time.Now().UnixNano()
+	last := t.last.Load()
+	if last != 0 && now-last < int64(t.interval) {
+		t.suppressed.Add(1)
+		return false
+	}
+	if !t.last.CompareAndSwap(last, now) {
+		// 并发情况下已有其他调用放行
+		t.suppressed.Add(1)
+		return false
+	}
+	return true
+}
+
+// Run 实现 zerolog.Hook，在放行的日志上附带 suppressed 字段。
+func (t *throttler) Run(e *zerolog.Event, _ zerolog.Level, _ string) {
+	if n := t.suppressed.Swap(0); n > 0 {
+		e.Int64("suppressed", n)
+	}
+}
@@ -0,0 +1,116 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// StackFieldName 调用栈字段名（启用 WithStack 时 error / fatal 日志携带）。
+const StackFieldName = "stack"
+
+// WithStack 为 error 及以上级别的日志（含 ErrorfE / ErrorE）附加调用栈字段。
+// 控制台输出时调用栈在日志行下方逐行打印，JSON 输出时为 stack 字符串字段。
+//
+// 用法：
+//
+//	logger.Init(logger.LevelInfo, false, logger.WithStack())
+func WithStack() Option {
+	return func(o *options) {
+		o.stack = true
+	}
+}
+
+// stackHook 在 error 及以上级别的事件中写入调用栈。
+type stackHook struct{}
+
+// Run 实现 zerolog.Hook。
+func (stackHook) Run(e *zerolog.Event, level zerolog.Level, _ string) {
+	if level >= zerolog.ErrorLevel && level < zerolog.NoLevel {
+		e.Str(StackFieldName, captureStack())
+	}
+}
+
+// 调用栈中跳过的内部帧前缀（zerolog、本包自身，以及经 NewStdLogger / NewSlogHandler 桥接时的标准库 log / slog）
+var stackSkipPrefixes = []string{
+	"github.com/rs/zerolog.",
+	"github.com/pylemonorg/gotools/logger.",
+	"runtime.",
+	"log.",
+	"log/slog.",
+}
+
+// captureStack 捕获当前调用栈，去掉日志库内部帧，格式与 panic 输出一致。
+func captureStack() string {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var b strings.Builder
+	for {
+		f, more := frames.Next()
+		if !skipFrame(f.Function) {
+			fmt.Fprintf(&b, "%s\n\t%s:%d\n", f.Function, f.File, f.Line)
+		}
+		if !more {
+			break
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// skipFrame 判断是否为日志库内部帧。
+func skipFrame(function string) bool {
+	for _, p := range stackSkipPrefixes {
+		if strings.HasPrefix(function, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatStackExtra 控制台输出时将调用栈追加在日志行下方。
+func formatStackExtra(evt map[string]interface{}, buf *bytes.Buffer) error {
+	if s, ok := evt[StackFieldName].(string); ok && s != "" {
+		buf.WriteString("\n")
+		buf.WriteString(s)
+	}
+	return nil
+}
+
+// ==================== 结构化错误日志 ====================
+
+// ErrorE 记录 error 级别日志（err 写入 error 字段，fields 作为附加字段），并返回包装了 err 的错误。
+// err 为 nil 时返回以 msg 为内容的新错误。
+//
+// 用法：
+//
+//	if err := client.Upload(key); err != nil {
+//	    return logger.ErrorE(err, "上传失败", map[string]any{"key": key})
+//	}
+func ErrorE(err error, msg string, fields ...map[string]any) error {
+	return errorE(std(), err, msg, fields)
+}
+
+// ErrorE 记录 error 级别日志并返回包装了 err 的错误，参见包级函数 ErrorE。
+func (l *Logger) ErrorE(err error, msg string, fields ...map[string]any) error {
+	return errorE(l.zl(), err, msg, fields)
+}
+
+// errorE ErrorE 的公共实现。
+func errorE(zl *zerolog.Logger, err error, msg string, fields []map[string]any) error {
+	e := zl.Error().Err(err)
+	for _, f := range fields {
+		e = e.Fields(f)
+	}
+	e.Msg(msg)
+
+	if err == nil {
+		return errors.New(msg)
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
@@ -0,0 +1,117 @@
+package maputil
+
+import (
+	"cmp"
+	"slices"
+)
+
+// ConflictPolicy 指定 Merge 遇到相同 key 时的处理方式。
+type ConflictPolicy int
+
+const (
+	// Overwrite 后面的 map 覆盖前面的值（默认）。
+	Overwrite ConflictPolicy = iota
+	// KeepFirst 保留最先出现的值。
+	KeepFirst
+)
+
+// Keys 返回 m 的所有 key，顺序不确定。
+func Keys[K comparable, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// SortedKeys 返回按升序排列的所有 key，适合需要稳定输出的场景（日志、签名、序列化）。
+func SortedKeys[K cmp.Ordered, V any](m map[K]V) []K {
+	keys := Keys(m)
+	slices.Sort(keys)
+	return keys
+}
+
+// Values 返回 m 的所有 value，顺序不确定。
+func Values[K comparable, V any](m map[K]V) []V {
+	values := make([]V, 0, len(m))
+	for _, v := range m {
+		values = append(values, v)
+	}
+	return values
+}
+
+// SortedValues 返回按升序排列的所有 value。
+func SortedValues[K comparable, V cmp.Ordered](m map[K]V) []V {
+	values := Values(m)
+	slices.Sort(values)
+	return values
+}
+
+// GetOrDefault 返回 m[k]，key 不存在时返回 def。
+//
+// 用法：
+//
+//	timeout := maputil.GetOrDefault(cfg, "timeout", 30)
+func GetOrDefault[K comparable, V any](m map[K]V, k K, def V) V {
+	if v, ok := m[k]; ok {
+		return v
+	}
+	return def
+}
+
+// Merge 按顺序合并多个 map 为一个新 map，不修改入参。相同 key 按 policy 处理。
+//
+// 用法：
+//
+//	cfg := maputil.Merge(maputil.Overwrite, defaults, fileCfg, envCfg)
+func Merge[K comparable, V any](policy ConflictPolicy, maps ...map[K]V) map[K]V {
+	return MergeFunc(func(_ K, old, cur V) V {
+		if policy == KeepFirst {
+			return old
+		}
+		return cur
+	}, maps...)
+}
+
+// MergeFunc 按顺序合并多个 map 为一个新 map，相同 key 时调用 resolve(key, 已有值, 新值) 决定结果。
+//
+// 用法：
+//
+//	total := maputil.MergeFunc(func(_ string, a, b int) int { return a + b }, countsA, countsB)
+func MergeFunc[K comparable, V any](resolve func(k K, old, cur V) V, maps ...map[K]V) map[K]V {
+	size := 0
+	for _, m := range maps {
+		size += len(m)
+	}
+	out := make(map[K]V, size)
+	for _, m := range maps {
+		for k, v := range m {
+			if old, ok := out[k]; ok {
+				out[k] = resolve(k, old, v)
+				continue
+			}
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// Filter 返回满足 keep 的键值对组成的新 map。
+func Filter[K comparable, V any](m map[K]V, keep func(K, V) bool) map[K]V {
+	out := make(map[K]V)
+	for k, v := range m {
+		if keep(k, v) {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// Invert 交换 key 与 value。多个 key 对应同一 value 时，结果保留其中任意一个。
+func Invert[K, V comparable](m map[K]V) map[V]K {
+	out := make(map[V]K, len(m))
+	for k, v := range m {
+		out[v] = k
+	}
+	return out
+}
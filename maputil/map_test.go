@@ -0,0 +1,113 @@
+package maputil
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestKeysAndValues(t *testing.T) {
+	m := map[string]int{"b": 2, "a": 1, "c": 3}
+
+	if got, want := SortedKeys(m), []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SortedKeys = %v, want %v", got, want)
+	}
+	if got, want := SortedValues(m), []int{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SortedValues = %v, want %v", got, want)
+	}
+	if got := Keys(m); len(got) != 3 {
+		t.Errorf("Keys len = %d, want 3", len(got))
+	}
+	if got := Values(m); len(got) != 3 {
+		t.Errorf("Values len = %d, want 3", len(got))
+	}
+}
+
+func TestGetOrDefault(t *testing.T) {
+	m := map[string]int{"a": 1, "zero": 0}
+	if got := GetOrDefault(m, "a", 9); got != 1 {
+		t.Errorf("GetOrDefault(a) = %d, want 1", got)
+	}
+	if got := GetOrDefault(m, "zero", 9); got != 0 {
+		t.Errorf("GetOrDefault(zero) = %d, want 0 (existing zero value)", got)
+	}
+	if got := GetOrDefault(m, "missing", 9); got != 9 {
+		t.Errorf("GetOrDefault(missing) = %d, want 9", got)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a := map[string]int{"x": 1, "y": 2}
+	b := map[string]int{"y": 20, "z": 30}
+
+	if got, want := Merge(Overwrite, a, b), map[string]int{"x": 1, "y": 20, "z": 30}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Merge(Overwrite) = %v, want %v", got, want)
+	}
+	if got, want := Merge(KeepFirst, a, b), map[string]int{"x": 1, "y": 2, "z": 30}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Merge(KeepFirst) = %v, want %v", got, want)
+	}
+	sum := MergeFunc(func(_ string, old, cur int) int { return old + cur }, a, b)
+	if sum["y"] != 22 {
+		t.Errorf("MergeFunc sum y = %d, want 22", sum["y"])
+	}
+	if a["y"] != 2 {
+		t.Error("Merge must not modify its inputs")
+	}
+}
+
+func TestFilterAndInvert(t *testing.T) {
+	m := map[string]string{"env": "prod", "debug": "", "region": "cn"}
+
+	got := Filter(m, func(_, v string) bool { return v != "" })
+	if want := map[string]string{"env": "prod", "region": "cn"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Filter = %v, want %v", got, want)
+	}
+
+	inv := Invert(map[string]int{"a": 1, "b": 2})
+	if want := map[int]string{1: "a", 2: "b"}; !reflect.DeepEqual(inv, want) {
+		t.Errorf("Invert = %v, want %v", inv, want)
+	}
+}
+
+func TestSyncMap(t *testing.T) {
+	var m SyncMap[string, int]
+
+	if _, ok := m.Load("a"); ok {
+		t.Error("Load on empty map should return ok=false")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			m.Store(strings.Repeat("k", i%10+1), i)
+		}(i)
+	}
+	wg.Wait()
+	if got := m.Len(); got != 10 {
+		t.Errorf("Len = %d, want 10", got)
+	}
+
+	if v, loaded := m.LoadOrStore("new", 7); loaded || v != 7 {
+		t.Errorf("LoadOrStore(new) = %d, %v; want 7, false", v, loaded)
+	}
+	if v, loaded := m.LoadOrStore("new", 8); !loaded || v != 7 {
+		t.Errorf("LoadOrStore(existing) = %d, %v; want 7, true", v, loaded)
+	}
+	if prev, loaded := m.Swap("new", 9); !loaded || prev != 7 {
+		t.Errorf("Swap = %d, %v; want 7, true", prev, loaded)
+	}
+	if v, loaded := m.LoadAndDelete("new"); !loaded || v != 9 {
+		t.Errorf("LoadAndDelete = %d, %v; want 9, true", v, loaded)
+	}
+	if len(m.ToMap()) != 10 {
+		t.Errorf("ToMap len = %d, want 10", len(m.ToMap()))
+	}
+
+	m.Clear()
+	if got := m.Len(); got != 0 {
+		t.Errorf("Len after Clear = %d, want 0", got)
+	}
+}
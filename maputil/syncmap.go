@@ -0,0 +1,89 @@
+package maputil
+
+import "sync"
+
+// SyncMap 是 sync.Map 的泛型封装，零值可直接使用，不可复制。
+//
+// 用法：
+//
+//	var cache maputil.SyncMap[string, *User]
+//	cache.Store("u1", user)
+//	if u, ok := cache.Load("u1"); ok { ... }
+type SyncMap[K comparable, V any] struct {
+	m sync.Map
+}
+
+// Load 读取 key 对应的值。
+func (s *SyncMap[K, V]) Load(key K) (value V, ok bool) {
+	v, ok := s.m.Load(key)
+	if !ok {
+		return value, false
+	}
+	return v.(V), true
+}
+
+// Store 写入键值对。
+func (s *SyncMap[K, V]) Store(key K, value V) {
+	s.m.Store(key, value)
+}
+
+// LoadOrStore 若 key 已存在则返回已有值（loaded 为 true），否则写入 value 并返回。
+func (s *SyncMap[K, V]) LoadOrStore(key K, value V) (actual V, loaded bool) {
+	v, loaded := s.m.LoadOrStore(key, value)
+	return v.(V), loaded
+}
+
+// LoadAndDelete 删除 key 并返回删除前的值。
+func (s *SyncMap[K, V]) LoadAndDelete(key K) (value V, loaded bool) {
+	v, loaded := s.m.LoadAndDelete(key)
+	if !loaded {
+		return value, false
+	}
+	return v.(V), true
+}
+
+// Swap 写入新值并返回旧值（loaded 表示旧值是否存在）。
+func (s *SyncMap[K, V]) Swap(key K, value V) (previous V, loaded bool) {
+	v, loaded := s.m.Swap(key, value)
+	if !loaded {
+		return previous, false
+	}
+	return v.(V), true
+}
+
+// Delete 删除 key。
+func (s *SyncMap[K, V]) Delete(key K) {
+	s.m.Delete(key)
+}
+
+// Range 遍历所有键值对，fn 返回 false 时停止。语义同 sync.Map.Range。
+func (s *SyncMap[K, V]) Range(fn func(key K, value V) bool) {
+	s.m.Range(func(k, v any) bool {
+		return fn(k.(K), v.(V))
+	})
+}
+
+// Len 返回当前元素个数（需遍历，并发写入时仅为近似值）。
+func (s *SyncMap[K, V]) Len() int {
+	n := 0
+	s.m.Range(func(_, _ any) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// Clear 删除所有元素。
+func (s *SyncMap[K, V]) Clear() {
+	s.m.Clear()
+}
+
+// ToMap 返回当前内容的快照。
+func (s *SyncMap[K, V]) ToMap() map[K]V {
+	out := make(map[K]V)
+	s.Range(func(k K, v V) bool {
+		out[k] = v
+		return true
+	})
+	return out
+}
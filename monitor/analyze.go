@@ -4,26 +4,31 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
-	"time"
 
 	"github.com/pylemonorg/gotools/db"
-	"github.com/pylemonorg/gotools/logger"
+	"github.com/pylemonorg/gotools/timeutil"
 )
 
-// AnalyzeFromRedis 从 Redis List 读取资源汇总记录，按 CPU 核心数分组后聚合分析。
-// 返回按 CPU 核心数升序排列的分析结果和格式化的报告字符串。
+// AnalyzeFromRedis 从 Redis List 读取资源汇总记录，按 CPU 核心数（带 cgroup CPU 配额的记录按配额）
+// 及 opts.GroupBy 标签分组后聚合分析。返回按标签、可用 CPU 核数升序排列的分析结果和格式化的报告字符串。
 //
 // 用法：
 //
 //	results, report, err := monitor.AnalyzeFromRedis(redisClient, "resource:summary:myapp", nil)
 //	fmt.Println(report)
+//
+//	// 只看 app=crawler 的记录，并按版本分组
+//	results, report, err = monitor.AnalyzeFromRedis(redisClient, key, &monitor.AnalyzeOptions{
+//	    Labels:  map[string]string{"app": "crawler"},
+//	    GroupBy: []string{"version"},
+//	})
 func AnalyzeFromRedis(redisClient *db.RedisClient, key string, opts *AnalyzeOptions) ([]AnalyzeResult, string, error) {
 	values, err := redisClient.LRange(key, 0, -1)
 	if err != nil {
 		return nil, "", fmt.Errorf("monitor: LRANGE [%s] 失败: %w", key, err)
 	}
 
-	logger.Infof("monitor: 从 Redis key [%s] 读取到 %d 条记录", key, len(values))
+	log.Infof("monitor: 从 Redis key [%s] 读取到 %d 条记录", key, len(values))
 
 	if len(values) == 0 {
 		return nil, "无记录", nil
@@ -31,15 +36,14 @@ func AnalyzeFromRedis(redisClient *db.RedisClient, key string, opts *AnalyzeOpti
 
 	records, parseErrors := parseRecords(values, opts)
 	if parseErrors > 0 {
-		logger.Warnf("monitor: 解析 %d 条记录失败", parseErrors)
+		log.Warnf("monitor: 解析 %d 条记录失败", parseErrors)
 	}
 
 	if len(records) == 0 {
 		return nil, "过滤后无有效记录", nil
 	}
 
-	grouped := groupByCPU(records)
-	results := analyzeGroups(grouped)
+	results := analyzeGroups(groupRecords(records, groupByKeys(opts)), opts)
 	report := formatReport(results)
 
 	return results, report, nil
@@ -47,6 +51,7 @@ func AnalyzeFromRedis(redisClient *db.RedisClient, key string, opts *AnalyzeOpti
 
 // AnalyzeRecords 对给定的 SummaryRecord 切片进行聚合分析，不依赖 Redis。
 // 适合已经从其他渠道获取到记录的场景。
+// 记录足够时同时分析各分组的内存趋势（见 TrendAnalysis），疑似内存泄漏的分组在报告开头列出。
 func AnalyzeRecords(records []SummaryRecord, opts *AnalyzeOptions) ([]AnalyzeResult, string) {
 	if len(records) == 0 {
 		return nil, "无记录"
@@ -57,8 +62,7 @@ func AnalyzeRecords(records []SummaryRecord, opts *AnalyzeOptions) ([]AnalyzeRes
 		return nil, "过滤后无有效记录"
 	}
 
-	grouped := groupByCPU(filtered)
-	results := analyzeGroups(grouped)
+	results := analyzeGroups(groupRecords(filtered, groupByKeys(opts)), opts)
 	report := formatReport(results)
 
 	return results, report
@@ -68,7 +72,7 @@ func AnalyzeRecords(records []SummaryRecord, opts *AnalyzeOptions) ([]AnalyzeRes
 // 内部实现
 // ---------------------------------------------------------------------------
 
-// parseRecords 解析 JSON 字符串列表并按时间过滤。
+// parseRecords 解析 JSON 字符串列表并按 opts 过滤。
 func parseRecords(values []string, opts *AnalyzeOptions) ([]SummaryRecord, int) {
 	var records []SummaryRecord
 	var parseErrors int
@@ -85,46 +89,124 @@ func parseRecords(values []string, opts *AnalyzeOptions) ([]SummaryRecord, int)
 	return filterRecords(records, opts), parseErrors
 }
 
-// filterRecords 按 Since 时间过滤记录。
+// filterRecords 按 Since 时间和 Labels 过滤记录。
 func filterRecords(records []SummaryRecord, opts *AnalyzeOptions) []SummaryRecord {
-	if opts == nil || opts.Since.IsZero() {
+	if opts == nil || (opts.Since.IsZero() && len(opts.Labels) == 0) {
 		return records
 	}
 
 	var filtered []SummaryRecord
 	for _, r := range records {
-		t, err := time.Parse(time.RFC3339, r.EndedAt)
-		if err != nil {
-			logger.Warnf("monitor: 解析记录时间失败: %s, 错误: %v", r.EndedAt, err)
+		if !matchLabels(r.Labels, opts.Labels) {
 			continue
 		}
-		if t.After(opts.Since) {
-			filtered = append(filtered, r)
+		if !opts.Since.IsZero() {
+			t, err := timeutil.ParseAny(r.EndedAt)
+			if err != nil {
+				log.Warnf("monitor: 解析记录时间失败: %s, 错误: %v", r.EndedAt, err)
+				continue
+			}
+			if !t.After(opts.Since) {
+				continue
+			}
 		}
+		filtered = append(filtered, r)
 	}
 	return filtered
 }
 
-// groupByCPU 按 CPU 核心数分组。
-func groupByCPU(records []SummaryRecord) map[int][]SummaryRecord {
-	grouped := make(map[int][]SummaryRecord)
+// matchLabels 判断 labels 是否包含 want 中的全部键值对。
+func matchLabels(labels, want map[string]string) bool {
+	for k, v := range want {
+		if got, ok := labels[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
+
+// groupByKeys 返回 opts 中的分组标签，opts 为 nil 时返回 nil。
+func groupByKeys(opts *AnalyzeOptions) []string {
+	if opts == nil {
+		return nil
+	}
+	return opts.GroupBy
+}
+
+// recordGroup 按 CPU 核心数（或 CPU 配额）和分组标签聚合的一组记录。
+type recordGroup struct {
+	numCPU   int     // 按核心数分组时有值
+	cpuQuota float64 // 按配额分组时有值
+	labels   map[string]string
+	records  []SummaryRecord
+}
+
+// groupRecords 按 CPU 核心数及 groupBy 中的标签分组，返回按标签、可用 CPU 核数升序排列的分组。
+// 带 cgroup CPU 配额的记录按配额分组（容器中宿主机核心数不代表实际可用 CPU）。
+// 记录缺少某个分组标签时，该标签值视为空串。
+func groupRecords(records []SummaryRecord, groupBy []string) []recordGroup {
+	type groupKey struct {
+		labels   string
+		numCPU   int
+		cpuQuota float64
+	}
+
+	index := make(map[groupKey]int)
+	var groups []recordGroup
 	for _, r := range records {
-		grouped[r.NumCPU] = append(grouped[r.NumCPU], r)
+		var labels map[string]string
+		if len(groupBy) > 0 {
+			labels = make(map[string]string, len(groupBy))
+			for _, k := range groupBy {
+				labels[k] = r.Labels[k]
+			}
+		}
+
+		key := groupKey{labels: formatLabels(labels), numCPU: r.NumCPU}
+		if r.CPUQuota > 0 {
+			key.numCPU, key.cpuQuota = 0, r.CPUQuota
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, recordGroup{numCPU: key.numCPU, cpuQuota: key.cpuQuota, labels: labels})
+		}
+		groups[i].records = append(groups[i].records, r)
 	}
-	return grouped
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		li, lj := formatLabels(groups[i].labels), formatLabels(groups[j].labels)
+		if li != lj {
+			return li < lj
+		}
+		ci, cj := groups[i].cpus(), groups[j].cpus()
+		if ci != cj {
+			return ci < cj
+		}
+		// 可用核数相同时，按核心数分组的排在按配额分组的前面
+		return groups[i].numCPU > groups[j].numCPU
+	})
+	return groups
 }
 
-// analyzeGroups 对分组后的记录进行聚合计算，返回按 CPU 核心数升序排列的结果。
-func analyzeGroups(grouped map[int][]SummaryRecord) []AnalyzeResult {
-	var cpuCounts []int
-	for k := range grouped {
-		cpuCounts = append(cpuCounts, k)
+// cpus 返回分组的可用 CPU 核数。
+func (g recordGroup) cpus() float64 {
+	if g.cpuQuota > 0 {
+		return g.cpuQuota
 	}
-	sort.Ints(cpuCounts)
+	return float64(g.numCPU)
+}
 
-	results := make([]AnalyzeResult, 0, len(cpuCounts))
-	for _, cpu := range cpuCounts {
-		results = append(results, analyzeOneGroup(cpu, grouped[cpu]))
+// analyzeGroups 对分组后的记录逐组进行聚合计算并分析内存趋势，保持分组顺序。opts 仅用于泄漏判定阈值，可为 nil。
+func analyzeGroups(groups []recordGroup, opts *AnalyzeOptions) []AnalyzeResult {
+	results := make([]AnalyzeResult, 0, len(groups))
+	for _, g := range groups {
+		r := analyzeOneGroup(g.numCPU, g.records)
+		r.CPUQuota = g.cpuQuota
+		r.Labels = g.labels
+		r.MemoryTrend = analyzeMemoryTrend(g.records, opts)
+		results = append(results, r)
 	}
 	return results
 }
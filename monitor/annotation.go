@@ -0,0 +1,137 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxAnnotations 内存中最多保留的事件标注数，超出时丢弃最早的。
+const maxAnnotations = 10000
+
+// Annotation 带时间戳的事件标注，用于把 CPU / 内存的波动与应用当时在做的事情对应起来。
+type Annotation struct {
+	Time   time.Time         `json:"time"`
+	Text   string            `json:"text"`
+	Fields map[string]string `json:"fields,omitempty"` // Text 中 key=value 形式的片段，如 "phase=upload start" → {"phase": "upload"}
+}
+
+// AnnotationSummary 从一个标注开始、到下一个标注（或汇总时刻）为止这段时间内的资源使用情况。
+type AnnotationSummary struct {
+	Annotation
+	End          time.Time `json:"end"`
+	SampleCount  int       `json:"sample_count"`
+	CPUMax       float64   `json:"cpu_max"`
+	CPUAvg       float64   `json:"cpu_avg"`
+	MemoryMax    uint64    `json:"memory_max"`
+	MemoryAvg    uint64    `json:"memory_avg"`
+	GoroutineMax int       `json:"goroutine_max"`
+}
+
+// Annotate 记录一条事件标注（时间为当前时间），随采样历史保存，并出现在状态页与 Stop 时的汇总中。
+// 文本中 key=value 形式的片段会解析到 Fields。未启动时也可调用，Start 时清空。
+//
+// 用法：
+//
+//	mon.Annotate("phase=upload start")
+//	uploadAll()
+//	mon.Annotate("phase=index start")
+//	buildIndex()
+//	mon.Stop() // 汇总中输出每个阶段的 CPU / 内存峰值
+func (m *ResourceMonitor) Annotate(text string) {
+	a := Annotation{Time: time.Now(), Text: text, Fields: parseAnnotationFields(text)}
+
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+	if len(m.annotations) >= maxAnnotations {
+		n := copy(m.annotations, m.annotations[len(m.annotations)-maxAnnotations+1:])
+		m.annotations = m.annotations[:n]
+	}
+	m.annotations = append(m.annotations, a)
+}
+
+// Annotatef 同 Annotate，文本按 format 格式化。
+func (m *ResourceMonitor) Annotatef(format string, args ...any) {
+	m.Annotate(fmt.Sprintf(format, args...))
+}
+
+// Annotations 返回本次运行的全部事件标注（按时间升序）的副本。
+func (m *ResourceMonitor) Annotations() []Annotation {
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+	return append([]Annotation(nil), m.annotations...)
+}
+
+// AnnotationSummaries 返回每个标注到下一个标注（最后一个到当前时刻）之间的资源使用情况。
+func (m *ResourceMonitor) AnnotationSummaries() []AnnotationSummary {
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+	return summarizeAnnotations(m.history, m.annotations, time.Now())
+}
+
+// annotationsSince 返回时间不早于 since 的标注副本，since 为零值时返回全部。
+func (m *ResourceMonitor) annotationsSince(since time.Time) []Annotation {
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+
+	var result []Annotation
+	for _, a := range m.annotations {
+		if !a.Time.Before(since) {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+// summarizeAnnotations 按标注把 history 切分为时间段并汇总，最后一段截止到 end。
+// history 与 annotations 均按时间升序。
+func summarizeAnnotations(history []ResourceStats, annotations []Annotation, end time.Time) []AnnotationSummary {
+	if len(annotations) == 0 {
+		return nil
+	}
+	result := make([]AnnotationSummary, len(annotations))
+	j := 0
+	for i, a := range annotations {
+		s := AnnotationSummary{Annotation: a, End: end}
+		if i+1 < len(annotations) {
+			s.End = annotations[i+1].Time
+		}
+
+		var cpuSum float64
+		var memSum uint64
+		for j < len(history) && history[j].Timestamp.Before(a.Time) {
+			j++
+		}
+		for k := j; k < len(history) && history[k].Timestamp.Before(s.End); k++ {
+			h := history[k]
+			s.SampleCount++
+			s.CPUMax = max(s.CPUMax, h.CPUPercent)
+			s.MemoryMax = max(s.MemoryMax, h.MemoryRSS)
+			s.GoroutineMax = max(s.GoroutineMax, h.NumGoroutines)
+			cpuSum += h.CPUPercent
+			memSum += h.MemoryRSS
+		}
+		if s.SampleCount > 0 {
+			s.CPUAvg = cpuSum / float64(s.SampleCount)
+			s.MemoryAvg = memSum / uint64(s.SampleCount)
+		}
+		result[i] = s
+	}
+	return result
+}
+
+// parseAnnotationFields 解析文本中以空白分隔的 key=value 片段，没有时返回 nil。
+func parseAnnotationFields(text string) map[string]string {
+	var fields map[string]string
+	for _, tok := range strings.Fields(text) {
+		k, v, ok := strings.Cut(tok, "=")
+		if !ok || k == "" {
+			continue
+		}
+		if fields == nil {
+			fields = make(map[string]string)
+		}
+		fields[k] = v
+	}
+	return fields
+}
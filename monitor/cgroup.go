@@ -0,0 +1,126 @@
+package monitor
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// cgroupRoot cgroup 文件系统挂载点。
+const cgroupRoot = "/sys/fs/cgroup"
+
+// cgroupV1Unlimited cgroup v1 中内存不限制时 memory.limit_in_bytes 的值接近 int64 上限（按页对齐），
+// 超过该阈值视为不限制。
+const cgroupV1Unlimited = 1 << 62
+
+// CgroupLimits 容器（cgroup）的 CPU / 内存限制。在容器中 runtime.NumCPU 返回的是宿主机核心数，
+// 实际可用 CPU 以 CPUQuota 为准。
+type CgroupLimits struct {
+	Version     int     `json:"version"`                // cgroup 版本（1 或 2），未检测到时为 0
+	CPUQuota    float64 `json:"cpu_quota,omitempty"`    // CPU 配额（核数，如 1.5），0 表示不限制
+	MemoryLimit uint64  `json:"memory_limit,omitempty"` // 内存上限（字节），0 表示不限制
+}
+
+// CPUs 返回实际可用的 CPU 核数：设置了 CPU 配额时返回配额，否则返回 numCPU。
+func (l CgroupLimits) CPUs(numCPU int) float64 {
+	if l.CPUQuota > 0 {
+		return l.CPUQuota
+	}
+	return float64(numCPU)
+}
+
+// DetectCgroupLimits 检测当前进程所在 cgroup（v1 / v2）的 CPU 配额和内存上限。
+// 非 Linux 系统或未运行在受限 cgroup 中时，对应字段为 0。
+func DetectCgroupLimits() CgroupLimits {
+	data, _ := os.ReadFile("/proc/self/cgroup")
+	return detectCgroupLimits(cgroupRoot, string(data))
+}
+
+// detectCgroupLimits 从 root 下的 cgroup 文件系统读取限制，selfCgroup 为 /proc/self/cgroup 的内容。
+func detectCgroupLimits(root, selfCgroup string) CgroupLimits {
+	paths := parseSelfCgroup(selfCgroup)
+
+	// cgroup v2：统一层级，根目录下存在 cgroup.controllers
+	if _, err := os.Stat(filepath.Join(root, "cgroup.controllers")); err == nil {
+		dir := cgroupDir(root, paths[""], "cpu.max")
+		limits := CgroupLimits{Version: 2}
+		if fields := strings.Fields(readCgroupFile(dir, "cpu.max")); len(fields) == 2 && fields[0] != "max" {
+			quota, err1 := strconv.ParseFloat(fields[0], 64)
+			period, err2 := strconv.ParseFloat(fields[1], 64)
+			if err1 == nil && err2 == nil && quota > 0 && period > 0 {
+				limits.CPUQuota = quota / period
+			}
+		}
+		dir = cgroupDir(root, paths[""], "memory.max")
+		if v, err := strconv.ParseUint(readCgroupFile(dir, "memory.max"), 10, 64); err == nil {
+			limits.MemoryLimit = v
+		}
+		return limits
+	}
+
+	// cgroup v1：各控制器独立挂载
+	cpuRoot := filepath.Join(root, "cpu")
+	memRoot := filepath.Join(root, "memory")
+	_, cpuErr := os.Stat(cpuRoot)
+	_, memErr := os.Stat(memRoot)
+	if cpuErr != nil && memErr != nil {
+		return CgroupLimits{}
+	}
+
+	limits := CgroupLimits{Version: 1}
+	dir := cgroupDir(cpuRoot, paths["cpu"], "cpu.cfs_quota_us")
+	quota, err1 := strconv.ParseFloat(readCgroupFile(dir, "cpu.cfs_quota_us"), 64)
+	period, err2 := strconv.ParseFloat(readCgroupFile(dir, "cpu.cfs_period_us"), 64)
+	if err1 == nil && err2 == nil && quota > 0 && period > 0 {
+		limits.CPUQuota = quota / period
+	}
+	dir = cgroupDir(memRoot, paths["memory"], "memory.limit_in_bytes")
+	if v, err := strconv.ParseUint(readCgroupFile(dir, "memory.limit_in_bytes"), 10, 64); err == nil && v < cgroupV1Unlimited {
+		limits.MemoryLimit = v
+	}
+	return limits
+}
+
+// parseSelfCgroup 解析 /proc/self/cgroup，返回控制器到 cgroup 路径的映射，v2 统一层级的 key 为空串。
+// 每行格式为 "hierarchy-ID:controller-list:path"，如 "4:cpu,cpuacct:/docker/abc" 或 "0::/"。
+func parseSelfCgroup(content string) map[string]string {
+	paths := make(map[string]string)
+	sc := bufio.NewScanner(strings.NewReader(content))
+	for sc.Scan() {
+		parts := strings.SplitN(sc.Text(), ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if parts[1] == "" {
+			paths[""] = parts[2]
+			continue
+		}
+		for _, c := range strings.Split(parts[1], ",") {
+			paths[c] = parts[2]
+		}
+	}
+	return paths
+}
+
+// cgroupDir 返回包含 file 的 cgroup 目录：优先使用 root 下的进程 cgroup 路径，
+// 不存在时（容器内启用了 cgroup namespace 或只挂载了自身 cgroup）回退到 root。
+func cgroupDir(root, path, file string) string {
+	if path != "" && path != "/" {
+		dir := filepath.Join(root, path)
+		if _, err := os.Stat(filepath.Join(dir, file)); err == nil {
+			return dir
+		}
+	}
+	return root
+}
+
+// readCgroupFile 读取 cgroup 文件内容（去除首尾空白），失败时返回空串。
+func readCgroupFile(dir, file string) string {
+	data, err := os.ReadFile(filepath.Join(dir, file))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
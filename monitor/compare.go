@@ -0,0 +1,243 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pylemonorg/gotools/db"
+	"github.com/pylemonorg/gotools/strutil"
+)
+
+// CompareResult 同一分组（CPU 核心数或 CPU 配额 + GroupBy 标签）在 A、B 两组记录中的对比结果。
+// 某一侧没有该分组的记录时，对应的 A 或 B 为 nil，各 Delta 为 0。
+type CompareResult struct {
+	NumCPU   int               // CPU 核心数（按配额分组时为 0）
+	CPUQuota float64           // CPU 配额（核数），按核心数分组时为 0
+	Labels   map[string]string // 分组标签（仅包含 GroupBy 中的 key），未分组时为 nil
+	A        *AnalyzeResult
+	B        *AnalyzeResult
+
+	// 以下为 B 相对 A 的变化百分比（(B-A)/A*100），A 为 0 时为 0
+	CPUAvgDelta       float64
+	CPUMaxDelta       float64
+	MemoryAvgDelta    float64
+	MemoryMaxDelta    float64
+	GoroutineAvgDelta float64
+	GoroutineMaxDelta float64
+}
+
+// CompareFromRedis 分别读取 keyA、keyB 两个 Redis List 中的资源汇总记录，按相同规则分组聚合后逐组对比，
+// 返回对比结果和并排展示 A / B 及变化百分比的报告，适合直接对比两个版本的运行数据。
+// opts 同时作用于两侧（过滤与分组）。
+//
+// 用法：
+//
+//	results, report, err := monitor.CompareFromRedis(redisClient,
+//	    "resource:summary:crawler:v1.4", "resource:summary:crawler:v1.5", nil)
+//	fmt.Println(report)
+func CompareFromRedis(redisClient *db.RedisClient, keyA, keyB string, opts *AnalyzeOptions) ([]CompareResult, string, error) {
+	recordsA, err := loadRecords(redisClient, keyA, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	recordsB, err := loadRecords(redisClient, keyB, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	results := compareGroups(recordsA, recordsB, groupByKeys(opts))
+	return results, formatCompareReport(results, keyA, keyB), nil
+}
+
+// CompareRecords 对两组 SummaryRecord 分别过滤、分组聚合后逐组对比，不依赖 Redis。
+func CompareRecords(a, b []SummaryRecord, opts *AnalyzeOptions) ([]CompareResult, string) {
+	results := compareGroups(filterRecords(a, opts), filterRecords(b, opts), groupByKeys(opts))
+	return results, formatCompareReport(results, "A", "B")
+}
+
+// ---------------------------------------------------------------------------
+// 内部实现
+// ---------------------------------------------------------------------------
+
+// loadRecords 从 Redis List 读取并解析、过滤汇总记录。
+func loadRecords(redisClient *db.RedisClient, key string, opts *AnalyzeOptions) ([]SummaryRecord, error) {
+	values, err := redisClient.LRange(key, 0, -1)
+	if err != nil {
+		return nil, fmt.Errorf("monitor: LRANGE [%s] 失败: %w", key, err)
+	}
+	records, parseErrors := parseRecords(values, opts)
+	if parseErrors > 0 {
+		log.Warnf("monitor: [%s] 解析 %d 条记录失败", key, parseErrors)
+	}
+	log.Infof("monitor: 从 Redis key [%s] 读取到 %d 条记录，过滤后 %d 条", key, len(values), len(records))
+	return records, nil
+}
+
+// compareGroups 分别聚合 a、b 后按分组配对，顺序与 groupRecords 一致（A、B 中的分组合并排序）。
+func compareGroups(a, b []SummaryRecord, groupBy []string) []CompareResult {
+	var results []CompareResult
+	index := make(map[string]int)
+
+	add := func(r AnalyzeResult, isA bool) {
+		key := fmt.Sprintf("%s|%d|%g", formatLabels(r.Labels), r.NumCPU, r.CPUQuota)
+		i, ok := index[key]
+		if !ok {
+			i = len(results)
+			index[key] = i
+			results = append(results, CompareResult{NumCPU: r.NumCPU, CPUQuota: r.CPUQuota, Labels: r.Labels})
+		}
+		if isA {
+			results[i].A = &r
+		} else {
+			results[i].B = &r
+		}
+	}
+	for _, r := range analyzeGroups(groupRecords(a, groupBy), nil) {
+		add(r, true)
+	}
+	for _, r := range analyzeGroups(groupRecords(b, groupBy), nil) {
+		add(r, false)
+	}
+
+	for i := range results {
+		c := &results[i]
+		if c.A == nil || c.B == nil {
+			continue
+		}
+		c.CPUAvgDelta = deltaPercent(c.A.CPUAvg, c.B.CPUAvg)
+		c.CPUMaxDelta = deltaPercent(c.A.CPUMax, c.B.CPUMax)
+		c.MemoryAvgDelta = deltaPercent(float64(c.A.MemoryAvg), float64(c.B.MemoryAvg))
+		c.MemoryMaxDelta = deltaPercent(float64(c.A.MemoryMax), float64(c.B.MemoryMax))
+		c.GoroutineAvgDelta = deltaPercent(float64(c.A.GoroutineAvg), float64(c.B.GoroutineAvg))
+		c.GoroutineMaxDelta = deltaPercent(float64(c.A.GoroutineMax), float64(c.B.GoroutineMax))
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		li, lj := formatLabels(results[i].Labels), formatLabels(results[j].Labels)
+		if li != lj {
+			return li < lj
+		}
+		ci, cj := results[i].cpus(), results[j].cpus()
+		if ci != cj {
+			return ci < cj
+		}
+		return results[i].NumCPU > results[j].NumCPU
+	})
+	return results
+}
+
+// cpus 返回分组的可用 CPU 核数。
+func (c CompareResult) cpus() float64 {
+	if c.CPUQuota > 0 {
+		return c.CPUQuota
+	}
+	return float64(c.NumCPU)
+}
+
+// deltaPercent 返回 b 相对 a 的变化百分比，a 为 0 时返回 0。
+func deltaPercent(a, b float64) float64 {
+	if a == 0 {
+		return 0
+	}
+	return (b - a) / a * 100
+}
+
+// ---------------------------------------------------------------------------
+// 对比报告格式化
+// ---------------------------------------------------------------------------
+
+// formatCompareReport 将对比结果格式化为并排表格，nameA / nameB 为两侧的名称（如 Redis key）。
+func formatCompareReport(results []CompareResult, nameA, nameB string) string {
+	var buf strings.Builder
+	w := tabwriter.NewWriter(&buf, 0, 0, 3, ' ', 0)
+
+	fmt.Fprintln(w, "\n========================================= 资源对比 =========================================")
+	fmt.Fprintf(w, "A: %s\n", nameA)
+	fmt.Fprintf(w, "B: %s\n\n", nameB)
+
+	if len(results) == 0 {
+		fmt.Fprintln(w, "无记录")
+	}
+	for _, c := range results {
+		formatOneCompare(w, c)
+	}
+
+	w.Flush()
+	return buf.String()
+}
+
+// formatOneCompare 格式化单个分组的对比。
+func formatOneCompare(w *tabwriter.Writer, c CompareResult) {
+	col1, col2, col3, col4 := 18, 18, 18, 12
+
+	if len(c.Labels) > 0 {
+		fmt.Fprintf(w, "标签: %s\n", formatLabels(c.Labels))
+	}
+	if c.CPUQuota > 0 {
+		fmt.Fprintf(w, "CPU 配额: %.2f 核\t(A 样本数: %d, B 样本数: %d)\n", c.CPUQuota, totalSamples(c.A), totalSamples(c.B))
+	} else {
+		fmt.Fprintf(w, "CPU 核心数: %d\t(A 样本数: %d, B 样本数: %d)\n", c.NumCPU, totalSamples(c.A), totalSamples(c.B))
+	}
+	fmt.Fprintln(w, strings.Repeat("-", 100))
+
+	row := func(name, a, b, delta string) {
+		fmt.Fprintf(w, "%s%s%s%s\n",
+			strutil.PadRight(name, col1),
+			strutil.PadRight(a, col2),
+			strutil.PadRight(b, col3),
+			strutil.PadRight(delta, col4))
+	}
+	row("指标", "A", "B", "变化")
+	row("------", "---", "---", "---")
+
+	both := c.A != nil && c.B != nil
+	delta := func(d float64) string {
+		if !both {
+			return "-"
+		}
+		return fmt.Sprintf("%+.1f%%", d)
+	}
+	pick := func(r *AnalyzeResult, f func(*AnalyzeResult) string) string {
+		if r == nil {
+			return "-"
+		}
+		return f(r)
+	}
+
+	row("CPU 平均 (%)",
+		pick(c.A, func(r *AnalyzeResult) string { return fmt.Sprintf("%.2f", r.CPUAvg) }),
+		pick(c.B, func(r *AnalyzeResult) string { return fmt.Sprintf("%.2f", r.CPUAvg) }),
+		delta(c.CPUAvgDelta))
+	row("CPU 最大 (%)",
+		pick(c.A, func(r *AnalyzeResult) string { return fmt.Sprintf("%.2f", r.CPUMax) }),
+		pick(c.B, func(r *AnalyzeResult) string { return fmt.Sprintf("%.2f", r.CPUMax) }),
+		delta(c.CPUMaxDelta))
+	row("内存平均",
+		pick(c.A, func(r *AnalyzeResult) string { return FormatBytes(r.MemoryAvg) }),
+		pick(c.B, func(r *AnalyzeResult) string { return FormatBytes(r.MemoryAvg) }),
+		delta(c.MemoryAvgDelta))
+	row("内存最大",
+		pick(c.A, func(r *AnalyzeResult) string { return FormatBytes(r.MemoryMax) }),
+		pick(c.B, func(r *AnalyzeResult) string { return FormatBytes(r.MemoryMax) }),
+		delta(c.MemoryMaxDelta))
+	row("协程平均",
+		pick(c.A, func(r *AnalyzeResult) string { return fmt.Sprintf("%d", r.GoroutineAvg) }),
+		pick(c.B, func(r *AnalyzeResult) string { return fmt.Sprintf("%d", r.GoroutineAvg) }),
+		delta(c.GoroutineAvgDelta))
+	row("协程最大",
+		pick(c.A, func(r *AnalyzeResult) string { return fmt.Sprintf("%d", r.GoroutineMax) }),
+		pick(c.B, func(r *AnalyzeResult) string { return fmt.Sprintf("%d", r.GoroutineMax) }),
+		delta(c.GoroutineMaxDelta))
+
+	fmt.Fprintln(w)
+}
+
+// totalSamples 返回分析结果的总样本数，r 为 nil 时返回 0。
+func totalSamples(r *AnalyzeResult) int {
+	if r == nil {
+		return 0
+	}
+	return r.TotalSamples
+}
@@ -0,0 +1,110 @@
+package monitor
+
+import (
+	"sort"
+
+	"github.com/pylemonorg/gotools/excel"
+)
+
+// ExportAnalyzeExcel 将分析结果导出为 xlsx 文件（覆盖已有文件），每个分组一行，分组标签展开为前置列。
+//
+// 用法：
+//
+//	results, _, err := monitor.AnalyzeFromRedis(redisClient, key, &monitor.AnalyzeOptions{
+//	    GroupBy: []string{"version"},
+//	})
+//	err = monitor.ExportAnalyzeExcel("resource_report.xlsx", results)
+func ExportAnalyzeExcel(path string, results []AnalyzeResult) error {
+	wb := excel.NewWorkbook()
+	defer wb.Close()
+	if err := WriteAnalyzeSheet(wb, "资源分析", results); err != nil {
+		return err
+	}
+	return wb.SaveAs(path)
+}
+
+// WriteAnalyzeSheet 将分析结果写入工作簿的工作表 sheet，便于与其他报表合并到同一个文件。
+// 内存列单位为 MB，CPU 列单位为 %；按 CPU 配额分组的行 "CPU 核心数" 为空，"CPU 平均/核心" 按配额计算；
+// 没有内存趋势（记录不足）的行趋势列为空。
+func WriteAnalyzeSheet(wb *excel.Workbook, sheet string, results []AnalyzeResult) error {
+	labelKeys := analyzeLabelKeys(results)
+
+	cols := make([]excel.Column, 0, len(labelKeys)+17)
+	for _, k := range labelKeys {
+		cols = append(cols, excel.Column{Name: k})
+	}
+	cols = append(cols,
+		excel.Column{Name: "CPU 核心数"},
+		excel.Column{Name: "记录数"},
+		excel.Column{Name: "样本数"},
+		excel.Column{Name: "CPU 最小值 (%)", Format: "0.00"},
+		excel.Column{Name: "CPU 最大值 (%)", Format: "0.00"},
+		excel.Column{Name: "CPU 加权平均 (%)", Format: "0.00"},
+		excel.Column{Name: "CPU 平均/核心 (%)", Format: "0.00"},
+		excel.Column{Name: "内存最小值 (MB)", Format: "0.00"},
+		excel.Column{Name: "内存最大值 (MB)", Format: "0.00"},
+		excel.Column{Name: "内存加权平均 (MB)", Format: "0.00"},
+		excel.Column{Name: "协程最小数"},
+		excel.Column{Name: "协程最大数"},
+		excel.Column{Name: "协程加权平均"},
+		excel.Column{Name: "CPU 配额 (核)", Format: "0.00"},
+		excel.Column{Name: "内存日增长 (%)", Format: "0.00"},
+		excel.Column{Name: "内存趋势 R²", Format: "0.00"},
+		excel.Column{Name: "疑似内存泄漏"},
+	)
+
+	rows := make([][]any, len(results))
+	for i, r := range results {
+		row := make([]any, 0, len(cols))
+		for _, k := range labelKeys {
+			row = append(row, r.Labels[k])
+		}
+		var numCPU, perCore, quota any
+		if r.NumCPU > 0 {
+			numCPU = r.NumCPU
+		}
+		if cpus := r.CPUs(); cpus > 0 {
+			perCore = r.CPUAvg / cpus
+		}
+		if r.CPUQuota > 0 {
+			quota = r.CPUQuota
+		}
+		var growth, r2, leak any
+		if t := r.MemoryTrend; t != nil {
+			growth, r2 = t.GrowthPerDay, t.R2
+			if t.Leak {
+				leak = "是"
+			}
+		}
+		row = append(row,
+			numCPU, r.RecordCount, r.TotalSamples,
+			r.CPUMin, r.CPUMax, r.CPUAvg, perCore,
+			toMB(r.MemoryMin), toMB(r.MemoryMax), toMB(r.MemoryAvg),
+			r.GoroutineMin, r.GoroutineMax, r.GoroutineAvg, quota,
+			growth, r2, leak,
+		)
+		rows[i] = row
+	}
+	return wb.WriteRows(sheet, cols, rows)
+}
+
+// analyzeLabelKeys 返回所有分析结果中出现过的标签 key（升序）。
+func analyzeLabelKeys(results []AnalyzeResult) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, r := range results {
+		for k := range r.Labels {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// toMB 将字节数转换为 MB。
+func toMB(bytes uint64) float64 {
+	return float64(bytes) / (1024 * 1024)
+}
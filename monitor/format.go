@@ -2,8 +2,12 @@ package monitor
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"text/tabwriter"
+	"time"
+
+	"github.com/pylemonorg/gotools/strutil"
 )
 
 // FormatBytes 将字节数格式化为人类可读的字符串（B / KB / MB / GB）。
@@ -36,6 +40,7 @@ func formatReport(results []AnalyzeResult) string {
 
 	fmt.Fprintln(w, "\n========================================= 资源分析 =========================================")
 
+	formatLeaks(w, results)
 	for _, r := range results {
 		formatOneGroup(w, r)
 	}
@@ -44,77 +49,123 @@ func formatReport(results []AnalyzeResult) string {
 	return buf.String()
 }
 
-// formatOneGroup 格式化单个 CPU 分组的报告。
+// formatOneGroup 格式化单个分组的报告。
 func formatOneGroup(w *tabwriter.Writer, r AnalyzeResult) {
 	col1, col2, col3, col4, col5 := 18, 15, 15, 15, 15
 
-	fmt.Fprintf(w, "CPU 核心数: %d\t(总记录数: %d, 总样本数: %d)\n", r.NumCPU, r.RecordCount, r.TotalSamples)
+	if len(r.Labels) > 0 {
+		fmt.Fprintf(w, "标签: %s\n", formatLabels(r.Labels))
+	}
+	if r.CPUQuota > 0 {
+		fmt.Fprintf(w, "CPU 配额: %.2f 核\t(总记录数: %d, 总样本数: %d)\n", r.CPUQuota, r.RecordCount, r.TotalSamples)
+	} else {
+		fmt.Fprintf(w, "CPU 核心数: %d\t(总记录数: %d, 总样本数: %d)\n", r.NumCPU, r.RecordCount, r.TotalSamples)
+	}
 	fmt.Fprintln(w, strings.Repeat("-", 100))
 
 	// 表头
 	fmt.Fprintf(w, "%s%s%s%s%s\n",
-		padRightCJK("指标", col1),
-		padRightCJK("最小值", col2),
-		padRightCJK("最大值", col3),
-		padRightCJK("加权平均值", col4),
-		padRightCJK("平均值/核心", col5))
+		strutil.PadRight("指标", col1),
+		strutil.PadRight("最小值", col2),
+		strutil.PadRight("最大值", col3),
+		strutil.PadRight("加权平均值", col4),
+		strutil.PadRight("平均值/核心", col5))
 
 	fmt.Fprintf(w, "%s%s%s%s%s\n",
-		padRightCJK("------", col1),
-		padRightCJK("---", col2),
-		padRightCJK("---", col3),
-		padRightCJK("--------", col4),
-		padRightCJK("--------", col5))
+		strutil.PadRight("------", col1),
+		strutil.PadRight("---", col2),
+		strutil.PadRight("---", col3),
+		strutil.PadRight("--------", col4),
+		strutil.PadRight("--------", col5))
 
 	// CPU
 	perCore := "-"
-	if r.NumCPU > 0 {
-		perCore = fmt.Sprintf("%.2f", r.CPUAvg/float64(r.NumCPU))
+	if cpus := r.CPUs(); cpus > 0 {
+		perCore = fmt.Sprintf("%.2f", r.CPUAvg/cpus)
 	}
 	fmt.Fprintf(w, "%s%s%s%s%s\n",
-		padRightCJK("CPU使用率 (%)", col1),
-		padRightCJK(fmt.Sprintf("%.2f", r.CPUMin), col2),
-		padRightCJK(fmt.Sprintf("%.2f", r.CPUMax), col3),
-		padRightCJK(fmt.Sprintf("%.2f", r.CPUAvg), col4),
-		padRightCJK(perCore, col5))
+		strutil.PadRight("CPU使用率 (%)", col1),
+		strutil.PadRight(fmt.Sprintf("%.2f", r.CPUMin), col2),
+		strutil.PadRight(fmt.Sprintf("%.2f", r.CPUMax), col3),
+		strutil.PadRight(fmt.Sprintf("%.2f", r.CPUAvg), col4),
+		strutil.PadRight(perCore, col5))
 
 	// 内存
 	fmt.Fprintf(w, "%s%s%s%s%s\n",
-		padRightCJK("内存", col1),
-		padRightCJK(FormatBytes(r.MemoryMin), col2),
-		padRightCJK(FormatBytes(r.MemoryMax), col3),
-		padRightCJK(FormatBytes(r.MemoryAvg), col4),
-		padRightCJK("-", col5))
+		strutil.PadRight("内存", col1),
+		strutil.PadRight(FormatBytes(r.MemoryMin), col2),
+		strutil.PadRight(FormatBytes(r.MemoryMax), col3),
+		strutil.PadRight(FormatBytes(r.MemoryAvg), col4),
+		strutil.PadRight("-", col5))
 
 	// Goroutine
 	fmt.Fprintf(w, "%s%s%s%s%s\n",
-		padRightCJK("协程数", col1),
-		padRightCJK(fmt.Sprintf("%d", r.GoroutineMin), col2),
-		padRightCJK(fmt.Sprintf("%d", r.GoroutineMax), col3),
-		padRightCJK(fmt.Sprintf("%d", r.GoroutineAvg), col4),
-		padRightCJK("-", col5))
+		strutil.PadRight("协程数", col1),
+		strutil.PadRight(fmt.Sprintf("%d", r.GoroutineMin), col2),
+		strutil.PadRight(fmt.Sprintf("%d", r.GoroutineMax), col3),
+		strutil.PadRight(fmt.Sprintf("%d", r.GoroutineAvg), col4),
+		strutil.PadRight("-", col5))
+
+	if t := r.MemoryTrend; t != nil {
+		leak := ""
+		if t.Leak {
+			leak = "  [疑似内存泄漏]"
+		}
+		fmt.Fprintf(w, "内存趋势: %s%s\n", formatTrend(t), leak)
+	}
 
 	fmt.Fprintln(w)
 }
 
-// cjkWidth 计算字符串显示宽度（CJK 字符算 2，ASCII 算 1）。
-func cjkWidth(s string) int {
-	n := 0
-	for _, r := range s {
-		if r > 127 {
-			n += 2
-		} else {
-			n++
+// formatLeaks 在报告开头列出疑似内存泄漏的分组，没有时不输出。
+func formatLeaks(w *tabwriter.Writer, results []AnalyzeResult) {
+	var lines []string
+	for _, r := range results {
+		if r.MemoryTrend == nil || !r.MemoryTrend.Leak {
+			continue
+		}
+		group := fmt.Sprintf("CPU 核心数 %d", r.NumCPU)
+		if r.CPUQuota > 0 {
+			group = fmt.Sprintf("CPU 配额 %.2f 核", r.CPUQuota)
+		}
+		if len(r.Labels) > 0 {
+			group = formatLabels(r.Labels) + ", " + group
 		}
+		lines = append(lines, fmt.Sprintf("  - [%s] %s", group, formatTrend(r.MemoryTrend)))
 	}
-	return n
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "疑似内存泄漏（%d 个分组）:\n%s\n\n", len(lines), strings.Join(lines, "\n"))
+}
+
+// formatTrend 将内存趋势格式化为一行，如 "+8.10%/天 (+12.00 MB/天, R²=0.92, 12 条记录, 2026-01-02 ~ 2026-01-09)"。
+func formatTrend(t *TrendAnalysis) string {
+	perDay := t.SlopePerHour * 24
+	sign := "+"
+	if perDay < 0 {
+		sign = "-"
+		perDay = -perDay
+	}
+	return fmt.Sprintf("%+.2f%%/天 (%s%s/天, R²=%.2f, %d 条记录, %s ~ %s)",
+		t.GrowthPerDay, sign, FormatBytes(uint64(perDay)), t.R2, t.Points,
+		t.From.Format(time.DateOnly), t.To.Format(time.DateOnly))
 }
 
-// padRightCJK 按显示宽度右填充空格，正确处理中文字符。
-func padRightCJK(s string, width int) string {
-	w := cjkWidth(s)
-	if w >= width {
-		return s
+// formatLabels 将标签格式化为按 key 排序的 "k1=v1, k2=v2"，空标签返回空串。
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + labels[k]
 	}
-	return s + strings.Repeat(" ", width-w)
+	return strings.Join(parts, ", ")
 }
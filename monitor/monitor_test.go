@@ -2,8 +2,15 @@ package monitor
 
 import (
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/xuri/excelize/v2"
 )
 
 // ---------------------------------------------------------------------------
@@ -32,42 +39,6 @@ func TestFormatBytes(t *testing.T) {
 	}
 }
 
-// ---------------------------------------------------------------------------
-// padRightCJK / cjkWidth
-// ---------------------------------------------------------------------------
-
-func TestCJKWidth(t *testing.T) {
-	tests := []struct {
-		input    string
-		expected int
-	}{
-		{"hello", 5},
-		{"你好", 4},
-		{"CPU使用率", 9},
-		{"", 0},
-		{"abc你好def", 10},
-	}
-
-	for _, tt := range tests {
-		result := cjkWidth(tt.input)
-		if result != tt.expected {
-			t.Errorf("cjkWidth(%q) = %d, 期望 %d", tt.input, result, tt.expected)
-		}
-	}
-}
-
-func TestPadRightCJK(t *testing.T) {
-	result := padRightCJK("你好", 10)
-	if cjkWidth(result) != 10 {
-		t.Errorf("padRightCJK 后宽度应为 10, 实际 %d", cjkWidth(result))
-	}
-
-	result = padRightCJK("hello", 5)
-	if result != "hello" {
-		t.Errorf("不需要填充时应原样返回, 实际 %q", result)
-	}
-}
-
 // ---------------------------------------------------------------------------
 // analyzeOneGroup
 // ---------------------------------------------------------------------------
@@ -179,6 +150,16 @@ func TestFilterRecords(t *testing.T) {
 	if len(result) != 2 {
 		t.Errorf("过滤后应返回 2 条, 实际 %d", len(result))
 	}
+
+	// 非 RFC3339 格式的记录时间（毫秒时间戳、"2006-01-02 15:04:05"）
+	mixed := []SummaryRecord{
+		{EndedAt: "1771120800000", ResourceSummary: ResourceSummary{SampleCount: 10}}, // 2026-02-15T10:00:00+08:00
+		{EndedAt: "2026-02-17 10:00:00", ResourceSummary: ResourceSummary{SampleCount: 30}},
+	}
+	result = filterRecords(mixed, &AnalyzeOptions{Since: since})
+	if len(result) != 1 || result[0].SampleCount != 30 {
+		t.Errorf("混合格式过滤后应只剩 SampleCount=30 的记录, 实际 %+v", result)
+	}
 }
 
 // ---------------------------------------------------------------------------
@@ -274,3 +255,524 @@ func TestAnalyzeRecordsEmpty(t *testing.T) {
 		t.Errorf("空输入报告 = %q, 期望 %q", report, "无记录")
 	}
 }
+
+// ---------------------------------------------------------------------------
+// resolveConfig / Option
+// ---------------------------------------------------------------------------
+
+func TestResolveConfigDefaults(t *testing.T) {
+	c := resolveConfig(nil, nil)
+	if c.Interval != defaultInterval {
+		t.Errorf("Interval = %v, 期望 %v", c.Interval, defaultInterval)
+	}
+	if c.LogInterval != defaultInterval {
+		t.Errorf("LogInterval = %v, 期望 %v", c.LogInterval, defaultInterval)
+	}
+}
+
+func TestResolveConfigOptions(t *testing.T) {
+	cfg := &Config{Interval: time.Second, SaveKey: "from-config"}
+	c := resolveConfig(cfg, []Option{
+		WithInterval(5 * time.Second),
+		WithSaver(nil, "from-option"),
+	})
+
+	if c.Interval != 5*time.Second {
+		t.Errorf("Interval = %v, 期望 5s", c.Interval)
+	}
+	// LogInterval 未设置时跟随最终的 Interval
+	if c.LogInterval != 5*time.Second {
+		t.Errorf("LogInterval = %v, 期望 5s", c.LogInterval)
+	}
+	if c.SaveKey != "from-option" {
+		t.Errorf("SaveKey = %q, 期望 %q", c.SaveKey, "from-option")
+	}
+	// 调用方的 cfg 不应被修改
+	if cfg.Interval != time.Second || cfg.SaveKey != "from-config" {
+		t.Errorf("原始 cfg 被修改: %+v", cfg)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Labels 过滤 / 分组
+// ---------------------------------------------------------------------------
+
+func TestAnalyzeRecordsLabels(t *testing.T) {
+	records := []SummaryRecord{
+		{NumCPU: 4, Labels: map[string]string{"app": "crawler", "version": "v1"}, ResourceSummary: ResourceSummary{SampleCount: 10, CPUAvg: 10}},
+		{NumCPU: 4, Labels: map[string]string{"app": "crawler", "version": "v2"}, ResourceSummary: ResourceSummary{SampleCount: 10, CPUAvg: 20}},
+		{NumCPU: 4, Labels: map[string]string{"app": "crawler", "version": "v2"}, ResourceSummary: ResourceSummary{SampleCount: 10, CPUAvg: 40}},
+		{NumCPU: 4, Labels: map[string]string{"app": "packer", "version": "v1"}, ResourceSummary: ResourceSummary{SampleCount: 10, CPUAvg: 90}},
+		{NumCPU: 4, ResourceSummary: ResourceSummary{SampleCount: 10, CPUAvg: 99}},
+	}
+
+	results, report := AnalyzeRecords(records, &AnalyzeOptions{
+		Labels:  map[string]string{"app": "crawler"},
+		GroupBy: []string{"version"},
+	})
+	if len(results) != 2 {
+		t.Fatalf("应返回 2 个分组, 实际 %d", len(results))
+	}
+	if results[0].Labels["version"] != "v1" || results[0].RecordCount != 1 {
+		t.Errorf("第一组 = %+v, 期望 version=v1 且 1 条记录", results[0])
+	}
+	if results[1].Labels["version"] != "v2" || results[1].RecordCount != 2 {
+		t.Errorf("第二组 = %+v, 期望 version=v2 且 2 条记录", results[1])
+	}
+	if results[1].CPUAvg != 30 {
+		t.Errorf("第二组 CPUAvg = %.1f, 期望 30.0", results[1].CPUAvg)
+	}
+	if !strings.Contains(report, "标签: version=v2") {
+		t.Errorf("报告应包含分组标签, 实际:\n%s", report)
+	}
+}
+
+func TestSummaryRecordLabelsJSON(t *testing.T) {
+	data, err := json.Marshal(SummaryRecord{NumCPU: 2})
+	if err != nil {
+		t.Fatalf("Marshal 失败: %v", err)
+	}
+	if strings.Contains(string(data), "labels") {
+		t.Errorf("无标签时不应输出 labels 字段: %s", data)
+	}
+
+	var decoded SummaryRecord
+	if err = json.Unmarshal([]byte(`{"num_cpu":2,"labels":{"app":"x"}}`), &decoded); err != nil {
+		t.Fatalf("Unmarshal 失败: %v", err)
+	}
+	if decoded.Labels["app"] != "x" {
+		t.Errorf("Labels = %v, 期望 app=x", decoded.Labels)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// goroutineWatchdog
+// ---------------------------------------------------------------------------
+
+func TestGoroutineWatchdogObserve(t *testing.T) {
+	w := newGoroutineWatchdog(&GoroutineWatchdog{Threshold: 10, Samples: 3})
+
+	// 低于阈值时即使持续增长也不触发
+	for _, n := range []int{2, 4, 6, 8} {
+		if w.observe(n) {
+			t.Fatalf("协程数 %d 未超过阈值，不应触发", n)
+		}
+	}
+	// 回落后重新计数
+	if w.observe(5) {
+		t.Fatal("回落时不应触发")
+	}
+	for _, n := range []int{11, 12} {
+		if w.observe(n) {
+			t.Fatalf("连续增长次数不足，协程数 %d 不应触发", n)
+		}
+	}
+	if !w.observe(13) {
+		t.Fatal("连续 3 次增长且超过阈值，应触发")
+	}
+	// 只触发一次
+	if w.observe(14) {
+		t.Fatal("同一轮运行只应触发一次")
+	}
+
+	w.reset()
+	for _, n := range []int{11, 12, 13} {
+		w.observe(n)
+	}
+	if !w.observe(14) {
+		t.Fatal("reset 后应可再次触发")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// summarizeProcesses
+// ---------------------------------------------------------------------------
+
+func TestSummarizeProcesses(t *testing.T) {
+	history := []ResourceStats{
+		{
+			Processes:       []ProcessStats{{PID: 200, CPUPercent: 10, MemoryRSS: 100}, {PID: 100, CPUPercent: 50, MemoryRSS: 300}},
+			TotalCPUPercent: 70, TotalMemoryRSS: 500,
+		},
+		{
+			// PID 200 已退出
+			Processes:       []ProcessStats{{PID: 100, CPUPercent: 30, MemoryRSS: 100}},
+			TotalCPUPercent: 40, TotalMemoryRSS: 200,
+		},
+	}
+
+	processes, aggregate := summarizeProcesses(history)
+	if len(processes) != 2 {
+		t.Fatalf("应返回 2 个进程汇总, 实际 %d", len(processes))
+	}
+	p100, p200 := processes[0], processes[1]
+	if p100.PID != 100 || p100.SampleCount != 2 || p100.CPUMin != 30 || p100.CPUMax != 50 || p100.CPUAvg != 40 {
+		t.Errorf("进程 100 汇总 = %+v", p100)
+	}
+	if p100.MemoryMin != 100 || p100.MemoryMax != 300 || p100.MemoryAvg != 200 {
+		t.Errorf("进程 100 内存汇总 = %+v", p100)
+	}
+	if p200.PID != 200 || p200.SampleCount != 1 || p200.CPUAvg != 10 {
+		t.Errorf("进程 200 汇总 = %+v", p200)
+	}
+	if aggregate == nil || aggregate.SampleCount != 2 || aggregate.CPUAvg != 55 || aggregate.MemoryMax != 500 {
+		t.Errorf("合计汇总 = %+v", aggregate)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// ExportAnalyzeExcel
+// ---------------------------------------------------------------------------
+
+func TestExportAnalyzeExcel(t *testing.T) {
+	results := []AnalyzeResult{
+		{NumCPU: 4, Labels: map[string]string{"version": "v1"}, RecordCount: 2, TotalSamples: 10,
+			CPUMin: 10, CPUMax: 50, CPUAvg: 20, MemoryAvg: 64 * 1024 * 1024, GoroutineAvg: 12},
+		{NumCPU: 8, Labels: map[string]string{"app": "crawler"}, RecordCount: 1},
+	}
+
+	path := filepath.Join(t.TempDir(), "report.xlsx")
+	if err := ExportAnalyzeExcel(path, results); err != nil {
+		t.Fatalf("ExportAnalyzeExcel: %v", err)
+	}
+
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	defer f.Close()
+	rows, err := f.GetRows("资源分析", excelize.Options{RawCellValue: true})
+	if err != nil {
+		t.Fatalf("GetRows: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want 3", len(rows))
+	}
+	if rows[0][0] != "app" || rows[0][1] != "version" || rows[0][2] != "CPU 核心数" {
+		t.Errorf("header = %v", rows[0])
+	}
+	// 第一行：app 为空，version=v1，CPU 平均/核心 = 20/4，内存加权平均 64 MB
+	if rows[1][1] != "v1" || rows[1][2] != "4" || rows[1][8] != "5" || rows[1][11] != "64" {
+		t.Errorf("row 1 = %v", rows[1])
+	}
+	if rows[2][0] != "crawler" {
+		t.Errorf("row 2 = %v", rows[2])
+	}
+}
+
+// ---------------------------------------------------------------------------
+// StatusHandler
+// ---------------------------------------------------------------------------
+
+func TestStatusHandler(t *testing.T) {
+	mon, err := NewResourceMonitor(nil, WithLabels(map[string]string{"app": "crawler"}))
+	if err != nil {
+		t.Fatalf("NewResourceMonitor: %v", err)
+	}
+	for i := range 5 {
+		mon.history = append(mon.history, ResourceStats{CPUPercent: float64(i * 10), NumGoroutines: i + 1})
+	}
+	handler := mon.StatusHandler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status?n=3", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	var report StatusReport
+	if err = json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if len(report.Recent) != 3 || report.Recent[0].NumGoroutines != 3 || report.Recent[2].NumGoroutines != 5 {
+		t.Errorf("recent = %+v", report.Recent)
+	}
+	if report.Summary == nil || report.Summary.SampleCount != 5 || report.Summary.CPUMax != 40 {
+		t.Errorf("summary = %+v", report.Summary)
+	}
+	if report.Current == nil || report.Labels["app"] != "crawler" || report.Running {
+		t.Errorf("report = %+v", report)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/status", nil)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	body := rec.Body.String()
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	if !strings.Contains(body, "app=crawler") || !strings.Contains(body, "汇总（5 次采样）") {
+		t.Errorf("unexpected html: %s", body)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// cgroup
+// ---------------------------------------------------------------------------
+
+func writeCgroupFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDetectCgroupLimits(t *testing.T) {
+	tests := []struct {
+		name       string
+		files      map[string]string
+		selfCgroup string
+		want       CgroupLimits
+	}{
+		{
+			name:       "v2 limited",
+			files:      map[string]string{"cgroup.controllers": "cpu memory", "cpu.max": "150000 100000\n", "memory.max": "2147483648\n"},
+			selfCgroup: "0::/\n",
+			want:       CgroupLimits{Version: 2, CPUQuota: 1.5, MemoryLimit: 2147483648},
+		},
+		{
+			name:       "v2 unlimited",
+			files:      map[string]string{"cgroup.controllers": "cpu memory", "cpu.max": "max 100000\n", "memory.max": "max\n"},
+			selfCgroup: "0::/\n",
+			want:       CgroupLimits{Version: 2},
+		},
+		{
+			name: "v2 nested path",
+			files: map[string]string{"cgroup.controllers": "cpu memory",
+				"kubepods/pod1/cpu.max": "50000 100000", "kubepods/pod1/memory.max": "1048576"},
+			selfCgroup: "0::/kubepods/pod1\n",
+			want:       CgroupLimits{Version: 2, CPUQuota: 0.5, MemoryLimit: 1048576},
+		},
+		{
+			name: "v1 limited",
+			files: map[string]string{"cpu/cpu.cfs_quota_us": "200000", "cpu/cpu.cfs_period_us": "100000",
+				"memory/memory.limit_in_bytes": "536870912"},
+			selfCgroup: "4:cpu,cpuacct:/\n3:memory:/\n",
+			want:       CgroupLimits{Version: 1, CPUQuota: 2, MemoryLimit: 536870912},
+		},
+		{
+			name: "v1 unlimited",
+			files: map[string]string{"cpu/cpu.cfs_quota_us": "-1", "cpu/cpu.cfs_period_us": "100000",
+				"memory/memory.limit_in_bytes": "9223372036854771712"},
+			selfCgroup: "4:cpu,cpuacct:/docker/abc\n3:memory:/docker/abc\n",
+			want:       CgroupLimits{Version: 1},
+		},
+		{
+			name: "no cgroup",
+			want: CgroupLimits{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeCgroupFiles(t, root, tt.files)
+			if got := detectCgroupLimits(root, tt.selfCgroup); got != tt.want {
+				t.Errorf("detectCgroupLimits() = %+v, 期望 %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRecordsCPUQuota(t *testing.T) {
+	records := []SummaryRecord{
+		{NumCPU: 64, CPUQuota: 2, ResourceSummary: ResourceSummary{SampleCount: 10, CPUAvg: 100}},
+		{NumCPU: 32, CPUQuota: 2, ResourceSummary: ResourceSummary{SampleCount: 10, CPUAvg: 60}},
+		{NumCPU: 4, ResourceSummary: ResourceSummary{SampleCount: 10, CPUAvg: 40}},
+	}
+
+	results, report := AnalyzeRecords(records, nil)
+	if len(results) != 2 {
+		t.Fatalf("分组数 = %d, 期望 2: %+v", len(results), results)
+	}
+	if results[0].CPUQuota != 2 || results[0].NumCPU != 0 || results[0].RecordCount != 2 || results[0].CPUAvg != 80 {
+		t.Errorf("配额分组 = %+v", results[0])
+	}
+	if results[1].NumCPU != 4 || results[1].CPUQuota != 0 || results[1].CPUs() != 4 {
+		t.Errorf("核心数分组 = %+v", results[1])
+	}
+	if !strings.Contains(report, "CPU 配额: 2.00 核") {
+		t.Errorf("报告缺少 CPU 配额: %s", report)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// 内存趋势
+// ---------------------------------------------------------------------------
+
+// trendRecords 生成每隔 step 一条、内存从 base 起每条增加 delta 的记录。
+func trendRecords(n int, step time.Duration, base, delta uint64) []SummaryRecord {
+	start := time.Date(2026, 2, 16, 0, 0, 0, 0, time.UTC)
+	records := make([]SummaryRecord, n)
+	for i := range records {
+		records[i] = SummaryRecord{
+			NumCPU:          4,
+			EndedAt:         start.Add(time.Duration(i) * step).Format(time.RFC3339),
+			ResourceSummary: ResourceSummary{SampleCount: 10, MemoryAvg: base + uint64(i)*delta},
+		}
+	}
+	return records
+}
+
+func TestLinearFit(t *testing.T) {
+	slope, r2, mean := linearFit([]float64{0, 1, 2, 3}, []float64{1, 3, 5, 7})
+	if slope != 2 || r2 != 1 || mean != 4 {
+		t.Errorf("linearFit = (%v, %v, %v), 期望 (2, 1, 4)", slope, r2, mean)
+	}
+	if slope, r2, _ := linearFit([]float64{0, 1, 2}, []float64{5, 5, 5}); slope != 0 || r2 != 0 {
+		t.Errorf("无变化时 = (%v, %v), 期望 (0, 0)", slope, r2)
+	}
+	if slope, _, _ := linearFit([]float64{1, 1}, []float64{1, 2}); slope != 0 {
+		t.Errorf("x 相同时斜率 = %v, 期望 0", slope)
+	}
+}
+
+func TestAnalyzeRecordsMemoryTrend(t *testing.T) {
+	// 每 6 小时一条，每条 +10 MB（基数 1 GB）：约 +3.9%/天，低于默认阈值
+	slow := trendRecords(8, 6*time.Hour, 1<<30, 10<<20)
+	results, report := AnalyzeRecords(slow, nil)
+	trend := results[0].MemoryTrend
+	if trend == nil || trend.Points != 8 || trend.R2 < 0.99 {
+		t.Fatalf("MemoryTrend = %+v", trend)
+	}
+	if trend.Leak {
+		t.Errorf("日增长 %.2f%% 低于默认阈值, 不应判定为泄漏", trend.GrowthPerDay)
+	}
+	if strings.Contains(report, "疑似内存泄漏") {
+		t.Errorf("报告不应包含疑似内存泄漏:\n%s", report)
+	}
+
+	results, report = AnalyzeRecords(slow, &AnalyzeOptions{LeakMinGrowth: 3})
+	if !results[0].MemoryTrend.Leak {
+		t.Errorf("降低阈值后应判定为泄漏: %+v", results[0].MemoryTrend)
+	}
+	if !strings.Contains(report, "疑似内存泄漏") {
+		t.Errorf("报告应包含疑似内存泄漏:\n%s", report)
+	}
+
+	// 内存来回波动，没有持续增长
+	noisy := trendRecords(8, 6*time.Hour, 1<<30, 0)
+	for i := range noisy {
+		if i%2 == 1 {
+			noisy[i].MemoryAvg += 200 << 20
+		}
+	}
+	results, _ = AnalyzeRecords(noisy, &AnalyzeOptions{LeakMinGrowth: 1})
+	if trend := results[0].MemoryTrend; trend == nil || trend.Leak {
+		t.Errorf("波动数据不应判定为泄漏: %+v", trend)
+	}
+}
+
+func TestAnalyzeRecordsMemoryTrendInsufficient(t *testing.T) {
+	if results, _ := AnalyzeRecords(trendRecords(minTrendPoints-1, time.Hour, 1<<30, 100<<20), nil); results[0].MemoryTrend != nil {
+		t.Errorf("记录不足时 MemoryTrend 应为 nil: %+v", results[0].MemoryTrend)
+	}
+	if results, _ := AnalyzeRecords(trendRecords(10, time.Minute, 1<<30, 100<<20), nil); results[0].MemoryTrend != nil {
+		t.Errorf("时间跨度不足时 MemoryTrend 应为 nil: %+v", results[0].MemoryTrend)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// CompareRecords
+// ---------------------------------------------------------------------------
+
+func TestCompareRecords(t *testing.T) {
+	a := []SummaryRecord{
+		{NumCPU: 4, ResourceSummary: ResourceSummary{SampleCount: 10, CPUAvg: 40, CPUMax: 80, MemoryAvg: 1000, MemoryMax: 2000, GoroutineAvg: 10, GoroutineMax: 20}},
+		{NumCPU: 8, ResourceSummary: ResourceSummary{SampleCount: 10, CPUAvg: 10}},
+	}
+	b := []SummaryRecord{
+		{NumCPU: 4, ResourceSummary: ResourceSummary{SampleCount: 10, CPUAvg: 30, CPUMax: 80, MemoryAvg: 1500, MemoryMax: 2000, GoroutineAvg: 12, GoroutineMax: 20}},
+	}
+
+	results, report := CompareRecords(a, b, nil)
+	if len(results) != 2 {
+		t.Fatalf("分组数 = %d, 期望 2", len(results))
+	}
+
+	c := results[0]
+	if c.NumCPU != 4 || c.A == nil || c.B == nil {
+		t.Fatalf("results[0] = %+v", c)
+	}
+	if c.CPUAvgDelta != -25 || c.CPUMaxDelta != 0 || c.MemoryAvgDelta != 50 || c.GoroutineAvgDelta != 20 {
+		t.Errorf("delta = %+v", c)
+	}
+
+	if results[1].NumCPU != 8 || results[1].A == nil || results[1].B != nil || results[1].CPUAvgDelta != 0 {
+		t.Errorf("results[1] = %+v", results[1])
+	}
+	if !strings.Contains(report, "-25.0%") || !strings.Contains(report, "+50.0%") {
+		t.Errorf("报告缺少变化百分比: %s", report)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Annotation
+// ---------------------------------------------------------------------------
+
+func TestParseAnnotationFields(t *testing.T) {
+	fields := parseAnnotationFields("phase=upload start files=42 =x")
+	if len(fields) != 2 || fields["phase"] != "upload" || fields["files"] != "42" {
+		t.Errorf("fields = %v, 期望 phase=upload files=42", fields)
+	}
+	if fields := parseAnnotationFields("just text"); fields != nil {
+		t.Errorf("fields = %v, 期望 nil", fields)
+	}
+}
+
+func TestSummarizeAnnotations(t *testing.T) {
+	base := time.Date(2026, 2, 16, 15, 0, 0, 0, time.Local)
+	var history []ResourceStats
+	for i := range 6 {
+		history = append(history, ResourceStats{
+			Timestamp:     base.Add(time.Duration(i) * time.Second),
+			CPUPercent:    float64(10 * (i + 1)),
+			MemoryRSS:     uint64(100 * (i + 1)),
+			NumGoroutines: i + 1,
+		})
+	}
+	annotations := []Annotation{
+		{Time: base.Add(500 * time.Millisecond), Text: "phase=upload start"},
+		{Time: base.Add(3 * time.Second), Text: "phase=index start"},
+	}
+
+	got := summarizeAnnotations(history, annotations, base.Add(10*time.Second))
+	if len(got) != 2 {
+		t.Fatalf("段数 = %d, 期望 2", len(got))
+	}
+	// 第一段：采样 1、2（20%、30%）
+	if a := got[0]; a.SampleCount != 2 || a.CPUMax != 30 || a.CPUAvg != 25 || a.MemoryMax != 300 || a.MemoryAvg != 250 || a.GoroutineMax != 3 || !a.End.Equal(annotations[1].Time) {
+		t.Errorf("got[0] = %+v", a)
+	}
+	// 第二段：采样 3、4、5（40%、50%、60%），截止到 end
+	if a := got[1]; a.SampleCount != 3 || a.CPUMax != 60 || a.CPUAvg != 50 || a.MemoryAvg != 500 || a.Text != "phase=index start" {
+		t.Errorf("got[1] = %+v", a)
+	}
+
+	if got := summarizeAnnotations(history, nil, base); got != nil {
+		t.Errorf("无标注时期望 nil, 得到 %+v", got)
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	m, err := NewResourceMonitor(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Annotate("phase=upload start")
+	m.Annotatef("phase=%s start", "index")
+
+	got := m.Annotations()
+	if len(got) != 2 || got[1].Text != "phase=index start" || got[1].Fields["phase"] != "index" {
+		t.Errorf("Annotations = %+v", got)
+	}
+	if report := m.Status(10); len(report.Annotations) != 2 {
+		t.Errorf("Status.Annotations = %+v, 期望 2 条", report.Annotations)
+	}
+}
@@ -0,0 +1,114 @@
+package monitor
+
+import "time"
+
+// 默认采样间隔。
+const defaultInterval = 2 * time.Second
+
+// Option 监控器函数式配置项，作用于 Config，可与 Config 同时使用（Option 后应用，优先级更高）。
+//
+// 用法：
+//
+//	mon, _ := monitor.NewResourceMonitor(nil,
+//	    monitor.WithInterval(5*time.Second),
+//	    monitor.WithSaver(saver, "resource:summary:myapp"),
+//	)
+type Option func(*Config)
+
+// WithInterval 设置采样间隔。d <= 0 时忽略。
+func WithInterval(d time.Duration) Option {
+	return func(c *Config) {
+		if d > 0 {
+			c.Interval = d
+		}
+	}
+}
+
+// WithLogInterval 设置日志输出间隔（不影响采样频率）。d <= 0 时忽略。
+func WithLogInterval(d time.Duration) Option {
+	return func(c *Config) {
+		if d > 0 {
+			c.LogInterval = d
+		}
+	}
+}
+
+// WithOnStats 设置采样回调（设置后不再输出默认日志）。
+func WithOnStats(fn func(stats *ResourceStats)) Option {
+	return func(c *Config) {
+		c.OnStats = fn
+	}
+}
+
+// WithSaver 设置汇总持久化实现及其 key（Stop 时保存）。
+func WithSaver(saver SummarySaver, key string) Option {
+	return func(c *Config) {
+		c.Saver = saver
+		c.SaveKey = key
+	}
+}
+
+// WithLabels 设置运行标签（如 app、version、job_id、region），随汇总一起持久化，
+// 可在 AnalyzeOptions 中用于过滤和分组。多次调用会合并，后设置的同名 key 覆盖先前的值。
+func WithLabels(labels map[string]string) Option {
+	return func(c *Config) {
+		if len(labels) == 0 {
+			return
+		}
+		merged := make(map[string]string, len(c.Labels)+len(labels))
+		for k, v := range c.Labels {
+			merged[k] = v
+		}
+		for k, v := range labels {
+			merged[k] = v
+		}
+		c.Labels = merged
+	}
+}
+
+// WithExporter 追加采样数据导出器（如 NewOTelExporter），每次采样后调用。
+func WithExporter(e StatsExporter) Option {
+	return func(c *Config) {
+		if e != nil {
+			c.Exporters = append(c.Exporters, e)
+		}
+	}
+}
+
+// WithGoroutineWatchdog 启用协程泄漏看门狗：协程数超过阈值且连续多次采样单调增长时，转储一次协程栈。
+func WithGoroutineWatchdog(w GoroutineWatchdog) Option {
+	return func(c *Config) {
+		c.GoroutineWatchdog = &w
+	}
+}
+
+// resolveConfig 合并 cfg 与 opts 并填充默认值，返回独立副本（不修改调用方的 cfg）。
+func resolveConfig(cfg *Config, opts []Option) Config {
+	var c Config
+	if cfg != nil {
+		c = *cfg
+		// 复制一份，避免 WithExporter 的 append 写入调用方 Config.Exporters 的底层数组
+		c.Exporters = append([]StatsExporter(nil), cfg.Exporters...)
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&c)
+		}
+	}
+
+	if c.Interval <= 0 {
+		c.Interval = defaultInterval
+	}
+	if c.LogInterval <= 0 {
+		c.LogInterval = c.Interval
+	}
+	if len(c.Labels) > 0 {
+		// 复制一份，避免调用方后续修改 map 影响已创建的监控器
+		labels := make(map[string]string, len(c.Labels))
+		for k, v := range c.Labels {
+			labels[k] = v
+		}
+		c.Labels = labels
+	}
+	return c
+}
@@ -0,0 +1,112 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// OTelExporter 将采样数据推送为 OpenTelemetry 指标的 StatsExporter 实现。
+// 每次采样更新一组 Gauge（当前值），并将 CPU / 内存记录到 Histogram（分布）。
+//
+// 用法：
+//
+//	exporter, err := monitor.NewOTelExporter(otel.Meter("myapp"),
+//	    attribute.String("app", "crawler"))
+//	if err != nil {
+//	    return err
+//	}
+//	mon, _ := monitor.NewResourceMonitor(nil, monitor.WithExporter(exporter))
+type OTelExporter struct {
+	attrs metric.MeasurementOption
+
+	cpuPercent    metric.Float64Gauge
+	memoryRSS     metric.Int64Gauge
+	memoryVMS     metric.Int64Gauge
+	memoryPercent metric.Float64Gauge
+	goroutines    metric.Int64Gauge
+	gcCount       metric.Int64Gauge
+	heapAlloc     metric.Int64Gauge
+	heapSys       metric.Int64Gauge
+
+	cpuHist    metric.Float64Histogram
+	memoryHist metric.Int64Histogram
+}
+
+// NewOTelExporter 基于调用方提供的 meter 创建 OTelExporter，attrs 会附加到每个数据点上。
+// 指标名统一以 "monitor." 为前缀。
+func NewOTelExporter(meter metric.Meter, attrs ...attribute.KeyValue) (*OTelExporter, error) {
+	if meter == nil {
+		return nil, fmt.Errorf("monitor: meter 不能为 nil")
+	}
+
+	e := &OTelExporter{attrs: metric.WithAttributes(attrs...)}
+	var err error
+
+	if e.cpuPercent, err = meter.Float64Gauge("monitor.cpu.percent",
+		metric.WithDescription("进程 CPU 使用率（多核可能 >100）"), metric.WithUnit("%")); err != nil {
+		return nil, wrapInstrumentErr("monitor.cpu.percent", err)
+	}
+	if e.memoryRSS, err = meter.Int64Gauge("monitor.memory.rss",
+		metric.WithDescription("进程常驻内存"), metric.WithUnit("By")); err != nil {
+		return nil, wrapInstrumentErr("monitor.memory.rss", err)
+	}
+	if e.memoryVMS, err = meter.Int64Gauge("monitor.memory.vms",
+		metric.WithDescription("进程虚拟内存"), metric.WithUnit("By")); err != nil {
+		return nil, wrapInstrumentErr("monitor.memory.vms", err)
+	}
+	if e.memoryPercent, err = meter.Float64Gauge("monitor.memory.percent",
+		metric.WithDescription("进程内存使用率"), metric.WithUnit("%")); err != nil {
+		return nil, wrapInstrumentErr("monitor.memory.percent", err)
+	}
+	if e.goroutines, err = meter.Int64Gauge("monitor.goroutines",
+		metric.WithDescription("Goroutine 数量"), metric.WithUnit("{goroutine}")); err != nil {
+		return nil, wrapInstrumentErr("monitor.goroutines", err)
+	}
+	if e.gcCount, err = meter.Int64Gauge("monitor.gc.count",
+		metric.WithDescription("GC 累计次数"), metric.WithUnit("{gc}")); err != nil {
+		return nil, wrapInstrumentErr("monitor.gc.count", err)
+	}
+	if e.heapAlloc, err = meter.Int64Gauge("monitor.heap.alloc",
+		metric.WithDescription("堆已分配内存"), metric.WithUnit("By")); err != nil {
+		return nil, wrapInstrumentErr("monitor.heap.alloc", err)
+	}
+	if e.heapSys, err = meter.Int64Gauge("monitor.heap.sys",
+		metric.WithDescription("堆系统内存"), metric.WithUnit("By")); err != nil {
+		return nil, wrapInstrumentErr("monitor.heap.sys", err)
+	}
+	if e.cpuHist, err = meter.Float64Histogram("monitor.cpu.percent.distribution",
+		metric.WithDescription("CPU 使用率分布"), metric.WithUnit("%")); err != nil {
+		return nil, wrapInstrumentErr("monitor.cpu.percent.distribution", err)
+	}
+	if e.memoryHist, err = meter.Int64Histogram("monitor.memory.rss.distribution",
+		metric.WithDescription("常驻内存分布"), metric.WithUnit("By")); err != nil {
+		return nil, wrapInstrumentErr("monitor.memory.rss.distribution", err)
+	}
+
+	return e, nil
+}
+
+// ExportStats 实现 StatsExporter 接口，将一次采样记录到各个指标。
+func (e *OTelExporter) ExportStats(stats *ResourceStats) {
+	ctx := context.Background()
+
+	e.cpuPercent.Record(ctx, stats.CPUPercent, e.attrs)
+	e.memoryRSS.Record(ctx, int64(stats.MemoryRSS), e.attrs)
+	e.memoryVMS.Record(ctx, int64(stats.MemoryVMS), e.attrs)
+	e.memoryPercent.Record(ctx, float64(stats.MemoryPercent), e.attrs)
+	e.goroutines.Record(ctx, int64(stats.NumGoroutines), e.attrs)
+	e.gcCount.Record(ctx, int64(stats.NumGC), e.attrs)
+	e.heapAlloc.Record(ctx, int64(stats.HeapAlloc), e.attrs)
+	e.heapSys.Record(ctx, int64(stats.HeapSys), e.attrs)
+
+	e.cpuHist.Record(ctx, stats.CPUPercent, e.attrs)
+	e.memoryHist.Record(ctx, int64(stats.MemoryRSS), e.attrs)
+}
+
+// wrapInstrumentErr 包装创建指标失败的错误。
+func wrapInstrumentErr(name string, err error) error {
+	return fmt.Errorf("monitor: 创建 OTel 指标 [%s] 失败: %w", name, err)
+}
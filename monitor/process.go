@@ -0,0 +1,147 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// NewResourceMonitorForPIDs 创建同时监控当前进程和 pids 指定的额外进程（如派生的 worker 子进程）的监控器。
+// 采样数据中包含各进程的 CPU / 内存及合计值，汇总中包含各进程汇总和合计汇总。
+// 与当前进程相同或重复的 PID 会被忽略；进程运行期间退出时，仅跳过其后续采样。
+//
+// 用法：
+//
+//	cmd := exec.Command("worker")
+//	_ = cmd.Start()
+//	mon, err := monitor.NewResourceMonitorForPIDs([]int32{int32(cmd.Process.Pid)}, nil)
+func NewResourceMonitorForPIDs(pids []int32, cfg *Config, opts ...Option) (*ResourceMonitor, error) {
+	m, err := NewResourceMonitor(cfg, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[int32]bool{m.proc.Pid: true}
+	for _, pid := range pids {
+		if seen[pid] {
+			continue
+		}
+		seen[pid] = true
+
+		p, err := process.NewProcess(pid)
+		if err != nil {
+			return nil, fmt.Errorf("monitor: 获取进程 [%d] 信息失败: %w", pid, err)
+		}
+		m.extraProcs = append(m.extraProcs, p)
+	}
+	return m, nil
+}
+
+// sampleExtraProcs 采样所有额外进程，填充 Processes 及合计字段。
+func (m *ResourceMonitor) sampleExtraProcs(stats *ResourceStats) {
+	stats.TotalCPUPercent = stats.CPUPercent
+	stats.TotalMemoryRSS = stats.MemoryRSS
+
+	for _, p := range m.extraProcs {
+		cpu, err := p.CPUPercent()
+		if err != nil {
+			log.Debugf("monitor: 获取进程 [%d] CPU 使用率失败: %v", p.Pid, err)
+			continue
+		}
+		mem, err := p.MemoryInfo()
+		if err != nil {
+			log.Debugf("monitor: 获取进程 [%d] 内存信息失败: %v", p.Pid, err)
+			continue
+		}
+		ps := ProcessStats{PID: p.Pid, CPUPercent: cpu, MemoryRSS: mem.RSS}
+		if pct, err := p.MemoryPercent(); err == nil {
+			ps.MemoryPercent = pct
+		}
+
+		stats.Processes = append(stats.Processes, ps)
+		stats.TotalCPUPercent += ps.CPUPercent
+		stats.TotalMemoryRSS += ps.MemoryRSS
+	}
+}
+
+// processAcc 单个进程 CPU / 内存的累加器。
+type processAcc struct {
+	n      int
+	cpuMin float64
+	cpuMax float64
+	cpuSum float64
+	memMin uint64
+	memMax uint64
+	memSum uint64
+}
+
+// add 累加一次采样。
+func (a *processAcc) add(cpu float64, mem uint64) {
+	if a.n == 0 || cpu < a.cpuMin {
+		a.cpuMin = cpu
+	}
+	if cpu > a.cpuMax {
+		a.cpuMax = cpu
+	}
+	if a.n == 0 || mem < a.memMin {
+		a.memMin = mem
+	}
+	if mem > a.memMax {
+		a.memMax = mem
+	}
+	a.cpuSum += cpu
+	a.memSum += mem
+	a.n++
+}
+
+// summary 生成汇总。
+func (a *processAcc) summary(pid int32) ProcessSummary {
+	s := ProcessSummary{
+		PID:         pid,
+		SampleCount: a.n,
+		CPUMin:      a.cpuMin,
+		CPUMax:      a.cpuMax,
+		MemoryMin:   a.memMin,
+		MemoryMax:   a.memMax,
+	}
+	if a.n > 0 {
+		s.CPUAvg = a.cpuSum / float64(a.n)
+		s.MemoryAvg = a.memSum / uint64(a.n)
+	}
+	return s
+}
+
+// summarizeProcesses 根据采样历史计算各额外进程（按 PID 升序）及合计的汇总。
+func summarizeProcesses(history []ResourceStats) ([]ProcessSummary, *ProcessSummary) {
+	if len(history) == 0 {
+		return nil, nil
+	}
+
+	perPID := make(map[int32]*processAcc)
+	var total processAcc
+	for _, s := range history {
+		for _, ps := range s.Processes {
+			acc, ok := perPID[ps.PID]
+			if !ok {
+				acc = &processAcc{}
+				perPID[ps.PID] = acc
+			}
+			acc.add(ps.CPUPercent, ps.MemoryRSS)
+		}
+		total.add(s.TotalCPUPercent, s.TotalMemoryRSS)
+	}
+
+	pids := make([]int32, 0, len(perPID))
+	for pid := range perPID {
+		pids = append(pids, pid)
+	}
+	sort.Slice(pids, func(i, j int) bool { return pids[i] < pids[j] })
+
+	processes := make([]ProcessSummary, 0, len(pids))
+	for _, pid := range pids {
+		processes = append(processes, perPID[pid].summary(pid))
+	}
+	aggregate := total.summary(0)
+	return processes, &aggregate
+}
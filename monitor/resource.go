@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"strings"
 	"sync"
 	"time"
 
@@ -12,9 +13,13 @@ import (
 	"github.com/shirou/gopsutil/v3/process"
 )
 
+// log monitor 模块日志，可通过 logger.SetModuleLevel("monitor", ...) 单独控制级别。
+var log = logger.Module("monitor")
+
 // ResourceMonitor 进程资源监控器，定时采样 CPU / 内存 / Goroutine 等指标。
 type ResourceMonitor struct {
 	proc        *process.Process
+	extraProcs  []*process.Process // 额外监控的进程（NewResourceMonitorForPIDs）
 	interval    time.Duration
 	logInterval time.Duration
 	lastLogTime time.Time
@@ -23,53 +28,47 @@ type ResourceMonitor struct {
 	running     bool
 	mu          sync.Mutex
 	numCPU      int
+	limits      CgroupLimits // 容器 cgroup 限制，创建时检测一次
 
-	onStats func(stats *ResourceStats)
+	onStats   func(stats *ResourceStats)
+	exporters []StatsExporter
 
 	saverMu sync.Mutex
 	saver   SummarySaver
 	saveKey string
+	labels  map[string]string
+
+	historyMu   sync.Mutex
+	history     []ResourceStats
+	annotations []Annotation // 事件标注，见 Annotate
 
-	historyMu sync.Mutex
-	history   []ResourceStats
+	watchdog *goroutineWatchdog
 }
 
-// NewResourceMonitor 创建资源监控器。cfg 可为 nil，使用默认配置。
-func NewResourceMonitor(cfg *Config) (*ResourceMonitor, error) {
+// NewResourceMonitor 创建资源监控器。cfg 可为 nil，使用默认配置；
+// opts 在 cfg 之后应用，可单独使用函数式配置（WithInterval、WithSaver、WithOnStats 等）。
+func NewResourceMonitor(cfg *Config, opts ...Option) (*ResourceMonitor, error) {
 	p, err := process.NewProcess(int32(os.Getpid()))
 	if err != nil {
 		return nil, fmt.Errorf("monitor: 获取进程信息失败: %w", err)
 	}
 
-	interval := 2 * time.Second
-	logInterval := 2 * time.Second
-	var onStats func(stats *ResourceStats)
-	var saver SummarySaver
-	var saveKey string
-
-	if cfg != nil {
-		if cfg.Interval > 0 {
-			interval = cfg.Interval
-			logInterval = cfg.Interval
-		}
-		if cfg.LogInterval > 0 {
-			logInterval = cfg.LogInterval
-		}
-		onStats = cfg.OnStats
-		saver = cfg.Saver
-		saveKey = cfg.SaveKey
-	}
+	c := resolveConfig(cfg, opts)
 
 	return &ResourceMonitor{
 		proc:        p,
-		interval:    interval,
-		logInterval: logInterval,
+		interval:    c.Interval,
+		logInterval: c.LogInterval,
 		stopChan:    make(chan struct{}),
-		onStats:     onStats,
-		saver:       saver,
-		saveKey:     saveKey,
+		onStats:     c.OnStats,
+		exporters:   c.Exporters,
+		saver:       c.Saver,
+		saveKey:     c.SaveKey,
+		labels:      c.Labels,
 		numCPU:      runtime.NumCPU(),
+		limits:      DetectCgroupLimits(),
 		history:     make([]ResourceStats, 0, 1000),
+		watchdog:    newGoroutineWatchdog(c.GoroutineWatchdog),
 	}, nil
 }
 
@@ -93,11 +92,20 @@ func (m *ResourceMonitor) Start() {
 
 	m.historyMu.Lock()
 	m.history = m.history[:0]
+	m.annotations = nil
 	m.historyMu.Unlock()
 
+	if m.watchdog != nil {
+		m.watchdog.reset()
+	}
+
 	m.wg.Add(1)
 	go m.loop()
-	logger.Infof("monitor: 资源监控已启动（间隔: %v, CPU 核心数: %d）", m.interval, m.numCPU)
+	if m.limits.CPUQuota > 0 || m.limits.MemoryLimit > 0 {
+		log.Infof("monitor: 资源监控已启动（间隔: %v, CPU 核心数: %d, %s）", m.interval, m.numCPU, m.formatLimits())
+	} else {
+		log.Infof("monitor: 资源监控已启动（间隔: %v, CPU 核心数: %d）", m.interval, m.numCPU)
+	}
 }
 
 // Stop 停止监控并输出汇总。
@@ -114,7 +122,7 @@ func (m *ResourceMonitor) Stop() {
 	m.wg.Wait()
 
 	m.logAndSaveSummary()
-	logger.Infof("monitor: 资源监控已停止")
+	log.Infof("monitor: 资源监控已停止")
 
 	m.mu.Lock()
 	m.stopChan = make(chan struct{})
@@ -131,18 +139,18 @@ func (m *ResourceMonitor) GetStats() (*ResourceStats, error) {
 	if cpu, err := m.proc.CPUPercent(); err == nil {
 		stats.CPUPercent = cpu
 	} else {
-		logger.Debugf("monitor: 获取 CPU 使用率失败: %v", err)
+		log.Debugf("monitor: 获取 CPU 使用率失败: %v", err)
 	}
 	if mem, err := m.proc.MemoryInfo(); err == nil {
 		stats.MemoryRSS = mem.RSS
 		stats.MemoryVMS = mem.VMS
 	} else {
-		logger.Debugf("monitor: 获取内存信息失败: %v", err)
+		log.Debugf("monitor: 获取内存信息失败: %v", err)
 	}
 	if pct, err := m.proc.MemoryPercent(); err == nil {
 		stats.MemoryPercent = pct
 	} else {
-		logger.Debugf("monitor: 获取内存百分比失败: %v", err)
+		log.Debugf("monitor: 获取内存百分比失败: %v", err)
 	}
 
 	var ms runtime.MemStats
@@ -151,6 +159,17 @@ func (m *ResourceMonitor) GetStats() (*ResourceStats, error) {
 	stats.HeapAlloc = ms.HeapAlloc
 	stats.HeapSys = ms.HeapSys
 
+	if m.limits.CPUQuota > 0 {
+		stats.CPUQuotaPercent = stats.CPUPercent / m.limits.CPUQuota
+	}
+	if m.limits.MemoryLimit > 0 {
+		stats.MemoryLimitPercent = float64(stats.MemoryRSS) / float64(m.limits.MemoryLimit) * 100
+	}
+
+	if len(m.extraProcs) > 0 {
+		m.sampleExtraProcs(stats)
+	}
+
 	return stats, nil
 }
 
@@ -208,6 +227,20 @@ func (m *ResourceMonitor) GetSummary() *ResourceSummary {
 	summary.MemoryAvg = memSum / uint64(n)
 	summary.GoroutineAvg = grSum / n
 
+	if m.limits.CPUQuota > 0 {
+		summary.CPUQuotaPercentMax = summary.CPUMax / m.limits.CPUQuota
+		summary.CPUQuotaPercentAvg = summary.CPUAvg / m.limits.CPUQuota
+	}
+	if m.limits.MemoryLimit > 0 {
+		limit := float64(m.limits.MemoryLimit)
+		summary.MemoryLimitPercentMax = float64(summary.MemoryMax) / limit * 100
+		summary.MemoryLimitPercentAvg = float64(summary.MemoryAvg) / limit * 100
+	}
+
+	if len(m.extraProcs) > 0 {
+		summary.Processes, summary.Aggregate = summarizeProcesses(m.history)
+	}
+
 	return summary
 }
 
@@ -227,7 +260,7 @@ func (m *ResourceMonitor) loop() {
 		case <-ticker.C:
 			stats, err := m.GetStats()
 			if err != nil {
-				logger.Debugf("monitor: 获取资源统计失败: %v", err)
+				log.Debugf("monitor: 获取资源统计失败: %v", err)
 				continue
 			}
 
@@ -241,6 +274,14 @@ func (m *ResourceMonitor) loop() {
 			m.history = append(m.history, *stats)
 			m.historyMu.Unlock()
 
+			for _, e := range m.exporters {
+				e.ExportStats(stats)
+			}
+
+			if m.watchdog != nil && m.watchdog.observe(stats.NumGoroutines) {
+				m.watchdog.dump(stats.NumGoroutines)
+			}
+
 			if m.onStats != nil {
 				m.onStats(stats)
 			} else {
@@ -260,29 +301,61 @@ func (m *ResourceMonitor) loop() {
 // logStats 输出单次采样日志。
 func (m *ResourceMonitor) logStats(stats *ResourceStats) {
 	coresUsed := stats.CPUPercent / 100.0
-	logger.Infof("monitor: CPU=%.1f%% (%.1f/%d核), 内存=%s(%.1f%%), Goroutines=%d, GC=%d",
+	log.Infof("monitor: CPU=%.1f%% (%.1f/%d核), 内存=%s(%.1f%%), Goroutines=%d, GC=%d",
 		stats.CPUPercent, coresUsed, m.numCPU,
 		FormatBytes(stats.MemoryRSS), stats.MemoryPercent,
 		stats.NumGoroutines, stats.NumGC)
+	if m.limits.CPUQuota > 0 || m.limits.MemoryLimit > 0 {
+		log.Infof("monitor: 容器限制占用 CPU=%.1f%%（配额 %.2f 核）, 内存=%.1f%%（上限 %s）",
+			stats.CPUQuotaPercent, m.limits.CPUQuota, stats.MemoryLimitPercent, FormatBytes(m.limits.MemoryLimit))
+	}
+	if len(m.extraProcs) > 0 {
+		log.Infof("monitor: 进程合计（含 %d 个额外进程）CPU=%.1f%%, 内存=%s",
+			len(stats.Processes), stats.TotalCPUPercent, FormatBytes(stats.TotalMemoryRSS))
+	}
 }
 
 // logAndSaveSummary 输出汇总日志，并在设置了 Saver 时持久化。
 func (m *ResourceMonitor) logAndSaveSummary() {
 	summary := m.GetSummary()
 	if summary == nil {
-		logger.Infof("monitor: 汇总 - 无采样数据")
+		log.Infof("monitor: 汇总 - 无采样数据")
 		return
 	}
 
-	logger.Infof("monitor: ========== 资源使用汇总 ==========")
-	logger.Infof("monitor: 采样次数: %d", summary.SampleCount)
-	logger.Infof("monitor: CPU (总核心: %d) - 最小: %.1f%%, 最大: %.1f%%, 平均: %.1f%%",
+	log.Infof("monitor: ========== 资源使用汇总 ==========")
+	log.Infof("monitor: 采样次数: %d", summary.SampleCount)
+	log.Infof("monitor: CPU (总核心: %d) - 最小: %.1f%%, 最大: %.1f%%, 平均: %.1f%%",
 		m.numCPU, summary.CPUMin, summary.CPUMax, summary.CPUAvg)
-	logger.Infof("monitor: 内存 - 最小: %s, 最大: %s, 平均: %s",
+	log.Infof("monitor: 内存 - 最小: %s, 最大: %s, 平均: %s",
 		FormatBytes(summary.MemoryMin), FormatBytes(summary.MemoryMax), FormatBytes(summary.MemoryAvg))
-	logger.Infof("monitor: Goroutines - 最小: %d, 最大: %d, 平均: %d",
+	log.Infof("monitor: Goroutines - 最小: %d, 最大: %d, 平均: %d",
 		summary.GoroutineMin, summary.GoroutineMax, summary.GoroutineAvg)
-	logger.Infof("monitor: ====================================")
+	if m.limits.CPUQuota > 0 {
+		log.Infof("monitor: CPU 占配额 (%.2f 核) - 最大: %.1f%%, 平均: %.1f%%",
+			m.limits.CPUQuota, summary.CPUQuotaPercentMax, summary.CPUQuotaPercentAvg)
+	}
+	if m.limits.MemoryLimit > 0 {
+		log.Infof("monitor: 内存占上限 (%s) - 最大: %.1f%%, 平均: %.1f%%",
+			FormatBytes(m.limits.MemoryLimit), summary.MemoryLimitPercentMax, summary.MemoryLimitPercentAvg)
+	}
+	for _, p := range summary.Processes {
+		log.Infof("monitor: 进程 [%d] (采样 %d 次) CPU - 最小: %.1f%%, 最大: %.1f%%, 平均: %.1f%%; 内存 - 最小: %s, 最大: %s, 平均: %s",
+			p.PID, p.SampleCount, p.CPUMin, p.CPUMax, p.CPUAvg,
+			FormatBytes(p.MemoryMin), FormatBytes(p.MemoryMax), FormatBytes(p.MemoryAvg))
+	}
+	if a := summary.Aggregate; a != nil {
+		log.Infof("monitor: 进程合计 CPU - 最小: %.1f%%, 最大: %.1f%%, 平均: %.1f%%; 内存 - 最小: %s, 最大: %s, 平均: %s",
+			a.CPUMin, a.CPUMax, a.CPUAvg,
+			FormatBytes(a.MemoryMin), FormatBytes(a.MemoryMax), FormatBytes(a.MemoryAvg))
+	}
+	annotations := m.AnnotationSummaries()
+	for _, a := range annotations {
+		log.Infof("monitor: 事件 [%s] %s (%v, 采样 %d 次) CPU - 最大: %.1f%%, 平均: %.1f%%; 内存 - 最大: %s, 平均: %s; Goroutines 最大: %d",
+			a.Time.Format("15:04:05"), a.Text, a.End.Sub(a.Time).Round(time.Second), a.SampleCount,
+			a.CPUMax, a.CPUAvg, FormatBytes(a.MemoryMax), FormatBytes(a.MemoryAvg), a.GoroutineMax)
+	}
+	log.Infof("monitor: ====================================")
 
 	// 持久化
 	m.saverMu.Lock()
@@ -295,17 +368,33 @@ func (m *ResourceMonitor) logAndSaveSummary() {
 
 	record := SummaryRecord{
 		NumCPU:          m.numCPU,
+		CPUQuota:        m.limits.CPUQuota,
+		MemoryLimit:     m.limits.MemoryLimit,
 		EndedAt:         time.Now().Format(time.RFC3339),
+		Labels:          m.labels,
+		Annotations:     annotations,
 		ResourceSummary: *summary,
 	}
 	jsonBytes, err := json.Marshal(record)
 	if err != nil {
-		logger.Warnf("monitor: 汇总 JSON 序列化失败: %v", err)
+		log.Warnf("monitor: 汇总 JSON 序列化失败: %v", err)
 		return
 	}
 	if err = saver.SaveSummary(key, string(jsonBytes)); err != nil {
-		logger.Warnf("monitor: 汇总保存失败: %v", err)
+		log.Warnf("monitor: 汇总保存失败: %v", err)
 		return
 	}
-	logger.Infof("monitor: 汇总已保存到 [%s]", key)
+	log.Infof("monitor: 汇总已保存到 [%s]", key)
+}
+
+// formatLimits 将 cgroup 限制格式化为日志片段，如 "CPU 配额: 1.50 核, 内存上限: 2.00 GB"。
+func (m *ResourceMonitor) formatLimits() string {
+	var parts []string
+	if m.limits.CPUQuota > 0 {
+		parts = append(parts, fmt.Sprintf("CPU 配额: %.2f 核", m.limits.CPUQuota))
+	}
+	if m.limits.MemoryLimit > 0 {
+		parts = append(parts, "内存上限: "+FormatBytes(m.limits.MemoryLimit))
+	}
+	return strings.Join(parts, ", ")
 }
@@ -0,0 +1,188 @@
+package monitor
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 状态页默认返回的最近采样条数及上限。
+const (
+	defaultStatusSamples = 60
+	maxStatusSamples     = 1000
+)
+
+// StatusReport 监控器当前状态快照：实时采样、最近若干次采样以及本次运行至今的汇总。
+type StatusReport struct {
+	Time     time.Time         `json:"time"`
+	PID      int               `json:"pid"`
+	Running  bool              `json:"running"`
+	Interval string            `json:"interval"`
+	NumCPU   int               `json:"num_cpu"`
+	Cgroup   *CgroupLimits     `json:"cgroup,omitempty"` // 容器 cgroup 限制，未运行在 cgroup 中时省略
+	Labels   map[string]string `json:"labels,omitempty"`
+	Current  *ResourceStats    `json:"current"`
+	Recent   []ResourceStats   `json:"recent"`
+	Summary  *ResourceSummary  `json:"summary"`
+
+	Annotations []Annotation `json:"annotations,omitempty"` // Recent 时间范围内的事件标注
+}
+
+// Status 返回当前状态快照。Current 为实时采样，Recent 为最近 n 次定时采样（按时间升序），
+// n <= 0 时默认 60，最多 1000；Summary 在尚无采样时为 nil；Annotations 为 Recent 时间范围内（无采样时为全部）的事件标注。
+func (m *ResourceMonitor) Status(n int) *StatusReport {
+	if n <= 0 {
+		n = defaultStatusSamples
+	}
+	n = min(n, maxStatusSamples)
+
+	m.mu.Lock()
+	running := m.running
+	m.mu.Unlock()
+
+	report := &StatusReport{
+		Time:     time.Now(),
+		PID:      os.Getpid(),
+		Running:  running,
+		Interval: m.interval.String(),
+		NumCPU:   m.numCPU,
+		Labels:   m.labels,
+		Recent:   m.recentHistory(n),
+		Summary:  m.GetSummary(),
+	}
+	var since time.Time
+	if len(report.Recent) > 0 {
+		since = report.Recent[0].Timestamp
+	}
+	report.Annotations = m.annotationsSince(since)
+	if m.limits.Version > 0 {
+		limits := m.limits
+		report.Cgroup = &limits
+	}
+	if stats, err := m.GetStats(); err == nil {
+		report.Current = stats
+	}
+	return report
+}
+
+// StatusHandler 返回展示监控器实时状态的 http.Handler，可挂载到已有的 mux 上。
+// 默认返回 JSON；浏览器访问（Accept 含 text/html）时返回自动刷新的 HTML 页面。
+// 查询参数：format=json|html 强制输出格式，n=最近采样条数（默认 60，最多 1000）。
+//
+// 用法：
+//
+//	mux.Handle("/debug/monitor", mon.StatusHandler())
+//
+//	// curl http://pod-ip:8080/debug/monitor?n=10
+func (m *ResourceMonitor) StatusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, _ := strconv.Atoi(r.URL.Query().Get("n"))
+		report := m.Status(n)
+
+		format := r.URL.Query().Get("format")
+		if format == "" && strings.Contains(r.Header.Get("Accept"), "text/html") {
+			format = "html"
+		}
+		w.Header().Set("Cache-Control", "no-store")
+
+		if format == "html" {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			if err := statusTemplate.Execute(w, newStatusView(report, m.interval)); err != nil {
+				log.Debugf("monitor: 渲染状态页失败: %v", err)
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			log.Debugf("monitor: 输出状态 JSON 失败: %v", err)
+		}
+	})
+}
+
+// recentHistory 返回最近 n 次采样的副本（按时间升序）。
+func (m *ResourceMonitor) recentHistory(n int) []ResourceStats {
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+
+	start := max(len(m.history)-n, 0)
+	return append([]ResourceStats(nil), m.history[start:]...)
+}
+
+// ---------------------------------------------------------------------------
+// HTML 状态页
+// ---------------------------------------------------------------------------
+
+// statusView 状态页模板数据。
+type statusView struct {
+	*StatusReport
+	Refresh int             // 自动刷新间隔（秒）
+	Rows    []ResourceStats // 最近采样，按时间降序（最新在前）
+}
+
+func newStatusView(report *StatusReport, interval time.Duration) statusView {
+	rows := make([]ResourceStats, len(report.Recent))
+	for i, s := range report.Recent {
+		rows[len(rows)-1-i] = s
+	}
+	return statusView{
+		StatusReport: report,
+		Refresh:      max(int(interval.Seconds()), 2),
+		Rows:         rows,
+	}
+}
+
+var statusTemplate = template.Must(template.New("status").Funcs(template.FuncMap{
+	"bytes": FormatBytes,
+	"time":  func(t time.Time) string { return t.Format("15:04:05") },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="{{.Refresh}}">
+<title>monitor - pid {{.PID}}</title>
+<style>
+body { font-family: monospace; margin: 20px; }
+table { border-collapse: collapse; margin-bottom: 20px; }
+th, td { border: 1px solid #ccc; padding: 4px 10px; text-align: right; }
+th { background: #f0f0f0; }
+</style>
+</head>
+<body>
+<h3>PID {{.PID}} · {{if .Running}}运行中{{else}}已停止{{end}} · 采样间隔 {{.Interval}} · CPU 核心数 {{.NumCPU}}{{with .Cgroup}}{{if .CPUQuota}} · CPU 配额 {{printf "%.2f" .CPUQuota}} 核{{end}}{{if .MemoryLimit}} · 内存上限 {{bytes .MemoryLimit}}{{end}}{{end}}{{range $k, $v := .Labels}} · {{$k}}={{$v}}{{end}}</h3>
+{{with .Current}}
+<table>
+<tr><th>当前</th><th>CPU</th><th>内存</th><th>内存%</th><th>Goroutines</th><th>GC</th><th>HeapAlloc</th><th>HeapSys</th></tr>
+<tr><td>{{time .Timestamp}}</td><td>{{printf "%.1f%%" .CPUPercent}}</td><td>{{bytes .MemoryRSS}}</td><td>{{printf "%.1f%%" .MemoryPercent}}</td><td>{{.NumGoroutines}}</td><td>{{.NumGC}}</td><td>{{bytes .HeapAlloc}}</td><td>{{bytes .HeapSys}}</td></tr>
+</table>
+{{end}}
+{{with .Summary}}
+<table>
+<tr><th>汇总（{{.SampleCount}} 次采样）</th><th>最小</th><th>最大</th><th>平均</th></tr>
+<tr><td>CPU</td><td>{{printf "%.1f%%" .CPUMin}}</td><td>{{printf "%.1f%%" .CPUMax}}</td><td>{{printf "%.1f%%" .CPUAvg}}</td></tr>
+<tr><td>内存</td><td>{{bytes .MemoryMin}}</td><td>{{bytes .MemoryMax}}</td><td>{{bytes .MemoryAvg}}</td></tr>
+<tr><td>Goroutines</td><td>{{.GoroutineMin}}</td><td>{{.GoroutineMax}}</td><td>{{.GoroutineAvg}}</td></tr>
+</table>
+{{end}}
+{{with .Annotations}}
+<table>
+<tr><th>事件时间</th><th>事件</th></tr>
+{{range .}}<tr><td>{{time .Time}}</td><td style="text-align: left">{{.Text}}</td></tr>
+{{end}}
+</table>
+{{end}}
+<table>
+<tr><th>时间</th><th>CPU</th><th>内存</th><th>Goroutines</th><th>GC</th><th>HeapAlloc</th></tr>
+{{range .Rows}}<tr><td>{{time .Timestamp}}</td><td>{{printf "%.1f%%" .CPUPercent}}</td><td>{{bytes .MemoryRSS}}</td><td>{{.NumGoroutines}}</td><td>{{.NumGC}}</td><td>{{bytes .HeapAlloc}}</td></tr>
+{{else}}<tr><td colspan="6">暂无采样</td></tr>
+{{end}}
+</table>
+</body>
+</html>
+`))
@@ -0,0 +1,101 @@
+package monitor
+
+import (
+	"time"
+
+	"github.com/pylemonorg/gotools/timeutil"
+)
+
+// 内存趋势分析的参数。
+const (
+	minTrendPoints       = 5         // 拟合所需的最少记录数
+	minTrendSpan         = time.Hour // 拟合所需的最短时间跨度
+	defaultLeakMinGrowth = 5.0       // 默认最小日增长率（%）
+	defaultLeakMinR2     = 0.6       // 默认最小 R²
+)
+
+// analyzeMemoryTrend 对记录的 MemoryAvg 与 EndedAt 做线性拟合，记录不足或时间跨度太短时返回 nil。
+func analyzeMemoryTrend(records []SummaryRecord, opts *AnalyzeOptions) *TrendAnalysis {
+	times := make([]time.Time, 0, len(records))
+	values := make([]float64, 0, len(records))
+	for _, r := range records {
+		t, err := timeutil.ParseAny(r.EndedAt)
+		if err != nil {
+			continue
+		}
+		times = append(times, t)
+		values = append(values, float64(r.MemoryAvg))
+	}
+	if len(times) < minTrendPoints {
+		return nil
+	}
+
+	from, to := times[0], times[0]
+	for _, t := range times[1:] {
+		if t.Before(from) {
+			from = t
+		}
+		if t.After(to) {
+			to = t
+		}
+	}
+	if to.Sub(from) < minTrendSpan {
+		return nil
+	}
+
+	hours := make([]float64, len(times))
+	for i, t := range times {
+		hours[i] = t.Sub(from).Hours()
+	}
+	slope, r2, mean := linearFit(hours, values)
+
+	trend := &TrendAnalysis{
+		Points:       len(times),
+		From:         from,
+		To:           to,
+		SlopePerHour: slope,
+		R2:           r2,
+	}
+	if mean > 0 {
+		trend.GrowthPerDay = slope * 24 / mean * 100
+	}
+
+	minGrowth, minR2 := defaultLeakMinGrowth, defaultLeakMinR2
+	if opts != nil && opts.LeakMinGrowth > 0 {
+		minGrowth = opts.LeakMinGrowth
+	}
+	if opts != nil && opts.LeakMinR2 > 0 {
+		minR2 = opts.LeakMinR2
+	}
+	trend.Leak = slope > 0 && trend.GrowthPerDay >= minGrowth && r2 >= minR2
+	return trend
+}
+
+// linearFit 最小二乘拟合 y = a + b·x，返回斜率 b、拟合优度 R² 和 y 的平均值。
+// y 没有变化时 R² 为 0（不存在可解释的趋势）。
+func linearFit(x, y []float64) (slope, r2, meanY float64) {
+	n := float64(len(x))
+	var sumX, sumY float64
+	for i := range x {
+		sumX += x[i]
+		sumY += y[i]
+	}
+	meanX := sumX / n
+	meanY = sumY / n
+
+	var sxx, sxy, syy float64
+	for i := range x {
+		dx, dy := x[i]-meanX, y[i]-meanY
+		sxx += dx * dx
+		sxy += dx * dy
+		syy += dy * dy
+	}
+	if sxx == 0 {
+		return 0, 0, meanY
+	}
+	slope = sxy / sxx
+	if syy > 0 {
+		r2 = sxy * sxy / (sxx * syy)
+	}
+	return slope, r2, meanY
+}
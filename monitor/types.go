@@ -11,15 +11,32 @@ import (
 
 // ResourceStats 单次资源采样数据。
 type ResourceStats struct {
-	CPUPercent    float64   // CPU 使用率（百分比，多核场景可能 >100%）
-	MemoryRSS     uint64   // 常驻内存（字节）
-	MemoryVMS     uint64   // 虚拟内存（字节）
-	MemoryPercent float32  // 内存使用率（百分比）
-	NumGoroutines int      // Goroutine 数量
-	NumGC         uint32   // GC 累计次数
-	HeapAlloc     uint64   // 堆已分配内存（字节）
-	HeapSys       uint64   // 堆系统内存（字节）
-	Timestamp     time.Time // 采样时间
+	CPUPercent    float64   `json:"cpu_percent"`    // CPU 使用率（百分比，多核场景可能 >100%）
+	MemoryRSS     uint64    `json:"memory_rss"`     // 常驻内存（字节）
+	MemoryVMS     uint64    `json:"memory_vms"`     // 虚拟内存（字节）
+	MemoryPercent float32   `json:"memory_percent"` // 内存使用率（百分比）
+	NumGoroutines int       `json:"num_goroutines"` // Goroutine 数量
+	NumGC         uint32    `json:"num_gc"`         // GC 累计次数
+	HeapAlloc     uint64    `json:"heap_alloc"`     // 堆已分配内存（字节）
+	HeapSys       uint64    `json:"heap_sys"`       // 堆系统内存（字节）
+	Timestamp     time.Time `json:"timestamp"`      // 采样时间
+
+	// 以下字段仅在运行于有 CPU 配额 / 内存上限的 cgroup（容器）中时有值
+	CPUQuotaPercent    float64 `json:"cpu_quota_percent,omitempty"`    // CPU 使用率占配额的百分比（100 表示用满配额）
+	MemoryLimitPercent float64 `json:"memory_limit_percent,omitempty"` // 常驻内存占内存上限的百分比
+
+	// 以下字段仅在监控额外进程（NewResourceMonitorForPIDs）时有值
+	Processes       []ProcessStats `json:"processes,omitempty"`         // 各额外进程的采样（已退出或采样失败的进程不包含在内）
+	TotalCPUPercent float64        `json:"total_cpu_percent,omitempty"` // 当前进程 + 额外进程的 CPU 使用率之和
+	TotalMemoryRSS  uint64         `json:"total_memory_rss,omitempty"`  // 当前进程 + 额外进程的常驻内存之和（字节）
+}
+
+// ProcessStats 单个额外进程的单次采样数据。
+type ProcessStats struct {
+	PID           int32   `json:"pid"`            // 进程 ID
+	CPUPercent    float64 `json:"cpu_percent"`    // CPU 使用率（百分比）
+	MemoryRSS     uint64  `json:"memory_rss"`     // 常驻内存（字节）
+	MemoryPercent float32 `json:"memory_percent"` // 内存使用率（百分比）
 }
 
 // FormatStats 将采样数据格式化为一行摘要字符串。
@@ -44,12 +61,38 @@ type ResourceSummary struct {
 	GoroutineMin int     `json:"goroutine_min"`
 	GoroutineMax int     `json:"goroutine_max"`
 	GoroutineAvg int     `json:"goroutine_avg"`
+
+	// 以下字段仅在运行于有 CPU 配额 / 内存上限的 cgroup（容器）中时有值
+	CPUQuotaPercentMax    float64 `json:"cpu_quota_percent_max,omitempty"`    // CPU 使用率占配额的最大百分比
+	CPUQuotaPercentAvg    float64 `json:"cpu_quota_percent_avg,omitempty"`    // CPU 使用率占配额的平均百分比
+	MemoryLimitPercentMax float64 `json:"memory_limit_percent_max,omitempty"` // 常驻内存占内存上限的最大百分比
+	MemoryLimitPercentAvg float64 `json:"memory_limit_percent_avg,omitempty"` // 常驻内存占内存上限的平均百分比
+
+	// 以下字段仅在监控额外进程时有值
+	Processes []ProcessSummary `json:"processes,omitempty"` // 各额外进程的汇总
+	Aggregate *ProcessSummary  `json:"aggregate,omitempty"` // 当前进程 + 额外进程的合计汇总（PID 为 0）
+}
+
+// ProcessSummary 单个进程（或进程合计）的 CPU / 内存汇总。
+type ProcessSummary struct {
+	PID         int32   `json:"pid"`
+	SampleCount int     `json:"sample_count"`
+	CPUMin      float64 `json:"cpu_min"`
+	CPUMax      float64 `json:"cpu_max"`
+	CPUAvg      float64 `json:"cpu_avg"`
+	MemoryMin   uint64  `json:"memory_min"`
+	MemoryMax   uint64  `json:"memory_max"`
+	MemoryAvg   uint64  `json:"memory_avg"`
 }
 
-// SummaryRecord 持久化到 Redis 的 JSON 结构，包含 CPU 核心数、记录时间和资源汇总。
+// SummaryRecord 持久化到 Redis 的 JSON 结构，包含 CPU 核心数、cgroup 限制、记录时间、运行标签和资源汇总。
 type SummaryRecord struct {
-	NumCPU  int    `json:"num_cpu"`
-	EndedAt string `json:"ended_at"`
+	NumCPU      int                 `json:"num_cpu"`
+	CPUQuota    float64             `json:"cpu_quota,omitempty"`    // cgroup CPU 配额（核数），未限制时省略
+	MemoryLimit uint64              `json:"memory_limit,omitempty"` // cgroup 内存上限（字节），未限制时省略
+	EndedAt     string              `json:"ended_at"`
+	Labels      map[string]string   `json:"labels,omitempty"`
+	Annotations []AnnotationSummary `json:"annotations,omitempty"` // 事件标注及各时间段的资源使用，见 Annotate
 	ResourceSummary
 }
 
@@ -63,17 +106,36 @@ type SummarySaver interface {
 	SaveSummary(key string, jsonValue string) error
 }
 
+// StatsExporter 采样数据导出接口，每次采样后调用（如推送到 OTel 等指标系统）。
+// 与 OnStats 和默认日志互不影响；实现应尽快返回，避免拖慢采样循环。
+type StatsExporter interface {
+	ExportStats(stats *ResourceStats)
+}
+
 // ---------------------------------------------------------------------------
 // 配置
 // ---------------------------------------------------------------------------
 
-// Config 监控器配置。
+// Config 监控器配置，是所有配置项的唯一定义。
+// 也可以通过 Option（WithInterval、WithSaver 等）函数式设置，两者可混用。
 type Config struct {
 	Interval    time.Duration              // 采样间隔，默认 2s
 	LogInterval time.Duration              // 日志输出间隔，默认等于 Interval
 	OnStats     func(stats *ResourceStats) // 采样回调（设置后不再输出默认日志）
 	Saver       SummarySaver               // 汇总持久化实现（Stop 时保存），可为 nil
 	SaveKey     string                     // 持久化的 Redis key
+	Labels      map[string]string          // 运行标签（如 app、version、job_id、region），随汇总一起持久化
+
+	Exporters         []StatsExporter    // 采样数据导出器，每次采样后依次调用
+	GoroutineWatchdog *GoroutineWatchdog // 协程泄漏看门狗，为 nil 时不启用
+}
+
+// GoroutineWatchdog 协程泄漏看门狗配置。
+// 协程数超过 Threshold 且连续 Samples 次采样单调增长时，转储一次全部协程栈。
+type GoroutineWatchdog struct {
+	Threshold int    // 协程数阈值，<= 0 时默认 1000
+	Samples   int    // 连续增长的采样次数，<= 0 时默认 10
+	DumpPath  string // 栈转储文件路径，为空时输出到日志（Warn 级别）
 }
 
 // ---------------------------------------------------------------------------
@@ -82,21 +144,53 @@ type Config struct {
 
 // AnalyzeOptions 资源分析选项。
 type AnalyzeOptions struct {
-	Since time.Time // 仅分析此时间之后的记录，零值表示不过滤
+	Since   time.Time         // 仅分析此时间之后的记录，零值表示不过滤
+	Labels  map[string]string // 仅分析标签全部匹配的记录，为空表示不过滤
+	GroupBy []string          // 除 CPU 核心数（或 CPU 配额）外，额外按这些标签分组（如 "app"、"version"）
+
+	// 以下为疑似内存泄漏的判定阈值，见 TrendAnalysis
+	LeakMinGrowth float64 // 最小日增长率（占平均内存的百分比），<= 0 时默认 5
+	LeakMinR2     float64 // 线性拟合的最小 R²（0~1），<= 0 时默认 0.6
 }
 
-// AnalyzeResult 单个 CPU 分组的聚合分析结果。
+// AnalyzeResult 单个分组（CPU 核心数或 CPU 配额 + GroupBy 标签）的聚合分析结果。
+// 记录带有 cgroup CPU 配额时按配额分组（NumCPU 为 0），否则按宿主机 CPU 核心数分组。
 type AnalyzeResult struct {
-	NumCPU       int     // CPU 核心数
-	RecordCount  int     // 记录条数
-	TotalSamples int     // 总采样次数
-	CPUMin       float64 // CPU 使用率最小值
-	CPUMax       float64 // CPU 使用率最大值
-	CPUAvg       float64 // CPU 加权平均值
-	MemoryMin    uint64  // 内存最小值（字节）
-	MemoryMax    uint64  // 内存最大值（字节）
-	MemoryAvg    uint64  // 内存加权平均值（字节）
-	GoroutineMin int     // Goroutine 最小数量
-	GoroutineMax int     // Goroutine 最大数量
-	GoroutineAvg int     // Goroutine 加权平均数量
+	NumCPU       int               // CPU 核心数（按配额分组时为 0）
+	CPUQuota     float64           // CPU 配额（核数），按核心数分组时为 0
+	Labels       map[string]string // 分组标签（仅包含 GroupBy 中的 key），未分组时为 nil
+	RecordCount  int               // 记录条数
+	TotalSamples int               // 总采样次数
+	CPUMin       float64           // CPU 使用率最小值
+	CPUMax       float64           // CPU 使用率最大值
+	CPUAvg       float64           // CPU 加权平均值
+	MemoryMin    uint64            // 内存最小值（字节）
+	MemoryMax    uint64            // 内存最大值（字节）
+	MemoryAvg    uint64            // 内存加权平均值（字节）
+	GoroutineMin int               // Goroutine 最小数量
+	GoroutineMax int               // Goroutine 最大数量
+	GoroutineAvg int               // Goroutine 加权平均数量
+	MemoryTrend  *TrendAnalysis    // 内存随时间的变化趋势，记录不足时为 nil
+}
+
+// TrendAnalysis 分组内各记录的内存加权平均值（MemoryAvg）随记录时间（EndedAt）的线性趋势（最小二乘拟合）。
+// 至少需要 5 条时间可解析的记录且时间跨度不少于 1 小时。
+// 斜率为正、日增长率不低于 AnalyzeOptions.LeakMinGrowth 且 R² 不低于 AnalyzeOptions.LeakMinR2 时判定为疑似内存泄漏：
+// 内存持续、稳定地增长，而不是偶发的峰值。
+type TrendAnalysis struct {
+	Points       int       // 参与拟合的记录数
+	From         time.Time // 最早的记录时间
+	To           time.Time // 最晚的记录时间
+	SlopePerHour float64   // 斜率（字节/小时）
+	GrowthPerDay float64   // 日增长率（每天增长量占平均内存的百分比）
+	R2           float64   // 拟合优度 R²（0~1），越接近 1 说明增长越稳定
+	Leak         bool      // 疑似内存泄漏
+}
+
+// CPUs 返回分组实际可用的 CPU 核数：按配额分组时返回配额，否则返回核心数。
+func (r AnalyzeResult) CPUs() float64 {
+	if r.CPUQuota > 0 {
+		return r.CPUQuota
+	}
+	return float64(r.NumCPU)
 }
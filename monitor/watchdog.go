@@ -0,0 +1,92 @@
+package monitor
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+)
+
+// 看门狗默认参数。
+const (
+	defaultWatchdogThreshold = 1000
+	defaultWatchdogSamples   = 10
+)
+
+// goroutineWatchdog 协程泄漏检测状态，仅在监控主循环中访问，无需加锁。
+type goroutineWatchdog struct {
+	cfg    GoroutineWatchdog
+	last   int  // 上一次采样的协程数
+	rising int  // 连续增长次数
+	fired  bool // 本轮运行是否已转储
+}
+
+// newGoroutineWatchdog 根据配置创建看门狗并填充默认值，cfg 为 nil 时返回 nil。
+func newGoroutineWatchdog(cfg *GoroutineWatchdog) *goroutineWatchdog {
+	if cfg == nil {
+		return nil
+	}
+	c := *cfg
+	if c.Threshold <= 0 {
+		c.Threshold = defaultWatchdogThreshold
+	}
+	if c.Samples <= 0 {
+		c.Samples = defaultWatchdogSamples
+	}
+	return &goroutineWatchdog{cfg: c}
+}
+
+// reset 清空检测状态，每次 Start 时调用。
+func (w *goroutineWatchdog) reset() {
+	w.last, w.rising, w.fired = 0, 0, false
+}
+
+// observe 记录一次采样的协程数，满足触发条件（且本轮尚未触发）时返回 true。
+func (w *goroutineWatchdog) observe(n int) bool {
+	if n > w.last && w.last > 0 {
+		w.rising++
+	} else {
+		w.rising = 0
+	}
+	w.last = n
+
+	if w.fired || n <= w.cfg.Threshold || w.rising < w.cfg.Samples {
+		return false
+	}
+	w.fired = true
+	return true
+}
+
+// dump 转储全部协程栈到文件或日志。
+func (w *goroutineWatchdog) dump(count int) {
+	log.Warnf("monitor: 疑似协程泄漏，协程数 %d 已连续 %d 次增长（阈值 %d），转储协程栈",
+		count, w.rising, w.cfg.Threshold)
+
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 2); err != nil {
+		log.Warnf("monitor: 获取协程栈失败: %v", err)
+		return
+	}
+
+	if w.cfg.DumpPath == "" {
+		log.Warnf("monitor: 协程栈:\n%s", buf.String())
+		return
+	}
+	if err := writeDumpFile(w.cfg.DumpPath, buf.Bytes()); err != nil {
+		log.Warnf("monitor: %v", err)
+		return
+	}
+	log.Warnf("monitor: 协程栈已写入 [%s]", w.cfg.DumpPath)
+}
+
+// writeDumpFile 写入转储文件，自动创建父目录。
+func writeDumpFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("创建转储目录失败: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("写入协程栈文件 [%s] 失败: %w", path, err)
+	}
+	return nil
+}
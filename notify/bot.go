@@ -0,0 +1,112 @@
+package notify
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pylemonorg/gotools/hashutil"
+)
+
+// ---------------------------------------------------------------------------
+// 钉钉
+// ---------------------------------------------------------------------------
+
+// DingTalk 钉钉群机器人发送器，以 Markdown 消息发送。
+type DingTalk struct {
+	webhook   string
+	secret    string
+	atMobiles []string
+}
+
+// NewDingTalk 创建钉钉群机器人发送器。
+// secret 为机器人「加签」密钥，未开启加签时传空串；atMobiles 为需要 @ 的成员手机号。
+//
+// 用法：
+//
+//	ding := notify.NewDingTalk("https://oapi.dingtalk.com/robot/send?access_token=xxx", "SECxxx")
+//	err := ding.Notify(ctx, &notify.Message{Title: "任务失败", Content: err.Error(), Level: notify.LevelError})
+func NewDingTalk(webhook, secret string, atMobiles ...string) *DingTalk {
+	return &DingTalk{webhook: webhook, secret: secret, atMobiles: atMobiles}
+}
+
+// Notify 实现 Notifier 接口。
+func (d *DingTalk) Notify(ctx context.Context, msg *Message) error {
+	text := msg.markdown()
+	if len(d.atMobiles) > 0 {
+		// 钉钉 Markdown 消息需要在正文中包含 @手机号 才会真正提醒
+		text += "\n\n@" + strings.Join(d.atMobiles, " @")
+	}
+	payload := map[string]any{
+		"msgtype": "markdown",
+		"markdown": map[string]string{
+			"title": msg.subject(),
+			"text":  text,
+		},
+		"at": map[string]any{"atMobiles": d.atMobiles},
+	}
+
+	body, err := postJSON(ctx, d.signedURL(time.Now()), nil, payload)
+	if err != nil {
+		return fmt.Errorf("notify: 钉钉: %w", err)
+	}
+	if err := checkBotResponse(body); err != nil {
+		return fmt.Errorf("notify: 钉钉: %w", err)
+	}
+	return nil
+}
+
+// signedURL 开启加签时在 webhook 后追加 timestamp 和 sign 参数。
+// 签名为 Base64(HMAC-SHA256(secret, timestamp + "\n" + secret))。
+func (d *DingTalk) signedURL(now time.Time) string {
+	if d.secret == "" {
+		return d.webhook
+	}
+	ts := strconv.FormatInt(now.UnixMilli(), 10)
+	sign := base64.StdEncoding.EncodeToString(hashutil.HMACSHA256([]byte(d.secret), []byte(ts+"\n"+d.secret)))
+
+	sep := "?"
+	if strings.Contains(d.webhook, "?") {
+		sep = "&"
+	}
+	return d.webhook + sep + "timestamp=" + ts + "&sign=" + url.QueryEscape(sign)
+}
+
+// ---------------------------------------------------------------------------
+// 企业微信
+// ---------------------------------------------------------------------------
+
+// WeCom 企业微信群机器人发送器，以 Markdown 消息发送。
+type WeCom struct {
+	webhook string
+}
+
+// NewWeCom 创建企业微信群机器人发送器。
+//
+// 用法：
+//
+//	wecom := notify.NewWeCom("https://qyapi.weixin.qq.com/cgi-bin/webhook/send?key=xxx")
+func NewWeCom(webhook string) *WeCom {
+	return &WeCom{webhook: webhook}
+}
+
+// Notify 实现 Notifier 接口。
+func (w *WeCom) Notify(ctx context.Context, msg *Message) error {
+	payload := map[string]any{
+		"msgtype":  "markdown",
+		"markdown": map[string]string{"content": msg.markdown()},
+	}
+
+	body, err := postJSON(ctx, w.webhook, nil, payload)
+	if err != nil {
+		return fmt.Errorf("notify: 企业微信: %w", err)
+	}
+	if err := checkBotResponse(body); err != nil {
+		return fmt.Errorf("notify: 企业微信: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTPConfig SMTP 发送配置。
+type SMTPConfig struct {
+	Host     string   // SMTP 服务器地址
+	Port     int      // 端口，默认 465（SSL）或 587（STARTTLS）
+	Username string   // 登录用户名，为空时不认证
+	Password string   // 登录密码或授权码
+	From     string   // 发件人，为空时使用 Username
+	To       []string // 收件人
+	SSL      bool     // true 使用隐式 TLS（通常 465 端口），false 时服务器支持则自动 STARTTLS
+}
+
+// Email SMTP 邮件发送器，正文为 UTF-8 纯文本。
+type Email struct {
+	cfg SMTPConfig
+}
+
+// NewEmail 创建邮件发送器。
+//
+// 用法：
+//
+//	mail := notify.NewEmail(notify.SMTPConfig{
+//	    Host:     "smtp.example.com",
+//	    Username: "alert@example.com",
+//	    Password: os.Getenv("SMTP_PASSWORD"),
+//	    To:       []string{"oncall@example.com"},
+//	    SSL:      true,
+//	})
+func NewEmail(cfg SMTPConfig) *Email {
+	if cfg.Port == 0 {
+		cfg.Port = 587
+		if cfg.SSL {
+			cfg.Port = 465
+		}
+	}
+	if cfg.From == "" {
+		cfg.From = cfg.Username
+	}
+	return &Email{cfg: cfg}
+}
+
+// Notify 实现 Notifier 接口。
+func (e *Email) Notify(ctx context.Context, msg *Message) error {
+	if len(e.cfg.To) == 0 {
+		return errors.New("notify: 邮件: 未配置收件人")
+	}
+	if err := e.send(ctx, buildMail(e.cfg.From, e.cfg.To, msg)); err != nil {
+		return fmt.Errorf("notify: 邮件: %w", err)
+	}
+	return nil
+}
+
+// send 连接 SMTP 服务器并投递一封邮件，ctx 的截止时间同时作为连接的读写超时。
+func (e *Email) send(ctx context.Context, data []byte) error {
+	addr := net.JoinHostPort(e.cfg.Host, strconv.Itoa(e.cfg.Port))
+	tlsCfg := &tls.Config{ServerName: e.cfg.Host}
+
+	var conn net.Conn
+	var err error
+	if e.cfg.SSL {
+		conn, err = (&tls.Dialer{Config: tlsCfg}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("连接 [%s] 失败: %w", addr, err)
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(30 * time.Second)
+	}
+	conn.SetDeadline(deadline)
+
+	c, err := smtp.NewClient(conn, e.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("握手失败: %w", err)
+	}
+	defer c.Close()
+
+	if !e.cfg.SSL {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err := c.StartTLS(tlsCfg); err != nil {
+				return fmt.Errorf("STARTTLS 失败: %w", err)
+			}
+		}
+	}
+	if e.cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", e.cfg.Username, e.cfg.Password, e.cfg.Host)); err != nil {
+			return fmt.Errorf("认证失败: %w", err)
+		}
+	}
+	if err := c.Mail(e.cfg.From); err != nil {
+		return fmt.Errorf("MAIL FROM 失败: %w", err)
+	}
+	for _, to := range e.cfg.To {
+		if err := c.Rcpt(to); err != nil {
+			return fmt.Errorf("RCPT TO [%s] 失败: %w", to, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("DATA 失败: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return fmt.Errorf("写入邮件失败: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("写入邮件失败: %w", err)
+	}
+	return c.Quit()
+}
+
+// buildMail 构造 MIME 邮件：主题按 RFC 2047 编码，正文 Base64 编码（每行 76 字符）。
+func buildMail(from string, to []string, msg *Message) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", msg.subject()))
+	fmt.Fprintf(&b, "Date: %s\r\n", msg.time().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+
+	body := base64.StdEncoding.EncodeToString([]byte(msg.plain()))
+	for len(body) > 76 {
+		b.WriteString(body[:76])
+		b.WriteString("\r\n")
+		body = body[76:]
+	}
+	b.WriteString(body)
+	b.WriteString("\r\n")
+	return b.Bytes()
+}
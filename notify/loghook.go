@@ -0,0 +1,155 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pylemonorg/gotools/logger"
+	"github.com/pylemonorg/gotools/strutil"
+)
+
+// LogHook 将日志作为通知发送的 logger.Hook，实现 logger.Flusher 和 io.Closer。
+// Fire 只把日志放入缓冲队列，由后台协程逐条发送，队列满时丢弃。
+// 发送失败只输出到 stderr，避免通过 logger 记录而再次触发钩子。
+type LogHook struct {
+	n       Notifier
+	timeout time.Duration
+	ch      chan *logger.Entry
+	flush   chan chan struct{}
+	done    chan struct{}
+
+	mu      sync.RWMutex
+	closed  bool
+	dropped atomic.Int64
+}
+
+// NewLogHook 创建日志通知钩子，bufferSize <= 0 时默认 100。
+// 日志量可能很大，n 通常应先用 WithRateLimit 包装。
+//
+// 用法：
+//
+//	n := notify.WithRateLimit(notify.NewWeCom(url), notify.RateLimit{Max: 20, Dedup: 5 * time.Minute})
+//	remove := logger.AddHook(notify.NewLogHook(n, 0), logger.LevelError)
+//	defer remove()
+func NewLogHook(n Notifier, bufferSize int) *LogHook {
+	if bufferSize <= 0 {
+		bufferSize = 100
+	}
+	h := &LogHook{
+		n:       n,
+		timeout: 10 * time.Second,
+		ch:      make(chan *logger.Entry, bufferSize),
+		flush:   make(chan chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go h.run()
+	return h
+}
+
+// Fire 实现 logger.Hook。
+func (h *LogHook) Fire(entry *logger.Entry) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.closed {
+		return nil
+	}
+	select {
+	case h.ch <- entry:
+	default:
+		h.dropped.Add(1)
+	}
+	return nil
+}
+
+// Flush 实现 logger.Flusher，发送缓冲中的全部日志并等待完成。
+func (h *LogHook) Flush() error {
+	h.mu.RLock()
+	if h.closed {
+		h.mu.RUnlock()
+		return nil
+	}
+	req := make(chan struct{})
+	h.flush <- req
+	h.mu.RUnlock()
+	<-req
+	return nil
+}
+
+// Close 实现 io.Closer，发送剩余日志后停止后台协程。可重复调用。
+func (h *LogHook) Close() error {
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return nil
+	}
+	h.closed = true
+	close(h.ch)
+	h.mu.Unlock()
+
+	<-h.done
+	if n := h.dropped.Load(); n > 0 {
+		fmt.Fprintf(os.Stderr, "notify: 日志钩子缓冲队列已满，共丢弃 %d 条日志\n", n)
+	}
+	return nil
+}
+
+// run 后台发送循环。
+func (h *LogHook) run() {
+	defer close(h.done)
+	for {
+		select {
+		case entry, ok := <-h.ch:
+			if !ok {
+				return
+			}
+			h.send(entry)
+		case req := <-h.flush:
+			for drained := false; !drained; {
+				select {
+				case entry, ok := <-h.ch:
+					if !ok { // Flush 等待期间 Close 关闭了队列
+						close(req)
+						return
+					}
+					h.send(entry)
+				default:
+					drained = true
+				}
+			}
+			close(req)
+		}
+	}
+}
+
+// send 将日志转换为消息并发送。
+func (h *LogHook) send(entry *logger.Entry) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	defer cancel()
+	if err := h.n.Notify(ctx, entryMessage(entry)); err != nil && err != ErrRateLimited {
+		fmt.Fprintf(os.Stderr, "notify: 发送日志通知失败: %v\n", err)
+	}
+}
+
+// entryMessage 将日志转换为消息：标题取日志首行（最多 64 字），模块名作为标题前缀，附加字段放入 Fields。
+func entryMessage(e *logger.Entry) *Message {
+	title, _, _ := strings.Cut(e.Message, "\n")
+	if e.Module != "" {
+		title = "[" + e.Module + "] " + title
+	}
+	fields := make(map[string]string, len(e.Fields))
+	for k, v := range e.Fields {
+		fields[k] = fmt.Sprint(v)
+	}
+	return &Message{
+		Title:   strutil.TruncateRunes(title, 64),
+		Content: e.Message,
+		Level:   e.Level,
+		Fields:  fields,
+		Time:    e.Time,
+	}
+}
@@ -0,0 +1,120 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pylemonorg/gotools/maputil"
+)
+
+// 消息级别，与 logger 的级别字符串一致。
+const (
+	LevelInfo  = "info"
+	LevelWarn  = "warn"
+	LevelError = "error"
+	LevelFatal = "fatal"
+)
+
+// Message 一条通知消息。
+type Message struct {
+	Title   string            // 标题（邮件主题 / 群消息标题）
+	Content string            // 正文，钉钉 / 企业微信按 Markdown 渲染
+	Level   string            // 级别，见 LevelInfo 等常量，为空时视为 info
+	Fields  map[string]string // 附加字段，按 key 排序后追加在正文之后
+	Time    time.Time         // 发生时间，零值时使用发送时间
+}
+
+// Notifier 通知发送器。实现需并发安全，Notify 应遵守 ctx 的取消和超时。
+type Notifier interface {
+	Notify(ctx context.Context, msg *Message) error
+}
+
+// NotifierFunc 函数形式的 Notifier。
+type NotifierFunc func(ctx context.Context, msg *Message) error
+
+// Notify 实现 Notifier 接口。
+func (f NotifierFunc) Notify(ctx context.Context, msg *Message) error { return f(ctx, msg) }
+
+// Multi 将消息依次发送到多个 Notifier，全部尝试后返回合并的错误（errors.Join）。
+//
+// 用法：
+//
+//	n := notify.Multi(
+//	    notify.NewDingTalk(dingURL, dingSecret),
+//	    notify.NewEmail(smtpCfg),
+//	)
+func Multi(notifiers ...Notifier) Notifier {
+	return NotifierFunc(func(ctx context.Context, msg *Message) error {
+		var errs []error
+		for _, n := range notifiers {
+			if err := n.Notify(ctx, msg); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// 消息渲染（内部）
+// ---------------------------------------------------------------------------
+
+// levelTag 返回级别标记，如 "[ERROR]"。
+func (m *Message) levelTag() string {
+	level := m.Level
+	if level == "" {
+		level = LevelInfo
+	}
+	return "[" + strings.ToUpper(level) + "]"
+}
+
+// time 返回消息时间，零值时返回当前时间。
+func (m *Message) time() time.Time {
+	if m.Time.IsZero() {
+		return time.Now()
+	}
+	return m.Time
+}
+
+// subject 返回带级别标记的标题。
+func (m *Message) subject() string {
+	return m.levelTag() + " " + m.Title
+}
+
+// markdown 渲染为 Markdown 文本（钉钉 / 企业微信）。
+func (m *Message) markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "### %s\n\n", m.subject())
+	if m.Content != "" {
+		b.WriteString(m.Content)
+		b.WriteString("\n\n")
+	}
+	for _, k := range maputil.SortedKeys(m.Fields) {
+		fmt.Fprintf(&b, "- **%s**: %s\n", k, m.Fields[k])
+	}
+	if len(m.Fields) > 0 {
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "> %s", m.time().Format(time.DateTime))
+	return b.String()
+}
+
+// plain 渲染为纯文本（邮件正文）。
+func (m *Message) plain() string {
+	var b strings.Builder
+	if m.Content != "" {
+		b.WriteString(m.Content)
+		b.WriteString("\n\n")
+	}
+	for _, k := range maputil.SortedKeys(m.Fields) {
+		fmt.Fprintf(&b, "%s: %s\n", k, m.Fields[k])
+	}
+	if len(m.Fields) > 0 {
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "时间: %s\n", m.time().Format(time.DateTime))
+	return b.String()
+}
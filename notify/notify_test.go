@@ -0,0 +1,275 @@
+package notify
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pylemonorg/gotools/hashutil"
+	"github.com/pylemonorg/gotools/logger"
+)
+
+// recorder 记录收到的请求体。
+type recorder struct {
+	mu     sync.Mutex
+	bodies []map[string]any
+	urls   []string
+}
+
+func (r *recorder) server(t *testing.T, resp string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		var m map[string]any
+		if err := json.Unmarshal(body, &m); err != nil {
+			t.Errorf("invalid JSON body: %s", body)
+		}
+		r.mu.Lock()
+		r.bodies = append(r.bodies, m)
+		r.urls = append(r.urls, req.URL.String())
+		r.mu.Unlock()
+		io.WriteString(w, resp)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestWebhook(t *testing.T) {
+	var rec recorder
+	srv := rec.server(t, "ok")
+
+	msg := &Message{Title: "t", Content: "c", Fields: map[string]string{"k": "v"}}
+	if err := NewWebhook(srv.URL, nil).Notify(context.Background(), msg); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	got := rec.bodies[0]
+	if got["title"] != "t" || got["content"] != "c" || got["level"] != LevelInfo {
+		t.Errorf("body = %v", got)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	if err := NewWebhook(failing.URL, nil).Notify(context.Background(), msg); err == nil {
+		t.Error("Notify should fail on 500")
+	}
+}
+
+func TestDingTalk(t *testing.T) {
+	var rec recorder
+	srv := rec.server(t, `{"errcode":0,"errmsg":"ok"}`)
+
+	d := NewDingTalk(srv.URL+"/robot/send?access_token=x", "SECret", "13800000000")
+	msg := &Message{Title: "任务失败", Content: "boom", Level: LevelError}
+	if err := d.Notify(context.Background(), msg); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	md := rec.bodies[0]["markdown"].(map[string]any)
+	if md["title"] != "[ERROR] 任务失败" {
+		t.Errorf("title = %v", md["title"])
+	}
+	if text := md["text"].(string); !strings.Contains(text, "boom") || !strings.Contains(text, "@13800000000") {
+		t.Errorf("text = %q", text)
+	}
+	if u := rec.urls[0]; !strings.Contains(u, "access_token=x&timestamp=") || !strings.Contains(u, "&sign=") {
+		t.Errorf("url = %q, want signed", u)
+	}
+
+	errSrv := (&recorder{}).server(t, `{"errcode":310000,"errmsg":"sign not match"}`)
+	if err := NewDingTalk(errSrv.URL, "").Notify(context.Background(), msg); err == nil || !strings.Contains(err.Error(), "310000") {
+		t.Errorf("Notify = %v, want errcode error", err)
+	}
+}
+
+func TestDingTalkSign(t *testing.T) {
+	d := NewDingTalk("https://example.com/send?access_token=x", "sec")
+	now := time.UnixMilli(1700000000000)
+	want := base64.StdEncoding.EncodeToString(hashutil.HMACSHA256([]byte("sec"), []byte("1700000000000\nsec")))
+
+	u := d.signedURL(now)
+	if !strings.HasPrefix(u, "https://example.com/send?access_token=x&timestamp=1700000000000&sign=") {
+		t.Fatalf("signedURL = %q", u)
+	}
+	if !strings.Contains(u, url.QueryEscape(want)) {
+		t.Errorf("signedURL = %q, want sign %q", u, want)
+	}
+}
+
+func TestWeCom(t *testing.T) {
+	var rec recorder
+	srv := rec.server(t, `{"errcode":0,"errmsg":"ok"}`)
+
+	msg := &Message{Title: "磁盘告警", Content: "使用率 95%", Level: LevelWarn}
+	if err := NewWeCom(srv.URL).Notify(context.Background(), msg); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	content := rec.bodies[0]["markdown"].(map[string]any)["content"].(string)
+	if !strings.HasPrefix(content, "### [WARN] 磁盘告警") || !strings.Contains(content, "使用率 95%") {
+		t.Errorf("content = %q", content)
+	}
+}
+
+func TestBuildMail(t *testing.T) {
+	msg := &Message{Title: "测试", Content: "正文", Time: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	mail := string(buildMail("a@example.com", []string{"b@example.com", "c@example.com"}, msg))
+
+	for _, want := range []string{
+		"From: a@example.com\r\n",
+		"To: b@example.com, c@example.com\r\n",
+		"Subject: =?UTF-8?b?",
+		"Content-Transfer-Encoding: base64\r\n\r\n",
+	} {
+		if !strings.Contains(mail, want) {
+			t.Errorf("mail missing %q:\n%s", want, mail)
+		}
+	}
+	_, body, _ := strings.Cut(mail, "\r\n\r\n")
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(body, "\r\n", ""))
+	if err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if !strings.Contains(string(decoded), "正文") || !strings.Contains(string(decoded), "2024-01-02 03:04:05") {
+		t.Errorf("body = %q", decoded)
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	tpl := MustTemplate(LevelWarn, "{{.App}} 内存过高", "当前 {{.MB}} MB")
+	msg, err := tpl.Render(map[string]any{"App": "crawler", "MB": 2048})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if msg.Title != "crawler 内存过高" || msg.Content != "当前 2048 MB" || msg.Level != LevelWarn {
+		t.Errorf("Render = %+v", msg)
+	}
+	if _, err := NewTemplate(LevelInfo, "{{", ""); err == nil {
+		t.Error("NewTemplate should fail on invalid template")
+	}
+}
+
+// collector 记录收到的消息。
+type collector struct {
+	mu   sync.Mutex
+	msgs []*Message
+}
+
+func (c *collector) Notify(_ context.Context, msg *Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.msgs = append(c.msgs, msg)
+	return nil
+}
+
+func TestRateLimiterMax(t *testing.T) {
+	var c collector
+	r := WithRateLimit(&c, RateLimit{Max: 2, Window: time.Minute})
+	now := time.Now()
+
+	for i := 0; i < 4; i++ {
+		_, ok := r.allow(&Message{Title: "x"}, now)
+		if want := i < 2; ok != want {
+			t.Errorf("allow #%d = %v, want %v", i, ok, want)
+		}
+	}
+	suppressed, ok := r.allow(&Message{Title: "x"}, now.Add(time.Minute+time.Second))
+	if !ok || suppressed != 2 {
+		t.Errorf("allow after window = (%d, %v), want (2, true)", suppressed, ok)
+	}
+}
+
+func TestRateLimiterDedup(t *testing.T) {
+	var c collector
+	r := WithRateLimit(&c, RateLimit{Dedup: time.Hour})
+	ctx := context.Background()
+
+	if err := r.Notify(ctx, &Message{Title: "a"}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if err := r.Notify(ctx, &Message{Title: "a"}); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("duplicate Notify = %v, want ErrRateLimited", err)
+	}
+	if err := r.Notify(ctx, &Message{Title: "b", Content: "c"}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if len(c.msgs) != 2 || !strings.Contains(c.msgs[1].Content, "已抑制 1 条") {
+		t.Errorf("msgs = %+v", c.msgs)
+	}
+	if r.Suppressed() != 0 {
+		t.Errorf("Suppressed = %d, want 0", r.Suppressed())
+	}
+}
+
+func TestMulti(t *testing.T) {
+	var c collector
+	boom := errors.New("boom")
+	n := Multi(NotifierFunc(func(context.Context, *Message) error { return boom }), &c)
+	if err := n.Notify(context.Background(), &Message{Title: "x"}); !errors.Is(err, boom) {
+		t.Errorf("Multi = %v, want boom", err)
+	}
+	if len(c.msgs) != 1 {
+		t.Errorf("second notifier got %d messages, want 1", len(c.msgs))
+	}
+}
+
+func TestLogHook(t *testing.T) {
+	var c collector
+	h := NewLogHook(&c, 0)
+	h.Fire(&logger.Entry{Level: LevelError, Message: "db down\nstack...", Module: "db", Fields: map[string]any{"retry": 3}})
+	if err := h.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	h.Close()
+	h.Fire(&logger.Entry{Message: "after close"})
+
+	if len(c.msgs) != 1 {
+		t.Fatalf("got %d messages, want 1", len(c.msgs))
+	}
+	m := c.msgs[0]
+	if m.Title != "[db] db down" || m.Level != LevelError || m.Fields["retry"] != "3" {
+		t.Errorf("message = %+v", m)
+	}
+}
+
+// slowCollector 每条消息耗时 1ms，使 Flush 的排空过程与 Close 重叠。
+type slowCollector struct{ collector }
+
+func (c *slowCollector) Notify(ctx context.Context, msg *Message) error {
+	time.Sleep(time.Millisecond)
+	return c.collector.Notify(ctx, msg)
+}
+
+func TestLogHookFlushDuringClose(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		var c slowCollector
+		h := NewLogHook(&c, 100)
+		for j := 0; j < 20; j++ {
+			h.Fire(&logger.Entry{Level: LevelError, Message: "boom"})
+		}
+		done := make(chan struct{})
+		go func() {
+			h.Flush()
+			close(done)
+		}()
+		time.Sleep(5 * time.Millisecond) // 等待进入 Flush 的排空过程
+		h.Close()
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			t.Fatal("Flush did not return after Close")
+		}
+		if len(c.msgs) != 20 {
+			t.Fatalf("got %d messages, want 20", len(c.msgs))
+		}
+	}
+}
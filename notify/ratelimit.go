@@ -0,0 +1,113 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited 表示消息因超出发送频率或重复而被丢弃。
+var ErrRateLimited = errors.New("notify: 超出发送频率限制，消息已丢弃")
+
+// dedupPruneSize 去重表超过该大小时清理过期条目。
+const dedupPruneSize = 1024
+
+// RateLimit 发送频率限制。钉钉、企业微信机器人都有每分钟 20 条左右的限制，
+// 告警风暴时应在发送器外包一层限流。
+type RateLimit struct {
+	Max    int           // 每个 Window 内最多发送条数，<= 0 表示不限制
+	Window time.Duration // 统计窗口，默认 1min
+	Dedup  time.Duration // 级别和标题都相同的消息在该时间内只发送一次，0 表示不去重
+}
+
+// RateLimiter 带限流和去重的 Notifier 包装。被丢弃的消息返回 ErrRateLimited，
+// 并在下一条成功放行的消息正文末尾注明期间抑制的条数。
+type RateLimiter struct {
+	next  Notifier
+	limit RateLimit
+
+	mu         sync.Mutex
+	sent       []time.Time          // 窗口内已放行的时间点
+	lastSent   map[string]time.Time // 去重 key -> 上次放行时间
+	suppressed int
+}
+
+// WithRateLimit 为 n 加上限流和去重。
+//
+// 用法：
+//
+//	n := notify.WithRateLimit(notify.NewDingTalk(url, secret), notify.RateLimit{
+//	    Max:   20,
+//	    Dedup: 10 * time.Minute,
+//	})
+func WithRateLimit(n Notifier, limit RateLimit) *RateLimiter {
+	if limit.Window <= 0 {
+		limit.Window = time.Minute
+	}
+	return &RateLimiter{next: n, limit: limit, lastSent: make(map[string]time.Time)}
+}
+
+// Notify 实现 Notifier 接口。
+func (r *RateLimiter) Notify(ctx context.Context, msg *Message) error {
+	suppressed, ok := r.allow(msg, time.Now())
+	if !ok {
+		return ErrRateLimited
+	}
+	if suppressed > 0 {
+		cp := *msg
+		cp.Content += fmt.Sprintf("\n\n（此前因限流已抑制 %d 条通知）", suppressed)
+		msg = &cp
+	}
+	return r.next.Notify(ctx, msg)
+}
+
+// Suppressed 返回当前累计、尚未随消息报告的抑制条数。
+func (r *RateLimiter) Suppressed() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.suppressed
+}
+
+// allow 判断是否放行；放行时返回并清零此前的抑制条数。
+func (r *RateLimiter) allow(msg *Message, now time.Time) (suppressed int, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := msg.Level + "\x00" + msg.Title
+	if r.limit.Dedup > 0 {
+		if last, seen := r.lastSent[key]; seen && now.Sub(last) < r.limit.Dedup {
+			r.suppressed++
+			return 0, false
+		}
+	}
+
+	if r.limit.Max > 0 {
+		cutoff := now.Add(-r.limit.Window)
+		i := 0
+		for i < len(r.sent) && !r.sent[i].After(cutoff) {
+			i++
+		}
+		r.sent = r.sent[i:]
+		if len(r.sent) >= r.limit.Max {
+			r.suppressed++
+			return 0, false
+		}
+		r.sent = append(r.sent, now)
+	}
+
+	if r.limit.Dedup > 0 {
+		if len(r.lastSent) >= dedupPruneSize {
+			for k, t := range r.lastSent {
+				if now.Sub(t) >= r.limit.Dedup {
+					delete(r.lastSent, k)
+				}
+			}
+		}
+		r.lastSent[key] = now
+	}
+
+	suppressed, r.suppressed = r.suppressed, 0
+	return suppressed, true
+}
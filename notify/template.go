@@ -0,0 +1,56 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// Template 消息模板，标题和正文均使用 text/template 语法。
+//
+// 用法：
+//
+//	tpl := notify.MustTemplate(notify.LevelWarn,
+//	    "{{.App}} 内存过高",
+//	    "当前内存 **{{.MemoryMB}} MB**，超过阈值 {{.LimitMB}} MB")
+//	msg, err := tpl.Render(map[string]any{"App": "crawler", "MemoryMB": 2048, "LimitMB": 1024})
+//	err = ding.Notify(ctx, msg)
+type Template struct {
+	level   string
+	title   *template.Template
+	content *template.Template
+}
+
+// NewTemplate 解析标题和正文模板。
+func NewTemplate(level, title, content string) (*Template, error) {
+	tt, err := template.New("title").Parse(title)
+	if err != nil {
+		return nil, fmt.Errorf("notify: 解析标题模板失败: %w", err)
+	}
+	ct, err := template.New("content").Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("notify: 解析正文模板失败: %w", err)
+	}
+	return &Template{level: level, title: tt, content: ct}, nil
+}
+
+// MustTemplate 同 NewTemplate，解析失败时 panic，适合包级变量初始化。
+func MustTemplate(level, title, content string) *Template {
+	t, err := NewTemplate(level, title, content)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Render 使用 data 渲染出一条消息。
+func (t *Template) Render(data any) (*Message, error) {
+	var title, content strings.Builder
+	if err := t.title.Execute(&title, data); err != nil {
+		return nil, fmt.Errorf("notify: 渲染标题失败: %w", err)
+	}
+	if err := t.content.Execute(&content, data); err != nil {
+		return nil, fmt.Errorf("notify: 渲染正文失败: %w", err)
+	}
+	return &Message{Title: title.String(), Content: content.String(), Level: t.level}, nil
+}
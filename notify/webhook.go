@@ -0,0 +1,104 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// defaultHTTPClient 各 HTTP 类发送器共用的客户端。
+var defaultHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// Webhook 将消息以 JSON POST 到任意 HTTP 地址的通用发送器。
+// 请求体为 Message 的 JSON（字段名为小写），非 2xx 响应视为失败。
+type Webhook struct {
+	url     string
+	headers map[string]string
+}
+
+// NewWebhook 创建通用 Webhook 发送器，headers 可用于设置鉴权头。
+func NewWebhook(url string, headers map[string]string) *Webhook {
+	return &Webhook{url: url, headers: headers}
+}
+
+// webhookPayload Webhook 请求体。
+type webhookPayload struct {
+	Title   string            `json:"title"`
+	Content string            `json:"content"`
+	Level   string            `json:"level"`
+	Fields  map[string]string `json:"fields,omitempty"`
+	Time    time.Time         `json:"time"`
+}
+
+// Notify 实现 Notifier 接口。
+func (w *Webhook) Notify(ctx context.Context, msg *Message) error {
+	level := msg.Level
+	if level == "" {
+		level = LevelInfo
+	}
+	payload := webhookPayload{
+		Title:   msg.Title,
+		Content: msg.Content,
+		Level:   level,
+		Fields:  msg.Fields,
+		Time:    msg.time(),
+	}
+	if _, err := postJSON(ctx, w.url, w.headers, payload); err != nil {
+		return fmt.Errorf("notify: webhook: %w", err)
+	}
+	return nil
+}
+
+// postJSON 发送 JSON POST 请求，返回响应体；非 2xx 响应返回错误。
+func postJSON(ctx context.Context, url string, headers map[string]string, payload any) ([]byte, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("序列化请求失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := defaultHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("读取响应失败: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("返回状态码 %d: %s", resp.StatusCode, respBody)
+	}
+	return respBody, nil
+}
+
+// botResponse 钉钉 / 企业微信机器人的响应结构。
+type botResponse struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+// checkBotResponse 检查机器人接口的业务错误码。
+func checkBotResponse(body []byte) error {
+	var r botResponse
+	if err := json.Unmarshal(body, &r); err != nil {
+		return fmt.Errorf("解析响应失败: %w", err)
+	}
+	if r.ErrCode != 0 {
+		return fmt.Errorf("errcode=%d errmsg=%s", r.ErrCode, r.ErrMsg)
+	}
+	return nil
+}
@@ -0,0 +1,253 @@
+package obsutil
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"strings"
+
+	obs "github.com/huaweicloud/huaweicloud-sdk-go-obs/obs"
+)
+
+// ---------------------------------------------------------------------------
+// 客户端加密（AES-GCM 信封加密）
+// ---------------------------------------------------------------------------
+//
+// 每个对象使用随机生成的 256 位数据密钥做 AES-GCM 加密，数据密钥再用主密钥（KeyProvider 提供）
+// 做 AES-GCM 加密后，与 nonce、主密钥 ID 一起保存在对象元数据（x-obs-meta-encryption-*）中。
+// OBS 上只存密文，主密钥不离开客户端；轮换主密钥时旧对象按元数据中的密钥 ID 解密，无需重新加密。
+//
+// 加密需要在内存中缓冲整个对象，不适合超大文件；分段上传（NewStreamingUploader）不支持加密。
+
+// 客户端加密相关的哨兵错误。
+var (
+	ErrNoEncryptionKey        = errors.New("obsutil: 对象已加密但客户端未配置密钥")
+	ErrEncryptionUnsupported  = errors.New("obsutil: 该操作不支持客户端加密")
+	ErrUnknownEncryptionKeyID = errors.New("obsutil: 未知的加密密钥 ID")
+)
+
+// 加密元数据的 key（OBS 以 x-obs-meta-* 头保存，返回时为小写）。
+const (
+	metaEncryptionAlg   = "encryption-alg"
+	metaEncryptionKeyID = "encryption-key-id"
+	metaEncryptionKey   = "encryption-key"
+	metaEncryptionNonce = "encryption-nonce"
+
+	encryptionAlgAESGCM = "AES-256-GCM"
+	dataKeySize         = 32
+)
+
+// KeyProvider 客户端加密的主密钥来源。主密钥长度为 16、24 或 32 字节（AES-128/192/256）。
+type KeyProvider interface {
+	// CurrentKey 返回加密新对象使用的主密钥及其 ID，ID 会明文写入对象元数据。
+	CurrentKey() (id string, key []byte, err error)
+	// Key 按 ID 返回主密钥，用于解密。
+	Key(id string) ([]byte, error)
+}
+
+// KeyRing 基于内存的 KeyProvider，支持主密钥轮换：新对象用 Current 加密，旧对象按 ID 在 Keys 中查找。
+//
+// 用法：
+//
+//	ring := &obsutil.KeyRing{
+//	    Current: "2026-02",
+//	    Keys:    map[string][]byte{"2025-08": oldKey, "2026-02": newKey},
+//	}
+//	secure := obsClient.WithEncryption(ring)
+type KeyRing struct {
+	Current string
+	Keys    map[string][]byte
+}
+
+// CurrentKey 实现 KeyProvider。
+func (r *KeyRing) CurrentKey() (string, []byte, error) {
+	key, err := r.Key(r.Current)
+	return r.Current, key, err
+}
+
+// Key 实现 KeyProvider。
+func (r *KeyRing) Key(id string) ([]byte, error) {
+	key, ok := r.Keys[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownEncryptionKeyID, id)
+	}
+	return key, nil
+}
+
+// StaticKey 返回只有一个主密钥的 KeyProvider。
+func StaticKey(id string, key []byte) KeyProvider {
+	return &KeyRing{Current: id, Keys: map[string][]byte{id: key}}
+}
+
+// WithEncryption 返回启用客户端加密的 ObsClient 副本（共享底层连接，关闭任一个即全部关闭）。
+// 副本的 PutObject / PutBytes / PutString / PutFile / PutObjectFrom 等上传前加密，
+// GetObject / GetObjectTo / DownloadObject 下载后按元数据自动解密；未加密的对象原样返回，便于逐步迁移。
+// kp 为 nil 时返回不加密的副本。
+//
+// 用法：
+//
+//	secure := obsClient.WithEncryption(obsutil.StaticKey("v1", masterKey))
+//	_, err := secure.PutBytes("pii/users.json", data)
+//	plain, err := secure.GetObject("pii/users.json")
+func (oc *ObsClient) WithEncryption(kp KeyProvider) *ObsClient {
+	c := *oc
+	c.keys = kp
+	return &c
+}
+
+// putEncrypted 读取 r 的全部内容，加密后上传。ContentType 按明文检测，加密信息写入元数据。
+func (oc *ObsClient) putEncrypted(key, name string, r io.Reader, o PutOptions) (*obs.PutObjectOutput, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("obsutil: 读取数据失败: %w", err)
+	}
+	if o.ContentType == "" {
+		o.ContentType = DetectContentType(name, data[:min(len(data), sniffLen)])
+	}
+
+	sealed, meta, err := sealEnvelope(oc.keys, data)
+	if err != nil {
+		return nil, err
+	}
+	metadata := make(map[string]string, len(o.Metadata)+len(meta))
+	maps.Copy(metadata, o.Metadata)
+	maps.Copy(metadata, meta)
+	o.Metadata = metadata
+
+	return oc.putObject(key, bytes.NewReader(sealed), o)
+}
+
+// objectBody 返回对象的明文内容：未加密的对象直接返回 Body，已加密的对象读取全部内容后解密。
+func (oc *ObsClient) objectBody(output *obs.GetObjectOutput) (io.Reader, error) {
+	if metaValue(output.Metadata, metaEncryptionAlg) == "" {
+		return output.Body, nil
+	}
+	if oc.keys == nil {
+		return nil, ErrNoEncryptionKey
+	}
+	data, err := io.ReadAll(output.Body)
+	if err != nil {
+		return nil, fmt.Errorf("obsutil: 读取对象内容失败: %w", err)
+	}
+	plain, err := openEnvelope(oc.keys, data, output.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(plain), nil
+}
+
+// sealEnvelope 用随机数据密钥加密 plain，返回密文和需要写入对象元数据的加密信息。
+func sealEnvelope(kp KeyProvider, plain []byte) ([]byte, map[string]string, error) {
+	keyID, masterKey, err := kp.CurrentKey()
+	if err != nil {
+		return nil, nil, fmt.Errorf("obsutil: 获取加密密钥失败: %w", err)
+	}
+
+	dataKey := make([]byte, dataKeySize)
+	if _, err = rand.Read(dataKey); err != nil {
+		return nil, nil, fmt.Errorf("obsutil: 生成数据密钥失败: %w", err)
+	}
+	nonce, sealed, err := gcmSeal(dataKey, plain, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	// 数据密钥以主密钥 ID 作为附加数据加密，防止篡改元数据中的密钥 ID
+	keyNonce, wrappedKey, err := gcmSeal(masterKey, dataKey, []byte(keyID))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	enc := base64.StdEncoding
+	return sealed, map[string]string{
+		metaEncryptionAlg:   encryptionAlgAESGCM,
+		metaEncryptionKeyID: keyID,
+		metaEncryptionKey:   enc.EncodeToString(append(keyNonce, wrappedKey...)),
+		metaEncryptionNonce: enc.EncodeToString(nonce),
+	}, nil
+}
+
+// openEnvelope 按对象元数据中的加密信息解密 sealed。
+func openEnvelope(kp KeyProvider, sealed []byte, meta map[string]string) ([]byte, error) {
+	if alg := metaValue(meta, metaEncryptionAlg); alg != encryptionAlgAESGCM {
+		return nil, fmt.Errorf("obsutil: 不支持的加密算法: %q", alg)
+	}
+	keyID := metaValue(meta, metaEncryptionKeyID)
+	masterKey, err := kp.Key(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("obsutil: 获取解密密钥失败: %w", err)
+	}
+
+	enc := base64.StdEncoding
+	wrapped, err := enc.DecodeString(metaValue(meta, metaEncryptionKey))
+	if err != nil {
+		return nil, fmt.Errorf("obsutil: 加密元数据格式错误: %w", err)
+	}
+	nonce, err := enc.DecodeString(metaValue(meta, metaEncryptionNonce))
+	if err != nil {
+		return nil, fmt.Errorf("obsutil: 加密元数据格式错误: %w", err)
+	}
+
+	dataKey, err := gcmOpen(masterKey, wrapped, []byte(keyID))
+	if err != nil {
+		return nil, fmt.Errorf("obsutil: 解密数据密钥失败（主密钥不匹配？）: %w", err)
+	}
+	plain, err := gcmOpen(dataKey, append(nonce, sealed...), nil)
+	if err != nil {
+		return nil, fmt.Errorf("obsutil: 解密对象失败（内容被篡改？）: %w", err)
+	}
+	return plain, nil
+}
+
+// gcmSeal 用 key 做 AES-GCM 加密，返回随机 nonce 和密文（含认证标签）。
+func gcmSeal(key, plain, additional []byte) (nonce, sealed []byte, err error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce = make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, nil, fmt.Errorf("obsutil: 生成 nonce 失败: %w", err)
+	}
+	return nonce, aead.Seal(nil, nonce, plain, additional), nil
+}
+
+// gcmOpen 解密 nonce 与密文拼接而成的 data。
+func gcmOpen(key, data, additional []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("密文长度不足")
+	}
+	nonce, sealed := data[:aead.NonceSize()], data[aead.NonceSize():]
+	return aead.Open(nil, nonce, sealed, additional)
+}
+
+// newGCM 创建 AES-GCM 实例，key 长度须为 16、24 或 32 字节。
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("obsutil: 无效的加密密钥: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// metaValue 大小写不敏感地读取元数据。
+func metaValue(meta map[string]string, key string) string {
+	if v, ok := meta[key]; ok {
+		return v
+	}
+	for k, v := range meta {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,262 @@
+package obsutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pylemonorg/gotools/workerpool"
+
+	obs "github.com/huaweicloud/huaweicloud-sdk-go-obs/obs"
+)
+
+// ErrMirrorIncomplete 表示同步结束但部分对象复制或删除失败，详情见 MirrorResult.Failed。
+var ErrMirrorIncomplete = errors.New("obsutil: 同步未全部成功")
+
+// MirrorAction 同步过程中对单个对象执行的动作。
+type MirrorAction string
+
+const (
+	MirrorCopy   MirrorAction = "copy"   // 目标端缺失或内容不同，已复制
+	MirrorSkip   MirrorAction = "skip"   // 两端一致，跳过
+	MirrorDelete MirrorAction = "delete" // 目标端多余，已删除
+)
+
+// MirrorOptions 桶间同步选项，nil 时全部使用默认值。
+type MirrorOptions struct {
+	DstPrefix   string                                           // 目标端前缀，为空时与源端前缀相同（key 保持不变）
+	Delete      bool                                             // 删除目标端存在而源端不存在的对象
+	DryRun      bool                                             // 只比较并统计，不实际复制或删除
+	Concurrency int                                              // 并发复制数，默认 8
+	PartSize    int64                                            // 跨区域流式复制时超过该大小的对象按分段上传，默认 64MB
+	Filter      func(key string) bool                            // 只同步返回 true 的源端 key（删除时同样按目标端对应的源 key 过滤）
+	OnObject    func(key string, action MirrorAction, err error) // 每个对象处理完成后回调（并发调用），key 为源端 key
+}
+
+// MirrorResult 桶间同步结果汇总。
+type MirrorResult struct {
+	Scanned     int              // 源端参与比较的对象数
+	Copied      int              // 复制的对象数
+	CopiedBytes int64            // 复制的字节数
+	Skipped     int              // 两端一致跳过的对象数
+	Deleted     int              // 删除的目标端对象数
+	Failed      map[string]error // 失败的 key（源端 key 或待删除的目标端 key）及原因
+	Duration    time.Duration    // 总耗时
+}
+
+// String 返回一行摘要。
+func (r *MirrorResult) String() string {
+	return fmt.Sprintf("扫描 %d，复制 %d（%s），跳过 %d，删除 %d，失败 %d，耗时 %v",
+		r.Scanned, r.Copied, formatSize(r.CopiedBytes), r.Skipped, r.Deleted, len(r.Failed), r.Duration.Round(time.Millisecond))
+}
+
+// Mirror 将 src 桶中 prefix 下的对象同步到 dst 桶：列出两端对象，复制目标端缺失或大小 / ETag 不同的对象，
+// 可选删除目标端多余的对象，返回汇总结果。部分对象失败时返回结果和 ErrMirrorIncomplete。
+//
+// 两端 endpoint 相同时使用服务端复制（不经过本机流量），否则边下载边上传。
+// 分段上传的对象 ETag 带 "-N" 后缀，与普通上传不可比，此时只比较大小。
+//
+// 用法：
+//
+//	result, err := obsutil.Mirror(srcClient, dstClient, "data/2024/", &obsutil.MirrorOptions{
+//	    Delete:      true,
+//	    Concurrency: 16,
+//	})
+//	log.Println(result)
+func Mirror(src, dst *ObsClient, prefix string, opts *MirrorOptions) (*MirrorResult, error) {
+	var o MirrorOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = 8
+	}
+	if o.PartSize <= 0 {
+		o.PartSize = 64 * 1024 * 1024
+	}
+	dstPrefix := prefix
+	if o.DstPrefix != "" {
+		dstPrefix = o.DstPrefix
+	}
+
+	start := time.Now()
+	result := &MirrorResult{Failed: make(map[string]error)}
+
+	srcObjects, err := src.ListAllObjects(prefix, 1000)
+	if err != nil {
+		return nil, fmt.Errorf("obsutil: 列出源端对象失败: %w", err)
+	}
+	dstObjects, err := dst.ListAllObjects(dstPrefix, 1000)
+	if err != nil {
+		return nil, fmt.Errorf("obsutil: 列出目标端对象失败: %w", err)
+	}
+	existing := make(map[string]obs.Content, len(dstObjects))
+	for _, c := range dstObjects {
+		existing[c.Key] = c
+	}
+	toDstKey := func(key string) string { return dstPrefix + strings.TrimPrefix(key, prefix) }
+
+	var mu sync.Mutex
+	report := func(key string, action MirrorAction, size int64, err error) {
+		mu.Lock()
+		switch {
+		case err != nil:
+			result.Failed[key] = err
+		case action == MirrorCopy:
+			result.Copied++
+			result.CopiedBytes += size
+		case action == MirrorSkip:
+			result.Skipped++
+		case action == MirrorDelete:
+			result.Deleted++
+		}
+		mu.Unlock()
+		if o.OnObject != nil {
+			o.OnObject(key, action, err)
+		}
+	}
+
+	sameEndpoint := src.endpoint == dst.endpoint
+	srcKeys := make(map[string]bool, len(srcObjects))
+	pool := workerpool.New(o.Concurrency)
+	for _, c := range srcObjects {
+		if o.Filter != nil && !o.Filter(c.Key) {
+			continue
+		}
+		result.Scanned++
+		dstKey := toDstKey(c.Key)
+		srcKeys[dstKey] = true
+
+		if d, ok := existing[dstKey]; ok && sameContent(c, d) {
+			report(c.Key, MirrorSkip, 0, nil)
+			continue
+		}
+		if o.DryRun {
+			report(c.Key, MirrorCopy, c.Size, nil)
+			continue
+		}
+		pool.Submit(func(_ context.Context) error {
+			err := copyBetween(src, dst, c, dstKey, sameEndpoint, o.PartSize)
+			report(c.Key, MirrorCopy, c.Size, err)
+			return nil
+		})
+	}
+	pool.Wait()
+
+	if o.Delete {
+		var extra []string
+		for _, d := range dstObjects {
+			if srcKeys[d.Key] {
+				continue
+			}
+			if o.Filter != nil && !o.Filter(prefix+strings.TrimPrefix(d.Key, dstPrefix)) {
+				continue
+			}
+			extra = append(extra, d.Key)
+		}
+		if o.DryRun {
+			for _, key := range extra {
+				report(key, MirrorDelete, 0, nil)
+			}
+		} else if len(extra) > 0 {
+			_, failed, _ := dst.DeleteObjects(extra)
+			failedSet := make(map[string]bool, len(failed))
+			for _, key := range failed {
+				failedSet[key] = true
+			}
+			for _, key := range extra {
+				var err error
+				if failedSet[key] {
+					err = errors.New("删除失败")
+				}
+				report(key, MirrorDelete, 0, err)
+			}
+		}
+	}
+
+	result.Duration = time.Since(start)
+	log.Infof("obsutil: 同步 %s/%s -> %s/%s 完成: %s", src.bucket, prefix, dst.bucket, dstPrefix, result)
+	if len(result.Failed) > 0 {
+		return result, fmt.Errorf("%w: %d 个对象失败", ErrMirrorIncomplete, len(result.Failed))
+	}
+	return result, nil
+}
+
+// sameContent 按大小和 ETag 判断两端对象是否一致；任一端为分段上传（ETag 含 "-"）时只比较大小。
+func sameContent(a, b obs.Content) bool {
+	if a.Size != b.Size {
+		return false
+	}
+	ea, eb := strings.Trim(a.ETag, `"`), strings.Trim(b.ETag, `"`)
+	if strings.Contains(ea, "-") || strings.Contains(eb, "-") {
+		return true
+	}
+	return ea == eb
+}
+
+// copyBetween 复制单个对象：同 endpoint 优先服务端复制，失败（如跨账号无权限）时回退为流式复制。
+func copyBetween(src, dst *ObsClient, c obs.Content, dstKey string, sameEndpoint bool, partSize int64) error {
+	if sameEndpoint && c.Size <= maxSinglePutSize {
+		input := &obs.CopyObjectInput{}
+		input.Bucket = dst.bucket
+		input.Key = dstKey
+		input.CopySourceBucket = src.bucket
+		input.CopySourceKey = c.Key
+		trace := dst.startTrace("CopyObject", input.Key)
+		output, err := dst.client.CopyObject(input)
+		trace(output, err)
+		if err == nil {
+			return nil
+		}
+		log.Debugf("obsutil: 服务端复制 [%s] 失败，改为流式复制: %v", c.Key, err)
+	}
+	return streamCopy(src, dst, c, dstKey, partSize)
+}
+
+// streamCopy 边下载边上传，保留 Content-Type 等 HTTP 头和自定义元数据；大对象按 partSize 分段上传。
+// 按原始字节复制：已加密的对象连同加密元数据原样复制，不重复加密。
+func streamCopy(src, dst *ObsClient, c obs.Content, dstKey string, partSize int64) error {
+	dst = dst.WithEncryption(nil)
+	getInput := &obs.GetObjectInput{}
+	getInput.Bucket = src.bucket
+	getInput.Key = c.Key
+	trace := src.startTrace("GetObject", getInput.Key)
+	output, err := src.client.GetObject(getInput)
+	trace(output, err)
+	if err != nil {
+		return fmt.Errorf("下载失败: %w", err)
+	}
+	defer output.Body.Close()
+
+	opts := PutOptions{
+		ContentType:        output.ContentType,
+		CacheControl:       output.CacheControl,
+		ContentDisposition: output.ContentDisposition,
+		ContentEncoding:    output.ContentEncoding,
+		Metadata:           output.Metadata,
+	}
+	if output.ContentLength <= partSize {
+		if err := dst.PutObjectFrom(dstKey, output.Body, output.ContentLength, opts); err != nil {
+			return fmt.Errorf("上传失败: %w", err)
+		}
+		return nil
+	}
+	return dst.putMultipartFrom(dstKey, output.Body, partSize, opts)
+}
+
+// formatSize 将字节数格式化为人类可读的字符串。
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.2f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -2,16 +2,22 @@ package obsutil
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"os"
+	"path"
 	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/pylemonorg/gotools/envutil"
 	"github.com/pylemonorg/gotools/logger"
+	"github.com/pylemonorg/gotools/retry"
+	"github.com/pylemonorg/gotools/workerpool"
 
 	obs "github.com/huaweicloud/huaweicloud-sdk-go-obs/obs"
 )
@@ -20,13 +26,26 @@ import (
 var (
 	ErrObsNilConfig        = errors.New("obsutil: 配置不能为 nil")
 	ErrObjectAlreadyExists = errors.New("obsutil: 对象已存在")
+	ErrPreconditionFailed  = errors.New("obsutil: 对象已被修改，条件写入未执行")
+	ErrObjectNotFound      = errors.New("obsutil: 对象不存在")
 )
 
+// 流式上传的大小限制。
+const (
+	maxSinglePutSize = 5 * 1024 * 1024 * 1024 // 单次 PutObject / CopyObject 的最大对象大小
+	streamPartSize   = 64 * 1024 * 1024       // 未知长度的流按该大小分段上传
+)
+
+// log obsutil 模块日志，可通过 logger.SetModuleLevel("obsutil", ...) 单独控制级别。
+var log = logger.Module("obsutil")
+
 // ObsClient 封装了华为云 OBS 客户端，提供便捷的对象存储操作。
 type ObsClient struct {
 	client   *obs.ObsClient
 	bucket   string
 	endpoint string
+	keys     KeyProvider // 非 nil 时启用客户端加密，见 WithEncryption
+	trace    TraceHook   // 非 nil 时追踪每次 API 调用，见 WithTrace
 }
 
 // ObsConfig 定义 OBS 连接所需的参数。
@@ -72,7 +91,7 @@ func NewObsClient(cfg *ObsConfig) (*ObsClient, error) {
 		return nil, fmt.Errorf("obsutil: 创建客户端失败: %w", err)
 	}
 
-	logger.Infof("obsutil: 连接成功 bucket=%s endpoint=%s", cfg.Bucket, cfg.Endpoint)
+	log.Infof("obsutil: 连接成功 bucket=%s endpoint=%s", cfg.Bucket, cfg.Endpoint)
 	return &ObsClient{
 		client:   client,
 		bucket:   cfg.Bucket,
@@ -83,20 +102,20 @@ func NewObsClient(cfg *ObsConfig) (*ObsClient, error) {
 // NewObsClientFromEnv 从环境变量创建 ObsClient 实例。
 // 读取的环境变量：OBS_AK / AccessKeyID、OBS_SK / SecretAccessKey、OBS_ENDPOINT、OBS_BUCKET。
 func NewObsClientFromEnv() (*ObsClient, error) {
-	ak := os.Getenv("OBS_AK")
+	ak := envutil.Get("OBS_AK", "")
 	if ak == "" {
-		ak = os.Getenv("AccessKeyID")
+		ak = envutil.Get("AccessKeyID", "")
 	}
-	sk := os.Getenv("OBS_SK")
+	sk := envutil.Get("OBS_SK", "")
 	if sk == "" {
-		sk = os.Getenv("SecretAccessKey")
+		sk = envutil.Get("SecretAccessKey", "")
 	}
 
 	return NewObsClient(&ObsConfig{
 		AccessKeyID:     ak,
 		SecretAccessKey: sk,
-		Endpoint:        os.Getenv("OBS_ENDPOINT"),
-		Bucket:          os.Getenv("OBS_BUCKET"),
+		Endpoint:        envutil.Get("OBS_ENDPOINT", ""),
+		Bucket:          envutil.Get("OBS_BUCKET", ""),
 	})
 }
 
@@ -104,10 +123,25 @@ func NewObsClientFromEnv() (*ObsClient, error) {
 func (oc *ObsClient) Close() {
 	if oc.client != nil {
 		oc.client.Close()
-		logger.Infof("obsutil: 客户端连接已关闭")
+		log.Infof("obsutil: 客户端连接已关闭")
 	}
 }
 
+// HealthCheck 通过 HeadBucket 检查存储桶可访问（凭证有效、桶存在），用于就绪探针（实现 healthcheck.Checker）。
+// SDK 不支持单次请求的 context，ctx 仅在调用前检查；超时由 SDK 的连接 / 读写超时控制。
+func (oc *ObsClient) HealthCheck(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	trace := oc.startTrace("HeadBucket", "")
+	output, err := oc.client.HeadBucket(oc.bucket)
+	trace(output, err)
+	if err != nil {
+		return fmt.Errorf("obsutil: 健康检查失败（桶 %s）: %w", oc.bucket, err)
+	}
+	return nil
+}
+
 // GetBucket 返回存储桶名称。
 func (oc *ObsClient) GetBucket() string { return oc.bucket }
 
@@ -121,8 +155,8 @@ func (oc *ObsClient) GetClient() *obs.ObsClient { return oc.client }
 // 上传操作
 // ---------------------------------------------------------------------------
 
-// PutFile 上传本地文件到 OBS。
-func (oc *ObsClient) PutFile(key, filePath string) (*obs.PutObjectOutput, error) {
+// PutFile 上传本地文件到 OBS。未指定 ContentType 时按文件扩展名或内容自动识别。
+func (oc *ObsClient) PutFile(key, filePath string, opts ...PutOptions) (*obs.PutObjectOutput, error) {
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
 		return nil, fmt.Errorf("obsutil: 文件不存在: %s", filePath)
 	}
@@ -133,48 +167,165 @@ func (oc *ObsClient) PutFile(key, filePath string) (*obs.PutObjectOutput, error)
 	}
 	defer fd.Close()
 
+	name := filePath
+	if path.Ext(filePath) == "" {
+		name = key
+	}
+	if oc.keys != nil {
+		return oc.putEncrypted(key, name, fd, firstPutOptions(opts))
+	}
+
+	head, body, err := sniffReader(fd)
+	if err != nil {
+		return nil, fmt.Errorf("obsutil: 读取文件失败: %w", err)
+	}
+
 	input := &obs.PutObjectInput{}
 	input.Bucket = oc.bucket
 	input.Key = key
-	input.Body = fd
+	input.Body = body
+	firstPutOptions(opts).apply(&input.ObjectOperationInput, &input.HttpHeader, name, head)
 
+	trace := oc.startTrace("PutObject", input.Key)
 	output, err := oc.client.PutObject(input)
+	trace(output, err)
 	if err != nil {
 		return nil, fmt.Errorf("obsutil: 上传文件失败: %w", err)
 	}
 	return output, nil
 }
 
-// PutObject 上传 io.Reader 数据流到 OBS。
-func (oc *ObsClient) PutObject(key string, body io.Reader) (*obs.PutObjectOutput, error) {
+// PutObject 上传 io.Reader 数据流到 OBS。未指定 ContentType 时按 key 扩展名或内容自动识别。
+func (oc *ObsClient) PutObject(key string, body io.Reader, opts ...PutOptions) (*obs.PutObjectOutput, error) {
+	if oc.keys != nil {
+		return oc.putEncrypted(key, key, body, firstPutOptions(opts))
+	}
+	return oc.putObject(key, body, firstPutOptions(opts))
+}
+
+// putObject 上传数据流（不加密）。
+func (oc *ObsClient) putObject(key string, body io.Reader, o PutOptions) (*obs.PutObjectOutput, error) {
+	head, body, err := sniffReader(body)
+	if err != nil {
+		return nil, fmt.Errorf("obsutil: 读取数据失败: %w", err)
+	}
+
 	input := &obs.PutObjectInput{}
 	input.Bucket = oc.bucket
 	input.Key = key
 	input.Body = body
+	o.apply(&input.ObjectOperationInput, &input.HttpHeader, key, head)
 
+	trace := oc.startTrace("PutObject", input.Key)
 	output, err := oc.client.PutObject(input)
+	trace(output, err)
 	if err != nil {
 		return nil, fmt.Errorf("obsutil: 上传对象失败: %w", err)
 	}
 	return output, nil
 }
 
+// PutObjectFrom 将 r 中的数据流式上传到 OBS，不在内存中缓冲整个对象，适合从文件、网络连接直接转存。
+// size 为数据长度提示：已知且不超过 5GB 时单次上传并设置 Content-Length；
+// size < 0（未知）或超过 5GB 时按 64MB 分段上传，此时每次只缓冲一个分段。
+// 启用客户端加密时会缓冲整个对象后加密上传。
+//
+// 用法：
+//
+//	resp, _ := http.Get(url)
+//	defer resp.Body.Close()
+//	err := obsClient.PutObjectFrom("mirror/file.bin", resp.Body, resp.ContentLength)
+func (oc *ObsClient) PutObjectFrom(key string, r io.Reader, size int64, opts ...PutOptions) error {
+	o := firstPutOptions(opts)
+	if oc.keys != nil {
+		if size >= 0 {
+			r = io.LimitReader(r, size)
+		}
+		_, err := oc.putEncrypted(key, key, r, o)
+		return err
+	}
+	if size >= 0 && size <= maxSinglePutSize {
+		head, body, err := sniffReader(io.LimitReader(r, size))
+		if err != nil {
+			return fmt.Errorf("obsutil: 读取数据失败: %w", err)
+		}
+
+		input := &obs.PutObjectInput{}
+		input.Bucket = oc.bucket
+		input.Key = key
+		input.ContentLength = size
+		input.Body = body
+		o.apply(&input.ObjectOperationInput, &input.HttpHeader, key, head)
+
+		trace := oc.startTrace("PutObject", input.Key)
+		output, err := oc.client.PutObject(input)
+		trace(output, err)
+		if err != nil {
+			return fmt.Errorf("obsutil: 上传对象失败: %w", err)
+		}
+		return nil
+	}
+	return oc.putMultipartFrom(key, r, streamPartSize, o)
+}
+
+// putMultipartFrom 按 partSize 读取 r 并分段上传，r 为空时上传空对象。
+func (oc *ObsClient) putMultipartFrom(key string, r io.Reader, partSize int64, o PutOptions) error {
+	buf := make([]byte, partSize)
+	n, err := io.ReadFull(r, buf)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		// 不足一个分段，直接普通上传
+		_, perr := oc.PutBytes(key, buf[:n], o)
+		return perr
+	}
+	if err != nil {
+		return fmt.Errorf("obsutil: 读取数据失败: %w", err)
+	}
+
+	if o.ContentType == "" {
+		o.ContentType = DetectContentType(key, buf[:min(n, sniffLen)])
+	}
+	uploader, err := oc.NewStreamingUploader(key, o)
+	if err != nil {
+		return err
+	}
+	for {
+		if err := uploader.WritePart(buf[:n]); err != nil {
+			uploader.Abort()
+			return err
+		}
+		// WritePart 返回时分段已上传完成，可复用缓冲区；
+		// ErrUnexpectedEOF 表示读到最后一个不足 partSize 的分段，下一轮写入后以 EOF 结束
+		n, err = io.ReadFull(r, buf)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			uploader.Abort()
+			return fmt.Errorf("obsutil: 读取数据失败: %w", err)
+		}
+	}
+	return uploader.Complete()
+}
+
 // PutBytes 上传字节数组到 OBS。
-func (oc *ObsClient) PutBytes(key string, data []byte) (*obs.PutObjectOutput, error) {
-	return oc.PutObject(key, bytes.NewReader(data))
+func (oc *ObsClient) PutBytes(key string, data []byte, opts ...PutOptions) (*obs.PutObjectOutput, error) {
+	return oc.PutObject(key, bytes.NewReader(data), opts...)
 }
 
 // PutString 上传字符串到 OBS。
-func (oc *ObsClient) PutString(key, content string) (*obs.PutObjectOutput, error) {
-	return oc.PutBytes(key, []byte(content))
+func (oc *ObsClient) PutString(key, content string, opts ...PutOptions) (*obs.PutObjectOutput, error) {
+	return oc.PutBytes(key, []byte(content), opts...)
 }
 
 // putObjectTimeout 单次 PutObject 超时时间。
 const putObjectTimeout = 30 * time.Second
 
+// errPutObjectTimeout 单次 PutObject 超时（总是可重试）。
+var errPutObjectTimeout = fmt.Errorf("obsutil: PutObject 超时(%v)", putObjectTimeout)
+
 // PutBytesWithRetry 上传字节数组到 OBS，带重试和单次超时（应对 503/限流/无响应）。
-// maxRetries <= 0 时默认 3 次，retryDelay <= 0 时默认 1s，之后指数退避。
-func (oc *ObsClient) PutBytesWithRetry(key string, data []byte, maxRetries int, retryDelay time.Duration) (*obs.PutObjectOutput, error) {
+// maxRetries <= 0 时默认 3 次，retryDelay <= 0 时默认 1s，之后指数退避（单次最长 30s，见 retry.Do）。
+func (oc *ObsClient) PutBytesWithRetry(key string, data []byte, maxRetries int, retryDelay time.Duration, opts ...PutOptions) (*obs.PutObjectOutput, error) {
 	if maxRetries <= 0 {
 		maxRetries = 3
 	}
@@ -182,21 +333,24 @@ func (oc *ObsClient) PutBytesWithRetry(key string, data []byte, maxRetries int,
 		retryDelay = time.Second
 	}
 
-	var lastErr error
-	for attempt := 0; attempt <= maxRetries; attempt++ {
-		if attempt > 0 {
-			delay := retryDelay * time.Duration(1<<uint(attempt-1))
-			logger.Warnf("obsutil: PutBytes 重试 (%d/%d) key=%s", attempt, maxRetries, key)
-			time.Sleep(delay)
-		}
-
+	policy := &retry.Policy{
+		MaxAttempts:     maxRetries + 1,
+		InitialInterval: retryDelay,
+		RetryIf: func(err error) bool {
+			return errors.Is(err, errPutObjectTimeout) || isRetryable(err)
+		},
+		OnRetry: func(attempt int, err error, delay time.Duration) {
+			log.Warnf("obsutil: PutBytes 重试 (%d/%d) key=%s: %v", attempt, maxRetries, key, err)
+		},
+	}
+	out, err := retry.DoValue(context.Background(), policy, func(ctx context.Context) (*obs.PutObjectOutput, error) {
 		type putResult struct {
 			out *obs.PutObjectOutput
 			err error
 		}
 		ch := make(chan putResult, 1)
 		go func() {
-			out, err := oc.PutObject(key, bytes.NewReader(data))
+			out, err := oc.PutObject(key, bytes.NewReader(data), opts...)
 			select {
 			case ch <- putResult{out, err}:
 			default:
@@ -205,34 +359,26 @@ func (oc *ObsClient) PutBytesWithRetry(key string, data []byte, maxRetries int,
 
 		select {
 		case r := <-ch:
-			if r.err == nil {
-				return r.out, nil
-			}
-			lastErr = r.err
-			if attempt < maxRetries && isRetryable(r.err) {
-				continue
-			}
-			return nil, r.err
+			return r.out, r.err
 		case <-time.After(putObjectTimeout):
-			lastErr = fmt.Errorf("obsutil: PutObject 超时(%v)", putObjectTimeout)
-			if attempt < maxRetries {
-				continue
-			}
-			return nil, lastErr
+			return nil, errPutObjectTimeout
 		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("obsutil: 上传失败: %w", err)
 	}
-	return nil, fmt.Errorf("obsutil: 上传失败（已重试 %d 次）: %w", maxRetries, lastErr)
+	return out, nil
 }
 
 // PutStringWithRetry 上传字符串到 OBS，带重试机制。
 // maxRetries <= 0 时默认 3 次，retryDelay <= 0 时默认 2s，之后指数退避。
-func (oc *ObsClient) PutStringWithRetry(key, content string, maxRetries int, retryDelay time.Duration) (*obs.PutObjectOutput, error) {
-	return oc.PutBytesWithRetry(key, []byte(content), maxRetries, retryDelay)
+func (oc *ObsClient) PutStringWithRetry(key, content string, maxRetries int, retryDelay time.Duration, opts ...PutOptions) (*obs.PutObjectOutput, error) {
+	return oc.PutBytesWithRetry(key, []byte(content), maxRetries, retryDelay, opts...)
 }
 
 // PutBytesMultipart 分段并行上传字节数组（适用于大文件）。
-// partSize <= 0 时默认 50MB，concurrency <= 0 时默认 5。
-func (oc *ObsClient) PutBytesMultipart(key string, data []byte, partSize int64, concurrency int) error {
+// partSize <= 0 时默认 50MB，concurrency <= 0 时默认 5。启用客户端加密时改为单次上传。
+func (oc *ObsClient) PutBytesMultipart(key string, data []byte, partSize int64, concurrency int, opts ...PutOptions) error {
 	dataLen := int64(len(data))
 	if partSize <= 0 {
 		partSize = 50 * 1024 * 1024
@@ -241,9 +387,9 @@ func (oc *ObsClient) PutBytesMultipart(key string, data []byte, partSize int64,
 		concurrency = 5
 	}
 
-	// 小文件直接普通上传
-	if dataLen <= partSize {
-		_, err := oc.PutBytes(key, data)
+	// 小文件或需要加密时直接普通上传
+	if dataLen <= partSize || oc.keys != nil {
+		_, err := oc.PutBytes(key, data, opts...)
 		return err
 	}
 
@@ -251,84 +397,59 @@ func (oc *ObsClient) PutBytesMultipart(key string, data []byte, partSize int64,
 	initInput := &obs.InitiateMultipartUploadInput{}
 	initInput.Bucket = oc.bucket
 	initInput.Key = key
+	firstPutOptions(opts).apply(&initInput.ObjectOperationInput, &initInput.HttpHeader, key, data[:min(len(data), sniffLen)])
 
+	trace := oc.startTrace("InitiateMultipartUpload", initInput.Key)
 	initOutput, err := oc.client.InitiateMultipartUpload(initInput)
+	trace(initOutput, err)
 	if err != nil {
 		return fmt.Errorf("obsutil: 初始化分段上传失败: %w", err)
 	}
 	uploadID := initOutput.UploadId
 	partCount := int((dataLen + partSize - 1) / partSize)
 
-	// 并发上传分段
-	type partResult struct {
-		PartNumber int
-		ETag       string
-		Err        error
-	}
-	results := make(chan partResult, partCount)
-	var wg sync.WaitGroup
-	sem := make(chan struct{}, concurrency)
-
-	for i := 0; i < partCount; i++ {
-		wg.Add(1)
-		go func(partNum int) {
-			defer wg.Done()
-			sem <- struct{}{}
-			defer func() { <-sem }()
-
-			start := int64(partNum) * partSize
-			end := start + partSize
-			if end > dataLen {
-				end = dataLen
-			}
-
-			uploadInput := &obs.UploadPartInput{}
-			uploadInput.Bucket = oc.bucket
-			uploadInput.Key = key
-			uploadInput.UploadId = uploadID
-			uploadInput.PartNumber = partNum + 1
-			uploadInput.Body = bytes.NewReader(data[start:end])
-
-			output, err := oc.client.UploadPart(uploadInput)
-			if err != nil {
-				results <- partResult{PartNumber: partNum + 1, Err: err}
-				return
-			}
-			results <- partResult{PartNumber: partNum + 1, ETag: output.ETag}
-		}(i)
+	// 并发上传分段，任一分段失败即停止提交剩余分段
+	partNums := make([]int, partCount)
+	for i := range partNums {
+		partNums[i] = i + 1
 	}
-	go func() { wg.Wait(); close(results) }()
+	parts, err := workerpool.Map(context.Background(), concurrency, partNums, func(_ context.Context, partNum int) (obs.Part, error) {
+		start := int64(partNum-1) * partSize
+		end := min(start+partSize, dataLen)
 
-	// 收集结果
-	parts := make([]obs.Part, 0, partCount)
-	var uploadErr error
-	for r := range results {
-		if r.Err != nil {
-			uploadErr = r.Err
-			continue
+		uploadInput := &obs.UploadPartInput{}
+		uploadInput.Bucket = oc.bucket
+		uploadInput.Key = key
+		uploadInput.UploadId = uploadID
+		uploadInput.PartNumber = partNum
+		uploadInput.Body = bytes.NewReader(data[start:end])
+
+		trace := oc.startTrace("UploadPart", uploadInput.Key)
+		output, err := oc.client.UploadPart(uploadInput)
+		trace(output, err)
+		if err != nil {
+			return obs.Part{}, err
 		}
-		parts = append(parts, obs.Part{PartNumber: r.PartNumber, ETag: r.ETag})
-	}
+		return obs.Part{PartNumber: partNum, ETag: output.ETag}, nil
+	}, workerpool.WithMode(workerpool.FirstError))
 
 	// 有失败则取消
-	if uploadErr != nil || len(parts) != partCount {
+	if err != nil {
 		oc.abortMultipartUpload(key, uploadID)
-		if uploadErr != nil {
-			return fmt.Errorf("obsutil: 分段上传失败: %w", uploadErr)
-		}
-		return fmt.Errorf("obsutil: 分段不完整: 期望 %d 个，实际 %d 个", partCount, len(parts))
+		return fmt.Errorf("obsutil: 分段上传失败: %w", err)
 	}
 
-	// 按分段号排序并完成上传
-	sort.Slice(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber })
-
+	// 结果已按分段号排序，直接完成上传
 	completeInput := &obs.CompleteMultipartUploadInput{}
 	completeInput.Bucket = oc.bucket
 	completeInput.Key = key
 	completeInput.UploadId = uploadID
 	completeInput.Parts = parts
 
-	if _, err = oc.client.CompleteMultipartUpload(completeInput); err != nil {
+	trace = oc.startTrace("CompleteMultipartUpload", completeInput.Key)
+	completeOutput, err := oc.client.CompleteMultipartUpload(completeInput)
+	trace(completeOutput, err)
+	if err != nil {
 		return fmt.Errorf("obsutil: 完成分段上传失败: %w", err)
 	}
 	return nil
@@ -340,7 +461,9 @@ func (oc *ObsClient) abortMultipartUpload(key, uploadID string) {
 	abortInput.Bucket = oc.bucket
 	abortInput.Key = key
 	abortInput.UploadId = uploadID
-	oc.client.AbortMultipartUpload(abortInput)
+	trace := oc.startTrace("AbortMultipartUpload", abortInput.Key)
+	output, err := oc.client.AbortMultipartUpload(abortInput)
+	trace(output, err)
 }
 
 // ---------------------------------------------------------------------------
@@ -353,38 +476,81 @@ func (oc *ObsClient) GetObject(key string) ([]byte, error) {
 	input.Bucket = oc.bucket
 	input.Key = key
 
+	trace := oc.startTrace("GetObject", input.Key)
 	output, err := oc.client.GetObject(input)
+	trace(output, err)
 	if err != nil {
 		return nil, fmt.Errorf("obsutil: 下载对象失败: %w", err)
 	}
 	defer output.Body.Close()
 
-	data, err := io.ReadAll(output.Body)
+	body, err := oc.objectBody(output)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(body)
 	if err != nil {
 		return nil, fmt.Errorf("obsutil: 读取对象内容失败: %w", err)
 	}
 	return data, nil
 }
 
+// GetObjectTo 将对象内容流式写入 w（文件、网络连接、HTTP 响应等），返回写入的字节数。
+//
+// 用法：
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//	    if _, err := obsClient.GetObjectTo(w, "reports/2024.pdf"); err != nil { ... }
+//	}
+func (oc *ObsClient) GetObjectTo(w io.Writer, key string) (int64, error) {
+	input := &obs.GetObjectInput{}
+	input.Bucket = oc.bucket
+	input.Key = key
+
+	trace := oc.startTrace("GetObject", input.Key)
+	output, err := oc.client.GetObject(input)
+	trace(output, err)
+	if err != nil {
+		return 0, fmt.Errorf("obsutil: 下载对象失败: %w", err)
+	}
+	defer output.Body.Close()
+
+	body, err := oc.objectBody(output)
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(w, body)
+	if err != nil {
+		return n, fmt.Errorf("obsutil: 写入对象内容失败: %w", err)
+	}
+	return n, nil
+}
+
 // DownloadObject 下载对象到本地文件。
 func (oc *ObsClient) DownloadObject(key, filePath string) error {
 	input := &obs.GetObjectInput{}
 	input.Bucket = oc.bucket
 	input.Key = key
 
+	trace := oc.startTrace("GetObject", input.Key)
 	output, err := oc.client.GetObject(input)
+	trace(output, err)
 	if err != nil {
 		return fmt.Errorf("obsutil: 下载对象失败: %w", err)
 	}
 	defer output.Body.Close()
 
+	body, err := oc.objectBody(output)
+	if err != nil {
+		return err
+	}
 	file, err := os.Create(filePath)
 	if err != nil {
 		return fmt.Errorf("obsutil: 创建本地文件失败: %w", err)
 	}
 	defer file.Close()
 
-	if _, err = io.Copy(file, output.Body); err != nil {
+	if _, err = io.Copy(file, body); err != nil {
 		return fmt.Errorf("obsutil: 写入本地文件失败: %w", err)
 	}
 	return nil
@@ -396,7 +562,10 @@ func (oc *ObsClient) ObjectExists(key string) (bool, error) {
 	input.Bucket = oc.bucket
 	input.Key = key
 
-	if _, err := oc.client.HeadObject(input); err != nil {
+	trace := oc.startTrace("HeadObject", input.Key)
+	output, err := oc.client.HeadObject(input)
+	trace(output, err)
+	if err != nil {
 		if obsErr, ok := err.(obs.ObsError); ok && obsErr.StatusCode == 404 {
 			return false, nil
 		}
@@ -415,29 +584,163 @@ func (oc *ObsClient) ObjectExistsWithRetry(key string, maxRetries int, retryDela
 		retryDelay = time.Second
 	}
 
+	policy := &retry.Policy{
+		MaxAttempts:     maxRetries + 1,
+		InitialInterval: retryDelay,
+		RetryIf:         isRetryable,
+	}
+	exists, err := oc.headExists(context.Background(), key, policy)
+	if err != nil {
+		return false, fmt.Errorf("obsutil: 检查对象是否存在失败: %w", err)
+	}
+	return exists, nil
+}
+
+// headExists 按 policy 重试 HeadObject，404 返回 false,nil。
+func (oc *ObsClient) headExists(ctx context.Context, key string, policy *retry.Policy) (bool, error) {
 	input := &obs.HeadObjectInput{}
 	input.Bucket = oc.bucket
 	input.Key = key
 
-	var lastErr error
-	for attempt := 0; attempt <= maxRetries; attempt++ {
-		if attempt > 0 {
-			time.Sleep(retryDelay * time.Duration(1<<uint(attempt-1)))
-		}
-
-		if _, err := oc.client.HeadObject(input); err == nil {
+	return retry.DoValue(ctx, policy, func(ctx context.Context) (bool, error) {
+		trace := oc.startTrace("HeadObject", input.Key)
+		output, err := oc.client.HeadObject(input)
+		trace(output, err)
+		if err == nil {
 			return true, nil
-		} else if obsErr, ok := err.(obs.ObsError); ok && obsErr.StatusCode == 404 {
+		}
+		if obsErr, ok := err.(obs.ObsError); ok && obsErr.StatusCode == 404 {
 			return false, nil
-		} else {
-			lastErr = err
-			if attempt < maxRetries && isRetryable(err) {
-				continue
+		}
+		return false, err
+	})
+}
+
+// defaultExistsConcurrency ObjectsExist 的默认并发数。
+const defaultExistsConcurrency = 32
+
+// ObjectsExist 以最多 concurrency 个并发（<= 0 时默认 32）检查多个对象是否存在，用于批量核对预期产出。
+// 每个 key 的检查遇到限流、网络抖动时按指数退避重试（最多 4 次）；重复的 key 只检查一次。
+// 返回的 map 包含所有检查成功的 key；部分 key 重试后仍失败时，这些 key 不在 map 中，
+// 并返回汇总了失败数量与首个错误的 error。
+//
+// 用法：
+//
+//	exists, err := obsClient.ObjectsExist(expectedKeys, 64)
+//	for _, k := range expectedKeys {
+//	    if ok, checked := exists[k]; checked && !ok {
+//	        missing = append(missing, k)
+//	    }
+//	}
+func (oc *ObsClient) ObjectsExist(keys []string, concurrency int) (map[string]bool, error) {
+	if concurrency <= 0 {
+		concurrency = defaultExistsConcurrency
+	}
+	policy := &retry.Policy{
+		InitialInterval: 500 * time.Millisecond,
+		Jitter:          0.2,
+		RetryIf:         isRetryable,
+	}
+
+	result := make(map[string]bool, len(keys))
+	var (
+		mu        sync.Mutex
+		failed    int
+		firstKey  string
+		firstErr  error
+		submitted = make(map[string]bool, len(keys))
+	)
+	pool := workerpool.New(concurrency)
+	for _, key := range keys {
+		if submitted[key] {
+			continue
+		}
+		submitted[key] = true
+		pool.Submit(func(ctx context.Context) error {
+			exists, err := oc.headExists(ctx, key, policy)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if failed == 0 {
+					firstKey, firstErr = key, err
+				}
+				failed++
+				return nil
+			}
+			result[key] = exists
+			return nil
+		})
+	}
+	if err := pool.Wait(); err != nil {
+		return result, fmt.Errorf("obsutil: 批量检查对象是否存在失败: %w", err)
+	}
+	if failed > 0 {
+		return result, fmt.Errorf("obsutil: %d/%d 个对象检查失败，首个 [%s]: %w", failed, len(submitted), firstKey, firstErr)
+	}
+	return result, nil
+}
+
+// 等待对象出现的轮询参数。
+const (
+	defaultWaitInterval = time.Second
+	maxWaitInterval     = 30 * time.Second
+)
+
+// errObjectPending 轮询时对象尚不存在（WaitForObject 内部使用，总是继续轮询）。
+var errObjectPending = errors.New("obsutil: 对象尚不存在")
+
+// WaitForObject 轮询 HeadObject 直到对象出现或 ctx 结束，用于等待上游任务的输出对象。
+// 轮询间隔从 pollInterval（<= 0 时默认 1s）开始按 1.5 倍退避，最长 30s（pollInterval 更大时以其为准）；
+// 限流、网络抖动等可重试错误继续轮询，其他错误（如 403）立即返回。
+// ctx 结束时对象仍未出现，返回的错误同时满足 errors.Is(err, ErrObjectNotFound) 和 errors.Is(err, ctx.Err())。
+//
+// 用法：
+//
+//	ctx, cancel := context.WithTimeout(ctx, 2*time.Hour)
+//	defer cancel()
+//	if err := obsClient.WaitForObject(ctx, "etl/2024-05-01/_SUCCESS", 10*time.Second); err != nil {
+//	    return err // errors.Is(err, obsutil.ErrObjectNotFound) 表示上游超时未产出
+//	}
+func (oc *ObsClient) WaitForObject(ctx context.Context, key string, pollInterval time.Duration) error {
+	if pollInterval <= 0 {
+		pollInterval = defaultWaitInterval
+	}
+	input := &obs.HeadObjectInput{}
+	input.Bucket = oc.bucket
+	input.Key = key
+
+	start := time.Now()
+	policy := &retry.Policy{
+		MaxAttempts:     math.MaxInt,
+		InitialInterval: pollInterval,
+		MaxInterval:     max(pollInterval, maxWaitInterval),
+		Multiplier:      1.5,
+		Jitter:          0.1,
+		RetryIf: func(err error) bool {
+			return errors.Is(err, errObjectPending) || isRetryable(err)
+		},
+		OnRetry: func(attempt int, err error, delay time.Duration) {
+			if !errors.Is(err, errObjectPending) {
+				log.Warnf("obsutil: 等待对象 [%s] 时检查失败（第 %d 次），%v 后重试: %v", key, attempt, delay, err)
 			}
-			return false, fmt.Errorf("obsutil: 检查对象是否存在失败: %w", lastErr)
+		},
+	}
+	err := retry.Do(ctx, policy, func(ctx context.Context) error {
+		trace := oc.startTrace("HeadObject", input.Key)
+		output, err := oc.client.HeadObject(input)
+		trace(output, err)
+		if obsErr, ok := err.(obs.ObsError); ok && obsErr.StatusCode == 404 {
+			return errObjectPending
 		}
+		return err
+	})
+	if err == nil {
+		return nil
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return fmt.Errorf("%w（已等待 %v）: %s: %w", ErrObjectNotFound, time.Since(start).Round(time.Second), key, ctxErr)
 	}
-	return false, fmt.Errorf("obsutil: 检查对象是否存在失败: %w", lastErr)
+	return fmt.Errorf("obsutil: 等待对象 [%s] 失败: %w", key, err)
 }
 
 // ---------------------------------------------------------------------------
@@ -450,7 +753,9 @@ func (oc *ObsClient) DeleteObject(key string) (*obs.DeleteObjectOutput, error) {
 	input.Bucket = oc.bucket
 	input.Key = key
 
+	trace := oc.startTrace("DeleteObject", input.Key)
 	output, err := oc.client.DeleteObject(input)
+	trace(output, err)
 	if err != nil {
 		return nil, fmt.Errorf("obsutil: 删除对象失败: %w", err)
 	}
@@ -498,7 +803,9 @@ func (oc *ObsClient) deleteObjectsBatch(keys []string) (int, []string, error) {
 	input.Objects = objects
 	input.Quiet = false
 
+	trace := oc.startTrace("DeleteObjects", "")
 	output, err := oc.client.DeleteObjects(input)
+	trace(output, err)
 	if err != nil {
 		return 0, keys, fmt.Errorf("obsutil: 批量删除失败: %w", err)
 	}
@@ -518,7 +825,10 @@ func (oc *ObsClient) CopyObject(srcKey, destKey string) error {
 	input.CopySourceBucket = oc.bucket
 	input.CopySourceKey = srcKey
 
-	if _, err := oc.client.CopyObject(input); err != nil {
+	trace := oc.startTrace("CopyObject", input.Key)
+	output, err := oc.client.CopyObject(input)
+	trace(output, err)
+	if err != nil {
 		return fmt.Errorf("obsutil: 复制对象失败: %w", err)
 	}
 	return nil
@@ -539,7 +849,9 @@ func (oc *ObsClient) ListObjects(prefix string, maxKeys int) ([]obs.Content, err
 	input.Prefix = prefix
 	input.MaxKeys = maxKeys
 
+	trace := oc.startTrace("ListObjects", input.Prefix)
 	output, err := oc.client.ListObjects(input)
+	trace(output, err)
 	if err != nil {
 		return nil, fmt.Errorf("obsutil: 列出对象失败: %w", err)
 	}
@@ -559,7 +871,9 @@ func (oc *ObsClient) ListObjectsWithMarker(prefix string, maxKeys int, marker st
 	input.MaxKeys = maxKeys
 	input.Marker = marker
 
+	trace := oc.startTrace("ListObjects", input.Prefix)
 	output, err := oc.client.ListObjects(input)
+	trace(output, err)
 	if err != nil {
 		return nil, "", fmt.Errorf("obsutil: 列出对象失败: %w", err)
 	}
@@ -603,7 +917,9 @@ func (oc *ObsClient) ListAllObjectsWithProgress(prefix string, maxKeysPerPage in
 		input.MaxKeys = pageSize
 		input.Marker = marker
 
+		trace := oc.startTrace("ListObjects", input.Prefix)
 		output, err := oc.client.ListObjects(input)
+		trace(output, err)
 		if err != nil {
 			return nil, fmt.Errorf("obsutil: 列出对象失败: %w", err)
 		}
@@ -669,6 +985,81 @@ func (oc *ObsClient) TryCreateLock(key string, lockContent []byte, instanceID st
 	return true, nil
 }
 
+// GetObjectWithETag 下载对象内容并返回其 ETag，配合 PutBytesIfMatch 实现"读取-比较-写入"。
+// 对象不存在时返回的错误满足 StatusCode(err) == 404。
+func (oc *ObsClient) GetObjectWithETag(key string) ([]byte, string, error) {
+	input := &obs.GetObjectInput{}
+	input.Bucket = oc.bucket
+	input.Key = key
+
+	trace := oc.startTrace("GetObject", input.Key)
+	output, err := oc.client.GetObject(input)
+	trace(output, err)
+	if err != nil {
+		return nil, "", fmt.Errorf("obsutil: 下载对象失败: %w", err)
+	}
+	defer output.Body.Close()
+
+	body, err := oc.objectBody(output)
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, "", fmt.Errorf("obsutil: 读取对象内容失败: %w", err)
+	}
+	return data, output.ETag, nil
+}
+
+// PutBytesIfAbsent 仅当对象不存在时写入（If-None-Match: *），返回新对象的 ETag；
+// 对象已存在时返回 ErrObjectAlreadyExists。
+// 条件写入用于锁、元数据等小对象，不做客户端加密；要求桶支持条件请求。
+func (oc *ObsClient) PutBytesIfAbsent(key string, data []byte) (string, error) {
+	etag, err := oc.putConditional(key, data, obs.HEADER_IF_NONE_MATCH, "*")
+	if errors.Is(err, ErrPreconditionFailed) {
+		return "", ErrObjectAlreadyExists
+	}
+	return etag, err
+}
+
+// PutBytesIfMatch 仅当对象当前的 ETag 等于 etag 时覆盖写入（If-Match），返回新的 ETag；
+// 对象已被他人修改或删除时返回 ErrPreconditionFailed。其余说明同 PutBytesIfAbsent。
+//
+// 用法：
+//
+//	data, etag, _ := obsClient.GetObjectWithETag("state.json")
+//	newData := update(data)
+//	if _, err := obsClient.PutBytesIfMatch("state.json", newData, etag); errors.Is(err, obsutil.ErrPreconditionFailed) {
+//	    // 并发修改，重新读取后重试
+//	}
+func (oc *ObsClient) PutBytesIfMatch(key string, data []byte, etag string) (string, error) {
+	if etag == "" {
+		return "", ErrPreconditionFailed
+	}
+	return oc.putConditional(key, data, obs.HEADER_IF_MATCH, etag)
+}
+
+// putConditional 带条件头上传，412（条件不满足）和 409（并发条件写入冲突）返回 ErrPreconditionFailed。
+func (oc *ObsClient) putConditional(key string, data []byte, header, value string) (string, error) {
+	input := &obs.PutObjectInput{}
+	input.Bucket = oc.bucket
+	input.Key = key
+	input.ContentLength = int64(len(data))
+	input.Body = bytes.NewReader(data)
+	PutOptions{}.apply(&input.ObjectOperationInput, &input.HttpHeader, key, data[:min(len(data), sniffLen)])
+
+	trace := oc.startTrace("PutObject", input.Key)
+	output, err := oc.client.PutObject(input, obs.WithCustomHeader(header, value))
+	trace(output, err)
+	if err != nil {
+		if code := StatusCode(err); code == 412 || code == 409 {
+			return "", ErrPreconditionFailed
+		}
+		return "", fmt.Errorf("obsutil: 条件上传对象失败: %w", err)
+	}
+	return output.ETag, nil
+}
+
 // ---------------------------------------------------------------------------
 // 流式分段上传
 // ---------------------------------------------------------------------------
@@ -692,13 +1083,20 @@ type StreamingUploader struct {
 	completed  bool
 }
 
-// NewStreamingUploader 创建流式上传器。
-func (oc *ObsClient) NewStreamingUploader(key string) (*StreamingUploader, error) {
+// NewStreamingUploader 创建流式上传器。创建时尚无数据，未指定 ContentType 时只按 key 扩展名识别。
+// 启用客户端加密时返回 ErrEncryptionUnsupported。
+func (oc *ObsClient) NewStreamingUploader(key string, opts ...PutOptions) (*StreamingUploader, error) {
+	if oc.keys != nil {
+		return nil, ErrEncryptionUnsupported
+	}
 	initInput := &obs.InitiateMultipartUploadInput{}
 	initInput.Bucket = oc.bucket
 	initInput.Key = key
+	firstPutOptions(opts).apply(&initInput.ObjectOperationInput, &initInput.HttpHeader, key, nil)
 
+	trace := oc.startTrace("InitiateMultipartUpload", initInput.Key)
 	initOutput, err := oc.client.InitiateMultipartUpload(initInput)
+	trace(initOutput, err)
 	if err != nil {
 		return nil, fmt.Errorf("obsutil: 初始化分段上传失败: %w", err)
 	}
@@ -744,7 +1142,9 @@ func (su *StreamingUploader) WritePart(data []byte) error {
 		uploadInput.PartNumber = partNum
 		uploadInput.Body = bytes.NewReader(data)
 
+		trace := su.obsClient.startTrace("UploadPart", uploadInput.Key)
 		output, err := su.obsClient.client.UploadPart(uploadInput)
+		trace(output, err)
 		if err != nil {
 			lastErr = err
 			continue
@@ -784,7 +1184,10 @@ func (su *StreamingUploader) Complete() error {
 	completeInput.UploadId = su.uploadID
 	completeInput.Parts = su.parts
 
-	if _, err := su.obsClient.client.CompleteMultipartUpload(completeInput); err != nil {
+	trace := su.obsClient.startTrace("CompleteMultipartUpload", completeInput.Key)
+	output, err := su.obsClient.client.CompleteMultipartUpload(completeInput)
+	trace(output, err)
+	if err != nil {
 		return fmt.Errorf("obsutil: 完成分段上传失败: %w", err)
 	}
 	su.completed = true
@@ -805,8 +1208,11 @@ func (su *StreamingUploader) Abort() error {
 	abortInput.Key = su.key
 	abortInput.UploadId = su.uploadID
 
-	if _, err := su.obsClient.client.AbortMultipartUpload(abortInput); err != nil {
-		logger.Warnf("obsutil: 取消分段上传失败（OBS 会自动清理）: %v", err)
+	trace := su.obsClient.startTrace("AbortMultipartUpload", abortInput.Key)
+	output, err := su.obsClient.client.AbortMultipartUpload(abortInput)
+	trace(output, err)
+	if err != nil {
+		log.Warnf("obsutil: 取消分段上传失败（OBS 会自动清理）: %v", err)
 	}
 	su.aborted = true
 	return nil
@@ -840,15 +1246,8 @@ var retryableKeywords = []string{
 }
 
 // isRetryable 判断错误是否可重试（限流/临时不可用/网络问题）。
-func isRetryable(err error) bool {
-	if err == nil {
-		return false
-	}
-	s := err.Error()
-	for _, kw := range retryableKeywords {
-		if strings.Contains(s, kw) {
-			return true
-		}
-	}
-	return false
-}
+var isRetryable = retry.ErrorContains(retryableKeywords...)
+
+// IsRetryable 判断 OBS 操作返回的错误是否为可重试的临时错误（限流/临时不可用/网络问题），
+// 供自行实现重试的调用方作为 retry.Policy.RetryIf 使用。
+func IsRetryable(err error) bool { return isRetryable(err) }
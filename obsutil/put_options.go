@@ -0,0 +1,106 @@
+package obsutil
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	obs "github.com/huaweicloud/huaweicloud-sdk-go-obs/obs"
+)
+
+// sniffLen 内容嗅探读取的字节数（同 http.DetectContentType）。
+const sniffLen = 512
+
+// PutOptions 上传选项，所有上传方法都接受可选的 PutOptions（只使用第一个）。
+//
+// 用法：
+//
+//	obsClient.PutFile("static/logo.png", "./logo.png") // 自动识别为 image/png
+//	obsClient.PutBytes("exports/report.csv", data, obsutil.PutOptions{
+//	    ContentDisposition: `attachment; filename="report.csv"`,
+//	    Metadata:           map[string]string{"source": "daily-job"},
+//	})
+type PutOptions struct {
+	ContentType        string            // 为空时自动检测，见 DetectContentType
+	CacheControl       string            // 如 "max-age=86400"
+	ContentDisposition string            // 如 `attachment; filename="a.csv"`
+	ContentEncoding    string            // 如 "gzip"（内容已压缩时设置）
+	Metadata           map[string]string // 自定义元数据，以 x-obs-meta-* 头保存
+}
+
+// DetectContentType 推断对象的 MIME 类型：先按 name（key 或文件路径）的扩展名，
+// 无法识别时嗅探内容开头 head（最多 512 字节），都无法判断时返回 application/octet-stream。
+func DetectContentType(name string, head []byte) string {
+	ext := strings.ToLower(path.Ext(name))
+	if ext != "" {
+		if t, ok := extraMIMETypes[ext]; ok {
+			return t
+		}
+		if t := mime.TypeByExtension(ext); t != "" {
+			return t
+		}
+	}
+	if len(head) > 0 {
+		return http.DetectContentType(head)
+	}
+	return "application/octet-stream"
+}
+
+// extraMIMETypes 补充 mime 包内置表（依赖系统 mime.types）中可能缺失的常见类型。
+var extraMIMETypes = map[string]string{
+	".txt":   "text/plain; charset=utf-8",
+	".log":   "text/plain; charset=utf-8",
+	".md":    "text/markdown; charset=utf-8",
+	".csv":   "text/csv; charset=utf-8",
+	".jsonl": "application/x-ndjson",
+	".yaml":  "application/yaml",
+	".yml":   "application/yaml",
+	".ico":   "image/x-icon",
+	".mp3":   "audio/mpeg",
+	".mp4":   "video/mp4",
+	".webm":  "video/webm",
+	".woff":  "font/woff",
+	".woff2": "font/woff2",
+	".zip":   "application/zip",
+	".gz":    "application/gzip",
+	".tar":   "application/x-tar",
+	".zst":   "application/zstd",
+	".xlsx":  "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	".docx":  "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+}
+
+// firstPutOptions 返回可变参数中的第一个选项，未传时返回零值。
+func firstPutOptions(opts []PutOptions) PutOptions {
+	if len(opts) == 0 {
+		return PutOptions{}
+	}
+	return opts[0]
+}
+
+// apply 将选项写入请求，ContentType 为空时按 name 和 head 自动检测。
+func (o PutOptions) apply(op *obs.ObjectOperationInput, h *obs.HttpHeader, name string, head []byte) {
+	h.ContentType = o.ContentType
+	if h.ContentType == "" {
+		h.ContentType = DetectContentType(name, head)
+	}
+	h.CacheControl = o.CacheControl
+	h.ContentDisposition = o.ContentDisposition
+	h.ContentEncoding = o.ContentEncoding
+	if len(o.Metadata) > 0 {
+		op.Metadata = o.Metadata
+	}
+}
+
+// sniffReader 读取 r 开头最多 512 字节用于类型检测，返回读取的内容和等价于原始 r 的新 Reader。
+func sniffReader(r io.Reader) ([]byte, io.Reader, error) {
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, nil, err
+	}
+	head = head[:n]
+	return head, io.MultiReader(bytes.NewReader(head), r), nil
+}
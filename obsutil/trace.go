@@ -0,0 +1,133 @@
+package obsutil
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	obs "github.com/huaweicloud/huaweicloud-sdk-go-obs/obs"
+)
+
+// ---------------------------------------------------------------------------
+// API 调用追踪
+// ---------------------------------------------------------------------------
+
+// OpTrace 一次 OBS API 调用的追踪信息。
+type OpTrace struct {
+	Op         string        // API 名称，如 "PutObject"、"GetObject"、"UploadPart"
+	Bucket     string        // 存储桶
+	Key        string        // 对象 key（ListObjects 为前缀，DeleteObjects 为空）
+	Duration   time.Duration // 调用耗时
+	StatusCode int           // HTTP 状态码，未收到响应（网络错误等）时为 0
+	RequestID  string        // 服务端返回的 x-obs-request-id，提交工单时提供给云厂商
+	Err        error         // 调用失败时的错误
+}
+
+// String 返回单行描述，如 "PutObject bucket=b key=a/b.json status=200 request_id=0000018... 35ms"。
+func (t OpTrace) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s bucket=%s", t.Op, t.Bucket)
+	if t.Key != "" {
+		fmt.Fprintf(&sb, " key=%s", t.Key)
+	}
+	if t.StatusCode != 0 {
+		fmt.Fprintf(&sb, " status=%d", t.StatusCode)
+	}
+	if t.RequestID != "" {
+		fmt.Fprintf(&sb, " request_id=%s", t.RequestID)
+	}
+	fmt.Fprintf(&sb, " %v", t.Duration.Round(time.Millisecond))
+	if t.Err != nil {
+		fmt.Fprintf(&sb, " err=%v", t.Err)
+	}
+	return sb.String()
+}
+
+// TraceHook 接收每次 OBS API 调用的追踪信息，在调用返回后同步执行，应避免阻塞。
+type TraceHook func(OpTrace)
+
+// LogTrace 以 debug 级别记录追踪信息的 TraceHook，可通过 logger.SetModuleLevel("obsutil", logger.LevelDebug) 打开。
+func LogTrace(t OpTrace) {
+	log.Debugf("obsutil: %s", t)
+}
+
+// WithTrace 返回对每次 OBS API 调用执行 hook 的 ObsClient 副本（共享底层连接），hook 为 nil 时关闭追踪。
+// 失败的调用同样会记录状态码与 request ID，便于与云厂商工单关联。
+//
+// 用法：
+//
+//	obsClient = obsClient.WithTrace(obsutil.LogTrace)
+//
+//	// 或接入自定义的指标 / 追踪系统
+//	obsClient = obsClient.WithTrace(func(t obsutil.OpTrace) {
+//	    metrics.ObserveOBS(t.Op, t.StatusCode, t.Duration)
+//	})
+func (oc *ObsClient) WithTrace(hook TraceHook) *ObsClient {
+	c := *oc
+	c.trace = hook
+	return &c
+}
+
+// startTrace 开始记录一次 API 调用，返回在调用结束后传入响应与错误的函数；未启用追踪时返回空操作。
+func (oc *ObsClient) startTrace(op, key string) func(out any, err error) {
+	if oc.trace == nil {
+		return func(any, error) {}
+	}
+	start := time.Now()
+	return func(out any, err error) {
+		t := OpTrace{Op: op, Bucket: oc.bucket, Key: key, Duration: time.Since(start), Err: err}
+		t.StatusCode, t.RequestID = responseInfo(out)
+		var obsErr obs.ObsError
+		if errors.As(err, &obsErr) {
+			t.StatusCode, t.RequestID = obsErr.StatusCode, obsErr.RequestId
+		}
+		oc.trace(t)
+	}
+}
+
+// responseInfo 从 SDK 的输出结构（均内嵌 obs.BaseModel）中取出状态码与 request ID。
+func responseInfo(out any) (int, string) {
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return 0, ""
+	}
+	v = v.Elem()
+	if v.Kind() != reflect.Struct {
+		return 0, ""
+	}
+	var code int
+	var id string
+	if f := v.FieldByName("StatusCode"); f.IsValid() && f.CanInt() {
+		code = int(f.Int())
+	}
+	if f := v.FieldByName("RequestId"); f.IsValid() && f.Kind() == reflect.String {
+		id = f.String()
+	}
+	return code, id
+}
+
+// RequestID 返回错误链中 OBS 服务端错误的 request ID，不是服务端错误（如网络错误）时返回空串。
+//
+// 用法：
+//
+//	if _, err := obsClient.PutBytes(key, data); err != nil {
+//	    log.Errorf("上传失败 request_id=%s: %v", obsutil.RequestID(err), err)
+//	}
+func RequestID(err error) string {
+	var obsErr obs.ObsError
+	if errors.As(err, &obsErr) {
+		return obsErr.RequestId
+	}
+	return ""
+}
+
+// StatusCode 返回错误链中 OBS 服务端错误的 HTTP 状态码，不是服务端错误时返回 0。
+func StatusCode(err error) int {
+	var obsErr obs.ObsError
+	if errors.As(err, &obsErr) {
+		return obsErr.StatusCode
+	}
+	return 0
+}
@@ -0,0 +1,345 @@
+// Package pathkey 按模板构建与解析对象存储 key，统一 "{app}/{yyyy}/{mm}/{dd}/{hash}.jsonl" 这类按日期分区的目录布局，
+// 避免各处手工拼接字符串导致的格式不一致（缺少补零、多余斜杠等）。
+package pathkey
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxKeyLen 对象 key 的最大字节数（OBS / S3 的限制）。
+const MaxKeyLen = 1024
+
+// pathkey 相关的哨兵错误。
+var (
+	ErrInvalidKey      = errors.New("pathkey: 非法的对象 key")
+	ErrInvalidTemplate = errors.New("pathkey: 非法的模板")
+	ErrMissingVar      = errors.New("pathkey: 缺少模板变量")
+	ErrNoMatch         = errors.New("pathkey: key 与模板不匹配")
+)
+
+// 日期占位符及其在 key 中的位数。
+var dateFields = map[string]int{
+	"yyyy": 4, // 年
+	"mm":   2, // 月
+	"dd":   2, // 日
+	"hh":   2, // 时（24 小时制）
+}
+
+// Validate 校验对象 key：非空、不超过 MaxKeyLen 字节、合法 UTF-8、不含控制字符、
+// 不以 "/" 开头、不含空目录（"//"）以及 "." / ".." 段。key 可以以 "/" 结尾（表示目录前缀）。
+func Validate(key string) error {
+	switch {
+	case key == "":
+		return fmt.Errorf("%w: 为空", ErrInvalidKey)
+	case len(key) > MaxKeyLen:
+		return fmt.Errorf("%w: 长度 %d 超过 %d 字节", ErrInvalidKey, len(key), MaxKeyLen)
+	case !utf8.ValidString(key):
+		return fmt.Errorf("%w: 不是合法的 UTF-8", ErrInvalidKey)
+	case strings.HasPrefix(key, "/"):
+		return fmt.Errorf("%w: 不能以 / 开头: %q", ErrInvalidKey, key)
+	}
+	if strings.ContainsFunc(key, unicode.IsControl) {
+		return fmt.Errorf("%w: 含控制字符: %q", ErrInvalidKey, key)
+	}
+	segs := strings.Split(strings.TrimSuffix(key, "/"), "/")
+	for _, seg := range segs {
+		switch seg {
+		case "":
+			return fmt.Errorf("%w: 含空目录: %q", ErrInvalidKey, key)
+		case ".", "..":
+			return fmt.Errorf("%w: 含 %q 段: %q", ErrInvalidKey, seg, key)
+		}
+	}
+	return nil
+}
+
+// Join 用 "/" 连接各段（去除各段首尾多余的 "/"，忽略空段）并校验结果。
+//
+// 用法：
+//
+//	key, err := pathkey.Join(prefix, "exports", name+".csv")
+func Join(elems ...string) (string, error) {
+	parts := make([]string, 0, len(elems))
+	for _, e := range elems {
+		if e = strings.Trim(e, "/"); e != "" {
+			parts = append(parts, e)
+		}
+	}
+	key := strings.Join(parts, "/")
+	if err := Validate(key); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// ---------------------------------------------------------------------------
+// 模板
+// ---------------------------------------------------------------------------
+
+// segment 模板的一段：字面量或占位符。
+type segment struct {
+	lit  string
+	name string // 占位符名，字面量时为空
+}
+
+// Template 对象 key 模板，由字面量和 {name} 占位符组成，创建后只读，并发安全。
+// {yyyy} {mm} {dd} {hh} 为日期占位符，按 Template 的时区（默认 UTC）格式化并补零；其余占位符为变量，
+// 取值不能为空、不能含 "/"。同一变量在模板中只能出现一次。
+//
+// 用法：
+//
+//	var eventKey = pathkey.MustParse("{app}/{yyyy}/{mm}/{dd}/{hash}.jsonl")
+//
+//	key, err := eventKey.Build(time.Now(), map[string]string{"app": "billing", "hash": sum})
+//	// billing/2024/05/01/3f2a....jsonl
+//
+//	p, err := eventKey.Extract(key)
+//	// p.Time = 2024-05-01 00:00:00 UTC, p.Vars["app"] = "billing"
+//
+//	prefix := eventKey.Prefix(day, map[string]string{"app": "billing"}) // billing/2024/05/01/，用于按天列举
+type Template struct {
+	raw  string
+	segs []segment
+	re   *regexp.Regexp
+	loc  *time.Location
+}
+
+// Parse 解析模板。模板本身须满足 Validate 的规则（占位符替换后判断），占位符名只能包含 ASCII 字母、数字和下划线，
+// 相邻的两个占位符之间必须有字面量（否则无法解析回来）。
+func Parse(tmpl string) (*Template, error) {
+	var segs []segment
+	seen := map[string]bool{}
+	rest := tmpl
+	for rest != "" {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			segs = append(segs, segment{lit: rest})
+			break
+		}
+		if open > 0 {
+			segs = append(segs, segment{lit: rest[:open]})
+		}
+		end := strings.IndexByte(rest[open:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("%w: 占位符未闭合: %q", ErrInvalidTemplate, tmpl)
+		}
+		name := rest[open+1 : open+end]
+		if !validName(name) {
+			return nil, fmt.Errorf("%w: 占位符名 %q 不合法", ErrInvalidTemplate, name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w: 占位符 {%s} 重复", ErrInvalidTemplate, name)
+		}
+		seen[name] = true
+		if n := len(segs); n > 0 && segs[n-1].name != "" {
+			return nil, fmt.Errorf("%w: 占位符 {%s} 与 {%s} 之间缺少分隔符", ErrInvalidTemplate, segs[n-1].name, name)
+		}
+		segs = append(segs, segment{name: name})
+		rest = rest[open+end+1:]
+	}
+	for _, s := range segs {
+		if strings.ContainsRune(s.lit, '}') {
+			return nil, fmt.Errorf("%w: 多余的 }: %q", ErrInvalidTemplate, tmpl)
+		}
+	}
+
+	// 用示例值代入后按 key 规则校验模板的字面量部分
+	var sample strings.Builder
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	for _, s := range segs {
+		if s.name == "" {
+			sample.WriteString(s.lit)
+			pattern.WriteString(regexp.QuoteMeta(s.lit))
+			continue
+		}
+		if n, ok := dateFields[s.name]; ok {
+			sample.WriteString(strings.Repeat("0", n))
+			fmt.Fprintf(&pattern, `(?P<%s>\d{%d})`, s.name, n)
+		} else {
+			sample.WriteString("x")
+			fmt.Fprintf(&pattern, `(?P<%s>[^/]+?)`, s.name)
+		}
+	}
+	pattern.WriteString("$")
+	if err := Validate(sample.String()); err != nil {
+		return nil, fmt.Errorf("%w: %q: %w", ErrInvalidTemplate, tmpl, err)
+	}
+	return &Template{raw: tmpl, segs: segs, re: regexp.MustCompile(pattern.String()), loc: time.UTC}, nil
+}
+
+// MustParse 同 Parse，模板不合法时 panic，用于包级变量初始化。
+func MustParse(tmpl string) *Template {
+	t, err := Parse(tmpl)
+	if err != nil {
+		panic(err.Error())
+	}
+	return t
+}
+
+// validName 占位符名只能包含 ASCII 字母、数字和下划线（同正则命名分组的规则）。
+func validName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if r != '_' && (r > unicode.MaxASCII || !unicode.IsLetter(r) && !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// String 返回模板原文。
+func (t *Template) String() string { return t.raw }
+
+// In 返回使用 loc 时区格式化 / 解析日期的模板副本，如按北京时间分区：tmpl.In(timeutil.CST)。
+func (t *Template) In(loc *time.Location) *Template {
+	c := *t
+	if loc == nil {
+		loc = time.UTC
+	}
+	c.loc = loc
+	return &c
+}
+
+// Vars 返回模板中的变量名（不含日期占位符），按出现顺序。
+func (t *Template) Vars() []string {
+	var names []string
+	for _, s := range t.segs {
+		if s.name != "" {
+			if _, ok := dateFields[s.name]; !ok {
+				names = append(names, s.name)
+			}
+		}
+	}
+	return names
+}
+
+// Build 代入日期和变量生成 key 并校验。缺少变量时返回 ErrMissingVar，变量值为空、含 "/" 或结果不合法时返回 ErrInvalidKey。
+func (t *Template) Build(date time.Time, vars map[string]string) (string, error) {
+	date = date.In(t.loc)
+	var b strings.Builder
+	for _, s := range t.segs {
+		if s.name == "" {
+			b.WriteString(s.lit)
+			continue
+		}
+		if _, ok := dateFields[s.name]; ok {
+			b.WriteString(formatDate(date, s.name))
+			continue
+		}
+		v, ok := vars[s.name]
+		if !ok {
+			return "", fmt.Errorf("%w: {%s}", ErrMissingVar, s.name)
+		}
+		if err := validValue(s.name, v); err != nil {
+			return "", err
+		}
+		b.WriteString(v)
+	}
+	key := b.String()
+	if err := Validate(key); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// Prefix 返回从模板开头代入到第一个无法确定的占位符（vars 中没有的变量，或 date 为零值时的日期占位符）之前的部分，
+// 截断到最后一个 "/"，用于 ListObjects 按前缀列举某个分区。全部占位符都能确定时返回完整 key。
+func (t *Template) Prefix(date time.Time, vars map[string]string) string {
+	if !date.IsZero() {
+		date = date.In(t.loc)
+	}
+	var b strings.Builder
+	for _, s := range t.segs {
+		if s.name == "" {
+			b.WriteString(s.lit)
+			continue
+		}
+		v, ok := vars[s.name]
+		if _, isDate := dateFields[s.name]; isDate {
+			v, ok = formatDate(date, s.name), !date.IsZero()
+		}
+		if !ok || validValue(s.name, v) != nil {
+			p := b.String()
+			return p[:strings.LastIndexByte(p, '/')+1]
+		}
+		b.WriteString(v)
+	}
+	return b.String()
+}
+
+// Parts Extract 从 key 中解析出的日期与变量。
+type Parts struct {
+	Time time.Time         // 由日期占位符组成的时间（Template 的时区），缺少的月 / 日取 1；模板不含 {yyyy} 时为零值
+	Vars map[string]string // 变量名到取值
+}
+
+// Extract 按模板解析 key，不匹配（含日期不合法，如 13 月）时返回 ErrNoMatch。
+func (t *Template) Extract(key string) (*Parts, error) {
+	m := t.re.FindStringSubmatch(key)
+	if m == nil {
+		return nil, fmt.Errorf("%w: %q 不符合 %q", ErrNoMatch, key, t.raw)
+	}
+	p := &Parts{Vars: map[string]string{}}
+	date := map[string]int{"mm": 1, "dd": 1}
+	for i, name := range t.re.SubexpNames() {
+		if i == 0 || name == "" {
+			continue
+		}
+		if _, ok := dateFields[name]; ok {
+			date[name], _ = strconv.Atoi(m[i])
+			continue
+		}
+		p.Vars[name] = m[i]
+	}
+	if year, ok := date["yyyy"]; ok {
+		p.Time = time.Date(year, time.Month(date["mm"]), date["dd"], date["hh"], 0, 0, 0, t.loc)
+		// time.Date 会把 13 月、32 日等进位到下一个月 / 年，回读不一致说明 key 中的日期不合法
+		if int(p.Time.Month()) != date["mm"] || p.Time.Day() != date["dd"] || p.Time.Hour() != date["hh"] {
+			return nil, fmt.Errorf("%w: %q 中的日期不合法", ErrNoMatch, key)
+		}
+	}
+	return p, nil
+}
+
+// Match 判断 key 是否符合模板。
+func (t *Template) Match(key string) bool {
+	_, err := t.Extract(key)
+	return err == nil
+}
+
+// formatDate 按日期占位符格式化。
+func formatDate(t time.Time, field string) string {
+	switch field {
+	case "yyyy":
+		return fmt.Sprintf("%04d", t.Year())
+	case "mm":
+		return fmt.Sprintf("%02d", int(t.Month()))
+	case "dd":
+		return fmt.Sprintf("%02d", t.Day())
+	default: // hh
+		return fmt.Sprintf("%02d", t.Hour())
+	}
+}
+
+// validValue 校验变量取值：非空、不含 "/"、不是 "." / ".."。
+func validValue(name, v string) error {
+	switch {
+	case v == "":
+		return fmt.Errorf("%w: 变量 {%s} 为空", ErrInvalidKey, name)
+	case strings.Contains(v, "/"):
+		return fmt.Errorf("%w: 变量 {%s} 含 /: %q", ErrInvalidKey, name, v)
+	case v == "." || v == "..":
+		return fmt.Errorf("%w: 变量 {%s} 不能为 %q", ErrInvalidKey, name, v)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package pathkey
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	valid := []string{"a", "app/2024/05/01/x.jsonl", "dir/", "中文/文件.txt", "a/.hidden"}
+	for _, k := range valid {
+		if err := Validate(k); err != nil {
+			t.Errorf("Validate(%q) = %v, 期望合法", k, err)
+		}
+	}
+	invalid := []string{"", "/a", "a//b", "a/../b", "./a", "a/.", "a\nb", "a\x00", strings.Repeat("x", MaxKeyLen+1), "\xff"}
+	for _, k := range invalid {
+		if err := Validate(k); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Validate(%q) = %v, 期望 ErrInvalidKey", k, err)
+		}
+	}
+}
+
+func TestJoin(t *testing.T) {
+	got, err := Join("exports/", "/2024/", "", "a.csv")
+	if err != nil || got != "exports/2024/a.csv" {
+		t.Errorf("Join = %q, %v", got, err)
+	}
+	if _, err := Join("a", "..", "b"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("期望拒绝 .. 段，实际 %v", err)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	cases := []string{
+		"{app",          // 未闭合
+		"app}/x",        // 多余的 }
+		"{}/x",          // 空名
+		"{a-b}/x",       // 非法字符
+		"{app}/{app}",   // 重复
+		"{yyyy}{mm}",    // 相邻占位符
+		"/{app}/x",      // 以 / 开头
+		"{app}//{yyyy}", // 空目录
+		"{app}/../x",    // .. 段
+	}
+	for _, tmpl := range cases {
+		if _, err := Parse(tmpl); !errors.Is(err, ErrInvalidTemplate) {
+			t.Errorf("Parse(%q) = %v, 期望 ErrInvalidTemplate", tmpl, err)
+		}
+	}
+}
+
+func TestBuildExtract(t *testing.T) {
+	tmpl := MustParse("{app}/{yyyy}/{mm}/{dd}/{hash}.jsonl")
+	date := time.Date(2024, 5, 1, 23, 30, 0, 0, time.UTC)
+
+	key, err := tmpl.Build(date, map[string]string{"app": "billing", "hash": "3f2a"})
+	if err != nil || key != "billing/2024/05/01/3f2a.jsonl" {
+		t.Fatalf("Build = %q, %v", key, err)
+	}
+
+	p, err := tmpl.Extract(key)
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if !p.Time.Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Time = %v", p.Time)
+	}
+	if p.Vars["app"] != "billing" || p.Vars["hash"] != "3f2a" {
+		t.Errorf("Vars = %v", p.Vars)
+	}
+
+	if _, err := tmpl.Build(date, map[string]string{"app": "billing"}); !errors.Is(err, ErrMissingVar) {
+		t.Errorf("缺少变量期望 ErrMissingVar，实际 %v", err)
+	}
+	for _, bad := range []string{"", "a/b", ".."} {
+		if _, err := tmpl.Build(date, map[string]string{"app": bad, "hash": "x"}); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("变量 %q 期望 ErrInvalidKey，实际 %v", bad, err)
+		}
+	}
+
+	for _, k := range []string{
+		"billing/2024/5/01/x.jsonl",    // 未补零
+		"billing/2024/05/01/x.json",    // 扩展名不同
+		"billing/2024/13/01/x.jsonl",   // 非法月份
+		"billing/2024/02/30/x.jsonl",   // 非法日期
+		"billing/x/2024/05/01/x.jsonl", // 多一层目录
+		"billing/2024/05/01/a/b.jsonl", // 变量含 /
+	} {
+		if tmpl.Match(k) {
+			t.Errorf("Match(%q) 期望 false", k)
+		}
+	}
+}
+
+func TestTemplateIn(t *testing.T) {
+	cst := time.FixedZone("CST", 8*3600)
+	hourly := MustParse("logs/{yyyy}-{mm}-{dd}/{hh}.log").In(cst)
+	date := time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC) // 北京时间 5 月 2 日 4 点
+	key, err := hourly.Build(date, nil)
+	if err != nil || key != "logs/2024-05-02/04.log" {
+		t.Fatalf("Build = %q, %v", key, err)
+	}
+	p, err := hourly.Extract(key)
+	if err != nil || !p.Time.Equal(date) {
+		t.Errorf("Extract = %v, %v, 期望 %v", p, err, date)
+	}
+}
+
+func TestPrefix(t *testing.T) {
+	tmpl := MustParse("{app}/{yyyy}/{mm}/{dd}/{hash}.jsonl")
+	date := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		date time.Time
+		vars map[string]string
+		want string
+	}{
+		{date, map[string]string{"app": "billing"}, "billing/2024/05/01/"},
+		{time.Time{}, map[string]string{"app": "billing"}, "billing/"},
+		{date, nil, ""},
+		{date, map[string]string{"app": "billing", "hash": "x"}, "billing/2024/05/01/x.jsonl"},
+	}
+	for _, tc := range cases {
+		if got := tmpl.Prefix(tc.date, tc.vars); got != tc.want {
+			t.Errorf("Prefix(%v, %v) = %q, 期望 %q", tc.date, tc.vars, got, tc.want)
+		}
+	}
+
+	if got := MustParse("raw/{app}-{yyyy}.csv").Prefix(date, nil); got != "raw/" {
+		t.Errorf("Prefix 期望截断到最后一个 /，实际 %q", got)
+	}
+}
+
+func TestVars(t *testing.T) {
+	got := MustParse("{app}/{yyyy}/{region}/{hash}.jsonl").Vars()
+	if strings.Join(got, ",") != "app,region,hash" {
+		t.Errorf("Vars = %v", got)
+	}
+}
@@ -24,3 +24,68 @@ func Deref[T any](p *T) T {
 	}
 	return *p
 }
+
+// DerefOr 解引用指针，p 为 nil 时返回 fallback。
+//
+// 用法：
+//
+//	ptr.DerefOr(cfg.Timeout, 30) // nil 时返回 30
+func DerefOr[T any](p *T, fallback T) T {
+	if p == nil {
+		return fallback
+	}
+	return *p
+}
+
+// ToSlice 将 []T 转换为 []*T，每个指针指向独立的副本。s 为 nil 时返回 nil。
+func ToSlice[T any](s []T) []*T {
+	if s == nil {
+		return nil
+	}
+	out := make([]*T, len(s))
+	for i := range s {
+		v := s[i]
+		out[i] = &v
+	}
+	return out
+}
+
+// DerefSlice 将 []*T 转换为 []T，nil 元素转换为 T 的零值。s 为 nil 时返回 nil。
+//
+// 用法：
+//
+//	names := ptr.DerefSlice(resp.Names) // SDK 返回的 []*string
+func DerefSlice[T any](s []*T) []T {
+	if s == nil {
+		return nil
+	}
+	out := make([]T, len(s))
+	for i, p := range s {
+		out[i] = Deref(p)
+	}
+	return out
+}
+
+// ToMap 将 map[K]V 转换为 map[K]*V，每个指针指向独立的副本。m 为 nil 时返回 nil。
+func ToMap[K comparable, V any](m map[K]V) map[K]*V {
+	if m == nil {
+		return nil
+	}
+	out := make(map[K]*V, len(m))
+	for k, v := range m {
+		out[k] = &v
+	}
+	return out
+}
+
+// DerefMap 将 map[K]*V 转换为 map[K]V，nil 值转换为 V 的零值。m 为 nil 时返回 nil。
+func DerefMap[K comparable, V any](m map[K]*V) map[K]V {
+	if m == nil {
+		return nil
+	}
+	out := make(map[K]V, len(m))
+	for k, p := range m {
+		out[k] = Deref(p)
+	}
+	return out
+}
@@ -0,0 +1,391 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/pylemonorg/gotools/db"
+	"github.com/pylemonorg/gotools/hashutil"
+	"github.com/pylemonorg/gotools/logger"
+)
+
+// log queue 模块日志，可通过 logger.SetModuleLevel("queue", ...) 单独控制级别。
+var log = logger.Module("queue")
+
+// 队列相关的哨兵错误。
+var (
+	ErrNilClient    = errors.New("queue: Redis 客户端不能为 nil")
+	ErrEmptyName    = errors.New("queue: 队列名不能为空")
+	ErrEmptyJobType = errors.New("queue: 任务类型不能为空")
+)
+
+// Job 队列中的一个任务。
+type Job struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	Payload    json.RawMessage `json:"payload,omitempty"`
+	Attempts   int             `json:"attempts"`             // 已失败次数
+	MaxRetries int             `json:"max_retries"`          // 最大重试次数，超过后进入死信队列
+	EnqueuedAt time.Time       `json:"enqueued_at"`          // 首次入队时间
+	LastError  string          `json:"last_error,omitempty"` // 最近一次失败原因
+}
+
+// Decode 将 Payload 反序列化到 v。
+func (j *Job) Decode(v any) error {
+	if err := json.Unmarshal(j.Payload, v); err != nil {
+		return fmt.Errorf("queue: 解析任务 %s 的 payload 失败: %w", j.ID, err)
+	}
+	return nil
+}
+
+// Options 队列配置。零值字段使用默认值，nil 等同于全部默认。
+type Options struct {
+	MaxRetries        int                             // 任务默认最大重试次数，< 0 表示不重试，0 时默认 3
+	VisibilityTimeout time.Duration                   // 任务被取出后未确认的最长时间，超时视为失败，<= 0 时默认 5m
+	PollInterval      time.Duration                   // 队列为空时的轮询间隔及延迟任务的检查间隔，<= 0 时默认 1s
+	Backoff           func(attempt int) time.Duration // 第 attempt 次失败后的重试等待，nil 时从 1s 起指数退避，最长 10m
+	OnDead            func(job *Job)                  // 任务进入死信队列时调用，可用于告警
+}
+
+func (o *Options) withDefaults() Options {
+	var r Options
+	if o != nil {
+		r = *o
+	}
+	if r.MaxRetries == 0 {
+		r.MaxRetries = 3
+	} else if r.MaxRetries < 0 {
+		r.MaxRetries = 0
+	}
+	if r.VisibilityTimeout <= 0 {
+		r.VisibilityTimeout = 5 * time.Minute
+	}
+	if r.PollInterval <= 0 {
+		r.PollInterval = time.Second
+	}
+	if r.Backoff == nil {
+		r.Backoff = defaultBackoff
+	}
+	return r
+}
+
+// defaultBackoff 1s、2s、4s ... 最长 10m。
+func defaultBackoff(attempt int) time.Duration {
+	d := time.Second << min(max(attempt-1, 0), 10)
+	return min(d, 10*time.Minute)
+}
+
+// Queue 基于 Redis 的可靠任务队列。使用的 key（{name} 为队列名）：
+//   - {name}:ready       待处理任务（List）
+//   - {name}:processing  处理中任务（ZSet，score 为可见性超时时刻）
+//   - {name}:delayed     等待重试 / 延迟执行的任务（ZSet，score 为可执行时刻）
+//   - {name}:dead        超过重试次数的死信任务（List）
+//
+// 用法：
+//
+//	q, _ := queue.New(redisClient, "email", nil)
+//	q.Enqueue(ctx, "send_welcome", map[string]any{"user_id": 42})
+//
+//	w := q.NewWorker(4)
+//	w.Handle("send_welcome", func(ctx context.Context, job *queue.Job) error {
+//	    var p struct{ UserID int64 `json:"user_id"` }
+//	    if err := job.Decode(&p); err != nil {
+//	        return retry.Permanent(err) // 直接进入死信，不再重试
+//	    }
+//	    return sendWelcome(ctx, p.UserID)
+//	})
+//	w.Start(ctx)
+//	defer w.Stop(context.Background())
+type Queue struct {
+	client *db.RedisClient
+	name   string
+	opts   Options
+}
+
+// New 创建队列。opts 为 nil 时使用默认配置。
+func New(client *db.RedisClient, name string, opts *Options) (*Queue, error) {
+	if client == nil || client.GetClient() == nil {
+		return nil, ErrNilClient
+	}
+	if name == "" {
+		return nil, ErrEmptyName
+	}
+	return &Queue{client: client, name: name, opts: opts.withDefaults()}, nil
+}
+
+// Name 返回队列名。
+func (q *Queue) Name() string { return q.name }
+
+// rdb 返回当前的 Redis 连接。每次操作都重新获取，RedisClient.Reconnect 替换连接后仍可使用。
+func (q *Queue) rdb() *redis.Client { return q.client.GetClient() }
+
+func (q *Queue) readyKey() string      { return q.name + ":ready" }
+func (q *Queue) processingKey() string { return q.name + ":processing" }
+func (q *Queue) delayedKey() string    { return q.name + ":delayed" }
+func (q *Queue) deadKey() string       { return q.name + ":dead" }
+
+// Enqueue 将任务加入队列，payload 序列化为 JSON，返回任务 ID（ULID）。
+func (q *Queue) Enqueue(ctx context.Context, jobType string, payload any) (string, error) {
+	return q.EnqueueIn(ctx, jobType, payload, 0)
+}
+
+// EnqueueIn 将任务延迟 delay 后加入队列，delay <= 0 时立即入队。
+func (q *Queue) EnqueueIn(ctx context.Context, jobType string, payload any, delay time.Duration) (string, error) {
+	if jobType == "" {
+		return "", ErrEmptyJobType
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("queue: 序列化 payload 失败: %w", err)
+	}
+	job := &Job{
+		ID:         hashutil.NewULID(),
+		Type:       jobType,
+		Payload:    raw,
+		MaxRetries: q.opts.MaxRetries,
+		EnqueuedAt: time.Now(),
+	}
+	data, err := json.Marshal(job)
+	if err != nil {
+		return "", fmt.Errorf("queue: 序列化任务失败: %w", err)
+	}
+
+	if delay > 0 {
+		err = q.rdb().ZAdd(ctx, q.delayedKey(), redis.Z{Score: float64(scoreAt(time.Now().Add(delay))), Member: data}).Err()
+	} else {
+		err = q.rdb().LPush(ctx, q.readyKey(), data).Err()
+	}
+	if err != nil {
+		return "", fmt.Errorf("queue: 任务入队失败: %w", err)
+	}
+	return job.ID, nil
+}
+
+// Stats 队列各状态的任务数量。
+type Stats struct {
+	Ready      int64
+	Processing int64
+	Delayed    int64
+	Dead       int64
+}
+
+// Stats 返回队列各状态的任务数量。
+func (q *Queue) Stats(ctx context.Context) (Stats, error) {
+	pipe := q.rdb().Pipeline()
+	ready := pipe.LLen(ctx, q.readyKey())
+	processing := pipe.ZCard(ctx, q.processingKey())
+	delayed := pipe.ZCard(ctx, q.delayedKey())
+	dead := pipe.LLen(ctx, q.deadKey())
+	if _, err := pipe.Exec(ctx); err != nil {
+		return Stats{}, fmt.Errorf("queue: 获取统计失败: %w", err)
+	}
+	return Stats{Ready: ready.Val(), Processing: processing.Val(), Delayed: delayed.Val(), Dead: dead.Val()}, nil
+}
+
+// DeadJobs 返回死信队列中最新的至多 limit 个任务（limit <= 0 时返回全部）。
+func (q *Queue) DeadJobs(ctx context.Context, limit int64) ([]*Job, error) {
+	stop := int64(-1)
+	if limit > 0 {
+		stop = limit - 1
+	}
+	items, err := q.rdb().LRange(ctx, q.deadKey(), 0, stop).Result()
+	if err != nil {
+		return nil, fmt.Errorf("queue: 读取死信队列失败: %w", err)
+	}
+	jobs := make([]*Job, 0, len(items))
+	for _, item := range items {
+		var job Job
+		if err := json.Unmarshal([]byte(item), &job); err != nil {
+			log.Warnf("queue: 跳过无法解析的死信任务: %v", err)
+			continue
+		}
+		jobs = append(jobs, &job)
+	}
+	return jobs, nil
+}
+
+// RequeueDead 将死信队列中的任务全部重新入队（重置失败次数），返回处理数量。
+// 只处理调用时已在死信队列中的任务，期间再次失败进入死信的任务不会被重复处理；
+// 每个任务通过 Lua 脚本原子地从死信队列移入 ready，中途失败也不会丢失任务。
+func (q *Queue) RequeueDead(ctx context.Context) (int, error) {
+	total, err := q.rdb().LLen(ctx, q.deadKey()).Result()
+	if err != nil {
+		return 0, fmt.Errorf("queue: 读取死信队列失败: %w", err)
+	}
+
+	n := 0
+	for range total {
+		// 从最早进入死信的一端开始处理
+		item, err := q.rdb().LIndex(ctx, q.deadKey(), -1).Result()
+		if errors.Is(err, redis.Nil) {
+			return n, nil
+		}
+		if err != nil {
+			return n, fmt.Errorf("queue: 读取死信任务失败: %w", err)
+		}
+
+		var data []byte
+		var job Job
+		if err := json.Unmarshal([]byte(item), &job); err != nil {
+			log.Warnf("queue: 丢弃无法解析的死信任务: %v", err)
+		} else {
+			job.Attempts = 0
+			job.LastError = ""
+			data, _ = json.Marshal(&job)
+		}
+		moved, err := requeueScript.Run(ctx, q.rdb(), []string{q.deadKey(), q.readyKey()}, item, data).Int()
+		if err != nil {
+			return n, fmt.Errorf("queue: 死信任务重新入队失败: %w", err)
+		}
+		if moved == 1 && data != nil {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// ---------------------------------------------------------------------------
+// 内部操作（Lua 脚本保证状态迁移的原子性）
+// ---------------------------------------------------------------------------
+
+// requeueScript 从死信队列尾部移除一个 ARGV[1] 并将 ARGV[2] 放入 ready，ARGV[2] 为空时仅移除。
+// 任务已不在死信队列中（被并发处理）时返回 0。
+var requeueScript = redis.NewScript(`
+if redis.call('LREM', KEYS[1], -1, ARGV[1]) == 0 then
+	return 0
+end
+if ARGV[2] ~= '' then
+	redis.call('LPUSH', KEYS[2], ARGV[2])
+end
+return 1
+`)
+
+// dequeueScript 从 ready 取出一个任务并放入 processing（score 为可见性超时时刻）。
+var dequeueScript = redis.NewScript(`
+local job = redis.call('RPOP', KEYS[1])
+if job then
+	redis.call('ZADD', KEYS[2], ARGV[1], job)
+end
+return job
+`)
+
+// promoteScript 将 delayed 中已到期的任务移入 ready。
+var promoteScript = redis.NewScript(`
+local jobs = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+for _, job in ipairs(jobs) do
+	redis.call('ZREM', KEYS[1], job)
+	redis.call('LPUSH', KEYS[2], job)
+end
+return #jobs
+`)
+
+// moveScript 若任务仍在 processing 中则将其移除并写入目标：
+// ARGV[3] 为 "list" 时 LPUSH 到 KEYS[2]，为 "zset" 时以 ARGV[4] 为 score ZADD 到 KEYS[2]，为空时仅移除。
+var moveScript = redis.NewScript(`
+if redis.call('ZREM', KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+if ARGV[3] == 'list' then
+	redis.call('LPUSH', KEYS[2], ARGV[2])
+elseif ARGV[3] == 'zset' then
+	redis.call('ZADD', KEYS[2], ARGV[4], ARGV[2])
+end
+return 1
+`)
+
+// dequeue 取出一个任务，队列为空时返回 nil。raw 为任务在 processing 中的原始成员值。
+func (q *Queue) dequeue(ctx context.Context) (job *Job, raw string, err error) {
+	deadline := scoreAt(time.Now().Add(q.opts.VisibilityTimeout))
+	raw, err = dequeueScript.Run(ctx, q.rdb(), []string{q.readyKey(), q.processingKey()}, deadline).Text()
+	if errors.Is(err, redis.Nil) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("queue: 取出任务失败: %w", err)
+	}
+	job = &Job{}
+	if err := json.Unmarshal([]byte(raw), job); err != nil {
+		// 无法解析的任务直接移入死信，避免反复取出
+		moveScript.Run(ctx, q.rdb(), []string{q.processingKey(), q.deadKey()}, raw, raw, "list")
+		return nil, "", fmt.Errorf("queue: 解析任务失败，已移入死信队列: %w", err)
+	}
+	return job, raw, nil
+}
+
+// ack 确认任务成功，从 processing 中移除。
+func (q *Queue) ack(ctx context.Context, raw string) error {
+	return moveScript.Run(ctx, q.rdb(), []string{q.processingKey(), q.processingKey()}, raw, "", "").Err()
+}
+
+// fail 记录任务失败：未超过重试次数时按退避放入 delayed，否则放入死信队列。
+// permanent 为 true 时直接进入死信队列。
+func (q *Queue) fail(ctx context.Context, job *Job, raw string, cause error, permanent bool) error {
+	job.Attempts++
+	job.LastError = cause.Error()
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("queue: 序列化任务失败: %w", err)
+	}
+
+	if permanent || job.Attempts > job.MaxRetries {
+		moved, err := moveScript.Run(ctx, q.rdb(), []string{q.processingKey(), q.deadKey()}, raw, data, "list").Int()
+		if err != nil {
+			return fmt.Errorf("queue: 任务移入死信队列失败: %w", err)
+		}
+		if moved == 1 {
+			log.Warnf("queue: 任务 %s（%s）失败 %d 次，已移入死信队列: %v", job.ID, job.Type, job.Attempts, cause)
+			if q.opts.OnDead != nil {
+				q.opts.OnDead(job)
+			}
+		}
+		return nil
+	}
+
+	runAt := scoreAt(time.Now().Add(q.opts.Backoff(job.Attempts)))
+	err = moveScript.Run(ctx, q.rdb(), []string{q.processingKey(), q.delayedKey()}, raw, data, "zset", runAt).Err()
+	if err != nil {
+		return fmt.Errorf("queue: 任务重新入队失败: %w", err)
+	}
+	return nil
+}
+
+// promoteDelayed 将到期的延迟任务移入 ready，返回移动数量。
+func (q *Queue) promoteDelayed(ctx context.Context) (int, error) {
+	return promoteScript.Run(ctx, q.rdb(), []string{q.delayedKey(), q.readyKey()}, scoreAt(time.Now()), 100).Int()
+}
+
+// reclaimExpired 将可见性超时的任务按失败处理（worker 崩溃或处理过慢）。
+func (q *Queue) reclaimExpired(ctx context.Context) error {
+	items, err := q.rdb().ZRangeByScore(ctx, q.processingKey(), &redis.ZRangeBy{
+		Min: "-inf", Max: strconv.FormatInt(scoreAt(time.Now()), 10), Count: 100,
+	}).Result()
+	if err != nil {
+		return err
+	}
+	for _, raw := range items {
+		var job Job
+		if err := json.Unmarshal([]byte(raw), &job); err != nil {
+			moveScript.Run(ctx, q.rdb(), []string{q.processingKey(), q.deadKey()}, raw, raw, "list")
+			continue
+		}
+		log.Warnf("queue: 任务 %s（%s）处理超时，按失败重试", job.ID, job.Type)
+		if err := q.fail(ctx, &job, raw, errVisibilityTimeout, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// errVisibilityTimeout 任务在可见性超时内未被确认。
+var errVisibilityTimeout = errors.New("queue: 任务处理超时（可见性超时）")
+
+// scoreAt 将时间转换为 ZSet score（毫秒时间戳）。
+func scoreAt(t time.Time) int64 {
+	return t.UnixMilli()
+}
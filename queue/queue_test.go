@@ -0,0 +1,555 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pylemonorg/gotools/internal/redistest"
+	"github.com/pylemonorg/gotools/retry"
+)
+
+// ---------------------------------------------------------------------------
+// 内存 Redis：实现队列用到的 List / ZSet 命令，并用 Go 代码模拟各 Lua 脚本
+// ---------------------------------------------------------------------------
+
+type memRedis struct {
+	lists map[string][]string // 下标 0 为左端（LPUSH 端）
+	zsets map[string]map[string]float64
+}
+
+func (m *memRedis) lpush(key string, vals ...string) int {
+	for _, v := range vals {
+		m.lists[key] = append([]string{v}, m.lists[key]...)
+	}
+	return len(m.lists[key])
+}
+
+func (m *memRedis) rpop(key string) (string, bool) {
+	l := m.lists[key]
+	if len(l) == 0 {
+		return "", false
+	}
+	m.lists[key] = l[:len(l)-1]
+	return l[len(l)-1], true
+}
+
+// lremLast 从右端起删除第一个等于 v 的元素（LREM key -1 v）。
+func (m *memRedis) lremLast(key, v string) bool {
+	l := m.lists[key]
+	for i := len(l) - 1; i >= 0; i-- {
+		if l[i] == v {
+			m.lists[key] = slices.Delete(l, i, i+1)
+			return true
+		}
+	}
+	return false
+}
+
+func (m *memRedis) zadd(key string, score float64, member string) {
+	if m.zsets[key] == nil {
+		m.zsets[key] = map[string]float64{}
+	}
+	m.zsets[key][member] = score
+}
+
+func (m *memRedis) zrem(key, member string) bool {
+	if _, ok := m.zsets[key][member]; !ok {
+		return false
+	}
+	delete(m.zsets[key], member)
+	return true
+}
+
+// zrangeByScore 返回 score <= max 的成员（按 score 升序），最多 limit 个。
+func (m *memRedis) zrangeByScore(key string, max float64, limit int) []string {
+	var out []string
+	for member, score := range m.zsets[key] {
+		if score <= max {
+			out = append(out, member)
+		}
+	}
+	z := m.zsets[key]
+	sort.Slice(out, func(i, j int) bool {
+		if z[out[i]] != z[out[j]] {
+			return z[out[i]] < z[out[j]]
+		}
+		return out[i] < out[j]
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+// eval 模拟 EVALSHA sha numkeys key [key ...] arg [arg ...]。
+func (m *memRedis) eval(args []string) any {
+	n, _ := strconv.Atoi(args[2])
+	keys, argv := args[3:3+n], args[3+n:]
+	score := func(s string) float64 {
+		f, _ := strconv.ParseFloat(s, 64)
+		return f
+	}
+	switch args[1] {
+	case requeueScript.Hash():
+		if !m.lremLast(keys[0], argv[0]) {
+			return 0
+		}
+		if argv[1] != "" {
+			m.lpush(keys[1], argv[1])
+		}
+		return 1
+	case dequeueScript.Hash():
+		job, ok := m.rpop(keys[0])
+		if !ok {
+			return nil
+		}
+		m.zadd(keys[1], score(argv[0]), job)
+		return job
+	case promoteScript.Hash():
+		limit, _ := strconv.Atoi(argv[1])
+		jobs := m.zrangeByScore(keys[0], score(argv[0]), limit)
+		for _, job := range jobs {
+			m.zrem(keys[0], job)
+			m.lpush(keys[1], job)
+		}
+		return len(jobs)
+	case moveScript.Hash():
+		if !m.zrem(keys[0], argv[0]) {
+			return 0
+		}
+		switch argv[2] {
+		case "list":
+			m.lpush(keys[1], argv[1])
+		case "zset":
+			m.zadd(keys[1], score(argv[3]), argv[1])
+		}
+		return 1
+	}
+	return redistest.Error("NOSCRIPT No matching script")
+}
+
+// newTestQueue 创建连接内存 Redis 的队列。
+func newTestQueue(t *testing.T, opts *Options) *Queue {
+	t.Helper()
+	m := &memRedis{lists: map[string][]string{}, zsets: map[string]map[string]float64{}}
+	srv := redistest.NewServer(t)
+	srv.Handle("LPUSH", func(args []string) any { return m.lpush(args[1], args[2:]...) })
+	srv.Handle("LLEN", func(args []string) any { return len(m.lists[args[1]]) })
+	srv.Handle("LRANGE", func(args []string) any {
+		l := m.lists[args[1]]
+		start, _ := strconv.Atoi(args[2])
+		stop, _ := strconv.Atoi(args[3])
+		if stop < 0 {
+			stop += len(l)
+		}
+		stop = min(stop, len(l)-1)
+		if start > stop {
+			return []string{}
+		}
+		return slices.Clone(l[start : stop+1])
+	})
+	srv.Handle("LINDEX", func(args []string) any {
+		l := m.lists[args[1]]
+		i, _ := strconv.Atoi(args[2])
+		if i < 0 {
+			i += len(l)
+		}
+		if i < 0 || i >= len(l) {
+			return nil
+		}
+		return l[i]
+	})
+	srv.Handle("ZADD", func(args []string) any {
+		s, _ := strconv.ParseFloat(args[2], 64)
+		m.zadd(args[1], s, args[3])
+		return 1
+	})
+	srv.Handle("ZCARD", func(args []string) any { return len(m.zsets[args[1]]) })
+	srv.Handle("ZRANGEBYSCORE", func(args []string) any {
+		// ZRANGEBYSCORE key -inf max LIMIT 0 count
+		max, _ := strconv.ParseFloat(args[3], 64)
+		limit := 0
+		if len(args) == 7 {
+			limit, _ = strconv.Atoi(args[6])
+		}
+		return m.zrangeByScore(args[1], max, limit)
+	})
+	srv.Handle("EVALSHA", m.eval)
+
+	q, err := New(srv.Client(t), "test", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return q
+}
+
+func mustStats(t *testing.T, q *Queue, want Stats) {
+	t.Helper()
+	got, err := q.Stats(context.Background())
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if got != want {
+		t.Errorf("Stats = %+v, 期望 %+v", got, want)
+	}
+}
+
+// waitFor 轮询直到 cond 为 true，超时则失败。
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("等待%s超时", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// noBackoff 失败后立即可重试。
+func noBackoff(int) time.Duration { return 0 }
+
+// ---------------------------------------------------------------------------
+// Queue
+// ---------------------------------------------------------------------------
+
+func TestNew(t *testing.T) {
+	if _, err := New(nil, "q", nil); !errors.Is(err, ErrNilClient) {
+		t.Errorf("err = %v, 期望 ErrNilClient", err)
+	}
+	q := newTestQueue(t, nil)
+	if _, err := New(q.client, "", nil); !errors.Is(err, ErrEmptyName) {
+		t.Errorf("err = %v, 期望 ErrEmptyName", err)
+	}
+	if _, err := q.Enqueue(context.Background(), "", nil); !errors.Is(err, ErrEmptyJobType) {
+		t.Errorf("err = %v, 期望 ErrEmptyJobType", err)
+	}
+	if q.opts.MaxRetries != 3 || q.opts.VisibilityTimeout != 5*time.Minute || q.opts.PollInterval != time.Second {
+		t.Errorf("默认值 = %+v", q.opts)
+	}
+	if o := (&Options{MaxRetries: -1}).withDefaults(); o.MaxRetries != 0 {
+		t.Errorf("MaxRetries < 0 时应不重试, 实际 %d", o.MaxRetries)
+	}
+}
+
+func TestDefaultBackoff(t *testing.T) {
+	cases := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{0, time.Second},
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{5, 16 * time.Second},
+		{10, 512 * time.Second},
+		{11, 10 * time.Minute},
+		{100, 10 * time.Minute},
+	}
+	for _, tc := range cases {
+		if got := defaultBackoff(tc.attempt); got != tc.want {
+			t.Errorf("defaultBackoff(%d) = %v, 期望 %v", tc.attempt, got, tc.want)
+		}
+	}
+}
+
+func TestEnqueueDequeueOrder(t *testing.T) {
+	ctx := context.Background()
+	q := newTestQueue(t, nil)
+
+	var ids []string
+	for i := range 3 {
+		id, err := q.Enqueue(ctx, "n", map[string]int{"i": i})
+		if err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	mustStats(t, q, Stats{Ready: 3})
+
+	// 先入先出
+	var raws []string
+	for i, id := range ids {
+		job, raw, err := q.dequeue(ctx)
+		if err != nil || job == nil {
+			t.Fatalf("dequeue #%d = %v, %v", i, job, err)
+		}
+		var p struct{ I int }
+		if err := job.Decode(&p); err != nil {
+			t.Fatalf("Decode: %v", err)
+		}
+		if job.ID != id || job.Type != "n" || p.I != i || job.MaxRetries != 3 {
+			t.Errorf("dequeue #%d = %+v (payload %d), 期望 ID %s", i, job, p.I, id)
+		}
+		raws = append(raws, raw)
+	}
+	if job, _, err := q.dequeue(ctx); job != nil || err != nil {
+		t.Errorf("空队列 dequeue = %v, %v", job, err)
+	}
+	mustStats(t, q, Stats{Processing: 3})
+
+	for _, raw := range raws {
+		if err := q.ack(ctx, raw); err != nil {
+			t.Fatalf("ack: %v", err)
+		}
+	}
+	mustStats(t, q, Stats{})
+}
+
+func TestEnqueueIn(t *testing.T) {
+	ctx := context.Background()
+	q := newTestQueue(t, nil)
+
+	if _, err := q.EnqueueIn(ctx, "later", nil, time.Hour); err != nil {
+		t.Fatalf("EnqueueIn: %v", err)
+	}
+	if _, err := q.EnqueueIn(ctx, "soon", nil, time.Millisecond); err != nil {
+		t.Fatalf("EnqueueIn: %v", err)
+	}
+	mustStats(t, q, Stats{Delayed: 2})
+
+	time.Sleep(5 * time.Millisecond)
+	if n, err := q.promoteDelayed(ctx); err != nil || n != 1 {
+		t.Fatalf("promoteDelayed = %d, %v, 期望只转移到期的 1 个", n, err)
+	}
+	job, _, _ := q.dequeue(ctx)
+	if job == nil || job.Type != "soon" {
+		t.Errorf("dequeue = %+v, 期望 soon", job)
+	}
+	mustStats(t, q, Stats{Processing: 1, Delayed: 1})
+}
+
+func TestFailRetryAndDead(t *testing.T) {
+	ctx := context.Background()
+	var dead []*Job
+	q := newTestQueue(t, &Options{MaxRetries: 1, Backoff: noBackoff, OnDead: func(job *Job) { dead = append(dead, job) }})
+
+	id, _ := q.Enqueue(ctx, "flaky", nil)
+	job, raw, _ := q.dequeue(ctx)
+
+	// 第 1 次失败：未超过重试次数，进入 delayed
+	if err := q.fail(ctx, job, raw, errors.New("boom 1"), false); err != nil {
+		t.Fatalf("fail: %v", err)
+	}
+	mustStats(t, q, Stats{Delayed: 1})
+	if n, _ := q.promoteDelayed(ctx); n != 1 {
+		t.Fatalf("promoteDelayed = %d, 期望 1", n)
+	}
+	job, raw, _ = q.dequeue(ctx)
+	if job == nil || job.ID != id || job.Attempts != 1 || job.LastError != "boom 1" {
+		t.Fatalf("重新投递的任务 = %+v", job)
+	}
+
+	// 第 2 次失败：超过 MaxRetries，进入死信
+	if err := q.fail(ctx, job, raw, errors.New("boom 2"), false); err != nil {
+		t.Fatalf("fail: %v", err)
+	}
+	mustStats(t, q, Stats{Dead: 1})
+	if len(dead) != 1 || dead[0].ID != id {
+		t.Errorf("OnDead 收到 %v", dead)
+	}
+	// 重复确认同一任务（如超时回收后原 worker 才完成）不应重复进入死信
+	if err := q.fail(ctx, job, raw, errors.New("boom 3"), true); err != nil || len(dead) != 1 {
+		t.Errorf("重复 fail = %v, OnDead 调用 %d 次", err, len(dead))
+	}
+
+	jobs, err := q.DeadJobs(ctx, 0)
+	if err != nil || len(jobs) != 1 || jobs[0].Attempts != 2 || jobs[0].LastError != "boom 2" {
+		t.Fatalf("DeadJobs = %+v, %v", jobs, err)
+	}
+	if n, err := q.RequeueDead(ctx); err != nil || n != 1 {
+		t.Fatalf("RequeueDead = %d, %v", n, err)
+	}
+	mustStats(t, q, Stats{Ready: 1})
+	job, _, _ = q.dequeue(ctx)
+	if job == nil || job.ID != id || job.Attempts != 0 || job.LastError != "" {
+		t.Errorf("RequeueDead 后的任务 = %+v, 期望重置失败次数", job)
+	}
+}
+
+func TestFailPermanent(t *testing.T) {
+	ctx := context.Background()
+	q := newTestQueue(t, &Options{MaxRetries: 5})
+
+	q.Enqueue(ctx, "bad", nil)
+	job, raw, _ := q.dequeue(ctx)
+	if err := q.fail(ctx, job, raw, errors.New("invalid"), true); err != nil {
+		t.Fatalf("fail: %v", err)
+	}
+	mustStats(t, q, Stats{Dead: 1})
+}
+
+func TestDeadJobsLimit(t *testing.T) {
+	ctx := context.Background()
+	q := newTestQueue(t, &Options{MaxRetries: -1})
+
+	for i := range 3 {
+		q.Enqueue(ctx, fmt.Sprintf("t%d", i), nil)
+		job, raw, _ := q.dequeue(ctx)
+		q.fail(ctx, job, raw, errors.New("x"), false)
+	}
+	jobs, err := q.DeadJobs(ctx, 2)
+	if err != nil || len(jobs) != 2 || jobs[0].Type != "t2" || jobs[1].Type != "t1" {
+		t.Errorf("DeadJobs(2) = %+v, %v, 期望最新的 t2、t1", jobs, err)
+	}
+}
+
+func TestVisibilityTimeout(t *testing.T) {
+	ctx := context.Background()
+	q := newTestQueue(t, &Options{VisibilityTimeout: 10 * time.Millisecond, Backoff: noBackoff})
+
+	id, _ := q.Enqueue(ctx, "slow", nil)
+	if job, _, _ := q.dequeue(ctx); job == nil {
+		t.Fatal("dequeue 应取到任务")
+	}
+
+	// 未超时不回收
+	if err := q.reclaimExpired(ctx); err != nil {
+		t.Fatalf("reclaimExpired: %v", err)
+	}
+	mustStats(t, q, Stats{Processing: 1})
+
+	time.Sleep(20 * time.Millisecond)
+	if err := q.reclaimExpired(ctx); err != nil {
+		t.Fatalf("reclaimExpired: %v", err)
+	}
+	mustStats(t, q, Stats{Delayed: 1})
+
+	q.promoteDelayed(ctx)
+	job, _, _ := q.dequeue(ctx)
+	if job == nil || job.ID != id || job.Attempts != 1 || job.LastError != errVisibilityTimeout.Error() {
+		t.Errorf("超时后重新投递的任务 = %+v", job)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Worker
+// ---------------------------------------------------------------------------
+
+func TestWorker(t *testing.T) {
+	ctx := context.Background()
+	q := newTestQueue(t, &Options{MaxRetries: 2, PollInterval: 5 * time.Millisecond, Backoff: noBackoff})
+
+	var mu sync.Mutex
+	var done []string
+	var flakyCalls atomic.Int32
+
+	w := q.NewWorker(2)
+	w.Handle("ok", func(_ context.Context, job *Job) error {
+		var s string
+		job.Decode(&s)
+		mu.Lock()
+		done = append(done, s)
+		mu.Unlock()
+		return nil
+	})
+	w.Handle("flaky", func(context.Context, *Job) error {
+		if flakyCalls.Add(1) == 1 {
+			return errors.New("temporary")
+		}
+		return nil
+	})
+	w.Handle("perm", func(context.Context, *Job) error { return retry.Permanent(errors.New("invalid")) })
+	w.Handle("panic", func(context.Context, *Job) error { panic("boom") })
+
+	for _, s := range []string{"a", "b", "c"} {
+		q.Enqueue(ctx, "ok", s)
+	}
+	q.Enqueue(ctx, "flaky", nil)
+	q.Enqueue(ctx, "perm", nil)
+	q.Enqueue(ctx, "panic", nil)
+	q.Enqueue(ctx, "unknown", nil)
+
+	w.Start(ctx)
+	defer w.Stop(ctx)
+
+	// ok 3 个完成、flaky 重试后成功；perm、unknown 直接进入死信，panic 重试 2 次后进入死信
+	waitFor(t, "任务处理完成", func() bool {
+		s, _ := q.Stats(ctx)
+		return s == Stats{Dead: 3} && flakyCalls.Load() == 2
+	})
+	mu.Lock()
+	slices.Sort(done)
+	if !slices.Equal(done, []string{"a", "b", "c"}) {
+		t.Errorf("done = %v", done)
+	}
+	mu.Unlock()
+
+	jobs, _ := q.DeadJobs(ctx, 0)
+	byType := map[string]*Job{}
+	for _, job := range jobs {
+		byType[job.Type] = job
+	}
+	if j := byType["perm"]; j == nil || j.Attempts != 1 {
+		t.Errorf("perm 死信 = %+v, 期望失败 1 次", j)
+	}
+	if j := byType["unknown"]; j == nil || !strings.Contains(j.LastError, "未注册") {
+		t.Errorf("unknown 死信 = %+v", j)
+	}
+	if j := byType["panic"]; j == nil || j.Attempts != 3 || !strings.Contains(j.LastError, "panic: boom") {
+		t.Errorf("panic 死信 = %+v, 期望失败 3 次", j)
+	}
+}
+
+func TestWorkerStopWaitsForRunningJob(t *testing.T) {
+	ctx := context.Background()
+	q := newTestQueue(t, &Options{PollInterval: 5 * time.Millisecond})
+
+	started := make(chan struct{})
+	var finished atomic.Bool
+	w := q.NewWorker(1)
+	w.Handle("slow", func(context.Context, *Job) error {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		finished.Store(true)
+		return nil
+	})
+	q.Enqueue(ctx, "slow", nil)
+	w.Start(ctx)
+	<-started
+
+	if err := w.Stop(ctx); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if !finished.Load() {
+		t.Error("Stop 应等待处理中的任务完成")
+	}
+	mustStats(t, q, Stats{}) // 任务已确认
+
+	// 停止后不再取新任务
+	q.Enqueue(ctx, "slow", nil)
+	time.Sleep(20 * time.Millisecond)
+	mustStats(t, q, Stats{Ready: 1})
+}
+
+func TestWorkerStopTimeout(t *testing.T) {
+	ctx := context.Background()
+	q := newTestQueue(t, &Options{PollInterval: 5 * time.Millisecond, VisibilityTimeout: time.Minute})
+
+	started := make(chan struct{})
+	w := q.NewWorker(1)
+	w.Handle("stuck", func(ctx context.Context, _ *Job) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	q.Enqueue(ctx, "stuck", nil)
+	w.Start(ctx)
+	<-started
+
+	stopCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := w.Stop(stopCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Stop = %v, 期望 DeadlineExceeded", err)
+	}
+	// 被取消的任务按失败处理，等待重试
+	mustStats(t, q, Stats{Delayed: 1})
+}
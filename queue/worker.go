@@ -0,0 +1,189 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/pylemonorg/gotools/retry"
+)
+
+// Handler 处理一个任务。返回 nil 表示成功；返回 retry.Permanent(err) 时任务直接进入死信队列，
+// 其他错误按队列的退避策略重试。ctx 在可见性超时或 Worker 停止时取消。
+type Handler func(ctx context.Context, job *Job) error
+
+// Worker 从队列中取出任务并按类型分发给 Handler，支持并发消费与优雅退出。
+type Worker struct {
+	q           *Queue
+	concurrency int
+
+	mu       sync.RWMutex
+	handlers map[string]Handler
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	cancel    context.CancelFunc
+	stopping  chan struct{} // 关闭后不再取新任务
+	wg        sync.WaitGroup
+}
+
+// NewWorker 创建消费者，concurrency 为同时处理的任务数（<= 0 时为 1）。
+func (q *Queue) NewWorker(concurrency int) *Worker {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	return &Worker{
+		q:           q,
+		concurrency: concurrency,
+		handlers:    make(map[string]Handler),
+		stopping:    make(chan struct{}),
+	}
+}
+
+// Handle 注册任务类型对应的处理函数，同一类型重复注册时覆盖。
+func (w *Worker) Handle(jobType string, h Handler) {
+	w.mu.Lock()
+	w.handlers[jobType] = h
+	w.mu.Unlock()
+}
+
+// Start 启动消费协程和维护协程（延迟任务转移、超时任务回收），非阻塞。重复调用无效。
+func (w *Worker) Start(ctx context.Context) {
+	w.startOnce.Do(func() {
+		ctx, w.cancel = context.WithCancel(ctx)
+		for i := 0; i < w.concurrency; i++ {
+			w.wg.Add(1)
+			go w.consume(ctx)
+		}
+		w.wg.Add(1)
+		go w.maintain(ctx)
+		log.Infof("queue: [%s] worker 已启动，并发 %d", w.q.name, w.concurrency)
+	})
+}
+
+// Stop 停止取新任务并等待处理中的任务完成。ctx 结束时取消处理中任务的 Context，
+// 未确认的任务会在可见性超时后被重新投递。
+func (w *Worker) Stop(ctx context.Context) error {
+	var err error
+	w.stopOnce.Do(func() {
+		close(w.stopping)
+		if w.cancel == nil {
+			return
+		}
+
+		done := make(chan struct{})
+		go func() {
+			w.wg.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			w.cancel()
+			<-done
+			err = fmt.Errorf("queue: 等待处理中的任务超时: %w", ctx.Err())
+		}
+		w.cancel()
+		log.Infof("queue: [%s] worker 已停止", w.q.name)
+	})
+	return err
+}
+
+// consume 循环取任务并处理，队列为空时按 PollInterval 等待。
+func (w *Worker) consume(ctx context.Context) {
+	defer w.wg.Done()
+	for {
+		select {
+		case <-w.stopping:
+			return
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		job, raw, err := w.q.dequeue(ctx)
+		if err != nil {
+			log.Errorf("%v", err)
+		}
+		if job == nil {
+			select {
+			case <-w.stopping:
+				return
+			case <-ctx.Done():
+				return
+			case <-time.After(w.q.opts.PollInterval):
+			}
+			continue
+		}
+		w.process(ctx, job, raw)
+	}
+}
+
+// process 执行 Handler 并根据结果确认或重试任务。
+func (w *Worker) process(ctx context.Context, job *Job, raw string) {
+	w.mu.RLock()
+	h, ok := w.handlers[job.Type]
+	w.mu.RUnlock()
+
+	var err error
+	if !ok {
+		err = retry.Permanent(fmt.Errorf("queue: 未注册任务类型 %q 的处理函数", job.Type))
+	} else {
+		jobCtx, cancel := context.WithTimeout(ctx, w.q.opts.VisibilityTimeout)
+		err = runHandler(jobCtx, h, job)
+		cancel()
+	}
+
+	// 任务状态迁移不受 Worker 停止影响，避免任务卡在 processing 中
+	opCtx := context.WithoutCancel(ctx)
+	if err == nil {
+		if ackErr := w.q.ack(opCtx, raw); ackErr != nil {
+			log.Errorf("queue: 确认任务 %s 失败: %v", job.ID, ackErr)
+		}
+		return
+	}
+
+	var perm *retry.PermanentError
+	permanent := errors.As(err, &perm)
+	if !permanent {
+		log.Warnf("queue: 任务 %s（%s）第 %d 次执行失败: %v", job.ID, job.Type, job.Attempts+1, err)
+	}
+	if failErr := w.q.fail(opCtx, job, raw, err, permanent); failErr != nil {
+		log.Errorf("%v", failErr)
+	}
+}
+
+// runHandler 执行 Handler 并将 panic 转换为错误。
+func runHandler(ctx context.Context, h Handler, job *Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("queue: 任务 panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	return h(ctx, job)
+}
+
+// maintain 定期转移到期的延迟任务并回收可见性超时的任务。
+func (w *Worker) maintain(ctx context.Context) {
+	defer w.wg.Done()
+	ticker := time.NewTicker(w.q.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stopping:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if _, err := w.q.promoteDelayed(ctx); err != nil && ctx.Err() == nil {
+			log.Errorf("queue: [%s] 转移延迟任务失败: %v", w.q.name, err)
+		}
+		if err := w.q.reclaimExpired(ctx); err != nil && ctx.Err() == nil {
+			log.Errorf("queue: [%s] 回收超时任务失败: %v", w.q.name, err)
+		}
+	}
+}
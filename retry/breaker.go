@@ -0,0 +1,172 @@
+package retry
+
+import (
+	"sync"
+	"time"
+)
+
+// BreakerState 熔断器状态。
+type BreakerState int
+
+const (
+	// StateClosed 正常放行，连续失败达到阈值后转为 StateOpen。
+	StateClosed BreakerState = iota
+	// StateOpen 拒绝所有请求，经过 OpenTimeout 后转为 StateHalfOpen。
+	StateOpen
+	// StateHalfOpen 放行少量探测请求：成功则恢复 StateClosed，失败则重新 StateOpen。
+	StateHalfOpen
+)
+
+// String 返回状态名称。
+func (s BreakerState) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// BreakerConfig 熔断器配置。零值字段使用默认值。
+type BreakerConfig struct {
+	FailureThreshold int           // 连续失败多少次后打开，<= 0 时默认 5
+	OpenTimeout      time.Duration // 打开状态持续时间，<= 0 时默认 30s
+	HalfOpenMaxCalls int           // 半开状态下允许同时进行的探测请求数，<= 0 时默认 1
+
+	// OnStateChange 状态变化时调用（在锁外同步调用），可用于记录日志或上报指标
+	OnStateChange func(from, to BreakerState)
+}
+
+// Breaker 基于连续失败次数的熔断器，可单独使用，也可挂在 Policy.Breaker 上由 Do 自动驱动。
+// 同一下游依赖应共享同一个 Breaker。
+//
+// 用法：
+//
+//	breaker := retry.NewBreaker(retry.BreakerConfig{FailureThreshold: 10})
+//	policy := &retry.Policy{MaxAttempts: 3, Breaker: breaker}
+//	err := retry.Do(ctx, policy, callDownstream)
+//	if errors.Is(err, retry.ErrCircuitOpen) { ... }
+type Breaker struct {
+	cfg BreakerConfig
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	inFlight int // 半开状态下进行中的探测请求数
+}
+
+// NewBreaker 创建熔断器。
+func NewBreaker(cfg BreakerConfig) *Breaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 5
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = 30 * time.Second
+	}
+	if cfg.HalfOpenMaxCalls <= 0 {
+		cfg.HalfOpenMaxCalls = 1
+	}
+	return &Breaker{cfg: cfg}
+}
+
+// State 返回当前状态（打开超时后会体现为 StateHalfOpen）。
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == StateOpen && time.Since(b.openedAt) >= b.cfg.OpenTimeout {
+		return StateHalfOpen
+	}
+	return b.state
+}
+
+// Allow 判断是否放行请求，不放行时返回 ErrCircuitOpen。
+// 放行后调用方必须调用 Success 或 Failure 之一报告结果。
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	from := b.state
+	if b.state == StateOpen && time.Since(b.openedAt) >= b.cfg.OpenTimeout {
+		b.state = StateHalfOpen
+		b.inFlight = 0
+	}
+	var err error
+	switch b.state {
+	case StateOpen:
+		err = ErrCircuitOpen
+	case StateHalfOpen:
+		if b.inFlight >= b.cfg.HalfOpenMaxCalls {
+			err = ErrCircuitOpen
+		} else {
+			b.inFlight++
+		}
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+	return err
+}
+
+// Success 报告一次成功：清零失败计数，半开状态下恢复为关闭。
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	from := b.state
+	b.failures = 0
+	if b.state == StateHalfOpen {
+		b.state = StateClosed
+		b.inFlight = 0
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+}
+
+// Failure 报告一次失败：关闭状态下累计连续失败，达到阈值后打开；半开状态下立即重新打开。
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	from := b.state
+	switch b.state {
+	case StateClosed:
+		b.failures++
+		if b.failures >= b.cfg.FailureThreshold {
+			b.open()
+		}
+	case StateHalfOpen:
+		b.open()
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+}
+
+// Reset 强制恢复为关闭状态。
+func (b *Breaker) Reset() {
+	b.mu.Lock()
+	from := b.state
+	b.state = StateClosed
+	b.failures = 0
+	b.inFlight = 0
+	b.mu.Unlock()
+
+	b.notify(from, StateClosed)
+}
+
+// open 切换到打开状态，调用方需持有锁。
+func (b *Breaker) open() {
+	b.state = StateOpen
+	b.openedAt = time.Now()
+	b.failures = 0
+	b.inFlight = 0
+}
+
+func (b *Breaker) notify(from, to BreakerState) {
+	if from != to && b.cfg.OnStateChange != nil {
+		b.cfg.OnStateChange(from, to)
+	}
+}
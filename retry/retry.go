@@ -0,0 +1,224 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+)
+
+// 重试相关的哨兵错误。
+var (
+	// ErrRetriesExhausted 表示达到最大尝试次数或总耗时上限后仍失败，
+	// 返回的错误同时包裹最后一次失败的原始错误。
+	ErrRetriesExhausted = errors.New("retry: 重试次数已用尽")
+	// ErrCircuitOpen 表示熔断器处于打开状态，请求被直接拒绝。
+	ErrCircuitOpen = errors.New("retry: 熔断器已打开")
+)
+
+// Policy 重试策略（指数退避 + 随机抖动）。零值字段使用默认值，nil 等同于 DefaultPolicy()。
+type Policy struct {
+	MaxAttempts     int           // 最大尝试次数（含首次执行），<= 0 时默认 4（即重试 3 次）
+	InitialInterval time.Duration // 首次重试前的等待时间，<= 0 时默认 1s
+	MaxInterval     time.Duration // 单次等待上限，<= 0 时默认 30s
+	Multiplier      float64       // 每次重试等待时间的倍数，<= 0 时默认 2（设为 1 则固定间隔）
+	Jitter          float64       // 随机抖动比例（0~1），等待时间在 ±Jitter 范围内随机浮动，0 表示不抖动
+	MaxElapsedTime  time.Duration // 总耗时上限（含等待），超过后不再重试，0 表示不限制
+
+	// RetryIf 判断错误是否可重试，nil 时除 PermanentError 外的错误都重试
+	RetryIf func(err error) bool
+	// Breaker 可选熔断器：打开时直接返回 ErrCircuitOpen，可重试错误计入失败
+	Breaker *Breaker
+
+	// OnRetry 每次重试等待前调用（attempt 从 1 开始），可用于记录日志
+	OnRetry func(attempt int, err error, delay time.Duration)
+	// OnGiveUp 最终失败时调用（含不可重试错误、次数用尽、ctx 结束、熔断），attempts 为已执行次数
+	OnGiveUp func(attempts int, err error)
+}
+
+// DefaultPolicy 返回默认策略：最多尝试 4 次，1s 起指数退避，±20% 抖动。
+func DefaultPolicy() *Policy {
+	return &Policy{Jitter: 0.2}
+}
+
+// PermanentError 不可重试的错误，Do 遇到后立即返回其内部错误。
+type PermanentError struct {
+	Err error
+}
+
+// Error 实现 error 接口。
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap 支持 errors.Is / errors.As。
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// Permanent 将 err 标记为不可重试，err 为 nil 时返回 nil。
+//
+// 用法：
+//
+//	if resp.StatusCode == 404 {
+//	    return retry.Permanent(ErrNotFound)
+//	}
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+// ErrorContains 返回按关键字（不区分大小写）判断错误是否可重试的 RetryIf 函数，
+// 适用于 SDK 只返回文本错误（如 "503"、"connection reset"）的场景。
+func ErrorContains(keywords ...string) func(err error) bool {
+	lower := make([]string, len(keywords))
+	for i, kw := range keywords {
+		lower[i] = strings.ToLower(kw)
+	}
+	return func(err error) bool {
+		if err == nil {
+			return false
+		}
+		s := strings.ToLower(err.Error())
+		for _, kw := range lower {
+			if strings.Contains(s, kw) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Do 执行 fn，失败时按 policy 指数退避重试，直到成功、遇到 PermanentError 或不可重试错误、
+// 达到最大尝试次数 / 总耗时上限、熔断器打开或 ctx 结束。
+//
+// 不可重试错误原样返回；次数或耗时用尽时返回的错误同时满足
+// errors.Is(err, ErrRetriesExhausted) 和 errors.Is(err, 原始错误)。
+//
+// 用法：
+//
+//	err := retry.Do(ctx, &retry.Policy{MaxAttempts: 5, Jitter: 0.2}, func(ctx context.Context) error {
+//	    return client.Upload(ctx, key, data)
+//	})
+func Do(ctx context.Context, policy *Policy, fn func(ctx context.Context) error) error {
+	_, err := DoValue(ctx, policy, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
+
+// DoValue 同 Do，fn 返回结果值。
+//
+// 用法：
+//
+//	out, err := retry.DoValue(ctx, nil, func(ctx context.Context) (*Resp, error) {
+//	    return client.Get(ctx, url)
+//	})
+func DoValue[T any](ctx context.Context, policy *Policy, fn func(ctx context.Context) (T, error)) (T, error) {
+	p := policy.withDefaults()
+	start := time.Now()
+	delay := p.InitialInterval
+
+	var zero T
+	giveUp := func(attempts int, err error) (T, error) {
+		if p.OnGiveUp != nil {
+			p.OnGiveUp(attempts, err)
+		}
+		return zero, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		if p.Breaker != nil {
+			if err := p.Breaker.Allow(); err != nil {
+				return giveUp(attempt-1, err)
+			}
+		}
+
+		v, err := fn(ctx)
+		if err == nil {
+			if p.Breaker != nil {
+				p.Breaker.Success()
+			}
+			return v, nil
+		}
+
+		var perm *PermanentError
+		if errors.As(err, &perm) {
+			p.breakerNeutral()
+			return giveUp(attempt, perm.Err)
+		}
+		if p.RetryIf != nil && !p.RetryIf(err) {
+			p.breakerNeutral()
+			return giveUp(attempt, err)
+		}
+		if p.Breaker != nil {
+			p.Breaker.Failure()
+		}
+		if attempt >= p.MaxAttempts {
+			return giveUp(attempt, fmt.Errorf("%w（已尝试 %d 次）: %w", ErrRetriesExhausted, attempt, err))
+		}
+
+		wait := jitter(delay, p.Jitter)
+		if p.MaxElapsedTime > 0 && time.Since(start)+wait > p.MaxElapsedTime {
+			return giveUp(attempt, fmt.Errorf("%w（超过总耗时上限 %s）: %w", ErrRetriesExhausted, p.MaxElapsedTime, err))
+		}
+		if p.OnRetry != nil {
+			p.OnRetry(attempt, err, wait)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return giveUp(attempt, fmt.Errorf("retry: 重试被取消（最后一次错误: %v）: %w", err, ctx.Err()))
+		case <-timer.C:
+		}
+
+		delay = min(time.Duration(float64(delay)*p.Multiplier), p.MaxInterval)
+	}
+}
+
+// breakerNeutral 不可重试错误（业务错误）不计入熔断失败，但需释放半开状态下的探测名额。
+func (p *Policy) breakerNeutral() {
+	if p.Breaker != nil {
+		p.Breaker.Success()
+	}
+}
+
+// withDefaults 返回填充默认值后的策略副本。
+func (p *Policy) withDefaults() Policy {
+	if p == nil {
+		p = DefaultPolicy()
+	}
+	r := *p
+	if r.MaxAttempts <= 0 {
+		r.MaxAttempts = 4
+	}
+	if r.InitialInterval <= 0 {
+		r.InitialInterval = time.Second
+	}
+	if r.MaxInterval <= 0 {
+		r.MaxInterval = 30 * time.Second
+	}
+	if r.MaxInterval < r.InitialInterval {
+		r.MaxInterval = r.InitialInterval
+	}
+	if r.Multiplier <= 0 {
+		r.Multiplier = 2
+	}
+	r.Jitter = min(max(r.Jitter, 0), 1)
+	return r
+}
+
+// jitter 在 d 的 ±fraction 范围内随机取值。
+func jitter(d time.Duration, fraction float64) time.Duration {
+	if fraction <= 0 {
+		return d
+	}
+	delta := fraction * float64(d)
+	return time.Duration(float64(d) - delta + rand.Float64()*2*delta)
+}
@@ -0,0 +1,186 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fastPolicy 返回毫秒级间隔的策略，避免测试变慢。
+func fastPolicy(attempts int) *Policy {
+	return &Policy{MaxAttempts: attempts, InitialInterval: time.Millisecond, MaxInterval: 2 * time.Millisecond}
+}
+
+func TestDoSucceedsAfterRetries(t *testing.T) {
+	calls := 0
+	var retries []int
+	p := fastPolicy(5)
+	p.OnRetry = func(attempt int, err error, delay time.Duration) { retries = append(retries, attempt) }
+
+	err := Do(context.Background(), p, func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("transient")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+	if len(retries) != 2 || retries[0] != 1 || retries[1] != 2 {
+		t.Errorf("OnRetry attempts = %v, want [1 2]", retries)
+	}
+}
+
+func TestDoExhausted(t *testing.T) {
+	errBoom := errors.New("boom")
+	calls := 0
+	gaveUp := 0
+	p := fastPolicy(3)
+	p.OnGiveUp = func(attempts int, err error) { gaveUp = attempts }
+
+	err := Do(context.Background(), p, func(ctx context.Context) error {
+		calls++
+		return errBoom
+	})
+	if !errors.Is(err, ErrRetriesExhausted) || !errors.Is(err, errBoom) {
+		t.Errorf("Do = %v, want ErrRetriesExhausted wrapping boom", err)
+	}
+	if calls != 3 || gaveUp != 3 {
+		t.Errorf("calls = %d, gaveUp = %d, want 3 and 3", calls, gaveUp)
+	}
+}
+
+func TestDoPermanentAndRetryIf(t *testing.T) {
+	errNotFound := errors.New("not found")
+	calls := 0
+	err := Do(context.Background(), fastPolicy(5), func(ctx context.Context) error {
+		calls++
+		return Permanent(errNotFound)
+	})
+	if err != errNotFound || calls != 1 {
+		t.Errorf("Permanent: err = %v, calls = %d; want unwrapped error after 1 call", err, calls)
+	}
+
+	calls = 0
+	p := fastPolicy(5)
+	p.RetryIf = ErrorContains("503")
+	err = Do(context.Background(), p, func(ctx context.Context) error {
+		calls++
+		return errNotFound
+	})
+	if err != errNotFound || calls != 1 {
+		t.Errorf("RetryIf: err = %v, calls = %d; want unwrapped error after 1 call", err, calls)
+	}
+}
+
+func TestDoContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Policy{MaxAttempts: 10, InitialInterval: time.Hour}
+	p.OnRetry = func(int, error, time.Duration) { cancel() }
+
+	err := Do(ctx, p, func(ctx context.Context) error { return errors.New("fail") })
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Do = %v, want context.Canceled", err)
+	}
+}
+
+func TestDoValue(t *testing.T) {
+	calls := 0
+	v, err := DoValue(context.Background(), fastPolicy(3), func(ctx context.Context) (string, error) {
+		calls++
+		if calls == 1 {
+			return "", errors.New("retry me")
+		}
+		return "ok", nil
+	})
+	if err != nil || v != "ok" {
+		t.Errorf("DoValue = %q, %v; want ok, nil", v, err)
+	}
+}
+
+func TestErrorContains(t *testing.T) {
+	retryable := ErrorContains("503", "Connection Reset")
+	if !retryable(errors.New("read tcp: connection reset by peer")) {
+		t.Error("keyword match should be case-insensitive")
+	}
+	if retryable(errors.New("404 not found")) || retryable(nil) {
+		t.Error("non-matching and nil errors should not be retryable")
+	}
+}
+
+func TestBreakerWithDo(t *testing.T) {
+	b := NewBreaker(BreakerConfig{FailureThreshold: 2, OpenTimeout: 20 * time.Millisecond})
+	p := fastPolicy(5)
+	p.Breaker = b
+
+	calls := 0
+	err := Do(context.Background(), p, func(ctx context.Context) error {
+		calls++
+		return errors.New("down")
+	})
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Do = %v, want ErrCircuitOpen", err)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2 (breaker opens after threshold)", calls)
+	}
+	if b.State() != StateOpen {
+		t.Errorf("State = %v, want open", b.State())
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if b.State() != StateHalfOpen {
+		t.Errorf("State after timeout = %v, want half-open", b.State())
+	}
+	if err := Do(context.Background(), p, func(ctx context.Context) error { return nil }); err != nil {
+		t.Fatalf("probe Do: %v", err)
+	}
+	if b.State() != StateClosed {
+		t.Errorf("State after successful probe = %v, want closed", b.State())
+	}
+}
+
+func TestBreakerHalfOpen(t *testing.T) {
+	var changes []string
+	b := NewBreaker(BreakerConfig{
+		FailureThreshold: 1,
+		OpenTimeout:      10 * time.Millisecond,
+		OnStateChange:    func(from, to BreakerState) { changes = append(changes, from.String()+"->"+to.String()) },
+	})
+
+	if err := b.Allow(); err != nil {
+		t.Fatalf("Allow in closed state: %v", err)
+	}
+	b.Failure()
+	if err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Allow in open state = %v, want ErrCircuitOpen", err)
+	}
+
+	time.Sleep(15 * time.Millisecond)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("first half-open probe should be allowed: %v", err)
+	}
+	if err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("second concurrent probe = %v, want ErrCircuitOpen", err)
+	}
+	b.Failure()
+	if b.State() != StateOpen {
+		t.Errorf("State after failed probe = %v, want open", b.State())
+	}
+
+	want := []string{"closed->open", "open->half-open", "half-open->open"}
+	if len(changes) != len(want) {
+		t.Fatalf("state changes = %v, want %v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("state changes = %v, want %v", changes, want)
+			break
+		}
+	}
+}
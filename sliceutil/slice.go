@@ -0,0 +1,132 @@
+package sliceutil
+
+// Map 对每个元素应用 fn，返回结果组成的新切片。s 为 nil 时返回 nil。
+//
+// 用法：
+//
+//	ids := sliceutil.Map(users, func(u User) int64 { return u.ID })
+func Map[T, R any](s []T, fn func(T) R) []R {
+	if s == nil {
+		return nil
+	}
+	out := make([]R, len(s))
+	for i, v := range s {
+		out[i] = fn(v)
+	}
+	return out
+}
+
+// Filter 返回满足 keep 的元素组成的新切片，保持原有顺序。
+func Filter[T any](s []T, keep func(T) bool) []T {
+	out := make([]T, 0, len(s))
+	for _, v := range s {
+		if keep(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Reduce 从 init 开始依次用 fn 累积每个元素，返回最终结果。
+//
+// 用法：
+//
+//	total := sliceutil.Reduce(orders, 0.0, func(acc float64, o Order) float64 { return acc + o.Amount })
+func Reduce[T, A any](s []T, init A, fn func(A, T) A) A {
+	acc := init
+	for _, v := range s {
+		acc = fn(acc, v)
+	}
+	return acc
+}
+
+// Chunk 将 s 按每批 size 个拆分，最后一批可能不足 size。
+// 子切片共享底层数组（容量已截断，对其 append 不会覆盖后续批次）。size <= 0 时 panic。
+//
+// 用法（Redis pipeline / OBS 批量删除）：
+//
+//	for _, batch := range sliceutil.Chunk(keys, 500) {
+//	    pipe := rdb.Pipeline()
+//	    // ...
+//	}
+func Chunk[T any](s []T, size int) [][]T {
+	if size <= 0 {
+		panic("sliceutil: Chunk size must be positive")
+	}
+	chunks := make([][]T, 0, (len(s)+size-1)/size)
+	for size < len(s) {
+		chunks = append(chunks, s[:size:size])
+		s = s[size:]
+	}
+	if len(s) > 0 {
+		chunks = append(chunks, s)
+	}
+	return chunks
+}
+
+// Unique 去除重复元素并保持首次出现的顺序，返回新切片。
+func Unique[T comparable](s []T) []T {
+	seen := make(map[T]struct{}, len(s))
+	out := make([]T, 0, len(s))
+	for _, v := range s {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		out = append(out, v)
+	}
+	return out
+}
+
+// Difference 返回在 a 中但不在 b 中的元素，保持 a 的顺序（a 中的重复元素会保留）。
+func Difference[T comparable](a, b []T) []T {
+	exclude := toSet(b)
+	out := make([]T, 0, len(a))
+	for _, v := range a {
+		if _, ok := exclude[v]; !ok {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Intersect 返回同时存在于 a 和 b 中的元素，保持 a 的顺序并去重。
+func Intersect[T comparable](a, b []T) []T {
+	include := toSet(b)
+	seen := make(map[T]struct{})
+	out := make([]T, 0)
+	for _, v := range a {
+		if _, ok := include[v]; !ok {
+			continue
+		}
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		out = append(out, v)
+	}
+	return out
+}
+
+// GroupBy 按 keyFn 的结果对元素分组，组内保持原有顺序。
+//
+// 用法：
+//
+//	byBucket := sliceutil.GroupBy(keys, func(k string) string { return hashutil.BucketKey("b", k, 16) })
+func GroupBy[T any, K comparable](s []T, keyFn func(T) K) map[K][]T {
+	groups := make(map[K][]T)
+	for _, v := range s {
+		k := keyFn(v)
+		groups[k] = append(groups[k], v)
+	}
+	return groups
+}
+
+// toSet 将切片转换为集合。
+func toSet[T comparable](s []T) map[T]struct{} {
+	set := make(map[T]struct{}, len(s))
+	for _, v := range s {
+		set[v] = struct{}{}
+	}
+	return set
+}
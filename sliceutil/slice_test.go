@@ -0,0 +1,93 @@
+package sliceutil
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestMap(t *testing.T) {
+	got := Map([]int{1, 2, 3}, strconv.Itoa)
+	want := []string{"1", "2", "3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Map = %q, want %q", got, want)
+	}
+	if got := Map[int, string](nil, strconv.Itoa); got != nil {
+		t.Errorf("Map(nil) = %v, want nil", got)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	got := Filter([]int{1, 2, 3, 4, 5}, func(n int) bool { return n%2 == 1 })
+	want := []int{1, 3, 5}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Filter = %v, want %v", got, want)
+	}
+}
+
+func TestReduce(t *testing.T) {
+	got := Reduce([]int{1, 2, 3, 4}, 10, func(acc, n int) int { return acc + n })
+	if got != 20 {
+		t.Errorf("Reduce = %d, want 20", got)
+	}
+}
+
+func TestChunk(t *testing.T) {
+	tests := []struct {
+		input []int
+		size  int
+		want  [][]int
+	}{
+		{[]int{1, 2, 3, 4, 5}, 2, [][]int{{1, 2}, {3, 4}, {5}}},
+		{[]int{1, 2, 3}, 3, [][]int{{1, 2, 3}}},
+		{nil, 2, [][]int{}},
+	}
+	for _, tt := range tests {
+		got := Chunk(tt.input, tt.size)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Chunk(%v, %d) = %v, want %v", tt.input, tt.size, got, tt.want)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Chunk with size 0 should panic")
+		}
+	}()
+	Chunk([]int{1}, 0)
+}
+
+func TestUnique(t *testing.T) {
+	got := Unique([]string{"b", "a", "b", "c", "a"})
+	want := []string{"b", "a", "c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unique = %q, want %q", got, want)
+	}
+}
+
+func TestDifferenceAndIntersect(t *testing.T) {
+	a := []int{1, 2, 3, 4, 2}
+	b := []int{2, 4, 6}
+
+	if got, want := Difference(a, b), []int{1, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Difference = %v, want %v", got, want)
+	}
+	if got, want := Intersect(a, b), []int{2, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Intersect = %v, want %v", got, want)
+	}
+	if got := Intersect(a, nil); len(got) != 0 {
+		t.Errorf("Intersect with empty = %v, want empty", got)
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	got := GroupBy([]string{"apple", "avocado", "banana", "blueberry", "cherry"}, func(s string) byte { return s[0] })
+	want := map[byte][]string{
+		'a': {"apple", "avocado"},
+		'b': {"banana", "blueberry"},
+		'c': {"cherry"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupBy = %v, want %v", got, want)
+	}
+}
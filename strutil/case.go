@@ -0,0 +1,103 @@
+package strutil
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SplitWords 将标识符拆分为单词，是各大小写转换函数的基础。
+// 拆分规则：
+//   - 非字母数字字符（空格、_、-、. 等）作为分隔符并丢弃；
+//   - 小写转大写处拆分："fooBar" → foo, Bar；
+//   - 连续大写（缩写）在最后一个大写前拆分："HTTPServer" → HTTP, Server；
+//   - 数字归属前一个单词，数字后的大写字母开启新单词："base64Encode" → base64, Encode。
+func SplitWords(s string) []string {
+	runes := []rune(s)
+	var words []string
+	start := -1
+	flush := func(end int) {
+		if start >= 0 && end > start {
+			words = append(words, string(runes[start:end]))
+		}
+		start = -1
+	}
+
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush(i)
+			continue
+		}
+		if start < 0 {
+			start = i
+			continue
+		}
+		if unicode.IsUpper(r) {
+			prev := runes[i-1]
+			switch {
+			case !unicode.IsUpper(prev):
+				// fooBar、base64Encode
+				flush(i)
+				start = i
+			case i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+				// HTTPServer：在 S 处拆分
+				flush(i)
+				start = i
+			}
+		}
+	}
+	flush(len(runes))
+	return words
+}
+
+// ToSnakeCase 转换为 snake_case："UserID" → "user_id"，"HTTPServer" → "http_server"。
+// 常用于 JSON 字段名与数据库列名互转。
+func ToSnakeCase(s string) string {
+	return joinLower(SplitWords(s), "_")
+}
+
+// ToKebabCase 转换为 kebab-case："userName" → "user-name"。
+func ToKebabCase(s string) string {
+	return joinLower(SplitWords(s), "-")
+}
+
+// ToCamelCase 转换为 lowerCamelCase："user_id" → "userId"，"HTTPServer" → "httpServer"。
+// 缩写按普通单词处理（仅首字母大写），以保证转换可逆。
+func ToCamelCase(s string) string {
+	words := SplitWords(s)
+	var sb strings.Builder
+	for i, w := range words {
+		if i == 0 {
+			sb.WriteString(strings.ToLower(w))
+			continue
+		}
+		sb.WriteString(capitalize(w))
+	}
+	return sb.String()
+}
+
+// ToPascalCase 转换为 PascalCase："user_id" → "UserId"，"http-server" → "HttpServer"。
+func ToPascalCase(s string) string {
+	words := SplitWords(s)
+	var sb strings.Builder
+	for _, w := range words {
+		sb.WriteString(capitalize(w))
+	}
+	return sb.String()
+}
+
+// joinLower 将单词转小写后以 sep 连接。
+func joinLower(words []string, sep string) string {
+	for i, w := range words {
+		words[i] = strings.ToLower(w)
+	}
+	return strings.Join(words, sep)
+}
+
+// capitalize 首字母大写、其余小写。
+func capitalize(w string) string {
+	runes := []rune(strings.ToLower(w))
+	if len(runes) > 0 {
+		runes[0] = unicode.ToUpper(runes[0])
+	}
+	return string(runes)
+}
@@ -0,0 +1,55 @@
+package strutil
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"fooBar", []string{"foo", "Bar"}},
+		{"HTTPServer", []string{"HTTP", "Server"}},
+		{"userID", []string{"user", "ID"}},
+		{"base64Encode", []string{"base64", "Encode"}},
+		{"HTTP2Server", []string{"HTTP2", "Server"}},
+		{"  snake_case-and.kebab ", []string{"snake", "case", "and", "kebab"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		got := SplitWords(tt.input)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitWords(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestCaseConversion(t *testing.T) {
+	tests := []struct {
+		input                       string
+		snake, kebab, camel, pascal string
+	}{
+		{"UserID", "user_id", "user-id", "userId", "UserId"},
+		{"HTTPServer", "http_server", "http-server", "httpServer", "HttpServer"},
+		{"user_name", "user_name", "user-name", "userName", "UserName"},
+		{"created-at", "created_at", "created-at", "createdAt", "CreatedAt"},
+		{"ipv4Addr", "ipv4_addr", "ipv4-addr", "ipv4Addr", "Ipv4Addr"},
+		{"Sha256Sum", "sha256_sum", "sha256-sum", "sha256Sum", "Sha256Sum"},
+	}
+	for _, tt := range tests {
+		if got := ToSnakeCase(tt.input); got != tt.snake {
+			t.Errorf("ToSnakeCase(%q) = %q, want %q", tt.input, got, tt.snake)
+		}
+		if got := ToKebabCase(tt.input); got != tt.kebab {
+			t.Errorf("ToKebabCase(%q) = %q, want %q", tt.input, got, tt.kebab)
+		}
+		if got := ToCamelCase(tt.input); got != tt.camel {
+			t.Errorf("ToCamelCase(%q) = %q, want %q", tt.input, got, tt.camel)
+		}
+		if got := ToPascalCase(tt.input); got != tt.pascal {
+			t.Errorf("ToPascalCase(%q) = %q, want %q", tt.input, got, tt.pascal)
+		}
+	}
+}
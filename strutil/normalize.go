@@ -0,0 +1,70 @@
+package strutil
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// 全角 ASCII 字符（！到～）与半角字符（! 到 ~）的码点差值。
+const fullWidthOffset = 0xFEE0
+
+// NormalizeNFC 将字符串转为 Unicode NFC（标准组合）形式，"e" + 组合重音 → "é"。
+// 不改变字符含义，适合比较或哈希前统一写法。
+func NormalizeNFC(s string) string {
+	return norm.NFC.String(s)
+}
+
+// NormalizeNFKC 将字符串转为 Unicode NFKC（兼容组合）形式：在 NFC 基础上把兼容字符换成标准写法，
+// 如全角 "１２３ＡＢＣ" → "123ABC"、全角空格 → 空格、"①" → "1"、"ﬁ" → "fi"。
+// 会改变部分字符的外观（包括中文全角标点 "，" → ","），适合搜索、去重等匹配场景，不适合原文展示。
+func NormalizeNFKC(s string) string {
+	return norm.NFKC.String(s)
+}
+
+// ToHalfWidth 将全角 ASCII 字符（Ａ、１、！等，U+FF01–U+FF5E）与全角空格（U+3000）转为对应半角字符，
+// 其他字符（汉字、"。"、"、" 等中文标点）保持不变。
+//
+// 用法：
+//
+//	strutil.ToHalfWidth("价格：１２３ＲＭＢ") // "价格:123RMB"
+func ToHalfWidth(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '　':
+			return ' '
+		case r >= '！' && r <= '～':
+			return r - fullWidthOffset
+		}
+		return r
+	}, s)
+}
+
+// ToFullWidth 将半角 ASCII 可打印字符（! 到 ~）与空格转为对应全角字符，其他字符保持不变；ToHalfWidth 的逆操作。
+func ToFullWidth(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == ' ':
+			return '　'
+		case r >= '!' && r <= '~':
+			return r + fullWidthOffset
+		}
+		return r
+	}, s)
+}
+
+// RemoveInvisibleChars 删除不可见字符：零宽空格 / 零宽连接符、BOM、软连字符、方向控制符等格式字符（Unicode Cf），
+// 以及除 \t \n \r 外的控制字符。爬取的网页文本中常混有这类字符，导致看起来相同的字符串匹配或哈希不一致。
+// 注意零宽连接符被删除后，组合 emoji（如 👨‍👩‍👧）会拆成多个单独的 emoji。
+func RemoveInvisibleChars(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\t' || r == '\n' || r == '\r':
+			return r
+		case unicode.IsControl(r), unicode.Is(unicode.Cf, r):
+			return -1
+		}
+		return r
+	}, s)
+}
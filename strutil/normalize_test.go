@@ -0,0 +1,57 @@
+package strutil
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	decomposed := "cafe\u0301" // e + 组合重音符
+	if got := NormalizeNFC(decomposed); got != "café" {
+		t.Errorf("NormalizeNFC(%q) = %q", decomposed, got)
+	}
+	if got := NormalizeNFKC("１２３ＡＢＣ　①ﬁ，"); got != "123ABC 1fi," {
+		t.Errorf("NormalizeNFKC = %q", got)
+	}
+}
+
+func TestToHalfWidth(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"价格：１２３ＲＭＢ", "价格:123RMB"},
+		{"Ｈｅｌｌｏ　Ｗｏｒｌｄ！", "Hello World!"},
+		{"中文。、「」", "中文。、「」"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := ToHalfWidth(tt.input); got != tt.want {
+			t.Errorf("ToHalfWidth(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestToFullWidth(t *testing.T) {
+	if got := ToFullWidth("A1 b~中"); got != "Ａ１　ｂ～中" {
+		t.Errorf("ToFullWidth = %q", got)
+	}
+	s := "Hello, World! 123"
+	if got := ToHalfWidth(ToFullWidth(s)); got != s {
+		t.Errorf("round trip = %q, want %q", got, s)
+	}
+}
+
+func TestRemoveInvisibleChars(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"\ufeff你\u200b好\u200d", "你好"},
+		{"soft\u00adhyphen", "softhyphen"},
+		{"a\x00b\x1bc\u202ed", "abcd"},
+		{"line1\nline2\tx\r", "line1\nline2\tx\r"},
+	}
+	for _, tt := range tests {
+		if got := RemoveInvisibleChars(tt.input); got != tt.want {
+			t.Errorf("RemoveInvisibleChars(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
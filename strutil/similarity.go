@@ -0,0 +1,185 @@
+package strutil
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// ---------------------------------------------------------------------------
+// 字符串相似度
+// ---------------------------------------------------------------------------
+//
+// 均按字符（rune）计算，中英文混合文本可直接使用。距离越小越相似，相似度取值 [0, 1]，1 表示完全相同。
+// 各算法的适用场景：
+//   - Levenshtein：编辑距离，适合错别字、少量增删（商户名、地址）；
+//   - JaroWinkler：对相同前缀加权，适合短字符串（人名、品牌名）；
+//   - NGramCosine：基于 n-gram 词频的余弦相似度，对词序变化不敏感，适合较长的标题。
+
+// Similarity 返回 a、b 规范化后的相似度（基于编辑距离，[0, 1]）。
+// 规范化包括 NFKC（全角转半角等）、转小写、删除不可见字符、合并连续空白并去除首尾空白，
+// 因此 "ＡＢＣ  超市" 与 "abc 超市" 的相似度为 1。
+//
+// 用法：
+//
+//	if strutil.Similarity(crawled.Title, existing.Title) >= 0.9 {
+//	    // 视为同一条记录
+//	}
+func Similarity(a, b string) float64 {
+	return LevenshteinSimilarity(normalizeForMatch(a), normalizeForMatch(b))
+}
+
+// normalizeForMatch 将字符串规范化为用于模糊匹配的形式。
+func normalizeForMatch(s string) string {
+	s = strings.ToLower(NormalizeNFKC(RemoveInvisibleChars(s)))
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// Levenshtein 返回 a、b 的编辑距离：把 a 变为 b 所需的最少单字符插入、删除、替换次数。
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) < len(rb) {
+		ra, rb = rb, ra
+	}
+	if len(rb) == 0 {
+		return len(ra)
+	}
+
+	// 滚动数组，空间 O(min(len(a), len(b)))
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// LevenshteinSimilarity 返回基于编辑距离的相似度：1 - 距离 / 较长字符串的字符数。两个空串相似度为 1。
+func LevenshteinSimilarity(a, b string) float64 {
+	n := max(len([]rune(a)), len([]rune(b)))
+	if n == 0 {
+		return 1
+	}
+	return 1 - float64(Levenshtein(a, b))/float64(n)
+}
+
+// Jaro 返回 a、b 的 Jaro 相似度 [0, 1]。两个空串相似度为 1，其中一个为空时为 0。
+func Jaro(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+
+	window := max(max(len(ra), len(rb))/2-1, 0)
+	matchedA := make([]bool, len(ra))
+	matchedB := make([]bool, len(rb))
+	matches := 0
+	for i, r := range ra {
+		lo, hi := max(i-window, 0), min(i+window+1, len(rb))
+		for j := lo; j < hi; j++ {
+			if !matchedB[j] && rb[j] == r {
+				matchedA[i], matchedB[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	// 匹配字符按顺序比较，顺序不同的对数的一半为换位数
+	transpositions := 0
+	j := 0
+	for i, r := range ra {
+		if !matchedA[i] {
+			continue
+		}
+		for !matchedB[j] {
+			j++
+		}
+		if r != rb[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	return (m/float64(len(ra)) + m/float64(len(rb)) + (m-float64(transpositions)/2)/m) / 3
+}
+
+// JaroWinkler 返回 a、b 的 Jaro-Winkler 相似度 [0, 1]：在 Jaro 基础上按相同前缀（最多 4 个字符）加权，
+// 前缀缩放系数为常用的 0.1。
+func JaroWinkler(a, b string) float64 {
+	sim := Jaro(a, b)
+	prefix := 0
+	ra, rb := []rune(a), []rune(b)
+	for prefix < min(len(ra), len(rb), 4) && ra[prefix] == rb[prefix] {
+		prefix++
+	}
+	return sim + float64(prefix)*0.1*(1-sim)
+}
+
+// NGramCosine 返回 a、b 的字符 n-gram 词频向量的余弦相似度 [0, 1]，n <= 0 时默认 2。
+// 忽略空白；字符数不足 n 的字符串整体作为一个 n-gram。两个空串相似度为 1，其中一个为空时为 0。
+//
+// 用法：
+//
+//	strutil.NGramCosine("2026 年春季新品发布会", "新品发布会（2026 春季）", 2)
+func NGramCosine(a, b string, n int) float64 {
+	if n <= 0 {
+		n = 2
+	}
+	ga, gb := nGrams(a, n), nGrams(b, n)
+	if len(ga) == 0 && len(gb) == 0 {
+		return 1
+	}
+	if len(ga) == 0 || len(gb) == 0 {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for g, ca := range ga {
+		dot += float64(ca * gb[g])
+		normA += float64(ca * ca)
+	}
+	for _, cb := range gb {
+		normB += float64(cb * cb)
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// nGrams 统计 s（去除空白后）的字符 n-gram 词频。
+func nGrams(s string, n int) map[string]int {
+	runes := []rune(strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s))
+	if len(runes) == 0 {
+		return nil
+	}
+	if len(runes) < n {
+		return map[string]int{string(runes): 1}
+	}
+	grams := make(map[string]int, len(runes)-n+1)
+	for i := 0; i+n <= len(runes); i++ {
+		grams[string(runes[i:i+n])]++
+	}
+	return grams
+}
@@ -0,0 +1,85 @@
+package strutil
+
+import (
+	"math"
+	"testing"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-3
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"kitten", "sitting", 3},
+		{"", "abc", 3},
+		{"abc", "", 3},
+		{"", "", 0},
+		{"北京市朝阳区", "北京朝阳区", 1},
+		{"flaw", "lawn", 2},
+	}
+	for _, tt := range tests {
+		if got := Levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("Levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+	if got := LevenshteinSimilarity("北京市朝阳区", "北京朝阳区"); !almostEqual(got, 5.0/6) {
+		t.Errorf("LevenshteinSimilarity = %f", got)
+	}
+	if got := LevenshteinSimilarity("", ""); got != 1 {
+		t.Errorf("LevenshteinSimilarity empty = %f", got)
+	}
+}
+
+func TestJaroWinkler(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		jaro, jw float64
+	}{
+		{"MARTHA", "MARHTA", 0.944, 0.961},
+		{"DWAYNE", "DUANE", 0.822, 0.840},
+		{"DIXON", "DICKSONX", 0.767, 0.813},
+		{"abc", "xyz", 0, 0},
+		{"", "", 1, 1},
+		{"abc", "", 0, 0},
+	}
+	for _, tt := range tests {
+		if got := Jaro(tt.a, tt.b); !almostEqual(got, tt.jaro) {
+			t.Errorf("Jaro(%q, %q) = %.3f, want %.3f", tt.a, tt.b, got, tt.jaro)
+		}
+		if got := JaroWinkler(tt.a, tt.b); !almostEqual(got, tt.jw) {
+			t.Errorf("JaroWinkler(%q, %q) = %.3f, want %.3f", tt.a, tt.b, got, tt.jw)
+		}
+	}
+}
+
+func TestNGramCosine(t *testing.T) {
+	if got := NGramCosine("新品发布会", "新品发布会", 2); !almostEqual(got, 1) {
+		t.Errorf("identical = %f", got)
+	}
+	// 词序变化："abc" 的 bigram 为 ab、bc，"bca" 为 bc、ca，共有 bc
+	if got := NGramCosine("abc", "bca", 2); !almostEqual(got, 0.5) {
+		t.Errorf("reordered = %f, want 0.5", got)
+	}
+	if got := NGramCosine("a", "a b", 0); !almostEqual(got, 0) {
+		t.Errorf("short = %f, want 0", got)
+	}
+	if got := NGramCosine("", "", 2); got != 1 {
+		t.Errorf("empty = %f", got)
+	}
+	if got := NGramCosine("abc", "", 2); got != 0 {
+		t.Errorf("one empty = %f", got)
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	if got := Similarity("ＡＢＣ  超市\u200b", " abc 超市"); got != 1 {
+		t.Errorf("Similarity normalized = %f, want 1", got)
+	}
+	if got := Similarity("星巴克咖啡", "星巴克"); !almostEqual(got, 0.6) {
+		t.Errorf("Similarity = %f, want 0.6", got)
+	}
+}
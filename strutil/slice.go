@@ -0,0 +1,50 @@
+package strutil
+
+import (
+	"strings"
+
+	"github.com/pylemonorg/gotools/sliceutil"
+)
+
+// Dedupe 去除重复字符串并保持首次出现的顺序，返回新切片。
+func Dedupe(ss []string) []string {
+	return sliceutil.Unique(ss)
+}
+
+// Contains 判断 ss 中是否包含 s。
+func Contains(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter 返回满足 keep 的元素组成的新切片，保持原有顺序。
+func Filter(ss []string, keep func(string) bool) []string {
+	return sliceutil.Filter(ss, keep)
+}
+
+// ChunkStrings 将 ss 按每批 size 个拆分，最后一批可能不足 size。
+// 等同于 sliceutil.Chunk。size <= 0 时 panic。
+//
+// 用法（OBS DeleteObjects 单次最多 1000 个 key）：
+//
+//	for _, batch := range strutil.ChunkStrings(keys, 1000) {
+//	    // 批量删除 batch
+//	}
+func ChunkStrings(ss []string, size int) [][]string {
+	return sliceutil.Chunk(ss, size)
+}
+
+// JoinNonEmpty 以 sep 连接 parts 中的非空字符串（仅含空白的也视为空）。
+//
+// 用法：
+//
+//	strutil.JoinNonEmpty("/", "logs", "", "2024") // "logs/2024"
+func JoinNonEmpty(sep string, parts ...string) string {
+	return strings.Join(Filter(parts, func(s string) bool {
+		return strings.TrimSpace(s) != ""
+	}), sep)
+}
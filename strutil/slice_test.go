@@ -0,0 +1,72 @@
+package strutil
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDedupe(t *testing.T) {
+	got := Dedupe([]string{"b", "a", "b", "c", "a"})
+	want := []string{"b", "a", "c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Dedupe = %q, want %q", got, want)
+	}
+	if got := Dedupe(nil); len(got) != 0 {
+		t.Errorf("Dedupe(nil) = %q, want empty", got)
+	}
+}
+
+func TestContains(t *testing.T) {
+	ss := []string{"a", "b"}
+	if !Contains(ss, "b") {
+		t.Error("Contains should find b")
+	}
+	if Contains(ss, "c") {
+		t.Error("Contains should not find c")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	got := Filter([]string{"a.json", "b.txt", "c.json"}, func(s string) bool {
+		return strings.HasSuffix(s, ".json")
+	})
+	want := []string{"a.json", "c.json"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Filter = %q, want %q", got, want)
+	}
+}
+
+func TestChunkStrings(t *testing.T) {
+	tests := []struct {
+		input []string
+		size  int
+		want  [][]string
+	}{
+		{[]string{"a", "b", "c", "d", "e"}, 2, [][]string{{"a", "b"}, {"c", "d"}, {"e"}}},
+		{[]string{"a", "b"}, 2, [][]string{{"a", "b"}}},
+		{[]string{"a"}, 10, [][]string{{"a"}}},
+		{nil, 3, [][]string{}},
+	}
+	for _, tt := range tests {
+		got := ChunkStrings(tt.input, tt.size)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ChunkStrings(%q, %d) = %q, want %q", tt.input, tt.size, got, tt.want)
+		}
+	}
+
+	chunks := ChunkStrings([]string{"a", "b", "c"}, 2)
+	chunks[0] = append(chunks[0], "x")
+	if chunks[1][0] != "c" {
+		t.Error("appending to a chunk must not overwrite the next chunk")
+	}
+}
+
+func TestJoinNonEmpty(t *testing.T) {
+	if got := JoinNonEmpty("/", "logs", "", " ", "2024"); got != "logs/2024" {
+		t.Errorf("JoinNonEmpty = %q, want %q", got, "logs/2024")
+	}
+	if got := JoinNonEmpty(","); got != "" {
+		t.Errorf("JoinNonEmpty() = %q, want empty", got)
+	}
+}
@@ -0,0 +1,120 @@
+package strutil
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/pylemonorg/gotools/hashutil"
+)
+
+// maxFilenameBytes 为常见文件系统单个文件名的字节上限。
+const maxFilenameBytes = 255
+
+// transliterations 为 NFKD 分解后仍非 ASCII 的常见拉丁字母转写表。
+var transliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'Æ': "ae", 'œ': "oe", 'Œ': "oe",
+	'ø': "o", 'Ø': "o", 'đ': "d", 'Đ': "d", 'ł': "l", 'Ł': "l",
+	'þ': "th", 'Þ': "th", 'ð': "d", 'Ð': "d",
+}
+
+// RandomAlphaNum 生成长度为 n 的随机字母数字串（[0-9A-Za-z]），基于 crypto/rand。
+func RandomAlphaNum(n int) string {
+	return hashutil.SecureRandomString(n)
+}
+
+// Slugify 将标题转换为 URL 安全的 slug：转小写、去除重音（"Café" → "cafe"），
+// 其余非字母数字字符替换为 "-"，并合并连续的 "-"。
+// 无法转写的字符（如中文）会被移除，结果可能为空串，调用方需自行回退（例如使用 hashutil.NewULID）。
+//
+// 用法：
+//
+//	strutil.Slugify("Hello, World! Ünïcödé 2024") // "hello-world-unicode-2024"
+func Slugify(s string) string {
+	var sb strings.Builder
+	pendingDash := false
+	write := func(str string) {
+		if pendingDash && sb.Len() > 0 {
+			sb.WriteByte('-')
+		}
+		pendingDash = false
+		sb.WriteString(str)
+	}
+
+	for _, r := range norm.NFKD.String(s) {
+		switch {
+		case r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			write(string(unicode.ToLower(r)))
+		case unicode.Is(unicode.Mn, r):
+			// 分解后的重音符号，直接丢弃
+		default:
+			if t, ok := transliterations[r]; ok {
+				write(t)
+			} else {
+				pendingDash = true
+			}
+		}
+	}
+	return sb.String()
+}
+
+// SanitizeFilename 清理文件名使其在 Linux / Windows / 对象存储中均可安全使用：
+//   - 路径分隔符与 Windows 保留字符（\ / : * ? " < > |）及控制字符替换为 "_"；
+//   - 去除首尾空白和点号；
+//   - Windows 保留设备名（CON、NUL、COM1 等）前加 "_"；
+//   - 超过 255 字节时按字符边界截断（尽量保留扩展名）。
+//
+// 中文等 Unicode 字符会保留。结果为空时返回 "_"。
+func SanitizeFilename(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || strings.ContainsRune(`\/:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, s)
+	s = strings.Trim(s, " \t.")
+	if s == "" {
+		return "_"
+	}
+
+	base := s
+	if i := strings.IndexByte(base, '.'); i >= 0 {
+		base = base[:i]
+	}
+	if isReservedWindowsName(base) {
+		s = "_" + s
+	}
+
+	if len(s) > maxFilenameBytes {
+		ext := ""
+		if i := strings.LastIndexByte(s, '.'); i > 0 && len(s)-i <= 16 {
+			ext = s[i:]
+		}
+		s = truncateBytes(s[:len(s)-len(ext)], maxFilenameBytes-len(ext)) + ext
+	}
+	return s
+}
+
+// isReservedWindowsName 判断是否为 Windows 保留设备名（不区分大小写）。
+func isReservedWindowsName(name string) bool {
+	switch strings.ToUpper(strings.TrimSpace(name)) {
+	case "CON", "PRN", "AUX", "NUL",
+		"COM1", "COM2", "COM3", "COM4", "COM5", "COM6", "COM7", "COM8", "COM9",
+		"LPT1", "LPT2", "LPT3", "LPT4", "LPT5", "LPT6", "LPT7", "LPT8", "LPT9":
+		return true
+	}
+	return false
+}
+
+// truncateBytes 将 s 截断到不超过 n 字节，且不切断多字节字符。
+func truncateBytes(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,74 @@
+package strutil
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+const alphanumericForTest = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+func TestRandomAlphaNum(t *testing.T) {
+	s := RandomAlphaNum(32)
+	if len(s) != 32 {
+		t.Fatalf("RandomAlphaNum(32) length = %d", len(s))
+	}
+	for _, r := range s {
+		if !strings.ContainsRune(alphanumericForTest, r) {
+			t.Errorf("RandomAlphaNum produced non-alphanumeric rune %q", r)
+		}
+	}
+	if RandomAlphaNum(32) == s {
+		t.Error("RandomAlphaNum returned the same value twice")
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Hello, World!", "hello-world"},
+		{"Hello, World! Ünïcödé 2024", "hello-world-unicode-2024"},
+		{"  --Already-slugged--  ", "already-slugged"},
+		{"Straße & Smørrebrød", "strasse-smorrebrod"},
+		{"Go 语言 入门", "go"},
+		{"中文标题", ""},
+		{"ＡＢＣ１２３", "abc123"},
+	}
+	for _, tt := range tests {
+		if got := Slugify(tt.input); got != tt.want {
+			t.Errorf("Slugify(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestSanitizeFilename(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"report.pdf", "report.pdf"},
+		{"a/b\\c:d*e?.txt", "a_b_c_d_e_.txt"},
+		{"  .hidden. ", "hidden"},
+		{"报告 2024.xlsx", "报告 2024.xlsx"},
+		{"CON.txt", "_CON.txt"},
+		{"nul", "_nul"},
+		{"...", "_"},
+		{"line\nbreak", "line_break"},
+	}
+	for _, tt := range tests {
+		if got := SanitizeFilename(tt.input); got != tt.want {
+			t.Errorf("SanitizeFilename(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+
+	long := strings.Repeat("中", 200) + ".json"
+	got := SanitizeFilename(long)
+	if len(got) > maxFilenameBytes {
+		t.Errorf("SanitizeFilename long name has %d bytes, want <= %d", len(got), maxFilenameBytes)
+	}
+	if !strings.HasSuffix(got, ".json") || !utf8.ValidString(got) {
+		t.Errorf("SanitizeFilename long name = %q, want valid UTF-8 ending in .json", got)
+	}
+}
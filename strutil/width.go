@@ -0,0 +1,97 @@
+package strutil
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/width"
+)
+
+// RuneWidth 返回单个字符在等宽终端中的显示宽度：
+// 中日韩文字、全角符号、emoji 等宽字符为 2，组合字符与控制字符为 0，其余为 1。
+func RuneWidth(r rune) int {
+	switch {
+	case r < 0x20 || r == 0x7f:
+		return 0
+	case r < 0x7f:
+		return 1
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	}
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+	return 1
+}
+
+// DisplayWidth 计算字符串在等宽终端中的显示宽度，中英文混排时用于对齐表格列。
+func DisplayWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		n += RuneWidth(r)
+	}
+	return n
+}
+
+// TruncateRunes 按字符数截断字符串，不会截断在多字节字符中间。
+func TruncateRunes(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	i := 0
+	for pos := range s {
+		if i == n {
+			return s[:pos]
+		}
+		i++
+	}
+	return s
+}
+
+// TruncateDisplayWidth 按显示宽度截断字符串，超出时在末尾追加 ellipsis，
+// 结果（含 ellipsis）的显示宽度不超过 width。未超出时原样返回。
+//
+// 用法：
+//
+//	strutil.TruncateDisplayWidth("中文English混排", 10, "...") // "中文Eng..."
+func TruncateDisplayWidth(s string, width int, ellipsis string) string {
+	if DisplayWidth(s) <= width {
+		return s
+	}
+	limit := width - DisplayWidth(ellipsis)
+	if limit <= 0 {
+		return TruncateDisplayWidth(ellipsis, width, "")
+	}
+	w := 0
+	for pos, r := range s {
+		rw := RuneWidth(r)
+		if w+rw > limit {
+			return s[:pos] + ellipsis
+		}
+		w += rw
+	}
+	return s + ellipsis
+}
+
+// PadRight 按显示宽度在右侧填充空格，正确处理中文字符；已达到 width 时原样返回。
+func PadRight(s string, width int) string {
+	w := DisplayWidth(s)
+	if w >= width {
+		return s
+	}
+	return s + strings.Repeat(" ", width-w)
+}
+
+// PadLeft 按显示宽度在左侧填充空格，常用于数字列右对齐。
+func PadLeft(s string, width int) string {
+	w := DisplayWidth(s)
+	if w >= width {
+		return s
+	}
+	return strings.Repeat(" ", width-w) + s
+}
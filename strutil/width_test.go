@@ -0,0 +1,81 @@
+package strutil
+
+import "testing"
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{"hello", 5},
+		{"你好", 4},
+		{"CPU使用率", 9},
+		{"", 0},
+		{"abc你好def", 10},
+		{"ＡＢ", 4},
+		{"café", 4},
+		{"é", 1},
+	}
+	for _, tt := range tests {
+		if got := DisplayWidth(tt.input); got != tt.want {
+			t.Errorf("DisplayWidth(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestTruncateRunes(t *testing.T) {
+	tests := []struct {
+		input string
+		n     int
+		want  string
+	}{
+		{"hello", 3, "hel"},
+		{"你好世界", 2, "你好"},
+		{"short", 10, "short"},
+		{"abc", 0, ""},
+	}
+	for _, tt := range tests {
+		if got := TruncateRunes(tt.input, tt.n); got != tt.want {
+			t.Errorf("TruncateRunes(%q, %d) = %q, want %q", tt.input, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestTruncateDisplayWidth(t *testing.T) {
+	tests := []struct {
+		input    string
+		width    int
+		ellipsis string
+		want     string
+	}{
+		{"中文English混排", 10, "...", "中文Eng..."},
+		{"你好世界", 5, "…", "你好…"},
+		{"你好世界", 5, "", "你好"},
+		{"fits", 4, "...", "fits"},
+		{"abcdef", 2, "...", ".."},
+	}
+	for _, tt := range tests {
+		got := TruncateDisplayWidth(tt.input, tt.width, tt.ellipsis)
+		if got != tt.want {
+			t.Errorf("TruncateDisplayWidth(%q, %d, %q) = %q, want %q", tt.input, tt.width, tt.ellipsis, got, tt.want)
+		}
+		if DisplayWidth(got) > tt.width {
+			t.Errorf("TruncateDisplayWidth(%q, %d, %q) width %d exceeds %d", tt.input, tt.width, tt.ellipsis, DisplayWidth(got), tt.width)
+		}
+	}
+}
+
+func TestPad(t *testing.T) {
+	if got := PadRight("你好", 6); got != "你好  " {
+		t.Errorf("PadRight = %q", got)
+	}
+	if got := PadLeft("你好", 6); got != "  你好" {
+		t.Errorf("PadLeft = %q", got)
+	}
+	if got := PadRight("hello", 5); got != "hello" {
+		t.Errorf("PadRight should not pad when width reached, got %q", got)
+	}
+	if got := PadLeft("toolong", 3); got != "toolong" {
+		t.Errorf("PadLeft should not truncate, got %q", got)
+	}
+}
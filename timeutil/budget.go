@@ -0,0 +1,191 @@
+package timeutil
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pylemonorg/gotools/logger"
+)
+
+// BudgetPhase 时间预算中的一个阶段。
+type BudgetPhase struct {
+	Name     string
+	Allotted time.Duration // 分配到的时长（已按剩余预算截断）
+	Used     time.Duration // 实际耗时（未结束时为截至当前的耗时）
+	Done     bool          // 是否已调用 done
+	TimedOut bool          // 阶段结束时 context 是否已超时（阶段分配或总预算耗尽）
+}
+
+// Budget 请求级时间预算：从总截止时间出发，按阶段切分并派生带超时的 context，
+// 记录各阶段分配与实际耗时。适合依次调用 OBS、Postgres、Redis 等多个下游的请求流水线，
+// 避免前面的阶段耗尽时间导致后面的阶段没有机会执行。并发安全。
+//
+// 用法：
+//
+//	b := timeutil.NewBudget(ctx, 3*time.Second)
+//	defer b.Cancel()
+//
+//	obsCtx, done := b.AllotFraction("obs", 0.3)
+//	data, err := fetchFromOBS(obsCtx)
+//	done()
+//
+//	pgCtx, done := b.AllotFraction("pg", 0.5)
+//	err = saveToPG(pgCtx, data)
+//	done()
+//
+//	redisCtx, done := b.AllotRemaining("redis")
+//	err = cacheToRedis(redisCtx, data)
+//	done()
+//
+//	b.Log("HandleUpload") // HandleUpload 时间预算: obs 320ms/900ms | pg 1.20秒/1.50秒 | redis 15ms/1.48秒 | 已用 1.54秒 / 预算 3.00秒，剩余 1.46秒
+type Budget struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	start  time.Time
+	total  time.Duration
+
+	mu     sync.Mutex
+	phases []*budgetPhase
+}
+
+// budgetPhase 阶段的内部状态。
+type budgetPhase struct {
+	BudgetPhase
+	start time.Time
+}
+
+// NewBudget 创建总时长为 total 的时间预算，派生的 context 截止时间不晚于 parent 的截止时间。
+func NewBudget(parent context.Context, total time.Duration) *Budget {
+	return NewBudgetUntil(parent, time.Now().Add(total))
+}
+
+// NewBudgetUntil 创建截止于 deadline 的时间预算（如使用上游请求携带的截止时间）。
+func NewBudgetUntil(parent context.Context, deadline time.Time) *Budget {
+	if parent == nil {
+		parent = context.Background()
+	}
+	if d, ok := parent.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	now := time.Now()
+	ctx, cancel := context.WithDeadline(parent, deadline)
+	return &Budget{ctx: ctx, cancel: cancel, start: now, total: deadline.Sub(now)}
+}
+
+// Context 返回整个预算的 context，截止于总截止时间。
+func (b *Budget) Context() context.Context {
+	return b.ctx
+}
+
+// Cancel 释放预算关联的 context，应在请求结束时调用（可重复调用）。
+func (b *Budget) Cancel() {
+	b.cancel()
+}
+
+// Deadline 返回总截止时间。
+func (b *Budget) Deadline() time.Time {
+	d, _ := b.ctx.Deadline()
+	return d
+}
+
+// Total 返回总预算时长。
+func (b *Budget) Total() time.Duration {
+	return b.total
+}
+
+// Elapsed 返回自创建以来已消耗的时长。
+func (b *Budget) Elapsed() time.Duration {
+	return time.Since(b.start)
+}
+
+// Remaining 返回剩余时长，已耗尽时返回 0。
+func (b *Budget) Remaining() time.Duration {
+	return max(time.Until(b.Deadline()), 0)
+}
+
+// Exhausted 判断预算是否已耗尽（到达截止时间或已取消）。
+func (b *Budget) Exhausted() bool {
+	return b.ctx.Err() != nil
+}
+
+// Allot 为名为 name 的阶段分配 d 时长（不超过剩余预算），返回派生的 context 与结束函数。
+// 阶段结束时必须调用 done（可重复调用），用于记录耗时并释放 context。
+func (b *Budget) Allot(name string, d time.Duration) (context.Context, func()) {
+	d = min(max(d, 0), b.Remaining())
+
+	p := &budgetPhase{BudgetPhase: BudgetPhase{Name: name, Allotted: d}, start: time.Now()}
+	ctx, cancel := context.WithTimeout(b.ctx, d)
+
+	b.mu.Lock()
+	b.phases = append(b.phases, p)
+	b.mu.Unlock()
+
+	var once sync.Once
+	done := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			p.Used = time.Since(p.start)
+			p.Done = true
+			p.TimedOut = ctx.Err() == context.DeadlineExceeded
+			b.mu.Unlock()
+			cancel()
+		})
+	}
+	return ctx, done
+}
+
+// AllotFraction 按总预算的比例 frac（0~1）为阶段分配时长，不超过剩余预算。
+// 前面的阶段提前完成时，节省的时间留给后续阶段（可配合 AllotRemaining 使用）。
+func (b *Budget) AllotFraction(name string, frac float64) (context.Context, func()) {
+	return b.Allot(name, time.Duration(float64(b.total)*frac))
+}
+
+// AllotRemaining 将全部剩余预算分配给阶段，通常用于最后一个阶段。
+func (b *Budget) AllotRemaining(name string) (context.Context, func()) {
+	return b.Allot(name, b.Remaining())
+}
+
+// Phases 返回各阶段（按分配顺序）的副本，未结束阶段的 Used 为截至当前的耗时。
+func (b *Budget) Phases() []BudgetPhase {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	phases := make([]BudgetPhase, len(b.phases))
+	for i, p := range b.phases {
+		phases[i] = p.BudgetPhase
+		if !p.Done {
+			phases[i].Used = time.Since(p.start)
+		}
+	}
+	return phases
+}
+
+// Report 返回各阶段耗时 / 分配时长及总体消耗的单行报告，超时的阶段标注 (超时)，未结束的标注 (进行中)。
+func (b *Budget) Report() string {
+	phases := b.Phases()
+	parts := make([]string, 0, len(phases)+1)
+	for _, p := range phases {
+		s := fmt.Sprintf("%s %s/%s", p.Name, FormatDuration(p.Used), FormatDuration(p.Allotted))
+		switch {
+		case !p.Done:
+			s += " (进行中)"
+		case p.TimedOut:
+			s += " (超时)"
+		}
+		parts = append(parts, s)
+	}
+	parts = append(parts, fmt.Sprintf("已用 %s / 预算 %s，剩余 %s",
+		FormatDuration(b.Elapsed()), FormatDuration(b.total), FormatDuration(b.Remaining())))
+	return strings.Join(parts, " | ")
+}
+
+// Log 记录 Report 的内容：已到达截止时间时为 warn 级别，否则为 info 级别。
+func (b *Budget) Log(name string) {
+	if b.Remaining() == 0 {
+		logger.Warnf("%s 时间预算已耗尽: %s", name, b.Report())
+		return
+	}
+	logger.Infof("%s 时间预算: %s", name, b.Report())
+}
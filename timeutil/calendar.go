@@ -0,0 +1,236 @@
+package timeutil
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Calendar 节假日日历，用于判断工作日 / 交易日。
+// 工作日：非节假日的周一至周五，以及调休上班的周末；交易日：非节假日的周一至周五（调休上班的周末不开市）。
+// 日期按日历的时区（默认 CST）计算，可通过 AddHolidays / AddWorkdays 补充或覆盖数据，并发安全。
+type Calendar struct {
+	mu       sync.RWMutex
+	loc      *time.Location
+	holidays map[int]string // yyyymmdd → 节日名
+	workdays map[int]bool   // 调休上班的周末
+}
+
+// NewCalendar 创建空日历（仅周末休息），loc 为 nil 时使用 CST。
+func NewCalendar(loc *time.Location) *Calendar {
+	if loc == nil {
+		loc = CST
+	}
+	return &Calendar{loc: loc, holidays: make(map[int]string), workdays: make(map[int]bool)}
+}
+
+// NewCNCalendar 创建预置中国法定节假日与调休安排的日历（CST），数据来自国务院办公厅每年发布的放假通知。
+// 未预置的年份只按周末判断，可通过 AddHolidays / AddWorkdays 补充。
+func NewCNCalendar() *Calendar {
+	c := NewCalendar(CST)
+	for _, h := range cnHolidays {
+		if err := c.AddHolidays(h.name, h.days...); err != nil {
+			panic(err)
+		}
+		if err := c.AddWorkdays(h.workdays...); err != nil {
+			panic(err)
+		}
+	}
+	return c
+}
+
+// CNCalendar 包级函数 IsWorkday / NextWorkday 等使用的默认日历（预置中国法定节假日）。
+// 新一年的放假通知发布后，可直接在其上补充：
+//
+//	timeutil.CNCalendar.AddHolidays("元旦", "2027-01-01~2027-01-03")
+var CNCalendar = NewCNCalendar()
+
+// AddHolidays 添加节假日，dates 为 "2006-01-02" 格式的日期或 "2006-01-02~2006-01-08" 格式的闭区间。
+// 同一天已标记为调休上班时会被取消。
+func (c *Calendar) AddHolidays(name string, dates ...string) error {
+	days, err := parseDateSpecs(dates)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, d := range days {
+		c.holidays[d] = name
+		delete(c.workdays, d)
+	}
+	return nil
+}
+
+// AddWorkdays 添加调休上班日（通常为周末），格式同 AddHolidays。同一天已标记为节假日时会被取消。
+func (c *Calendar) AddWorkdays(dates ...string) error {
+	days, err := parseDateSpecs(dates)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, d := range days {
+		c.workdays[d] = true
+		delete(c.holidays, d)
+	}
+	return nil
+}
+
+// Holiday 返回 t 所在日期的节日名，非节假日时返回 false（普通周末不算节假日）。
+func (c *Calendar) Holiday(t time.Time) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	name, ok := c.holidays[c.dateKey(t)]
+	return name, ok
+}
+
+// IsWorkday 判断 t 所在日期是否为工作日（含调休上班的周末）。
+func (c *Calendar) IsWorkday(t time.Time) bool {
+	t = t.In(c.loc)
+	key := c.dateKey(t)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.workdays[key] {
+		return true
+	}
+	if _, ok := c.holidays[key]; ok {
+		return false
+	}
+	return !isWeekend(t)
+}
+
+// IsTradingDay 判断 t 所在日期是否为交易日（周一至周五且非节假日，调休上班的周末不是交易日）。
+func (c *Calendar) IsTradingDay(t time.Time) bool {
+	t = t.In(c.loc)
+	if isWeekend(t) {
+		return false
+	}
+	_, holiday := c.Holiday(t)
+	return !holiday
+}
+
+// NextWorkday 返回 t 所在日期之后（不含当天）的第一个工作日的 00:00:00（日历时区）。
+func (c *Calendar) NextWorkday(t time.Time) time.Time {
+	return c.next(t, c.IsWorkday)
+}
+
+// NextTradingDay 返回 t 所在日期之后（不含当天）的第一个交易日的 00:00:00（日历时区）。
+func (c *Calendar) NextTradingDay(t time.Time) time.Time {
+	return c.next(t, c.IsTradingDay)
+}
+
+// AddWorkdaysTo 返回 t 所在日期之后第 n 个工作日的 00:00:00（n <= 0 时返回当天 00:00:00）。
+//
+// 用法：
+//
+//	due := timeutil.CNCalendar.AddWorkdaysTo(time.Now(), 3) // 3 个工作日后
+func (c *Calendar) AddWorkdaysTo(t time.Time, n int) time.Time {
+	day := StartOfDay(t, c.loc)
+	for ; n > 0; n-- {
+		day = c.NextWorkday(day)
+	}
+	return day
+}
+
+// next 从 t 的次日开始逐日查找第一个满足 match 的日期。
+func (c *Calendar) next(t time.Time, match func(time.Time) bool) time.Time {
+	day := StartOfDay(t, c.loc)
+	for {
+		day = time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, c.loc)
+		if match(day) {
+			return day
+		}
+	}
+}
+
+// dateKey 返回 t 在日历时区的 yyyymmdd。
+func (c *Calendar) dateKey(t time.Time) int {
+	t = t.In(c.loc)
+	return t.Year()*10000 + int(t.Month())*100 + t.Day()
+}
+
+// isWeekend 判断 t 是否为周六或周日。
+func isWeekend(t time.Time) bool {
+	wd := t.Weekday()
+	return wd == time.Saturday || wd == time.Sunday
+}
+
+// parseDateSpecs 解析日期或日期区间列表，返回 yyyymmdd 列表。
+func parseDateSpecs(specs []string) ([]int, error) {
+	var days []int
+	for _, spec := range specs {
+		from, to, isRange := strings.Cut(spec, "~")
+		start, err := time.Parse(LayoutDate, strings.TrimSpace(from))
+		if err != nil {
+			return nil, fmt.Errorf("timeutil: 非法的日期 %q: %w", spec, err)
+		}
+		end := start
+		if isRange {
+			if end, err = time.Parse(LayoutDate, strings.TrimSpace(to)); err != nil {
+				return nil, fmt.Errorf("timeutil: 非法的日期 %q: %w", spec, err)
+			}
+			if end.Before(start) {
+				return nil, fmt.Errorf("timeutil: 日期区间 %q 结束早于开始", spec)
+			}
+		}
+		for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+			days = append(days, d.Year()*10000+int(d.Month())*100+d.Day())
+		}
+	}
+	return days, nil
+}
+
+// ---------------------------------------------------------------------------
+// 包级函数（使用 CNCalendar）
+// ---------------------------------------------------------------------------
+
+// IsWorkday 按 CNCalendar 判断 t 所在日期是否为工作日。
+func IsWorkday(t time.Time) bool { return CNCalendar.IsWorkday(t) }
+
+// IsTradingDay 按 CNCalendar 判断 t 所在日期是否为交易日。
+func IsTradingDay(t time.Time) bool { return CNCalendar.IsTradingDay(t) }
+
+// NextWorkday 按 CNCalendar 返回 t 之后的第一个工作日（CST 00:00:00）。
+func NextWorkday(t time.Time) time.Time { return CNCalendar.NextWorkday(t) }
+
+// NextTradingDay 按 CNCalendar 返回 t 之后的第一个交易日（CST 00:00:00）。
+func NextTradingDay(t time.Time) time.Time { return CNCalendar.NextTradingDay(t) }
+
+// ---------------------------------------------------------------------------
+// 预置数据
+// ---------------------------------------------------------------------------
+
+// cnHolidays 中国法定节假日与调休上班日。
+var cnHolidays = []struct {
+	name     string
+	days     []string
+	workdays []string
+}{
+	// 2024
+	{"元旦", []string{"2024-01-01"}, nil},
+	{"春节", []string{"2024-02-10~2024-02-17"}, []string{"2024-02-04", "2024-02-18"}},
+	{"清明节", []string{"2024-04-04~2024-04-06"}, []string{"2024-04-07"}},
+	{"劳动节", []string{"2024-05-01~2024-05-05"}, []string{"2024-04-28", "2024-05-11"}},
+	{"端午节", []string{"2024-06-10"}, nil},
+	{"中秋节", []string{"2024-09-15~2024-09-17"}, []string{"2024-09-14"}},
+	{"国庆节", []string{"2024-10-01~2024-10-07"}, []string{"2024-09-29", "2024-10-12"}},
+
+	// 2025
+	{"元旦", []string{"2025-01-01"}, nil},
+	{"春节", []string{"2025-01-28~2025-02-04"}, []string{"2025-01-26", "2025-02-08"}},
+	{"清明节", []string{"2025-04-04~2025-04-06"}, nil},
+	{"劳动节", []string{"2025-05-01~2025-05-05"}, []string{"2025-04-27"}},
+	{"端午节", []string{"2025-05-31~2025-06-02"}, nil},
+	{"国庆节、中秋节", []string{"2025-10-01~2025-10-08"}, []string{"2025-09-28", "2025-10-11"}},
+
+	// 2026
+	{"元旦", []string{"2026-01-01~2026-01-03"}, []string{"2026-01-04"}},
+	{"春节", []string{"2026-02-15~2026-02-23"}, []string{"2026-02-14", "2026-02-28"}},
+	{"清明节", []string{"2026-04-04~2026-04-06"}, nil},
+	{"劳动节", []string{"2026-05-01~2026-05-05"}, []string{"2026-05-09"}},
+	{"端午节", []string{"2026-06-19~2026-06-21"}, nil},
+	{"中秋节", []string{"2026-09-25~2026-09-27"}, nil},
+	{"国庆节", []string{"2026-10-01~2026-10-07"}, []string{"2026-09-20", "2026-10-10"}},
+}
@@ -0,0 +1,196 @@
+package timeutil
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule 调度规则，Next 返回 t 之后的下一次触发时间，返回零值表示不再触发。
+type Schedule interface {
+	Next(t time.Time) time.Time
+}
+
+// everySchedule 固定间隔。
+type everySchedule struct {
+	interval time.Duration
+}
+
+// Next 实现 Schedule。
+func (s everySchedule) Next(t time.Time) time.Time {
+	return t.Add(s.interval)
+}
+
+// dailySchedule 每天固定时刻。
+type dailySchedule struct {
+	hour, min, sec int
+}
+
+// Next 实现 Schedule。
+func (s dailySchedule) Next(t time.Time) time.Time {
+	next := time.Date(t.Year(), t.Month(), t.Day(), s.hour, s.min, s.sec, 0, t.Location())
+	if !next.After(t) {
+		next = time.Date(t.Year(), t.Month(), t.Day()+1, s.hour, s.min, s.sec, 0, t.Location())
+	}
+	return next
+}
+
+// parseClock 解析 "HH:MM" 或 "HH:MM:SS"。
+func parseClock(s string) (hour, min, sec int, err error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) != 2 && len(parts) != 3 {
+		return 0, 0, 0, fmt.Errorf("timeutil: 非法的时刻 %q，应为 HH:MM 或 HH:MM:SS", s)
+	}
+	limits := []int{23, 59, 59}
+	vals := make([]int, 3)
+	for i, p := range parts {
+		v, err := strconv.Atoi(p)
+		if err != nil || v < 0 || v > limits[i] {
+			return 0, 0, 0, fmt.Errorf("timeutil: 非法的时刻 %q", s)
+		}
+		vals[i] = v
+	}
+	return vals[0], vals[1], vals[2], nil
+}
+
+// cronSchedule 标准 5 段 cron 表达式（分 时 日 月 周）。
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64 // 位图
+	domStar, dowStar              bool   // 日 / 周是否为 *（决定两者的组合方式）
+}
+
+// cronField cron 各字段的取值范围。
+var cronFields = []struct {
+	name     string
+	min, max int
+}{
+	{"分", 0, 59},
+	{"时", 0, 23},
+	{"日", 1, 31},
+	{"月", 1, 12},
+	{"周", 0, 7}, // 0 和 7 都表示周日
+}
+
+// ParseCron 解析标准 5 段 cron 表达式："分 时 日 月 周"。
+// 每段支持 *、数字、范围 a-b、步长 */n 或 a-b/n、逗号分隔的列表；周的 0 和 7 都表示周日。
+// 日和周都不是 * 时，两者满足其一即触发（与 crontab 一致）。
+// 另支持 @hourly、@daily、@weekly、@monthly 简写。
+//
+// 用法：
+//
+//	s, err := timeutil.ParseCron("*/5 9-18 * * 1-5") // 工作日 9~18 点每 5 分钟
+func ParseCron(expr string) (Schedule, error) {
+	switch strings.TrimSpace(expr) {
+	case "@hourly":
+		expr = "0 * * * *"
+	case "@daily", "@midnight":
+		expr = "0 0 * * *"
+	case "@weekly":
+		expr = "0 0 * * 0"
+	case "@monthly":
+		expr = "0 0 1 * *"
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("timeutil: cron 表达式 %q 应为 5 段（分 时 日 月 周）", expr)
+	}
+	bits := make([]uint64, 5)
+	for i, f := range fields {
+		b, err := parseCronField(f, cronFields[i].min, cronFields[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("timeutil: cron 表达式 %q 的%s字段: %w", expr, cronFields[i].name, err)
+		}
+		bits[i] = b
+	}
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1 // 7 → 周日
+	}
+	return &cronSchedule{
+		minute: bits[0], hour: bits[1], dom: bits[2], month: bits[3], dow: bits[4],
+		domStar: fields[2] == "*", dowStar: fields[4] == "*",
+	}, nil
+}
+
+// parseCronField 解析单个字段为位图。
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			s, err := strconv.Atoi(part[i+1:])
+			if err != nil || s <= 0 {
+				return 0, fmt.Errorf("非法的步长 %q", part)
+			}
+			rangePart, step = part[:i], s
+		}
+
+		lo, hi := min, max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			a, b, _ := strings.Cut(rangePart, "-")
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(a)
+			hi, err2 = strconv.Atoi(b)
+			if err1 != nil || err2 != nil || lo > hi {
+				return 0, fmt.Errorf("非法的范围 %q", part)
+			}
+		default:
+			v, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return 0, fmt.Errorf("非法的值 %q", part)
+			}
+			lo, hi = v, v
+			if step > 1 {
+				hi = max // "5/15" 表示从 5 开始每 15
+			}
+		}
+		if lo < min || hi > max {
+			return 0, fmt.Errorf("%q 超出范围 %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// Next 实现 Schedule，逐级（月 → 日 → 时 → 分）跳到下一个匹配时刻，最多向后查找 5 年。
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches 判断日 / 周是否匹配。
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	domOK := s.dom&(1<<uint(t.Day())) != 0
+	dowOK := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domOK && dowOK
+	}
+	return domOK || dowOK
+}
@@ -0,0 +1,124 @@
+package timeutil
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronNext(t *testing.T) {
+	// 2026-02-01 为周日
+	from := time.Date(2026, 2, 1, 10, 7, 30, 0, time.UTC)
+	at := func(m time.Month, d, h, min int) time.Time { return time.Date(2026, m, d, h, min, 0, 0, time.UTC) }
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", at(2, 1, 10, 8)},
+		{"7 * * * *", at(2, 1, 11, 7)}, // 当前分钟不算
+		{"*/5 * * * *", at(2, 1, 10, 10)},
+		{"5/15 * * * *", at(2, 1, 10, 20)},
+		{"0-10/3 * * * *", at(2, 1, 10, 9)},
+		{"30 9-18 * * *", at(2, 1, 10, 30)},
+		{"0 9-18/4 * * *", at(2, 1, 13, 0)},
+		{"0,45 8,20 * * *", at(2, 1, 20, 0)},
+		{"0 0 * * *", at(2, 2, 0, 0)},
+		{"@hourly", at(2, 1, 11, 0)},
+		{"@daily", at(2, 2, 0, 0)},
+		{"@midnight", at(2, 2, 0, 0)},
+		{"@weekly", at(2, 8, 0, 0)},
+		{"@monthly", at(3, 1, 0, 0)},
+		{"0 9 * * 1-5", at(2, 2, 9, 0)}, // 下一个工作日
+		{"0 9 * * 0", at(2, 8, 9, 0)},   // 今天 9 点已过，下周日
+		{"0 9 * * 7", at(2, 8, 9, 0)},   // 7 同样表示周日
+		{"0 12 * * 6,0", at(2, 1, 12, 0)},
+		{"0 0 15 * *", at(2, 15, 0, 0)},
+		{"0 0 31 * *", at(3, 31, 0, 0)},                              // 跳过没有 31 日的月份
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)}, // 闰年
+		{"0 0 1 1,7 *", at(7, 1, 0, 0)},
+		{"0 0 * 3 *", at(3, 1, 0, 0)},
+
+		// 日和周都不是 * 时满足其一即触发
+		{"0 0 13 * 5", at(2, 6, 0, 0)},  // 周五（2/6）早于 13 日
+		{"0 0 3 * 5", at(2, 3, 0, 0)},   // 3 日早于周五
+		{"0 0 13 * *", at(2, 13, 0, 0)}, // 周为 * 时只看日
+		{"0 0 * * 5", at(2, 6, 0, 0)},   // 日为 * 时只看周
+	}
+	for _, tt := range tests {
+		s, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Errorf("ParseCron(%q) error: %v", tt.expr, err)
+			continue
+		}
+		if got := s.Next(from); !got.Equal(tt.want) {
+			t.Errorf("ParseCron(%q).Next(%v) = %v, want %v", tt.expr, from, got, tt.want)
+		}
+	}
+}
+
+func TestCronNextSequence(t *testing.T) {
+	s, _ := ParseCron("*/20 23 * * *")
+	next := time.Date(2026, 12, 31, 22, 59, 0, 0, time.UTC)
+	want := []time.Time{
+		time.Date(2026, 12, 31, 23, 0, 0, 0, time.UTC),
+		time.Date(2026, 12, 31, 23, 20, 0, 0, time.UTC),
+		time.Date(2026, 12, 31, 23, 40, 0, 0, time.UTC),
+		time.Date(2027, 1, 1, 23, 0, 0, 0, time.UTC), // 跨年
+	}
+	for i, w := range want {
+		next = s.Next(next)
+		if !next.Equal(w) {
+			t.Fatalf("Next #%d = %v, want %v", i, next, w)
+		}
+	}
+}
+
+// Next 按 t 所在时区计算。
+func TestCronNextLocation(t *testing.T) {
+	cst := time.FixedZone("CST", 8*3600)
+	s, _ := ParseCron("0 9 * * *")
+	from := time.Date(2026, 2, 1, 8, 30, 0, 0, cst)
+	got := s.Next(from)
+	if want := time.Date(2026, 2, 1, 9, 0, 0, 0, cst); !got.Equal(want) || got.Location() != cst {
+		t.Errorf("Next(%v) = %v, want %v", from, got, want)
+	}
+	got = s.Next(from.UTC()) // UTC 00:30，当天 UTC 09:00
+	if want := time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Next(%v) = %v, want %v", from.UTC(), got, want)
+	}
+}
+
+func TestCronNever(t *testing.T) {
+	s, err := ParseCron("0 0 30 2 *") // 2 月没有 30 日
+	if err != nil {
+		t.Fatalf("ParseCron error: %v", err)
+	}
+	if got := s.Next(time.Now()); !got.IsZero() {
+		t.Errorf("Next = %v, want zero time", got)
+	}
+}
+
+func TestParseCronError(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 32 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"-1 * * * *",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"*/x * * * *",
+		"1-x * * * *",
+		"a * * * *",
+		"1,,2 * * * *",
+		"@yearly",
+	} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) should fail", expr)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package timeutil
+
+import "time"
+
+// CST 中国标准时间（UTC+8，无夏令时）。使用固定时区，不依赖系统 tzdata，
+// 在精简容器镜像中同样可用。
+var CST = time.FixedZone("CST", 8*60*60)
+
+// NowCST 返回中国标准时间的当前时间。
+func NowCST() time.Time {
+	return time.Now().In(CST)
+}
+
+// ToCST 将 t 转换为中国标准时间。
+func ToCST(t time.Time) time.Time {
+	return t.In(CST)
+}
+
+// TodayCST 返回中国标准时间今天的 00:00:00。
+func TodayCST() time.Time {
+	return StartOfDay(time.Now(), CST)
+}
+
+// ParseInCST 按 layout 解析 value，不带时区的时间按中国标准时间解释。
+//
+// 用法：
+//
+//	t, err := timeutil.ParseInCST(timeutil.LayoutDateTime, "2026-02-01 08:00:00")
+func ParseInCST(layout, value string) (time.Time, error) {
+	return time.ParseInLocation(layout, value, CST)
+}
+
+// ParseAnyCST 同 ParseAny，不带时区的格式按中国标准时间解析。
+// 服务器时区为 UTC 时，解析国内数据源的 "2026-02-01 08:00:00" 应使用此函数。
+func ParseAnyCST(s string) (time.Time, error) {
+	return ParseAnyIn(s, CST)
+}
+
+// FormatCST 将 t 转换为中国标准时间后按 layout 格式化。
+func FormatCST(t time.Time, layout string) string {
+	return t.In(CST).Format(layout)
+}
@@ -0,0 +1,96 @@
+package timeutil
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// extendedUnits ParseDurationExtended 额外支持的单位（按长度降序，避免前缀误匹配）。
+var extendedUnits = []struct {
+	suffix string
+	unit   time.Duration
+}{
+	{"小时", time.Hour},
+	{"分钟", time.Minute},
+	{"天", 24 * time.Hour},
+	{"周", 7 * 24 * time.Hour},
+	{"秒", time.Second},
+	{"w", 7 * 24 * time.Hour},
+	{"d", 24 * time.Hour},
+}
+
+// ParseDurationExtended 在 time.ParseDuration 的基础上支持天（d）和周（w）单位，以及中文单位
+// （周、天、小时、分钟、秒），可与标准单位混用。天和周按固定的 24h / 168h 计算。
+//
+// 用法：
+//
+//	timeutil.ParseDurationExtended("3d")      // 72h
+//	timeutil.ParseDurationExtended("1w2d12h") // 228h
+//	timeutil.ParseDurationExtended("1.5d")    // 36h
+//	timeutil.ParseDurationExtended("2天3小时")  // 51h
+func ParseDurationExtended(s string) (time.Duration, error) {
+	orig := s
+	s = strings.TrimSpace(s)
+	neg := false
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	if s == "" {
+		return 0, fmt.Errorf("timeutil: 非法的时长 %q", orig)
+	}
+
+	var total time.Duration
+	var std strings.Builder // 标准单位部分交给 time.ParseDuration
+	for s != "" {
+		i := 0
+		for i < len(s) && (s[i] == '.' || (s[i] >= '0' && s[i] <= '9')) {
+			i++
+		}
+		if i == 0 {
+			return 0, fmt.Errorf("timeutil: 非法的时长 %q", orig)
+		}
+		num, rest := s[:i], s[i:]
+
+		j := 0
+		for j < len(rest) && (rest[j] < '0' || rest[j] > '9') && rest[j] != '.' {
+			j++
+		}
+		unit := rest[:j]
+		s = rest[j:]
+
+		if d, ok := extendedUnit(unit); ok {
+			v, err := strconv.ParseFloat(num, 64)
+			if err != nil {
+				return 0, fmt.Errorf("timeutil: 非法的时长 %q", orig)
+			}
+			total += time.Duration(v * float64(d))
+			continue
+		}
+		std.WriteString(num + unit)
+	}
+
+	if std.Len() > 0 {
+		d, err := time.ParseDuration(std.String())
+		if err != nil {
+			return 0, fmt.Errorf("timeutil: 非法的时长 %q: %w", orig, err)
+		}
+		total += d
+	}
+	if neg {
+		total = -total
+	}
+	return total, nil
+}
+
+// extendedUnit 查找扩展单位。
+func extendedUnit(unit string) (time.Duration, bool) {
+	for _, u := range extendedUnits {
+		if unit == u.suffix {
+			return u.unit, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,92 @@
+package timeutil
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDurationExtended(t *testing.T) {
+	const day = 24 * time.Hour
+	tests := []struct {
+		input string
+		want  time.Duration
+	}{
+		{"0", 0},
+		{"90s", 90 * time.Second},
+		{"1h30m", 90 * time.Minute},
+		{"250ms", 250 * time.Millisecond},
+		{"3d", 3 * day},
+		{"1w", 7 * day},
+		{"1w2d12h", 9*day + 12*time.Hour},
+		{"1.5d", 36 * time.Hour},
+		{".5w", 84 * time.Hour},
+		{"2d30m15s", 2*day + 30*time.Minute + 15*time.Second},
+		{"1h1d", 25 * time.Hour}, // 单位顺序不限
+		{"-2d", -2 * day},
+		{"+1d", day},
+		{"-1d12h", -36 * time.Hour},
+		{"  7d  ", 7 * day},
+		{"2天3小时", 2*day + 3*time.Hour},
+		{"1周", 7 * day},
+		{"10分钟30秒", 10*time.Minute + 30*time.Second},
+		{"1天12h", 36 * time.Hour},
+	}
+	for _, tt := range tests {
+		got, err := ParseDurationExtended(tt.input)
+		if err != nil {
+			t.Errorf("ParseDurationExtended(%q) error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDurationExtended(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseDurationExtendedError(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"   ",
+		"-",
+		"d",
+		"5",
+		"1x",
+		"1 d",
+		"1.2.3d",
+		"..d",
+		"1d-2h",
+		"--1d",
+		"1年",
+		"1分",
+	} {
+		if got, err := ParseDurationExtended(input); err == nil {
+			t.Errorf("ParseDurationExtended(%q) = %v, want error", input, got)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		opts []DurationOption
+		want string
+	}{
+		{320 * time.Millisecond, nil, "320ms"},
+		{2500 * time.Millisecond, nil, "2.50秒"},
+		{2500 * time.Millisecond, []DurationOption{WithEnglish()}, "2.50s"},
+		{3*time.Minute + 12*time.Second, nil, "3分12秒"},
+		{3*time.Minute + 12*time.Second, []DurationOption{WithEnglish()}, "3m12s"},
+		{125 * time.Minute, nil, "125分0秒"},
+		{125 * time.Minute, []DurationOption{WithHours()}, "2小时5分"},
+		{125 * time.Minute, []DurationOption{WithHours(), WithEnglish()}, "2h5m"},
+		{2 * time.Hour, []DurationOption{WithHours()}, "2小时"},
+		{time.Hour + 5*time.Second, []DurationOption{WithHours(), WithEnglish()}, "1h5s"},
+		{59 * time.Minute, []DurationOption{WithHours()}, "59分0秒"}, // 不足 1 小时不按小时输出
+		{time.Minute, []DurationOption{nil}, "1分0秒"},
+	}
+	for _, tt := range tests {
+		if got := FormatDuration(tt.d, tt.opts...); got != tt.want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
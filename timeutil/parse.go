@@ -0,0 +1,171 @@
+package timeutil
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 常用时间格式。
+const (
+	LayoutDateTime   = "2006-01-02 15:04:05"
+	LayoutDate       = "2006-01-02"
+	LayoutDateTimeMs = "2006-01-02 15:04:05.000"
+	LayoutCompact    = "20060102150405"
+	LayoutCN         = "2006年01月02日 15:04:05"
+	LayoutDateCN     = "2006年01月02日"
+)
+
+// parseLayouts ParseAny 依次尝试的格式（月 / 日使用 "1" / "2"，同时兼容补零和不补零）。
+var parseLayouts = []string{
+	time.RFC3339Nano,
+	"2006-1-2T15:04:05.999999999",
+	"2006-1-2 15:04:05.999999999Z07:00",
+	"2006-1-2 15:04:05.999999999 -0700",
+	"2006-1-2 15:04:05.999999999",
+	"2006-1-2 15:04",
+	"2006-1-2",
+	"2006/1/2 15:04:05.999999999",
+	"2006/1/2 15:04",
+	"2006/1/2",
+	"2006.1.2 15:04:05",
+	"2006.1.2",
+	"2006年1月2日 15:04:05",
+	"2006年1月2日15:04:05",
+	"2006年1月2日 15时4分5秒", // 中文写法的分 / 秒常不补零，"4" / "5" 同时兼容补零
+	"2006年1月2日15时4分5秒",
+	"2006年1月2日 15时4分",
+	"2006年1月2日15时4分",
+	"2006年1月2日 15:04",
+	"2006年1月2日",
+	"2006年1月",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC850,
+	time.RFC822Z,
+	time.RFC822,
+	time.ANSIC,
+	time.UnixDate,
+	time.RubyDate,
+	"02/Jan/2006:15:04:05 -0700", // nginx / Apache 访问日志
+	"Jan 2, 2006 15:04:05",
+	"Jan 2, 2006",
+	"2 Jan 2006",
+}
+
+// ParseAny 自动识别常见格式解析时间字符串，不带时区的格式按本地时区解析。
+// 支持 RFC3339、"2006-01-02 15:04:05"、"2006/01/02"、"2006年01月02日 15时04分05秒" 等中文格式、
+// RFC1123 / ANSIC 等标准格式、nginx 日志格式，以及 Unix 时间戳（按位数识别秒 / 毫秒 / 微秒 / 纳秒，可带小数秒）。
+// 8 位和 14 位纯数字按 "20060102" / "20060102150405" 解析。
+//
+// 用法：
+//
+//	t, err := timeutil.ParseAny("2026-02-01 08:00:00")
+//	t, err := timeutil.ParseAny("1738368000123") // 毫秒时间戳
+func ParseAny(s string) (time.Time, error) {
+	return ParseAnyIn(s, time.Local)
+}
+
+// ParseAnyIn 同 ParseAny，不带时区的格式按 loc 解析（loc 为 nil 时为 UTC）。
+func ParseAnyIn(s string, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, fmt.Errorf("timeutil: 时间字符串为空")
+	}
+
+	if isDigits(s) {
+		switch len(s) {
+		case 8:
+			if t, err := time.ParseInLocation("20060102", s, loc); err == nil {
+				return t, nil
+			}
+		case 14:
+			if t, err := time.ParseInLocation(LayoutCompact, s, loc); err == nil {
+				return t, nil
+			}
+		}
+		if t, ok := parseUnix(s); ok {
+			return t.In(loc), nil
+		}
+	}
+	if t, ok := parseUnixFloat(s); ok {
+		return t.In(loc), nil
+	}
+
+	for _, layout := range parseLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("timeutil: 无法识别的时间格式 %q", s)
+}
+
+// isDigits 判断 s 是否全为数字。
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// parseUnix 按位数解析整数时间戳：<= 11 位为秒，12~14 位为毫秒，15~17 位为微秒，更长为纳秒。
+func parseUnix(s string) (time.Time, bool) {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	switch {
+	case len(s) <= 11:
+		return time.Unix(n, 0), true
+	case len(s) <= 14:
+		return time.UnixMilli(n), true
+	case len(s) <= 17:
+		return time.UnixMicro(n), true
+	default:
+		return time.Unix(0, n), true
+	}
+}
+
+// parseUnixFloat 解析带小数的秒级时间戳，如 "1738368000.123"。
+func parseUnixFloat(s string) (time.Time, bool) {
+	sec, frac, ok := strings.Cut(s, ".")
+	if !ok || !isDigits(sec) || !isDigits(frac) || len(sec) > 11 {
+		return time.Time{}, false
+	}
+	n, err := strconv.ParseInt(sec, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if len(frac) > 9 {
+		frac = frac[:9]
+	}
+	nsec, _ := strconv.ParseInt(frac+strings.Repeat("0", 9-len(frac)), 10, 64)
+	return time.Unix(n, nsec), true
+}
+
+// FormatUnix 将秒级时间戳按 layout 格式化为本地时间，layout 为空时使用 LayoutDateTime。
+func FormatUnix(sec int64, layout string) string {
+	if layout == "" {
+		layout = LayoutDateTime
+	}
+	return time.Unix(sec, 0).Format(layout)
+}
+
+// FormatUnixMilli 将毫秒时间戳按 layout 格式化为本地时间，layout 为空时使用 LayoutDateTimeMs。
+//
+// 用法：
+//
+//	timeutil.FormatUnixMilli(1738368000123, "")           // "2025-02-01 08:00:00.123"
+//	timeutil.FormatUnixMilli(ms, timeutil.LayoutCN)       // "2025年02月01日 08:00:00"
+func FormatUnixMilli(ms int64, layout string) string {
+	if layout == "" {
+		layout = LayoutDateTimeMs
+	}
+	return time.UnixMilli(ms).Format(layout)
+}
@@ -0,0 +1,108 @@
+package timeutil
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAnyIn(t *testing.T) {
+	cst := time.FixedZone("CST", 8*3600)
+	date := func(y int, m time.Month, d, h, min, sec, nsec int, loc *time.Location) time.Time {
+		return time.Date(y, m, d, h, min, sec, nsec, loc)
+	}
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		// ISO / 常用格式
+		{"2026-02-01T08:05:03Z", date(2026, 2, 1, 8, 5, 3, 0, time.UTC)},
+		{"2026-02-01T08:05:03.123+08:00", date(2026, 2, 1, 8, 5, 3, 123e6, cst)},
+		{"2026-2-1T8:05:03", date(2026, 2, 1, 8, 5, 3, 0, cst)},
+		{"2026-02-01 08:05:03", date(2026, 2, 1, 8, 5, 3, 0, cst)},
+		{"2026-02-01 08:05:03.5", date(2026, 2, 1, 8, 5, 3, 5e8, cst)},
+		{"2026-02-01 08:05:03 +0000", date(2026, 2, 1, 8, 5, 3, 0, time.UTC)},
+		{"2026-2-1 8:05", date(2026, 2, 1, 8, 5, 0, 0, cst)},
+		{"2026-02-01", date(2026, 2, 1, 0, 0, 0, 0, cst)},
+		{"  2026-02-01  ", date(2026, 2, 1, 0, 0, 0, 0, cst)},
+		{"2026/02/01 08:05:03", date(2026, 2, 1, 8, 5, 3, 0, cst)},
+		{"2026/2/1 8:05", date(2026, 2, 1, 8, 5, 0, 0, cst)},
+		{"2026/2/1", date(2026, 2, 1, 0, 0, 0, 0, cst)},
+		{"2026.02.01 08:05:03", date(2026, 2, 1, 8, 5, 3, 0, cst)},
+		{"2026.2.1", date(2026, 2, 1, 0, 0, 0, 0, cst)},
+
+		// 中文格式（补零与不补零）
+		{"2026年02月01日 08:05:03", date(2026, 2, 1, 8, 5, 3, 0, cst)},
+		{"2026年2月1日08:05:03", date(2026, 2, 1, 8, 5, 3, 0, cst)},
+		{"2026年2月1日 8时5分", date(2026, 2, 1, 8, 5, 0, 0, cst)},
+		{"2026年2月1日8时5分3秒", date(2026, 2, 1, 8, 5, 3, 0, cst)},
+		{"2026年02月01日 08时05分03秒", date(2026, 2, 1, 8, 5, 3, 0, cst)},
+		{"2026年2月1日 18时30分", date(2026, 2, 1, 18, 30, 0, 0, cst)},
+		{"2026年2月1日 8:05", date(2026, 2, 1, 8, 5, 0, 0, cst)},
+		{"2026年2月1日", date(2026, 2, 1, 0, 0, 0, 0, cst)},
+		{"2026年2月", date(2026, 2, 1, 0, 0, 0, 0, cst)},
+
+		// 标准格式与日志格式
+		{"Sun, 01 Feb 2026 08:05:03 +0000", date(2026, 2, 1, 8, 5, 3, 0, time.UTC)},
+		{"Sun Feb  1 08:05:03 2026", date(2026, 2, 1, 8, 5, 3, 0, cst)},
+		{"01/Feb/2026:08:05:03 +0000", date(2026, 2, 1, 8, 5, 3, 0, time.UTC)},
+		{"Feb 1, 2026 08:05:03", date(2026, 2, 1, 8, 5, 3, 0, cst)},
+		{"Feb 1, 2026", date(2026, 2, 1, 0, 0, 0, 0, cst)},
+		{"1 Feb 2026", date(2026, 2, 1, 0, 0, 0, 0, cst)},
+
+		// 纯数字
+		{"20260201", date(2026, 2, 1, 0, 0, 0, 0, cst)},
+		{"20260201080503", date(2026, 2, 1, 8, 5, 3, 0, cst)},
+		{"1769904303", time.Unix(1769904303, 0)},
+		{"1769904303123", time.UnixMilli(1769904303123)},
+		{"1769904303123456", time.UnixMicro(1769904303123456)},
+		{"1769904303123456789", time.Unix(0, 1769904303123456789)},
+		{"1769904303.5", time.Unix(1769904303, 5e8)},
+		{"1769904303.1234567891", time.Unix(1769904303, 123456789)},
+		{"0", time.Unix(0, 0)},
+	}
+	for _, tt := range tests {
+		got, err := ParseAnyIn(tt.input, cst)
+		if err != nil {
+			t.Errorf("ParseAnyIn(%q) error: %v", tt.input, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseAnyIn(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseAnyInLocation(t *testing.T) {
+	cst := time.FixedZone("CST", 8*3600)
+	got, _ := ParseAnyIn("2026-02-01 08:00:00", cst)
+	if got.Location() != cst {
+		t.Errorf("location = %v, want CST", got.Location())
+	}
+	got, _ = ParseAnyIn("1769904303", cst)
+	if got.Location() != cst {
+		t.Errorf("timestamp location = %v, want CST", got.Location())
+	}
+	got, _ = ParseAnyIn("2026-02-01", nil)
+	if got.Location() != time.UTC {
+		t.Errorf("nil location = %v, want UTC", got.Location())
+	}
+}
+
+func TestParseAnyError(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"   ",
+		"not a time",
+		"2026-13-01",
+		"2026-02-30",
+		"2026年2月1日 25时",
+		"12345678901234567890", // 超出 int64
+		"1.2.3",
+		"1769904303.",
+		"-1769904303",
+	} {
+		if got, err := ParseAny(input); err == nil {
+			t.Errorf("ParseAny(%q) = %v, want error", input, got)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package timeutil
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pylemonorg/gotools/logger"
+)
+
+// defaultRateWindow 默认按最近 1 分钟的进度计算速率。
+const defaultRateWindow = time.Minute
+
+// Progress 长时间批处理任务的进度、速率与剩余时间估算。并发安全，可在多个 worker 中同时 Add。
+// 速率按最近一段时间（默认 1 分钟，见 WithRateWindow）的进度计算，任务中途变快或变慢时 ETA 能及时跟上。
+//
+// 用法：
+//
+//	p := timeutil.NewProgress(int64(len(keys)))
+//	stop := p.LogEvery("迁移对象", 10*time.Second) // 迁移对象 进度: 12,340/100,000 (12%) 850/s ETA 1m43s
+//	defer stop()
+//	for _, key := range keys {
+//	    migrate(key)
+//	    p.Add(1)
+//	}
+type Progress struct {
+	mu      sync.Mutex
+	total   int64
+	done    int64
+	start   time.Time
+	window  time.Duration
+	samples []progressSample // 按时间递增的进度采样，用于计算最近的速率
+}
+
+// progressSample 某一时刻的已完成数量。
+type progressSample struct {
+	at   time.Time
+	done int64
+}
+
+// ProgressOption Progress 的可选配置。
+type ProgressOption func(*Progress)
+
+// WithRateWindow 设置计算速率的时间窗口（默认 1 分钟），窗口越短 ETA 对速率变化越敏感、波动也越大。
+func WithRateWindow(d time.Duration) ProgressOption {
+	return func(p *Progress) {
+		if d > 0 {
+			p.window = d
+		}
+	}
+}
+
+// NewProgress 创建总数为 total 的进度并开始计时，total <= 0 表示总数未知（不输出百分比和 ETA）。
+func NewProgress(total int64, opts ...ProgressOption) *Progress {
+	now := time.Now()
+	p := &Progress{
+		total:   total,
+		start:   now,
+		window:  defaultRateWindow,
+		samples: []progressSample{{at: now}},
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(p)
+		}
+	}
+	return p
+}
+
+// Add 增加 n 个已完成数量，返回累计完成数。
+func (p *Progress) Add(n int64) int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done += n
+	p.sample(time.Now())
+	return p.done
+}
+
+// Set 直接设置累计完成数（如从断点续传的位置开始）。
+func (p *Progress) Set(done int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done = done
+	p.sample(time.Now())
+}
+
+// SetTotal 更新总数（如边扫描边处理，总数逐步确定）。
+func (p *Progress) SetTotal(total int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.total = total
+}
+
+// sample 记录一次采样（同一秒内只保留最新的一次），并丢弃窗口之外多余的旧采样。调用方持有锁。
+func (p *Progress) sample(now time.Time) {
+	if n := len(p.samples); n > 1 && now.Sub(p.samples[n-2].at) < time.Second {
+		p.samples[n-1] = progressSample{at: now, done: p.done}
+	} else {
+		p.samples = append(p.samples, progressSample{at: now, done: p.done})
+	}
+	// 保留一个早于窗口起点的采样，使速率覆盖完整的窗口
+	cutoff := now.Add(-p.window)
+	drop := 0
+	for drop+1 < len(p.samples) && !p.samples[drop+1].at.After(cutoff) {
+		drop++
+	}
+	if drop > 0 {
+		p.samples = append(p.samples[:0], p.samples[drop:]...)
+	}
+}
+
+// Done 返回累计完成数。
+func (p *Progress) Done() int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.done
+}
+
+// Total 返回总数，<= 0 表示未知。
+func (p *Progress) Total() int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.total
+}
+
+// Elapsed 返回自创建以来的耗时。
+func (p *Progress) Elapsed() time.Duration {
+	return time.Since(p.start)
+}
+
+// Percent 返回完成百分比（0~100），总数未知时返回 0。
+func (p *Progress) Percent() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.percent()
+}
+
+// percent Percent 的实现，调用方持有锁。
+func (p *Progress) percent() float64 {
+	if p.total <= 0 {
+		return 0
+	}
+	return min(float64(p.done)/float64(p.total)*100, 100)
+}
+
+// Rate 返回最近时间窗口内的速率（个/秒）。
+func (p *Progress) Rate() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.rate(time.Now())
+}
+
+// rate Rate 的实现，调用方持有锁。
+func (p *Progress) rate(now time.Time) float64 {
+	oldest := p.samples[0]
+	span := now.Sub(oldest.at)
+	if span <= 0 {
+		return 0
+	}
+	return float64(p.done-oldest.done) / span.Seconds()
+}
+
+// ETA 返回按当前速率估算的剩余时间，已完成时返回 0；总数未知或速率为 0 时无法估算，返回 -1。
+func (p *Progress) ETA() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.eta(time.Now())
+}
+
+// eta ETA 的实现，调用方持有锁。
+func (p *Progress) eta(now time.Time) time.Duration {
+	if p.total <= 0 {
+		return -1
+	}
+	remaining := p.total - p.done
+	if remaining <= 0 {
+		return 0
+	}
+	rate := p.rate(now)
+	if rate <= 0 {
+		return -1
+	}
+	return time.Duration(float64(remaining) / rate * float64(time.Second))
+}
+
+// String 返回单行进度，如 "12,340/100,000 (12%) 850/s ETA 1m43s"；
+// 总数未知时为 "12,340 850/s"，已完成时 ETA 部分为总耗时，如 "100,000/100,000 (100%) 830/s 用时 2m0s"。
+func (p *Progress) String() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+
+	if p.total <= 0 {
+		return fmt.Sprintf("%s %s/s", formatCount(p.done), formatRate(p.rate(now)))
+	}
+	s := fmt.Sprintf("%s/%s (%d%%) %s/s", formatCount(p.done), formatCount(p.total),
+		int(p.percent()), formatRate(p.rate(now)))
+	if p.done >= p.total {
+		return s + " 用时 " + formatETA(now.Sub(p.start))
+	}
+	if eta := p.eta(now); eta >= 0 {
+		return s + " ETA " + formatETA(eta)
+	}
+	return s + " ETA -"
+}
+
+// LogEvery 启动后台 goroutine，每隔 interval 以 info 级别记录一次进度（"name 进度: ..."），
+// 完成（达到总数）或调用返回的 stop 后记录最后一次进度并退出。stop 可重复调用。interval <= 0 时默认 10 秒。
+func (p *Progress) LogEvery(name string, interval time.Duration) (stop func()) {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	var once sync.Once
+
+	go func() {
+		defer close(exited)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				logger.Infof("%s 进度: %s", name, p.String())
+				return
+			case <-ticker.C:
+				logger.Infof("%s 进度: %s", name, p.String())
+				if total := p.Total(); total > 0 && p.Done() >= total {
+					return
+				}
+			}
+		}
+	}()
+
+	return func() {
+		once.Do(func() { close(done) })
+		<-exited
+	}
+}
+
+// formatCount 格式化整数并添加千分位，如 12340 → "12,340"。
+func formatCount(n int64) string {
+	s := strconv.FormatInt(n, 10)
+	sign := ""
+	if n < 0 {
+		sign, s = "-", s[1:]
+	}
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return sign + s
+}
+
+// formatRate 格式化速率：>= 100 时取整并添加千分位，否则保留 1 位小数。
+func formatRate(r float64) string {
+	if r >= 100 {
+		return formatCount(int64(r + 0.5))
+	}
+	return strconv.FormatFloat(r, 'f', 1, 64)
+}
+
+// formatETA 将剩余时间格式化为精确到秒的紧凑形式，如 "43s"、"1m43s"、"2h5m"。
+func formatETA(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d < time.Minute {
+		return strconv.Itoa(int(d.Seconds())) + "s"
+	}
+	return FormatDuration(d, WithHours(), WithEnglish())
+}
@@ -0,0 +1,153 @@
+package timeutil
+
+import "time"
+
+// TimeRange 左闭右开的时间区间 [Start, End)。
+type TimeRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Duration 返回区间长度，End 早于 Start 时返回 0。
+func (r TimeRange) Duration() time.Duration {
+	if r.End.Before(r.Start) {
+		return 0
+	}
+	return r.End.Sub(r.Start)
+}
+
+// IsZero 判断区间是否为空（长度为 0）。
+func (r TimeRange) IsZero() bool {
+	return !r.End.After(r.Start)
+}
+
+// Contains 判断 t 是否在区间内（Start <= t < End）。
+func (r TimeRange) Contains(t time.Time) bool {
+	return !t.Before(r.Start) && t.Before(r.End)
+}
+
+// Overlaps 判断两个区间是否有交集（首尾相接不算重叠）。
+func (r TimeRange) Overlaps(o TimeRange) bool {
+	return r.Start.Before(o.End) && o.Start.Before(r.End)
+}
+
+// Overlap 返回两个区间的交集，无交集时 ok 为 false。
+func (r TimeRange) Overlap(o TimeRange) (TimeRange, bool) {
+	if !r.Overlaps(o) {
+		return TimeRange{}, false
+	}
+	start, end := r.Start, r.End
+	if o.Start.After(start) {
+		start = o.Start
+	}
+	if o.End.Before(end) {
+		end = o.End
+	}
+	return TimeRange{Start: start, End: end}, true
+}
+
+// Split 将区间按 d 切分为连续的子区间，最后一段可能不足 d。d <= 0 时返回区间本身。
+//
+// 用法：
+//
+//	// 按小时分批拉取一天的数据
+//	for _, r := range timeutil.DayRange(day, nil).Split(time.Hour) {
+//	    fetch(r.Start, r.End)
+//	}
+func (r TimeRange) Split(d time.Duration) []TimeRange {
+	if r.IsZero() {
+		return nil
+	}
+	if d <= 0 {
+		return []TimeRange{r}
+	}
+	parts := make([]TimeRange, 0, int(r.Duration()/d)+1)
+	for start := r.Start; start.Before(r.End); start = start.Add(d) {
+		end := start.Add(d)
+		if end.After(r.End) {
+			end = r.End
+		}
+		parts = append(parts, TimeRange{Start: start, End: end})
+	}
+	return parts
+}
+
+// ---------------------------------------------------------------------------
+// 日 / 周 / 月边界
+// ---------------------------------------------------------------------------
+
+// inLoc 将 t 转换到 loc，loc 为 nil 时保持 t 原有时区。
+func inLoc(t time.Time, loc *time.Location) time.Time {
+	if loc == nil {
+		return t
+	}
+	return t.In(loc)
+}
+
+// StartOfDay 返回 t 在 loc 时区当天的 00:00:00（loc 为 nil 时使用 t 的时区）。
+func StartOfDay(t time.Time, loc *time.Location) time.Time {
+	t = inLoc(t, loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// EndOfDay 返回 t 在 loc 时区当天的 23:59:59.999999999。
+func EndOfDay(t time.Time, loc *time.Location) time.Time {
+	start := StartOfDay(t, loc)
+	return time.Date(start.Year(), start.Month(), start.Day()+1, 0, 0, 0, 0, start.Location()).Add(-time.Nanosecond)
+}
+
+// StartOfWeek 返回 t 在 loc 时区所在周的周一 00:00:00（周一为一周的第一天）。
+func StartOfWeek(t time.Time, loc *time.Location) time.Time {
+	day := StartOfDay(t, loc)
+	offset := (int(day.Weekday()) + 6) % 7 // 周一 → 0，周日 → 6
+	return time.Date(day.Year(), day.Month(), day.Day()-offset, 0, 0, 0, 0, day.Location())
+}
+
+// StartOfMonth 返回 t 在 loc 时区所在月的 1 日 00:00:00。
+func StartOfMonth(t time.Time, loc *time.Location) time.Time {
+	t = inLoc(t, loc)
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// DayRange 返回 t 在 loc 时区所在自然日的区间 [当天 00:00, 次日 00:00)。
+// 按日历计算，夏令时切换日的长度可能不是 24 小时。
+func DayRange(t time.Time, loc *time.Location) TimeRange {
+	start := StartOfDay(t, loc)
+	return TimeRange{Start: start, End: time.Date(start.Year(), start.Month(), start.Day()+1, 0, 0, 0, 0, start.Location())}
+}
+
+// IsSameDay 判断 a、b 在 loc 时区是否为同一天（loc 为 nil 时使用 a 的时区）。
+func IsSameDay(a, b time.Time, loc *time.Location) bool {
+	if loc == nil {
+		loc = a.Location()
+	}
+	a, b = a.In(loc), b.In(loc)
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay()
+}
+
+// BetweenHours 判断 t 的时刻（按 t 的时区）是否在 [start, end) 内，start / end 格式为 "HH:MM" 或 "HH:MM:SS"。
+// start 晚于 end 时表示跨午夜的区间（如 "22:00" ~ "06:00"）。格式错误时返回 false。
+//
+// 用法：
+//
+//	if timeutil.BetweenHours(time.Now(), "09:00", "18:00") {
+//	    // 工作时间
+//	}
+func BetweenHours(t time.Time, start, end string) bool {
+	sh, sm, ss, err := parseClock(start)
+	if err != nil {
+		return false
+	}
+	eh, em, es, err := parseClock(end)
+	if err != nil {
+		return false
+	}
+
+	cur := t.Hour()*3600 + t.Minute()*60 + t.Second()
+	from := sh*3600 + sm*60 + ss
+	to := eh*3600 + em*60 + es
+	if from <= to {
+		return cur >= from && cur < to
+	}
+	return cur >= from || cur < to
+}
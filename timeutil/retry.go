@@ -0,0 +1,113 @@
+package timeutil
+
+import (
+	"context"
+	"time"
+
+	"github.com/pylemonorg/gotools/retry"
+)
+
+// RetryPolicy 重试策略（指数退避 + 随机抖动）。零值字段使用默认值。
+// 底层由 retry 包实现；需要熔断器或 OnGiveUp 回调时直接使用 retry.Policy。
+type RetryPolicy struct {
+	MaxRetries      int           // 最大重试次数（不含首次执行），<= 0 时默认 3
+	InitialInterval time.Duration // 首次重试前的等待时间，<= 0 时默认 1s
+	MaxInterval     time.Duration // 单次等待上限，<= 0 时默认 30s
+	Multiplier      float64       // 每次重试等待时间的倍数，<= 0 时默认 2（设为 1 则固定间隔）
+	Jitter          float64       // 随机抖动比例（0~1），等待时间在 ±Jitter 范围内随机浮动，0 表示不抖动
+	MaxElapsedTime  time.Duration // 总耗时上限（含等待），超过后不再重试，0 表示不限制
+
+	// RetryIf 判断错误是否可重试，nil 时除 PermanentError 外的错误都重试
+	RetryIf func(err error) bool
+	// OnRetry 每次重试等待前调用（attempt 从 1 开始），可用于记录日志
+	OnRetry func(attempt int, err error, delay time.Duration)
+}
+
+// DefaultRetryPolicy 返回默认策略：最多重试 3 次，1s 起指数退避，±20% 抖动。
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{Jitter: 0.2}
+}
+
+// PermanentError 不可重试的错误，Retry 遇到后立即返回其内部错误。等同于 retry.PermanentError。
+type PermanentError = retry.PermanentError
+
+// Permanent 将 err 标记为不可重试，err 为 nil 时返回 nil。
+//
+// 用法：
+//
+//	if resp.StatusCode == 404 {
+//	    return timeutil.Permanent(ErrNotFound)
+//	}
+func Permanent(err error) error {
+	return retry.Permanent(err)
+}
+
+// Retry 执行 fn，失败时按 policy 指数退避重试，直到成功、遇到 PermanentError、
+// 达到最大重试次数 / 总耗时上限或 ctx 结束。policy 为 nil 时使用 DefaultRetryPolicy。
+//
+// 用法：
+//
+//	err := timeutil.Retry(ctx, &timeutil.RetryPolicy{MaxRetries: 5, Jitter: 0.2}, func(ctx context.Context) error {
+//	    return client.Upload(ctx, key, data)
+//	})
+func Retry(ctx context.Context, policy *RetryPolicy, fn func(ctx context.Context) error) error {
+	_, err := RetryValue(ctx, policy, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
+
+// RetryValue 同 Retry，fn 返回结果值。
+//
+// 用法：
+//
+//	out, err := timeutil.RetryValue(ctx, nil, func(ctx context.Context) (*Resp, error) {
+//	    return client.Get(ctx, url)
+//	})
+func RetryValue[T any](ctx context.Context, policy *RetryPolicy, fn func(ctx context.Context) (T, error)) (T, error) {
+	return retry.DoValue(ctx, policy.toPolicy(), fn)
+}
+
+// toPolicy 转换为 retry.Policy（MaxRetries 不含首次执行，MaxAttempts 含首次执行）。
+func (p *RetryPolicy) toPolicy() *retry.Policy {
+	r := p.withDefaults()
+	return &retry.Policy{
+		MaxAttempts:     r.MaxRetries + 1,
+		InitialInterval: r.InitialInterval,
+		MaxInterval:     r.MaxInterval,
+		Multiplier:      r.Multiplier,
+		Jitter:          r.Jitter,
+		MaxElapsedTime:  r.MaxElapsedTime,
+		RetryIf:         r.RetryIf,
+		OnRetry:         r.OnRetry,
+	}
+}
+
+// withDefaults 返回填充默认值后的策略副本。
+func (p *RetryPolicy) withDefaults() RetryPolicy {
+	if p == nil {
+		p = DefaultRetryPolicy()
+	}
+	r := *p
+	if r.MaxRetries <= 0 {
+		r.MaxRetries = 3
+	}
+	if r.InitialInterval <= 0 {
+		r.InitialInterval = time.Second
+	}
+	if r.MaxInterval <= 0 {
+		r.MaxInterval = 30 * time.Second
+	}
+	if r.MaxInterval < r.InitialInterval {
+		r.MaxInterval = r.InitialInterval
+	}
+	if r.Multiplier <= 0 {
+		r.Multiplier = 2
+	}
+	if r.Jitter < 0 {
+		r.Jitter = 0
+	} else if r.Jitter > 1 {
+		r.Jitter = 1
+	}
+	return r
+}
@@ -0,0 +1,248 @@
+package timeutil
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pylemonorg/gotools/logger"
+)
+
+// OverlapPolicy 任务上一次执行尚未结束时再次到达触发时间的处理策略。
+type OverlapPolicy int
+
+const (
+	OverlapSkip  OverlapPolicy = iota // 跳过本次触发（默认）
+	OverlapAllow                      // 允许并发执行
+	OverlapWait                       // 等待上次结束后再计算下一次触发时间（串行执行，错过的触发不补）
+)
+
+// JobOption 任务选项。
+type JobOption func(*job)
+
+// WithJobName 设置任务名（用于日志），默认为 "job-N"。
+func WithJobName(name string) JobOption {
+	return func(j *job) {
+		j.name = name
+	}
+}
+
+// WithOverlap 设置任务的重叠执行策略，默认 OverlapSkip。
+func WithOverlap(p OverlapPolicy) JobOption {
+	return func(j *job) {
+		j.overlap = p
+	}
+}
+
+// WithRunImmediately 启动时立即执行一次，再按调度规则执行。
+func WithRunImmediately() JobOption {
+	return func(j *job) {
+		j.immediate = true
+	}
+}
+
+// WithWorkdaysOnly 只在工作日执行，非工作日的触发直接跳过。cal 为 nil 时使用 CNCalendar。
+//
+// 用法：
+//
+//	s.At("09:30", sendDailyReport, timeutil.WithWorkdaysOnly(nil))
+func WithWorkdaysOnly(cal *Calendar) JobOption {
+	return func(j *job) {
+		if cal == nil {
+			cal = CNCalendar
+		}
+		j.calendar = cal
+	}
+}
+
+// job 已注册的任务。
+type job struct {
+	name      string
+	schedule  Schedule
+	fn        func(ctx context.Context)
+	overlap   OverlapPolicy
+	immediate bool
+	calendar  *Calendar   // 非 nil 时只在工作日执行
+	running   atomic.Bool // OverlapSkip 下是否正在执行
+}
+
+// Scheduler 周期任务调度器，支持固定间隔、每日定时和 cron 表达式。
+// 任务 panic 会被捕获并记录日志，不影响调度器和其他任务。
+//
+// 用法：
+//
+//	s := timeutil.NewScheduler(nil)
+//	s.Every(30*time.Second, reportStats, timeutil.WithJobName("report"))
+//	s.At("03:00", cleanup)
+//	s.Cron("*/5 9-18 * * 1-5", syncData, timeutil.WithOverlap(timeutil.OverlapWait))
+//	s.Start()
+//	defer s.Stop(context.Background())
+type Scheduler struct {
+	loc *time.Location
+
+	mu      sync.Mutex
+	jobs    []*job
+	started bool
+	stopped bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewScheduler 创建调度器，loc 为 At / Cron 使用的时区，nil 时为本地时区。
+func NewScheduler(loc *time.Location) *Scheduler {
+	if loc == nil {
+		loc = time.Local
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{loc: loc, ctx: ctx, cancel: cancel}
+}
+
+// Every 每隔 d 执行一次 fn（从 Start 开始计时）。
+func (s *Scheduler) Every(d time.Duration, fn func(ctx context.Context), opts ...JobOption) error {
+	if d <= 0 {
+		return fmt.Errorf("timeutil: 调度间隔必须大于 0")
+	}
+	return s.Schedule(everySchedule{interval: d}, fn, opts...)
+}
+
+// At 每天在 clock（"HH:MM" 或 "HH:MM:SS"，调度器时区）执行一次 fn。
+func (s *Scheduler) At(clock string, fn func(ctx context.Context), opts ...JobOption) error {
+	h, m, sec, err := parseClock(clock)
+	if err != nil {
+		return err
+	}
+	return s.Schedule(dailySchedule{hour: h, min: m, sec: sec}, fn, opts...)
+}
+
+// Cron 按 cron 表达式（格式见 ParseCron，调度器时区）执行 fn。
+func (s *Scheduler) Cron(expr string, fn func(ctx context.Context), opts ...JobOption) error {
+	sched, err := ParseCron(expr)
+	if err != nil {
+		return err
+	}
+	return s.Schedule(sched, fn, opts...)
+}
+
+// Schedule 按自定义调度规则执行 fn。Start 之后添加的任务立即开始调度。
+// fn 的 ctx 在 Stop 时取消，长任务应据此尽快退出。
+func (s *Scheduler) Schedule(sched Schedule, fn func(ctx context.Context), opts ...JobOption) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return fmt.Errorf("timeutil: 调度器已停止")
+	}
+
+	j := &job{name: fmt.Sprintf("job-%d", len(s.jobs)+1), schedule: sched, fn: fn}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(j)
+		}
+	}
+	s.jobs = append(s.jobs, j)
+	if s.started {
+		s.startJob(j)
+	}
+	return nil
+}
+
+// Start 开始调度所有任务。重复调用无效果。
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started || s.stopped {
+		return
+	}
+	s.started = true
+	for _, j := range s.jobs {
+		s.startJob(j)
+	}
+}
+
+// Stop 停止调度并取消任务的 ctx，等待正在执行的任务结束；ctx 到期时不再等待并返回 ctx.Err()。
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	s.stopped = true
+	s.mu.Unlock()
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("timeutil: 等待任务结束超时: %w", ctx.Err())
+	}
+}
+
+// startJob 启动任务的调度协程（调用方持有 s.mu）。
+func (s *Scheduler) startJob(j *job) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		if j.immediate {
+			s.fire(j)
+		}
+		for {
+			now := time.Now().In(s.loc)
+			next := j.schedule.Next(now)
+			if next.IsZero() {
+				return
+			}
+			timer := time.NewTimer(next.Sub(now))
+			select {
+			case <-s.ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			s.fire(j)
+		}
+	}()
+}
+
+// fire 按重叠策略执行一次任务。
+func (s *Scheduler) fire(j *job) {
+	if j.calendar != nil && !j.calendar.IsWorkday(time.Now()) {
+		logger.Debugf("timeutil: 任务 [%s] 非工作日，跳过本次", j.name)
+		return
+	}
+	switch j.overlap {
+	case OverlapWait:
+		s.run(j)
+	case OverlapAllow:
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.run(j)
+		}()
+	default:
+		if !j.running.CompareAndSwap(false, true) {
+			logger.Warnf("timeutil: 任务 [%s] 上次执行尚未结束，跳过本次", j.name)
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer j.running.Store(false)
+			s.run(j)
+		}()
+	}
+}
+
+// run 执行任务并捕获 panic。
+func (s *Scheduler) run(j *job) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("timeutil: 任务 [%s] panic: %v\n%s", j.name, r, debug.Stack())
+		}
+	}()
+	j.fn(s.ctx)
+}
@@ -0,0 +1,105 @@
+package timeutil
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pylemonorg/gotools/logger"
+)
+
+// Lap 秒表的一个阶段。
+type Lap struct {
+	Name     string
+	Duration time.Duration
+}
+
+// Stopwatch 多阶段计时器，用于统计流水线各阶段的耗时。并发安全。
+//
+// 用法：
+//
+//	sw := timeutil.NewStopwatch()
+//	fetch()
+//	sw.Lap("fetch")
+//	parse()
+//	sw.Lap("parse")
+//	sw.Log("ProcessBatch") // ProcessBatch 耗时明细: fetch 1.20秒 (80.0%) | parse 300ms (20.0%) | 总耗时 1.50秒
+type Stopwatch struct {
+	mu    sync.Mutex
+	start time.Time
+	last  time.Time
+	laps  []Lap
+}
+
+// NewStopwatch 创建并立即开始计时的秒表。
+func NewStopwatch() *Stopwatch {
+	now := time.Now()
+	return &Stopwatch{start: now, last: now}
+}
+
+// Lap 结束当前阶段并命名，返回该阶段耗时（自上一次 Lap 或创建以来）。
+// 同名阶段多次调用时耗时累加（适合循环中统计）。
+func (sw *Stopwatch) Lap(name string) time.Duration {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	now := time.Now()
+	d := now.Sub(sw.last)
+	sw.last = now
+	for i := range sw.laps {
+		if sw.laps[i].Name == name {
+			sw.laps[i].Duration += d
+			return d
+		}
+	}
+	sw.laps = append(sw.laps, Lap{Name: name, Duration: d})
+	return d
+}
+
+// Reset 清空所有阶段并重新开始计时。
+func (sw *Stopwatch) Reset() {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	now := time.Now()
+	sw.start, sw.last, sw.laps = now, now, nil
+}
+
+// Elapsed 返回自创建（或 Reset）以来的总耗时。
+func (sw *Stopwatch) Elapsed() time.Duration {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	return time.Since(sw.start)
+}
+
+// Laps 返回各阶段耗时（按首次出现顺序）的副本。
+func (sw *Stopwatch) Laps() []Lap {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	laps := make([]Lap, len(sw.laps))
+	copy(laps, sw.laps)
+	return laps
+}
+
+// Report 返回各阶段耗时及占比的单行报告，总耗时截止到最后一次 Lap。
+func (sw *Stopwatch) Report() string {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	total := sw.last.Sub(sw.start)
+	parts := make([]string, 0, len(sw.laps)+1)
+	for _, l := range sw.laps {
+		pct := 0.0
+		if total > 0 {
+			pct = float64(l.Duration) / float64(total) * 100
+		}
+		parts = append(parts, fmt.Sprintf("%s %s (%.1f%%)", l.Name, FormatDuration(l.Duration), pct))
+	}
+	parts = append(parts, "总耗时 "+FormatDuration(total))
+	return strings.Join(parts, " | ")
+}
+
+// Log 以 info 级别记录 Report 的内容。
+func (sw *Stopwatch) Log(name string) {
+	logger.Infof("%s 耗时明细: %s", name, sw.Report())
+}
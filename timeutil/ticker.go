@@ -0,0 +1,104 @@
+package timeutil
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// Ticker 可在 select 中使用的定时器，语义与 time.Ticker 相同：
+// 接收方处理不及时时丢弃多余的 tick，不会积压。
+type Ticker struct {
+	C <-chan time.Time
+
+	stop chan struct{}
+	once sync.Once
+}
+
+// NewJitterTicker 创建间隔在 base 的 ±jitterFraction 范围内随机浮动的 Ticker（jitterFraction 取值 0~1）。
+// 多实例部署的周期任务使用随机间隔，可避免同一时刻集中访问 OBS / Redis。base <= 0 时 panic。
+//
+// 用法：
+//
+//	t := timeutil.NewJitterTicker(time.Minute, 0.2) // 48s ~ 72s
+//	defer t.Stop()
+//	for {
+//	    select {
+//	    case <-ctx.Done():
+//	        return
+//	    case <-t.C:
+//	        flush()
+//	    }
+//	}
+func NewJitterTicker(base time.Duration, jitterFraction float64) *Ticker {
+	if base <= 0 {
+		panic("timeutil: NewJitterTicker 的 base 必须大于 0")
+	}
+	return newTicker(func() time.Duration {
+		return jitterDuration(base, jitterFraction)
+	})
+}
+
+// NewRateTicker 创建每秒触发 opsPerSecond 次的 Ticker，用于限制操作速率（如每秒最多 200 次请求）。
+// opsPerSecond <= 0 时 panic。
+//
+// 用法：
+//
+//	t := timeutil.NewRateTicker(200)
+//	defer t.Stop()
+//	for _, key := range keys {
+//	    <-t.C
+//	    client.Get(key)
+//	}
+func NewRateTicker(opsPerSecond float64) *Ticker {
+	if opsPerSecond <= 0 {
+		panic("timeutil: NewRateTicker 的 opsPerSecond 必须大于 0")
+	}
+	interval := time.Duration(float64(time.Second) / opsPerSecond)
+	if interval <= 0 {
+		interval = 1
+	}
+	return newTicker(func() time.Duration { return interval })
+}
+
+// newTicker 启动按 next 返回的间隔触发的 Ticker。
+func newTicker(next func() time.Duration) *Ticker {
+	c := make(chan time.Time, 1)
+	t := &Ticker{C: c, stop: make(chan struct{})}
+	go func() {
+		timer := time.NewTimer(next())
+		defer timer.Stop()
+		for {
+			select {
+			case <-t.stop:
+				return
+			case now := <-timer.C:
+				select {
+				case c <- now:
+				default:
+				}
+				timer.Reset(next())
+			}
+		}
+	}()
+	return t
+}
+
+// Stop 停止 Ticker，之后不再发送 tick（不关闭 C）。可重复调用。
+func (t *Ticker) Stop() {
+	t.once.Do(func() {
+		close(t.stop)
+	})
+}
+
+// jitterDuration 在 d 的 ±fraction 范围内随机取值，fraction 限制在 0~1。
+func jitterDuration(d time.Duration, fraction float64) time.Duration {
+	if fraction <= 0 {
+		return d
+	}
+	if fraction > 1 {
+		fraction = 1
+	}
+	delta := fraction * float64(d)
+	return time.Duration(float64(d) - delta + rand.Float64()*2*delta)
+}
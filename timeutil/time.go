@@ -7,22 +7,76 @@ import (
 	"github.com/pylemonorg/gotools/logger"
 )
 
+// DurationOption FormatDuration 的格式选项。
+type DurationOption func(*durationFormat)
+
+// durationFormat 格式选项集合。
+type durationFormat struct {
+	english bool
+	hours   bool
+}
+
+// WithEnglish 使用英文单位输出："320ms"、"2.50s"、"3m12s"。
+func WithEnglish() DurationOption {
+	return func(f *durationFormat) {
+		f.english = true
+	}
+}
+
+// WithHours 超过 1 小时时按小时输出："2小时3分"、"2h3m"（为 0 的分 / 秒省略）。
+func WithHours() DurationOption {
+	return func(f *durationFormat) {
+		f.hours = true
+	}
+}
+
 // FormatDuration 将 time.Duration 格式化为人类可读的字符串。
 // < 1s → "320ms"，< 1min → "2.50秒"，>= 1min → "3分12秒"。
-func FormatDuration(d time.Duration) string {
+// opts 可选 WithEnglish（英文单位）、WithHours（按小时输出长耗时）。
+//
+// 用法：
+//
+//	timeutil.FormatDuration(125 * time.Minute)                                             // "125分0秒"
+//	timeutil.FormatDuration(125*time.Minute, timeutil.WithHours())                         // "2小时5分"
+//	timeutil.FormatDuration(125*time.Minute, timeutil.WithHours(), timeutil.WithEnglish()) // "2h5m"
+func FormatDuration(d time.Duration, opts ...DurationOption) string {
+	var f durationFormat
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&f)
+		}
+	}
+	secUnit, minUnit, hourUnit := "秒", "分", "小时"
+	if f.english {
+		secUnit, minUnit, hourUnit = "s", "m", "h"
+	}
+
 	switch {
 	case d < time.Second:
 		return fmt.Sprintf("%dms", d.Milliseconds())
 	case d < time.Minute:
-		return fmt.Sprintf("%.2f秒", d.Seconds())
+		return fmt.Sprintf("%.2f%s", d.Seconds(), secUnit)
+	case f.hours && d >= time.Hour:
+		h := int(d.Hours())
+		m := int(d.Minutes()) % 60
+		sec := int(d.Seconds()) % 60
+		out := fmt.Sprintf("%d%s", h, hourUnit)
+		if m > 0 {
+			out += fmt.Sprintf("%d%s", m, minUnit)
+		}
+		if sec > 0 {
+			out += fmt.Sprintf("%d%s", sec, secUnit)
+		}
+		return out
 	default:
 		m := int(d.Minutes())
-		s := int(d.Seconds()) % 60
-		return fmt.Sprintf("%d分%d秒", m, s)
+		sec := int(d.Seconds()) % 60
+		return fmt.Sprintf("%d%s%d%s", m, minUnit, sec, secUnit)
 	}
 }
 
 // TrackTime 返回一个 deferred 函数，用于统计并记录代码块的执行耗时。
+// 需要分阶段统计时使用 Stopwatch。
 //
 // 用法：
 //
@@ -0,0 +1,48 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/pylemonorg/gotools/obsutil"
+)
+
+// InstrumentObs 返回对每次 OBS API 调用生成 span 的 ObsClient 副本（共享底层连接），span 以 ctx 中的 span 为父级。
+// 副本很轻量，可在每个请求中创建；会替换 oc 上已有的 TraceHook。
+//
+// 用法：
+//
+//	obs := tracing.InstrumentObs(ctx, s.obs)
+//	_, err := obs.PutBytes(key, data)
+func InstrumentObs(ctx context.Context, oc *obsutil.ObsClient) *obsutil.ObsClient {
+	return oc.WithTrace(ObsHook(ctx))
+}
+
+// ObsHook 返回以 ctx 中的 span 为父级生成 span 的 obsutil.TraceHook。
+// span 名为 "obs.<API 名>"（如 "obs.PutObject"），属性包含存储桶、对象 key、HTTP 状态码与 request ID。
+func ObsHook(ctx context.Context) obsutil.TraceHook {
+	return func(t obsutil.OpTrace) {
+		end := time.Now()
+		attrs := []attribute.KeyValue{
+			attribute.String("obs.operation", t.Op),
+			attribute.String("obs.bucket", t.Bucket),
+		}
+		if t.Key != "" {
+			attrs = append(attrs, attribute.String("obs.key", t.Key))
+		}
+		if t.StatusCode != 0 {
+			attrs = append(attrs, attribute.Int("http.response.status_code", t.StatusCode))
+		}
+		if t.RequestID != "" {
+			attrs = append(attrs, attribute.String("obs.request_id", t.RequestID))
+		}
+		_, span := Tracer().Start(ctx, "obs."+t.Op,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithTimestamp(end.Add(-t.Duration)),
+			trace.WithAttributes(attrs...))
+		End(span, t.Err, trace.WithTimestamp(end))
+	}
+}
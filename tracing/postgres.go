@@ -0,0 +1,45 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/pylemonorg/gotools/db"
+)
+
+// InstrumentPostgres 返回对每次 SQL 调用生成 span 的 PostgresClient 副本（共享连接池），span 以 ctx 中的 span 为父级。
+// 副本很轻量，可在每个请求中创建；会替换 c 上已有的 QueryTraceHook。
+//
+// 用法：
+//
+//	func (s *Service) GetUser(ctx context.Context, id int64) (*User, error) {
+//	    pg := tracing.InstrumentPostgres(ctx, s.pg)
+//	    return queryUser(pg, id)
+//	}
+func InstrumentPostgres(ctx context.Context, c *db.PostgresClient) *db.PostgresClient {
+	return c.WithTrace(PostgresHook(ctx))
+}
+
+// PostgresHook 返回以 ctx 中的 span 为父级生成 span 的 db.QueryTraceHook。
+// span 名为 "postgres.<方法名>"（如 "postgres.Query"），属性包含 SQL 摘要（见 QueryDigest）与行数，不记录参数值。
+func PostgresHook(ctx context.Context) db.QueryTraceHook {
+	return func(t db.QueryTrace) {
+		end := time.Now()
+		attrs := []attribute.KeyValue{
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.operation", t.Op),
+			attribute.String("db.statement", QueryDigest(t.Query)),
+		}
+		if t.Rows >= 0 {
+			attrs = append(attrs, attribute.Int64("db.rows", t.Rows))
+		}
+		_, span := Tracer().Start(ctx, "postgres."+t.Op,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithTimestamp(end.Add(-t.Duration)),
+			trace.WithAttributes(attrs...))
+		End(span, t.Err, trace.WithTimestamp(end))
+	}
+}
@@ -0,0 +1,114 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/pylemonorg/gotools/db"
+)
+
+// InstrumentRedis 为 rc 添加追踪钩子，之后每条命令（含 Pipeline）生成一个 span，重连后仍然有效。
+// span 的父级取自命令的 context，即 rc.SetContext 设置的 context 或直接调用 GetClient() 时传入的 ctx。
+//
+// 用法：
+//
+//	tracing.InstrumentRedis(redisClient)
+//
+//	// 需要与请求的 span 关联时，直接使用底层客户端并传入请求的 ctx
+//	val, err := redisClient.GetClient().Get(ctx, key).Result()
+func InstrumentRedis(rc *db.RedisClient) {
+	rc.AddHook(RedisHook())
+}
+
+// RedisHook 返回生成 span 的 go-redis 钩子，可用于未经 db.RedisClient 封装的 redis.Client。
+// span 名为 "redis.<命令>"（如 "redis.get"），属性包含命令名与第一个 key（不记录值）；redis.Nil 不视为错误。
+func RedisHook() redis.Hook {
+	return redisHook{}
+}
+
+// redisHook 实现 redis.Hook。
+type redisHook struct{}
+
+// DialHook 不追踪建立连接。
+func (redisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+// ProcessHook 为单条命令生成 span。
+func (redisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		attrs := []attribute.KeyValue{
+			attribute.String("db.system", "redis"),
+			attribute.String("db.operation", cmd.Name()),
+		}
+		if key := redisCmdKey(cmd); key != "" {
+			attrs = append(attrs, attribute.String("db.redis.key", key))
+		}
+		ctx, span := Tracer().Start(ctx, "redis."+cmd.Name(),
+			trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+
+		err := next(ctx, cmd)
+		End(span, redisErr(err))
+		return err
+	}
+}
+
+// ProcessPipelineHook 为整个 Pipeline / 事务生成一个 span，属性包含命令数与去重后的命令名。
+func (redisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		names := make([]string, 0, len(cmds))
+		seen := make(map[string]bool, len(cmds))
+		for _, cmd := range cmds {
+			if name := cmd.Name(); !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+		ctx, span := Tracer().Start(ctx, "redis.pipeline",
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("db.system", "redis"),
+				attribute.String("db.operation", strings.Join(names, " ")),
+				attribute.Int("db.redis.pipeline_length", len(cmds)),
+			))
+
+		err := next(ctx, cmds)
+		End(span, redisErr(err))
+		return err
+	}
+}
+
+// redisCmdKey 返回命令的第一个参数（大多数命令为 key），不是字符串或命令无参数时返回空串。
+func redisCmdKey(cmd redis.Cmder) string {
+	args := cmd.Args()
+	if len(args) < 2 {
+		return ""
+	}
+	switch cmd.Name() {
+	case "eval", "evalsha", "eval_ro", "evalsha_ro":
+		// EVAL script numkeys key ...
+		if len(args) < 4 {
+			return ""
+		}
+		key, _ := args[3].(string)
+		return key
+	}
+	key, _ := args[1].(string)
+	return key
+}
+
+// redisErr 过滤 redis.Nil（key 不存在不是错误）。
+func redisErr(err error) error {
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,113 @@
+// Package tracing 提供 OpenTelemetry 链路追踪的便捷封装，并为 db（Redis / PostgreSQL）与 obsutil 的调用生成 span。
+//
+// 默认使用 otel.GetTracerProvider()，即应用通过 otel.SetTracerProvider 配置的全局 provider；
+// 未配置时为空操作实现，不产生任何开销以外的影响。
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/pylemonorg/gotools/strutil"
+)
+
+// instrumentationName 本包创建 tracer 时使用的名称。
+const instrumentationName = "github.com/pylemonorg/gotools/tracing"
+
+// maxDigestRunes QueryDigest 结果的最大字符数。
+const maxDigestRunes = 256
+
+// provider 通过 SetTracerProvider 设置的 TracerProvider，为空时使用全局 provider。
+var provider atomic.Pointer[trace.TracerProvider]
+
+// SetTracerProvider 设置本包使用的 TracerProvider（如仅为 db / obs 调用单独配置采样），nil 时恢复使用全局 provider。
+func SetTracerProvider(tp trace.TracerProvider) {
+	if tp == nil {
+		provider.Store(nil)
+		return
+	}
+	provider.Store(&tp)
+}
+
+// Tracer 返回本包使用的 tracer。
+func Tracer() trace.Tracer {
+	if tp := provider.Load(); tp != nil {
+		return (*tp).Tracer(instrumentationName)
+	}
+	return otel.GetTracerProvider().Tracer(instrumentationName)
+}
+
+// StartSpan 以 ctx 中的 span 为父级开始一个新 span，返回携带新 span 的 context。
+// 调用方负责结束 span，通常使用 End 记录错误并结束。
+//
+// 用法：
+//
+//	ctx, span := tracing.StartSpan(ctx, "crawler.fetch", attribute.String("url", u))
+//	body, err := fetch(ctx, u)
+//	tracing.End(span, err)
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End 结束 span；err 非 nil 时记录错误事件并将状态设为 Error。
+func End(span trace.Span, err error, opts ...trace.SpanEndOption) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End(opts...)
+}
+
+// Run 在名为 name 的 span 中执行 fn，fn 返回的错误会记录到 span 上并原样返回；fn panic 时同样记录后继续 panic。
+//
+// 用法：
+//
+//	err := tracing.Run(ctx, "order.sync", func(ctx context.Context) error {
+//	    return syncOrders(ctx)
+//	})
+func Run(ctx context.Context, name string, fn func(ctx context.Context) error, attrs ...attribute.KeyValue) (err error) {
+	ctx, span := StartSpan(ctx, name, attrs...)
+	defer func() {
+		if r := recover(); r != nil {
+			End(span, fmt.Errorf("panic: %v", r), trace.WithStackTrace(true))
+			panic(r)
+		}
+		End(span, err)
+	}()
+	return fn(ctx)
+}
+
+var (
+	digestStringRe = regexp.MustCompile(`'(?:[^']|'')*'`)
+	digestNumberRe = regexp.MustCompile(`\b\d+(?:\.\d+)?\b`)
+	digestListRe   = regexp.MustCompile(`\(\s*\?(?:\s*,\s*\?)+\s*\)`)
+	digestParamRe  = regexp.MustCompile(`\$\d+`)
+)
+
+// QueryDigest 返回 SQL 的摘要，用作 span 属性：合并空白，字符串与数字字面量替换为 "?"，
+// 占位符 $1、$2 统一为 "?"，IN 列表 "(?, ?, ?)" 折叠为 "(?)"，超过 256 个字符时截断。
+// 摘要不含参数值，相同结构的语句得到相同摘要，便于在追踪系统中聚合。
+//
+// 用法：
+//
+//	tracing.QueryDigest("SELECT * FROM users WHERE id IN (1, 2, 3) AND name = 'a'")
+//	// "SELECT * FROM users WHERE id IN (?) AND name = ?"
+func QueryDigest(query string) string {
+	s := strings.Join(strings.Fields(query), " ")
+	s = digestStringRe.ReplaceAllString(s, "?")
+	s = digestParamRe.ReplaceAllString(s, "?")
+	s = digestNumberRe.ReplaceAllString(s, "?")
+	s = digestListRe.ReplaceAllString(s, "(?)")
+	if len([]rune(s)) > maxDigestRunes {
+		s = strutil.TruncateRunes(s, maxDigestRunes) + "..."
+	}
+	return s
+}
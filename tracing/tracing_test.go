@@ -0,0 +1,234 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/pylemonorg/gotools/db"
+	"github.com/pylemonorg/gotools/obsutil"
+)
+
+// ---------------------------------------------------------------------------
+// 记录 span 的测试用 TracerProvider
+// ---------------------------------------------------------------------------
+
+type recordedSpan struct {
+	noop.Span
+	name   string
+	parent trace.Span
+	start  time.Time
+	end    time.Time
+	attrs  map[attribute.Key]attribute.Value
+	status codes.Code
+	errs   []error
+	ended  bool
+}
+
+func (s *recordedSpan) SetStatus(code codes.Code, _ string) { s.status = code }
+
+func (s *recordedSpan) RecordError(err error, _ ...trace.EventOption) { s.errs = append(s.errs, err) }
+
+func (s *recordedSpan) End(opts ...trace.SpanEndOption) {
+	s.ended = true
+	cfg := trace.NewSpanEndConfig(opts...)
+	s.end = cfg.Timestamp()
+}
+
+type recorder struct {
+	embedded.Tracer
+
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+type recorderProvider struct {
+	embedded.TracerProvider
+	r *recorder
+}
+
+func (p recorderProvider) Tracer(string, ...trace.TracerOption) trace.Tracer { return p.r }
+
+func (r *recorder) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	cfg := trace.NewSpanStartConfig(opts...)
+	s := &recordedSpan{
+		name:   name,
+		parent: trace.SpanFromContext(ctx),
+		start:  cfg.Timestamp(),
+		attrs:  make(map[attribute.Key]attribute.Value),
+	}
+	for _, kv := range cfg.Attributes() {
+		s.attrs[kv.Key] = kv.Value
+	}
+	r.mu.Lock()
+	r.spans = append(r.spans, s)
+	r.mu.Unlock()
+	return trace.ContextWithSpan(ctx, s), s
+}
+
+func useRecorder(t *testing.T) *recorder {
+	r := &recorder{}
+	SetTracerProvider(recorderProvider{r: r})
+	t.Cleanup(func() { SetTracerProvider(nil) })
+	return r
+}
+
+// ---------------------------------------------------------------------------
+// StartSpan / End / Run
+// ---------------------------------------------------------------------------
+
+func TestRun(t *testing.T) {
+	r := useRecorder(t)
+
+	wantErr := errors.New("boom")
+	err := Run(context.Background(), "outer", func(ctx context.Context) error {
+		_, child := StartSpan(ctx, "inner", attribute.String("k", "v"))
+		End(child, nil)
+		return wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("Run 返回 %v, 期望 %v", err, wantErr)
+	}
+	if len(r.spans) != 2 {
+		t.Fatalf("span 数 = %d, 期望 2", len(r.spans))
+	}
+	outer, inner := r.spans[0], r.spans[1]
+	if !outer.ended || outer.status != codes.Error || len(outer.errs) != 1 {
+		t.Errorf("outer = %+v, 期望已结束且记录错误", outer)
+	}
+	if !inner.ended || inner.status != codes.Unset || inner.parent != outer || inner.attrs["k"].AsString() != "v" {
+		t.Errorf("inner = %+v, 期望以 outer 为父级且无错误", inner)
+	}
+}
+
+func TestRunPanic(t *testing.T) {
+	r := useRecorder(t)
+	defer func() {
+		if recover() == nil {
+			t.Error("期望继续 panic")
+		}
+		if len(r.spans) != 1 || !r.spans[0].ended || r.spans[0].status != codes.Error {
+			t.Errorf("spans = %+v, 期望记录 panic", r.spans)
+		}
+	}()
+	_ = Run(context.Background(), "panic", func(context.Context) error { panic("oops") })
+}
+
+// ---------------------------------------------------------------------------
+// QueryDigest
+// ---------------------------------------------------------------------------
+
+func TestQueryDigest(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"SELECT * FROM users WHERE id IN (1, 2, 3) AND name = 'a''b'", "SELECT * FROM users WHERE id IN (?) AND name = ?"},
+		{"SELECT id\n  FROM t2\n WHERE created_at > $1 AND score >= 0.5", "SELECT id FROM t2 WHERE created_at > ? AND score >= ?"},
+		{"INSERT INTO t (a, b) VALUES ($1, $2)", "INSERT INTO t (a, b) VALUES (?)"},
+		{"UPDATE t SET n = n + 1 WHERE id = $1", "UPDATE t SET n = n + ? WHERE id = ?"},
+	}
+	for _, tt := range tests {
+		if got := QueryDigest(tt.input); got != tt.want {
+			t.Errorf("QueryDigest(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+
+	long := "SELECT " + strings.Repeat("col, ", 100) + "x FROM t"
+	if got := QueryDigest(long); len([]rune(got)) != maxDigestRunes+3 || !strings.HasSuffix(got, "...") {
+		t.Errorf("长语句未截断: %d 字符", len([]rune(got)))
+	}
+}
+
+// ---------------------------------------------------------------------------
+// PostgresHook / ObsHook
+// ---------------------------------------------------------------------------
+
+func TestPostgresHook(t *testing.T) {
+	r := useRecorder(t)
+	ctx, parent := StartSpan(context.Background(), "request")
+
+	hook := PostgresHook(ctx)
+	hook(db.QueryTrace{Op: "Exec", Query: "DELETE FROM t WHERE id = $1", Duration: 20 * time.Millisecond, Rows: 3})
+	hook(db.QueryTrace{Op: "Query", Query: "SELECT 1", Rows: -1, Err: errors.New("conn reset")})
+
+	if len(r.spans) != 3 {
+		t.Fatalf("span 数 = %d, 期望 3", len(r.spans))
+	}
+	exec := r.spans[1]
+	if exec.name != "postgres.Exec" || exec.parent != parent || exec.attrs["db.statement"].AsString() != "DELETE FROM t WHERE id = ?" || exec.attrs["db.rows"].AsInt64() != 3 {
+		t.Errorf("exec span = %+v", exec)
+	}
+	if d := exec.end.Sub(exec.start); d != 20*time.Millisecond {
+		t.Errorf("exec span 时长 = %v, 期望 20ms", d)
+	}
+	query := r.spans[2]
+	if _, ok := query.attrs["db.rows"]; ok || query.status != codes.Error {
+		t.Errorf("query span = %+v, 期望无行数且状态为 Error", query)
+	}
+}
+
+func TestObsHook(t *testing.T) {
+	r := useRecorder(t)
+
+	ObsHook(context.Background())(obsutil.OpTrace{
+		Op: "PutObject", Bucket: "b", Key: "a/b.json", Duration: time.Millisecond, StatusCode: 200, RequestID: "req-1",
+	})
+	if len(r.spans) != 1 {
+		t.Fatalf("span 数 = %d, 期望 1", len(r.spans))
+	}
+	s := r.spans[0]
+	if s.name != "obs.PutObject" || s.attrs["obs.key"].AsString() != "a/b.json" ||
+		s.attrs["http.response.status_code"].AsInt64() != 200 || s.attrs["obs.request_id"].AsString() != "req-1" || s.status != codes.Unset {
+		t.Errorf("span = %+v", s)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// RedisHook
+// ---------------------------------------------------------------------------
+
+func TestRedisHook(t *testing.T) {
+	r := useRecorder(t)
+	ctx := context.Background()
+	hook := RedisHook()
+
+	process := hook.ProcessHook(func(context.Context, redis.Cmder) error { return redis.Nil })
+	if err := process(ctx, redis.NewStringCmd(ctx, "get", "user:1")); !errors.Is(err, redis.Nil) {
+		t.Fatalf("ProcessHook 返回 %v, 期望原样返回 redis.Nil", err)
+	}
+	pipeline := hook.ProcessPipelineHook(func(context.Context, []redis.Cmder) error { return errors.New("timeout") })
+	_ = pipeline(ctx, []redis.Cmder{
+		redis.NewStatusCmd(ctx, "set", "a", 1),
+		redis.NewStatusCmd(ctx, "set", "b", 2),
+		redis.NewIntCmd(ctx, "incr", "c"),
+	})
+
+	if len(r.spans) != 2 {
+		t.Fatalf("span 数 = %d, 期望 2", len(r.spans))
+	}
+	get := r.spans[0]
+	if get.name != "redis.get" || get.attrs["db.redis.key"].AsString() != "user:1" || get.status != codes.Unset {
+		t.Errorf("get span = %+v, 期望 redis.Nil 不视为错误", get)
+	}
+	pipe := r.spans[1]
+	if pipe.name != "redis.pipeline" || pipe.attrs["db.operation"].AsString() != "set incr" ||
+		pipe.attrs["db.redis.pipeline_length"].AsInt64() != 3 || pipe.status != codes.Error {
+		t.Errorf("pipeline span = %+v", pipe)
+	}
+
+	eval := redis.NewCmd(ctx, "evalsha", "sha", 1, "lock:order")
+	if key := redisCmdKey(eval); key != "lock:order" {
+		t.Errorf("redisCmdKey(evalsha) = %q, 期望 lock:order", key)
+	}
+}
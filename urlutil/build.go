@@ -0,0 +1,85 @@
+package urlutil
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Build 在 base 的路径后追加 pathSegments（每段单独做路径转义，段内的 "/" 会被编码为 %2F），
+// 并设置 query 参数（与 base 已有参数合并，同名覆盖，按 key 排序）。
+//
+// 用法：
+//
+//	u, err := urlutil.Build("https://api.example.com/v1", []string{"users", "a/b c"}, map[string]string{"page": "2"})
+//	// "https://api.example.com/v1/users/a%2Fb%20c?page=2"
+func Build(base string, pathSegments []string, query map[string]string) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("urlutil: 解析 base URL 失败: %w", err)
+	}
+
+	if len(pathSegments) > 0 {
+		escaped := strings.TrimSuffix(u.EscapedPath(), "/")
+		for _, seg := range pathSegments {
+			escaped += "/" + url.PathEscape(seg)
+		}
+		unescaped, err := url.PathUnescape(escaped)
+		if err != nil {
+			return "", fmt.Errorf("urlutil: 拼接路径失败: %w", err)
+		}
+		u.Path, u.RawPath = unescaped, escaped
+	}
+
+	if len(query) > 0 {
+		values := u.Query()
+		for k, v := range query {
+			values.Set(k, v)
+		}
+		u.RawQuery = values.Encode()
+	}
+	return u.String(), nil
+}
+
+// Expand 展开 URL 模板中的 {name} 占位符：路径部分的值做路径转义，"?" 之后的值做查询参数转义。
+// 模板中引用了 vars 中不存在的变量时返回错误，避免生成 "/users//posts" 这类错误 URL。
+//
+// 用法：
+//
+//	u, err := urlutil.Expand("/users/{id}/posts/{post}?q={q}", map[string]string{
+//	    "id": "42", "post": "a/b", "q": "x&y",
+//	})
+//	// "/users/42/posts/a%2Fb?q=x%26y"
+func Expand(tmpl string, vars map[string]string) (string, error) {
+	var b strings.Builder
+	b.Grow(len(tmpl))
+	inQuery := false
+
+	for i := 0; i < len(tmpl); i++ {
+		c := tmpl[i]
+		if c == '?' {
+			inQuery = true
+		}
+		if c != '{' {
+			b.WriteByte(c)
+			continue
+		}
+
+		end := strings.IndexByte(tmpl[i:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("urlutil: 模板 %q 中的 { 未闭合", tmpl)
+		}
+		name := tmpl[i+1 : i+end]
+		v, ok := vars[name]
+		if !ok {
+			return "", fmt.Errorf("urlutil: 模板 %q 缺少变量 %q", tmpl, name)
+		}
+		if inQuery {
+			b.WriteString(url.QueryEscape(v))
+		} else {
+			b.WriteString(url.PathEscape(v))
+		}
+		i += end
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,63 @@
+package urlutil
+
+import "testing"
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		base     string
+		segments []string
+		query    map[string]string
+		want     string
+	}{
+		{"https://api.example.com/v1", []string{"users", "a/b c"}, map[string]string{"page": "2"}, "https://api.example.com/v1/users/a%2Fb%20c?page=2"},
+		{"https://api.example.com/v1/", []string{"x"}, nil, "https://api.example.com/v1/x"},
+		{"https://api.example.com", []string{"中文", "100%"}, nil, "https://api.example.com/%E4%B8%AD%E6%96%87/100%25"},
+		{"https://api.example.com/a%2Fb", []string{"c"}, nil, "https://api.example.com/a%2Fb/c"},
+		{"https://api.example.com/s?b=1&a=0", nil, map[string]string{"b": "2", "q": "x&y"}, "https://api.example.com/s?a=0&b=2&q=x%26y"},
+		{"https://api.example.com/s?a=1", nil, nil, "https://api.example.com/s?a=1"},
+	}
+	for _, tt := range tests {
+		got, err := Build(tt.base, tt.segments, tt.query)
+		if err != nil {
+			t.Errorf("Build(%q, %q) error: %v", tt.base, tt.segments, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Build(%q, %q, %v) = %q, want %q", tt.base, tt.segments, tt.query, got, tt.want)
+		}
+	}
+
+	if _, err := Build("http://%zz", nil, nil); err == nil {
+		t.Error("Build with invalid base should fail")
+	}
+}
+
+func TestExpand(t *testing.T) {
+	vars := map[string]string{"id": "42", "post": "a/b", "q": "x&y", "sp": "a b"}
+	tests := []struct {
+		tmpl string
+		want string
+	}{
+		{"/users/{id}/posts/{post}?q={q}", "/users/42/posts/a%2Fb?q=x%26y"},
+		{"https://example.com/{sp}?s={sp}", "https://example.com/a%20b?s=a+b"},
+		{"/static/path", "/static/path"},
+		{"{id}{id}", "4242"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		got, err := Expand(tt.tmpl, vars)
+		if err != nil {
+			t.Errorf("Expand(%q) error: %v", tt.tmpl, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Expand(%q) = %q, want %q", tt.tmpl, got, tt.want)
+		}
+	}
+
+	for _, tmpl := range []string{"/users/{missing}", "/users/{id", "/{}"} {
+		if got, err := Expand(tmpl, vars); err == nil {
+			t.Errorf("Expand(%q) = %q, want error", tmpl, got)
+		}
+	}
+}
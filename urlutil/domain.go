@@ -0,0 +1,90 @@
+package urlutil
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// Hostname 返回 URL 的主机名（小写、不含端口和末尾的 "."）。
+// rawURL 不含 "://" 时视为裸主机名（可带端口），如 "www.example.com:8080"。
+func Hostname(rawURL string) (string, error) {
+	s := strings.TrimSpace(rawURL)
+	if !strings.Contains(s, "://") {
+		s = "//" + s
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("urlutil: 解析 URL 失败: %w", err)
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "" {
+		return "", fmt.Errorf("urlutil: URL 不含主机名: %s", rawURL)
+	}
+	return host, nil
+}
+
+// RegistrableDomain 返回 URL 的可注册域名（eTLD+1），基于 Public Suffix List，
+// 正确处理 co.uk、com.cn 等多级后缀。IP 地址原样返回。
+//
+// 用法：
+//
+//	urlutil.RegistrableDomain("https://news.bbc.co.uk/a") // "bbc.co.uk"
+//	urlutil.RegistrableDomain("www.example.com.cn")       // "example.com.cn"
+func RegistrableDomain(rawURL string) (string, error) {
+	host, err := Hostname(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if net.ParseIP(host) != nil {
+		return host, nil
+	}
+	domain, err := publicsuffix.EffectiveTLDPlusOne(host)
+	if err != nil {
+		return "", fmt.Errorf("urlutil: 获取可注册域名失败: %w", err)
+	}
+	return domain, nil
+}
+
+// Subdomain 返回可注册域名之前的子域名部分，如 "a.b.example.co.uk" → "a.b"；没有子域名或出错时返回空串。
+func Subdomain(rawURL string) string {
+	host, err := Hostname(rawURL)
+	if err != nil {
+		return ""
+	}
+	domain, err := RegistrableDomain(host)
+	if err != nil || domain == host {
+		return ""
+	}
+	return strings.TrimSuffix(host, "."+domain)
+}
+
+// IsSameSite 判断两个 URL 是否属于同一站点（可注册域名相同），任一无法解析时返回 false。
+//
+// 用法：
+//
+//	urlutil.IsSameSite("https://www.example.com", "http://img.example.com/x.png") // true
+//	urlutil.IsSameSite("https://a.github.io", "https://b.github.io")             // false（github.io 是公共后缀）
+func IsSameSite(a, b string) bool {
+	da, err := RegistrableDomain(a)
+	if err != nil {
+		return false
+	}
+	db, err := RegistrableDomain(b)
+	if err != nil {
+		return false
+	}
+	return da == db
+}
+
+// IsIPHost 判断 URL 的主机是否为 IP 地址（IPv4 或 IPv6）。
+func IsIPHost(rawURL string) bool {
+	host, err := Hostname(rawURL)
+	if err != nil {
+		return false
+	}
+	return net.ParseIP(host) != nil
+}
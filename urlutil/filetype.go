@@ -0,0 +1,254 @@
+package urlutil
+
+import (
+	"mime"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// FileCategory URL 指向资源的类别，按扩展名判断。
+type FileCategory string
+
+const (
+	CategoryUnknown    FileCategory = ""           // 无扩展名或未收录的扩展名
+	CategoryPage       FileCategory = "page"       // 网页：.html、.php、.aspx 等
+	CategoryImage      FileCategory = "image"      // 图片：.jpg、.png、.webp、.svg 等
+	CategoryVideo      FileCategory = "video"      // 视频：.mp4、.mkv、.m3u8 等
+	CategoryAudio      FileCategory = "audio"      // 音频：.mp3、.flac、.m4a 等
+	CategoryDocument   FileCategory = "document"   // 文档：.pdf、.docx、.xlsx、.txt 等
+	CategoryArchive    FileCategory = "archive"    // 压缩包：.zip、.tar.gz、.7z 等
+	CategoryExecutable FileCategory = "executable" // 可执行文件 / 安装包：.exe、.apk、.dmg 等
+	CategoryCode       FileCategory = "code"       // 前端资源与数据：.js、.css、.json、.xml 等
+)
+
+// extInfo 扩展名对应的 MIME 类型与类别。
+type extInfo struct {
+	mime     string
+	category FileCategory
+}
+
+// knownExts 常见扩展名表（小写、含 "."）。
+// 内置而非依赖 mime.TypeByExtension，避免结果随系统 mime.types 变化。
+var knownExts = map[string]extInfo{
+	// 网页
+	".html":  {"text/html", CategoryPage},
+	".htm":   {"text/html", CategoryPage},
+	".shtml": {"text/html", CategoryPage},
+	".xhtml": {"application/xhtml+xml", CategoryPage},
+	".php":   {"text/html", CategoryPage},
+	".asp":   {"text/html", CategoryPage},
+	".aspx":  {"text/html", CategoryPage},
+	".jsp":   {"text/html", CategoryPage},
+	".do":    {"text/html", CategoryPage},
+
+	// 图片
+	".jpg":  {"image/jpeg", CategoryImage},
+	".jpeg": {"image/jpeg", CategoryImage},
+	".png":  {"image/png", CategoryImage},
+	".gif":  {"image/gif", CategoryImage},
+	".webp": {"image/webp", CategoryImage},
+	".bmp":  {"image/bmp", CategoryImage},
+	".svg":  {"image/svg+xml", CategoryImage},
+	".ico":  {"image/x-icon", CategoryImage},
+	".tif":  {"image/tiff", CategoryImage},
+	".tiff": {"image/tiff", CategoryImage},
+	".avif": {"image/avif", CategoryImage},
+	".heic": {"image/heic", CategoryImage},
+
+	// 视频
+	".mp4":  {"video/mp4", CategoryVideo},
+	".m4v":  {"video/mp4", CategoryVideo},
+	".webm": {"video/webm", CategoryVideo},
+	".mkv":  {"video/x-matroska", CategoryVideo},
+	".avi":  {"video/x-msvideo", CategoryVideo},
+	".mov":  {"video/quicktime", CategoryVideo},
+	".flv":  {"video/x-flv", CategoryVideo},
+	".wmv":  {"video/x-ms-wmv", CategoryVideo},
+	".ts":   {"video/mp2t", CategoryVideo},
+	".m3u8": {"application/vnd.apple.mpegurl", CategoryVideo},
+
+	// 音频
+	".mp3":  {"audio/mpeg", CategoryAudio},
+	".wav":  {"audio/wav", CategoryAudio},
+	".flac": {"audio/flac", CategoryAudio},
+	".aac":  {"audio/aac", CategoryAudio},
+	".m4a":  {"audio/mp4", CategoryAudio},
+	".ogg":  {"audio/ogg", CategoryAudio},
+	".opus": {"audio/opus", CategoryAudio},
+	".wma":  {"audio/x-ms-wma", CategoryAudio},
+
+	// 文档
+	".pdf":  {"application/pdf", CategoryDocument},
+	".doc":  {"application/msword", CategoryDocument},
+	".docx": {"application/vnd.openxmlformats-officedocument.wordprocessingml.document", CategoryDocument},
+	".xls":  {"application/vnd.ms-excel", CategoryDocument},
+	".xlsx": {"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", CategoryDocument},
+	".ppt":  {"application/vnd.ms-powerpoint", CategoryDocument},
+	".pptx": {"application/vnd.openxmlformats-officedocument.presentationml.presentation", CategoryDocument},
+	".odt":  {"application/vnd.oasis.opendocument.text", CategoryDocument},
+	".ods":  {"application/vnd.oasis.opendocument.spreadsheet", CategoryDocument},
+	".rtf":  {"application/rtf", CategoryDocument},
+	".txt":  {"text/plain", CategoryDocument},
+	".md":   {"text/markdown", CategoryDocument},
+	".csv":  {"text/csv", CategoryDocument},
+	".epub": {"application/epub+zip", CategoryDocument},
+	".wps":  {"application/vnd.ms-works", CategoryDocument},
+
+	// 压缩包
+	".zip": {"application/zip", CategoryArchive},
+	".rar": {"application/vnd.rar", CategoryArchive},
+	".7z":  {"application/x-7z-compressed", CategoryArchive},
+	".tar": {"application/x-tar", CategoryArchive},
+	".gz":  {"application/gzip", CategoryArchive},
+	".tgz": {"application/gzip", CategoryArchive},
+	".bz2": {"application/x-bzip2", CategoryArchive},
+	".xz":  {"application/x-xz", CategoryArchive},
+	".zst": {"application/zstd", CategoryArchive},
+
+	// 可执行文件 / 安装包
+	".exe": {"application/vnd.microsoft.portable-executable", CategoryExecutable},
+	".msi": {"application/x-msi", CategoryExecutable},
+	".apk": {"application/vnd.android.package-archive", CategoryExecutable},
+	".dmg": {"application/x-apple-diskimage", CategoryExecutable},
+	".deb": {"application/vnd.debian.binary-package", CategoryExecutable},
+	".rpm": {"application/x-rpm", CategoryExecutable},
+	".iso": {"application/x-iso9660-image", CategoryExecutable},
+	".bin": {"application/octet-stream", CategoryExecutable},
+
+	// 前端资源与数据
+	".js":    {"text/javascript", CategoryCode},
+	".mjs":   {"text/javascript", CategoryCode},
+	".css":   {"text/css", CategoryCode},
+	".json":  {"application/json", CategoryCode},
+	".xml":   {"application/xml", CategoryCode},
+	".wasm":  {"application/wasm", CategoryCode},
+	".woff":  {"font/woff", CategoryCode},
+	".woff2": {"font/woff2", CategoryCode},
+	".ttf":   {"font/ttf", CategoryCode},
+}
+
+// ExtFromURL 返回 URL 路径最后一段的扩展名（小写、含 "."），忽略查询参数与 #fragment；
+// 路径以 "/" 结尾或最后一段不含 "." 时返回空串。路径中的百分号编码会先解码。
+//
+// 用法：
+//
+//	urlutil.ExtFromURL("https://cdn.example.com/a/Photo.JPG?w=200#top") // ".jpg"
+//	urlutil.ExtFromURL("https://example.com/v1.2/list")                 // ""
+//	urlutil.ExtFromURL("https://example.com/download?file=a.pdf")       // ""
+func ExtFromURL(rawURL string) string {
+	p := urlPath(rawURL)
+	if p == "" || strings.HasSuffix(p, "/") {
+		return ""
+	}
+	return strings.ToLower(path.Ext(path.Base(p)))
+}
+
+// urlPath 返回 URL 的路径部分（已解码），解析失败时退化为截掉 ? 和 # 之后的内容。
+func urlPath(rawURL string) string {
+	s := strings.TrimSpace(rawURL)
+	if u, err := url.Parse(s); err == nil {
+		return u.Path
+	}
+	if i := strings.IndexAny(s, "?#"); i >= 0 {
+		s = s[:i]
+	}
+	return s
+}
+
+// HasExtension 判断 URL 的扩展名是否为 exts 之一，exts 大小写不敏感、可带或不带 "."。
+//
+// 用法：
+//
+//	urlutil.HasExtension(link, "jpg", "png", ".webp")
+func HasExtension(rawURL string, exts ...string) bool {
+	ext := ExtFromURL(rawURL)
+	if ext == "" {
+		return false
+	}
+	for _, e := range exts {
+		if !strings.HasPrefix(e, ".") {
+			e = "." + e
+		}
+		if strings.EqualFold(ext, e) {
+			return true
+		}
+	}
+	return false
+}
+
+// GuessMIME 根据 URL 扩展名推测 MIME 类型（不含 charset 等参数），
+// 优先使用内置表，其次使用系统 mime 表，都无法识别时返回空串。
+//
+// 用法：
+//
+//	urlutil.GuessMIME("https://example.com/report.PDF?dl=1") // "application/pdf"
+func GuessMIME(rawURL string) string {
+	ext := ExtFromURL(rawURL)
+	if ext == "" {
+		return ""
+	}
+	if info, ok := knownExts[ext]; ok {
+		return info.mime
+	}
+	if typ := mime.TypeByExtension(ext); typ != "" {
+		mediaType, _, err := mime.ParseMediaType(typ)
+		if err == nil {
+			return mediaType
+		}
+		return typ
+	}
+	return ""
+}
+
+// Category 根据 URL 扩展名返回资源类别，无扩展名或未收录时返回 CategoryUnknown。
+// 注意无扩展名的 URL（如 "/news/123"）多为动态网页，但也可能是下载接口，需结合响应的 Content-Type 判断。
+func Category(rawURL string) FileCategory {
+	return knownExts[ExtFromURL(rawURL)].category
+}
+
+// IsPage 判断 URL 是否为网页：扩展名为 .html / .php 等，或没有扩展名。
+func IsPage(rawURL string) bool {
+	c := Category(rawURL)
+	return c == CategoryPage || (c == CategoryUnknown && ExtFromURL(rawURL) == "")
+}
+
+// IsImage 判断 URL 是否指向图片。
+func IsImage(rawURL string) bool { return Category(rawURL) == CategoryImage }
+
+// IsVideo 判断 URL 是否指向视频（含 .m3u8 播放列表）。
+func IsVideo(rawURL string) bool { return Category(rawURL) == CategoryVideo }
+
+// IsAudio 判断 URL 是否指向音频。
+func IsAudio(rawURL string) bool { return Category(rawURL) == CategoryAudio }
+
+// IsMedia 判断 URL 是否指向图片、视频或音频。
+func IsMedia(rawURL string) bool {
+	switch Category(rawURL) {
+	case CategoryImage, CategoryVideo, CategoryAudio:
+		return true
+	}
+	return false
+}
+
+// IsDocument 判断 URL 是否指向文档（PDF、Office、纯文本等）。
+func IsDocument(rawURL string) bool { return Category(rawURL) == CategoryDocument }
+
+// IsArchive 判断 URL 是否指向压缩包。
+func IsArchive(rawURL string) bool { return Category(rawURL) == CategoryArchive }
+
+// IsBinaryResource 判断 URL 是否指向非网页的静态资源（媒体、文档、压缩包、安装包、前端资源等），
+// 爬虫扩展待抓取队列时可用于过滤不需要解析链接的 URL。
+//
+// 用法：
+//
+//	for _, link := range links {
+//	    if urlutil.IsBinaryResource(link) {
+//	        continue
+//	    }
+//	    frontier.Push(link)
+//	}
+func IsBinaryResource(rawURL string) bool {
+	c := Category(rawURL)
+	return c != CategoryUnknown && c != CategoryPage
+}
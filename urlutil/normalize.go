@@ -0,0 +1,162 @@
+package urlutil
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// DefaultTrackingParams 默认去除的跟踪参数，以 * 结尾表示前缀匹配。
+var DefaultTrackingParams = []string{
+	"utm_*", "fbclid", "gclid", "dclid", "gclsrc", "msclkid", "yclid",
+	"mc_cid", "mc_eid", "_ga", "_gl", "igshid", "spm",
+}
+
+// NormalizeOptions URL 标准化选项，零值即默认行为。
+type NormalizeOptions struct {
+	UpgradeHTTPS   bool     // 将 http 升级为 https
+	KeepFragment   bool     // 保留 #fragment（默认去除）
+	KeepQueryOrder bool     // 保留查询参数原有顺序（默认按 key 排序）
+	KeepTracking   bool     // 保留跟踪参数（默认去除 DefaultTrackingParams）
+	StripParams    []string // 额外去除的查询参数，以 * 结尾表示前缀匹配
+}
+
+// Normalize 将 URL 规范化，用于去重和哈希：
+//   - scheme、host 转小写，去除默认端口（http:80、https:443）和 host 末尾的 "."
+//   - 解析路径中的 "." / ".." 段，空路径补为 "/"，百分号编码统一（不必要的编码如 %7E 还原，其余为大写形式，
+//     编码的 "/" 即 %2F 保留）
+//   - 查询参数按 key 排序，去除跟踪参数（utm_* 等）和空查询
+//   - 去除 #fragment
+//
+// opts 为 nil 时使用默认选项。rawURL 必须是带 scheme 和 host 的绝对 URL。
+//
+// 用法：
+//
+//	u, err := urlutil.Normalize("HTTP://Example.COM:80/a/./b/../c?b=2&a=1&utm_source=x#top", nil)
+//	// "http://example.com/a/c?a=1&b=2"
+func Normalize(rawURL string, opts *NormalizeOptions) (string, error) {
+	if opts == nil {
+		opts = &NormalizeOptions{}
+	}
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", fmt.Errorf("urlutil: 解析 URL 失败: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("urlutil: 不是绝对 URL: %s", rawURL)
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	if opts.UpgradeHTTPS && u.Scheme == "http" {
+		u.Scheme = "https"
+	}
+	u.Host = normalizeHost(u.Scheme, u.Host)
+
+	normalizePath(u)
+
+	if !opts.KeepFragment {
+		u.Fragment, u.RawFragment = "", ""
+	}
+	u.RawQuery = normalizeQuery(u.RawQuery, opts)
+	u.ForceQuery = false
+
+	return u.String(), nil
+}
+
+// normalizeHost host 转小写，去除默认端口和末尾的 "."。
+func normalizeHost(scheme, host string) string {
+	host = strings.ToLower(host)
+	hostname, port := host, ""
+	if i := strings.LastIndex(host, ":"); i >= 0 && !strings.HasSuffix(host, "]") {
+		hostname, port = host[:i], host[i+1:]
+	}
+	hostname = strings.TrimSuffix(hostname, ".")
+	if (scheme == "http" && port == "80") || (scheme == "https" && port == "443") || port == "" {
+		return hostname
+	}
+	return hostname + ":" + port
+}
+
+// normalizePath 解析 "." / ".." 段并统一百分号编码，保留末尾的 "/"。
+func normalizePath(u *url.URL) {
+	p := u.EscapedPath()
+	if p == "" {
+		u.Path, u.RawPath = "/", ""
+		return
+	}
+	cleaned := path.Clean(p)
+	if strings.HasSuffix(p, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+
+	unescaped, err := url.PathUnescape(cleaned)
+	if err != nil {
+		return
+	}
+	u.Path, u.RawPath = unescaped, ""
+	// 路径中包含编码的 "/"（%2F）时必须保留，否则语义会改变：逐段解码后重新编码，
+	// 段内的 "/" 编码为 %2F，其余字符与不含 %2F 的路径一样统一编码（如 %7E 还原为 "~"）
+	if strings.Contains(strings.ToUpper(cleaned), "%2F") {
+		segs := strings.Split(cleaned, "/")
+		for i, seg := range segs {
+			seg, _ = url.PathUnescape(seg) // 整体已解码成功，逐段不会失败
+			segs[i] = strings.ReplaceAll((&url.URL{Path: seg}).EscapedPath(), "/", "%2F")
+		}
+		u.RawPath = strings.Join(segs, "/")
+	}
+}
+
+// normalizeQuery 去除跟踪参数并（默认）按 key 排序。
+func normalizeQuery(rawQuery string, opts *NormalizeOptions) string {
+	if rawQuery == "" {
+		return ""
+	}
+
+	strip := opts.StripParams
+	if !opts.KeepTracking {
+		strip = append(append([]string{}, DefaultTrackingParams...), strip...)
+	}
+
+	if opts.KeepQueryOrder {
+		var kept []string
+		for _, pair := range strings.Split(rawQuery, "&") {
+			if pair == "" {
+				continue
+			}
+			key, _, _ := strings.Cut(pair, "=")
+			if k, err := url.QueryUnescape(key); err == nil && matchParam(k, strip) {
+				continue
+			}
+			kept = append(kept, pair)
+		}
+		return strings.Join(kept, "&")
+	}
+
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return rawQuery
+	}
+	for k := range values {
+		if matchParam(k, strip) {
+			delete(values, k)
+		}
+	}
+	return values.Encode()
+}
+
+// matchParam 判断参数名是否命中 patterns（大小写不敏感，* 结尾为前缀匹配）。
+func matchParam(key string, patterns []string) bool {
+	key = strings.ToLower(key)
+	for _, p := range patterns {
+		p = strings.ToLower(p)
+		if prefix, ok := strings.CutSuffix(p, "*"); ok {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		} else if key == p {
+			return true
+		}
+	}
+	return false
+}
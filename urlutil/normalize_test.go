@@ -0,0 +1,62 @@
+package urlutil
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		input string
+		opts  *NormalizeOptions
+		want  string
+	}{
+		{"HTTP://Example.COM:80/a/./b/../c?b=2&a=1&utm_source=x#top", nil, "http://example.com/a/c?a=1&b=2"},
+		{"https://example.com:443", nil, "https://example.com/"},
+		{"https://example.com:8443/", nil, "https://example.com:8443/"},
+		{"https://example.com./a/", nil, "https://example.com/a/"},
+		{"https://[::1]:443/", nil, "https://[::1]/"},
+		{"https://example.com/a//b/../c/", nil, "https://example.com/a/c/"},
+		{"https://example.com/%7euser/%e4%b8%ad", nil, "https://example.com/~user/%E4%B8%AD"},
+		{"https://example.com/a%20b", nil, "https://example.com/a%20b"},
+		{"https://example.com/?", nil, "https://example.com/"},
+		{"https://example.com/?fbclid=1&GCLID=2&utm_Medium=3", nil, "https://example.com/"},
+		{"http://example.com/#frag", &NormalizeOptions{UpgradeHTTPS: true, KeepFragment: true}, "https://example.com/#frag"},
+		{"https://example.com/?b=2&a=1&utm_source=x", &NormalizeOptions{KeepQueryOrder: true}, "https://example.com/?b=2&a=1"},
+		{"https://example.com/?utm_source=x&a=1", &NormalizeOptions{KeepTracking: true}, "https://example.com/?a=1&utm_source=x"},
+		{"https://example.com/?sid=1&ref_a=2&a=3", &NormalizeOptions{StripParams: []string{"sid", "ref_*"}}, "https://example.com/?a=3"},
+
+		// 编码的 "/" 保留，其余编码与普通路径一致
+		{"https://example.com/a%2fb/%7Ex", nil, "https://example.com/a%2Fb/~x"},
+		{"https://example.com/a%2Fb/%e4%b8%ad/./c", nil, "https://example.com/a%2Fb/%E4%B8%AD/c"},
+		{"https://example.com/a%2Fb/x%25y", nil, "https://example.com/a%2Fb/x%25y"},
+	}
+	for _, tt := range tests {
+		got, err := Normalize(tt.input, tt.opts)
+		if err != nil {
+			t.Errorf("Normalize(%q) error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeIdempotent(t *testing.T) {
+	for _, input := range []string{
+		"HTTP://Example.COM:80/a/./b/../c?b=2&a=1#top",
+		"https://example.com/a%2fb/%7Ex?q=%e4%b8%ad",
+	} {
+		once, _ := Normalize(input, nil)
+		twice, _ := Normalize(once, nil)
+		if once != twice {
+			t.Errorf("Normalize not idempotent: %q -> %q -> %q", input, once, twice)
+		}
+	}
+}
+
+func TestNormalizeError(t *testing.T) {
+	for _, input := range []string{"", "/relative/path", "example.com/a", "http://%zz"} {
+		if got, err := Normalize(input, nil); err == nil {
+			t.Errorf("Normalize(%q) = %q, want error", input, got)
+		}
+	}
+}
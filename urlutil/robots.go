@@ -0,0 +1,450 @@
+package urlutil
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// robots.txt 缓存与抓取的默认值。
+const (
+	defaultRobotsTTL      = 24 * time.Hour
+	defaultRobotsErrorTTL = 5 * time.Minute
+	defaultRobotsMaxSize  = 512 << 10 // RFC 9309 要求至少解析 500 KiB
+	defaultRobotsMaxHosts = 10000
+	defaultRobotsAgent    = "gotools-robots/1.0"
+)
+
+// HTTPDoer 发送 HTTP 请求的接口，*http.Client 即实现了该接口，可替换为带代理、限流或测试用的客户端。
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// RobotsOptions RobotsFilter 的选项，零值即默认配置。
+type RobotsOptions struct {
+	Client    HTTPDoer      // 抓取 robots.txt 的客户端，为 nil 时使用 10 秒超时的 http.Client
+	UserAgent string        // 抓取 robots.txt 时的 User-Agent，为空时默认 "gotools-robots/1.0"
+	TTL       time.Duration // 成功获取（含 4xx）的缓存时间，<= 0 时默认 24 小时
+	ErrorTTL  time.Duration // 无法获取（网络错误、5xx）时的缓存时间，<= 0 时默认 5 分钟
+	MaxSize   int64         // robots.txt 最大读取字节数，超出部分忽略，<= 0 时默认 512 KiB
+	MaxHosts  int           // 最多缓存的主机数，超出时先清理过期条目，<= 0 时默认 10000
+}
+
+// RobotsFilter 按 robots.txt 判断 URL 是否允许抓取。每个主机（scheme + host + 端口）的 robots.txt
+// 只抓取一次并缓存，同一主机的并发查询共享同一次抓取。并发安全。
+//
+// 获取失败时按 RFC 9309 处理：4xx（含 404）视为没有限制，全部允许；网络错误或 5xx 视为暂时不可访问，全部禁止，
+// 按 ErrorTTL 缓存后重试。
+//
+// 用法：
+//
+//	robots := urlutil.NewRobotsFilter(nil)
+//	for _, link := range links {
+//	    if !robots.Allowed("MyCrawler", link) {
+//	        continue
+//	    }
+//	    time.Sleep(robots.CrawlDelay("MyCrawler", link))
+//	    fetch(link)
+//	}
+type RobotsFilter struct {
+	client    HTTPDoer
+	userAgent string
+	ttl       time.Duration
+	errorTTL  time.Duration
+	maxSize   int64
+	maxHosts  int
+
+	mu      sync.Mutex
+	entries map[string]*robotsEntry // key 为 "scheme://host[:port]"
+}
+
+// robotsEntry 一个主机的 robots.txt 缓存，ready 关闭后 robots / err / expires 可读。
+type robotsEntry struct {
+	ready   chan struct{}
+	robots  *Robots
+	err     error
+	expires time.Time
+}
+
+// NewRobotsFilter 创建 RobotsFilter，opts 可为 nil。
+func NewRobotsFilter(opts *RobotsOptions) *RobotsFilter {
+	if opts == nil {
+		opts = &RobotsOptions{}
+	}
+	f := &RobotsFilter{
+		client:    opts.Client,
+		userAgent: opts.UserAgent,
+		ttl:       opts.TTL,
+		errorTTL:  opts.ErrorTTL,
+		maxSize:   opts.MaxSize,
+		maxHosts:  opts.MaxHosts,
+		entries:   make(map[string]*robotsEntry),
+	}
+	if f.client == nil {
+		f.client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if f.userAgent == "" {
+		f.userAgent = defaultRobotsAgent
+	}
+	if f.ttl <= 0 {
+		f.ttl = defaultRobotsTTL
+	}
+	if f.errorTTL <= 0 {
+		f.errorTTL = defaultRobotsErrorTTL
+	}
+	if f.maxSize <= 0 {
+		f.maxSize = defaultRobotsMaxSize
+	}
+	if f.maxHosts <= 0 {
+		f.maxHosts = defaultRobotsMaxHosts
+	}
+	return f
+}
+
+// Allowed 判断 userAgent 是否允许抓取 rawURL。URL 不合法或 robots.txt 暂时无法获取时返回 false。
+// userAgent 为爬虫的产品名（如 "MyCrawler"）或完整的 User-Agent 字符串。
+func (f *RobotsFilter) Allowed(userAgent, rawURL string) bool {
+	ok, _ := f.Check(context.Background(), userAgent, rawURL)
+	return ok
+}
+
+// Check 同 Allowed，可通过 ctx 控制等待 robots.txt 的时间，并返回不允许的原因（URL 不合法、robots.txt 无法获取）。
+// 被 robots.txt 规则禁止时返回 (false, nil)。
+func (f *RobotsFilter) Check(ctx context.Context, userAgent, rawURL string) (bool, error) {
+	u, err := parseRobotsTarget(rawURL)
+	if err != nil {
+		return false, err
+	}
+	if u.EscapedPath() == "/robots.txt" {
+		return true, nil
+	}
+	r, err := f.robotsFor(ctx, u)
+	if err != nil {
+		return false, err
+	}
+	return r.Allowed(userAgent, u.RequestURI()), nil
+}
+
+// CrawlDelay 返回 rawURL 所在主机对 userAgent 声明的 Crawl-delay，未声明或无法获取时返回 0。
+func (f *RobotsFilter) CrawlDelay(userAgent, rawURL string) time.Duration {
+	r, err := f.Robots(context.Background(), rawURL)
+	if err != nil {
+		return 0
+	}
+	return r.CrawlDelay(userAgent)
+}
+
+// Robots 返回 rawURL 所在主机已解析的 robots.txt（必要时抓取），可用于读取 Sitemaps 等信息。
+func (f *RobotsFilter) Robots(ctx context.Context, rawURL string) (*Robots, error) {
+	u, err := parseRobotsTarget(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return f.robotsFor(ctx, u)
+}
+
+// Forget 删除 rawURL 所在主机的缓存，下次查询时重新抓取。
+func (f *RobotsFilter) Forget(rawURL string) {
+	u, err := parseRobotsTarget(rawURL)
+	if err != nil {
+		return
+	}
+	f.mu.Lock()
+	delete(f.entries, robotsHostKey(u))
+	f.mu.Unlock()
+}
+
+// parseRobotsTarget 解析待检查的 URL，要求为带主机名的 http / https 绝对 URL。
+func parseRobotsTarget(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil, fmt.Errorf("urlutil: 解析 URL 失败: %w", err)
+	}
+	if scheme := strings.ToLower(u.Scheme); scheme != "http" && scheme != "https" {
+		return nil, fmt.Errorf("%w: %q", ErrUnsafeScheme, u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return nil, ErrMissingHost
+	}
+	return u, nil
+}
+
+// robotsHostKey 返回主机的缓存 key。
+func robotsHostKey(u *url.URL) string {
+	return strings.ToLower(u.Scheme) + "://" + strings.ToLower(u.Host)
+}
+
+// robotsFor 返回 u 所在主机的 robots.txt，缓存未命中或已过期时抓取，同一主机的并发调用只抓取一次。
+func (f *RobotsFilter) robotsFor(ctx context.Context, u *url.URL) (*Robots, error) {
+	key := robotsHostKey(u)
+
+	f.mu.Lock()
+	e, ok := f.entries[key]
+	if ok {
+		select {
+		case <-e.ready:
+			if time.Now().After(e.expires) {
+				ok = false
+			}
+		default: // 正在抓取
+		}
+	}
+	if !ok {
+		f.evictLocked()
+		e = &robotsEntry{ready: make(chan struct{})}
+		f.entries[key] = e
+		go f.fetch(key, e)
+	}
+	f.mu.Unlock()
+
+	select {
+	case <-e.ready:
+		return e.robots, e.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("urlutil: 等待 %s/robots.txt: %w", key, ctx.Err())
+	}
+}
+
+// evictLocked 缓存已满时清理过期条目，仍然满时随机淘汰一个已完成的条目。调用方持有锁。
+func (f *RobotsFilter) evictLocked() {
+	if len(f.entries) < f.maxHosts {
+		return
+	}
+	now := time.Now()
+	var victim string
+	for k, e := range f.entries {
+		select {
+		case <-e.ready:
+			if now.After(e.expires) {
+				delete(f.entries, k)
+			} else if victim == "" {
+				victim = k
+			}
+		default:
+		}
+	}
+	if len(f.entries) >= f.maxHosts && victim != "" {
+		delete(f.entries, victim)
+	}
+}
+
+// fetch 抓取并解析 robots.txt，结果写入 e 后关闭 e.ready。
+// 使用独立的 context（超时由 client 控制），避免某个调用方取消导致其他等待者一起失败。
+func (f *RobotsFilter) fetch(key string, e *robotsEntry) {
+	defer close(e.ready)
+	e.robots, e.err = f.download(key + "/robots.txt")
+	if e.err != nil {
+		e.expires = time.Now().Add(f.errorTTL)
+		return
+	}
+	e.expires = time.Now().Add(f.ttl)
+}
+
+// download 下载并解析 robots.txt：2xx 解析内容，4xx 全部允许，其余情况返回错误。
+func (f *RobotsFilter) download(robotsURL string) (*Robots, error) {
+	req, err := http.NewRequest(http.MethodGet, robotsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("urlutil: 创建 robots.txt 请求失败: %w", err)
+	}
+	req.Header.Set("User-Agent", f.userAgent)
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("urlutil: 获取 %s 失败: %w", robotsURL, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		data, err := io.ReadAll(io.LimitReader(resp.Body, f.maxSize))
+		if err != nil {
+			return nil, fmt.Errorf("urlutil: 读取 %s 失败: %w", robotsURL, err)
+		}
+		return ParseRobots(data), nil
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		return &Robots{}, nil
+	default:
+		return nil, fmt.Errorf("urlutil: 获取 %s 失败: HTTP %d", robotsURL, resp.StatusCode)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// robots.txt 解析与匹配
+// ---------------------------------------------------------------------------
+
+// Robots 解析后的 robots.txt。零值表示没有任何限制。
+type Robots struct {
+	Sitemaps []string // Sitemap 声明
+	groups   []robotsGroup
+}
+
+// robotsGroup 一组 User-agent 及其规则。
+type robotsGroup struct {
+	agents     []string // 小写的产品名，"*" 表示所有爬虫
+	rules      []robotsRule
+	crawlDelay time.Duration
+}
+
+// robotsRule 一条 Allow / Disallow 规则。
+type robotsRule struct {
+	allow   bool
+	pattern string
+}
+
+// ParseRobots 按 RFC 9309 解析 robots.txt 内容（忽略无法识别的行），支持 Allow / Disallow 中的 * 与 $ 通配符，
+// 以及非标准的 Crawl-delay 和 Sitemap。
+//
+// 用法：
+//
+//	r := urlutil.ParseRobots(data)
+//	ok := r.Allowed("MyCrawler", "/search?q=go")
+func ParseRobots(data []byte) *Robots {
+	r := &Robots{}
+	var cur *robotsGroup
+	inAgents := false // 上一条有效行是否为 User-agent（连续的 User-agent 属于同一组）
+
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // UTF-8 BOM
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64<<10), len(data)+1)
+	for sc.Scan() {
+		line := sc.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if !inAgents {
+				r.groups = append(r.groups, robotsGroup{})
+				cur = &r.groups[len(r.groups)-1]
+			}
+			cur.agents = append(cur.agents, strings.ToLower(value))
+			inAgents = true
+		case "allow", "disallow":
+			inAgents = false
+			if cur == nil || value == "" {
+				continue // 组外的规则无效；空的 Disallow 表示不限制
+			}
+			cur.rules = append(cur.rules, robotsRule{allow: key == "allow", pattern: value})
+		case "crawl-delay":
+			inAgents = false
+			if cur == nil {
+				continue
+			}
+			if secs, err := strconv.ParseFloat(value, 64); err == nil && secs > 0 {
+				cur.crawlDelay = time.Duration(secs * float64(time.Second))
+			}
+		case "sitemap":
+			if value != "" {
+				r.Sitemaps = append(r.Sitemaps, value)
+			}
+		}
+	}
+	return r
+}
+
+// Allowed 判断 userAgent 是否允许访问 path（路径及查询串，如 "/a/b?x=1"）。
+// 按最长匹配规则判定，Allow 与 Disallow 长度相同时 Allow 优先；没有匹配规则时允许。
+func (r *Robots) Allowed(userAgent, path string) bool {
+	if path == "" {
+		path = "/"
+	}
+	allowed, best := true, -1
+	for _, g := range r.groupsFor(userAgent) {
+		for _, rule := range g.rules {
+			n := len(rule.pattern)
+			if n < best || (n == best && !rule.allow) || !robotsMatch(rule.pattern, path) {
+				continue
+			}
+			allowed, best = rule.allow, n
+		}
+	}
+	return allowed
+}
+
+// CrawlDelay 返回对 userAgent 生效的 Crawl-delay，未声明时返回 0。
+func (r *Robots) CrawlDelay(userAgent string) time.Duration {
+	var d time.Duration
+	for _, g := range r.groupsFor(userAgent) {
+		d = max(d, g.crawlDelay)
+	}
+	return d
+}
+
+// groupsFor 返回对 userAgent 生效的组：产品名匹配最长的组（同名的多个组合并生效），都不匹配时使用 "*" 组。
+// 组的产品名与 userAgent 的产品名（第一个 "/" 或空白之前的部分）相同，或出现在完整的 User-Agent 字符串中即视为匹配。
+func (r *Robots) groupsFor(userAgent string) []*robotsGroup {
+	ua := strings.ToLower(strings.TrimSpace(userAgent))
+	product := ua
+	if i := strings.IndexAny(product, "/ \t"); i >= 0 {
+		product = product[:i]
+	}
+
+	var matched, wildcard []*robotsGroup
+	bestLen := 0
+	for i := range r.groups {
+		g := &r.groups[i]
+		for _, agent := range g.agents {
+			if agent == "*" {
+				wildcard = append(wildcard, g)
+				continue
+			}
+			if agent == "" || (agent != product && !strings.Contains(ua, agent)) {
+				continue
+			}
+			if len(agent) > bestLen {
+				matched, bestLen = matched[:0], len(agent)
+			}
+			if len(agent) == bestLen {
+				matched = append(matched, g)
+			}
+			break
+		}
+	}
+	if len(matched) > 0 {
+		return matched
+	}
+	return wildcard
+}
+
+// robotsMatch 判断 pattern 是否匹配 path 的开头：* 匹配任意字符序列，结尾的 $ 表示必须匹配到 path 末尾。
+func robotsMatch(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	if anchored {
+		pattern = pattern[:len(pattern)-1]
+	}
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	pos := len(parts[0])
+	if len(parts) == 1 {
+		return !anchored || pos == len(path)
+	}
+	for i, part := range parts[1:] {
+		if anchored && i == len(parts)-2 {
+			// 最后一段必须位于末尾，且不能与已匹配的部分重叠
+			return len(path)-len(part) >= pos && strings.HasSuffix(path, part)
+		}
+		idx := strings.Index(path[pos:], part)
+		if idx < 0 {
+			return false
+		}
+		pos += idx + len(part)
+	}
+	return true
+}
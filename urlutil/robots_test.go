@@ -0,0 +1,178 @@
+package urlutil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRobotsMatch(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"/", "/anything", true},
+		{"/fish", "/fish", true},
+		{"/fish", "/fish.html", true},
+		{"/fish", "/fish/salmon.html", true},
+		{"/fish", "/Fish.asp", false},
+		{"/fish", "/catfish", false},
+		{"/fish/", "/fish", false},
+		{"/*.php", "/index.php", true},
+		{"/*.php", "/folder/any.php.file.html", true},
+		{"/*.php", "/windows.PHP", false},
+		{"/*.php$", "/filename.php", true},
+		{"/*.php$", "/filename.php?parameters", false},
+		{"/*.php$", "/filename.php/", false},
+		{"/fish*.php", "/fishheads/catfish.php?parameters", true},
+		{"/fish*.php", "/Fish.PHP", false},
+		{"/a*b*c", "/aXbYc", true},
+		{"/a*b*c", "/aXcYb", false},
+		{"/*", "/", true},
+		{"/$", "/", true},
+		{"/$", "/a", false},
+		{"/ab*b$", "/ab", false}, // 末尾段不能与已匹配的部分重叠
+		{"/ab*b$", "/abb", true},
+	}
+	for _, tt := range tests {
+		if got := robotsMatch(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("robotsMatch(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+const testRobots = "\xef\xbb\xbf" + `# 注释
+User-agent: *
+Disallow: /private/
+Allow: /private/public
+Disallow: /*.pdf$
+Crawl-delay: 2
+
+User-agent: GoodBot
+User-agent: OtherBot   # 连续的 User-agent 属于同一组
+Allow: /
+Disallow: /admin
+Crawl-delay: 0.5
+
+User-agent: BadBot
+Disallow: /
+
+Disallow: /ignored-without-group
+Sitemap: https://example.com/sitemap.xml
+sitemap: https://example.com/news.xml
+invalid line
+`
+
+func TestParseRobots(t *testing.T) {
+	r := ParseRobots([]byte(testRobots))
+
+	tests := []struct {
+		agent, path string
+		want        bool
+	}{
+		{"AnyBot", "/", true},
+		{"AnyBot", "", true},
+		{"AnyBot", "/private/x", false},
+		{"AnyBot", "/private/public/page", true}, // 更长的 Allow 优先
+		{"AnyBot", "/docs/a.pdf", false},
+		{"AnyBot", "/docs/a.pdf?x=1", true},
+		{"GoodBot", "/private/x", true}, // 匹配具名组后不再使用 * 组
+		{"goodbot/2.1", "/admin/users", false},
+		{"OtherBot", "/admin", false},
+		{"Mozilla/5.0 (compatible; GoodBot/2.1)", "/admin", false},
+		{"BadBot", "/", false},
+		{"BadBot", "/anything", false},
+	}
+	for _, tt := range tests {
+		if got := r.Allowed(tt.agent, tt.path); got != tt.want {
+			t.Errorf("Allowed(%q, %q) = %v, want %v", tt.agent, tt.path, got, tt.want)
+		}
+	}
+
+	if d := r.CrawlDelay("AnyBot"); d != 2*time.Second {
+		t.Errorf("CrawlDelay(AnyBot) = %v, want 2s", d)
+	}
+	if d := r.CrawlDelay("GoodBot"); d != 500*time.Millisecond {
+		t.Errorf("CrawlDelay(GoodBot) = %v, want 500ms", d)
+	}
+	if d := r.CrawlDelay("BadBot"); d != 0 {
+		t.Errorf("CrawlDelay(BadBot) = %v, want 0", d)
+	}
+	if want := []string{"https://example.com/sitemap.xml", "https://example.com/news.xml"}; !slices.Equal(r.Sitemaps, want) {
+		t.Errorf("Sitemaps = %v, want %v", r.Sitemaps, want)
+	}
+}
+
+func TestParseRobotsEdgeCases(t *testing.T) {
+	tests := []struct {
+		name, data, path string
+		want             bool
+	}{
+		{"empty", "", "/a", true},
+		{"empty disallow", "User-agent: *\nDisallow:", "/a", true},
+		{"rule outside group", "Disallow: /", "/a", true},
+		{"equal length allow wins", "User-agent: *\nDisallow: /a\nAllow: /a", "/a", true},
+		{"CRLF", "User-agent: *\r\nDisallow: /a\r\n", "/a/b", false},
+		{"case-insensitive keys", "USER-AGENT: *\nDISALLOW: /a", "/a", false},
+	}
+	for _, tt := range tests {
+		if got := ParseRobots([]byte(tt.data)).Allowed("Bot", tt.path); got != tt.want {
+			t.Errorf("%s: Allowed(%q) = %v, want %v", tt.name, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRobotsFilter(t *testing.T) {
+	var fetches atomic.Int32
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		fetches.Add(1)
+		w.WriteHeader(status)
+		w.Write([]byte(testRobots))
+	}))
+	defer srv.Close()
+
+	f := NewRobotsFilter(&RobotsOptions{Client: srv.Client()})
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/", true},
+		{"/private/x", false},
+		{"/private/public", true},
+		{"/robots.txt", true},
+	}
+	for _, tt := range tests {
+		if got := f.Allowed("AnyBot", srv.URL+tt.path); got != tt.want {
+			t.Errorf("Allowed(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+	if d := f.CrawlDelay("AnyBot", srv.URL+"/"); d != 2*time.Second {
+		t.Errorf("CrawlDelay = %v, want 2s", d)
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("robots.txt fetched %d times, want 1", n)
+	}
+	if f.Allowed("AnyBot", "not a url") {
+		t.Error("invalid URL should not be allowed")
+	}
+
+	// 5xx 时全部禁止，4xx 时全部允许
+	status = http.StatusServiceUnavailable
+	f.Forget(srv.URL)
+	if f.Allowed("AnyBot", srv.URL+"/") {
+		t.Error("5xx robots.txt should disallow everything")
+	}
+	status = http.StatusNotFound
+	f.Forget(srv.URL)
+	if !f.Allowed("AnyBot", srv.URL+"/private/x") {
+		t.Error("4xx robots.txt should allow everything")
+	}
+}
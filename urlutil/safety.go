@@ -0,0 +1,186 @@
+package urlutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// URL 安全检查的哨兵错误。
+var (
+	ErrURLTooLong     = errors.New("urlutil: URL 过长")
+	ErrUnsafeScheme   = errors.New("urlutil: 不允许的 scheme")
+	ErrMissingHost    = errors.New("urlutil: URL 缺少主机名")
+	ErrURLUserinfo    = errors.New("urlutil: URL 不允许包含用户名密码")
+	ErrPrivateHost    = errors.New("urlutil: 主机指向内网 / 本机地址")
+	ErrObfuscatedHost = errors.New("urlutil: 主机为非标准格式的 IP 地址")
+)
+
+// defaultMaxURLLength 默认最大 URL 长度。
+const defaultMaxURLLength = 2048
+
+// IsValidHTTPURL 判断 s 是否为带主机名的合法 http / https 绝对 URL。
+func IsValidHTTPURL(s string) bool {
+	u, err := url.Parse(s)
+	if err != nil {
+		return false
+	}
+	scheme := strings.ToLower(u.Scheme)
+	return (scheme == "http" || scheme == "https") && u.Hostname() != ""
+}
+
+// SafetyOptions 外部 URL 安全检查选项，零值即默认的严格策略。
+type SafetyOptions struct {
+	MaxLength     int           // 最大长度，<= 0 时默认 2048
+	AllowPrivate  bool          // 允许内网 / 本机地址（仅用于测试环境）
+	AllowUserinfo bool          // 允许 user:pass@host 形式
+	ResolveDNS    bool          // 解析域名并检查所有 IP 是否为内网地址（需要网络，有额外延迟）
+	DNSTimeout    time.Duration // DNS 解析超时，<= 0 时默认 3s
+}
+
+// 额外禁止的网段（net.IP 的 IsPrivate / IsLoopback 等未覆盖的特殊用途地址）
+var blockedNets = func() []*net.IPNet {
+	cidrs := []string{
+		"0.0.0.0/8",     // 本网络
+		"100.64.0.0/10", // 运营商级 NAT
+		"192.0.0.0/24",  // IETF 协议分配
+		"198.18.0.0/15", // 基准测试
+		"240.0.0.0/4",   // 保留
+		"64:ff9b::/96",  // NAT64（可映射到内网 IPv4）
+	}
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, c := range cidrs {
+		_, n, _ := net.ParseCIDR(c)
+		nets = append(nets, n)
+	}
+	return nets
+}()
+
+// isPrivateIP 判断 IP 是否为内网、本机、链路本地、组播或其他特殊用途地址。
+func isPrivateIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return true
+	}
+	for _, n := range blockedNets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckExternalURL 检查用户提供的 URL 是否可以安全地由服务端访问（SSRF 防护），不安全时返回原因。
+// 拒绝：非 http / https scheme（javascript:、data:、file: 等）、超长 URL、带用户名密码的 URL、
+// localhost / 内网 / 本机 IP、十进制 / 十六进制等非标准 IP 写法；opts.ResolveDNS 为 true 时
+// 还会解析域名并拒绝解析到内网地址的域名。
+//
+// 注意：检查与实际请求之间 DNS 结果可能变化（DNS rebinding），高安全场景应在 http.Transport 的
+// DialContext 中再次校验连接的 IP。
+//
+// 用法：
+//
+//	if err := urlutil.CheckExternalURL(userURL, &urlutil.SafetyOptions{ResolveDNS: true}); err != nil {
+//	    return fmt.Errorf("回调地址不合法: %w", err)
+//	}
+func CheckExternalURL(s string, opts *SafetyOptions) error {
+	if opts == nil {
+		opts = &SafetyOptions{}
+	}
+	maxLen := opts.MaxLength
+	if maxLen <= 0 {
+		maxLen = defaultMaxURLLength
+	}
+	if len(s) > maxLen {
+		return fmt.Errorf("%w: %d > %d", ErrURLTooLong, len(s), maxLen)
+	}
+
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return fmt.Errorf("urlutil: 解析 URL 失败: %w", err)
+	}
+	if scheme := strings.ToLower(u.Scheme); scheme != "http" && scheme != "https" {
+		return fmt.Errorf("%w: %q", ErrUnsafeScheme, u.Scheme)
+	}
+	if u.User != nil && !opts.AllowUserinfo {
+		return ErrURLUserinfo
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "" {
+		return ErrMissingHost
+	}
+	if opts.AllowPrivate {
+		return nil
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		if isPrivateIP(ip) {
+			return fmt.Errorf("%w: %s", ErrPrivateHost, host)
+		}
+		return nil
+	}
+	if isObfuscatedIP(host) {
+		return fmt.Errorf("%w: %s", ErrObfuscatedHost, host)
+	}
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || !strings.Contains(host, ".") {
+		return fmt.Errorf("%w: %s", ErrPrivateHost, host)
+	}
+
+	if opts.ResolveDNS {
+		timeout := opts.DNSTimeout
+		if timeout <= 0 {
+			timeout = 3 * time.Second
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return fmt.Errorf("urlutil: 解析域名 %s 失败: %w", host, err)
+		}
+		for _, a := range addrs {
+			if isPrivateIP(a.IP) {
+				return fmt.Errorf("%w: %s 解析到 %s", ErrPrivateHost, host, a.IP)
+			}
+		}
+	}
+	return nil
+}
+
+// IsSafeExternalURL 同 CheckExternalURL，只返回是否安全。
+func IsSafeExternalURL(s string, opts *SafetyOptions) bool {
+	return CheckExternalURL(s, opts) == nil
+}
+
+// isObfuscatedIP 判断主机名是否为浏览器 / 部分 HTTP 库会当作 IP 解析的非标准写法，
+// 如 "2130706433"（十进制）、"0x7f.1"（十六进制）、"127.1"（省略段）、"0177.0.0.1"（八进制）。
+func isObfuscatedIP(host string) bool {
+	parts := strings.Split(host, ".")
+	if len(parts) > 4 {
+		return false
+	}
+	for _, p := range parts {
+		if p == "" {
+			return false
+		}
+		if strings.HasPrefix(p, "0x") {
+			p = p[2:]
+			for _, c := range p {
+				if !strings.ContainsRune("0123456789abcdef", c) {
+					return false
+				}
+			}
+			continue
+		}
+		for _, c := range p {
+			if c < '0' || c > '9' {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -37,6 +37,7 @@ func normalizeHTTPS(rawURL string) string {
 }
 
 // ToMD5 先将 URL 标准化为 https，再返回其 MD5 十六进制摘要。
+// 仅做简单的 http → https 替换（保持已有哈希值不变），完整规范化请先调用 Normalize。
 func ToMD5(rawURL string) (string, error) {
 	return hashutil.MD5(normalizeHTTPS(rawURL))
 }
@@ -45,3 +46,62 @@ func ToMD5(rawURL string) (string, error) {
 func ToSHA256(rawURL string) (string, error) {
 	return hashutil.SHA256(normalizeHTTPS(rawURL))
 }
+
+// skipSchemes ResolveAll 跳过的非页面链接 scheme。
+var skipSchemes = []string{"mailto:", "tel:", "javascript:", "data:", "sms:", "ftp:", "file:"}
+
+// ResolveAll 批量将页面中的链接解析为绝对 URL（base 只解析一次），适合爬虫的链接提取。
+// 跳过空链接、仅含 #fragment 的页内锚点和 mailto: / tel: / javascript: 等非页面链接；
+// 结果去除 #fragment 后去重，保持首次出现的顺序。解析失败的链接不影响其他链接，错误汇总在 errs 中。
+//
+// 用法：
+//
+//	links, errs := urlutil.ResolveAll(pageURL, hrefs)
+//	for _, err := range errs {
+//	    log.Debugf("跳过链接: %v", err)
+//	}
+func ResolveAll(baseURL string, hrefs []string) (links []string, errs []error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, []error{fmt.Errorf("urlutil: 解析 base URL 失败: %w", err)}
+	}
+
+	seen := make(map[string]struct{}, len(hrefs))
+	links = make([]string, 0, len(hrefs))
+	for _, href := range hrefs {
+		href = strings.TrimSpace(href)
+		if href == "" || strings.HasPrefix(href, "#") || hasSkipScheme(href) {
+			continue
+		}
+
+		abs, err := base.Parse(href)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("urlutil: 解析相对 URL [%s] 失败: %w", href, err))
+			continue
+		}
+		if abs.Scheme != "http" && abs.Scheme != "https" {
+			errs = append(errs, fmt.Errorf("urlutil: 无法解析为绝对 URL: %s", href))
+			continue
+		}
+		abs.Fragment, abs.RawFragment = "", ""
+
+		s := abs.String()
+		if _, ok := seen[s]; ok {
+			continue
+		}
+		seen[s] = struct{}{}
+		links = append(links, s)
+	}
+	return links, errs
+}
+
+// hasSkipScheme 判断链接是否为需要跳过的 scheme（大小写不敏感）。
+func hasSkipScheme(href string) bool {
+	lower := strings.ToLower(href)
+	for _, s := range skipSchemes {
+		if strings.HasPrefix(lower, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,239 @@
+package versionutil
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidConstraint 表示版本约束格式无效。
+var ErrInvalidConstraint = errors.New("versionutil: 无效的版本约束")
+
+// Constraint 版本约束，由 "||" 分隔的若干组条件构成，满足任意一组即可；
+// 组内条件以逗号或空白分隔，需全部满足。支持的写法：
+//
+//	=1.2.3  !=1.2.3  >1.2.3  >=1.2.3  <1.2.3  <=1.2.3
+//	1.2 / 1.2.x / 1.2.*   等价于 >=1.2.0 <1.3.0（部分版本号按范围匹配）
+//	~1.2.3                等价于 >=1.2.3 <1.3.0
+//	^1.2.3                等价于 >=1.2.3 <2.0.0（^0.2.3 为 <0.3.0）
+//	* / x                 任意版本
+type Constraint struct {
+	raw    string
+	groups [][]term
+}
+
+// term 单个比较条件。
+type term struct {
+	op string
+	v  Version
+}
+
+// ParseConstraint 解析版本约束。
+//
+// 用法：
+//
+//	c, err := versionutil.ParseConstraint(">=7.0, <8")
+//	if c.Check(versionutil.MustParse("7.2.4")) { ... }
+func ParseConstraint(s string) (*Constraint, error) {
+	c := &Constraint{raw: s}
+	for _, group := range strings.Split(s, "||") {
+		fields := strings.FieldsFunc(group, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+		fields = joinOperators(fields)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidConstraint, s)
+		}
+		var terms []term
+		for _, f := range fields {
+			ts, err := parseTerm(f)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q: %v", ErrInvalidConstraint, s, err)
+			}
+			terms = append(terms, ts...)
+		}
+		c.groups = append(c.groups, terms)
+	}
+	return c, nil
+}
+
+// MustParseConstraint 同 ParseConstraint，解析失败时 panic。
+func MustParseConstraint(s string) *Constraint {
+	c, err := ParseConstraint(s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Check 判断 v 是否满足约束。
+func (c *Constraint) Check(v Version) bool {
+	for _, group := range c.groups {
+		ok := true
+		for _, t := range group {
+			if !t.match(v) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// String 返回原始约束字符串。
+func (c *Constraint) String() string { return c.raw }
+
+// Satisfies 解析版本号和约束并判断是否满足。
+//
+// 用法：
+//
+//	ok, err := versionutil.Satisfies(redisVersion, ">=7.0")
+func Satisfies(version, constraint string) (bool, error) {
+	v, err := Parse(version)
+	if err != nil {
+		return false, err
+	}
+	c, err := ParseConstraint(constraint)
+	if err != nil {
+		return false, err
+	}
+	return c.Check(v), nil
+}
+
+// ---------------------------------------------------------------------------
+// 内部实现
+// ---------------------------------------------------------------------------
+
+// operators 按长度降序排列，保证最长匹配。
+var operators = []string{">=", "<=", "!=", "==", ">", "<", "=", "~", "^"}
+
+// joinOperators 将被空白拆开的运算符与其后的版本号合并（如 ">= 7.0"）。
+func joinOperators(fields []string) []string {
+	out := make([]string, 0, len(fields))
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		if isOperator(f) && i+1 < len(fields) {
+			f += fields[i+1]
+			i++
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+func isOperator(s string) bool {
+	for _, op := range operators {
+		if s == op {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTerm 解析单个条件，部分版本号、~、^ 会展开为一对上下界条件。
+func parseTerm(s string) ([]term, error) {
+	op := ""
+	for _, candidate := range operators {
+		if strings.HasPrefix(s, candidate) {
+			op = candidate
+			break
+		}
+	}
+	rest := strings.TrimSpace(s[len(op):])
+	if op == "==" {
+		op = "="
+	}
+	if rest == "*" || rest == "x" || rest == "X" {
+		if op != "" && op != "=" {
+			return nil, fmt.Errorf("通配符不能与 %s 组合", op)
+		}
+		return nil, nil
+	}
+
+	v, n, err := parseVersion(rest, true)
+	if err != nil {
+		return nil, err
+	}
+	if n < 3 && (v.Prerelease != "" || v.Build != "") {
+		return nil, fmt.Errorf("部分版本号 %q 不能带预发布或构建标识", rest)
+	}
+
+	switch op {
+	case "", "=":
+		if n == 3 {
+			return []term{{"=", v}}, nil
+		}
+		return []term{{">=", v}, {"<", bump(v, n)}}, nil
+	case "!=":
+		if n == 3 {
+			return []term{{"!=", v}}, nil
+		}
+		return nil, fmt.Errorf("!= 需要完整版本号")
+	case ">":
+		if n < 3 {
+			// >7 即 >=8.0.0
+			return []term{{">=", bump(v, n)}}, nil
+		}
+		return []term{{">", v}}, nil
+	case "<=":
+		if n < 3 {
+			// <=7.2 即 <7.3.0
+			return []term{{"<", bump(v, n)}}, nil
+		}
+		return []term{{"<=", v}}, nil
+	case ">=", "<":
+		return []term{{op, v}}, nil
+	case "~":
+		upper := n
+		if upper > 2 {
+			upper = 2
+		}
+		return []term{{">=", v}, {"<", bump(v, upper)}}, nil
+	case "^":
+		// 从左起第一个非零段（或最后一个写出的段）加一
+		upper := 1
+		switch {
+		case v.Major == 0 && n >= 2 && (v.Minor != 0 || n == 2):
+			upper = 2
+		case v.Major == 0 && v.Minor == 0 && n == 3:
+			upper = 3
+		}
+		return []term{{">=", v}, {"<", bump(v, upper)}}, nil
+	}
+	return nil, fmt.Errorf("未知运算符 %q", op)
+}
+
+// bump 将第 n 段（1 起）加一，其后各段归零，并清除预发布标识。n 为 0 时返回一个不可达的上界。
+func bump(v Version, n int) Version {
+	switch n {
+	case 0:
+		return Version{Major: int(^uint(0) >> 1)}
+	case 1:
+		return Version{Major: v.Major + 1}
+	case 2:
+		return Version{Major: v.Major, Minor: v.Minor + 1}
+	default:
+		return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+	}
+}
+
+// match 判断 v 是否满足单个条件。
+func (t term) match(v Version) bool {
+	c := v.Compare(t.v)
+	switch t.op {
+	case "=":
+		return c == 0
+	case "!=":
+		return c != 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	}
+	return false
+}
@@ -0,0 +1,199 @@
+package versionutil
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidVersion 表示版本号格式无效。
+var ErrInvalidVersion = errors.New("versionutil: 无效的版本号")
+
+// Version 语义化版本号（SemVer 2.0），解析时较为宽松：
+// 允许 "v" 前缀，允许省略次版本号和修订号（"7"、"7.0" 视为 "7.0.0"）。
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string // 预发布标识，如 "rc.1"
+	Build      string // 构建元数据，如 "20240102"，不参与比较
+}
+
+// Parse 解析版本号，如 "1.2.3"、"v1.2.3-rc.1+build.5"、"7.0"。
+//
+// 用法：
+//
+//	v, err := versionutil.Parse("7.2.4")
+//	if err == nil && v.Major >= 7 { ... }
+func Parse(s string) (Version, error) {
+	v, _, err := parseVersion(s, false)
+	return v, err
+}
+
+// MustParse 同 Parse，解析失败时 panic，适合常量版本号。
+func MustParse(s string) Version {
+	v, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// String 返回规范格式 "MAJOR.MINOR.PATCH[-PRERELEASE][+BUILD]"。
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// Compare 按 SemVer 优先级比较 v 和 o：v < o 返回 -1，相等返回 0，v > o 返回 1。
+// 预发布版本低于对应的正式版本（1.0.0-rc.1 < 1.0.0），构建元数据不参与比较。
+func (v Version) Compare(o Version) int {
+	if c := cmp.Compare(v.Major, o.Major); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(v.Minor, o.Minor); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(v.Patch, o.Patch); c != 0 {
+		return c
+	}
+	return comparePrerelease(v.Prerelease, o.Prerelease)
+}
+
+// Less 判断 v 是否低于 o。
+func (v Version) Less(o Version) bool { return v.Compare(o) < 0 }
+
+// Equal 判断 v 和 o 优先级是否相同（忽略构建元数据）。
+func (v Version) Equal(o Version) bool { return v.Compare(o) == 0 }
+
+// Compare 解析并比较两个版本号字符串，返回值同 Version.Compare。
+//
+// 用法：
+//
+//	c, err := versionutil.Compare("1.10.0", "1.9.3") // c == 1
+func Compare(a, b string) (int, error) {
+	va, err := Parse(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := Parse(b)
+	if err != nil {
+		return 0, err
+	}
+	return va.Compare(vb), nil
+}
+
+// ---------------------------------------------------------------------------
+// 内部实现
+// ---------------------------------------------------------------------------
+
+// parseVersion 解析版本号，同时返回实际写出的数字段数（1~3），供约束中的部分版本号使用。
+// allowWildcard 为 true 时数字段可以是 "x"、"X" 或 "*"，计为未写出。
+func parseVersion(s string, allowWildcard bool) (Version, int, error) {
+	var v Version
+	orig := s
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "v"), "V")
+	if s == "" {
+		return v, 0, fmt.Errorf("%w: %q", ErrInvalidVersion, orig)
+	}
+
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		v.Build = s[i+1:]
+		s = s[:i]
+		if !validIdentifiers(v.Build) {
+			return v, 0, fmt.Errorf("%w: %q", ErrInvalidVersion, orig)
+		}
+	}
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		v.Prerelease = s[i+1:]
+		s = s[:i]
+		if !validIdentifiers(v.Prerelease) {
+			return v, 0, fmt.Errorf("%w: %q", ErrInvalidVersion, orig)
+		}
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return v, 0, fmt.Errorf("%w: %q", ErrInvalidVersion, orig)
+	}
+	nums := [3]*int{&v.Major, &v.Minor, &v.Patch}
+	n := 0
+	for i, p := range parts {
+		if allowWildcard && (p == "x" || p == "X" || p == "*") {
+			// 通配符之后的段必须也是通配符
+			for _, rest := range parts[i+1:] {
+				if rest != "x" && rest != "X" && rest != "*" {
+					return v, 0, fmt.Errorf("%w: %q", ErrInvalidVersion, orig)
+				}
+			}
+			break
+		}
+		num, err := strconv.Atoi(p)
+		if err != nil || num < 0 || p == "" || p[0] == '+' {
+			return v, 0, fmt.Errorf("%w: %q", ErrInvalidVersion, orig)
+		}
+		*nums[i] = num
+		n++
+	}
+	return v, n, nil
+}
+
+// validIdentifiers 检查点分隔的预发布 / 构建标识：非空，仅含字母、数字和连字符。
+func validIdentifiers(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, id := range strings.Split(s, ".") {
+		if id == "" {
+			return false
+		}
+		for _, r := range id {
+			if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// comparePrerelease 按 SemVer 规则比较预发布标识：无预发布的版本更高；
+// 逐段比较，纯数字段按数值比较且低于字母段，段数多者更高。
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if c := cmp.Compare(an, bn); c != 0 {
+				return c
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return cmp.Compare(len(as), len(bs))
+}
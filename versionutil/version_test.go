@@ -0,0 +1,119 @@
+package versionutil
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"1.2.3", "1.2.3"},
+		{"v1.2.3", "1.2.3"},
+		{"7", "7.0.0"},
+		{"7.0", "7.0.0"},
+		{" 7.2.4 ", "7.2.4"},
+		{"1.0.0-rc.1", "1.0.0-rc.1"},
+		{"1.0.0-rc.1+build.5", "1.0.0-rc.1+build.5"},
+		{"1.0.0+20240102", "1.0.0+20240102"},
+	}
+	for _, tt := range tests {
+		v, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got := v.String(); got != tt.want {
+			t.Errorf("Parse(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "v", "1.2.3.4", "1.a", "1..2", "-1.0", "1.0.0-", "1.0.0-rc..1", "1.0.0+b!"} {
+		if _, err := Parse(in); !errors.Is(err, ErrInvalidVersion) {
+			t.Errorf("Parse(%q) = %v, want ErrInvalidVersion", in, err)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"1.10.0", "1.9.3", 1},
+		{"2.0.0", "10.0.0", -1},
+		{"7", "7.0.0", 0},
+		{"1.0.0-rc.1", "1.0.0", -1},
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta", -1},
+		{"1.0.0-beta.2", "1.0.0-beta.11", -1},
+		{"1.0.0-rc.1", "1.0.0-beta.11", 1},
+		{"1.0.0+a", "1.0.0+b", 0},
+	}
+	for _, tt := range tests {
+		got, err := Compare(tt.a, tt.b)
+		if err != nil {
+			t.Errorf("Compare(%q, %q) error: %v", tt.a, tt.b, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+	if _, err := Compare("1.0", "bad"); err == nil {
+		t.Error("Compare with invalid version should fail")
+	}
+}
+
+func TestConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		want       bool
+	}{
+		{">=7.0", "7.0.0", true},
+		{">=7.0", "6.2.14", false},
+		{">= 7.0, < 8", "7.2.4", true},
+		{">= 7.0, < 8", "8.0.0", false},
+		{">=6.2 <7 || >=7.2", "7.0.5", false},
+		{">=6.2 <7 || >=7.2", "7.2.0", true},
+		{"7.2", "7.2.9", true},
+		{"7.2.x", "7.3.0", false},
+		{"=1.2.3", "1.2.3", true},
+		{"==1.2.3", "1.2.4", false},
+		{"!=1.2.3", "1.2.4", true},
+		{">7", "7.9.9", false},
+		{">7", "8.0.0", true},
+		{"<=7.2", "7.2.9", true},
+		{"<=7.2", "7.3.0", false},
+		{"~1.2.3", "1.2.9", true},
+		{"~1.2.3", "1.3.0", false},
+		{"~1", "1.9.0", true},
+		{"^1.2.3", "1.9.0", true},
+		{"^1.2.3", "2.0.0", false},
+		{"^0.2.3", "0.2.9", true},
+		{"^0.2.3", "0.3.0", false},
+		{"^0.0.3", "0.0.4", false},
+		{"*", "0.0.1", true},
+		{"x", "99.0.0", true},
+	}
+	for _, tt := range tests {
+		got, err := Satisfies(tt.version, tt.constraint)
+		if err != nil {
+			t.Errorf("Satisfies(%q, %q) error: %v", tt.version, tt.constraint, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Satisfies(%q, %q) = %v, want %v", tt.version, tt.constraint, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", ">=", ">=1 ||", ">=abc", "!=1.2", ">*", "1.x.2", ">=1.0-rc"} {
+		if _, err := ParseConstraint(in); !errors.Is(err, ErrInvalidConstraint) {
+			t.Errorf("ParseConstraint(%q) = %v, want ErrInvalidConstraint", in, err)
+		}
+	}
+}
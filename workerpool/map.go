@@ -0,0 +1,27 @@
+package workerpool
+
+import "context"
+
+// Map 以最多 n 个并发对 items 中每个元素执行 fn，结果按 items 的顺序返回。
+// 错误聚合方式由 opts 中的 WithMode 决定；出错元素对应位置为 R 的零值。
+//
+// 用法：
+//
+//	sums, err := workerpool.Map(ctx, 4, paths, func(ctx context.Context, path string) (string, error) {
+//	    return hashutil.SHA256File(path)
+//	}, workerpool.WithMode(workerpool.FirstError))
+func Map[T, R any](ctx context.Context, n int, items []T, fn func(ctx context.Context, item T) (R, error), opts ...Option) ([]R, error) {
+	results := make([]R, len(items))
+	pool := New(n, append([]Option{WithContext(ctx)}, opts...)...)
+	for i, item := range items {
+		pool.Submit(func(ctx context.Context) error {
+			r, err := fn(ctx, item)
+			if err != nil {
+				return err
+			}
+			results[i] = r
+			return nil
+		})
+	}
+	return results, pool.Wait()
+}
@@ -0,0 +1,154 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// Mode 指定 Pool 的错误聚合方式。
+type Mode int
+
+const (
+	// CollectAll 执行全部任务，Wait 返回所有错误的 errors.Join 结果（默认）。
+	CollectAll Mode = iota
+	// FirstError 任一任务失败后取消 Context，未开始的任务不再执行，Wait 仅返回第一个错误。
+	FirstError
+)
+
+// PanicError 表示任务发生 panic，已被 Pool 恢复。
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("workerpool: 任务 panic: %v", e.Value)
+}
+
+// Option 配置 Pool。
+type Option func(*Pool)
+
+// WithContext 设置父 Context。父 Context 取消后，未开始的任务不再执行。
+func WithContext(ctx context.Context) Option {
+	return func(p *Pool) { p.parent = ctx }
+}
+
+// WithMode 设置错误聚合方式，默认 CollectAll。
+func WithMode(mode Mode) Option {
+	return func(p *Pool) { p.mode = mode }
+}
+
+// Pool 是并发数有上限的 goroutine 池。
+//
+// 用法：
+//
+//	pool := workerpool.New(8, workerpool.WithMode(workerpool.FirstError))
+//	for _, key := range keys {
+//	    pool.Submit(func(ctx context.Context) error {
+//	        return download(ctx, key)
+//	    })
+//	}
+//	if err := pool.Wait(); err != nil { ... }
+type Pool struct {
+	parent context.Context
+	mode   Mode
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	sem    chan struct{}
+	wg     sync.WaitGroup
+
+	mu       sync.Mutex
+	errs     []error
+	canceled bool // 有任务因 Context 取消而未执行
+}
+
+// New 创建最多同时运行 n 个任务的 Pool（n <= 0 时为 1）。
+func New(n int, opts ...Option) *Pool {
+	if n <= 0 {
+		n = 1
+	}
+	p := &Pool{parent: context.Background(), sem: make(chan struct{}, n)}
+	for _, opt := range opts {
+		opt(p)
+	}
+	p.ctx, p.cancel = context.WithCancel(p.parent)
+	return p
+}
+
+// Context 返回传给任务的 Context，Wait 返回后或 FirstError 模式下首个错误发生时被取消。
+func (p *Pool) Context() context.Context {
+	return p.ctx
+}
+
+// Submit 提交任务。并发已满时阻塞直到有空闲 worker；
+// Context 已取消时任务不会执行。不可在 Wait 之后调用。
+func (p *Pool) Submit(fn func(ctx context.Context) error) {
+	select {
+	case p.sem <- struct{}{}:
+	case <-p.ctx.Done():
+		p.markCanceled()
+		return
+	}
+	if p.ctx.Err() != nil {
+		<-p.sem
+		p.markCanceled()
+		return
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer func() { <-p.sem }()
+		if err := p.run(fn); err != nil {
+			p.addError(err)
+		}
+	}()
+}
+
+// Wait 等待所有已提交任务结束并返回聚合后的错误。
+// CollectAll 模式返回 errors.Join(所有错误)；FirstError 模式返回第一个错误。
+// 若有任务因父 Context 取消而未执行，返回值中包含该 Context 的错误。
+func (p *Pool) Wait() error {
+	p.wg.Wait()
+	defer p.cancel()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.mode == FirstError && len(p.errs) > 0 {
+		return p.errs[0]
+	}
+	errs := p.errs
+	if p.canceled && p.parent.Err() != nil {
+		errs = append(errs, p.parent.Err())
+	}
+	return errors.Join(errs...)
+}
+
+// run 执行任务并把 panic 转换为 *PanicError。
+func (p *Pool) run(fn func(ctx context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return fn(p.ctx)
+}
+
+func (p *Pool) addError(err error) {
+	p.mu.Lock()
+	p.errs = append(p.errs, err)
+	p.mu.Unlock()
+	if p.mode == FirstError {
+		p.cancel()
+	}
+}
+
+func (p *Pool) markCanceled() {
+	p.mu.Lock()
+	p.canceled = true
+	p.mu.Unlock()
+}
@@ -0,0 +1,130 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPoolBoundsConcurrency(t *testing.T) {
+	const limit = 3
+	var running, peak atomic.Int32
+
+	pool := New(limit)
+	for i := 0; i < 20; i++ {
+		pool.Submit(func(ctx context.Context) error {
+			n := running.Add(1)
+			for {
+				old := peak.Load()
+				if n <= old || peak.CompareAndSwap(old, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			running.Add(-1)
+			return nil
+		})
+	}
+	if err := pool.Wait(); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	if got := peak.Load(); got > limit {
+		t.Errorf("peak concurrency = %d, want <= %d", got, limit)
+	}
+}
+
+func TestPoolCollectAll(t *testing.T) {
+	errA := errors.New("a")
+	errB := errors.New("b")
+
+	pool := New(2)
+	pool.Submit(func(ctx context.Context) error { return errA })
+	pool.Submit(func(ctx context.Context) error { return nil })
+	pool.Submit(func(ctx context.Context) error { return errB })
+
+	err := pool.Wait()
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Errorf("Wait = %v, want both errors joined", err)
+	}
+}
+
+func TestPoolFirstErrorCancels(t *testing.T) {
+	errBoom := errors.New("boom")
+	var ran atomic.Int32
+
+	pool := New(1, WithMode(FirstError))
+	pool.Submit(func(ctx context.Context) error { return errBoom })
+	for i := 0; i < 10; i++ {
+		pool.Submit(func(ctx context.Context) error {
+			ran.Add(1)
+			return errors.New("should not be reported")
+		})
+	}
+
+	if err := pool.Wait(); !errors.Is(err, errBoom) {
+		t.Errorf("Wait = %v, want %v", err, errBoom)
+	}
+	if ran.Load() == 10 {
+		t.Error("tasks submitted after the first error should be skipped")
+	}
+}
+
+func TestPoolRecoversPanic(t *testing.T) {
+	pool := New(2)
+	pool.Submit(func(ctx context.Context) error { panic("oops") })
+
+	var pe *PanicError
+	if err := pool.Wait(); !errors.As(err, &pe) {
+		t.Fatalf("Wait = %v, want *PanicError", err)
+	}
+	if pe.Value != "oops" || len(pe.Stack) == 0 {
+		t.Errorf("PanicError = %+v, want value oops with stack", pe)
+	}
+}
+
+func TestPoolParentContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var ran atomic.Int32
+	pool := New(2, WithContext(ctx))
+	pool.Submit(func(ctx context.Context) error {
+		ran.Add(1)
+		return nil
+	})
+
+	if err := pool.Wait(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait = %v, want context.Canceled", err)
+	}
+	if ran.Load() != 0 {
+		t.Error("task should not run after parent context is canceled")
+	}
+}
+
+func TestMap(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+	got, err := Map(context.Background(), 3, items, func(ctx context.Context, n int) (string, error) {
+		time.Sleep(time.Duration(5-n) * time.Millisecond)
+		return fmt.Sprint(n * n), nil
+	})
+	if err != nil {
+		t.Fatalf("Map: %v", err)
+	}
+	if want := []string{"1", "4", "9", "16", "25"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Map = %v, want %v", got, want)
+	}
+
+	_, err = Map(context.Background(), 2, items, func(ctx context.Context, n int) (int, error) {
+		if n == 3 {
+			return 0, errors.New("bad item")
+		}
+		return n, nil
+	})
+	if err == nil {
+		t.Error("Map should return the task error")
+	}
+}